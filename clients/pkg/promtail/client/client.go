@@ -24,6 +24,7 @@ import (
 
 	lokiutil "github.com/grafana/loki/pkg/util"
 	"github.com/grafana/loki/pkg/util/build"
+	"github.com/grafana/loki/pkg/util/httpreq"
 )
 
 const (
@@ -455,6 +456,10 @@ func (c *client) send(ctx context.Context, tenantID string, buf []byte) (int, er
 		req.Header.Set("X-Scope-OrgID", tenantID)
 	}
 
+	if c.cfg.AckMode != "" {
+		req.Header.Set(httpreq.LokiPushAckModeHeader, c.cfg.AckMode)
+	}
+
 	// Add custom headers on request
 	if len(c.cfg.Headers) > 0 {
 		for k, v := range c.cfg.Headers {
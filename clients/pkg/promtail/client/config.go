@@ -2,6 +2,7 @@ package client
 
 import (
 	"flag"
+	"fmt"
 	"time"
 
 	"github.com/grafana/dskit/backoff"
@@ -9,6 +10,7 @@ import (
 	"github.com/prometheus/common/config"
 
 	lokiflag "github.com/grafana/loki/pkg/util/flagext"
+	"github.com/grafana/loki/pkg/util/httpreq"
 )
 
 // NOTE the helm chart for promtail and fluent-bit also have defaults for these values, please update to match if you make changes here.
@@ -44,6 +46,10 @@ type Config struct {
 	// 429 'Too Many Requests' response from the distributor. Helps
 	// prevent HOL blocking in multitenant deployments.
 	DropRateLimitedBatches bool `yaml:"drop_rate_limited_batches"`
+
+	// The acknowledgement mode of the push requests, sent in the X-Loki-Ack-Mode
+	// header: async, quorum or all. Empty means the default mode of Loki.
+	AckMode string `yaml:"ack_mode,omitempty"`
 }
 
 // RegisterFlags with prefix registers flags where every name is prefixed by
@@ -101,6 +107,10 @@ func (c *Config) UnmarshalYAML(unmarshal func(interface{}) error) error {
 		return err
 	}
 
+	if !httpreq.ValidPushAckMode(cfg.AckMode) {
+		return fmt.Errorf("invalid ack_mode %q, expected one of %s, %s or %s", cfg.AckMode, httpreq.PushAckModeAsync, httpreq.PushAckModeQuorum, httpreq.PushAckModeAll)
+	}
+
 	*c = Config(cfg)
 	return nil
 }
@@ -33,6 +33,7 @@ timeout: 5s
 basic_auth:
   username: promtail
 enable_http2: false
+ack_mode: async
 `
 
 var clientInvalidHTTPConfig = `
@@ -41,6 +42,11 @@ bearer_token: tkn
 bearer_token_file: tkn_file
 `
 
+var clientInvalidAckModeConfig = `
+url: http://localhost:3100/loki/api/v1/push
+ack_mode: sync
+`
+
 func Test_Config(t *testing.T) {
 	u, err := url.Parse("http://localhost:3100/loki/api/v1/push")
 	require.NoError(t, err)
@@ -87,6 +93,7 @@ func Test_Config(t *testing.T) {
 					},
 					FollowRedirects: true,
 				},
+				AckMode: "async",
 			},
 			nil,
 		},
@@ -95,6 +102,11 @@ func Test_Config(t *testing.T) {
 			Config{},
 			errors.New("at most one of bearer_token & bearer_token_file must be configured"),
 		},
+		{
+			clientInvalidAckModeConfig,
+			Config{},
+			errors.New(`invalid ack_mode "sync", expected one of async, quorum or all`),
+		},
 	}
 	for _, tc := range tests {
 		tc := tc
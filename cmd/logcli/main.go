@@ -311,6 +311,16 @@ func main() {
 		rangeQuery.Limit = 0
 	}
 
+	// The values of the parameters are bound to the query before it is run,
+	// so that they don't need to be quoted or escaped.
+	for _, q := range []*query.Query{rangeQuery, instantQuery} {
+		qs, err := syntax.BindParams(q.QueryString, q.Params)
+		if err != nil {
+			log.Fatalf("Unable to bind the query parameters: %s", err)
+		}
+		q.QueryString = qs
+	}
+
 	switch cmd {
 	case queryCmd.FullCommand():
 		location, err := time.LoadLocation(*timezone)
@@ -331,6 +341,8 @@ func main() {
 
 		if *tail || *follow {
 			rangeQuery.TailQuery(time.Duration(*delayFor)*time.Second, queryClient, out)
+		} else if rangeQuery.Stream {
+			rangeQuery.DoExport(queryClient, out)
 		} else if rangeQuery.ParallelMaxWorkers == 1 {
 			rangeQuery.DoQuery(queryClient, out, *statistics)
 		} else {
@@ -439,6 +451,7 @@ func newQueryClient(app *kingpin.Application) client.Client {
 	app.Flag("key", "Path to the client certificate key. Can also be set using LOKI_CLIENT_KEY_PATH env var.").Default("").Envar("LOKI_CLIENT_KEY_PATH").StringVar(&client.TLSConfig.KeyFile)
 	app.Flag("org-id", "adds X-Scope-OrgID to API requests for representing tenant ID. Useful for requesting tenant data when bypassing an auth gateway. Can also be set using LOKI_ORG_ID env var.").Default("").Envar("LOKI_ORG_ID").StringVar(&client.OrgID)
 	app.Flag("query-tags", "adds X-Query-Tags http header to API requests. This header value will be part of `metrics.go` statistics. Useful for tracking the query. Can also be set using LOKI_QUERY_TAGS env var.").Default("").Envar("LOKI_QUERY_TAGS").StringVar(&client.QueryTags)
+	app.Flag("allow-partial-results", "adds the X-Loki-Allow-Partial-Results http header to API requests. Failing parts of a query are returned as warnings instead of failing the whole query. Can also be set using LOKI_ALLOW_PARTIAL_RESULTS env var.").Default("false").Envar("LOKI_ALLOW_PARTIAL_RESULTS").BoolVar(&client.AllowPartial)
 	app.Flag("bearer-token", "adds the Authorization header to API requests for authentication purposes. Can also be set using LOKI_BEARER_TOKEN env var.").Default("").Envar("LOKI_BEARER_TOKEN").StringVar(&client.BearerToken)
 	app.Flag("bearer-token-file", "adds the Authorization header to API requests for authentication purposes. Can also be set using LOKI_BEARER_TOKEN_FILE env var.").Default("").Envar("LOKI_BEARER_TOKEN_FILE").StringVar(&client.BearerTokenFile)
 	app.Flag("retries", "How many times to retry each query when getting an error response from Loki. Can also be set using LOKI_CLIENT_RETRIES env var.").Default("0").Envar("LOKI_CLIENT_RETRIES").IntVar(&client.Retries)
@@ -510,7 +523,7 @@ func newQuery(instant bool, cmd *kingpin.CmdClause) *query.Query {
 	var now, from, to string
 	var since time.Duration
 
-	q := &query.Query{}
+	q := &query.Query{Params: make(map[string]string)}
 
 	// executed after all command flags are parsed
 	cmd.Action(func(c *kingpin.ParseContext) error {
@@ -535,6 +548,7 @@ func newQuery(instant bool, cmd *kingpin.CmdClause) *query.Query {
 	})
 
 	cmd.Flag("limit", "Limit on number of entries to print. Setting it to 0 will fetch all entries.").Default("30").IntVar(&q.Limit)
+	cmd.Flag("param", "Value of a parameter of the query, e.g. --param app=api for $app. Can be repeated.").StringMapVar(&q.Params)
 	if instant {
 		cmd.Arg("query", "eg 'rate({foo=\"bar\"} |~ \".*error.*\" [5m])'").Required().StringVar(&q.QueryString)
 		cmd.Flag("now", "Time at which to execute the instant query.").StringVar(&now)
@@ -552,6 +566,8 @@ func newQuery(instant bool, cmd *kingpin.CmdClause) *query.Query {
 		cmd.Flag("overwrite-completed-parts", "Overwrites completed part files. This will download the range again, and replace the original completed part file. Default will skip a range if it's part file is already downloaded.").Default("false").BoolVar(&q.OverwriteCompleted)
 		cmd.Flag("merge-parts", "Reads the part files in order and writes the output to stdout. Original part files will be deleted with this option.").Default("false").BoolVar(&q.MergeParts)
 		cmd.Flag("keep-parts", "Overrides the default behaviour of --merge-parts which will delete the part files once all the files have been read. This option will keep the part files.").Default("false").BoolVar(&q.KeepParts)
+		cmd.Flag("stream", "Stream all matching entries of a log query using the export endpoint instead of querying in batches. Entries are printed as they are received. When set, batch and parallel flags are ignored and a limit of 0 exports all entries, resuming the export whenever the server limit is reached.").Default("false").BoolVar(&q.Stream)
+		cmd.Flag("cursor", "Resume an interrupted --stream export from this cursor.").Default("").StringVar(&q.Cursor)
 	}
 
 	cmd.Flag("forward", "Scan forwards through logs.").Default("false").BoolVar(&q.Forward)
@@ -22,6 +22,7 @@ import (
 	"github.com/grafana/loki/pkg/canary/reader"
 	"github.com/grafana/loki/pkg/canary/writer"
 	_ "github.com/grafana/loki/pkg/util/build"
+	"github.com/grafana/loki/pkg/util/httpreq"
 )
 
 const (
@@ -55,6 +56,7 @@ func main() {
 	user := flag.String("user", "", "Loki username.")
 	pass := flag.String("pass", "", "Loki password. This credential should have both read and write permissions to Loki endpoints")
 	tenantID := flag.String("tenant-id", "", "Tenant ID to be set in X-Scope-OrgID header.")
+	ackMode := flag.String("ack-mode", "", "When pushing the logs directly, acknowledgement mode of the push requests to be set in the X-Loki-Ack-Mode header: async, quorum or all. Defaults to the quorum mode of Loki.")
 	writeTimeout := flag.Duration("write-timeout", 10*time.Second, "How long to wait write response from Loki")
 	writeMinBackoff := flag.Duration("write-min-backoff", defaultMinBackoff, "Initial backoff time before first retry ")
 	writeMaxBackoff := flag.Duration("write-max-backoff", defaultMaxBackoff, "Maximum backoff time between retries ")
@@ -108,6 +110,11 @@ func main() {
 		os.Exit(1)
 	}
 
+	if !httpreq.ValidPushAckMode(*ackMode) {
+		_, _ = fmt.Fprintf(os.Stderr, "Ack mode must be one of async, quorum or all\n")
+		os.Exit(1)
+	}
+
 	var tlsConfig *tls.Config
 	tc := config.TLSConfig{}
 	if *certFile != "" || *keyFile != "" || *caFile != "" {
@@ -169,6 +176,7 @@ func main() {
 				tlsConfig,
 				*caFile, *certFile, *keyFile,
 				*user, *pass,
+				*ackMode,
 				&backoffCfg,
 				log.NewLogfmtLogger(os.Stderr),
 			)
@@ -21,6 +21,7 @@ import (
 
 	"github.com/grafana/loki/pkg/logproto"
 	"github.com/grafana/loki/pkg/util/build"
+	"github.com/grafana/loki/pkg/util/httpreq"
 )
 
 const (
@@ -55,6 +56,9 @@ type Push struct {
 	// auth
 	username, password string
 
+	// acknowledgement mode of the push requests, see the X-Loki-Ack-Mode header.
+	ackMode string
+
 	// Will add these label to the logs pushed to loki
 	labelName, labelValue, streamName, streamValue string
 
@@ -73,6 +77,7 @@ func NewPush(
 	tlsCfg *tls.Config,
 	caFile, certFile, keyFile string,
 	username, password string,
+	ackMode string,
 	backoffCfg *backoff.Config,
 	logger log.Logger,
 ) (*Push, error) {
@@ -128,6 +133,7 @@ func NewPush(
 		streamValue: streamValue,
 		username:    username,
 		password:    password,
+		ackMode:     ackMode,
 		backoff:     backoffCfg,
 	}
 	go p.run()
@@ -252,6 +258,10 @@ func (p *Push) send(ctx context.Context, payload []byte) (int, error) {
 		req.Header.Set("X-Scope-OrgID", p.tenantID)
 	}
 
+	if p.ackMode != "" {
+		req.Header.Set(httpreq.LokiPushAckModeHeader, p.ackMode)
+	}
+
 	// basic auth if provided
 	if p.username != "" {
 		req.SetBasicAuth(p.username, p.password)
@@ -1193,16 +1193,7 @@ func (hb *headBlock) Iterator(ctx context.Context, direction logproto.Direction,
 	stats.AddHeadChunkLines(int64(len(hb.entries)))
 	streams := map[string]*logproto.Stream{}
 	baseHash := pipeline.BaseLabels().Hash()
-	process := func(e entry) {
-		// apply time filtering
-		if e.t < mint || e.t >= maxt {
-			return
-		}
-		stats.AddHeadChunkBytes(int64(len(e.s)))
-		newLine, parsedLbs, matches := pipeline.ProcessString(e.t, e.s, e.structuredMetadata...)
-		if !matches {
-			return
-		}
+	appendEntry := func(e entry, newLine string, parsedLbs log.LabelsResult) {
 		stats.AddPostFilterLines(1)
 		var stream *logproto.Stream
 		labels := parsedLbs.Labels().String()
@@ -1220,6 +1211,22 @@ func (hb *headBlock) Iterator(ctx context.Context, direction logproto.Direction,
 			StructuredMetadata: logproto.FromLabelsToLabelAdapters(e.structuredMetadata),
 		})
 	}
+	process := func(e entry) {
+		// apply time filtering
+		if e.t < mint || e.t >= maxt {
+			return
+		}
+		stats.AddHeadChunkBytes(int64(len(e.s)))
+		newLine, parsedLbs, matches := pipeline.ProcessString(e.t, e.s, e.structuredMetadata...)
+		if !matches {
+			return
+		}
+		appendEntry(e, newLine, parsedLbs)
+		// the line could have been fanned out into several lines.
+		for line, parsedLbs, ok := pipeline.Next(); ok; line, parsedLbs, ok = pipeline.Next() {
+			appendEntry(e, string(line), parsedLbs)
+		}
+	}
 
 	if direction == logproto.FORWARD {
 		for _, e := range hb.entries {
@@ -1250,12 +1257,7 @@ func (hb *headBlock) SampleIterator(ctx context.Context, mint, maxt int64, extra
 	series := map[string]*logproto.Series{}
 	baseHash := extractor.BaseLabels().Hash()
 
-	for _, e := range hb.entries {
-		stats.AddHeadChunkBytes(int64(len(e.s)))
-		value, parsedLabels, ok := extractor.ProcessString(e.t, e.s, e.structuredMetadata...)
-		if !ok {
-			continue
-		}
+	appendSample := func(e entry, value float64, parsedLabels log.LabelsResult, hash uint64) {
 		stats.AddPostFilterLines(1)
 		var (
 			found bool
@@ -1275,10 +1277,25 @@ func (hb *headBlock) SampleIterator(ctx context.Context, mint, maxt int64, extra
 		s.Samples = append(s.Samples, logproto.Sample{
 			Timestamp: e.t,
 			Value:     value,
-			Hash:      xxhash.Sum64(unsafeGetBytes(e.s)),
+			Hash:      hash,
 		})
 	}
 
+	for _, e := range hb.entries {
+		stats.AddHeadChunkBytes(int64(len(e.s)))
+		value, parsedLabels, ok := extractor.ProcessString(e.t, e.s, e.structuredMetadata...)
+		if !ok {
+			continue
+		}
+		hash := xxhash.Sum64(unsafeGetBytes(e.s))
+		appendSample(e, value, parsedLabels, hash)
+		// the samples of a line fanned out into several lines must not be deduplicated together.
+		for value, parsedLabels, ok := extractor.Next(); ok; value, parsedLabels, ok = extractor.Next() {
+			hash++
+			appendSample(e, value, parsedLabels, hash)
+		}
+	}
+
 	if len(series) == 0 {
 		return iter.NoopIterator
 	}
@@ -1613,6 +1630,13 @@ func (e *entryBufferedIterator) Labels() string { return e.currLabels.String() }
 func (e *entryBufferedIterator) StreamHash() uint64 { return e.pipeline.BaseLabels().Hash() }
 
 func (e *entryBufferedIterator) Next() bool {
+	// the current line could have been fanned out into several lines.
+	if newLine, lbs, matches := e.pipeline.Next(); matches {
+		e.stats.AddPostFilterLines(1)
+		e.currLabels = lbs
+		e.cur.Line = string(newLine)
+		return true
+	}
 	for e.bufferedIterator.Next() {
 		newLine, lbs, matches := e.pipeline.Process(e.currTs, e.currLine, e.currStructuredMetadata...)
 		if !matches {
@@ -1652,6 +1676,15 @@ type sampleBufferedIterator struct {
 }
 
 func (e *sampleBufferedIterator) Next() bool {
+	// the current line could have been fanned out into several lines.
+	if val, labels, ok := e.extractor.Next(); ok {
+		e.stats.AddPostFilterLines(1)
+		e.currLabels = labels
+		e.cur.Value = val
+		// the samples of the same line must not be deduplicated together.
+		e.cur.Hash++
+		return true
+	}
 	for e.bufferedIterator.Next() {
 		val, labels, ok := e.extractor.Process(e.currTs, e.currLine, e.currStructuredMetadata...)
 		if !ok {
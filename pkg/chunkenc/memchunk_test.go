@@ -842,6 +842,7 @@ func (nomatchPipeline) Process(_ int64, line []byte, _ ...labels.Label) ([]byte,
 func (nomatchPipeline) ProcessString(_ int64, line string, _ ...labels.Label) (string, log.LabelsResult, bool) {
 	return line, nil, false
 }
+func (nomatchPipeline) Next() ([]byte, log.LabelsResult, bool) { return nil, nil, false }
 
 func BenchmarkRead(b *testing.B) {
 	for _, bs := range testBlockSizes {
@@ -1967,3 +1968,57 @@ func TestMemChunk_IteratorOptions(t *testing.T) {
 		})
 	}
 }
+
+func TestMemChunk_IteratorUnnest(t *testing.T) {
+	lbs := labels.FromStrings("app", "foo")
+	pipelineExpr, err := syntax.ParseLogSelector(`{app="foo"} | unnest items`, true)
+	require.NoError(t, err)
+	pipeline, err := pipelineExpr.Pipeline()
+	require.NoError(t, err)
+	sampleExpr, err := syntax.ParseSampleExpr(`sum by (sku) (count_over_time({app="foo"} | unnest items [1m]))`)
+	require.NoError(t, err)
+	extractor, err := sampleExpr.Extractor()
+	require.NoError(t, err)
+
+	for _, f := range allPossibleFormats {
+		t.Run(fmt.Sprintf("%v-%v", f.headBlockFmt, f.chunkFormat), func(t *testing.T) {
+			chk := newMemChunkWithFormat(f.chunkFormat, EncNone, f.headBlockFmt, testBlockSize, testTargetSize)
+			for i := int64(0); i < 3; i++ {
+				require.NoError(t, chk.Append(&logproto.Entry{Timestamp: time.Unix(0, i), Line: `{"items":[{"sku":"a"},{"sku":"b"},{"sku":"b"}]}`}))
+				// the last line stays in the head block.
+				if i == 1 {
+					require.NoError(t, chk.cut())
+				}
+			}
+
+			it, err := chk.Iterator(context.Background(), time.Unix(0, 0), time.Unix(0, math.MaxInt64), logproto.FORWARD, pipeline.ForStream(lbs))
+			require.NoError(t, err)
+			entries := map[string]int{}
+			for it.Next() {
+				require.Equal(t, fmt.Sprintf(`{"sku":"%s"}`, labelValue(t, it.Labels(), "sku")), it.Entry().Line)
+				entries[it.Labels()]++
+			}
+			require.NoError(t, it.Close())
+			require.Equal(t, map[string]int{`{app="foo", sku="a"}`: 3, `{app="foo", sku="b"}`: 6}, entries)
+
+			sampleIt := chk.SampleIterator(context.Background(), time.Unix(0, 0), time.Unix(0, math.MaxInt64), extractor.ForStream(lbs))
+			samples := map[string]int{}
+			hashes := map[uint64]struct{}{}
+			for sampleIt.Next() {
+				samples[sampleIt.Labels()]++
+				hashes[sampleIt.Sample().Hash] = struct{}{}
+			}
+			require.NoError(t, sampleIt.Close())
+			require.Equal(t, map[string]int{`{sku="a"}`: 3, `{sku="b"}`: 6}, samples)
+			// the samples of the same line are not deduplicated together.
+			require.Len(t, hashes, 3)
+		})
+	}
+}
+
+func labelValue(t *testing.T, lbs, name string) string {
+	t.Helper()
+	ls, err := syntax.ParseLabels(lbs)
+	require.NoError(t, err)
+	return ls.Get(name)
+}
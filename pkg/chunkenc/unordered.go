@@ -263,33 +263,38 @@ func (hb *unorderedHeadBlock) Iterator(ctx context.Context, direction logproto.D
 		maxt,
 		func(statsCtx *stats.Context, ts int64, line string, structuredMetadataSymbols symbols) error {
 			newLine, parsedLbs, matches := pipeline.ProcessString(ts, line, hb.symbolizer.Lookup(structuredMetadataSymbols)...)
-			if !matches {
-				return nil
-			}
-			statsCtx.AddPostFilterLines(1)
-			var stream *logproto.Stream
-			labels := parsedLbs.String()
-			var ok bool
-			if stream, ok = streams[labels]; !ok {
-				stream = &logproto.Stream{
-					Labels: labels,
-					Hash:   baseHash,
+			for matches {
+				statsCtx.AddPostFilterLines(1)
+				var stream *logproto.Stream
+				labels := parsedLbs.String()
+				var ok bool
+				if stream, ok = streams[labels]; !ok {
+					stream = &logproto.Stream{
+						Labels: labels,
+						Hash:   baseHash,
+					}
+					streams[labels] = stream
 				}
-				streams[labels] = stream
-			}
 
-			entry := logproto.Entry{
-				Timestamp: time.Unix(0, ts),
-				Line:      newLine,
-			}
+				entry := logproto.Entry{
+					Timestamp: time.Unix(0, ts),
+					Line:      newLine,
+				}
 
-			// Most of the time, there is no need to send back the structured metadata, as they are already part of the labels results.
-			// Still it might be needed for example when appending entries from one chunk into another one.
-			if iterOptions.KeepStructuredMetdata {
-				entry.StructuredMetadata = logproto.FromLabelsToLabelAdapters(hb.symbolizer.Lookup(structuredMetadataSymbols))
-			}
+				// Most of the time, there is no need to send back the structured metadata, as they are already part of the labels results.
+				// Still it might be needed for example when appending entries from one chunk into another one.
+				if iterOptions.KeepStructuredMetdata {
+					entry.StructuredMetadata = logproto.FromLabelsToLabelAdapters(hb.symbolizer.Lookup(structuredMetadataSymbols))
+				}
+
+				stream.Entries = append(stream.Entries, entry)
 
-			stream.Entries = append(stream.Entries, entry)
+				// the line could have been fanned out into several lines.
+				var next []byte
+				if next, parsedLbs, matches = pipeline.Next(); matches {
+					newLine = string(next)
+				}
+			}
 			return nil
 		},
 	)
@@ -323,26 +328,34 @@ func (hb *unorderedHeadBlock) SampleIterator(
 			if !ok {
 				return nil
 			}
-			statsCtx.AddPostFilterLines(1)
-			var (
-				found bool
-				s     *logproto.Series
-			)
-			lbs := parsedLabels.String()
-			s, found = series[lbs]
-			if !found {
-				s = &logproto.Series{
-					Labels:     lbs,
-					Samples:    SamplesPool.Get(hb.lines).([]logproto.Sample)[:0],
-					StreamHash: baseHash,
+			hash := xxhash.Sum64(unsafeGetBytes(line))
+			for ok {
+				statsCtx.AddPostFilterLines(1)
+				var (
+					found bool
+					s     *logproto.Series
+				)
+				lbs := parsedLabels.String()
+				s, found = series[lbs]
+				if !found {
+					s = &logproto.Series{
+						Labels:     lbs,
+						Samples:    SamplesPool.Get(hb.lines).([]logproto.Sample)[:0],
+						StreamHash: baseHash,
+					}
+					series[lbs] = s
 				}
-				series[lbs] = s
+				s.Samples = append(s.Samples, logproto.Sample{
+					Timestamp: ts,
+					Value:     value,
+					Hash:      hash,
+				})
+
+				// the line could have been fanned out into several lines,
+				// whose samples must not be deduplicated together.
+				value, parsedLabels, ok = extractor.Next()
+				hash++
 			}
-			s.Samples = append(s.Samples, logproto.Sample{
-				Timestamp: ts,
-				Value:     value,
-				Hash:      xxhash.Sum64(unsafeGetBytes(line)),
-			})
 			return nil
 		},
 	)
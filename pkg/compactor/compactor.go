@@ -19,10 +19,12 @@ import (
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
 
 	"github.com/grafana/loki/pkg/analytics"
 	"github.com/grafana/loki/pkg/compactor/deletion"
 	"github.com/grafana/loki/pkg/compactor/retention"
+	"github.com/grafana/loki/pkg/compactor/tiering"
 	"github.com/grafana/loki/pkg/storage/chunk/client"
 	"github.com/grafana/loki/pkg/storage/chunk/client/local"
 	chunk_util "github.com/grafana/loki/pkg/storage/chunk/client/util"
@@ -91,6 +93,9 @@ type Config struct {
 	RunOnce                   bool            `yaml:"_" doc:"hidden"`
 	TablesToCompact           int             `yaml:"tables_to_compact"`
 	SkipLatestNTables         int             `yaml:"skip_latest_n_tables"`
+	ColdStorageInterval       time.Duration   `yaml:"cold_storage_interval"`
+
+	ChunkMerge retention.ChunkMergeConfig `yaml:"chunk_merge" doc:"description=Configures the merging of the small chunks of the streams, such as the ones flushed by the ingesters on chunk_idle_period. The CLI flags prefix for this block config is: compactor.chunk-merge"`
 
 	// Deprecated
 	DeletionMode string `yaml:"deletion_mode" doc:"deprecated|description=Use deletion_mode per tenant configuration instead."`
@@ -121,8 +126,10 @@ func (cfg *Config) RegisterFlagsWithPrefix(prefix string, f *flag.FlagSet) {
 	f.BoolVar(&cfg.RunOnce, prefix+"compactor.run-once", false, deprecated+"Run the compactor one time to cleanup and compact index files only (no retention applied)")
 
 	cfg.CompactorRing.RegisterFlagsWithPrefix(prefix+"compactor.", "collectors/", f)
+	cfg.ChunkMerge.RegisterFlagsWithPrefix(prefix+"compactor.chunk-merge.", f)
 	f.IntVar(&cfg.TablesToCompact, prefix+"compactor.tables-to-compact", 0, deprecated+"Number of tables that compactor will try to compact. Newer tables are chosen when this is less than the number of tables available.")
 	f.IntVar(&cfg.SkipLatestNTables, prefix+"compactor.skip-latest-n-tables", 0, deprecated+"Do not compact N latest tables. Together with -compactor.run-once and -compactor.tables-to-compact, this is useful when clearing compactor backlogs.")
+	f.DurationVar(&cfg.ColdStorageInterval, prefix+"compactor.cold-storage-interval", time.Hour, deprecated+"Interval at which to move the chunks and index files older than the cold_storage_after limit of the tenants to the cold storage, if a cold storage is configured in the storage_config. Each run lists all the chunks of the tenants moving data to the cold storage.")
 }
 
 // RegisterFlags registers flags.
@@ -148,6 +155,13 @@ func (cfg *Config) Validate() error {
 		return err
 	}
 
+	if cfg.ChunkMerge.Enabled && !cfg.RetentionEnabled {
+		return errors.New("merging chunks requires retention to be enabled")
+	}
+	if err := cfg.ChunkMerge.Validate(); err != nil {
+		return err
+	}
+
 	if cfg.DeletionMode != "" {
 		level.Warn(util_log.Logger).Log("msg", "boltdb.shipper.compactor.deletion-mode has been deprecated and will be ignored. This has been moved to the deletion_mode per tenant configuration.")
 	}
@@ -188,13 +202,16 @@ type Compactor struct {
 
 type storeContainer struct {
 	tableMarker        retention.TableMarker
+	chunkMerger        retention.TableChunkMerger
 	sweeper            *retention.Sweeper
 	indexStorageClient storage.Client
+	coldStorageMover   *tiering.Mover
 }
 
 type Limits interface {
 	deletion.Limits
 	retention.Limits
+	tiering.Limits
 	DefaultLimits() *validation.Limits
 }
 
@@ -296,6 +313,12 @@ func (c *Compactor) init(objectStoreClients map[string]client.ObjectClient, sche
 		var sc storeContainer
 		sc.indexStorageClient = storage.NewIndexStorageClient(objectClient, c.cfg.SharedStoreKeyPrefix)
 
+		tieredClient, tiered := objectClient.(*client.TieredObjectClient)
+		if tiered && limits != nil {
+			r := prometheus.WrapRegistererWith(prometheus.Labels{"object_store": objectStoreType}, r)
+			sc.coldStorageMover = tiering.NewMover(tieredClient, c.cfg.SharedStoreKeyPrefix, schemaConfig, limits, r)
+		}
+
 		if c.cfg.RetentionEnabled {
 			// given that compaction can now run on multiple object stores, marker files are stored under /retention/{objectStoreType}/markers/
 			// if any markers are found in the common markers dir (/retention/markers/), copy them to the store specific dirs
@@ -309,7 +332,12 @@ func (c *Compactor) init(objectStoreClients map[string]client.ObjectClient, sche
 				r                = prometheus.WrapRegistererWith(prometheus.Labels{"object_store": objectStoreType}, r)
 			)
 
-			if _, ok := objectClient.(*local.FSObjectClient); ok {
+			hotClient := objectClient
+			if tiered {
+				// chunks are encoded for the store of their schema period, which is the hot tier.
+				hotClient = tieredClient.Hot()
+			}
+			if _, ok := hotClient.(*local.FSObjectClient); ok {
 				encoder = client.FSEncoder
 			}
 			chunkClient := client.NewClient(objectClient, encoder, schemaConfig)
@@ -323,6 +351,13 @@ func (c *Compactor) init(objectStoreClients map[string]client.ObjectClient, sche
 			if err != nil {
 				return fmt.Errorf("failed to init table marker: %w", err)
 			}
+
+			if c.cfg.ChunkMerge.Enabled {
+				sc.chunkMerger, err = retention.NewChunkMerger(retentionWorkDir, c.cfg.ChunkMerge, chunkClient, schemaConfig, r)
+				if err != nil {
+					return fmt.Errorf("failed to init chunk merger: %w", err)
+				}
+			}
 		}
 
 		c.storeContainers[objectStoreType] = sc
@@ -363,7 +398,7 @@ func (c *Compactor) initDeletes(objectClient client.ObjectClient, r prometheus.R
 		r,
 	)
 
-	c.expirationChecker = newExpirationChecker(retention.NewExpirationChecker(limits), c.deleteRequestsManager)
+	c.expirationChecker = newExpirationChecker(retention.NewExpirationChecker(limits, filepath.Join(c.cfg.WorkingDirectory, "retention"), r), c.deleteRequestsManager)
 	return nil
 }
 
@@ -511,6 +546,7 @@ func (c *Compactor) runCompactions(ctx context.Context) {
 	}()
 
 	lastRetentionRunAt := time.Unix(0, 0)
+	lastColdStorageRunAt := time.Unix(0, 0)
 	runCompaction := func() {
 		applyRetention := false
 		if c.cfg.RetentionEnabled && time.Since(lastRetentionRunAt) >= c.cfg.ApplyRetentionInterval {
@@ -526,6 +562,12 @@ func (c *Compactor) runCompactions(ctx context.Context) {
 		if applyRetention {
 			lastRetentionRunAt = time.Now()
 		}
+
+		// data is moved to the cold storage after the compaction so that both don't change the same index files concurrently.
+		if time.Since(lastColdStorageRunAt) >= c.cfg.ColdStorageInterval {
+			c.moveToColdStorage(ctx)
+			lastColdStorageRunAt = time.Now()
+		}
 	}
 
 	c.wg.Add(1)
@@ -562,6 +604,23 @@ func (c *Compactor) runCompactions(ctx context.Context) {
 	level.Info(util_log.Logger).Log("msg", "compactor started")
 }
 
+// moveToColdStorage moves the data older than the cold_storage_after limit of the tenants
+// to the cold storage of the object stores which have one.
+func (c *Compactor) moveToColdStorage(ctx context.Context) {
+	for objectStoreType, sc := range c.storeContainers {
+		if sc.coldStorageMover == nil {
+			continue
+		}
+
+		level.Info(util_log.Logger).Log("msg", "moving data to cold storage", "object_store", objectStoreType)
+		if err := sc.coldStorageMover.Run(ctx, model.Now()); err != nil {
+			level.Error(util_log.Logger).Log("msg", "failed to move data to cold storage", "object_store", objectStoreType, "err", err)
+			continue
+		}
+		level.Info(util_log.Logger).Log("msg", "finished moving data to cold storage", "object_store", objectStoreType)
+	}
+}
+
 func (c *Compactor) stopping(_ error) error {
 	return services.StopManagerAndAwaitStopped(context.Background(), c.subservices)
 }
@@ -584,7 +643,7 @@ func (c *Compactor) CompactTable(ctx context.Context, tableName string, applyRet
 	}
 
 	table, err := newTable(ctx, filepath.Join(c.cfg.WorkingDirectory, tableName), sc.indexStorageClient, indexCompactor,
-		schemaCfg, sc.tableMarker, c.expirationChecker, c.cfg.UploadParallelism)
+		schemaCfg, sc.tableMarker, sc.chunkMerger, c.expirationChecker, c.cfg.UploadParallelism)
 	if err != nil {
 		level.Error(util_log.Logger).Log("msg", "failed to initialize table for compaction", "table", tableName, "err", err)
 		return err
@@ -596,7 +655,10 @@ func (c *Compactor) CompactTable(ctx context.Context, tableName string, applyRet
 		intervalMayHaveExpiredChunks = c.expirationChecker.IntervalMayHaveExpiredChunks(interval, "")
 	}
 
-	err = table.compact(intervalMayHaveExpiredChunks)
+	// small chunks are merged once the table has ended, when no more chunks are expected to be flushed for it.
+	mergeChunks := applyRetention && sc.chunkMerger != nil && schemaCfg.IndexType == config.TSDBType && interval.End.Before(model.Now())
+
+	err = table.compact(intervalMayHaveExpiredChunks, mergeChunks)
 	if err != nil {
 		level.Error(util_log.Logger).Log("msg", "failed to compact files", "table", tableName, "err", err)
 		return err
@@ -733,11 +795,26 @@ func newExpirationChecker(retentionExpiryChecker, deletionExpiryChecker retentio
 }
 
 func (e *expirationChecker) Expired(ref retention.ChunkEntry, now model.Time) (bool, filter.Func) {
-	if expired, nonDeletedIntervals := e.retentionExpiryChecker.Expired(ref, now); expired {
-		return expired, nonDeletedIntervals
+	retentionExpired, retentionFilter := e.retentionExpiryChecker.Expired(ref, now)
+	if retentionExpired && retentionFilter == nil {
+		return true, nil
 	}
 
-	return e.deletionExpiryChecker.Expired(ref, now)
+	deletionExpired, deletionFilter := e.deletionExpiryChecker.Expired(ref, now)
+	switch {
+	case !retentionExpired:
+		return deletionExpired, deletionFilter
+	case !deletionExpired:
+		return true, retentionFilter
+	case deletionFilter == nil:
+		return true, nil
+	}
+
+	// Only some lines are expired by the retention rules with a filter and by the delete requests,
+	// delete the lines selected by any of them.
+	return true, func(ts time.Time, s string, structuredMetadata ...labels.Label) bool {
+		return retentionFilter(ts, s, structuredMetadata...) || deletionFilter(ts, s, structuredMetadata...)
+	}
 }
 
 func (e *expirationChecker) MarkPhaseStarted() {
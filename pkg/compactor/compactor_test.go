@@ -11,11 +11,14 @@ import (
 
 	"github.com/grafana/dskit/flagext"
 	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
 	"github.com/stretchr/testify/require"
 
+	"github.com/grafana/loki/pkg/compactor/retention"
 	"github.com/grafana/loki/pkg/storage/chunk/client"
 	"github.com/grafana/loki/pkg/storage/chunk/client/local"
 	"github.com/grafana/loki/pkg/storage/config"
+	"github.com/grafana/loki/pkg/util/filter"
 	loki_net "github.com/grafana/loki/pkg/util/net"
 )
 
@@ -275,3 +278,86 @@ func Test_tableSort(t *testing.T) {
 	sortTablesByRange(intervals)
 	require.Equal(t, []string{"index_19195", "index_19192", "index_19191"}, intervals)
 }
+
+type fakeExpirationChecker struct {
+	retention.ExpirationChecker
+	expired    bool
+	filterFunc filter.Func
+}
+
+func (f fakeExpirationChecker) Expired(_ retention.ChunkEntry, _ model.Time) (bool, filter.Func) {
+	return f.expired, f.filterFunc
+}
+
+func Test_expirationChecker_Expired(t *testing.T) {
+	containsFilter := func(s string) filter.Func {
+		return func(_ time.Time, line string, _ ...labels.Label) bool {
+			return strings.Contains(line, s)
+		}
+	}
+
+	for _, tc := range []struct {
+		name            string
+		retention       fakeExpirationChecker
+		deletion        fakeExpirationChecker
+		expired         bool
+		wholeChunk      bool
+		deletedLines    []string
+		nonDeletedLines []string
+	}{
+		{
+			name: "nothing expired",
+		},
+		{
+			name:       "expired by retention",
+			retention:  fakeExpirationChecker{expired: true},
+			deletion:   fakeExpirationChecker{expired: true, filterFunc: containsFilter("b")},
+			expired:    true,
+			wholeChunk: true,
+		},
+		{
+			name:            "lines expired by retention",
+			retention:       fakeExpirationChecker{expired: true, filterFunc: containsFilter("a")},
+			expired:         true,
+			deletedLines:    []string{"a"},
+			nonDeletedLines: []string{"b"},
+		},
+		{
+			name:            "lines expired by deletion",
+			deletion:        fakeExpirationChecker{expired: true, filterFunc: containsFilter("b")},
+			expired:         true,
+			deletedLines:    []string{"b"},
+			nonDeletedLines: []string{"a"},
+		},
+		{
+			name:       "lines expired by retention and chunk deleted",
+			retention:  fakeExpirationChecker{expired: true, filterFunc: containsFilter("a")},
+			deletion:   fakeExpirationChecker{expired: true},
+			expired:    true,
+			wholeChunk: true,
+		},
+		{
+			name:            "lines expired by retention and deletion",
+			retention:       fakeExpirationChecker{expired: true, filterFunc: containsFilter("a")},
+			deletion:        fakeExpirationChecker{expired: true, filterFunc: containsFilter("b")},
+			expired:         true,
+			deletedLines:    []string{"a", "b"},
+			nonDeletedLines: []string{"c"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			expired, filterFunc := newExpirationChecker(tc.retention, tc.deletion).Expired(retention.ChunkEntry{}, model.Now())
+			require.Equal(t, tc.expired, expired)
+			if tc.wholeChunk || !tc.expired {
+				require.Nil(t, filterFunc)
+				return
+			}
+			for _, line := range tc.deletedLines {
+				require.True(t, filterFunc(time.Now(), line))
+			}
+			for _, line := range tc.nonDeletedLines {
+				require.False(t, filterFunc(time.Now(), line))
+			}
+		})
+	}
+}
@@ -382,6 +382,29 @@ func TestDeleteRequest_FilterFunction(t *testing.T) {
 		require.Equal(t, float64(1), testutil.ToFloat64(dr.Metrics.deletedLinesTotal))
 	})
 
+	t.Run("one line matching with csv parser and label filter", func(t *testing.T) {
+		dr := DeleteRequest{
+			Query:        `{foo="bar"} | csv sep=";" "user;status" | user="bob"`,
+			DeletedLines: 0,
+			Metrics:      newDeleteRequestsManagerMetrics(prometheus.NewPedanticRegistry()),
+			StartTime:    0,
+			EndTime:      math.MaxInt64,
+		}
+
+		lblStr := lblFooBar
+		lbls := mustParseLabel(lblStr)
+
+		require.NoError(t, dr.SetQuery(dr.Query))
+		f, err := dr.FilterFunction(lbls)
+		require.NoError(t, err)
+
+		require.True(t, f(time.Now(), `bob;200`))
+		require.False(t, f(time.Now(), `alice;200`))
+		require.False(t, f(time.Now(), ""))
+		require.Equal(t, int32(1), dr.DeletedLines)
+		require.Equal(t, float64(1), testutil.ToFloat64(dr.Metrics.deletedLinesTotal))
+	})
+
 	t.Run("labels not matching", func(t *testing.T) {
 		dr := DeleteRequest{
 			Query:        `{foo="bar"} |= "some"`,
@@ -18,6 +18,11 @@ func parseDeletionQuery(query string) (syntax.LogSelectorExpr, error) {
 		return nil, errInvalidQuery
 	}
 
+	// the lines of a delete request are not merged like the ones of a log query.
+	if err := syntax.ValidateNoMergeStages(logSelectorExpr); err != nil {
+		return nil, err
+	}
+
 	return logSelectorExpr, nil
 }
 
@@ -31,6 +31,18 @@ func TestParseLogQLExpressionForDeletion(t *testing.T) {
 		require.NoError(t, err)
 	})
 
+	t.Run("pipeline expression with merge stages", func(t *testing.T) {
+		for _, query := range []string{
+			`{env="dev", secret="true"} |= "social sec number" | distinct id`,
+			`{env="dev", secret="true"} |= "social sec number" | dedup`,
+			`{env="dev", secret="true"} | json | limit 1 by (id)`,
+		} {
+			logSelectorExpr, err := parseDeletionQuery(query)
+			require.Nil(t, logSelectorExpr)
+			require.ErrorContains(t, err, "stage is only supported in log queries")
+		}
+	})
+
 	t.Run("pipeline expression with invalid line filter", func(t *testing.T) {
 		logSelectorExpr, err := parseDeletionQuery(`{env="dev", secret="true"} |= social sec number`)
 		require.Nil(t, logSelectorExpr)
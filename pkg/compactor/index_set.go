@@ -63,6 +63,10 @@ type indexSet struct {
 	compactedIndex CompactedIndex
 	sourceObjects  []storage.IndexFile
 	logger         log.Logger
+
+	// chunkMerger marks the chunks replaced by the merged ones for deletion once the index no longer refers to them.
+	chunkMerger    retention.TableChunkMerger
+	replacedChunks []string
 }
 
 // newUserIndexSet intializes a new index set for user index.
@@ -182,6 +186,27 @@ func (is *indexSet) runRetention(tableMarker retention.TableMarker) error {
 	return nil
 }
 
+// runChunkMerge merges the small chunks of the index set
+func (is *indexSet) runChunkMerge(chunkMerger retention.TableChunkMerger) error {
+	if is.compactedIndex == nil {
+		return nil
+	}
+
+	replacedChunks, err := chunkMerger.MergeChunks(is.ctx, is.tableName, is.userID, is.compactedIndex, is.logger)
+	if err != nil {
+		return err
+	}
+
+	if len(replacedChunks) > 0 {
+		is.uploadCompactedDB = true
+		is.removeSourceObjects = true
+		is.chunkMerger = chunkMerger
+		is.replacedChunks = replacedChunks
+	}
+
+	return nil
+}
+
 // upload uploads the compacted index in compressed format.
 func (is *indexSet) upload() error {
 	if is.compactedIndex == nil {
@@ -282,6 +307,7 @@ func (is *indexSet) removeFilesFromStorage() error {
 // - recreate the compacted db if required.
 // - upload the compacted db if required.
 // - remove the source objects from storage if required.
+// - mark the chunks replaced by merged chunks for deletion, once no index in the storage refers to them.
 func (is *indexSet) done() error {
 	if is.uploadCompactedDB {
 		if err := is.upload(); err != nil {
@@ -290,7 +316,13 @@ func (is *indexSet) done() error {
 	}
 
 	if is.removeSourceObjects {
-		return is.removeFilesFromStorage()
+		if err := is.removeFilesFromStorage(); err != nil {
+			return err
+		}
+	}
+
+	if len(is.replacedChunks) > 0 {
+		return is.chunkMerger.MarkForDelete(is.replacedChunks)
 	}
 
 	return nil
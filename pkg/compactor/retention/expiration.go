@@ -5,9 +5,11 @@ import (
 	"time"
 
 	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/model/labels"
 
+	"github.com/grafana/loki/pkg/logql/log"
 	"github.com/grafana/loki/pkg/util/filter"
 	util_log "github.com/grafana/loki/pkg/util/log"
 	"github.com/grafana/loki/pkg/validation"
@@ -34,6 +36,8 @@ type ExpirationChecker interface {
 type expirationChecker struct {
 	tenantsRetention         *TenantsRetention
 	latestRetentionStartTime latestRetentionStartTime
+	filters                  *filterTracker
+	metrics                  *expirationMetrics
 }
 
 type Limits interface {
@@ -43,21 +47,88 @@ type Limits interface {
 	DefaultLimits() *validation.Limits
 }
 
-func NewExpirationChecker(limits Limits) ExpirationChecker {
+// NewExpirationChecker returns the ExpirationChecker of the retention rules. The chunks already filtered by the rules
+// with a filter are tracked in the working directory, or only in memory when it is empty.
+func NewExpirationChecker(limits Limits, workingDir string, r prometheus.Registerer) ExpirationChecker {
 	return &expirationChecker{
 		tenantsRetention: NewTenantsRetention(limits),
+		filters:          newFilterTracker(limits, workingDir),
+		metrics:          newExpirationMetrics(r),
 	}
 }
 
 // Expired tells if a ref chunk is expired based on retention rules.
+// When the chunk isn't expired as a whole but the retention rules with a filter
+// expire some of its lines, a filter.Func selecting these lines is returned.
 func (e *expirationChecker) Expired(ref ChunkEntry, now model.Time) (bool, filter.Func) {
 	userID := unsafeGetString(ref.UserID)
 	period := e.tenantsRetention.RetentionPeriodFor(userID, ref.Labels)
 	// The 0 value should disable retention
-	if period <= 0 {
-		return false, nil
+	if period > 0 && now.Sub(ref.Through) > period {
+		return true, nil
+	}
+
+	if filterFunc := e.expiredLinesFilter(userID, ref, now); filterFunc != nil {
+		return true, filterFunc
+	}
+	return false, nil
+}
+
+// lineRetention is a retention rule with a filter applied to the lines of a chunk.
+type lineRetention struct {
+	process      func(ts int64, line string, structuredMetadata ...labels.Label) (string, log.LabelsResult, bool)
+	deletedLines prometheus.Counter
+	deletedBytes prometheus.Counter
+}
+
+// expiredLinesFilter returns the filter.Func selecting the lines of the chunk expired by the
+// retention rules with a filter, or nil if none of these rules has to filter the chunk.
+// A rule filters a chunk once the whole chunk is older than its period, unless the chunk
+// was already filtered by the rule in an earlier retention phase.
+func (e *expirationChecker) expiredLinesFilter(userID string, ref ChunkEntry, now model.Time) filter.Func {
+	now = e.filters.now(now)
+	var rules []lineRetention
+	for _, rule := range e.tenantsRetention.limits.StreamRetention(userID) {
+		if rule.Expr == nil || rule.Period <= 0 || !labels.Selector(rule.Matchers).Matches(ref.Labels) {
+			continue
+		}
+		cutoff := now.Add(-time.Duration(rule.Period))
+		if !ref.Through.Before(cutoff) || ref.Through.Before(e.filters.watermark(userID, rule)) {
+			continue
+		}
+
+		pipeline, err := rule.Expr.Pipeline()
+		if err != nil {
+			// The filter is checked when the limits are loaded so this error should not occur.
+			level.Error(util_log.Logger).Log("msg", "failed to build retention filter", "user", userID, "rule", rule.Expr.String(), "err", err)
+			continue
+		}
+		rules = append(rules, lineRetention{
+			process:      pipeline.ForStream(ref.Labels).ProcessString,
+			deletedLines: e.metrics.deletedLinesTotal.WithLabelValues(userID, rule.Expr.String()),
+			deletedBytes: e.metrics.deletedBytesTotal.WithLabelValues(userID, rule.Expr.String()),
+		})
+	}
+	if len(rules) == 0 {
+		return nil
+	}
+
+	return func(ts time.Time, line string, structuredMetadata ...labels.Label) bool {
+		for _, rule := range rules {
+			if _, _, matches := rule.process(ts.UnixNano(), line, structuredMetadata...); !matches {
+				continue
+			}
+
+			size := len(line)
+			for _, l := range structuredMetadata {
+				size += len(l.Name) + len(l.Value)
+			}
+			rule.deletedLines.Inc()
+			rule.deletedBytes.Add(float64(size))
+			return true
+		}
+		return false
 	}
-	return now.Sub(ref.Through) > period, nil
 }
 
 // DropFromIndex tells if it is okay to drop the chunk entry from index table.
@@ -74,14 +145,17 @@ func (e *expirationChecker) DropFromIndex(ref ChunkEntry, tableEndTime model.Tim
 }
 
 func (e *expirationChecker) MarkPhaseStarted() {
-	e.latestRetentionStartTime = findLatestRetentionStartTime(model.Now(), e.tenantsRetention.limits)
+	now := model.Now()
+	e.latestRetentionStartTime = findLatestRetentionStartTime(now, e.tenantsRetention.limits)
+	e.filters.start(now)
 	level.Info(util_log.Logger).Log("msg", fmt.Sprintf("overall smallest retention period %v, default smallest retention period %v",
 		e.latestRetentionStartTime.overall, e.latestRetentionStartTime.defaults))
 }
 
-func (e *expirationChecker) MarkPhaseFailed()   {}
-func (e *expirationChecker) MarkPhaseTimedOut() {}
-func (e *expirationChecker) MarkPhaseFinished() {}
+// The chunks filtered by the rules with a filter are only tracked once a phase went through all tables.
+func (e *expirationChecker) MarkPhaseFailed()   { e.filters.fail() }
+func (e *expirationChecker) MarkPhaseTimedOut() { e.filters.timeout() }
+func (e *expirationChecker) MarkPhaseFinished() { e.filters.finish() }
 
 func (e *expirationChecker) IntervalMayHaveExpiredChunks(interval model.Interval, userID string) bool {
 	// when userID is empty, it means we are checking for common index table. In this case we use e.overallLatestRetentionStartTime.
@@ -97,7 +171,7 @@ func (e *expirationChecker) IntervalMayHaveExpiredChunks(interval model.Interval
 			latestRetentionStartTime = e.latestRetentionStartTime.defaults
 		}
 	}
-	return interval.Start.Before(latestRetentionStartTime)
+	return interval.Start.Before(latestRetentionStartTime) || e.filters.intervalMayHaveUnfilteredChunks(interval, userID)
 }
 
 // NeverExpiringExpirationChecker returns an expiration checker that never expires anything
@@ -140,6 +214,10 @@ func (tr *TenantsRetention) RetentionPeriodFor(userID string, lbs labels.Labels)
 	)
 Outer:
 	for _, streamRetention := range streamRetentions {
+		// Rules with a filter only expire lines, not whole streams.
+		if streamRetention.Filter != "" {
+			continue
+		}
 		for _, m := range streamRetention.Matchers {
 			if !m.Matches(lbs.Get(m.Name)) {
 				continue Outer
@@ -183,6 +261,10 @@ func findLatestRetentionStartTime(now model.Time, limits Limits) latestRetention
 	defaultLimits := limits.DefaultLimits()
 	smallestDefaultRetentionPeriod := defaultLimits.RetentionPeriod
 	for _, streamRetention := range defaultLimits.StreamRetention {
+		// Rules with a filter are tracked by the filterTracker.
+		if streamRetention.Filter != "" {
+			continue
+		}
 		if streamRetention.Period < smallestDefaultRetentionPeriod {
 			smallestDefaultRetentionPeriod = streamRetention.Period
 		}
@@ -196,6 +278,9 @@ func findLatestRetentionStartTime(now model.Time, limits Limits) latestRetention
 	for userID, limit := range limitsByUserID {
 		smallestRetentionPeriodForUser := limit.RetentionPeriod
 		for _, streamRetention := range limit.StreamRetention {
+			if streamRetention.Filter != "" {
+				continue
+			}
 			if streamRetention.Period < smallestRetentionPeriodForUser {
 				smallestRetentionPeriodForUser = streamRetention.Period
 			}
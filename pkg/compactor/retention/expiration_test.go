@@ -101,7 +101,7 @@ func Test_expirationChecker_Expired(t *testing.T) {
 	o, err := overridesTestConfig(d, f)
 	require.NoError(t, err)
 
-	e := NewExpirationChecker(o)
+	e := NewExpirationChecker(o, "", nil)
 	tests := []struct {
 		name string
 		ref  ChunkEntry
@@ -123,6 +123,115 @@ func Test_expirationChecker_Expired(t *testing.T) {
 	}
 }
 
+func Test_expirationChecker_Expired_filter(t *testing.T) {
+	d := defaultLimitsTestConfig()
+	d.RetentionPeriod = model.Duration(2160 * time.Hour)
+	d.StreamRetention = []validation.StreamRetention{
+		{Period: model.Duration(72 * time.Hour), Selector: `{app="api"}`, Filter: `| level="debug"`},
+		{Period: model.Duration(48 * time.Hour), Selector: `{app=~".+"}`, Filter: `|= "/health"`},
+		{Period: model.Duration(24 * time.Hour), Selector: `{app="api", env="dev"}`},
+	}
+	require.NoError(t, d.Validate())
+	o, err := overridesTestConfig(d, fakeOverrides{})
+	require.NoError(t, err)
+	e := NewExpirationChecker(o, "", nil)
+
+	now := model.Now()
+	debug := labels.FromStrings("level", "debug")
+
+	// Whole streams are expired by the rules without filter.
+	expired, filterFunc := e.Expired(newChunkEntry("1", `{app="api", env="dev"}`, now.Add(-30*time.Hour), now.Add(-25*time.Hour)), now)
+	require.True(t, expired)
+	require.Nil(t, filterFunc)
+
+	// Chunks newer than the period of the rules with filter are left untouched.
+	expired, filterFunc = e.Expired(newChunkEntry("1", `{app="api"}`, now.Add(-40*time.Hour), now.Add(-30*time.Hour)), now)
+	require.False(t, expired)
+	require.Nil(t, filterFunc)
+
+	// A rule only filters the chunks older than its period as a whole.
+	expired, filterFunc = e.Expired(newChunkEntry("1", `{app="api"}`, now.Add(-100*time.Hour), now.Add(-60*time.Hour)), now)
+	require.True(t, expired)
+	require.NotNil(t, filterFunc)
+	require.False(t, filterFunc(now.Add(-80*time.Hour).Time(), "msg", debug...))
+	require.True(t, filterFunc(now.Add(-80*time.Hour).Time(), "GET /health"))
+
+	expired, filterFunc = e.Expired(newChunkEntry("1", `{app="api"}`, now.Add(-100*time.Hour), now.Add(-80*time.Hour)), now)
+	require.True(t, expired)
+	require.NotNil(t, filterFunc)
+	for _, tc := range []struct {
+		name               string
+		line               string
+		structuredMetadata labels.Labels
+		deleted            bool
+	}{
+		{"debug line", "msg", debug, true},
+		{"info line", "msg", labels.FromStrings("level", "info"), false},
+		{"health check", "GET /health", nil, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.deleted, filterFunc(now.Add(-90*time.Hour).Time(), tc.line, tc.structuredMetadata...))
+		})
+	}
+
+	// Only the rules matching the stream apply.
+	expired, filterFunc = e.Expired(newChunkEntry("1", `{app="web"}`, now.Add(-100*time.Hour), now.Add(-80*time.Hour)), now)
+	require.True(t, expired)
+	require.False(t, filterFunc(now.Add(-90*time.Hour).Time(), "msg", debug...))
+	require.True(t, filterFunc(now.Add(-90*time.Hour).Time(), "GET /health"))
+
+	// Rules with a filter don't change the retention period of the streams.
+	require.Equal(t, 2160*time.Hour, e.(*expirationChecker).tenantsRetention.RetentionPeriodFor("1", labels.FromStrings("app", "api")))
+}
+
+func Test_expirationChecker_filterWatermarks(t *testing.T) {
+	d := defaultLimitsTestConfig()
+	d.StreamRetention = []validation.StreamRetention{
+		{Period: model.Duration(24 * time.Hour), Selector: `{app="api"}`, Filter: `|= "/health"`},
+	}
+	require.NoError(t, d.Validate())
+	limits := fakeLimits{
+		defaultLimit: retentionLimit{retentionPeriod: 2160 * time.Hour},
+		perTenant: map[string]retentionLimit{
+			"1": {retentionPeriod: 2160 * time.Hour, streamRetention: d.StreamRetention},
+		},
+	}
+	workingDir := t.TempDir()
+	table := model.Interval{Start: model.Now().Add(-74 * time.Hour), End: model.Now().Add(-50 * time.Hour)}
+	ref := newChunkEntry("1", `{app="api"}`, table.Start.Add(time.Hour), table.Start.Add(2*time.Hour))
+
+	// Rules with a filter don't make whole tables expire.
+	require.Equal(t, model.Time(0).Add(-2160*time.Hour), findLatestRetentionStartTime(0, limits).byUser["1"])
+
+	e := NewExpirationChecker(limits, workingDir, nil)
+	e.MarkPhaseStarted()
+	require.True(t, e.IntervalMayHaveExpiredChunks(table, ""))
+	require.True(t, e.IntervalMayHaveExpiredChunks(table, "1"))
+	expired, filterFunc := e.Expired(ref, model.Now())
+	require.True(t, expired)
+	require.NotNil(t, filterFunc)
+
+	// The chunks aren't tracked as filtered when the phase failed.
+	e.MarkPhaseFailed()
+	e.MarkPhaseStarted()
+	expired, _ = e.Expired(ref, model.Now())
+	require.True(t, expired)
+	e.MarkPhaseFinished()
+
+	// The chunks filtered by a finished phase aren't filtered again, even after a restart.
+	e = NewExpirationChecker(limits, workingDir, nil)
+	e.MarkPhaseStarted()
+	require.False(t, e.IntervalMayHaveExpiredChunks(table, ""))
+	require.False(t, e.IntervalMayHaveExpiredChunks(table, "1"))
+	expired, filterFunc = e.Expired(ref, model.Now())
+	require.False(t, expired)
+	require.Nil(t, filterFunc)
+
+	// The tables of the chunks getting older than the period of the rule are still processed.
+	recentTable := model.Interval{Start: model.Now().Add(-25 * time.Hour), End: model.Now().Add(-time.Hour)}
+	require.True(t, e.IntervalMayHaveExpiredChunks(recentTable, "1"))
+}
+
 func Test_expirationChecker_Expired_zeroValue(t *testing.T) {
 
 	// Default retention should be zero
@@ -139,7 +248,7 @@ func Test_expirationChecker_Expired_zeroValue(t *testing.T) {
 	}
 	o, err := overridesTestConfig(d, f)
 	require.NoError(t, err)
-	e := NewExpirationChecker(o)
+	e := NewExpirationChecker(o, "", nil)
 	tests := []struct {
 		name string
 		ref  ChunkEntry
@@ -185,7 +294,7 @@ func Test_expirationChecker_Expired_zeroValueOverride(t *testing.T) {
 	o, err := overridesTestConfig(d, f)
 	require.NoError(t, err)
 
-	e := NewExpirationChecker(o)
+	e := NewExpirationChecker(o, "", nil)
 	tests := []struct {
 		name string
 		ref  ChunkEntry
@@ -219,7 +328,7 @@ func Test_expirationChecker_DropFromIndex_zeroValue(t *testing.T) {
 	}
 	o, err := overridesTestConfig(d, f)
 	require.NoError(t, err)
-	e := NewExpirationChecker(o)
+	e := NewExpirationChecker(o, "", nil)
 
 	chunkFrom := model.Now().Add(-3 * time.Hour)
 	chunkThrough := model.Now().Add(-2 * time.Hour)
@@ -401,6 +510,7 @@ func TestFindLatestRetentionStartTime(t *testing.T) {
 func TestExpirationChecker_IntervalMayHaveExpiredChunks(t *testing.T) {
 	now := model.Now()
 	expirationChecker := expirationChecker{
+		filters: newFilterTracker(fakeLimits{}, ""),
 		latestRetentionStartTime: latestRetentionStartTime{
 			overall:  now.Add(-24 * time.Hour),
 			defaults: now.Add(-48 * time.Hour),
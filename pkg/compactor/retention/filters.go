@@ -0,0 +1,207 @@
+package retention
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/common/model"
+
+	chunk_util "github.com/grafana/loki/pkg/storage/chunk/client/util"
+	util_log "github.com/grafana/loki/pkg/util/log"
+	"github.com/grafana/loki/pkg/validation"
+)
+
+// filterWatermarksFile is the file of the working directory keeping the watermarks of the retention rules with a filter.
+const filterWatermarksFile = "filter_watermarks.json"
+
+// filterTracker tracks the chunks already filtered by the retention rules with a filter.
+// A rule filters a chunk once the whole chunk is older than its period, after which the
+// chunk, or the chunk rewritten without the expired lines, never has to be read again.
+// The watermark of a rule is its cutoff at the start of the last retention phase which
+// finished without failure or timeout: all chunks ending before it were already filtered.
+type filterTracker struct {
+	limits Limits
+	path   string
+
+	// watermarks by rule key, see filterRuleKey.
+	watermarks map[string]model.Time
+
+	// state of the current retention phase.
+	started    bool
+	phaseStart model.Time
+	timedOut   bool
+	overridden map[string]struct{}
+}
+
+func newFilterTracker(limits Limits, workingDir string) *filterTracker {
+	f := &filterTracker{
+		limits:     limits,
+		watermarks: map[string]model.Time{},
+	}
+	if workingDir == "" {
+		return f
+	}
+
+	f.path = filepath.Join(workingDir, filterWatermarksFile)
+	buf, err := os.ReadFile(f.path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			level.Warn(util_log.Logger).Log("msg", "failed to read retention filter watermarks, all chunks will be filtered again", "path", f.path, "err", err)
+		}
+		return f
+	}
+	if err := json.Unmarshal(buf, &f.watermarks); err != nil {
+		level.Warn(util_log.Logger).Log("msg", "failed to decode retention filter watermarks, all chunks will be filtered again", "path", f.path, "err", err)
+		f.watermarks = map[string]model.Time{}
+	}
+	return f
+}
+
+// filterRuleKey identifies a rule with a filter of the default limits, when the scope is empty, or of the overrides of a tenant.
+// The period isn't part of the key so that changing it doesn't filter the chunks already filtered again.
+func filterRuleKey(scope string, rule validation.StreamRetention) string {
+	return scope + "/" + rule.Expr.String()
+}
+
+// filterRules returns the retention rules with a filter of the given limits.
+func filterRules(limits *validation.Limits) []validation.StreamRetention {
+	if limits == nil {
+		return nil
+	}
+	var rules []validation.StreamRetention
+	for _, rule := range limits.StreamRetention {
+		if rule.Expr != nil && rule.Period > 0 {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+func (f *filterTracker) start(now model.Time) {
+	f.started = true
+	f.phaseStart = now
+	f.timedOut = false
+	f.overridden = map[string]struct{}{}
+	for userID := range f.limits.AllByUserID() {
+		f.overridden[userID] = struct{}{}
+	}
+}
+
+func (f *filterTracker) timeout() {
+	f.timedOut = true
+}
+
+func (f *filterTracker) fail() {
+	f.started = false
+}
+
+// finish advances the watermarks of all rules once the phase went through all tables.
+func (f *filterTracker) finish() {
+	if !f.started || f.timedOut {
+		f.started = false
+		return
+	}
+	f.started = false
+
+	// The watermarks of the rules no longer configured are dropped. A watermark never moves back
+	// when the period of its rule is increased since the chunks before it were already filtered.
+	watermarks := map[string]model.Time{}
+	advance := func(scope string, rules []validation.StreamRetention) {
+		for _, rule := range rules {
+			key := filterRuleKey(scope, rule)
+			watermark := f.phaseStart.Add(-time.Duration(rule.Period))
+			if previous := f.watermarks[key]; previous.After(watermark) {
+				watermark = previous
+			}
+			watermarks[key] = watermark
+		}
+	}
+	advance("", filterRules(f.limits.DefaultLimits()))
+	for userID, limits := range f.limits.AllByUserID() {
+		advance(userID, filterRules(limits))
+	}
+	f.watermarks = watermarks
+
+	if f.path == "" {
+		return
+	}
+	if err := f.save(); err != nil {
+		level.Error(util_log.Logger).Log("msg", "failed to save retention filter watermarks", "path", f.path, "err", err)
+	}
+}
+
+func (f *filterTracker) save() error {
+	buf, err := json.Marshal(f.watermarks)
+	if err != nil {
+		return err
+	}
+	if err := chunk_util.EnsureDirectory(filepath.Dir(f.path)); err != nil {
+		return err
+	}
+	tmp := f.path + ".tmp"
+	if err := os.WriteFile(tmp, buf, 0o640); err != nil {
+		return err
+	}
+	return os.Rename(tmp, f.path)
+}
+
+// now returns the time the cutoffs of the rules are computed from, the start of the phase if any.
+func (f *filterTracker) now(now model.Time) model.Time {
+	if f.started {
+		return f.phaseStart
+	}
+	return now
+}
+
+func (f *filterTracker) scope(userID string) string {
+	if _, ok := f.overridden[userID]; ok {
+		return userID
+	}
+	return ""
+}
+
+// watermark returns the time before which all chunks of the tenant were filtered by the rule.
+func (f *filterTracker) watermark(userID string, rule validation.StreamRetention) model.Time {
+	return f.watermarks[filterRuleKey(f.scope(userID), rule)]
+}
+
+// intervalMayHaveUnfilteredChunks tells whether a table may index chunks a rule with a filter still has to filter,
+// for any tenant when the userID is empty.
+func (f *filterTracker) intervalMayHaveUnfilteredChunks(interval model.Interval, userID string) bool {
+	if !f.started {
+		return false
+	}
+
+	mayHaveUnfilteredChunks := func(scope string, rules []validation.StreamRetention) bool {
+		for _, rule := range rules {
+			cutoff := f.phaseStart.Add(-time.Duration(rule.Period))
+			// Chunks are shorter than a table so a table can only index chunks ending after the
+			// watermark when it ends less than the length of a table before the watermark.
+			earliestEnd := f.watermarks[filterRuleKey(scope, rule)].Add(-interval.End.Sub(interval.Start))
+			if interval.Start.Before(cutoff) && !interval.End.Before(earliestEnd) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if userID != "" {
+		if _, ok := f.overridden[userID]; ok {
+			return mayHaveUnfilteredChunks(userID, filterRules(f.limits.AllByUserID()[userID]))
+		}
+		return mayHaveUnfilteredChunks("", filterRules(f.limits.DefaultLimits()))
+	}
+
+	if mayHaveUnfilteredChunks("", filterRules(f.limits.DefaultLimits())) {
+		return true
+	}
+	for userID, limits := range f.limits.AllByUserID() {
+		if mayHaveUnfilteredChunks(userID, filterRules(limits)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,387 @@
+package retention
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+
+	"github.com/grafana/loki/pkg/chunkenc"
+	"github.com/grafana/loki/pkg/iter"
+	"github.com/grafana/loki/pkg/logproto"
+	logql_log "github.com/grafana/loki/pkg/logql/log"
+	"github.com/grafana/loki/pkg/storage/chunk"
+	"github.com/grafana/loki/pkg/storage/chunk/client"
+	"github.com/grafana/loki/pkg/storage/config"
+	"github.com/grafana/loki/pkg/util"
+)
+
+// maxMergeSizeFactor bounds the total uncompressed size of the chunks merged at once
+// to this factor of the target size, to bound the memory used by a merge.
+const maxMergeSizeFactor = 10
+
+// ChunkMergeConfig configures the merging of the small chunks of the streams by the compactor.
+type ChunkMergeConfig struct {
+	Enabled    bool   `yaml:"enabled"`
+	TargetSize int    `yaml:"target_size"`
+	BlockSize  int    `yaml:"block_size"`
+	Encoding   string `yaml:"encoding"`
+
+	parsedEncoding chunkenc.Encoding `yaml:"-"` // placeholder for validated encoding
+}
+
+// RegisterFlagsWithPrefix registers flags.
+func (cfg *ChunkMergeConfig) RegisterFlagsWithPrefix(prefix string, f *flag.FlagSet) {
+	f.BoolVar(&cfg.Enabled, prefix+"enabled", false, "(Experimental) Merge the adjacent small chunks of each stream into chunks close to the target size once their index table has ended, using the chunk format of their schema period. Requires retention to be enabled since the merged chunks are deleted by the retention sweeper. Only the chunks of the TSDB index are merged.")
+	f.IntVar(&cfg.TargetSize, prefix+"target-size", 1572864, "The target compressed size in bytes of the merged chunks. Only the chunks whose uncompressed size is below this size are merged. This should match the ingester chunk_target_size.") // 1.5 MB
+	f.IntVar(&cfg.BlockSize, prefix+"block-size", 256*1024, "The targeted uncompressed size in bytes of the blocks of the merged chunks. This should match the ingester chunk_block_size.")
+	f.StringVar(&cfg.Encoding, prefix+"encoding", chunkenc.EncGZIP.String(), fmt.Sprintf("The algorithm to use for compressing the merged chunks. This should match the ingester chunk_encoding. (%s)", chunkenc.SupportedEncoding()))
+}
+
+// Validate verifies the config does not contain inappropriate values.
+func (cfg *ChunkMergeConfig) Validate() error {
+	if !cfg.Enabled {
+		return nil
+	}
+	if cfg.TargetSize <= 0 {
+		return errors.New("chunk merge target size must be > 0")
+	}
+	if cfg.BlockSize <= 0 {
+		return errors.New("chunk merge block size must be > 0")
+	}
+
+	enc, err := chunkenc.ParseEncoding(cfg.Encoding)
+	if err != nil {
+		return err
+	}
+	cfg.parsedEncoding = enc
+	return nil
+}
+
+type TableChunkMerger interface {
+	// MergeChunks merges the small chunks of the streams of a given table and returns the IDs of the chunks
+	// replaced by the merged ones in the index. The index has been modified when any chunk was replaced.
+	MergeChunks(ctx context.Context, tableName, userID string, indexProcessor IndexProcessor, logger log.Logger) ([]string, error)
+	// MarkForDelete marks the replaced chunks for deletion. It must only be called once the modified index
+	// has been uploaded, since the index in the storage keeps referring to the replaced chunks until then.
+	MarkForDelete(chunkIDs []string) error
+}
+
+// ChunkMerger merges the adjacent small chunks of each stream of a table into chunks close to the target size.
+// The merged chunks are indexed in place of the small ones in the same index, and the small chunks are marked
+// for deletion once the index is uploaded so that the Sweeper deletes them once the index referring to them
+// is no longer used.
+type ChunkMerger struct {
+	workingDirectory string
+	cfg              ChunkMergeConfig
+	chunkClient      client.Client
+	schemaCfg        config.SchemaConfig
+	metrics          *chunkMergerMetrics
+}
+
+func NewChunkMerger(workingDirectory string, cfg ChunkMergeConfig, chunkClient client.Client, schemaCfg config.SchemaConfig, r prometheus.Registerer) (*ChunkMerger, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &ChunkMerger{
+		workingDirectory: workingDirectory,
+		cfg:              cfg,
+		chunkClient:      chunkClient,
+		schemaCfg:        schemaCfg,
+		metrics:          newChunkMergerMetrics(r),
+	}, nil
+}
+
+// MergeChunks merges the small chunks of the streams of a given table.
+func (m *ChunkMerger) MergeChunks(ctx context.Context, tableName, _ string, indexProcessor IndexProcessor, logger log.Logger) ([]string, error) {
+	start := time.Now()
+	status := statusSuccess
+	defer func() {
+		m.metrics.tableProcessedDurationSeconds.WithLabelValues(tableName, status).Observe(time.Since(start).Seconds())
+		level.Debug(logger).Log("msg", "finished to merge chunks of table", "duration", time.Since(start))
+	}()
+
+	replacedChunks, err := m.mergeTable(ctx, tableName, indexProcessor, logger)
+	if err != nil {
+		status = statusFailure
+		return nil, err
+	}
+	return replacedChunks, nil
+}
+
+// MarkForDelete marks the chunks replaced by the merged ones for deletion.
+func (m *ChunkMerger) MarkForDelete(chunkIDs []string) error {
+	markerWriter, err := NewMarkerStorageWriter(m.workingDirectory)
+	if err != nil {
+		return fmt.Errorf("failed to create marker writer: %w", err)
+	}
+	for _, chunkID := range chunkIDs {
+		if err := markerWriter.Put([]byte(chunkID)); err != nil {
+			markerWriter.Close()
+			return err
+		}
+	}
+	if err := markerWriter.Close(); err != nil {
+		return fmt.Errorf("failed to close marker writer: %w", err)
+	}
+
+	m.metrics.sourceChunksTotal.Add(float64(len(chunkIDs)))
+	return nil
+}
+
+// mergeCandidate is a chunk which may be merged with the adjacent chunks of its stream.
+type mergeCandidate struct {
+	chunkID       string
+	from, through model.Time
+	size          int
+}
+
+type streamChunks struct {
+	userID string
+	labels labels.Labels
+	chunks []mergeCandidate
+}
+
+func (m *ChunkMerger) mergeTable(ctx context.Context, tableName string, indexProcessor IndexProcessor, logger log.Logger) ([]string, error) {
+	tableInterval := ExtractIntervalFromTableName(tableName)
+
+	// Only the chunks fully within the table are merged since the other ones are also indexed
+	// by the adjacent tables, which keep referring to them.
+	// The chunk entries are reused by the iteration so they are copied.
+	streams := map[string]*streamChunks{}
+	err := indexProcessor.ForEachChunk(ctx, func(c ChunkEntry) (bool, error) {
+		if c.Entries == 0 || c.From < tableInterval.Start || c.Through > tableInterval.End {
+			return false, nil
+		}
+
+		key := string(c.UserID) + string(c.SeriesID)
+		stream, ok := streams[key]
+		if !ok {
+			stream = &streamChunks{
+				userID: string(c.UserID),
+				labels: c.Labels.Copy(),
+			}
+			streams[key] = stream
+		}
+		stream.chunks = append(stream.chunks, mergeCandidate{
+			chunkID: string(c.ChunkID),
+			from:    c.From,
+			through: c.Through,
+			size:    int(c.KB) << 10,
+		})
+		return false, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	mergedChunks := map[string]struct{}{}
+	for _, stream := range streams {
+		for _, group := range m.groupSmallChunks(stream.chunks) {
+			replacedChunks, err := m.mergeChunks(ctx, stream, group, indexProcessor)
+			if err != nil {
+				return nil, fmt.Errorf("failed to merge %d chunks of stream %s: %w", len(group), stream.labels, err)
+			}
+			for _, chunkID := range replacedChunks {
+				mergedChunks[chunkID] = struct{}{}
+			}
+		}
+	}
+	if len(mergedChunks) == 0 {
+		return nil, nil
+	}
+
+	replacedChunks := make([]string, 0, len(mergedChunks))
+	err = indexProcessor.ForEachChunk(ctx, func(c ChunkEntry) (bool, error) {
+		if _, ok := mergedChunks[unsafeGetString(c.ChunkID)]; !ok {
+			return false, nil
+		}
+		replacedChunks = append(replacedChunks, string(c.ChunkID))
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	level.Info(logger).Log("msg", "merged small chunks", "chunks", len(replacedChunks))
+	return replacedChunks, nil
+}
+
+// groupSmallChunks returns the groups of adjacent small chunks which are worth being merged together.
+// Chunks are small when their uncompressed size is below the target size, in which case they are
+// below the target size once compressed as well.
+func (m *ChunkMerger) groupSmallChunks(chunks []mergeCandidate) [][]mergeCandidate {
+	sort.Slice(chunks, func(i, j int) bool {
+		if chunks[i].from != chunks[j].from {
+			return chunks[i].from < chunks[j].from
+		}
+		return chunks[i].through < chunks[j].through
+	})
+
+	var (
+		groups    [][]mergeCandidate
+		group     []mergeCandidate
+		groupSize int
+	)
+	flush := func() {
+		if len(group) > 1 {
+			groups = append(groups, group)
+		}
+		group, groupSize = nil, 0
+	}
+
+	for _, c := range chunks {
+		if c.size >= m.cfg.TargetSize {
+			flush()
+			continue
+		}
+		if groupSize+c.size > maxMergeSizeFactor*m.cfg.TargetSize {
+			flush()
+		}
+		group = append(group, c)
+		groupSize += c.size
+	}
+	flush()
+
+	return groups
+}
+
+// mergeChunks writes the entries of the group of chunks into new chunks cut at the target size,
+// then indexes and uploads them. Duplicate entries of the chunks are only written once.
+// It returns the IDs of the chunks of the group replaced by the new chunks.
+func (m *ChunkMerger) mergeChunks(ctx context.Context, stream *streamChunks, group []mergeCandidate, indexer chunkIndexer) ([]string, error) {
+	chks := make([]chunk.Chunk, 0, len(group))
+	sourceChunks := make(map[string]struct{}, len(group))
+	for _, c := range group {
+		sourceChunks[c.chunkID] = struct{}{}
+		chk, err := chunk.ParseExternalKey(stream.userID, c.chunkID)
+		if err != nil {
+			return nil, err
+		}
+		chks = append(chks, chk)
+	}
+
+	chks, err := m.chunkClient.GetChunks(ctx, chks)
+	if err != nil {
+		return nil, err
+	}
+	if len(chks) != len(group) {
+		return nil, fmt.Errorf("expected %d chunks but found %d in storage", len(group), len(chks))
+	}
+
+	periodConfig, err := m.schemaCfg.SchemaForTime(chks[0].From)
+	if err != nil {
+		return nil, err
+	}
+	format, headFmt, err := periodConfig.ChunkFormat()
+	if err != nil {
+		return nil, err
+	}
+
+	pipeline := logql_log.NewNoopPipeline().ForStream(stream.labels)
+	its := make([]iter.EntryIterator, 0, len(chks))
+	for _, chk := range chks {
+		facade, ok := chk.Data.(*chunkenc.Facade)
+		if !ok {
+			return nil, errors.New("invalid chunk type")
+		}
+		it, err := facade.LokiChunk().Iterator(ctx, time.Unix(0, 0), time.Unix(0, math.MaxInt64), logproto.FORWARD, pipeline, iter.WithKeepStructuredMetadata())
+		if err != nil {
+			return nil, err
+		}
+		its = append(its, it)
+	}
+	it := iter.NewMergeEntryIterator(ctx, its, logproto.FORWARD)
+	defer it.Close()
+
+	var (
+		newChunks []chunk.Chunk
+		memChunk  *chunkenc.MemChunk
+	)
+	cut := func() error {
+		if err := memChunk.Close(); err != nil {
+			return err
+		}
+		from, through := util.RoundToMilliseconds(memChunk.Bounds())
+		newChunk := chunk.NewChunk(
+			stream.userID, chks[0].FingerprintModel(), chks[0].Metric,
+			chunkenc.NewFacade(memChunk, m.cfg.BlockSize, m.cfg.TargetSize),
+			from,
+			through,
+		)
+		if err := newChunk.Encode(); err != nil {
+			return err
+		}
+		newChunks = append(newChunks, newChunk)
+		memChunk = nil
+		return nil
+	}
+
+	for it.Next() {
+		entry := it.Entry()
+		if memChunk != nil && !memChunk.SpaceFor(&entry) {
+			if err := cut(); err != nil {
+				return nil, err
+			}
+		}
+		if memChunk == nil {
+			memChunk = chunkenc.NewMemChunk(format, m.cfg.parsedEncoding, headFmt, m.cfg.BlockSize, m.cfg.TargetSize)
+		}
+		if err := memChunk.Append(&entry); err != nil {
+			return nil, err
+		}
+	}
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+	if memChunk == nil {
+		return nil, errors.New("no entries found in chunks")
+	}
+	if err := cut(); err != nil {
+		return nil, err
+	}
+
+	// A new chunk identical to one of the group, for example when the other chunks only
+	// contain duplicates of its entries, is already indexed and uploaded so it is kept.
+	keptChunks := make(map[string]struct{}, len(newChunks))
+	uploadChunks := make([]chunk.Chunk, 0, len(newChunks))
+	for _, newChunk := range newChunks {
+		chunkID := m.schemaCfg.ExternalKey(newChunk.ChunkRef)
+		if _, ok := sourceChunks[chunkID]; ok {
+			keptChunks[chunkID] = struct{}{}
+			continue
+		}
+
+		indexed, err := indexer.IndexChunk(newChunk)
+		if err != nil {
+			return nil, err
+		}
+		if !indexed {
+			return nil, fmt.Errorf("merged chunk %s is out of the range of the table", chunkID)
+		}
+		uploadChunks = append(uploadChunks, newChunk)
+	}
+	if err := m.chunkClient.PutChunks(ctx, uploadChunks); err != nil {
+		return nil, err
+	}
+
+	replacedChunks := make([]string, 0, len(group))
+	for _, c := range group {
+		if _, ok := keptChunks[c.chunkID]; !ok {
+			replacedChunks = append(replacedChunks, c.chunkID)
+		}
+	}
+
+	m.metrics.createdChunksTotal.Add(float64(len(uploadChunks)))
+	return replacedChunks, nil
+}
@@ -0,0 +1,160 @@
+package retention
+
+import (
+	"context"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/pkg/chunkenc"
+	"github.com/grafana/loki/pkg/iter"
+	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/logql/log"
+	"github.com/grafana/loki/pkg/storage/chunk"
+	"github.com/grafana/loki/pkg/storage/config"
+	util_log "github.com/grafana/loki/pkg/util/log"
+)
+
+func defaultChunkMergeConfig() ChunkMergeConfig {
+	var cfg ChunkMergeConfig
+	cfg.RegisterFlagsWithPrefix("", flag.NewFlagSet("test", flag.PanicOnError))
+	cfg.Enabled = true
+	return cfg
+}
+
+func TestChunkMerger_MergeChunks(t *testing.T) {
+	store := newTestStore(t)
+	// tsdb period of the schema
+	tableStart := model.TimeFromUnix(start.Add(150*time.Hour).Unix() / 86400 * 86400)
+	tableName := schemaCfg.Configs[4].IndexTables.TableFor(tableStart)
+
+	fooBar := labels.FromStrings("foo", "bar")
+	fooBuzz := labels.FromStrings("foo", "buzz")
+	small := []chunk.Chunk{
+		createChunk(t, "1", fooBar, tableStart.Add(time.Hour), tableStart.Add(2*time.Hour)),
+		// the entry of the overlap with the previous chunk is only written once
+		createChunk(t, "1", fooBar, tableStart.Add(2*time.Hour), tableStart.Add(3*time.Hour)),
+		createChunk(t, "1", fooBar, tableStart.Add(5*time.Hour), tableStart.Add(6*time.Hour)),
+	}
+	notMerged := []chunk.Chunk{
+		// also indexed by the next table
+		createChunk(t, "1", fooBar, tableStart.Add(23*time.Hour), tableStart.Add(25*time.Hour)),
+		// alone in its stream
+		createChunk(t, "1", fooBuzz, tableStart.Add(time.Hour), tableStart.Add(2*time.Hour)),
+	}
+	require.NoError(t, store.Put(context.Background(), append(small, notMerged...)))
+
+	// the merged chunks are written with the chunk format of the schema, which needs to support structured metadata
+	mergeSchemaCfg := schemaCfg
+	mergeSchemaCfg.Configs = append([]config.PeriodConfig{}, schemaCfg.Configs...)
+	mergeSchemaCfg.Configs[4].Schema = "v13"
+
+	workDir := t.TempDir()
+	merger, err := NewChunkMerger(workDir, defaultChunkMergeConfig(), store.chunkClient, mergeSchemaCfg, nil)
+	require.NoError(t, err)
+
+	table := store.tables[tableName]
+	replacedChunks, err := merger.MergeChunks(context.Background(), tableName, "1", table, util_log.Logger)
+	require.NoError(t, err)
+	smallChunkIDs := make([]string, 0, len(small))
+	for _, c := range small {
+		smallChunkIDs = append(smallChunkIDs, getChunkID(c.ChunkRef))
+	}
+	require.ElementsMatch(t, smallChunkIDs, replacedChunks)
+
+	// the small chunks are replaced by the merged one in the index, and only marked for deletion once asked to
+	_, err = os.Stat(filepath.Join(workDir, MarkersFolder))
+	require.True(t, os.IsNotExist(err))
+	require.NoError(t, merger.MarkForDelete(replacedChunks))
+	markers, err := os.ReadDir(filepath.Join(workDir, MarkersFolder))
+	require.NoError(t, err)
+	require.Len(t, markers, 1)
+
+	var merged []chunk.Chunk
+	for _, chk := range table.chunks["1"] {
+		for _, c := range small {
+			require.NotEqual(t, c.ChunkRef, chk.ChunkRef)
+		}
+		if chk.Metric.Get("foo") == "bar" && chk.From < tableStart.Add(23*time.Hour) {
+			merged = append(merged, chk)
+		}
+	}
+	require.Len(t, table.chunks["1"], 3)
+	require.Len(t, merged, 1)
+	require.Equal(t, tableStart.Add(time.Hour), merged[0].From)
+	require.Equal(t, tableStart.Add(6*time.Hour), merged[0].Through)
+
+	// the merged chunk is uploaded with all the entries of the small ones
+	fetched, err := store.chunkClient.GetChunks(context.Background(), []chunk.Chunk{merged[0]})
+	require.NoError(t, err)
+	require.Len(t, fetched, 1)
+
+	it, err := fetched[0].Data.(*chunkenc.Facade).LokiChunk().Iterator(context.Background(), merged[0].From.Time(), merged[0].Through.Add(time.Minute).Time(), logproto.FORWARD, log.NewNoopPipeline().ForStream(fooBar), iter.WithKeepStructuredMetadata())
+	require.NoError(t, err)
+	for _, interval := range []model.Interval{
+		{Start: tableStart.Add(time.Hour), End: tableStart.Add(3 * time.Hour)},
+		{Start: tableStart.Add(5 * time.Hour), End: tableStart.Add(6 * time.Hour)},
+	} {
+		for curr := interval.Start; curr <= interval.End; curr = curr.Add(time.Minute) {
+			require.True(t, it.Next())
+			require.Equal(t, logproto.Entry{
+				Timestamp:          curr.Time(),
+				Line:               curr.String(),
+				StructuredMetadata: logproto.FromLabelsToLabelAdapters(labels.FromStrings("foo", curr.String())),
+			}, it.Entry())
+		}
+	}
+	require.False(t, it.Next())
+
+	// there is nothing left to merge
+	replacedChunks, err = merger.MergeChunks(context.Background(), tableName, "1", table, util_log.Logger)
+	require.NoError(t, err)
+	require.Empty(t, replacedChunks)
+}
+
+func TestChunkMerger_groupSmallChunks(t *testing.T) {
+	cfg := defaultChunkMergeConfig()
+	cfg.TargetSize = 10
+	merger := &ChunkMerger{cfg: cfg}
+
+	candidates := func(sizes ...int) []mergeCandidate {
+		res := make([]mergeCandidate, 0, len(sizes))
+		for i, size := range sizes {
+			res = append(res, mergeCandidate{chunkID: string(rune('a' + i)), from: model.Time(i), through: model.Time(i + 1), size: size})
+		}
+		return res
+	}
+	ids := func(groups [][]mergeCandidate) [][]string {
+		res := [][]string{}
+		for _, group := range groups {
+			var g []string
+			for _, c := range group {
+				g = append(g, c.chunkID)
+			}
+			res = append(res, g)
+		}
+		return res
+	}
+
+	for _, tc := range []struct {
+		name     string
+		sizes    []int
+		expected [][]string
+	}{
+		{"no chunks", nil, [][]string{}},
+		{"single small chunk", []int{1}, [][]string{}},
+		{"small chunks", []int{1, 2, 3}, [][]string{{"a", "b", "c"}}},
+		{"big chunks split the groups", []int{1, 2, 10, 3, 20, 4, 5}, [][]string{{"a", "b"}, {"f", "g"}}},
+		{"groups are bounded", []int{9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9}, [][]string{{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k"}}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, ids(merger.groupSmallChunks(candidates(tc.sizes...))))
+		})
+	}
+}
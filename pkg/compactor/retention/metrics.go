@@ -74,3 +74,50 @@ func newMarkerMetrics(r prometheus.Registerer) *markerMetrics {
 		}, []string{"table", "status"}),
 	}
 }
+
+type expirationMetrics struct {
+	deletedLinesTotal *prometheus.CounterVec
+	deletedBytesTotal *prometheus.CounterVec
+}
+
+func newExpirationMetrics(r prometheus.Registerer) *expirationMetrics {
+	return &expirationMetrics{
+		deletedLinesTotal: promauto.With(r).NewCounterVec(prometheus.CounterOpts{
+			Namespace: "loki",
+			Name:      "compactor_retention_filter_deleted_lines_total",
+			Help:      "Total number of lines deleted by the retention rules with a filter.",
+		}, []string{"user", "rule"}),
+		deletedBytesTotal: promauto.With(r).NewCounterVec(prometheus.CounterOpts{
+			Namespace: "loki",
+			Name:      "compactor_retention_filter_deleted_bytes_total",
+			Help:      "Total bytes of the lines, including their structured metadata, deleted by the retention rules with a filter.",
+		}, []string{"user", "rule"}),
+	}
+}
+
+type chunkMergerMetrics struct {
+	tableProcessedDurationSeconds *prometheus.HistogramVec
+	sourceChunksTotal             prometheus.Counter
+	createdChunksTotal            prometheus.Counter
+}
+
+func newChunkMergerMetrics(r prometheus.Registerer) *chunkMergerMetrics {
+	return &chunkMergerMetrics{
+		tableProcessedDurationSeconds: promauto.With(r).NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "loki",
+			Name:      "compactor_chunk_merge_table_processed_duration_seconds",
+			Help:      "Time (in seconds) spent in merging the small chunks of a table",
+			Buckets:   []float64{1, 2.5, 5, 10, 20, 40, 90, 360, 600, 1800},
+		}, []string{"table", "status"}),
+		sourceChunksTotal: promauto.With(r).NewCounter(prometheus.CounterOpts{
+			Namespace: "loki",
+			Name:      "compactor_chunk_merge_source_chunks_total",
+			Help:      "Total number of small chunks merged and marked for deletion.",
+		}),
+		createdChunksTotal: promauto.With(r).NewCounter(prometheus.CounterOpts{
+			Namespace: "loki",
+			Name:      "compactor_chunk_merge_created_chunks_total",
+			Help:      "Total number of chunks created by merging small chunks.",
+		}),
+	}
+}
@@ -45,6 +45,10 @@ func (c ChunkRef) String() string {
 type ChunkEntry struct {
 	ChunkRef
 	Labels labels.Labels
+	// KB is the approximate uncompressed size of the chunk in KB and Entries its number of entries.
+	// They are only known when Entries is not zero, depending on the index type.
+	KB      uint32
+	Entries uint32
 }
 
 type ChunkEntryCallback func(ChunkEntry) (deleteChunk bool, err error)
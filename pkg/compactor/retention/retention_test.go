@@ -26,6 +26,7 @@ import (
 	"github.com/grafana/loki/pkg/logproto"
 	"github.com/grafana/loki/pkg/logql/log"
 	"github.com/grafana/loki/pkg/storage/chunk"
+	"github.com/grafana/loki/pkg/storage/chunk/client"
 	"github.com/grafana/loki/pkg/util/filter"
 	util_log "github.com/grafana/loki/pkg/util/log"
 	"github.com/grafana/loki/pkg/validation"
@@ -142,7 +143,7 @@ func Test_Retention(t *testing.T) {
 			store.Stop()
 
 			// marks and sweep
-			expiration := NewExpirationChecker(tt.limits)
+			expiration := NewExpirationChecker(tt.limits, "", nil)
 			workDir := filepath.Join(t.TempDir(), "retention")
 			chunkClient := &mockChunkClient{deletedChunks: map[string]struct{}{}}
 			sweep, err := NewSweeper(workDir, chunkClient, 10, 0, nil)
@@ -198,11 +199,11 @@ func Test_EmptyTable(t *testing.T) {
 	tables := store.indexTables()
 	require.Len(t, tables, 1)
 	// Set a very low retention to make sure all chunks are marked for deletion which will create an empty table.
-	empty, _, err := markForDelete(context.Background(), 0, tables[0].name, noopWriter{}, tables[0], NewExpirationChecker(&fakeLimits{perTenant: map[string]retentionLimit{"1": {retentionPeriod: time.Second}, "2": {retentionPeriod: time.Second}}}), nil, util_log.Logger)
+	empty, _, err := markForDelete(context.Background(), 0, tables[0].name, noopWriter{}, tables[0], NewExpirationChecker(&fakeLimits{perTenant: map[string]retentionLimit{"1": {retentionPeriod: time.Second}, "2": {retentionPeriod: time.Second}}}, "", nil), nil, util_log.Logger)
 	require.NoError(t, err)
 	require.True(t, empty)
 
-	_, _, err = markForDelete(context.Background(), 0, tables[0].name, noopWriter{}, newTable("test"), NewExpirationChecker(&fakeLimits{}), nil, util_log.Logger)
+	_, _, err = markForDelete(context.Background(), 0, tables[0].name, noopWriter{}, newTable("test"), NewExpirationChecker(&fakeLimits{}, "", nil), nil, util_log.Logger)
 	require.Equal(t, err, errNoChunksFound)
 }
 
@@ -900,6 +901,68 @@ func TestDeleteTimeout(t *testing.T) {
 	}
 }
 
+type getChunksCounter struct {
+	client.Client
+	calls int
+}
+
+func (c *getChunksCounter) GetChunks(ctx context.Context, chunks []chunk.Chunk) ([]chunk.Chunk, error) {
+	c.calls++
+	return c.Client.GetChunks(ctx, chunks)
+}
+
+func TestMarkForDelete_FilterRetention(t *testing.T) {
+	schema := allSchemas[3]
+	store := newTestStore(t)
+	tableInterval := ExtractIntervalFromTableName(schema.config.IndexTables.TableFor(model.Now().Add(-72 * time.Hour)))
+
+	// The lines are the timestamps of the chunk in seconds, one every minute, the filter selects one line every 5 minutes.
+	limits := defaultLimitsTestConfig()
+	limits.StreamRetention = []validation.StreamRetention{
+		{Period: model.Duration(24 * time.Hour), Selector: `{foo="1"}`, Filter: `|~ "00$"`},
+	}
+	require.NoError(t, limits.Validate())
+	expiration := NewExpirationChecker(fakeLimits{
+		defaultLimit: retentionLimit{retentionPeriod: 2160 * time.Hour},
+		perTenant: map[string]retentionLimit{
+			"1": {retentionPeriod: 2160 * time.Hour, streamRetention: limits.StreamRetention},
+		},
+	}, t.TempDir(), nil)
+
+	c := createChunk(t, "1", labels.Labels{labels.Label{Name: "foo", Value: "1"}}, tableInterval.Start.Add(time.Hour), tableInterval.Start.Add(2*time.Hour))
+	require.NoError(t, store.Put(context.TODO(), []chunk.Chunk{c}))
+	store.Stop()
+
+	tables := store.indexTables()
+	require.Len(t, tables, 1)
+	table := tables[0]
+	chunkClient := &getChunksCounter{Client: store.chunkClient}
+
+	// The first pass rewrites the chunk without the filtered lines.
+	expiration.MarkPhaseStarted()
+	require.True(t, expiration.IntervalMayHaveExpiredChunks(tableInterval, ""))
+	empty, modified, err := markForDelete(context.Background(), 0, table.name, noopWriter{}, table, expiration, newChunkRewriter(chunkClient, table.name, table), util_log.Logger)
+	require.NoError(t, err)
+	require.False(t, empty)
+	require.True(t, modified)
+	require.Equal(t, 1, chunkClient.calls)
+	require.Len(t, table.chunks["1"], 1)
+	require.NotEqual(t, getChunkID(c.ChunkRef), getChunkID(table.chunks["1"][0].ChunkRef))
+	expiration.MarkPhaseFinished()
+
+	// The second pass neither reads nor rewrites the filtered chunk.
+	expiration.MarkPhaseStarted()
+	require.False(t, expiration.IntervalMayHaveExpiredChunks(tableInterval, ""))
+	rewritten := table.chunks["1"][0]
+	empty, modified, err = markForDelete(context.Background(), 0, table.name, noopWriter{}, table, expiration, newChunkRewriter(chunkClient, table.name, table), util_log.Logger)
+	require.NoError(t, err)
+	require.False(t, empty)
+	require.False(t, modified)
+	require.Equal(t, 1, chunkClient.calls)
+	require.Equal(t, []chunk.Chunk{rewritten}, table.chunks["1"])
+	expiration.MarkPhaseFinished()
+}
+
 func TestMarkForDelete_DropChunkFromIndex(t *testing.T) {
 	schema := allSchemas[2]
 	store := newTestStore(t)
@@ -927,7 +990,7 @@ func TestMarkForDelete_DropChunkFromIndex(t *testing.T) {
 
 	for i, table := range tables {
 		empty, _, err := markForDelete(context.Background(), 0, table.name, noopWriter{}, table,
-			NewExpirationChecker(fakeLimits{perTenant: map[string]retentionLimit{"1": {retentionPeriod: retentionPeriod}}}), nil, util_log.Logger)
+			NewExpirationChecker(fakeLimits{perTenant: map[string]retentionLimit{"1": {retentionPeriod: retentionPeriod}}}, "", nil), nil, util_log.Logger)
 		require.NoError(t, err)
 		if i == 7 {
 			require.False(t, empty)
@@ -3,6 +3,7 @@ package retention
 import (
 	"context"
 	"fmt"
+	"math"
 	"path/filepath"
 	"sort"
 	"testing"
@@ -149,7 +150,8 @@ func (t *table) ForEachChunk(ctx context.Context, callback ChunkEntryCallback) e
 			}
 		}
 
-		t.chunks[userID] = t.chunks[userID][:i]
+		// keep the chunks indexed by the callback while iterating
+		t.chunks[userID] = append(t.chunks[userID][:i], t.chunks[userID][len(chks):]...)
 	}
 
 	return ctx.Err()
@@ -315,7 +317,9 @@ func entryFromChunk(c chunk.Chunk) ChunkEntry {
 			From:     c.From,
 			Through:  c.Through,
 		},
-		Labels: labels.NewBuilder(c.Metric).Del(labels.MetricName).Labels(),
+		Labels:  labels.NewBuilder(c.Metric).Del(labels.MetricName).Labels(),
+		KB:      uint32(math.Round(float64(c.Data.UncompressedSize()) / float64(1<<10))),
+		Entries: uint32(c.Data.Entries()),
 	}
 }
 
@@ -74,6 +74,7 @@ type table struct {
 	indexStorageClient storage.Client
 	indexCompactor     IndexCompactor
 	tableMarker        retention.TableMarker
+	chunkMerger        retention.TableChunkMerger
 	expirationChecker  tableExpirationChecker
 	periodConfig       config.PeriodConfig
 
@@ -88,7 +89,7 @@ type table struct {
 
 func newTable(ctx context.Context, workingDirectory string, indexStorageClient storage.Client,
 	indexCompactor IndexCompactor, periodConfig config.PeriodConfig,
-	tableMarker retention.TableMarker, expirationChecker tableExpirationChecker,
+	tableMarker retention.TableMarker, chunkMerger retention.TableChunkMerger, expirationChecker tableExpirationChecker,
 	uploadConcurrency int,
 ) (*table, error) {
 	err := chunk_util.EnsureDirectory(workingDirectory)
@@ -103,6 +104,7 @@ func newTable(ctx context.Context, workingDirectory string, indexStorageClient s
 		indexStorageClient: indexStorageClient,
 		indexCompactor:     indexCompactor,
 		tableMarker:        tableMarker,
+		chunkMerger:        chunkMerger,
 		expirationChecker:  expirationChecker,
 		periodConfig:       periodConfig,
 		indexSets:          map[string]*indexSet{},
@@ -115,7 +117,7 @@ func newTable(ctx context.Context, workingDirectory string, indexStorageClient s
 	return &table, nil
 }
 
-func (t *table) compact(applyRetention bool) error {
+func (t *table) compact(applyRetention, mergeChunks bool) error {
 	t.indexStorageClient.RefreshIndexTableCache(t.ctx, t.name)
 	indexFiles, usersWithPerUserIndex, err := t.indexStorageClient.ListFiles(t.ctx, t.name, false)
 	if err != nil {
@@ -181,6 +183,13 @@ func (t *table) compact(applyRetention bool) error {
 		}
 	}
 
+	if mergeChunks {
+		err := t.mergeChunks()
+		if err != nil {
+			return err
+		}
+	}
+
 	return t.done()
 }
 
@@ -245,6 +254,29 @@ func (t *table) applyRetention() error {
 	return nil
 }
 
+// mergeChunks merges the small chunks of the index sets
+func (t *table) mergeChunks() error {
+	for userID, is := range t.indexSets {
+		// make sure we do not merge chunks of common index set which got compacted away to per-user index
+		if userID == "" && is.compactedIndex == nil && is.removeSourceObjects && !is.uploadCompactedDB {
+			continue
+		}
+
+		if is.compactedIndex == nil && len(is.ListSourceFiles()) == 1 {
+			if err := t.openCompactedIndexForRetention(is); err != nil {
+				return err
+			}
+		}
+
+		err := is.runChunkMerge(t.chunkMerger)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (t *table) openCompactedIndexForRetention(idxSet *indexSet) error {
 	sourceFiles := idxSet.ListSourceFiles()
 	if len(sourceFiles) != 1 {
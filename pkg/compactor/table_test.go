@@ -2,7 +2,9 @@ package compactor
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -13,6 +15,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/loki/pkg/compactor/retention"
+	"github.com/grafana/loki/pkg/storage/chunk/client"
 	"github.com/grafana/loki/pkg/storage/chunk/client/local"
 	"github.com/grafana/loki/pkg/storage/config"
 	"github.com/grafana/loki/pkg/storage/stores/shipper/indexshipper/storage"
@@ -199,10 +202,10 @@ func TestTable_Compaction(t *testing.T) {
 					require.NoError(t, err)
 
 					table, err := newTable(context.Background(), tableWorkingDirectory, storage.NewIndexStorageClient(objectClient, ""),
-						newTestIndexCompactor(), config.PeriodConfig{}, nil, nil, 10)
+						newTestIndexCompactor(), config.PeriodConfig{}, nil, nil, nil, 10)
 					require.NoError(t, err)
 
-					require.NoError(t, table.compact(false))
+					require.NoError(t, table.compact(false, false))
 
 					numUserIndexSets, numCommonIndexSets := 0, 0
 					for _, is := range table.indexSets {
@@ -236,10 +239,10 @@ func TestTable_Compaction(t *testing.T) {
 
 					// running compaction again should not do anything.
 					table, err = newTable(context.Background(), tableWorkingDirectory, storage.NewIndexStorageClient(objectClient, ""),
-						newTestIndexCompactor(), config.PeriodConfig{}, nil, nil, 10)
+						newTestIndexCompactor(), config.PeriodConfig{}, nil, nil, nil, 10)
 					require.NoError(t, err)
 
-					require.NoError(t, table.compact(false))
+					require.NoError(t, table.compact(false, false))
 
 					for _, is := range table.indexSets {
 						require.False(t, is.uploadCompactedDB)
@@ -377,18 +380,101 @@ func TestTable_CompactionRetention(t *testing.T) {
 
 				table, err := newTable(context.Background(), tableWorkingDirectory, storage.NewIndexStorageClient(objectClient, ""),
 					newTestIndexCompactor(), config.PeriodConfig{},
-					tt.tableMarker, IntervalMayHaveExpiredChunksFunc(func(interval model.Interval, userID string) bool {
+					tt.tableMarker, nil, IntervalMayHaveExpiredChunksFunc(func(interval model.Interval, userID string) bool {
 						return true
 					}), 10)
 				require.NoError(t, err)
 
-				require.NoError(t, table.compact(true))
+				require.NoError(t, table.compact(true, false))
 				tt.assert(t, objectStoragePath, tableName)
 			})
 		}
 	}
 }
 
+type fakeChunkMerger struct {
+	mergedUsers  []string
+	markedChunks []string
+}
+
+func (f *fakeChunkMerger) MergeChunks(_ context.Context, _, userID string, _ retention.IndexProcessor, _ log.Logger) ([]string, error) {
+	f.mergedUsers = append(f.mergedUsers, userID)
+	return []string{"chunk-" + userID}, nil
+}
+
+func (f *fakeChunkMerger) MarkForDelete(chunkIDs []string) error {
+	f.markedChunks = append(f.markedChunks, chunkIDs...)
+	return nil
+}
+
+type failingPutObjectClient struct {
+	client.ObjectClient
+}
+
+func (failingPutObjectClient) PutObject(_ context.Context, _ string, _ io.ReadSeeker) error {
+	return errors.New("put failed")
+}
+
+func TestTable_CompactionChunkMerge(t *testing.T) {
+	numUsers := 10
+	for _, tc := range []struct {
+		name        string
+		mergeChunks bool
+		failUpload  bool
+	}{
+		{name: "merge chunks", mergeChunks: true},
+		{name: "no merge"},
+		{name: "upload failure", mergeChunks: true, failUpload: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tempDir := t.TempDir()
+			tableName := fmt.Sprintf("%s12345", tableName)
+
+			objectStoragePath := filepath.Join(tempDir, objectsStorageDirName)
+			tableWorkingDirectory := filepath.Join(tempDir, workingDirName, tableName)
+
+			// a single compacted file per index set needs to be opened for merging chunks
+			SetupTable(t, filepath.Join(objectStoragePath, tableName), IndexesConfig{NumCompactedFiles: 1}, PerUserIndexesConfig{
+				IndexesConfig: IndexesConfig{NumCompactedFiles: 1},
+				NumUsers:      numUsers,
+			})
+
+			var objectClient client.ObjectClient
+			objectClient, err := local.NewFSObjectClient(local.FSConfig{Directory: objectStoragePath})
+			require.NoError(t, err)
+			if tc.failUpload {
+				objectClient = failingPutObjectClient{objectClient}
+			}
+
+			chunkMerger := &fakeChunkMerger{}
+			table, err := newTable(context.Background(), tableWorkingDirectory, storage.NewIndexStorageClient(objectClient, ""),
+				newTestIndexCompactor(), config.PeriodConfig{}, nil, chunkMerger, nil, 10)
+			require.NoError(t, err)
+
+			err = table.compact(false, tc.mergeChunks)
+			if !tc.mergeChunks {
+				require.NoError(t, err)
+				require.Empty(t, chunkMerger.mergedUsers)
+				return
+			}
+			require.Len(t, chunkMerger.mergedUsers, numUsers+1)
+
+			// the replaced chunks are only marked for deletion once the modified index is uploaded
+			if tc.failUpload {
+				require.Error(t, err)
+				require.Empty(t, chunkMerger.markedChunks)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, chunkMerger.markedChunks, numUsers+1)
+			validateTable(t, filepath.Join(objectStoragePath, tableName), 1, numUsers, func(filename string) {
+				// the modified index is uploaded compressed
+				require.True(t, strings.HasSuffix(filename, ".gz"))
+			})
+		})
+	}
+}
+
 func validateTable(t *testing.T, path string, expectedNumCommonDBs, numUsers int, filesCallback func(filename string)) {
 	files, folders := listDir(t, path)
 	require.Len(t, files, expectedNumCommonDBs)
@@ -452,11 +538,11 @@ func TestTable_CompactionFailure(t *testing.T) {
 	require.NoError(t, err)
 
 	table, err := newTable(context.Background(), tableWorkingDirectory, storage.NewIndexStorageClient(objectClient, ""),
-		newTestIndexCompactor(), config.PeriodConfig{}, nil, nil, 10)
+		newTestIndexCompactor(), config.PeriodConfig{}, nil, nil, nil, 10)
 	require.NoError(t, err)
 
 	// compaction should fail due to a non-boltdb file.
-	require.Error(t, table.compact(false))
+	require.Error(t, table.compact(false, false))
 
 	// ensure that files in storage are intact.
 	files, err := os.ReadDir(tablePathInStorage)
@@ -470,9 +556,9 @@ func TestTable_CompactionFailure(t *testing.T) {
 	require.NoError(t, os.Remove(filepath.Join(tablePathInStorage, "fail.gz")))
 
 	table, err = newTable(context.Background(), tableWorkingDirectory, storage.NewIndexStorageClient(objectClient, ""),
-		newTestIndexCompactor(), config.PeriodConfig{}, nil, nil, 10)
+		newTestIndexCompactor(), config.PeriodConfig{}, nil, nil, nil, 10)
 	require.NoError(t, err)
-	require.NoError(t, table.compact(false))
+	require.NoError(t, table.compact(false, false))
 
 	// ensure that we have cleanup the local working directory after successful compaction.
 	require.NoFileExists(t, tableWorkingDirectory)
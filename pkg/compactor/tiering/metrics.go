@@ -0,0 +1,43 @@
+package tiering
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	statusFailure = "failure"
+	statusSuccess = "success"
+)
+
+type metrics struct {
+	runsTotal         *prometheus.CounterVec
+	lastSuccess       prometheus.Gauge
+	movedObjectsTotal *prometheus.CounterVec
+	movedBytesTotal   *prometheus.CounterVec
+}
+
+func newMetrics(r prometheus.Registerer) *metrics {
+	return &metrics{
+		runsTotal: promauto.With(r).NewCounterVec(prometheus.CounterOpts{
+			Namespace: "loki",
+			Name:      "compactor_cold_storage_runs_total",
+			Help:      "Total number of runs moving data to the cold storage by status",
+		}, []string{"status"}),
+		lastSuccess: promauto.With(r).NewGauge(prometheus.GaugeOpts{
+			Namespace: "loki",
+			Name:      "compactor_cold_storage_last_successful_run_timestamp_seconds",
+			Help:      "Unix timestamp of the last successful run moving data to the cold storage",
+		}),
+		movedObjectsTotal: promauto.With(r).NewCounterVec(prometheus.CounterOpts{
+			Namespace: "loki",
+			Name:      "compactor_cold_storage_moved_objects_total",
+			Help:      "Total number of objects moved to the cold storage by type",
+		}, []string{"type"}),
+		movedBytesTotal: promauto.With(r).NewCounterVec(prometheus.CounterOpts{
+			Namespace: "loki",
+			Name:      "compactor_cold_storage_moved_bytes_total",
+			Help:      "Total bytes of the objects moved to the cold storage by type",
+		}, []string{"type"}),
+	}
+}
@@ -0,0 +1,315 @@
+package tiering
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/loki/pkg/storage/chunk"
+	"github.com/grafana/loki/pkg/storage/chunk/client"
+	"github.com/grafana/loki/pkg/storage/config"
+	util_log "github.com/grafana/loki/pkg/util/log"
+	"github.com/grafana/loki/pkg/validation"
+)
+
+const (
+	objectTypeChunk = "chunk"
+	objectTypeIndex = "index"
+
+	delimiter = "/"
+)
+
+type Limits interface {
+	ColdStorageAfter(userID string) time.Duration
+	DefaultLimits() *validation.Limits
+	AllByUserID() map[string]*validation.Limits
+}
+
+// Mover moves the chunks and index files older than the cold_storage_after limit of their
+// tenant from the hot tier to the cold tier of a TieredObjectClient.
+type Mover struct {
+	hot, cold    client.ObjectClient
+	indexPrefix  string
+	schemaConfig config.SchemaConfig
+	limits       Limits
+	metrics      *metrics
+}
+
+// NewMover makes a new Mover of the objects of the tiered client. indexPrefix is the
+// prefix of the keys of the index tables in the object store, whose periods are taken
+// from the schema config.
+func NewMover(objectClient *client.TieredObjectClient, indexPrefix string, schemaConfig config.SchemaConfig, limits Limits, r prometheus.Registerer) *Mover {
+	return &Mover{
+		hot:          objectClient.Hot(),
+		cold:         objectClient.Cold(),
+		indexPrefix:  indexPrefix,
+		schemaConfig: schemaConfig,
+		limits:       limits,
+		metrics:      newMetrics(r),
+	}
+}
+
+// Run moves the chunks and index files which are older than the cold_storage_after limit of their tenant at now.
+func (m *Mover) Run(ctx context.Context, now model.Time) (err error) {
+	status := statusSuccess
+	defer func() {
+		if err != nil {
+			status = statusFailure
+		}
+		m.metrics.runsTotal.WithLabelValues(status).Inc()
+		if status == statusSuccess {
+			m.metrics.lastSuccess.SetToCurrentTime()
+		}
+	}()
+
+	if err := m.moveIndex(ctx, now); err != nil {
+		return fmt.Errorf("failed to move index to cold storage: %w", err)
+	}
+	if err := m.moveChunks(ctx, now); err != nil {
+		return fmt.Errorf("failed to move chunks to cold storage: %w", err)
+	}
+	return nil
+}
+
+// moveIndex moves the index files of the tables older than the cold_storage_after limit.
+// Per tenant files are moved according to the limit of their tenant, while the files
+// shared by all the tenants are only moved once they are old enough for all the tenants.
+// Only the tables old enough for at least one tenant are listed.
+func (m *Mover) moveIndex(ctx context.Context, now model.Time) error {
+	latestCutoff, ok := m.latestCutoff(now)
+	if !ok {
+		return nil
+	}
+
+	_, tables, err := m.hot.List(ctx, m.indexPrefix, delimiter)
+	if err != nil {
+		return err
+	}
+
+	sharedCutoff, moveShared := m.sharedIndexCutoff(now)
+	for _, table := range tables {
+		tableName := strings.TrimSuffix(strings.TrimPrefix(string(table), m.indexPrefix), delimiter)
+		tableEnd, ok := m.tableEnd(tableName)
+		if !ok || !tableEnd.Before(latestCutoff) {
+			// Not an index table, for example the delete requests, or a table too recent for all tenants.
+			continue
+		}
+
+		sharedFiles, tenants, err := m.hot.List(ctx, string(table), delimiter)
+		if err != nil {
+			return err
+		}
+
+		if moveShared && tableEnd.Before(sharedCutoff) {
+			for _, file := range sharedFiles {
+				if err := m.move(ctx, file.Key, objectTypeIndex); err != nil {
+					return err
+				}
+			}
+		}
+
+		for _, tenant := range tenants {
+			userID := strings.TrimSuffix(strings.TrimPrefix(string(tenant), string(table)), delimiter)
+			cutoff, ok := m.cutoff(userID, now)
+			if !ok || !tableEnd.Before(cutoff) {
+				continue
+			}
+
+			files, _, err := m.hot.List(ctx, string(tenant), "")
+			if err != nil {
+				return err
+			}
+			for _, file := range files {
+				if err := m.move(ctx, file.Key, objectTypeIndex); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// moveChunks moves the chunks whose end is older than the cold_storage_after limit of their tenant.
+// The keys of the chunks only start with their tenant and fingerprint, not with their time, so all
+// the chunks of the tenants moving data are listed.
+func (m *Mover) moveChunks(ctx context.Context, now model.Time) error {
+	_, prefixes, err := m.hot.List(ctx, "", delimiter)
+	if err != nil {
+		return err
+	}
+
+	for _, prefix := range prefixes {
+		if string(prefix) == m.indexPrefix {
+			continue
+		}
+		userID := strings.TrimSuffix(string(prefix), delimiter)
+		cutoff, ok := m.cutoff(userID, now)
+		if !ok {
+			continue
+		}
+
+		objects, _, err := m.hot.List(ctx, string(prefix), "")
+		if err != nil {
+			return err
+		}
+		for _, object := range objects {
+			chk, ok := parseChunkKey(userID, object.Key)
+			if !ok || !chk.Through.Before(cutoff) {
+				continue
+			}
+			if err := m.move(ctx, object.Key, objectTypeChunk); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// cutoff returns the time before which the data of the tenant is moved to the cold storage,
+// or false if the data of the tenant is never moved.
+func (m *Mover) cutoff(userID string, now model.Time) (model.Time, bool) {
+	after := m.limits.ColdStorageAfter(userID)
+	if after <= 0 {
+		return 0, false
+	}
+	return now.Add(-after), true
+}
+
+// latestCutoff returns the latest time before which the data of a tenant is moved to the cold storage,
+// or false if no tenant moves its data.
+func (m *Mover) latestCutoff(now model.Time) (model.Time, bool) {
+	minAfter := m.limits.DefaultLimits().ColdStorageAfter
+	for _, limits := range m.limits.AllByUserID() {
+		if limits == nil || limits.ColdStorageAfter <= 0 {
+			continue
+		}
+		if minAfter <= 0 || limits.ColdStorageAfter < minAfter {
+			minAfter = limits.ColdStorageAfter
+		}
+	}
+	if minAfter <= 0 {
+		return 0, false
+	}
+	return now.Add(-time.Duration(minAfter)), true
+}
+
+// tableEnd returns the end of the index table with the period of the schema config it belongs to,
+// or false if the name isn't the one of an index table.
+func (m *Mover) tableEnd(tableName string) (model.Time, bool) {
+	for i, periodConfig := range m.schemaConfig.Configs {
+		period := periodConfig.IndexTables.Period
+		if period <= 0 || !strings.HasPrefix(tableName, periodConfig.IndexTables.Prefix) {
+			continue
+		}
+		tableNumber, err := strconv.ParseInt(strings.TrimPrefix(tableName, periodConfig.IndexTables.Prefix), 10, 64)
+		if err != nil {
+			continue
+		}
+
+		// The table must overlap the schema period, in case several periods share the same prefix.
+		start := model.TimeFromUnix(tableNumber * int64(period/time.Second))
+		end := start.Add(period)
+		if !end.After(periodConfig.From.Time) {
+			continue
+		}
+		if i+1 < len(m.schemaConfig.Configs) && !start.Before(m.schemaConfig.Configs[i+1].From.Time) {
+			continue
+		}
+		return end, true
+	}
+	return 0, false
+}
+
+// sharedIndexCutoff returns the time before which the index files shared by all the tenants
+// are moved to the cold storage, or false if a tenant never moves its data.
+func (m *Mover) sharedIndexCutoff(now model.Time) (model.Time, bool) {
+	maxAfter := m.limits.DefaultLimits().ColdStorageAfter
+	for _, limits := range m.limits.AllByUserID() {
+		if limits == nil {
+			continue
+		}
+		if limits.ColdStorageAfter <= 0 {
+			return 0, false
+		}
+		if limits.ColdStorageAfter > maxAfter {
+			maxAfter = limits.ColdStorageAfter
+		}
+	}
+	if maxAfter <= 0 {
+		return 0, false
+	}
+	return now.Add(-time.Duration(maxAfter)), true
+}
+
+// move copies the object to the cold tier before deleting it from the hot tier, so that
+// readers always find it in one of the tiers. The object is copied through a temporary
+// file rather than memory since uploads need to seek the object.
+func (m *Mover) move(ctx context.Context, key, objectType string) error {
+	reader, _, err := m.hot.GetObject(ctx, key)
+	if err != nil {
+		if m.hot.IsObjectNotFoundErr(err) {
+			// The object has been deleted since it was listed.
+			return nil
+		}
+		return err
+	}
+	defer reader.Close()
+
+	file, err := os.CreateTemp("", "cold-storage-")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			level.Warn(util_log.Logger).Log("msg", "failed to close temporary file", "file", file.Name(), "err", err)
+		}
+		if err := os.Remove(file.Name()); err != nil {
+			level.Warn(util_log.Logger).Log("msg", "failed to remove temporary file", "file", file.Name(), "err", err)
+		}
+	}()
+
+	size, err := io.Copy(file, reader)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	if err := m.cold.PutObject(ctx, key, file); err != nil {
+		return err
+	}
+	if err := m.hot.DeleteObject(ctx, key); err != nil && !m.hot.IsObjectNotFoundErr(err) {
+		return err
+	}
+
+	level.Debug(util_log.Logger).Log("msg", "moved object to cold storage", "key", key, "type", objectType)
+	m.metrics.movedObjectsTotal.WithLabelValues(objectType).Inc()
+	m.metrics.movedBytesTotal.WithLabelValues(objectType).Add(float64(size))
+	return nil
+}
+
+// parseChunkKey parses the key of a chunk of the tenant, including the keys
+// whose last part is base64 encoded by the filesystem object store.
+func parseChunkKey(userID, key string) (chunk.Chunk, bool) {
+	if chk, err := chunk.ParseExternalKey(userID, key); err == nil {
+		return chk, true
+	}
+
+	split := strings.LastIndexByte(key, '/')
+	tail, err := base64.StdEncoding.DecodeString(key[split+1:])
+	if err != nil {
+		return chunk.Chunk{}, false
+	}
+	chk, err := chunk.ParseExternalKey(userID, key[:split+1]+string(tail))
+	return chk, err == nil
+}
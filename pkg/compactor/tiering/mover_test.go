@@ -0,0 +1,117 @@
+package tiering
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/pkg/storage/chunk/client"
+	"github.com/grafana/loki/pkg/storage/chunk/client/local"
+	"github.com/grafana/loki/pkg/storage/config"
+	"github.com/grafana/loki/pkg/validation"
+)
+
+type fakeLimits struct {
+	defaultLimits validation.Limits
+	perTenant     map[string]time.Duration
+}
+
+func (f fakeLimits) ColdStorageAfter(userID string) time.Duration {
+	if after, ok := f.perTenant[userID]; ok {
+		return after
+	}
+	return time.Duration(f.defaultLimits.ColdStorageAfter)
+}
+
+func (f fakeLimits) DefaultLimits() *validation.Limits {
+	return &f.defaultLimits
+}
+
+func (f fakeLimits) AllByUserID() map[string]*validation.Limits {
+	res := make(map[string]*validation.Limits, len(f.perTenant))
+	for userID, after := range f.perTenant {
+		res[userID] = &validation.Limits{ColdStorageAfter: model.Duration(after)}
+	}
+	return res
+}
+
+func chunkKey(userID string, through model.Time) string {
+	return fmt.Sprintf("%s/%x/%x:%x:%x", userID, 42, int64(through.Add(-time.Hour)), int64(through), 1)
+}
+
+func fsChunkKey(userID string, through model.Time) string {
+	tail := base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%x:%x:%x", int64(through.Add(-time.Hour)), int64(through), 1)))
+	return fmt.Sprintf("%s/%x/%s", userID, 42, tail)
+}
+
+func TestMover(t *testing.T) {
+	ctx := context.Background()
+	hot, err := local.NewFSObjectClient(local.FSConfig{Directory: t.TempDir()})
+	require.NoError(t, err)
+	cold, err := local.NewFSObjectClient(local.FSConfig{Directory: t.TempDir()})
+	require.NoError(t, err)
+
+	now := model.TimeFromUnix(101 * 86400)
+	oldTable := "index/table_97"
+	newTable := "index/table_100"
+	// The weekly table 14 only ends at day 105.
+	weeklyTable := "index/weekly_14"
+	schemaConfig := config.SchemaConfig{Configs: []config.PeriodConfig{
+		{From: config.DayTime{Time: 0}, IndexTables: config.PeriodicTableConfig{Prefix: "table_", Period: 24 * time.Hour}},
+		{From: config.DayTime{Time: model.TimeFromUnix(98 * 86400)}, IndexTables: config.PeriodicTableConfig{Prefix: "weekly_", Period: 7 * 24 * time.Hour}},
+	}}
+
+	moved := []string{
+		chunkKey("cold", now.Add(-72*time.Hour)),
+		fsChunkKey("cold", now.Add(-96*time.Hour)),
+		oldTable + "/cold/index.gz",
+	}
+	notMoved := []string{
+		chunkKey("cold", now.Add(-time.Hour)),
+		chunkKey("hot", now.Add(-72*time.Hour)),
+		"cold/not-a-chunk",
+		newTable + "/cold/index.gz",
+		weeklyTable + "/cold/index.gz",
+		oldTable + "/hot/index.gz",
+		oldTable + "/shared.gz",
+		"index/delete_requests/delete_requests.gz",
+	}
+	for _, key := range append(moved, notMoved...) {
+		require.NoError(t, hot.PutObject(ctx, key, bytes.NewReader([]byte(key))))
+	}
+
+	limits := fakeLimits{perTenant: map[string]time.Duration{"cold": 48 * time.Hour, "hot": 0}}
+	mover := NewMover(client.NewTieredObjectClient(hot, cold), "index/", schemaConfig, limits, nil)
+	require.NoError(t, mover.Run(ctx, now))
+
+	requireTier := func(t *testing.T, key string, inTier, notInTier client.ObjectClient) {
+		t.Helper()
+		reader, _, err := inTier.GetObject(ctx, key)
+		require.NoError(t, err, key)
+		require.NoError(t, reader.Close())
+		_, _, err = notInTier.GetObject(ctx, key)
+		require.True(t, notInTier.IsObjectNotFoundErr(err), key)
+	}
+	for _, key := range moved {
+		requireTier(t, key, cold, hot)
+	}
+	for _, key := range notMoved {
+		requireTier(t, key, hot, cold)
+	}
+
+	// Shared index files are moved once all the tenants move their data.
+	limits.perTenant["hot"] = 24 * time.Hour
+	limits.defaultLimits.ColdStorageAfter = model.Duration(24 * time.Hour)
+	require.NoError(t, mover.Run(ctx, now))
+	for _, key := range []string{oldTable + "/shared.gz", oldTable + "/hot/index.gz", chunkKey("hot", now.Add(-72*time.Hour))} {
+		requireTier(t, key, cold, hot)
+	}
+	requireTier(t, newTable+"/cold/index.gz", hot, cold)
+	requireTier(t, "index/delete_requests/delete_requests.gz", hot, cold)
+}
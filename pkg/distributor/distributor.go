@@ -17,6 +17,7 @@ import (
 	"github.com/prometheus/prometheus/model/labels"
 	"google.golang.org/grpc/codes"
 
+	"github.com/grafana/dskit/concurrency"
 	"github.com/grafana/dskit/httpgrpc"
 	"github.com/grafana/dskit/kv"
 	"github.com/grafana/dskit/limiter"
@@ -31,18 +32,22 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 	"go.uber.org/atomic"
+	"golang.org/x/exp/slices"
 
 	"github.com/grafana/loki/pkg/analytics"
 	"github.com/grafana/loki/pkg/compactor/retention"
 	"github.com/grafana/loki/pkg/distributor/clientpool"
+	"github.com/grafana/loki/pkg/distributor/labelpolicy"
 	"github.com/grafana/loki/pkg/distributor/shardstreams"
 	"github.com/grafana/loki/pkg/distributor/writefailures"
 	"github.com/grafana/loki/pkg/ingester"
 	"github.com/grafana/loki/pkg/ingester/client"
 	"github.com/grafana/loki/pkg/logproto"
 	"github.com/grafana/loki/pkg/logql/syntax"
+	"github.com/grafana/loki/pkg/push"
 	"github.com/grafana/loki/pkg/runtime"
 	"github.com/grafana/loki/pkg/util"
+	"github.com/grafana/loki/pkg/util/httpreq"
 	util_log "github.com/grafana/loki/pkg/util/log"
 	"github.com/grafana/loki/pkg/validation"
 )
@@ -71,6 +76,8 @@ type Config struct {
 
 	// WriteFailuresLoggingCfg customizes write failures logging behavior.
 	WriteFailuresLogging writefailures.Cfg `yaml:"write_failures_logging" doc:"description=Experimental. Customize the logging of write failures."`
+
+	MaxAsyncPushes int `yaml:"max_async_pushes"`
 }
 
 // RegisterFlags registers distributor-related flags.
@@ -78,6 +85,7 @@ func (cfg *Config) RegisterFlags(fs *flag.FlagSet) {
 	cfg.DistributorRing.RegisterFlags(fs)
 	cfg.RateStore.RegisterFlagsWithPrefix("distributor.rate-store", fs)
 	cfg.WriteFailuresLogging.RegisterFlagsWithPrefix("distributor.write-failures-logging", fs)
+	fs.IntVar(&cfg.MaxAsyncPushes, "distributor.max-async-pushes", 1000, "Maximum number of push requests acknowledged with the async mode of the X-Loki-Ack-Mode header which are still being sent to the ingesters. Once reached, the push requests asking for the async mode are acknowledged once a quorum of ingesters received them. 0 to always wait for a quorum.")
 }
 
 // RateStore manages the ingestion rate of streams, populated by data fetched from ingesters.
@@ -113,15 +121,24 @@ type Distributor struct {
 	// Per-user rate limiter.
 	ingestionRateLimiter *limiter.RateLimiter
 	labelCache           *lru.Cache
+	// Distinct values of the labels limited by the label policies.
+	labelValues *labelpolicy.Tracker
 
 	// Push failures rate limiter.
 	writeFailuresManager *writefailures.Manager
 
+	// Number of push requests acknowledged before being sent to the ingesters which are still in flight.
+	asyncPushes *atomic.Int32
+
 	// metrics
 	ingesterAppends        *prometheus.CounterVec
 	ingesterAppendTimeouts *prometheus.CounterVec
 	replicationFactor      prometheus.Gauge
 	streamShardCount       prometheus.Counter
+	asyncPushFailures      *prometheus.CounterVec
+
+	ingestionPipelineMetrics *ingestionPipelineMetrics
+	ingestionPipelines       *ingestionPipelinesCache
 }
 
 // New a distributor creates.
@@ -173,9 +190,11 @@ func New(
 		validator:             validator,
 		pool:                  clientpool.NewPool("ingester", clientCfg.PoolConfig, ingestersRing, factory, util_log.Logger),
 		labelCache:            labelCache,
+		labelValues:           labelpolicy.NewTracker(),
 		shardTracker:          NewShardTracker(),
 		healthyInstancesCount: atomic.NewUint32(0),
 		rateLimitStrat:        rateLimitStrat,
+		asyncPushes:           atomic.NewInt32(0),
 		ingesterAppends: promauto.With(registerer).NewCounterVec(prometheus.CounterOpts{
 			Namespace: "loki",
 			Name:      "distributor_ingester_appends_total",
@@ -196,7 +215,14 @@ func New(
 			Name:      "stream_sharding_count",
 			Help:      "Total number of times the distributor has sharded streams",
 		}),
-		writeFailuresManager: writefailures.NewManager(util_log.Logger, registerer, cfg.WriteFailuresLogging, configs, "distributor"),
+		asyncPushFailures: promauto.With(registerer).NewCounterVec(prometheus.CounterOpts{
+			Namespace: "loki",
+			Name:      "distributor_async_push_failures_total",
+			Help:      "The total number of push requests acknowledged before being sent to the ingesters which failed to reach a quorum of ingesters.",
+		}, []string{"tenant"}),
+		writeFailuresManager:     writefailures.NewManager(util_log.Logger, registerer, cfg.WriteFailuresLogging, configs, "distributor"),
+		ingestionPipelineMetrics: newIngestionPipelineMetrics(registerer),
+		ingestionPipelines:       newIngestionPipelinesCache(),
 	}
 
 	if overrides.IngestionRateStrategy() == validation.GlobalIngestionRateStrategy {
@@ -219,6 +245,11 @@ func New(
 	d.distributorsLifecycler = distributorsLifecycler
 
 	d.replicationFactor.Set(float64(ingestersRing.ReplicationFactor()))
+	promauto.With(registerer).NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: "loki",
+		Name:      "distributor_async_pushes_in_flight",
+		Help:      "The number of push requests acknowledged before being sent to the ingesters which are still in flight.",
+	}, func() float64 { return float64(d.asyncPushes.Load()) })
 	rfStats.Set(int64(ingestersRing.ReplicationFactor()))
 
 	rs := NewRateStore(
@@ -236,7 +267,7 @@ func New(
 	)
 	d.rateStore = rs
 
-	servs = append(servs, d.pool, rs)
+	servs = append(servs, d.pool, rs, d.labelValues)
 	d.subservices, err = services.NewManager(servs...)
 	if err != nil {
 		return nil, errors.Wrap(err, "services manager")
@@ -306,6 +337,16 @@ func (d *Distributor) Push(ctx context.Context, req *logproto.PushRequest) (*log
 	var validationErrors util.GroupedErrors
 	validationContext := d.validator.getValidationContextForTime(time.Now(), tenantID)
 
+	labelPolicy := d.validator.Limits.LabelPolicy(tenantID)
+	pipelines, release, err := d.ingestionPipelines.get(tenantID, d.validator.Limits.IngestionPipelines(tenantID))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		pipelines.updateMetrics(d.ingestionPipelineMetrics, tenantID)
+		release()
+	}()
+
 	func() {
 		sp := opentracing.SpanFromContext(ctx)
 		if sp != nil {
@@ -336,6 +377,13 @@ func (d *Distributor) Push(ctx context.Context, req *logproto.PushRequest) (*log
 				continue
 			}
 
+			if labelPolicy.Enabled() {
+				if err := d.applyLabelPolicy(validationContext, labelPolicy, &stream); err != nil {
+					validationErrors.Add(err)
+					continue
+				}
+			}
+
 			n := 0
 			pushSize := 0
 			prevTs := stream.Entries[0].Timestamp
@@ -370,14 +418,17 @@ func (d *Distributor) Push(ctx context.Context, req *logproto.PushRequest) (*log
 			}
 			stream.Entries = stream.Entries[:n]
 
-			shardStreamsCfg := d.validator.Limits.ShardStreams(tenantID)
-			if shardStreamsCfg.Enabled {
-				derivedKeys, derivedStreams := d.shardStream(stream, pushSize, tenantID)
-				keys = append(keys, derivedKeys...)
-				streams = append(streams, derivedStreams...)
-			} else {
-				keys = append(keys, util.TokenFor(tenantID, stream.Labels))
-				streams = append(streams, streamTracker{stream: stream})
+			if len(pipelines) == 0 {
+				keys, streams = d.appendStream(keys, streams, stream, pushSize, tenantID)
+				continue
+			}
+
+			for _, processed := range d.applyIngestionPipelines(validationContext, pipelines, stream, &validationErrors) {
+				pushSize := 0
+				for _, e := range processed.Entries {
+					pushSize += len(e.Line)
+				}
+				keys, streams = d.appendStream(keys, streams, processed, pushSize, tenantID)
 			}
 		}
 	}()
@@ -403,6 +454,8 @@ func (d *Distributor) Push(ctx context.Context, req *logproto.PushRequest) (*log
 		return nil, httpgrpc.Errorf(http.StatusTooManyRequests, err.Error())
 	}
 
+	ackMode := httpreq.PushAckMode(ctx)
+
 	const maxExpectedReplicationSet = 5 // typical replication factor 3 plus one for inactive plus one for luck
 	var descs [maxExpectedReplicationSet]ring.InstanceDesc
 
@@ -426,6 +479,14 @@ func (d *Distributor) Push(ctx context.Context, req *logproto.PushRequest) (*log
 
 			streams[i].minSuccess = len(replicationSet.Instances) - replicationSet.MaxErrors
 			streams[i].maxFailures = replicationSet.MaxErrors
+			if ackMode == httpreq.PushAckModeAll {
+				// the unhealthy ingesters are already left out of the replication set.
+				if rf := d.ingestersRing.ReplicationFactor(); len(replicationSet.Instances) < rf {
+					return fmt.Errorf("all %d live replicas required by the %s push ack mode, could only find %d", rf, httpreq.PushAckModeAll, len(replicationSet.Instances))
+				}
+				streams[i].minSuccess = len(replicationSet.Instances)
+				streams[i].maxFailures = 0
+			}
 			for _, ingester := range replicationSet.Instances {
 				streamsByIngester[ingester.Addr] = append(streamsByIngester[ingester.Addr], &streams[i])
 				ingesterDescs[ingester.Addr] = ingester
@@ -453,6 +514,12 @@ func (d *Distributor) Push(ctx context.Context, req *logproto.PushRequest) (*log
 			d.sendStreams(localCtx, ingester, samples, &tracker)
 		}(ingesterDescs[ingester], streams)
 	}
+
+	if ackMode == httpreq.PushAckModeAsync && d.startAsyncPush() {
+		go d.awaitAsyncPush(tenantID, &tracker)
+		return &logproto.PushResponse{}, validationErr
+	}
+
 	select {
 	case err := <-tracker.err:
 		return nil, err
@@ -463,6 +530,155 @@ func (d *Distributor) Push(ctx context.Context, req *logproto.PushRequest) (*log
 	}
 }
 
+// startAsyncPush reserves one of the push requests which can be acknowledged before being sent to the ingesters.
+// It returns false if the maximum number of them are already in flight.
+func (d *Distributor) startAsyncPush() bool {
+	if d.asyncPushes.Inc() > int32(d.cfg.MaxAsyncPushes) {
+		d.asyncPushes.Dec()
+		return false
+	}
+	return true
+}
+
+// awaitAsyncPush waits for a push request acknowledged before being sent to the ingesters to be sent, and records its failure.
+func (d *Distributor) awaitAsyncPush(tenantID string, tracker *pushTracker) {
+	defer d.asyncPushes.Dec()
+
+	select {
+	case err := <-tracker.err:
+		d.asyncPushFailures.WithLabelValues(tenantID).Inc()
+		d.writeFailuresManager.Log(tenantID, fmt.Errorf("async push failed: %w", err))
+	case <-tracker.done:
+	}
+}
+
+// applyLabelPolicy enforces the label policy of the tenant on the labels of the stream.
+// Depending on the action of the policy, an error is returned for the violating streams
+// or the offending labels are removed from the stream.
+func (d *Distributor) applyLabelPolicy(vContext validationContext, policy *labelpolicy.Config, stream *logproto.Stream) error {
+	lbs, err := syntax.ParseLabels(stream.Labels)
+	if err != nil {
+		return fmt.Errorf(validation.InvalidLabelsErrorMsg, stream.Labels, err)
+	}
+
+	var offending labels.Labels
+	for _, l := range lbs {
+		reason, err := d.checkLabelPolicy(vContext.userID, policy, stream.Labels, l)
+		if err == nil {
+			continue
+		}
+		d.writeFailuresManager.Log(vContext.userID, err)
+
+		if policy.Action == labelpolicy.ActionReject {
+			validation.DiscardedSamples.WithLabelValues(reason, vContext.userID).Add(float64(len(stream.Entries)))
+			validation.DiscardedBytes.WithLabelValues(reason, vContext.userID).Add(float64(entriesSize(stream.Entries)))
+			return err
+		}
+		validation.MutatedSamples.WithLabelValues(reason, vContext.userID).Add(float64(len(stream.Entries)))
+		validation.MutatedBytes.WithLabelValues(reason, vContext.userID).Add(float64(entriesSize(stream.Entries)))
+		offending = append(offending, l)
+	}
+	if len(offending) == 0 {
+		return nil
+	}
+
+	remaining := make(labels.Labels, 0, len(lbs)-len(offending))
+	for _, l := range lbs {
+		if offending.Has(l.Name) {
+			continue
+		}
+		remaining = append(remaining, l)
+	}
+	if len(remaining) == 0 {
+		validation.DiscardedSamples.WithLabelValues(validation.MissingLabels, vContext.userID).Add(float64(len(stream.Entries)))
+		return fmt.Errorf(validation.MissingLabelsErrorMsg)
+	}
+
+	if policy.Action == labelpolicy.ActionStructuredMetadata {
+		for i := range stream.Entries {
+			metadata := make(push.LabelsAdapter, 0, len(stream.Entries[i].StructuredMetadata)+len(offending))
+			metadata = append(metadata, stream.Entries[i].StructuredMetadata...)
+			for _, l := range offending {
+				metadata = append(metadata, push.LabelAdapter{Name: l.Name, Value: l.Value})
+			}
+			stream.Entries[i].StructuredMetadata = metadata
+		}
+	}
+
+	stream.Labels, stream.Hash = remaining.String(), remaining.Hash()
+	return nil
+}
+
+// checkLabelPolicy returns the reason and the error if the label violates the policy.
+func (d *Distributor) checkLabelPolicy(tenantID string, policy *labelpolicy.Config, stream string, l labels.Label) (string, error) {
+	if len(policy.AllowedLabels) > 0 && !slices.Contains(policy.AllowedLabels, l.Name) {
+		return validation.DisallowedLabelName, fmt.Errorf(validation.DisallowedLabelNameErrorMsg, stream, l.Name)
+	}
+
+	for _, limit := range policy.MaxLabelValues {
+		if limit.Label != l.Name {
+			continue
+		}
+		// Only new values are rejected once the limit is reached, known values are still accepted.
+		if !d.labelValues.Observe(tenantID, l.Name, l.Value, limit.MaxValues, time.Duration(policy.Window)) {
+			return validation.LabelValuesLimit, fmt.Errorf(validation.LabelValuesLimitErrorMsg, stream, l.Name, limit.MaxValues)
+		}
+	}
+	return "", nil
+}
+
+func entriesSize(entries []logproto.Entry) int {
+	size := 0
+	for _, e := range entries {
+		size += len(e.Line)
+	}
+	return size
+}
+
+// appendStream appends the stream to the streams sent to the ingesters, sharding it if enabled.
+func (d *Distributor) appendStream(keys []uint32, streams []streamTracker, stream logproto.Stream, pushSize int, tenantID string) ([]uint32, []streamTracker) {
+	shardStreamsCfg := d.validator.Limits.ShardStreams(tenantID)
+	if shardStreamsCfg.Enabled {
+		derivedKeys, derivedStreams := d.shardStream(stream, pushSize, tenantID)
+		return append(keys, derivedKeys...), append(streams, derivedStreams...)
+	}
+	return append(keys, util.TokenFor(tenantID, stream.Labels)), append(streams, streamTracker{stream: stream})
+}
+
+// applyIngestionPipelines applies the ingestion pipelines of the tenant to the validated
+// entries of the stream. The streams and entries modified by the pipelines are validated again.
+func (d *Distributor) applyIngestionPipelines(vContext validationContext, pipelines ingestionPipelines, stream logproto.Stream, validationErrors *util.GroupedErrors) []logproto.Stream {
+	lbs, err := syntax.ParseLabels(stream.Labels)
+	if err != nil {
+		// The labels were already validated.
+		validationErrors.Add(err)
+		return nil
+	}
+
+	processed := pipelines.process(stream, lbs, func(labels string, entry logproto.Entry) bool {
+		if err := d.validator.ValidateEntry(vContext, labels, entry); err != nil {
+			d.writeFailuresManager.Log(vContext.userID, err)
+			validationErrors.Add(err)
+			return false
+		}
+		return true
+	})
+
+	n := 0
+	for _, s := range processed {
+		if s.Labels != stream.Labels {
+			if s.Labels, s.Hash, err = d.parseStreamLabels(vContext, s.Labels, &s); err != nil {
+				d.writeFailuresManager.Log(vContext.userID, err)
+				validationErrors.Add(err)
+				continue
+			}
+		}
+		processed[n] = s
+		n++
+	}
+	return processed[:n]
+}
+
 // shardStream shards (divides) the given stream into N smaller streams, where
 // N is the sharding size for the given stream. shardSteam returns the smaller
 // streams and their associated keys for hashing to ingesters.
@@ -760,6 +976,29 @@ func newRingAndLifecycler(cfg RingConfig, instanceCount *atomic.Uint32, logger l
 	return distributorsRing, distributorsLifecycler, nil
 }
 
+// outOfOrderStats gets the out-of-order stats of the tenant of the context from all the healthy ingesters.
+// Unlike queries, the stats of every ingester are needed, the ingesters failing to respond are logged and skipped.
+func (d *Distributor) outOfOrderStats(ctx context.Context) ([]*logproto.OutOfOrderStatsResponse, error) {
+	ingesters, err := d.ingestersRing.GetAllHealthy(ring.Read)
+	if err != nil {
+		return nil, err
+	}
+
+	resps := make([]*logproto.OutOfOrderStatsResponse, len(ingesters.Instances))
+	err = concurrency.ForEachJob(ctx, len(ingesters.Instances), len(ingesters.Instances), func(ctx context.Context, idx int) error {
+		addr := ingesters.Instances[idx].Addr
+		client, err := d.pool.GetClientFor(addr)
+		if err == nil {
+			resps[idx], err = client.(logproto.StreamDataClient).GetOutOfOrderStats(ctx, &logproto.OutOfOrderStatsRequest{})
+		}
+		if err != nil {
+			level.Error(util_log.Logger).Log("msg", "unable to get out-of-order stats from ingester", "ingester", addr, "err", err)
+		}
+		return nil
+	})
+	return resps, err
+}
+
 // HealthyInstancesCount implements the ReadLifecycler interface.
 //
 // We use a ring lifecycler delegate to count the number of members of the
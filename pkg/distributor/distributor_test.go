@@ -22,19 +22,24 @@ import (
 	"github.com/grafana/dskit/services"
 	"github.com/grafana/dskit/user"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/exp/maps"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/health/grpc_health_v1"
 
+	"github.com/grafana/loki/pkg/distributor/labelpolicy"
 	"github.com/grafana/loki/pkg/ingester"
 	"github.com/grafana/loki/pkg/ingester/client"
 	"github.com/grafana/loki/pkg/logproto"
 	"github.com/grafana/loki/pkg/logql/syntax"
+	"github.com/grafana/loki/pkg/push"
 	"github.com/grafana/loki/pkg/runtime"
 	fe "github.com/grafana/loki/pkg/util/flagext"
 	loki_flagext "github.com/grafana/loki/pkg/util/flagext"
+	"github.com/grafana/loki/pkg/util/httpreq"
 	util_log "github.com/grafana/loki/pkg/util/log"
 	loki_net "github.com/grafana/loki/pkg/util/net"
 	"github.com/grafana/loki/pkg/util/test"
@@ -490,6 +495,89 @@ func TestDistributorPushErrors(t *testing.T) {
 	})
 }
 
+func TestDistributorPushAckModes(t *testing.T) {
+	limits := &validation.Limits{}
+	flagext.DefaultValues(limits)
+
+	t.Run("all mode fails with a single push failure", func(t *testing.T) {
+		distributors, ingesters := prepare(t, 1, 3, limits, nil)
+		ingesters[0].failAfter = 5 * time.Millisecond
+
+		_, err := distributors[0].Push(httpreq.InjectPushAckMode(ctx, httpreq.PushAckModeQuorum), makeWriteRequest(10, 64))
+		require.NoError(t, err)
+		_, err = distributors[0].Push(httpreq.InjectPushAckMode(ctx, httpreq.PushAckModeAll), makeWriteRequest(10, 64))
+		require.Error(t, err)
+	})
+	t.Run("all mode fails with an unhealthy ingester", func(t *testing.T) {
+		distributors, _ := prepare(t, 1, 3, limits, nil)
+
+		err := distributors[0].cfg.DistributorRing.KVStore.Mock.CAS(context.Background(), ingester.RingKey, func(in interface{}) (interface{}, bool, error) {
+			desc := in.(*ring.Desc)
+			instance := desc.Ingesters["ingester-0"]
+			instance.Timestamp = time.Now().Add(-2 * time.Hour).Unix()
+			desc.Ingesters["ingester-0"] = instance
+			return desc, true, nil
+		})
+		require.NoError(t, err)
+		test.Poll(t, time.Second, 2, func() interface{} {
+			replicationSet, err := distributors[0].ingestersRing.GetAllHealthy(ring.Write)
+			require.NoError(t, err)
+			return len(replicationSet.Instances)
+		})
+
+		_, err = distributors[0].Push(httpreq.InjectPushAckMode(ctx, httpreq.PushAckModeQuorum), makeWriteRequest(10, 64))
+		require.NoError(t, err)
+		_, err = distributors[0].Push(httpreq.InjectPushAckMode(ctx, httpreq.PushAckModeAll), makeWriteRequest(10, 64))
+		require.EqualError(t, err, "all 3 live replicas required by the all push ack mode, could only find 2")
+	})
+	t.Run("all mode waits for all the ingesters", func(t *testing.T) {
+		distributors, ingesters := prepare(t, 1, 3, limits, nil)
+		ingesters[2].succeedAfter = 20 * time.Millisecond
+
+		_, err := distributors[0].Push(httpreq.InjectPushAckMode(ctx, httpreq.PushAckModeAll), makeWriteRequest(10, 64))
+		require.NoError(t, err)
+		for i := range ingesters {
+			require.Len(t, ingesters[i].pushed, 1)
+		}
+	})
+	t.Run("async mode doesn't wait for the ingesters", func(t *testing.T) {
+		distributors, ingesters := prepare(t, 1, 3, limits, nil)
+		for i := range ingesters {
+			ingesters[i].succeedAfter = 100 * time.Millisecond
+		}
+
+		start := time.Now()
+		_, err := distributors[0].Push(httpreq.InjectPushAckMode(ctx, httpreq.PushAckModeAsync), makeWriteRequest(10, 64))
+		require.NoError(t, err)
+		require.Less(t, time.Since(start), 100*time.Millisecond)
+
+		require.Eventually(t, func() bool {
+			return distributors[0].asyncPushes.Load() == 0 && len(ingesters[0].pushed) == 1 && len(ingesters[1].pushed) == 1 && len(ingesters[2].pushed) == 1
+		}, time.Second, 10*time.Millisecond)
+	})
+	t.Run("async mode records the failures", func(t *testing.T) {
+		distributors, ingesters := prepare(t, 1, 3, limits, nil)
+		ingesters[0].failAfter = 5 * time.Millisecond
+		ingesters[1].failAfter = 5 * time.Millisecond
+
+		_, err := distributors[0].Push(httpreq.InjectPushAckMode(ctx, httpreq.PushAckModeAsync), makeWriteRequest(10, 64))
+		require.NoError(t, err)
+		require.Eventually(t, func() bool {
+			return testutil.ToFloat64(distributors[0].asyncPushFailures.WithLabelValues("test")) == 1
+		}, time.Second, 10*time.Millisecond)
+	})
+	t.Run("async mode waits for a quorum once too many pushes are in flight", func(t *testing.T) {
+		distributors, ingesters := prepare(t, 1, 3, limits, nil)
+		distributors[0].cfg.MaxAsyncPushes = 0
+		ingesters[0].failAfter = 5 * time.Millisecond
+		ingesters[1].failAfter = 5 * time.Millisecond
+
+		_, err := distributors[0].Push(httpreq.InjectPushAckMode(ctx, httpreq.PushAckModeAsync), makeWriteRequest(10, 64))
+		require.Error(t, err)
+		require.Equal(t, int32(0), distributors[0].asyncPushes.Load())
+	})
+}
+
 func Test_SortLabelsOnPush(t *testing.T) {
 	limits := &validation.Limits{}
 	flagext.DefaultValues(limits)
@@ -1215,10 +1303,11 @@ type mockIngester struct {
 	logproto.PusherClient
 	logproto.StreamDataClient
 
-	failAfter    time.Duration
-	succeedAfter time.Duration
-	mu           sync.Mutex
-	pushed       []*logproto.PushRequest
+	failAfter       time.Duration
+	succeedAfter    time.Duration
+	mu              sync.Mutex
+	pushed          []*logproto.PushRequest
+	outOfOrderStats *logproto.OutOfOrderStatsResponse
 }
 
 func (i *mockIngester) Push(_ context.Context, in *logproto.PushRequest, _ ...grpc.CallOption) (*logproto.PushResponse, error) {
@@ -1241,6 +1330,13 @@ func (i *mockIngester) GetStreamRates(_ context.Context, _ *logproto.StreamRates
 	return &logproto.StreamRatesResponse{}, nil
 }
 
+func (i *mockIngester) GetOutOfOrderStats(_ context.Context, _ *logproto.OutOfOrderStatsRequest, _ ...grpc.CallOption) (*logproto.OutOfOrderStatsResponse, error) {
+	if i.outOfOrderStats == nil {
+		return &logproto.OutOfOrderStatsResponse{}, nil
+	}
+	return i.outOfOrderStats, nil
+}
+
 func (i *mockIngester) Close() error {
 	return nil
 }
@@ -1253,3 +1349,59 @@ type fakeRateStore struct {
 func (s *fakeRateStore) RateFor(_ string, _ uint64) (int64, float64) {
 	return s.rate, s.pushRate
 }
+
+func TestDistributor_PushLabelPolicy(t *testing.T) {
+	now := time.Now()
+	pushStreams := func(t *testing.T, policy *labelpolicy.Config, streams ...string) (map[string][]logproto.Entry, error) {
+		limits := &validation.Limits{}
+		flagext.DefaultValues(limits)
+		limits.EnforceMetricName = false
+		limits.AllowStructuredMetadata = true
+		limits.LabelPolicy = policy
+		require.NoError(t, limits.Validate())
+
+		ingester := &mockIngester{}
+		distributors, _ := prepare(t, 1, 3, limits, func(addr string) (ring_client.PoolClient, error) { return ingester, nil })
+
+		req := &logproto.PushRequest{}
+		for _, s := range streams {
+			req.Streams = append(req.Streams, logproto.Stream{Labels: s, Entries: []logproto.Entry{{Timestamp: now, Line: "line"}}})
+		}
+		_, err := distributors[0].Push(ctx, req)
+
+		pushed := map[string][]logproto.Entry{}
+		for _, req := range ingester.pushed {
+			for _, s := range req.Streams {
+				pushed[s.Labels] = s.Entries
+			}
+		}
+		return pushed, err
+	}
+
+	t.Run("disallowed labels are rejected", func(t *testing.T) {
+		pushed, err := pushStreams(t, &labelpolicy.Config{AllowedLabels: []string{"app"}, Action: labelpolicy.ActionReject}, `{app="a"}`, `{app="b", request_id="1"}`)
+		require.ErrorContains(t, err, "label name not allowed by the label policy: 'request_id'")
+		require.Equal(t, []string{`{app="a"}`}, maps.Keys(pushed))
+	})
+
+	t.Run("disallowed labels are dropped", func(t *testing.T) {
+		pushed, err := pushStreams(t, &labelpolicy.Config{AllowedLabels: []string{"app"}, Action: labelpolicy.ActionDrop}, `{app="a", request_id="1"}`)
+		require.NoError(t, err)
+		require.Equal(t, map[string][]logproto.Entry{`{app="a"}`: {{Timestamp: now, Line: "line"}}}, pushed)
+	})
+
+	t.Run("labels exceeding their distinct values are moved to structured metadata", func(t *testing.T) {
+		policy := &labelpolicy.Config{
+			MaxLabelValues: []labelpolicy.LabelLimit{{Label: "request_id", MaxValues: 2}},
+			Window:         model.Duration(time.Hour),
+			Action:         labelpolicy.ActionStructuredMetadata,
+		}
+		pushed, err := pushStreams(t, policy, `{app="a", request_id="1"}`, `{app="a", request_id="2"}`, `{app="a", request_id="3"}`, `{app="a", request_id="1"}`)
+		require.NoError(t, err)
+		require.Equal(t, map[string][]logproto.Entry{
+			`{app="a", request_id="1"}`: {{Timestamp: now, Line: "line"}},
+			`{app="a", request_id="2"}`: {{Timestamp: now, Line: "line"}},
+			`{app="a"}`:                 {{Timestamp: now, Line: "line", StructuredMetadata: push.LabelsAdapter{{Name: "request_id", Value: "3"}}}},
+		}, pushed)
+	})
+}
@@ -1,6 +1,7 @@
 package distributor
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"strings"
@@ -9,11 +10,16 @@ import (
 	"github.com/grafana/dskit/httpgrpc"
 
 	"github.com/grafana/loki/pkg/util"
+	"github.com/grafana/loki/pkg/util/httpreq"
 
 	"github.com/grafana/dskit/tenant"
 
+	"github.com/grafana/loki/pkg/ingester"
 	"github.com/grafana/loki/pkg/loghttp/push"
+	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/logql/syntax"
 	util_log "github.com/grafana/loki/pkg/util/log"
+	"github.com/grafana/loki/pkg/util/unmarshal"
 	"github.com/grafana/loki/pkg/validation"
 )
 
@@ -26,6 +32,15 @@ func (d *Distributor) PushHandler(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	ctx := r.Context()
+	if mode := r.Header.Get(httpreq.LokiPushAckModeHeader); mode != "" {
+		if !httpreq.ValidPushAckMode(mode) {
+			http.Error(w, fmt.Sprintf("invalid %s header %q, expected one of %s, %s or %s", httpreq.LokiPushAckModeHeader, mode, httpreq.PushAckModeAsync, httpreq.PushAckModeQuorum, httpreq.PushAckModeAll), http.StatusBadRequest)
+			return
+		}
+		ctx = httpreq.InjectPushAckMode(ctx, mode)
+	}
+
 	req, err := push.ParseRequest(logger, tenantID, r, d.tenantsRetention)
 	if err != nil {
 		if d.tenantConfigs.LogPushRequest(tenantID) {
@@ -52,7 +67,7 @@ func (d *Distributor) PushHandler(w http.ResponseWriter, r *http.Request) {
 		)
 	}
 
-	_, err = d.Push(r.Context(), req)
+	_, err = d.Push(ctx, req)
 	if err == nil {
 		if d.tenantConfigs.LogPushRequest(tenantID) {
 			level.Debug(logger).Log(
@@ -86,6 +101,88 @@ func (d *Distributor) PushHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// IngestionPipelinesDryRunResponse is the response of the ingestion pipelines dry-run endpoint.
+type IngestionPipelinesDryRunResponse struct {
+	Streams []logproto.Stream               `json:"streams"`
+	Stages  []IngestionPipelinesDryRunStage `json:"stages"`
+}
+
+// IngestionPipelinesDryRunStage holds the number of entries processed by a stage of an ingestion pipeline.
+type IngestionPipelinesDryRunStage struct {
+	Pipeline string `json:"pipeline"`
+	Stage    string `json:"stage"`
+	Entries  int    `json:"entries"`
+	Dropped  int    `json:"dropped"`
+	Errors   int    `json:"errors"`
+}
+
+// IngestionPipelinesDryRunHandler applies the ingestion pipelines of the tenant to the
+// streams of a JSON push request and returns the resulting streams along with the
+// number of entries processed by each stage. Nothing is ingested.
+func (d *Distributor) IngestionPipelinesDryRunHandler(w http.ResponseWriter, r *http.Request) {
+	tenantID, err := tenant.TenantID(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var req logproto.PushRequest
+	if err := unmarshal.DecodePushRequest(r.Body, &req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	pipelines, err := newIngestionPipelines(d.validator.Limits.IngestionPipelines(tenantID))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	resp := IngestionPipelinesDryRunResponse{Streams: []logproto.Stream{}}
+	for _, stream := range req.Streams {
+		lbs, err := syntax.ParseLabels(stream.Labels)
+		if err != nil {
+			http.Error(w, fmt.Sprintf(validation.InvalidLabelsErrorMsg, stream.Labels, err), http.StatusBadRequest)
+			return
+		}
+		resp.Streams = append(resp.Streams, pipelines.process(stream, lbs, func(string, logproto.Entry) bool { return true })...)
+	}
+	for _, p := range pipelines {
+		for _, s := range p.stages {
+			resp.Stages = append(resp.Stages, IngestionPipelinesDryRunStage{
+				Pipeline: p.name,
+				Stage:    s.name,
+				Entries:  s.entries,
+				Dropped:  s.dropped,
+				Errors:   s.errors,
+			})
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		level.Error(util_log.Logger).Log("msg", "error encoding ingestion pipelines dry-run response", "err", err)
+	}
+}
+
+// OutOfOrderStatsHandler returns the out-of-order stats of the tenant and of its streams sending
+// the most out-of-order entries, aggregated over the ingesters.
+func (d *Distributor) OutOfOrderStatsHandler(w http.ResponseWriter, r *http.Request) {
+	limit, err := ingester.OutOfOrderStatsLimit(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resps, err := d.outOfOrderStats(r.Context())
+	if err != nil {
+		level.Error(util_log.Logger).Log("msg", "error getting out-of-order stats from ingesters", "err", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	ingester.WriteOutOfOrderStats(w, ingester.MergeOutOfOrderStats(resps, limit))
+}
+
 // ServeHTTP implements the distributor ring status page.
 //
 // If the rate limiting strategy is local instead of global, no ring is used by
@@ -1,14 +1,23 @@
 package distributor
 
 import (
+	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/grafana/dskit/flagext"
+	ring_client "github.com/grafana/dskit/ring/client"
+	"github.com/grafana/dskit/user"
 	"github.com/stretchr/testify/require"
 
+	"github.com/grafana/loki/pkg/ingester"
+	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/util/httpreq"
 	"github.com/grafana/loki/pkg/validation"
 )
 
@@ -54,3 +63,103 @@ func TestDistributorRingHandler(t *testing.T) {
 		require.NotContains(t, string(body), "<th>Instance ID</th>")
 	})
 }
+
+func TestIngestionPipelinesDryRunHandler(t *testing.T) {
+	limits := &validation.Limits{}
+	flagext.DefaultValues(limits)
+	limits.IngestionPipelines = []validation.IngestionPipeline{{
+		Name:     "drop-debug",
+		Pipeline: `| logfmt | level != "debug"`,
+	}}
+	require.NoError(t, limits.Validate())
+
+	ingester := &mockIngester{}
+	distributors, _ := prepare(t, 1, 3, limits, func(addr string) (ring_client.PoolClient, error) { return ingester, nil })
+
+	body := `{"streams": [{"stream": {"app": "api"}, "values": [["1", "level=debug msg=a"], ["2", "level=info msg=b"]]}]}`
+	req := httptest.NewRequest(http.MethodPost, "/distributor/ingestion_pipelines/dry_run", strings.NewReader(body))
+	req = req.WithContext(user.InjectOrgID(req.Context(), "test"))
+	rec := httptest.NewRecorder()
+	distributors[0].IngestionPipelinesDryRunHandler(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var resp IngestionPipelinesDryRunResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	require.Len(t, resp.Streams, 1)
+	require.Equal(t, `{app="api"}`, resp.Streams[0].Labels)
+	require.Len(t, resp.Streams[0].Entries, 1)
+	require.True(t, time.Unix(0, 2).Equal(resp.Streams[0].Entries[0].Timestamp))
+	require.Equal(t, "level=info msg=b", resp.Streams[0].Entries[0].Line)
+	require.Equal(t, []IngestionPipelinesDryRunStage{
+		{Pipeline: "drop-debug", Stage: "0_logfmt", Entries: 2},
+		{Pipeline: "drop-debug", Stage: "1_label_filter", Entries: 2, Dropped: 1},
+	}, resp.Stages)
+
+	// Nothing is ingested.
+	require.Empty(t, ingester.pushed)
+}
+
+func TestOutOfOrderStatsHandler(t *testing.T) {
+	limits := &validation.Limits{}
+	flagext.DefaultValues(limits)
+
+	stream := &logproto.StreamOutOfOrderStats{Labels: `{app="api"}`, OutOfOrderEntries: 2, OutOfOrderBytes: 20, MaxDistance: int64(time.Second), TotalDistance: int64(time.Second)}
+	ingesters := map[string]*mockIngester{
+		"ingester-0": {outOfOrderStats: &logproto.OutOfOrderStatsResponse{Streams: []*logproto.StreamOutOfOrderStats{stream}}},
+		"ingester-1": {outOfOrderStats: &logproto.OutOfOrderStatsResponse{Streams: []*logproto.StreamOutOfOrderStats{stream, {Labels: `{app="web"}`, RejectedEntries: 1, RejectedBytes: 5}}}},
+		"ingester-2": {},
+	}
+	distributors, _ := prepare(t, 1, 3, limits, func(addr string) (ring_client.PoolClient, error) { return ingesters[addr], nil })
+
+	req := httptest.NewRequest(http.MethodGet, "/distributor/out_of_order_stats?limit=1", nil)
+	req = req.WithContext(user.InjectOrgID(req.Context(), "test"))
+	rec := httptest.NewRecorder()
+	distributors[0].OutOfOrderStatsHandler(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var resp ingester.OutOfOrderStats
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	require.Equal(t, ingester.OutOfOrderStats{
+		// the stats of the replicas of a stream aren't summed
+		Tenant: ingester.OutOfOrderStreamStats{Streams: 2, OutOfOrderEntries: 2, OutOfOrderBytes: 20, MaxDistance: "1s", AvgDistance: "500ms", RejectedEntries: 1, RejectedBytes: 5},
+		Streams: []ingester.OutOfOrderStreamStats{
+			{Labels: `{app="web"}`, MaxDistance: "0s", AvgDistance: "0s", RejectedEntries: 1, RejectedBytes: 5},
+		},
+	}, resp)
+
+	req = httptest.NewRequest(http.MethodGet, "/distributor/out_of_order_stats?limit=foo", nil)
+	req = req.WithContext(user.InjectOrgID(req.Context(), "test"))
+	rec = httptest.NewRecorder()
+	distributors[0].OutOfOrderStatsHandler(rec, req)
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestPushHandlerAckMode(t *testing.T) {
+	limits := &validation.Limits{}
+	flagext.DefaultValues(limits)
+	distributors, _ := prepare(t, 1, 3, limits, nil)
+
+	for _, tc := range []struct {
+		mode     string
+		expected int
+	}{
+		{"", http.StatusNoContent},
+		{httpreq.PushAckModeAsync, http.StatusNoContent},
+		{httpreq.PushAckModeQuorum, http.StatusNoContent},
+		{httpreq.PushAckModeAll, http.StatusNoContent},
+		{"sync", http.StatusBadRequest},
+	} {
+		t.Run(tc.mode, func(t *testing.T) {
+			body := fmt.Sprintf(`{"streams": [{"stream": {"app": "api"}, "values": [["%d", "line"]]}]}`, time.Now().UnixNano())
+			req := httptest.NewRequest(http.MethodPost, "/loki/api/v1/push", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			if tc.mode != "" {
+				req.Header.Set(httpreq.LokiPushAckModeHeader, tc.mode)
+			}
+			req = req.WithContext(user.InjectOrgID(req.Context(), "test"))
+			rec := httptest.NewRecorder()
+			distributors[0].PushHandler(rec, req)
+			require.Equal(t, tc.expected, rec.Code, rec.Body.String())
+		})
+	}
+}
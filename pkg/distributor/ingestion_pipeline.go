@@ -0,0 +1,378 @@
+package distributor
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/prometheus/model/labels"
+
+	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/logql/log"
+	"github.com/grafana/loki/pkg/logql/syntax"
+	"github.com/grafana/loki/pkg/logqlmodel"
+	"github.com/grafana/loki/pkg/push"
+	"github.com/grafana/loki/pkg/validation"
+)
+
+type ingestionPipelineMetrics struct {
+	entries *prometheus.CounterVec
+	dropped *prometheus.CounterVec
+	errors  *prometheus.CounterVec
+}
+
+func newIngestionPipelineMetrics(registerer prometheus.Registerer) *ingestionPipelineMetrics {
+	return &ingestionPipelineMetrics{
+		entries: promauto.With(registerer).NewCounterVec(prometheus.CounterOpts{
+			Namespace: "loki",
+			Name:      "distributor_ingestion_pipeline_entries_total",
+			Help:      "The total number of entries processed by a stage of an ingestion pipeline.",
+		}, []string{"tenant", "pipeline", "stage"}),
+		dropped: promauto.With(registerer).NewCounterVec(prometheus.CounterOpts{
+			Namespace: "loki",
+			Name:      "distributor_ingestion_pipeline_entries_dropped_total",
+			Help:      "The total number of entries dropped by a stage of an ingestion pipeline.",
+		}, []string{"tenant", "pipeline", "stage"}),
+		errors: promauto.With(registerer).NewCounterVec(prometheus.CounterOpts{
+			Namespace: "loki",
+			Name:      "distributor_ingestion_pipeline_errors_total",
+			Help:      "The total number of entries for which a stage of an ingestion pipeline failed.",
+		}, []string{"tenant", "pipeline", "stage"}),
+	}
+}
+
+// ingestionStage is a stage of an ingestion pipeline along with the number of
+// entries it processed.
+type ingestionStage struct {
+	name  string
+	stage log.Stage
+
+	entries, dropped, errors int
+}
+
+// ingestionPipeline is an ingestion pipeline of a tenant used by a single push
+// request at a time. Stages and label builders aren't safe for concurrent use.
+type ingestionPipeline struct {
+	name     string
+	matchers []*labels.Matcher
+	stages   []*ingestionStage
+
+	// streamLabels are the labels set by the stages which become stream labels.
+	streamLabels map[string]struct{}
+	// structuredMetadata are the labels which are stored as structured metadata.
+	structuredMetadata map[string]struct{}
+
+	builder *log.BaseLabelsBuilder
+}
+
+// ingestionPipelines are the ingestion pipelines of a tenant in the order they are applied.
+type ingestionPipelines []*ingestionPipeline
+
+// newIngestionPipelines builds the ingestion pipelines from their validated configuration.
+func newIngestionPipelines(cfgs []validation.IngestionPipeline) (ingestionPipelines, error) {
+	pipelines := make(ingestionPipelines, 0, len(cfgs))
+	for _, cfg := range cfgs {
+		p := &ingestionPipeline{
+			name:               cfg.Name,
+			matchers:           cfg.Matchers,
+			stages:             make([]*ingestionStage, 0, len(cfg.Stages)),
+			streamLabels:       map[string]struct{}{},
+			structuredMetadata: make(map[string]struct{}, len(cfg.StructuredMetadata)),
+			builder:            log.NewBaseLabelsBuilder(),
+		}
+		for _, name := range cfg.StructuredMetadata {
+			p.structuredMetadata[name] = struct{}{}
+		}
+
+		for i, expr := range cfg.Stages {
+			stage, err := expr.Stage()
+			if err != nil {
+				return nil, fmt.Errorf("ingestion pipeline %s: %w", cfg.Name, logqlmodel.NewStageError(expr.String(), err))
+			}
+			if fmtExpr, ok := expr.(*syntax.LabelFmtExpr); ok {
+				for _, f := range fmtExpr.Formats {
+					p.streamLabels[f.Name] = struct{}{}
+				}
+			}
+			p.stages = append(p.stages, &ingestionStage{name: stageName(i, expr), stage: stage})
+		}
+		pipelines = append(pipelines, p)
+	}
+	return pipelines, nil
+}
+
+// ingestionPipelinesCache reuses the ingestion pipelines of the tenants across
+// push requests, as building them parses all their stages.
+type ingestionPipelinesCache struct {
+	mtx     sync.Mutex
+	tenants map[string]*cachedIngestionPipelines
+}
+
+// cachedIngestionPipelines are the idle ingestion pipelines built from the configuration of a tenant.
+// At most as many pipelines as concurrent push requests of the tenant are built.
+type cachedIngestionPipelines struct {
+	cfgs []validation.IngestionPipeline
+	idle []ingestionPipelines
+}
+
+func newIngestionPipelinesCache() *ingestionPipelinesCache {
+	return &ingestionPipelinesCache{tenants: map[string]*cachedIngestionPipelines{}}
+}
+
+// get returns ingestion pipelines for the configuration of the tenant along with
+// the function returning them to the cache once the push request is done.
+// Pipelines built from a previous configuration of the tenant are never reused.
+func (c *ingestionPipelinesCache) get(tenantID string, cfgs []validation.IngestionPipeline) (ingestionPipelines, func(), error) {
+	if len(cfgs) == 0 {
+		return nil, func() {}, nil
+	}
+
+	c.mtx.Lock()
+	cached, ok := c.tenants[tenantID]
+	if !ok || !sameIngestionPipelinesConfig(cached.cfgs, cfgs) {
+		cached = &cachedIngestionPipelines{cfgs: cfgs}
+		c.tenants[tenantID] = cached
+	}
+	var pipelines ingestionPipelines
+	if n := len(cached.idle); n > 0 {
+		pipelines = cached.idle[n-1]
+		cached.idle = cached.idle[:n-1]
+	}
+	c.mtx.Unlock()
+
+	if pipelines == nil {
+		var err error
+		if pipelines, err = newIngestionPipelines(cfgs); err != nil {
+			return nil, nil, err
+		}
+	}
+	return pipelines, func() {
+		c.mtx.Lock()
+		defer c.mtx.Unlock()
+		cached.idle = append(cached.idle, pipelines)
+	}, nil
+}
+
+// sameIngestionPipelinesConfig returns whether both configurations are the same
+// instance, as the limits only return a new one when the runtime configuration changes.
+func sameIngestionPipelinesConfig(a, b []validation.IngestionPipeline) bool {
+	return len(a) == len(b) && &a[0] == &b[0]
+}
+
+// stageName names the stage by its position in the pipeline and its kind.
+func stageName(i int, expr syntax.StageExpr) string {
+	var kind string
+	switch e := expr.(type) {
+	case *syntax.LabelParserExpr:
+		kind = e.Op
+	case *syntax.JSONExpressionParser:
+		kind = syntax.OpParserTypeJSON
+	case *syntax.LogfmtParserExpr, *syntax.LogfmtExpressionParser:
+		kind = syntax.OpParserTypeLogfmt
+	case *syntax.XMLExpressionParser:
+		kind = syntax.OpParserTypeXML
+	case *syntax.CSVParserExpr:
+		kind = syntax.OpParserTypeCSV
+	case *syntax.KVParserExpr:
+		kind = syntax.OpParserTypeKV
+	case *syntax.DecodeExpr:
+		kind = syntax.OpDecode
+	case *syntax.JSONUnescapeExpr:
+		kind = syntax.OpJSONUnescape
+	case *syntax.LabelFmtExpr:
+		kind = syntax.OpFmtLabel
+	case *syntax.LineFmtExpr:
+		kind = syntax.OpFmtLine
+	case *syntax.DropLabelsExpr:
+		kind = syntax.OpDrop
+	case *syntax.KeepLabelsExpr:
+		kind = syntax.OpKeep
+	case *syntax.DecolorizeExpr:
+		kind = syntax.OpDecolorize
+	case *syntax.LabelFilterExpr:
+		kind = "label_filter"
+	case *syntax.LineFilterExpr:
+		kind = "line_filter"
+	case *syntax.UnnestExpr:
+		kind = syntax.OpUnnest
+	default:
+		kind = "unknown"
+	}
+	return fmt.Sprintf("%d_%s", i, kind)
+}
+
+// process applies the matching pipelines to the entries of the stream and
+// groups the resulting entries by their stream labels.
+// Entries modified by a pipeline are passed to validate and dropped if it returns false.
+func (p ingestionPipelines) process(stream logproto.Stream, lbs labels.Labels, validate func(labels string, entry logproto.Entry) bool) []logproto.Stream {
+	var (
+		result = make([]logproto.Stream, 0, 1)
+		index  = make(map[uint64]int, 1)
+	)
+
+	for _, entry := range stream.Entries {
+		var (
+			entryLabels = lbs
+			modified    bool
+			ok          = true
+		)
+		for _, pipeline := range p {
+			if !pipeline.matches(entryLabels) {
+				continue
+			}
+			var changed bool
+			entryLabels, entry, changed, ok = pipeline.processEntry(entryLabels, entry)
+			if !ok {
+				break
+			}
+			modified = modified || changed
+		}
+		if !ok {
+			continue
+		}
+
+		hash := entryLabels.Hash()
+		i, found := index[hash]
+		if !found {
+			i = len(result)
+			index[hash] = i
+			result = append(result, logproto.Stream{Labels: entryLabels.String(), Hash: hash})
+		}
+		if modified && !validate(result[i].Labels, entry) {
+			continue
+		}
+		result[i].Entries = append(result[i].Entries, entry)
+	}
+
+	n := 0
+	for _, s := range result {
+		if len(s.Entries) > 0 {
+			result[n] = s
+			n++
+		}
+	}
+	return result[:n]
+}
+
+// updateMetrics records the number of entries processed by each stage and resets them.
+func (p ingestionPipelines) updateMetrics(metrics *ingestionPipelineMetrics, tenantID string) {
+	for _, pipeline := range p {
+		for _, s := range pipeline.stages {
+			if s.entries == 0 {
+				continue
+			}
+			metrics.entries.WithLabelValues(tenantID, pipeline.name, s.name).Add(float64(s.entries))
+			if s.dropped > 0 {
+				metrics.dropped.WithLabelValues(tenantID, pipeline.name, s.name).Add(float64(s.dropped))
+			}
+			if s.errors > 0 {
+				metrics.errors.WithLabelValues(tenantID, pipeline.name, s.name).Add(float64(s.errors))
+			}
+			s.entries, s.dropped, s.errors = 0, 0, 0
+		}
+	}
+}
+
+func (p *ingestionPipeline) matches(lbs labels.Labels) bool {
+	for _, m := range p.matchers {
+		if !m.Matches(lbs.Get(m.Name)) {
+			return false
+		}
+	}
+	return true
+}
+
+// processEntry applies the stages to the entry and returns the stream labels and
+// the entry resulting from it, whether the entry was modified and whether it is kept.
+func (p *ingestionPipeline) processEntry(lbs labels.Labels, entry logproto.Entry) (labels.Labels, logproto.Entry, bool, bool) {
+	lb := p.builder.ForLabels(lbs, lbs.Hash())
+	lb.Reset()
+	for _, m := range entry.StructuredMetadata {
+		lb.Add(labels.Label{Name: m.Name, Value: m.Value})
+	}
+
+	var (
+		line = []byte(entry.Line)
+		ts   = entry.Timestamp.UnixNano()
+		ok   bool
+	)
+	for _, s := range p.stages {
+		s.entries++
+		hadErr := lb.HasErr()
+		line, ok = s.stage.Process(ts, line, lb)
+		if !ok {
+			s.dropped++
+			return nil, entry, false, false
+		}
+		if !hadErr && lb.HasErr() {
+			s.errors++
+		}
+	}
+
+	var (
+		streamLabels = make(labels.Labels, 0, len(lbs))
+		metadata     = make(push.LabelsAdapter, 0, len(entry.StructuredMetadata))
+	)
+	for _, l := range lb.LabelsResult().Labels() {
+		switch {
+		case l.Name == logqlmodel.ErrorLabel || l.Name == logqlmodel.ErrorDetailsLabel:
+			// Errors of the stages aren't stored.
+		case l.Value == "":
+			// Labels with an empty value are the same as missing labels.
+		case p.isStructuredMetadata(l.Name) || (hasStructuredMetadata(entry.StructuredMetadata, l.Name) && !lbs.Has(l.Name)):
+			metadata = append(metadata, logproto.LabelAdapter{Name: l.Name, Value: l.Value})
+		case lbs.Has(l.Name) || p.isStreamLabel(l.Name):
+			streamLabels = append(streamLabels, l)
+		}
+		// All other labels were extracted by the stages and are discarded.
+	}
+	sort.Sort(streamLabels)
+
+	modified := string(line) != entry.Line || !sameStructuredMetadata(entry.StructuredMetadata, metadata)
+	if modified {
+		entry.Line = string(line)
+		if len(metadata) == 0 {
+			metadata = nil
+		}
+		entry.StructuredMetadata = metadata
+	}
+	return streamLabels, entry, modified, true
+}
+
+func (p *ingestionPipeline) isStreamLabel(name string) bool {
+	_, ok := p.streamLabels[name]
+	return ok
+}
+
+func (p *ingestionPipeline) isStructuredMetadata(name string) bool {
+	_, ok := p.structuredMetadata[name]
+	return ok
+}
+
+func hasStructuredMetadata(metadata push.LabelsAdapter, name string) bool {
+	for _, m := range metadata {
+		if m.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// sameStructuredMetadata returns whether both structured metadata hold the same labels, in any order.
+func sameStructuredMetadata(a, b push.LabelsAdapter) bool {
+	if len(a) != len(b) {
+		return false
+	}
+Outer:
+	for _, x := range a {
+		for _, y := range b {
+			if x == y {
+				continue Outer
+			}
+		}
+		return false
+	}
+	return true
+}
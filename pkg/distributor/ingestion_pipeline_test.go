@@ -0,0 +1,207 @@
+package distributor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/dskit/flagext"
+	ring_client "github.com/grafana/dskit/ring/client"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/logql/syntax"
+	"github.com/grafana/loki/pkg/push"
+	"github.com/grafana/loki/pkg/validation"
+)
+
+func testIngestionPipelines(t *testing.T, cfgs ...validation.IngestionPipeline) ingestionPipelines {
+	t.Helper()
+	limits := validation.Limits{}
+	flagext.DefaultValues(&limits)
+	limits.IngestionPipelines = cfgs
+	require.NoError(t, limits.Validate())
+	pipelines, err := newIngestionPipelines(limits.IngestionPipelines)
+	require.NoError(t, err)
+	return pipelines
+}
+
+func processStream(t *testing.T, pipelines ingestionPipelines, stream logproto.Stream) []logproto.Stream {
+	t.Helper()
+	lbs, err := syntax.ParseLabels(stream.Labels)
+	require.NoError(t, err)
+	return pipelines.process(stream, lbs, func(string, logproto.Entry) bool { return true })
+}
+
+func TestIngestionPipelines(t *testing.T) {
+	pipelines := testIngestionPipelines(t, validation.IngestionPipeline{
+		Name:               "json",
+		Selector:           `{app="api"}`,
+		Pipeline:           `| json | level != "debug" | label_format service=component | drop component | line_format "{{.msg}}"`,
+		StructuredMetadata: []string{"trace_id"},
+	})
+
+	ts := time.Unix(1, 0)
+	streams := processStream(t, pipelines, logproto.Stream{
+		Labels: `{app="api", env="prod"}`,
+		Entries: []logproto.Entry{
+			{Timestamp: ts, Line: `{"level":"debug","component":"db","trace_id":"1","msg":"debug"}`},
+			{Timestamp: ts, Line: `{"level":"info","component":"db","trace_id":"2","msg":"query"}`},
+			{Timestamp: ts, Line: `{"level":"info","component":"http","trace_id":"3","msg":"request"}`, StructuredMetadata: push.LabelsAdapter{{Name: "user", Value: "a"}}},
+		},
+	})
+	require.Equal(t, []logproto.Stream{
+		{
+			Labels:  `{app="api", env="prod", service="db"}`,
+			Hash:    streams[0].Hash,
+			Entries: []logproto.Entry{{Timestamp: ts, Line: "query", StructuredMetadata: push.LabelsAdapter{{Name: "trace_id", Value: "2"}}}},
+		},
+		{
+			Labels:  `{app="api", env="prod", service="http"}`,
+			Hash:    streams[1].Hash,
+			Entries: []logproto.Entry{{Timestamp: ts, Line: "request", StructuredMetadata: push.LabelsAdapter{{Name: "trace_id", Value: "3"}, {Name: "user", Value: "a"}}}},
+		},
+	}, streams)
+
+	stages := pipelines[0].stages
+	require.Equal(t, []string{"0_json", "1_label_filter", "2_label_format", "3_drop", "4_line_format"}, []string{stages[0].name, stages[1].name, stages[2].name, stages[3].name, stages[4].name})
+	require.Equal(t, 3, stages[1].entries)
+	require.Equal(t, 1, stages[1].dropped)
+	require.Equal(t, 2, stages[4].entries)
+
+	// Streams not matching the selector are left untouched.
+	other := logproto.Stream{Labels: `{app="web"}`, Entries: []logproto.Entry{{Timestamp: ts, Line: `{"level":"debug"}`}}}
+	streams = processStream(t, pipelines, other)
+	require.Len(t, streams, 1)
+	require.Equal(t, other.Labels, streams[0].Labels)
+	require.Equal(t, other.Entries, streams[0].Entries)
+}
+
+func TestIngestionPipelines_Chained(t *testing.T) {
+	pipelines := testIngestionPipelines(t,
+		validation.IngestionPipeline{Name: "drop-health", Pipeline: `!= "/health"`},
+		validation.IngestionPipeline{Name: "json", Pipeline: `| json | __error__ = "" | label_format level=lvl`},
+	)
+
+	ts := time.Unix(1, 0)
+	streams := processStream(t, pipelines, logproto.Stream{
+		Labels: `{app="web"}`,
+		Entries: []logproto.Entry{
+			{Timestamp: ts, Line: `{"lvl":"info","path":"/health"}`},
+			{Timestamp: ts, Line: `{"lvl":"warn","path":"/login"}`},
+			{Timestamp: ts, Line: `not json`},
+		},
+	})
+	require.Len(t, streams, 1)
+	require.Equal(t, `{app="web", level="warn"}`, streams[0].Labels)
+	require.Equal(t, []logproto.Entry{{Timestamp: ts, Line: `{"lvl":"warn","path":"/login"}`}}, streams[0].Entries)
+
+	require.Equal(t, 1, pipelines[0].stages[0].dropped)
+	require.Equal(t, 1, pipelines[1].stages[0].errors)
+	require.Equal(t, 1, pipelines[1].stages[1].dropped)
+}
+
+func TestIngestionPipelinesCache(t *testing.T) {
+	cfgs := []validation.IngestionPipeline{{Name: "a", Selector: `{app="api"}`, Pipeline: `| json`}}
+	limits := validation.Limits{}
+	flagext.DefaultValues(&limits)
+	limits.IngestionPipelines = cfgs
+	require.NoError(t, limits.Validate())
+
+	c := newIngestionPipelinesCache()
+	first, release, err := c.get("fake", limits.IngestionPipelines)
+	require.NoError(t, err)
+
+	// Pipelines in use are never shared.
+	second, releaseSecond, err := c.get("fake", limits.IngestionPipelines)
+	require.NoError(t, err)
+	require.NotSame(t, first[0], second[0])
+	releaseSecond()
+	release()
+
+	reused, release, err := c.get("fake", limits.IngestionPipelines)
+	require.NoError(t, err)
+	require.Same(t, first[0], reused[0])
+	release()
+
+	// A new configuration of the tenant builds new pipelines.
+	updated := append([]validation.IngestionPipeline(nil), limits.IngestionPipelines...)
+	rebuilt, release, err := c.get("fake", updated)
+	require.NoError(t, err)
+	require.NotSame(t, first[0], rebuilt[0])
+	require.NotSame(t, second[0], rebuilt[0])
+	release()
+}
+
+func TestIngestionPipelines_Validate(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		cfgs []validation.IngestionPipeline
+	}{
+		{
+			name: "missing name",
+			cfgs: []validation.IngestionPipeline{{Pipeline: `| json`}},
+		},
+		{
+			name: "duplicate name",
+			cfgs: []validation.IngestionPipeline{{Name: "a", Pipeline: `| json`}, {Name: "a", Pipeline: `| logfmt`}},
+		},
+		{
+			name: "invalid selector",
+			cfgs: []validation.IngestionPipeline{{Name: "a", Selector: `{app=}`, Pipeline: `| json`}},
+		},
+		{
+			name: "no stages",
+			cfgs: []validation.IngestionPipeline{{Name: "a"}},
+		},
+		{
+			name: "invalid template",
+			cfgs: []validation.IngestionPipeline{{Name: "a", Pipeline: `| line_format "{{.foo"`}},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			limits := validation.Limits{}
+			flagext.DefaultValues(&limits)
+			limits.IngestionPipelines = tc.cfgs
+			require.Error(t, limits.Validate())
+		})
+	}
+}
+
+func TestDistributor_PushIngestionPipelines(t *testing.T) {
+	limits := &validation.Limits{}
+	flagext.DefaultValues(limits)
+	limits.EnforceMetricName = false
+	limits.AllowStructuredMetadata = true
+	limits.MaxLabelNamesPerSeries = 2
+	limits.IngestionPipelines = []validation.IngestionPipeline{{
+		Name:               "json",
+		Pipeline:           `| json | level != "debug" | label_format level="{{.level}}", service="{{.service}}"`,
+		StructuredMetadata: []string{"trace_id"},
+	}}
+	require.NoError(t, limits.Validate())
+
+	ingester := &mockIngester{}
+	distributors, _ := prepare(t, 1, 5, limits, func(addr string) (ring_client.PoolClient, error) { return ingester, nil })
+
+	now := time.Now()
+	_, err := distributors[0].Push(ctx, &logproto.PushRequest{Streams: []logproto.Stream{{
+		Labels: `{app="api"}`,
+		Entries: []logproto.Entry{
+			{Timestamp: now, Line: `{"level":"debug","trace_id":"1"}`},
+			{Timestamp: now, Line: `{"level":"info","trace_id":"2"}`},
+			{Timestamp: now, Line: `{"level":"info","service":"db"}`},
+		},
+	}}})
+	// The last entry ends up with too many labels.
+	require.Error(t, err)
+
+	pushed := map[string][]logproto.Entry{}
+	for _, req := range ingester.pushed {
+		for _, s := range req.Streams {
+			pushed[s.Labels] = s.Entries
+		}
+	}
+	require.Equal(t, map[string][]logproto.Entry{
+		`{app="api", level="info"}`: {{Timestamp: now, Line: `{"level":"info","trace_id":"2"}`, StructuredMetadata: push.LabelsAdapter{{Name: "trace_id", Value: "2"}}}},
+	}, pushed)
+}
@@ -0,0 +1,60 @@
+package labelpolicy
+
+import (
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/common/model"
+)
+
+const (
+	// ActionReject rejects the streams violating the policy.
+	ActionReject = "reject"
+	// ActionDrop removes the offending labels from the streams violating the policy.
+	ActionDrop = "drop"
+	// ActionStructuredMetadata moves the offending labels of the streams violating
+	// the policy to the structured metadata of their entries.
+	ActionStructuredMetadata = "structured_metadata"
+)
+
+type Config struct {
+	AllowedLabels  []string       `yaml:"allowed_labels" json:"allowed_labels" doc:"description=Label names allowed in the streams of the tenant. All label names are allowed if empty."`
+	MaxLabelValues []LabelLimit   `yaml:"max_label_values" json:"max_label_values" doc:"description=Maximum number of distinct values of the given labels over the window. The values are counted by each distributor.\nExample:\n max_label_values:\n - label: pod\n max_values: 1000"`
+	Window         model.Duration `yaml:"window" json:"window" doc:"description=Sliding window over which the distinct values of the labels are counted. A value stops counting once it was not seen for the window. Defaults to 1h."`
+	Action         string         `yaml:"action" json:"action" doc:"description=Action taken on the streams violating the policy: reject the stream, drop the offending labels or move them to structured_metadata. Defaults to reject."`
+}
+
+type LabelLimit struct {
+	Label     string `yaml:"label" json:"label" doc:"description:Name of the label."`
+	MaxValues int    `yaml:"max_values" json:"max_values" doc:"description:Maximum number of distinct values of the label over the window."`
+}
+
+func (cfg *Config) RegisterFlagsWithPrefix(prefix string, fs *flag.FlagSet) {
+	cfg.Window = model.Duration(time.Hour)
+	fs.Var(&cfg.Window, prefix+".window", "Sliding window over which the distinct values of the labels are counted. A value stops counting once it was not seen for the window.")
+	fs.StringVar(&cfg.Action, prefix+".action", ActionReject, "Action taken on the streams violating the label policy. Supported values are: reject, drop, structured_metadata.")
+}
+
+// Enabled returns whether any label policy is configured.
+func (cfg *Config) Enabled() bool {
+	return cfg != nil && (len(cfg.AllowedLabels) > 0 || len(cfg.MaxLabelValues) > 0)
+}
+
+// Validate validates that the label policy is valid.
+func (cfg *Config) Validate() error {
+	switch cfg.Action {
+	case ActionReject, ActionDrop, ActionStructuredMetadata:
+	default:
+		return fmt.Errorf("invalid label policy action %q, must be one of %s, %s, %s", cfg.Action, ActionReject, ActionDrop, ActionStructuredMetadata)
+	}
+	if len(cfg.MaxLabelValues) > 0 && cfg.Window <= 0 {
+		return fmt.Errorf("label policy window must be positive, was %s", cfg.Window)
+	}
+	for _, l := range cfg.MaxLabelValues {
+		if l.Label == "" || l.MaxValues <= 0 {
+			return fmt.Errorf("invalid label policy limit for label %q: label must be set and max_values must be positive", l.Label)
+		}
+	}
+	return nil
+}
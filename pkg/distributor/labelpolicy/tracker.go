@@ -0,0 +1,113 @@
+package labelpolicy
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/grafana/dskit/services"
+)
+
+// evictionInterval is the interval at which the expired values are removed.
+const evictionInterval = time.Minute
+
+// Tracker counts the distinct values of labels per tenant over a sliding window.
+// Each value expires once it wasn't seen for the window, so the values of a
+// label never expire all at once. At most the limit of values of a label are
+// tracked, values beyond the limit are rejected without being recorded.
+type Tracker struct {
+	services.Service
+
+	mtx     sync.Mutex
+	tenants map[string]map[string]*labelValues
+	now     func() time.Time
+}
+
+// labelValues are the values of a label ordered by the time they were last seen.
+type labelValues struct {
+	values map[string]*list.Element
+	order  *list.List
+	// window is the window of the last observation, used to evict the values.
+	window time.Duration
+}
+
+type labelValue struct {
+	value    string
+	lastSeen time.Time
+}
+
+func NewTracker() *Tracker {
+	t := &Tracker{
+		tenants: map[string]map[string]*labelValues{},
+		now:     time.Now,
+	}
+	t.Service = services.NewTimerService(evictionInterval, nil, t.evict, nil)
+	return t
+}
+
+// Observe records the value of the label of the tenant and returns whether it is
+// within the limit of distinct values seen over the window. Known values are
+// always accepted, new values only as long as fewer than limit values are known.
+func (t *Tracker) Observe(tenantID, label, value string, limit int, window time.Duration) bool {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	now := t.now()
+	tenant, ok := t.tenants[tenantID]
+	if !ok {
+		tenant = map[string]*labelValues{}
+		t.tenants[tenantID] = tenant
+	}
+	lv, ok := tenant[label]
+	if !ok {
+		lv = &labelValues{values: map[string]*list.Element{}, order: list.New()}
+		tenant[label] = lv
+	}
+	lv.window = window
+	lv.expire(now.Add(-window))
+
+	if e, ok := lv.values[value]; ok {
+		e.Value.(*labelValue).lastSeen = now
+		lv.order.MoveToBack(e)
+		return true
+	}
+	if len(lv.values) >= limit {
+		return false
+	}
+	lv.values[value] = lv.order.PushBack(&labelValue{value: value, lastSeen: now})
+	return true
+}
+
+// expire removes the values last seen at or before the given time.
+func (lv *labelValues) expire(before time.Time) {
+	for e := lv.order.Front(); e != nil; e = lv.order.Front() {
+		v := e.Value.(*labelValue)
+		if v.lastSeen.After(before) {
+			return
+		}
+		lv.order.Remove(e)
+		delete(lv.values, v.value)
+	}
+}
+
+// evict removes the values which weren't seen for the window of the label,
+// along with the labels and tenants left without values.
+func (t *Tracker) evict(_ context.Context) error {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	now := t.now()
+	for tenantID, tenant := range t.tenants {
+		for label, lv := range tenant {
+			lv.expire(now.Add(-lv.window))
+			if len(lv.values) == 0 {
+				delete(tenant, label)
+			}
+		}
+		if len(tenant) == 0 {
+			delete(t.tenants, tenantID)
+		}
+	}
+	return nil
+}
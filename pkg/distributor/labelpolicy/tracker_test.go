@@ -0,0 +1,99 @@
+package labelpolicy
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTracker(t *testing.T) {
+	now := time.Unix(0, 0)
+	tracker := NewTracker()
+	tracker.now = func() time.Time { return now }
+
+	// Values are counted exactly up to the limit.
+	for i := 0; i < 1000; i++ {
+		require.True(t, tracker.Observe("tenant", "pod", fmt.Sprintf("pod-%d", i), 1000, time.Hour))
+	}
+	for i := 1000; i < 2000; i++ {
+		require.False(t, tracker.Observe("tenant", "pod", fmt.Sprintf("pod-%d", i), 1000, time.Hour))
+	}
+
+	// Known values are still accepted.
+	for i := 0; i < 1000; i++ {
+		require.True(t, tracker.Observe("tenant", "pod", fmt.Sprintf("pod-%d", i), 1000, time.Hour))
+	}
+
+	// Labels and tenants are counted separately.
+	require.True(t, tracker.Observe("tenant", "namespace", "pod-1000", 1, time.Hour))
+	require.False(t, tracker.Observe("tenant", "namespace", "pod-1001", 1, time.Hour))
+	require.True(t, tracker.Observe("other", "pod", "pod-1000", 1, time.Hour))
+}
+
+func TestTracker_SlidingWindow(t *testing.T) {
+	now := time.Unix(0, 0)
+	tracker := NewTracker()
+	tracker.now = func() time.Time { return now }
+
+	require.True(t, tracker.Observe("tenant", "pod", "a", 2, time.Hour))
+	now = now.Add(30 * time.Minute)
+	require.True(t, tracker.Observe("tenant", "pod", "b", 2, time.Hour))
+	require.False(t, tracker.Observe("tenant", "pod", "c", 2, time.Hour))
+
+	// Only the value not seen for the window expires.
+	now = now.Add(30 * time.Minute)
+	require.True(t, tracker.Observe("tenant", "pod", "c", 2, time.Hour))
+	require.False(t, tracker.Observe("tenant", "pod", "a", 2, time.Hour))
+
+	// Seeing a value again keeps it from expiring.
+	now = now.Add(20 * time.Minute)
+	require.True(t, tracker.Observe("tenant", "pod", "b", 2, time.Hour))
+	now = now.Add(20 * time.Minute)
+	require.False(t, tracker.Observe("tenant", "pod", "a", 2, time.Hour))
+	now = now.Add(20 * time.Minute)
+	require.True(t, tracker.Observe("tenant", "pod", "a", 2, time.Hour))
+}
+
+func TestTracker_Evict(t *testing.T) {
+	now := time.Unix(0, 0)
+	tracker := NewTracker()
+	tracker.now = func() time.Time { return now }
+
+	require.True(t, tracker.Observe("tenant", "pod", "a", 10, time.Hour))
+	require.True(t, tracker.Observe("other", "pod", "a", 10, time.Hour))
+	now = now.Add(30 * time.Minute)
+	require.True(t, tracker.Observe("tenant", "namespace", "a", 10, time.Hour))
+	require.True(t, tracker.Observe("tenant", "pod", "b", 10, time.Hour))
+
+	now = now.Add(45 * time.Minute)
+	require.NoError(t, tracker.evict(context.Background()))
+	require.Len(t, tracker.tenants, 1)
+	require.Len(t, tracker.tenants["tenant"], 2)
+	require.Len(t, tracker.tenants["tenant"]["pod"].values, 1)
+	require.Contains(t, tracker.tenants["tenant"]["pod"].values, "b")
+
+	now = now.Add(time.Hour)
+	require.NoError(t, tracker.evict(context.Background()))
+	require.Empty(t, tracker.tenants)
+}
+
+func TestConfig_Validate(t *testing.T) {
+	cfg := Config{Action: ActionDrop, Window: 0}
+	require.NoError(t, cfg.Validate())
+	require.False(t, cfg.Enabled())
+
+	cfg.MaxLabelValues = []LabelLimit{{Label: "pod", MaxValues: 10}}
+	require.Error(t, cfg.Validate())
+	cfg.Window.Set("1h") //nolint:errcheck
+	require.NoError(t, cfg.Validate())
+	require.True(t, cfg.Enabled())
+
+	cfg.MaxLabelValues[0].MaxValues = 0
+	require.Error(t, cfg.Validate())
+
+	cfg = Config{Action: "ignore"}
+	require.Error(t, cfg.Validate())
+}
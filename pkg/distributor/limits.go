@@ -4,7 +4,9 @@ import (
 	"time"
 
 	"github.com/grafana/loki/pkg/compactor/retention"
+	"github.com/grafana/loki/pkg/distributor/labelpolicy"
 	"github.com/grafana/loki/pkg/distributor/shardstreams"
+	"github.com/grafana/loki/pkg/validation"
 )
 
 // Limits is an interface for distributor limits/related configs
@@ -24,6 +26,8 @@ type Limits interface {
 	IncrementDuplicateTimestamps(userID string) bool
 
 	ShardStreams(userID string) *shardstreams.Config
+	LabelPolicy(userID string) *labelpolicy.Config
+	IngestionPipelines(userID string) []validation.IngestionPipeline
 	IngestionRateStrategy() string
 	IngestionRateBytes(userID string) float64
 	IngestionBurstSizeBytes(userID string) int
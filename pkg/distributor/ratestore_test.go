@@ -333,6 +333,10 @@ func (c *fakeStreamDataClient) GetStreamRates(_ context.Context, _ *logproto.Str
 	return c.resp, c.err
 }
 
+func (c *fakeStreamDataClient) GetOutOfOrderStats(_ context.Context, _ *logproto.OutOfOrderStatsRequest, _ ...grpc.CallOption) (*logproto.OutOfOrderStatsResponse, error) {
+	return &logproto.OutOfOrderStatsResponse{}, nil
+}
+
 type fakeOverrides struct {
 	Limits
 	enabled bool
@@ -311,6 +311,11 @@ type WALCheckpointWriter struct {
 	final         string // filename to atomically rotate upon completion
 	bufSize       int
 	recs          [][]byte
+
+	// encrypts the records, nil if the WAL encryption is disabled.
+	encryptor *walEncryptor
+	// when the current and the previous checkpoints started.
+	advancedAt, prevAdvancedAt time.Time
 }
 
 func (w *WALCheckpointWriter) Advance() (bool, error) {
@@ -356,6 +361,7 @@ func (w *WALCheckpointWriter) Advance() (bool, error) {
 	w.checkpointWAL = checkpoint
 	w.lastSegment = lastSegment
 	w.final = checkpointDir
+	w.advancedAt = time.Now()
 
 	return false, nil
 }
@@ -364,6 +370,13 @@ func (w *WALCheckpointWriter) Advance() (bool, error) {
 var recordBufferPool = prompool.New(1<<16, 1<<28, 2, func(size int) interface{} { return make([]byte, 0, size) })
 
 func (w *WALCheckpointWriter) Write(s *Series) error {
+	// The streams of a tenant shredded during the checkpoint may have been read before they were dropped, and
+	// would be recovered with a new data key. The streams created since were written after the segments the
+	// checkpoint replaces, so none of the series of the tenant is needed.
+	if w.encryptor != nil && w.encryptor.shreddedAfter(s.UserID, w.advancedAt) {
+		return nil
+	}
+
 	size := s.Size() + 1 // +1 for header
 	buf := recordBufferPool.Get(size).([]byte)[:size]
 
@@ -371,6 +384,14 @@ func (w *WALCheckpointWriter) Write(s *Series) error {
 	if err != nil {
 		return err
 	}
+	if w.encryptor != nil {
+		encrypted, err := w.encryptor.encrypt(s.UserID, b, recordBufferPool.Get(size+encryptedRecordOverhead(s.UserID)).([]byte))
+		recordBufferPool.Put(b)
+		if err != nil {
+			return err
+		}
+		b = encrypted
+	}
 
 	w.recs = append(w.recs, b)
 	w.bufSize += len(b)
@@ -513,6 +534,17 @@ func (w *WALCheckpointWriter) Close(abort bool) error {
 		}
 	}
 
+	if w.encryptor != nil {
+		// The records encrypted with the data keys retired before the previous checkpoint started were all
+		// written to the segments covered by this checkpoint, which were just deleted.
+		if !w.prevAdvancedAt.IsZero() {
+			if err := w.encryptor.prune(w.prevAdvancedAt); err != nil {
+				level.Error(util_log.Logger).Log("msg", "error deleting retired WAL data keys", "err", err)
+			}
+		}
+		w.prevAdvancedAt = w.advancedAt
+	}
+
 	return nil
 }
 
@@ -181,6 +181,9 @@ type Interface interface {
 	GetOrCreateInstance(instanceID string) (*instance, error)
 	ShutdownHandler(w http.ResponseWriter, r *http.Request)
 	PrepareShutdown(w http.ResponseWriter, r *http.Request)
+	SnapshotHandler(w http.ResponseWriter, r *http.Request)
+	ShredWALKeysHandler(w http.ResponseWriter, r *http.Request)
+	OutOfOrderStatsHandler(w http.ResponseWriter, r *http.Request)
 }
 
 // Ingester builds chunks for incoming log streams.
@@ -228,6 +231,14 @@ type Ingester struct {
 
 	wal WAL
 
+	// Uploads the WAL to an object store, nil if the WAL snapshots are disabled.
+	snapshotter *walSnapshotter
+	// Whether the WAL was restored from a snapshot, which is deleted once replayed.
+	snapshotRestored bool
+
+	// Encrypts the WAL, nil if the WAL encryption is disabled.
+	walEncryptor *walEncryptor
+
 	chunkFilter chunk.RequestChunkFilterer
 
 	streamRateCalculator *StreamRateCalculator
@@ -278,7 +289,30 @@ func New(cfg Config, clientConfig client.Config, store Store, limits Limits, con
 		}
 	}
 
-	wal, err := newWAL(cfg.WAL, registerer, metrics, newIngesterSeriesIter(i))
+	if cfg.WAL.Snapshot.Enabled {
+		snapshotter, err := newWALSnapshotter(cfg.WAL.Dir, cfg.WAL.Snapshot, cfg.LifecyclerConfig.ID, metrics)
+		if err != nil {
+			return nil, err
+		}
+		i.snapshotter = snapshotter
+
+		// The snapshot needs to be restored before the WAL is opened, which creates a new segment.
+		i.snapshotRestored, err = snapshotter.restore(context.Background())
+		if err != nil {
+			return nil, fmt.Errorf("restoring WAL snapshot: %w", err)
+		}
+	}
+
+	if cfg.WAL.Encryption.Enabled {
+		// The data keys are loaded after the snapshot is restored, as they are part of it.
+		encryptor, err := newWALEncryptor(cfg.WAL.Dir, cfg.WAL.Encryption, metrics)
+		if err != nil {
+			return nil, fmt.Errorf("loading WAL encryption keys: %w", err)
+		}
+		i.walEncryptor = encryptor
+	}
+
+	wal, err := newWAL(cfg.WAL, registerer, metrics, newIngesterSeriesIter(i), i.walEncryptor)
 	if err != nil {
 		return nil, err
 	}
@@ -422,6 +456,9 @@ func (i *Ingester) starting(ctx context.Context) error {
 			return err
 		}
 		defer checkpointCloser.Close()
+		if i.walEncryptor != nil {
+			checkpointReader = newDecryptingWALReader(checkpointReader, i.walEncryptor, i.metrics)
+		}
 
 		checkpointRecoveryErr := RecoverCheckpoint(checkpointReader, recoverer)
 		if checkpointRecoveryErr != nil {
@@ -445,7 +482,11 @@ func (i *Ingester) starting(ctx context.Context) error {
 		}
 		defer segmentCloser.Close()
 
-		segmentRecoveryErr := RecoverWAL(segmentReader, recoverer)
+		var segmentRecordReader WALReader = segmentReader
+		if i.walEncryptor != nil {
+			segmentRecordReader = newDecryptingWALReader(segmentReader, i.walEncryptor, i.metrics)
+		}
+		segmentRecoveryErr := RecoverWAL(segmentRecordReader, recoverer)
 		if segmentRecoveryErr != nil {
 			i.metrics.walCorruptionsTotal.WithLabelValues(walTypeSegment).Inc()
 			level.Error(util_log.Logger).Log(
@@ -463,6 +504,13 @@ func (i *Ingester) starting(ctx context.Context) error {
 		endReplay()
 
 		i.wal.Start()
+
+		if i.snapshotRestored {
+			// The replayed data is now part of the local WAL.
+			if err := i.snapshotter.delete(ctx); err != nil {
+				level.Error(util_log.Logger).Log("msg", "failed to delete restored WAL snapshot", "err", err)
+			}
+		}
 	}
 
 	i.InitFlushQueues()
@@ -527,6 +575,13 @@ func (i *Ingester) stopping(_ error) error {
 	if i.flushOnShutdownSwitch.Get() {
 		i.lifecycler.SetFlushOnShutdown(true)
 	}
+	// The chunks flushed on shutdown don't need to be handed off.
+	if i.snapshotter != nil && i.cfg.WAL.Snapshot.OnShutdown && !i.lifecycler.FlushOnShutdown() {
+		if err := i.snapshotter.upload(context.Background()); err != nil {
+			level.Error(util_log.Logger).Log("msg", "failed to upload WAL snapshot on shutdown", "err", err)
+			errs.Add(err)
+		}
+	}
 	errs.Add(services.StopAndAwaitTerminated(context.Background(), i.lifecycler))
 
 	for _, flushQueue := range i.flushQueues {
@@ -755,6 +810,60 @@ func shutdownMarkerExists(p string) (bool, error) {
 	return s.Mode().IsRegular(), nil
 }
 
+// SnapshotHandler handles the /ingester/snapshot endpoint, which uploads a snapshot of the WAL to the object store.
+func (i *Ingester) SnapshotHandler(w http.ResponseWriter, r *http.Request) {
+	if i.snapshotter == nil {
+		http.Error(w, "WAL snapshots are not enabled", http.StatusBadRequest)
+		return
+	}
+	if err := i.snapshotter.upload(r.Context()); err != nil {
+		level.Error(util_log.Logger).Log("msg", "failed to upload WAL snapshot", "err", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ShredWALKeysHandler handles the /ingester/wal/shred endpoint, which drops the streams of the tenant from memory
+// and deletes its WAL data keys. The records of the tenant in the WAL, checkpoints and snapshots can't be decrypted
+// anymore and are skipped on replay.
+func (i *Ingester) ShredWALKeysHandler(w http.ResponseWriter, r *http.Request) {
+	if i.walEncryptor == nil {
+		http.Error(w, "WAL encryption is not enabled", http.StatusBadRequest)
+		return
+	}
+	tenantID, err := tenant.TenantID(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	i.dropStreams(tenantID)
+	if _, err := i.walEncryptor.shred(tenantID); err != nil {
+		level.Error(util_log.Logger).Log("msg", "failed to shred WAL data keys", "tenant", tenantID, "err", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// dropStreams removes the streams of the tenant from memory along with their chunks, flushed or not.
+func (i *Ingester) dropStreams(tenantID string) {
+	instance, ok := i.getInstanceByID(tenantID)
+	if !ok {
+		return
+	}
+	instance.streams.WithLock(func() {
+		_ = instance.streams.ForEach(func(s *stream) (bool, error) {
+			s.chunkMtx.Lock()
+			i.metrics.memoryChunks.Sub(float64(len(s.chunks)))
+			s.chunks = nil
+			s.chunkMtx.Unlock()
+			instance.removeStream(s)
+			return true, nil
+		})
+	})
+}
+
 // ShutdownHandler handles a graceful shutdown of the ingester service and
 // termination of the Loki process.
 func (i *Ingester) ShutdownHandler(w http.ResponseWriter, r *http.Request) {
@@ -444,7 +444,7 @@ func (i *instance) Query(ctx context.Context, req logql.SelectLogParams) (iter.E
 		return nil, err
 	}
 
-	return iter.NewSortEntryIterator(iters, req.Direction), nil
+	return logql.NewMergeStagesIterator(iter.NewSortEntryIterator(iters, req.Direction), expr, req.Start, req.End)
 }
 
 func (i *instance) QuerySample(ctx context.Context, req logql.SelectSampleParams) (iter.SampleIterator, error) {
@@ -703,6 +703,39 @@ func Test_QueryWithDelete(t *testing.T) {
 	require.Equal(t, logs, []string{`msg="dispatcher_7"`})
 }
 
+func Test_QueryWithLimitBy(t *testing.T) {
+	instance := defaultInstance(t)
+
+	for _, tc := range []struct {
+		selector string
+		want     []string
+	}{
+		{`{job="3"} | limit 2 by (host)`, []string{`msg="dispatcher_9"`, `msg="worker_8"`}},
+		// the range is sampled in intervals of 2^23ns, about 8.4ms.
+		{`{job="3"} | limit 2 by (log_stream) sample`, []string{`msg="dispatcher_9"`, `msg="worker_8"`, `msg="dispatcher_7"`}},
+	} {
+		it, err := instance.Query(context.TODO(),
+			logql.SelectLogParams{
+				QueryRequest: &logproto.QueryRequest{
+					Selector:  tc.selector,
+					Limit:     uint32(10),
+					Start:     time.Unix(0, 0),
+					End:       time.Unix(0, 10000000),
+					Direction: logproto.BACKWARD,
+				},
+			},
+		)
+		require.NoError(t, err)
+
+		var logs []string
+		for it.Next() {
+			logs = append(logs, it.Entry().Line)
+		}
+		require.NoError(t, it.Close())
+		require.Equal(t, tc.want, logs, tc.selector)
+	}
+}
+
 func Test_QuerySampleWithDelete(t *testing.T) {
 	instance := defaultInstance(t)
 
@@ -25,6 +25,13 @@ type ingesterMetrics struct {
 	walLoggedBytesTotal     prometheus.Counter
 	walRecordsLogged        prometheus.Counter
 
+	walSnapshotUploadsTotal       *prometheus.CounterVec
+	walSnapshotUploadedBytesTotal prometheus.Counter
+	walSnapshotRestoresTotal      prometheus.Counter
+
+	walEncryptionDataKeys     prometheus.Gauge
+	walShreddedRecordsSkipped prometheus.Counter
+
 	recoveredStreamsTotal prometheus.Counter
 	recoveredChunksTotal  prometheus.Counter
 	recoveredEntriesTotal prometheus.Counter
@@ -139,6 +146,26 @@ func newIngesterMetrics(r prometheus.Registerer) *ingesterMetrics {
 			Name: "loki_ingester_wal_logged_bytes_total",
 			Help: "Total number of bytes written to disk for WAL records.",
 		}),
+		walSnapshotUploadsTotal: promauto.With(r).NewCounterVec(prometheus.CounterOpts{
+			Name: "loki_ingester_wal_snapshot_uploads_total",
+			Help: "Total number of WAL snapshots uploaded to the object store by status.",
+		}, []string{"status"}),
+		walSnapshotUploadedBytesTotal: promauto.With(r).NewCounter(prometheus.CounterOpts{
+			Name: "loki_ingester_wal_snapshot_uploaded_bytes_total",
+			Help: "Total number of bytes of the WAL uploaded to the object store by the snapshots.",
+		}),
+		walSnapshotRestoresTotal: promauto.With(r).NewCounter(prometheus.CounterOpts{
+			Name: "loki_ingester_wal_snapshot_restores_total",
+			Help: "Total number of WAL snapshots restored from the object store.",
+		}),
+		walEncryptionDataKeys: promauto.With(r).NewGauge(prometheus.GaugeOpts{
+			Name: "loki_ingester_wal_encryption_data_keys",
+			Help: "Number of data keys encrypting the WAL and checkpoints.",
+		}),
+		walShreddedRecordsSkipped: promauto.With(r).NewCounter(prometheus.CounterOpts{
+			Name: "loki_ingester_wal_shredded_records_skipped_total",
+			Help: "Total number of WAL and checkpoint records skipped during replay because the data key of their tenant was shredded.",
+		}),
 		recoveredStreamsTotal: promauto.With(r).NewCounter(prometheus.CounterOpts{
 			Name: "loki_ingester_wal_recovered_streams_total",
 			Help: "Total number of streams recovered from the WAL.",
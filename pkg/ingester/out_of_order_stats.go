@@ -0,0 +1,214 @@
+package ingester
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/tenant"
+
+	"github.com/grafana/loki/pkg/logproto"
+	util_log "github.com/grafana/loki/pkg/util/log"
+)
+
+// DefaultOutOfOrderStatsLimit is the default number of streams returned by the out-of-order stats endpoints.
+const DefaultOutOfOrderStatsLimit = 10
+
+// outOfOrderStats tracks how far out of order the entries pushed to a stream are.
+// Not thread-safe; accesses are locked by the chunkMtx of the stream.
+type outOfOrderStats struct {
+	entries, bytes                 uint64
+	maxDistance, totalDistance     time.Duration
+	rejectedEntries, rejectedBytes uint64
+}
+
+// accepted records an entry accepted distance behind the newest entry of the stream.
+func (s *outOfOrderStats) accepted(distance time.Duration, bytes int) {
+	s.entries++
+	s.bytes += uint64(bytes)
+	s.totalDistance += distance
+	if distance > s.maxDistance {
+		s.maxDistance = distance
+	}
+}
+
+// rejected records an entry rejected for being out of order or too far behind.
+func (s *outOfOrderStats) rejected(bytes int) {
+	s.rejectedEntries++
+	s.rejectedBytes += uint64(bytes)
+}
+
+func (s *outOfOrderStats) isZero() bool {
+	return s.entries == 0 && s.rejectedEntries == 0
+}
+
+// OutOfOrderStats are the out-of-order stats of a tenant, returned by the out-of-order stats endpoints.
+type OutOfOrderStats struct {
+	Tenant OutOfOrderStreamStats `json:"tenant"`
+	// The streams sending the most out-of-order entries, in descending order.
+	Streams []OutOfOrderStreamStats `json:"streams"`
+}
+
+// OutOfOrderStreamStats are the out-of-order stats of a stream, or the sum of those of all the streams of a tenant.
+type OutOfOrderStreamStats struct {
+	Labels string `json:"labels,omitempty"`
+	// Streams is the number of streams which received out-of-order entries, only set for tenants.
+	Streams           int    `json:"streams,omitempty"`
+	OutOfOrderEntries uint64 `json:"out_of_order_entries"`
+	OutOfOrderBytes   uint64 `json:"out_of_order_bytes"`
+	MaxDistance       string `json:"max_distance"`
+	AvgDistance       string `json:"avg_distance"`
+	RejectedEntries   uint64 `json:"rejected_entries"`
+	RejectedBytes     uint64 `json:"rejected_bytes"`
+}
+
+func newOutOfOrderStreamStats(s *logproto.StreamOutOfOrderStats) OutOfOrderStreamStats {
+	var avg time.Duration
+	if s.OutOfOrderEntries > 0 {
+		avg = time.Duration(s.TotalDistance / int64(s.OutOfOrderEntries))
+	}
+	return OutOfOrderStreamStats{
+		Labels:            s.Labels,
+		OutOfOrderEntries: s.OutOfOrderEntries,
+		OutOfOrderBytes:   s.OutOfOrderBytes,
+		MaxDistance:       time.Duration(s.MaxDistance).String(),
+		AvgDistance:       avg.String(),
+		RejectedEntries:   s.RejectedEntries,
+		RejectedBytes:     s.RejectedBytes,
+	}
+}
+
+// MergeOutOfOrderStats merges the out-of-order stats returned by the ingesters for a tenant and keeps the
+// limit streams with the most rejected entries, then the most out-of-order entries.
+// As a stream is replicated to several ingesters, the highest stats reported for each stream are kept.
+func MergeOutOfOrderStats(resps []*logproto.OutOfOrderStatsResponse, limit int) OutOfOrderStats {
+	byStream := map[string]*logproto.StreamOutOfOrderStats{}
+	for _, resp := range resps {
+		if resp == nil {
+			continue
+		}
+		for _, s := range resp.Streams {
+			merged, ok := byStream[s.Labels]
+			if !ok {
+				byStream[s.Labels] = s
+				continue
+			}
+			if s.RejectedEntries > merged.RejectedEntries || (s.RejectedEntries == merged.RejectedEntries && s.OutOfOrderEntries > merged.OutOfOrderEntries) {
+				byStream[s.Labels] = s
+			}
+		}
+	}
+
+	streams := make([]*logproto.StreamOutOfOrderStats, 0, len(byStream))
+	total := &logproto.StreamOutOfOrderStats{}
+	for _, s := range byStream {
+		streams = append(streams, s)
+		total.OutOfOrderEntries += s.OutOfOrderEntries
+		total.OutOfOrderBytes += s.OutOfOrderBytes
+		total.TotalDistance += s.TotalDistance
+		total.RejectedEntries += s.RejectedEntries
+		total.RejectedBytes += s.RejectedBytes
+		if s.MaxDistance > total.MaxDistance {
+			total.MaxDistance = s.MaxDistance
+		}
+	}
+	sort.Slice(streams, func(i, j int) bool {
+		if streams[i].RejectedEntries != streams[j].RejectedEntries {
+			return streams[i].RejectedEntries > streams[j].RejectedEntries
+		}
+		if streams[i].OutOfOrderEntries != streams[j].OutOfOrderEntries {
+			return streams[i].OutOfOrderEntries > streams[j].OutOfOrderEntries
+		}
+		return streams[i].Labels < streams[j].Labels
+	})
+	if limit > 0 && len(streams) > limit {
+		streams = streams[:limit]
+	}
+
+	res := OutOfOrderStats{
+		Tenant:  newOutOfOrderStreamStats(total),
+		Streams: make([]OutOfOrderStreamStats, 0, len(streams)),
+	}
+	res.Tenant.Streams = len(byStream)
+	for _, s := range streams {
+		res.Streams = append(res.Streams, newOutOfOrderStreamStats(s))
+	}
+	return res
+}
+
+// OutOfOrderStatsLimit parses the limit parameter of the out-of-order stats endpoints.
+func OutOfOrderStatsLimit(r *http.Request) (int, error) {
+	value := r.FormValue("limit")
+	if value == "" {
+		return DefaultOutOfOrderStatsLimit, nil
+	}
+	return strconv.Atoi(value)
+}
+
+// WriteOutOfOrderStats writes the out-of-order stats as a JSON response.
+func WriteOutOfOrderStats(w http.ResponseWriter, stats OutOfOrderStats) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(stats); err != nil {
+		level.Error(util_log.Logger).Log("msg", "error encoding out-of-order stats response", "err", err)
+	}
+}
+
+// outOfOrderStats returns the out-of-order stats of the streams which received out-of-order entries.
+func (i *instance) outOfOrderStats(ctx context.Context) (*logproto.OutOfOrderStatsResponse, error) {
+	resp := &logproto.OutOfOrderStatsResponse{}
+	err := i.forAllStreams(ctx, func(s *stream) error {
+		s.chunkMtx.RLock()
+		stats := s.outOfOrderStats
+		s.chunkMtx.RUnlock()
+
+		if stats.isZero() {
+			return nil
+		}
+		resp.Streams = append(resp.Streams, &logproto.StreamOutOfOrderStats{
+			Labels:            s.labelsString,
+			OutOfOrderEntries: stats.entries,
+			OutOfOrderBytes:   stats.bytes,
+			MaxDistance:       int64(stats.maxDistance),
+			TotalDistance:     int64(stats.totalDistance),
+			RejectedEntries:   stats.rejectedEntries,
+			RejectedBytes:     stats.rejectedBytes,
+		})
+		return nil
+	})
+	return resp, err
+}
+
+// GetOutOfOrderStats returns the out-of-order stats of the streams of the tenant which received out-of-order entries.
+func (i *Ingester) GetOutOfOrderStats(ctx context.Context, _ *logproto.OutOfOrderStatsRequest) (*logproto.OutOfOrderStatsResponse, error) {
+	instanceID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	instance, ok := i.getInstanceByID(instanceID)
+	if !ok {
+		return &logproto.OutOfOrderStatsResponse{}, nil
+	}
+	return instance.outOfOrderStats(ctx)
+}
+
+// OutOfOrderStatsHandler handles the /ingester/out_of_order_stats endpoint, which returns the out-of-order
+// stats of the tenant and of its streams sending the most out-of-order entries to this ingester.
+func (i *Ingester) OutOfOrderStatsHandler(w http.ResponseWriter, r *http.Request) {
+	limit, err := OutOfOrderStatsLimit(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resp, err := i.GetOutOfOrderStats(r.Context(), &logproto.OutOfOrderStatsRequest{})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	WriteOutOfOrderStats(w, MergeOutOfOrderStats([]*logproto.OutOfOrderStatsResponse{resp}, limit))
+}
@@ -0,0 +1,136 @@
+package ingester
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/grafana/dskit/services"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/pkg/distributor/writefailures"
+	"github.com/grafana/loki/pkg/ingester/client"
+	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/runtime"
+	"github.com/grafana/loki/pkg/storage/chunk"
+	"github.com/grafana/loki/pkg/validation"
+)
+
+func TestStreamOutOfOrderStats(t *testing.T) {
+	cfg := defaultIngesterTestConfig(t)
+	cfg.MaxChunkAge = 10 * time.Second
+	limits, err := validation.NewOverrides(defaultLimitsTestConfig(), nil)
+	require.NoError(t, err)
+	limiter := NewLimiter(limits, NilMetrics, &ringCountMock{count: 1}, 1)
+
+	chunkfmt, headfmt := defaultChunkFormat(t)
+
+	s := newStream(
+		chunkfmt,
+		headfmt,
+		&cfg,
+		limiter,
+		"fake",
+		model.Fingerprint(0),
+		labels.Labels{
+			{Name: "foo", Value: "bar"},
+		},
+		true,
+		NewStreamRateCalculator(),
+		NilMetrics,
+		nil,
+	)
+
+	_, err = s.Push(context.Background(), []logproto.Entry{
+		{Timestamp: time.Unix(10, 0), Line: "x"},
+		{Timestamp: time.Unix(8, 0), Line: "xx"},
+		{Timestamp: time.Unix(12, 0), Line: "x"},
+		{Timestamp: time.Unix(11, 0), Line: "xxx"},
+		// rejected for being older than the validity window of 5s
+		{Timestamp: time.Unix(1, 0), Line: "xxxx"},
+	}, recordPool.GetRecord(), 0, true, false)
+	require.Error(t, err)
+
+	require.Equal(t, outOfOrderStats{
+		entries:         2,
+		bytes:           5,
+		maxDistance:     2 * time.Second,
+		totalDistance:   3 * time.Second,
+		rejectedEntries: 1,
+		rejectedBytes:   4,
+	}, s.outOfOrderStats)
+}
+
+func TestMergeOutOfOrderStats(t *testing.T) {
+	resps := []*logproto.OutOfOrderStatsResponse{
+		{Streams: []*logproto.StreamOutOfOrderStats{
+			{Labels: `{app="a"}`, OutOfOrderEntries: 4, OutOfOrderBytes: 40, MaxDistance: int64(3 * time.Second), TotalDistance: int64(4 * time.Second)},
+			{Labels: `{app="b"}`, RejectedEntries: 1, RejectedBytes: 10},
+		}},
+		// replica of the streams with more entries pushed
+		{Streams: []*logproto.StreamOutOfOrderStats{
+			{Labels: `{app="a"}`, OutOfOrderEntries: 6, OutOfOrderBytes: 60, MaxDistance: int64(3 * time.Second), TotalDistance: int64(6 * time.Second)},
+			{Labels: `{app="c"}`, OutOfOrderEntries: 1, OutOfOrderBytes: 10, MaxDistance: int64(time.Minute), TotalDistance: int64(time.Minute)},
+		}},
+		nil,
+	}
+
+	require.Equal(t, OutOfOrderStats{
+		Tenant: OutOfOrderStreamStats{
+			Streams:           3,
+			OutOfOrderEntries: 7,
+			OutOfOrderBytes:   70,
+			MaxDistance:       "1m0s",
+			AvgDistance:       "9.428571428s",
+			RejectedEntries:   1,
+			RejectedBytes:     10,
+		},
+		Streams: []OutOfOrderStreamStats{
+			{Labels: `{app="b"}`, MaxDistance: "0s", AvgDistance: "0s", RejectedEntries: 1, RejectedBytes: 10},
+			{Labels: `{app="a"}`, OutOfOrderEntries: 6, OutOfOrderBytes: 60, MaxDistance: "3s", AvgDistance: "1s"},
+		},
+	}, MergeOutOfOrderStats(resps, 2))
+}
+
+func TestIngester_OutOfOrderStatsHandler(t *testing.T) {
+	ingesterConfig := defaultIngesterTestConfig(t)
+	ingesterConfig.MaxChunkAge = 10 * time.Second
+	limits, err := validation.NewOverrides(defaultLimitsTestConfig(), nil)
+	require.NoError(t, err)
+	store := &mockStore{chunks: map[string][]chunk.Chunk{}}
+
+	i, err := New(ingesterConfig, client.Config{}, store, limits, runtime.DefaultTenantConfigs(), nil, writefailures.Cfg{})
+	require.NoError(t, err)
+	require.Nil(t, services.StartAndAwaitRunning(context.Background(), i))
+	defer services.StopAndAwaitTerminated(context.Background(), i) //nolint:errcheck
+
+	ctx := user.InjectOrgID(context.Background(), "test")
+	_, err = i.Push(ctx, &logproto.PushRequest{Streams: []logproto.Stream{
+		{Labels: `{app="a"}`, Entries: []logproto.Entry{
+			{Timestamp: time.Unix(10, 0), Line: "1"},
+			{Timestamp: time.Unix(9, 0), Line: "2"},
+		}},
+		{Labels: `{app="b"}`, Entries: []logproto.Entry{
+			{Timestamp: time.Unix(10, 0), Line: "1"},
+		}},
+	}})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/ingester/out_of_order_stats?limit=5", nil).WithContext(ctx)
+	w := httptest.NewRecorder()
+	i.OutOfOrderStatsHandler(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var stats OutOfOrderStats
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &stats))
+	require.Equal(t, 1, stats.Tenant.Streams)
+	require.Equal(t, []OutOfOrderStreamStats{
+		{Labels: `{app="a"}`, OutOfOrderEntries: 1, OutOfOrderBytes: 1, MaxDistance: "1s", AvgDistance: "1s"},
+	}, stats.Streams)
+}
@@ -0,0 +1,301 @@
+package ingester
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/go-kit/log/level"
+	"github.com/pkg/errors"
+
+	"github.com/grafana/loki/pkg/storage/chunk/client"
+	util_log "github.com/grafana/loki/pkg/util/log"
+)
+
+const (
+	snapshotManifestName = "manifest.json"
+	// snapshotRestoreDir is the directory of the WAL directory the snapshot is downloaded to before being moved
+	// into the WAL directory, so that an interrupted restore doesn't leave a partial WAL behind.
+	snapshotRestoreDir = "snapshot.tmp"
+)
+
+// SnapshotConfig configures the snapshots of the WAL uploaded to an object store.
+type SnapshotConfig struct {
+	Enabled     bool   `yaml:"enabled"`
+	ObjectStore string `yaml:"object_store"`
+	KeyPrefix   string `yaml:"key_prefix"`
+	OnShutdown  bool   `yaml:"on_shutdown"`
+
+	// ObjectClient is the client of the object store the snapshots are uploaded to.
+	ObjectClient client.ObjectClient `yaml:"-"`
+}
+
+// RegisterFlags adds the flags required to config this to the given FlagSet
+func (cfg *SnapshotConfig) RegisterFlags(f *flag.FlagSet) {
+	f.BoolVar(&cfg.Enabled, "ingester.wal-snapshot-enabled", false, "Enable the snapshots of the WAL to an object store. A snapshot holds the last checkpoint and the WAL segments following it. An ingester starting with an empty WAL directory restores the snapshot of its ingester ID before joining the ring, so an ingester replacing another one with the same ID and tokens recovers its un-flushed data.")
+	f.StringVar(&cfg.ObjectStore, "ingester.wal-snapshot-object-store", "", "The object store the WAL snapshots are uploaded to. Defaults to the object store of the active schema period.")
+	f.StringVar(&cfg.KeyPrefix, "ingester.wal-snapshot-key-prefix", "wal-snapshots/", "Prefix of the keys of the WAL snapshots in the object store. The snapshot of an ingester is stored under its ingester ID.")
+	f.BoolVar(&cfg.OnShutdown, "ingester.wal-snapshot-on-shutdown", true, "Upload a snapshot of the WAL on shutdown, unless the chunks are flushed on shutdown. Snapshots can also be uploaded on demand with the /ingester/snapshot endpoint.")
+}
+
+func (cfg *SnapshotConfig) Validate() error {
+	if !cfg.Enabled {
+		return nil
+	}
+	if cfg.KeyPrefix == "" || strings.HasPrefix(cfg.KeyPrefix, "/") || !strings.HasSuffix(cfg.KeyPrefix, "/") {
+		return errors.Errorf("invalid WAL snapshot key prefix %q, it should end with a '/' but not start with one", cfg.KeyPrefix)
+	}
+	return nil
+}
+
+// snapshotManifest lists the files of a snapshot, relative to the WAL directory.
+// It is uploaded after all the files so that only complete snapshots are restored.
+type snapshotManifest struct {
+	Files []string `json:"files"`
+}
+
+// walSnapshotter uploads the WAL directory of an ingester to an object store and restores it.
+type walSnapshotter struct {
+	dir          string
+	key          string
+	objectClient client.ObjectClient
+	metrics      *ingesterMetrics
+
+	// serializes the uploads of snapshots.
+	mtx sync.Mutex
+}
+
+func newWALSnapshotter(dir string, cfg SnapshotConfig, ingesterID string, metrics *ingesterMetrics) (*walSnapshotter, error) {
+	if cfg.ObjectClient == nil {
+		return nil, errors.New("no object client configured for the WAL snapshots")
+	}
+
+	return &walSnapshotter{
+		dir:          dir,
+		key:          cfg.KeyPrefix + ingesterID + "/",
+		objectClient: cfg.ObjectClient,
+		metrics:      metrics,
+	}, nil
+}
+
+// upload uploads the last checkpoint and the WAL segments of the WAL directory, then the manifest listing them.
+// The files of the previous snapshot which aren't part of the new one are deleted once the manifest is uploaded.
+func (s *walSnapshotter) upload(ctx context.Context) (err error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	status := "success"
+	defer func() {
+		if err != nil {
+			status = "failure"
+		}
+		s.metrics.walSnapshotUploadsTotal.WithLabelValues(status).Inc()
+	}()
+
+	files, err := walFiles(s.dir)
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		if err := s.uploadFile(ctx, file); err != nil {
+			return fmt.Errorf("failed to upload WAL file %s: %w", file, err)
+		}
+	}
+
+	manifest, err := json.Marshal(snapshotManifest{Files: files})
+	if err != nil {
+		return err
+	}
+	if err := s.objectClient.PutObject(ctx, s.key+snapshotManifestName, strings.NewReader(string(manifest))); err != nil {
+		return fmt.Errorf("failed to upload WAL snapshot manifest: %w", err)
+	}
+
+	keep := make(map[string]struct{}, len(files)+1)
+	keep[s.key+snapshotManifestName] = struct{}{}
+	for _, file := range files {
+		keep[s.key+file] = struct{}{}
+	}
+	objects, _, err := s.objectClient.List(ctx, s.key, "")
+	if err != nil {
+		return err
+	}
+	for _, object := range objects {
+		if _, ok := keep[object.Key]; ok {
+			continue
+		}
+		if err := s.objectClient.DeleteObject(ctx, object.Key); err != nil && !s.objectClient.IsObjectNotFoundErr(err) {
+			return err
+		}
+	}
+
+	level.Info(util_log.Logger).Log("msg", "uploaded WAL snapshot", "files", len(files), "key", s.key)
+	return nil
+}
+
+func (s *walSnapshotter) uploadFile(ctx context.Context, file string) error {
+	f, err := os.Open(filepath.Join(s.dir, filepath.FromSlash(file)))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	// The last segment may still be written to, only its current content is uploaded.
+	if err := s.objectClient.PutObject(ctx, s.key+file, io.NewSectionReader(f, 0, info.Size())); err != nil {
+		return err
+	}
+	s.metrics.walSnapshotUploadedBytesTotal.Add(float64(info.Size()))
+	return nil
+}
+
+// restore downloads the snapshot to the WAL directory if the WAL directory is empty, or deletes it otherwise,
+// as the local WAL is at least as recent and a later restore would replay stale data.
+// It returns false if there was no snapshot to restore.
+func (s *walSnapshotter) restore(ctx context.Context) (bool, error) {
+	restoreDir := filepath.Join(s.dir, snapshotRestoreDir)
+	if err := os.RemoveAll(restoreDir); err != nil {
+		return false, err
+	}
+
+	files, err := walFiles(s.dir)
+	if err != nil {
+		return false, err
+	}
+	if len(files) > 0 {
+		level.Info(util_log.Logger).Log("msg", "WAL directory is not empty, deleting WAL snapshot instead of restoring it", "dir", s.dir)
+		if err := s.delete(ctx); err != nil {
+			return false, fmt.Errorf("failed to delete WAL snapshot: %w", err)
+		}
+		return false, nil
+	}
+
+	reader, _, err := s.objectClient.GetObject(ctx, s.key+snapshotManifestName)
+	if err != nil {
+		if s.objectClient.IsObjectNotFoundErr(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	var manifest snapshotManifest
+	err = json.NewDecoder(reader).Decode(&manifest)
+	reader.Close()
+	if err != nil {
+		return false, fmt.Errorf("failed to decode WAL snapshot manifest: %w", err)
+	}
+
+	for _, file := range manifest.Files {
+		if !filepath.IsLocal(filepath.FromSlash(file)) {
+			return false, fmt.Errorf("invalid file %q in WAL snapshot manifest", file)
+		}
+		if err := s.downloadFile(ctx, file, restoreDir); err != nil {
+			return false, fmt.Errorf("failed to download WAL file %s: %w", file, err)
+		}
+	}
+
+	entries, err := os.ReadDir(restoreDir)
+	if err != nil && !os.IsNotExist(err) {
+		return false, err
+	}
+	for _, entry := range entries {
+		if err := os.Rename(filepath.Join(restoreDir, entry.Name()), filepath.Join(s.dir, entry.Name())); err != nil {
+			return false, err
+		}
+	}
+	if err := os.RemoveAll(restoreDir); err != nil {
+		return false, err
+	}
+
+	level.Info(util_log.Logger).Log("msg", "restored WAL snapshot", "files", len(manifest.Files), "key", s.key)
+	s.metrics.walSnapshotRestoresTotal.Inc()
+	return true, nil
+}
+
+func (s *walSnapshotter) downloadFile(ctx context.Context, file, dir string) error {
+	reader, _, err := s.objectClient.GetObject(ctx, s.key+file)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	filename := filepath.Join(dir, filepath.FromSlash(file))
+	if err := os.MkdirAll(filepath.Dir(filename), os.ModePerm); err != nil {
+		return err
+	}
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, reader); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// delete deletes the snapshot, starting with its manifest so that a partially deleted snapshot is never restored.
+func (s *walSnapshotter) delete(ctx context.Context) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if err := s.objectClient.DeleteObject(ctx, s.key+snapshotManifestName); err != nil && !s.objectClient.IsObjectNotFoundErr(err) {
+		return err
+	}
+	objects, _, err := s.objectClient.List(ctx, s.key, "")
+	if err != nil {
+		return err
+	}
+	for _, object := range objects {
+		if err := s.objectClient.DeleteObject(ctx, object.Key); err != nil && !s.objectClient.IsObjectNotFoundErr(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// walFiles returns the files of the last checkpoint, the WAL segments and the encryption keys of the WAL directory,
+// relative to it.
+func walFiles(dir string) ([]string, error) {
+	var files []string
+
+	checkpointDir, _, err := lastCheckpoint(dir)
+	if err != nil {
+		return nil, err
+	}
+	if checkpointDir != "" {
+		entries, err := os.ReadDir(checkpointDir)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				files = append(files, path.Join(filepath.Base(checkpointDir), entry.Name()))
+			}
+		}
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		// The data keys are needed to replay an encrypted WAL.
+		if _, err := strconv.Atoi(entry.Name()); err == nil || entry.Name() == walKeysFileName {
+			files = append(files, entry.Name())
+		}
+	}
+	return files, nil
+}
@@ -0,0 +1,231 @@
+package ingester
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/grafana/dskit/services"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/pkg/distributor/writefailures"
+	"github.com/grafana/loki/pkg/ingester/client"
+	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/runtime"
+	"github.com/grafana/loki/pkg/storage/chunk"
+	"github.com/grafana/loki/pkg/storage/chunk/client/local"
+	"github.com/grafana/loki/pkg/validation"
+)
+
+func TestIngesterWALSnapshot(t *testing.T) {
+	objectClient, err := local.NewFSObjectClient(local.FSConfig{Directory: t.TempDir()})
+	require.NoError(t, err)
+
+	newConfig := func(walDir string) Config {
+		cfg := defaultIngesterTestConfigWithWAL(t, walDir)
+		// no checkpoint is written, the snapshot is made of WAL segments
+		cfg.WAL.CheckpointDuration = time.Hour
+		cfg.WAL.Snapshot = SnapshotConfig{
+			Enabled:      true,
+			KeyPrefix:    "wal-snapshots/",
+			OnShutdown:   true,
+			ObjectClient: objectClient,
+		}
+		return cfg
+	}
+
+	limits, err := validation.NewOverrides(defaultLimitsTestConfig(), nil)
+	require.NoError(t, err)
+	store := &mockStore{chunks: map[string][]chunk.Chunk{}}
+
+	i, err := New(newConfig(t.TempDir()), client.Config{}, store, limits, runtime.DefaultTenantConfigs(), nil, writefailures.Cfg{})
+	require.NoError(t, err)
+	require.Nil(t, services.StartAndAwaitRunning(context.Background(), i))
+	defer services.StopAndAwaitTerminated(context.Background(), i) //nolint:errcheck
+
+	req := logproto.PushRequest{
+		Streams: []logproto.Stream{
+			{Labels: `{foo="bar",bar="baz1"}`},
+			{Labels: `{foo="bar",bar="baz2"}`},
+		},
+	}
+	start := time.Now()
+	steps := 10
+	end := start.Add(time.Second * time.Duration(steps))
+	for i := 0; i < steps; i++ {
+		for j := range req.Streams {
+			req.Streams[j].Entries = append(req.Streams[j].Entries, logproto.Entry{
+				Timestamp: start.Add(time.Duration(i) * time.Second),
+				Line:      fmt.Sprintf("line %d", i),
+			})
+		}
+	}
+
+	ctx := user.InjectOrgID(context.Background(), "test")
+	_, err = i.Push(ctx, &req)
+	require.NoError(t, err)
+
+	// the snapshot is uploaded on shutdown
+	require.Nil(t, services.StopAndAwaitTerminated(context.Background(), i))
+	_, _, err = objectClient.GetObject(context.Background(), "wal-snapshots/localhost/"+snapshotManifestName)
+	require.NoError(t, err)
+
+	// an ingester with the same ID and an empty WAL directory restores the snapshot
+	i, err = New(newConfig(t.TempDir()), client.Config{}, store, limits, runtime.DefaultTenantConfigs(), nil, writefailures.Cfg{})
+	require.NoError(t, err)
+	require.Nil(t, services.StartAndAwaitRunning(context.Background(), i))
+	defer services.StopAndAwaitTerminated(context.Background(), i) //nolint:errcheck
+
+	ensureIngesterData(ctx, t, start, end, i)
+
+	// the restored snapshot is deleted once replayed
+	objects, _, err := objectClient.List(context.Background(), "wal-snapshots/", "")
+	require.NoError(t, err)
+	require.Empty(t, objects)
+}
+
+func TestIngesterWALSnapshot_LocalWAL(t *testing.T) {
+	objectClient, err := local.NewFSObjectClient(local.FSConfig{Directory: t.TempDir()})
+	require.NoError(t, err)
+
+	walDir := t.TempDir()
+	cfg := defaultIngesterTestConfigWithWAL(t, walDir)
+	cfg.WAL.CheckpointDuration = time.Hour
+	cfg.WAL.Snapshot = SnapshotConfig{
+		Enabled:      true,
+		KeyPrefix:    "wal-snapshots/",
+		OnShutdown:   true,
+		ObjectClient: objectClient,
+	}
+
+	limits, err := validation.NewOverrides(defaultLimitsTestConfig(), nil)
+	require.NoError(t, err)
+	store := &mockStore{chunks: map[string][]chunk.Chunk{}}
+
+	i, err := New(cfg, client.Config{}, store, limits, runtime.DefaultTenantConfigs(), nil, writefailures.Cfg{})
+	require.NoError(t, err)
+	require.Nil(t, services.StartAndAwaitRunning(context.Background(), i))
+	defer services.StopAndAwaitTerminated(context.Background(), i) //nolint:errcheck
+
+	start := time.Now()
+	req := logproto.PushRequest{
+		Streams: []logproto.Stream{
+			{Labels: `{foo="bar",bar="baz1"}`, Entries: []logproto.Entry{{Timestamp: start, Line: "line 0"}}},
+			{Labels: `{foo="bar",bar="baz2"}`, Entries: []logproto.Entry{{Timestamp: start, Line: "line 0"}}},
+		},
+	}
+	ctx := user.InjectOrgID(context.Background(), "test")
+	_, err = i.Push(ctx, &req)
+	require.NoError(t, err)
+
+	// the snapshot is uploaded on shutdown
+	require.Nil(t, services.StopAndAwaitTerminated(context.Background(), i))
+	objects, _, err := objectClient.List(context.Background(), "wal-snapshots/", "")
+	require.NoError(t, err)
+	require.NotEmpty(t, objects)
+
+	// the ingester restarting with its WAL directory replays it and deletes the snapshot,
+	// so that it can't be restored after later writes to the WAL
+	i, err = New(cfg, client.Config{}, store, limits, runtime.DefaultTenantConfigs(), nil, writefailures.Cfg{})
+	require.NoError(t, err)
+	require.Nil(t, services.StartAndAwaitRunning(context.Background(), i))
+	defer services.StopAndAwaitTerminated(context.Background(), i) //nolint:errcheck
+
+	ensureIngesterData(ctx, t, start, start.Add(time.Second), i)
+
+	objects, _, err = objectClient.List(context.Background(), "wal-snapshots/", "")
+	require.NoError(t, err)
+	require.Empty(t, objects)
+}
+
+func TestWALSnapshotter(t *testing.T) {
+	objectClient, err := local.NewFSObjectClient(local.FSConfig{Directory: t.TempDir()})
+	require.NoError(t, err)
+	cfg := SnapshotConfig{Enabled: true, KeyPrefix: "snapshots/", ObjectClient: objectClient}
+	metrics := newIngesterMetrics(prometheus.NewRegistry())
+
+	writeFiles := func(dir string, files map[string]string) {
+		for name, content := range files {
+			require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), os.ModePerm))
+			require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+		}
+	}
+	keys := func() []string {
+		objects, _, err := objectClient.List(context.Background(), "snapshots/", "")
+		require.NoError(t, err)
+		var res []string
+		for _, object := range objects {
+			res = append(res, object.Key)
+		}
+		return res
+	}
+
+	dir := t.TempDir()
+	writeFiles(dir, map[string]string{
+		"checkpoint.000001/00000000": "old checkpoint",
+		"00000001":                   "segment 1",
+		"00000002":                   "segment 2",
+	})
+	snapshotter, err := newWALSnapshotter(dir, cfg, "ingester-1", metrics)
+	require.NoError(t, err)
+	require.NoError(t, snapshotter.upload(context.Background()))
+
+	// a new checkpoint replaces the old one and the segments it covers
+	require.NoError(t, os.RemoveAll(filepath.Join(dir, "checkpoint.000001")))
+	require.NoError(t, os.Remove(filepath.Join(dir, "00000001")))
+	require.NoError(t, os.Remove(filepath.Join(dir, "00000002")))
+	writeFiles(dir, map[string]string{
+		"checkpoint.000002/00000000": "new checkpoint",
+		"00000003":                   "segment 3",
+		"checkpoint.000003.tmp/foo":  "in-progress checkpoint",
+	})
+	require.NoError(t, snapshotter.upload(context.Background()))
+	require.ElementsMatch(t, []string{
+		"snapshots/ingester-1/checkpoint.000002/00000000",
+		"snapshots/ingester-1/00000003",
+		"snapshots/ingester-1/" + snapshotManifestName,
+	}, keys())
+
+	// a different ingester ID has no snapshot
+	other, err := newWALSnapshotter(t.TempDir(), cfg, "ingester-2", metrics)
+	require.NoError(t, err)
+	restored, err := other.restore(context.Background())
+	require.NoError(t, err)
+	require.False(t, restored)
+
+	restoreDir := t.TempDir()
+	restorer, err := newWALSnapshotter(restoreDir, cfg, "ingester-1", metrics)
+	require.NoError(t, err)
+	restored, err = restorer.restore(context.Background())
+	require.NoError(t, err)
+	require.True(t, restored)
+	for name, content := range map[string]string{
+		"checkpoint.000002/00000000": "new checkpoint",
+		"00000003":                   "segment 3",
+	} {
+		b, err := os.ReadFile(filepath.Join(restoreDir, name))
+		require.NoError(t, err)
+		require.Equal(t, content, string(b))
+	}
+	_, err = os.Stat(filepath.Join(restoreDir, snapshotRestoreDir))
+	require.True(t, os.IsNotExist(err))
+
+	require.NoError(t, restorer.delete(context.Background()))
+	require.Empty(t, keys())
+
+	// a WAL directory which isn't empty is not overwritten, and the snapshot is deleted instead
+	require.NoError(t, snapshotter.upload(context.Background()))
+	require.NotEmpty(t, keys())
+	restored, err = snapshotter.restore(context.Background())
+	require.NoError(t, err)
+	require.False(t, restored)
+	require.Empty(t, keys())
+	b, err := os.ReadFile(filepath.Join(dir, "00000003"))
+	require.NoError(t, err)
+	require.Equal(t, "segment 3", string(b))
+}
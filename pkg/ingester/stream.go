@@ -58,6 +58,9 @@ type stream struct {
 	// of accepted writes and for chunk synchronization.
 	highestTs time.Time
 
+	// tracks how far out of order the entries pushed to the stream are.
+	outOfOrderStats outOfOrderStats
+
 	metrics *ingesterMetrics
 
 	tailers   map[uint32]*tailer
@@ -336,12 +339,16 @@ func (s *stream) storeEntries(ctx context.Context, entries []logproto.Entry) (in
 			invalid = append(invalid, entryWithError{&entries[i], err})
 			if chunkenc.IsOutOfOrderErr(err) {
 				s.writeFailures.Log(s.tenant, err)
+				s.outOfOrderStats.rejected(len(entries[i].Line))
 				outOfOrderSamples++
 				outOfOrderBytes += len(entries[i].Line)
 			}
 			continue
 		}
 
+		if entries[i].Timestamp.Before(s.highestTs) {
+			s.outOfOrderStats.accepted(s.highestTs.Sub(entries[i].Timestamp), len(entries[i].Line))
+		}
 		s.entryCt++
 		s.lastLine.ts = entries[i].Timestamp
 		s.lastLine.content = entries[i].Line
@@ -398,6 +405,7 @@ func (s *stream) validateEntries(entries []logproto.Entry, isReplay, rateLimitWh
 		if !isReplay && s.unorderedWrites && !highestTs.IsZero() && cutoff.After(entries[i].Timestamp) {
 			failedEntriesWithError = append(failedEntriesWithError, entryWithError{&entries[i], chunkenc.ErrTooFarBehind(cutoff)})
 			s.writeFailures.Log(s.tenant, fmt.Errorf("%w for stream %s", failedEntriesWithError[len(failedEntriesWithError)-1].e, s.labels))
+			s.outOfOrderStats.rejected(lineBytes)
 			outOfOrderSamples++
 			outOfOrderBytes += lineBytes
 			continue
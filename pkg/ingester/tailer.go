@@ -152,20 +152,26 @@ func (t *tailer) processStream(stream logproto.Stream, lbs labels.Labels) []*log
 	sp := t.pipeline.ForStream(lbs)
 	for _, e := range stream.Entries {
 		newLine, parsedLbs, ok := sp.ProcessString(e.Timestamp.UnixNano(), e.Line)
-		if !ok {
-			continue
-		}
-		var stream *logproto.Stream
-		if stream, ok = streams[parsedLbs.Hash()]; !ok {
-			stream = &logproto.Stream{
-				Labels: parsedLbs.String(),
+		for ok {
+			var stream *logproto.Stream
+			var found bool
+			if stream, found = streams[parsedLbs.Hash()]; !found {
+				stream = &logproto.Stream{
+					Labels: parsedLbs.String(),
+				}
+				streams[parsedLbs.Hash()] = stream
+			}
+			stream.Entries = append(stream.Entries, logproto.Entry{
+				Timestamp: e.Timestamp,
+				Line:      newLine,
+			})
+
+			// the line could have been fanned out into several lines.
+			var next []byte
+			if next, parsedLbs, ok = sp.Next(); ok {
+				newLine = string(next)
 			}
-			streams[parsedLbs.Hash()] = stream
 		}
-		stream.Entries = append(stream.Entries, logproto.Entry{
-			Timestamp: e.Timestamp,
-			Line:      newLine,
-		})
 	}
 	streamsResult := make([]*logproto.Stream, 0, len(streams))
 	for _, stream := range streams {
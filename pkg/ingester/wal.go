@@ -29,13 +29,24 @@ type WALConfig struct {
 	CheckpointDuration  time.Duration    `yaml:"checkpoint_duration"`
 	FlushOnShutdown     bool             `yaml:"flush_on_shutdown"`
 	ReplayMemoryCeiling flagext.ByteSize `yaml:"replay_memory_ceiling"`
+	Snapshot            SnapshotConfig   `yaml:"snapshot"`
+	Encryption          EncryptionConfig `yaml:"encryption"`
 }
 
 func (cfg *WALConfig) Validate() error {
 	if cfg.Enabled && cfg.CheckpointDuration < 1 {
 		return errors.Errorf("invalid checkpoint duration: %v", cfg.CheckpointDuration)
 	}
-	return nil
+	if cfg.Snapshot.Enabled && !cfg.Enabled {
+		return errors.New("WAL snapshots require the WAL to be enabled")
+	}
+	if cfg.Encryption.Enabled && !cfg.Enabled {
+		return errors.New("WAL encryption requires the WAL to be enabled")
+	}
+	if err := cfg.Snapshot.Validate(); err != nil {
+		return err
+	}
+	return cfg.Encryption.Validate()
 }
 
 // RegisterFlags adds the flags required to config this to the given FlagSet
@@ -48,6 +59,9 @@ func (cfg *WALConfig) RegisterFlags(f *flag.FlagSet) {
 	// Need to set default here
 	cfg.ReplayMemoryCeiling = flagext.ByteSize(defaultCeiling)
 	f.Var(&cfg.ReplayMemoryCeiling, "ingester.wal-replay-memory-ceiling", "Maximum memory size the WAL may use during replay. After hitting this, it will flush data to storage before continuing. A unit suffix (KB, MB, GB) may be applied.")
+
+	cfg.Snapshot.RegisterFlags(f)
+	cfg.Encryption.RegisterFlags(f)
 }
 
 // WAL interface allows us to have a no-op WAL when the WAL is disabled.
@@ -70,13 +84,15 @@ type walWrapper struct {
 	wal        *wlog.WL
 	metrics    *ingesterMetrics
 	seriesIter SeriesIter
+	// encrypts the records, nil if the WAL encryption is disabled.
+	encryptor *walEncryptor
 
 	wait sync.WaitGroup
 	quit chan struct{}
 }
 
 // newWAL creates a WAL object. If the WAL is disabled, then the returned WAL is a no-op WAL.
-func newWAL(cfg WALConfig, registerer prometheus.Registerer, metrics *ingesterMetrics, seriesIter SeriesIter) (WAL, error) {
+func newWAL(cfg WALConfig, registerer prometheus.Registerer, metrics *ingesterMetrics, seriesIter SeriesIter, encryptor *walEncryptor) (WAL, error) {
 	if !cfg.Enabled {
 		return noopWAL{}, nil
 	}
@@ -92,6 +108,7 @@ func newWAL(cfg WALConfig, registerer prometheus.Registerer, metrics *ingesterMe
 		wal:        tsdbWAL,
 		metrics:    metrics,
 		seriesIter: seriesIter,
+		encryptor:  encryptor,
 	}
 
 	return w, nil
@@ -118,25 +135,40 @@ func (w *walWrapper) Log(record *wal.Record) error {
 		// Always write series then entries.
 		if len(record.Series) > 0 {
 			*buf = record.EncodeSeries(*buf)
-			if err := w.wal.Log(*buf); err != nil {
+			if err := w.log(record.UserID, *buf); err != nil {
 				return err
 			}
-			w.metrics.walRecordsLogged.Inc()
-			w.metrics.walLoggedBytesTotal.Add(float64(len(*buf)))
 			*buf = (*buf)[:0]
 		}
 		if len(record.RefEntries) > 0 {
 			*buf = record.EncodeEntries(wal.CurrentEntriesRec, *buf)
-			if err := w.wal.Log(*buf); err != nil {
+			if err := w.log(record.UserID, *buf); err != nil {
 				return err
 			}
-			w.metrics.walRecordsLogged.Inc()
-			w.metrics.walLoggedBytesTotal.Add(float64(len(*buf)))
 		}
 		return nil
 	}
 }
 
+func (w *walWrapper) log(userID string, rec []byte) error {
+	if w.encryptor != nil {
+		buf := recordPool.GetBytes()
+		defer recordPool.PutBytes(buf)
+
+		var err error
+		if *buf, err = w.encryptor.encrypt(userID, rec, *buf); err != nil {
+			return err
+		}
+		rec = *buf
+	}
+	if err := w.wal.Log(rec); err != nil {
+		return err
+	}
+	w.metrics.walRecordsLogged.Inc()
+	w.metrics.walLoggedBytesTotal.Add(float64(len(rec)))
+	return nil
+}
+
 func (w *walWrapper) Stop() error {
 	close(w.quit)
 	w.wait.Wait()
@@ -149,6 +181,7 @@ func (w *walWrapper) checkpointWriter() *WALCheckpointWriter {
 	return &WALCheckpointWriter{
 		metrics:    w.metrics,
 		segmentWAL: w.wal,
+		encryptor:  w.encryptor,
 	}
 }
 
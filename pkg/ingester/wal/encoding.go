@@ -27,6 +27,9 @@ const (
 	WALRecordEntriesV2
 	// WALRecordEntriesV3 is the type for the WAL record for samples with structured metadata.
 	WALRecordEntriesV3
+	// EncryptedRecord is the type for an encrypted WAL or Checkpoint record, which wraps
+	// one of the other record types encrypted with the data key of its tenant.
+	EncryptedRecord
 )
 
 // The current type of Entries that this distribution writes.
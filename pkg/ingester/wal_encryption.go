@@ -0,0 +1,521 @@
+package ingester
+
+import (
+	"context"
+	"crypto/cipher"
+	"encoding/binary"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log/level"
+	"github.com/pkg/errors"
+
+	"github.com/grafana/loki/pkg/ingester/wal"
+	"github.com/grafana/loki/pkg/util/kms"
+	util_log "github.com/grafana/loki/pkg/util/log"
+)
+
+// walKeysFileName is the file of the WAL directory holding the wrapped data keys of the tenants.
+const walKeysFileName = "encryption-keys.json"
+
+// EncryptionConfig configures the encryption at rest of the WAL segments and checkpoints.
+type EncryptionConfig struct {
+	Enabled               bool          `yaml:"enabled"`
+	KMS                   kms.Config    `yaml:",inline"`
+	DataKeyRotationPeriod time.Duration `yaml:"data_key_rotation_period"`
+}
+
+// RegisterFlags adds the flags required to config this to the given FlagSet
+func (cfg *EncryptionConfig) RegisterFlags(f *flag.FlagSet) {
+	f.BoolVar(&cfg.Enabled, "ingester.wal-encryption-enabled", false, "Encrypt the WAL segments and checkpoints. Each record is encrypted with AES-256-GCM using a data key of its tenant, which is stored in the WAL directory wrapped by a master key of the key provider.")
+	cfg.KMS.RegisterFlagsWithPrefix("ingester.wal-encryption-", f)
+	f.DurationVar(&cfg.DataKeyRotationPeriod, "ingester.wal-encryption-data-key-rotation-period", 24*time.Hour, "Period after which a new data key is generated for a tenant. The data keys which aren't needed to replay the WAL anymore are deleted after the checkpoints. 0 to disable.")
+}
+
+func (cfg *EncryptionConfig) Validate() error {
+	if !cfg.Enabled {
+		return nil
+	}
+	if err := cfg.KMS.Validate(); err != nil {
+		return fmt.Errorf("invalid WAL encryption config: %w", err)
+	}
+	return nil
+}
+
+// storedDataKey is a data key as stored in the keys file of the WAL directory.
+type storedDataKey struct {
+	ID          uint64    `json:"id"`
+	MasterKeyID string    `json:"master_key_id"`
+	WrappedKey  []byte    `json:"wrapped_key"`
+	Created     time.Time `json:"created"`
+	// Retired is when the key was replaced by a new data key of the tenant.
+	Retired time.Time `json:"retired,omitempty"`
+}
+
+// deletedDataKey is a data key which was shredded or pruned, whose records are skipped on replay.
+type deletedDataKey struct {
+	ID      uint64    `json:"id"`
+	Deleted time.Time `json:"deleted"`
+}
+
+type walKeysFile struct {
+	// NextID is the ID of the next data key, IDs are never reused so that the records of a shredded
+	// tenant can't be mistaken for the records of a new key.
+	NextID  uint64                     `json:"next_id"`
+	Tenants map[string][]storedDataKey `json:"tenants"`
+	// Deleted are the keys which may still be needed by the records of the WAL directory, so that only their
+	// records are skipped on replay and the records of any other unknown key fail the replay.
+	Deleted []deletedDataKey `json:"deleted,omitempty"`
+}
+
+type dataKey struct {
+	storedDataKey
+	aead cipher.AEAD
+}
+
+// walEncryptor encrypts the records of the WAL and the checkpoints with per-tenant data keys, so that the
+// records of a tenant can be made unreadable by deleting its keys.
+type walEncryptor struct {
+	path           string
+	provider       kms.KeyProvider
+	rotationPeriod time.Duration
+	metrics        *ingesterMetrics
+
+	// updateMtx serializes the updates of the keys, which call the KMS and write the keys file without holding mtx.
+	updateMtx sync.Mutex
+
+	mtx     sync.RWMutex
+	nextID  uint64
+	keys    map[uint64]*dataKey
+	tenants map[string][]*dataKey // by creation order, the last key being the current one.
+	// deleted holds when the shredded and pruned keys were deleted, by ID.
+	deleted map[uint64]time.Time
+	// shredded holds when the keys of the tenants were last shredded, see shreddedAfter.
+	shredded map[string]time.Time
+}
+
+func newWALEncryptor(dir string, cfg EncryptionConfig, metrics *ingesterMetrics) (*walEncryptor, error) {
+	e := &walEncryptor{
+		path:           filepath.Join(dir, walKeysFileName),
+		provider:       kms.NewKeyProvider(cfg.KMS),
+		rotationPeriod: cfg.DataKeyRotationPeriod,
+		metrics:        metrics,
+		nextID:         1,
+		keys:           map[uint64]*dataKey{},
+		tenants:        map[string][]*dataKey{},
+		deleted:        map[uint64]time.Time{},
+		shredded:       map[string]time.Time{},
+	}
+
+	b, err := os.ReadFile(e.path)
+	if os.IsNotExist(err) {
+		return e, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var file walKeysFile
+	if err := json.Unmarshal(b, &file); err != nil {
+		return nil, fmt.Errorf("failed to decode WAL encryption keys: %w", err)
+	}
+	if file.NextID > e.nextID {
+		e.nextID = file.NextID
+	}
+	for _, k := range file.Deleted {
+		e.deleted[k.ID] = k.Deleted
+	}
+	for tenant, keys := range file.Tenants {
+		for _, stored := range keys {
+			key, err := e.provider.Unwrap(context.Background(), stored.MasterKeyID, stored.WrappedKey)
+			if err != nil {
+				return nil, fmt.Errorf("failed to unwrap WAL data key %d of tenant %s: %w", stored.ID, tenant, err)
+			}
+			aead, err := kms.NewAEAD(key)
+			if err != nil {
+				return nil, err
+			}
+			k := &dataKey{storedDataKey: stored, aead: aead}
+			e.keys[k.ID] = k
+			e.tenants[tenant] = append(e.tenants[tenant], k)
+		}
+	}
+	e.metrics.walEncryptionDataKeys.Set(float64(len(e.keys)))
+	return e, nil
+}
+
+// encrypt appends the encrypted record to dst: the record type, the tenant, the ID of its data key and the
+// nonce, which are authenticated, then the encrypted record.
+func (e *walEncryptor) encrypt(tenant string, rec, dst []byte) ([]byte, error) {
+	key, err := e.currentKey(tenant)
+	if err != nil {
+		return nil, err
+	}
+
+	dst = append(dst[:0], byte(wal.EncryptedRecord))
+	dst = binary.AppendUvarint(dst, uint64(len(tenant)))
+	dst = append(dst, tenant...)
+	dst = binary.AppendUvarint(dst, key.ID)
+	return kms.Seal(key.aead, dst, rec, dst)
+}
+
+// encryptedRecordOverhead returns the size added to the records of the tenant by the encryption.
+func encryptedRecordOverhead(tenant string) int {
+	// type, tenant length and key ID varints, nonce and GCM tag.
+	return 1 + binary.MaxVarintLen64*2 + len(tenant) + 12 + 16
+}
+
+// decrypt appends the decrypted record to dst. Records which aren't encrypted are returned as is.
+// It returns false if the data key of the record was shredded or pruned, and an error if the key is unknown,
+// like when the keys file is missing or out of date.
+func (e *walEncryptor) decrypt(rec, dst []byte) ([]byte, bool, error) {
+	if len(rec) == 0 || wal.RecordType(rec[0]) != wal.EncryptedRecord {
+		return rec, true, nil
+	}
+
+	header := rec[1:]
+	tenantLen, n := binary.Uvarint(header)
+	if n <= 0 || uint64(len(header)-n) < tenantLen {
+		return nil, false, errors.New("invalid encrypted WAL record header")
+	}
+	header = header[n+int(tenantLen):]
+	keyID, n := binary.Uvarint(header)
+	if n <= 0 {
+		return nil, false, errors.New("invalid encrypted WAL record header")
+	}
+	headerLen := len(rec) - len(header) + n
+
+	e.mtx.RLock()
+	key, ok := e.keys[keyID]
+	_, deleted := e.deleted[keyID]
+	e.mtx.RUnlock()
+	if deleted {
+		return nil, false, nil
+	}
+	if !ok {
+		return nil, false, fmt.Errorf("unknown WAL data key %d, the keys file %s is missing or out of date", keyID, e.path)
+	}
+	plain, err := kms.Open(key.aead, dst[:0], rec[headerLen:], rec[:headerLen])
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to decrypt WAL record with data key %d: %w", keyID, err)
+	}
+	return plain, true, nil
+}
+
+// currentKey returns the data key of the tenant, generating a new one if it has none or if it's due for rotation.
+func (e *walEncryptor) currentKey(tenant string) (*dataKey, error) {
+	e.mtx.RLock()
+	key := e.lastKey(tenant)
+	e.mtx.RUnlock()
+	if key != nil && !e.dueForRotation(key) {
+		return key, nil
+	}
+
+	e.updateMtx.Lock()
+	defer e.updateMtx.Unlock()
+	e.mtx.RLock()
+	key = e.lastKey(tenant)
+	e.mtx.RUnlock()
+	if key != nil && !e.dueForRotation(key) {
+		return key, nil
+	}
+	return e.rotate(tenant)
+}
+
+func (e *walEncryptor) lastKey(tenant string) *dataKey {
+	keys := e.tenants[tenant]
+	if len(keys) == 0 {
+		return nil
+	}
+	return keys[len(keys)-1]
+}
+
+func (e *walEncryptor) dueForRotation(key *dataKey) bool {
+	return e.rotationPeriod > 0 && time.Since(key.Created) > e.rotationPeriod
+}
+
+// rotate generates a new data key for the tenant and retires its previous one. The data keys wrapped
+// by a previous master key are wrapped again with the current one, so that previous master keys can be
+// removed once all the data keys were rotated. Requires updateMtx: as the keys only change while holding
+// it, they are read without mtx, which is only taken to apply the new keys so that records keep being
+// encrypted and decrypted while the KMS is called and the keys file is written.
+func (e *walEncryptor) rotate(tenant string) (*dataKey, error) {
+	ctx := context.Background()
+	raw, err := kms.NewDataKey()
+	if err != nil {
+		return nil, err
+	}
+	masterKeyID, wrapped, err := e.provider.Wrap(ctx, raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to wrap WAL data key: %w", err)
+	}
+	aead, err := kms.NewAEAD(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	key := &dataKey{
+		storedDataKey: storedDataKey{ID: e.nextID, MasterKeyID: masterKeyID, WrappedKey: wrapped, Created: now},
+		aead:          aead,
+	}
+	rewrapped := map[uint64]storedDataKey{}
+	for _, k := range e.keys {
+		if k.MasterKeyID == masterKeyID {
+			continue
+		}
+		raw, err := e.provider.Unwrap(ctx, k.MasterKeyID, k.WrappedKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unwrap WAL data key %d: %w", k.ID, err)
+		}
+		stored := k.storedDataKey
+		stored.MasterKeyID, stored.WrappedKey, err = e.provider.Wrap(ctx, raw)
+		if err != nil {
+			return nil, fmt.Errorf("failed to wrap WAL data key %d: %w", k.ID, err)
+		}
+		rewrapped[k.ID] = stored
+	}
+
+	// The keys are only updated once saved, as the records encrypted with a new key can't be replayed without it.
+	previous := e.lastKey(tenant)
+	tenants := make(map[string][]*dataKey, len(e.tenants)+1)
+	for t, keys := range e.tenants {
+		tenants[t] = keys
+	}
+	tenants[tenant] = append(append([]*dataKey{}, tenants[tenant]...), key)
+	if err := e.save(e.nextID+1, tenants, e.deleted, func(k *dataKey) storedDataKey {
+		stored := k.storedDataKey
+		if r, ok := rewrapped[k.ID]; ok {
+			stored = r
+		}
+		if k == previous {
+			stored.Retired = now
+		}
+		return stored
+	}); err != nil {
+		return nil, err
+	}
+
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	for id, stored := range rewrapped {
+		e.keys[id].MasterKeyID, e.keys[id].WrappedKey = stored.MasterKeyID, stored.WrappedKey
+	}
+	if previous != nil {
+		previous.Retired = now
+	}
+	e.nextID++
+	e.keys[key.ID] = key
+	e.tenants = tenants
+	e.metrics.walEncryptionDataKeys.Set(float64(len(e.keys)))
+	level.Info(util_log.Logger).Log("msg", "generated WAL data key", "tenant", tenant, "id", key.ID, "master_key_id", masterKeyID)
+	return key, nil
+}
+
+// shred deletes the data keys of the tenant, which makes its records in the WAL directory, checkpoints and
+// snapshots unreadable. The records of the tenant written after are encrypted with a new key, the streams
+// of the tenant in memory must be dropped first so that the next checkpoints don't write them again.
+func (e *walEncryptor) shred(tenant string) (int, error) {
+	e.updateMtx.Lock()
+	defer e.updateMtx.Unlock()
+
+	keys := e.tenants[tenant]
+	if len(keys) == 0 {
+		e.mtx.Lock()
+		e.shredded[tenant] = time.Now()
+		e.mtx.Unlock()
+		return 0, nil
+	}
+	tenants := make(map[string][]*dataKey, len(e.tenants))
+	for t, keys := range e.tenants {
+		if t != tenant {
+			tenants[t] = keys
+		}
+	}
+	now := time.Now()
+	deleted := make(map[uint64]time.Time, len(e.deleted)+len(keys))
+	for id, t := range e.deleted {
+		deleted[id] = t
+	}
+	for _, k := range keys {
+		deleted[k.ID] = now
+	}
+	if err := e.save(e.nextID, tenants, deleted, nil); err != nil {
+		return 0, err
+	}
+
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	e.shredded[tenant] = now
+	for _, k := range keys {
+		delete(e.keys, k.ID)
+	}
+	e.tenants = tenants
+	e.deleted = deleted
+	e.metrics.walEncryptionDataKeys.Set(float64(len(e.keys)))
+	level.Info(util_log.Logger).Log("msg", "shredded WAL data keys", "tenant", tenant, "keys", len(keys))
+	return len(keys), nil
+}
+
+// shreddedAfter tells whether the keys of the tenant were shredded after the given time.
+func (e *walEncryptor) shreddedAfter(tenant string, t time.Time) bool {
+	e.mtx.RLock()
+	defer e.mtx.RUnlock()
+	shredded, ok := e.shredded[tenant]
+	return ok && shredded.After(t)
+}
+
+// prune deletes the data keys retired before the given time, which no WAL segment or checkpoint needs anymore.
+// No checkpoint started before the given time is running anymore, so neither are the shreds before it needed,
+// nor the keys deleted before it, as no record of the WAL directory can be encrypted with them.
+func (e *walEncryptor) prune(before time.Time) error {
+	e.updateMtx.Lock()
+	defer e.updateMtx.Unlock()
+
+	e.mtx.Lock()
+	for t, shredded := range e.shredded {
+		if shredded.Before(before) {
+			delete(e.shredded, t)
+		}
+	}
+	e.mtx.Unlock()
+
+	tenants := make(map[string][]*dataKey, len(e.tenants))
+	var pruned []*dataKey
+	for t, keys := range e.tenants {
+		kept := make([]*dataKey, 0, len(keys))
+		for _, k := range keys {
+			if !k.Retired.IsZero() && k.Retired.Before(before) {
+				pruned = append(pruned, k)
+				continue
+			}
+			kept = append(kept, k)
+		}
+		tenants[t] = kept
+	}
+	now := time.Now()
+	deleted := make(map[uint64]time.Time, len(e.deleted)+len(pruned))
+	for id, t := range e.deleted {
+		if !t.Before(before) {
+			deleted[id] = t
+		}
+	}
+	if len(pruned) == 0 && len(deleted) == len(e.deleted) {
+		return nil
+	}
+	for _, k := range pruned {
+		deleted[k.ID] = now
+	}
+	if err := e.save(e.nextID, tenants, deleted, nil); err != nil {
+		return err
+	}
+
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	for _, k := range pruned {
+		delete(e.keys, k.ID)
+	}
+	e.tenants = tenants
+	e.deleted = deleted
+	e.metrics.walEncryptionDataKeys.Set(float64(len(e.keys)))
+	return nil
+}
+
+// save atomically writes the keys file. Requires updateMtx.
+func (e *walEncryptor) save(nextID uint64, tenants map[string][]*dataKey, deleted map[uint64]time.Time, stored func(*dataKey) storedDataKey) error {
+	file := walKeysFile{NextID: nextID, Tenants: make(map[string][]storedDataKey, len(tenants))}
+	for id, t := range deleted {
+		file.Deleted = append(file.Deleted, deletedDataKey{ID: id, Deleted: t})
+	}
+	sort.Slice(file.Deleted, func(i, j int) bool { return file.Deleted[i].ID < file.Deleted[j].ID })
+	for t, keys := range tenants {
+		if len(keys) == 0 {
+			continue
+		}
+		for _, k := range keys {
+			if stored != nil {
+				file.Tenants[t] = append(file.Tenants[t], stored(k))
+			} else {
+				file.Tenants[t] = append(file.Tenants[t], k.storedDataKey)
+			}
+		}
+	}
+	b, err := json.Marshal(file)
+	if err != nil {
+		return err
+	}
+
+	tmp := e.path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, e.path)
+}
+
+// decryptingWALReader decrypts the records of the WAL and checkpoints, skipping the records of the shredded and pruned keys.
+type decryptingWALReader struct {
+	WALReader
+	encryptor *walEncryptor
+	metrics   *ingesterMetrics
+
+	rec []byte
+	buf []byte
+	err error
+}
+
+func newDecryptingWALReader(reader WALReader, encryptor *walEncryptor, metrics *ingesterMetrics) *decryptingWALReader {
+	return &decryptingWALReader{WALReader: reader, encryptor: encryptor, metrics: metrics}
+}
+
+func (r *decryptingWALReader) Next() bool {
+	for r.WALReader.Next() {
+		r.rec, r.err = nil, nil
+		if r.WALReader.Err() != nil {
+			return true
+		}
+
+		rec, ok, err := r.encryptor.decrypt(r.WALReader.Record(), r.buf)
+		if err != nil {
+			r.err = err
+			return true
+		}
+		if !ok {
+			r.metrics.walShreddedRecordsSkipped.Inc()
+			continue
+		}
+		if cap(rec) > cap(r.buf) {
+			r.buf = rec
+		}
+		r.rec = rec
+		return true
+	}
+	return false
+}
+
+func (r *decryptingWALReader) Err() error {
+	if r.err != nil {
+		return r.err
+	}
+	return r.WALReader.Err()
+}
+
+func (r *decryptingWALReader) Record() []byte {
+	return r.rec
+}
@@ -0,0 +1,276 @@
+package ingester
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/grafana/dskit/services"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+
+	"github.com/grafana/loki/pkg/distributor/writefailures"
+	"github.com/grafana/loki/pkg/ingester/client"
+	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/runtime"
+	"github.com/grafana/loki/pkg/storage/chunk"
+	"github.com/grafana/loki/pkg/util/kms"
+	"github.com/grafana/loki/pkg/validation"
+)
+
+func newMasterKey(t *testing.T) string {
+	key := make([]byte, 32)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+	return base64.StdEncoding.EncodeToString(key)
+}
+
+func writeMasterKeys(t *testing.T, path string, file kms.MasterKeysFile) {
+	b, err := yaml.Marshal(file)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, b, 0o600))
+}
+
+func TestIngesterWALEncryption(t *testing.T) {
+	walDir := t.TempDir()
+	keyFile := filepath.Join(t.TempDir(), "keys.yaml")
+	writeMasterKeys(t, keyFile, kms.MasterKeysFile{Current: "key-1", Keys: map[string]string{"key-1": newMasterKey(t)}})
+
+	ingesterConfig := defaultIngesterTestConfigWithWAL(t, walDir)
+	ingesterConfig.WAL.Encryption = EncryptionConfig{
+		Enabled:               true,
+		KMS:                   kms.Config{Provider: kms.ProviderFile, KeyFile: keyFile},
+		DataKeyRotationPeriod: time.Hour,
+	}
+	require.NoError(t, ingesterConfig.WAL.Validate())
+
+	limits, err := validation.NewOverrides(defaultLimitsTestConfig(), nil)
+	require.NoError(t, err)
+	store := &mockStore{chunks: map[string][]chunk.Chunk{}}
+
+	i, err := New(ingesterConfig, client.Config{}, store, limits, runtime.DefaultTenantConfigs(), nil, writefailures.Cfg{})
+	require.NoError(t, err)
+	require.Nil(t, services.StartAndAwaitRunning(context.Background(), i))
+	defer services.StopAndAwaitTerminated(context.Background(), i) //nolint:errcheck
+
+	req := logproto.PushRequest{
+		Streams: []logproto.Stream{
+			{Labels: `{foo="bar",bar="baz1"}`},
+			{Labels: `{foo="bar",bar="baz2"}`},
+		},
+	}
+	start := time.Now()
+	steps := 10
+	end := start.Add(time.Second * time.Duration(steps))
+	for i := 0; i < steps; i++ {
+		for j := range req.Streams {
+			req.Streams[j].Entries = append(req.Streams[j].Entries, logproto.Entry{
+				Timestamp: start.Add(time.Duration(i) * time.Second),
+				Line:      fmt.Sprintf("secret line %d", i),
+			})
+		}
+	}
+
+	ctx := user.InjectOrgID(context.Background(), "test")
+	_, err = i.Push(ctx, &req)
+	require.NoError(t, err)
+	require.Nil(t, services.StopAndAwaitTerminated(context.Background(), i))
+
+	// nothing is written in clear
+	segment, err := os.ReadFile(filepath.Join(walDir, "00000000"))
+	require.NoError(t, err)
+	require.NotEmpty(t, segment)
+	require.False(t, bytes.Contains(segment, []byte("secret line")))
+	require.False(t, bytes.Contains(segment, []byte("baz1")))
+
+	// replay from the WAL segments
+	i, err = New(ingesterConfig, client.Config{}, store, limits, runtime.DefaultTenantConfigs(), nil, writefailures.Cfg{})
+	require.NoError(t, err)
+	require.Nil(t, services.StartAndAwaitRunning(context.Background(), i))
+	defer services.StopAndAwaitTerminated(context.Background(), i) //nolint:errcheck
+	ensureIngesterData(ctx, t, start, end, i)
+
+	expectCheckpoint(t, walDir, true, ingesterConfig.WAL.CheckpointDuration*5)
+	require.Nil(t, services.StopAndAwaitTerminated(context.Background(), i))
+
+	// replay from the checkpoint and the WAL segments
+	i, err = New(ingesterConfig, client.Config{}, store, limits, runtime.DefaultTenantConfigs(), nil, writefailures.Cfg{})
+	require.NoError(t, err)
+	require.Nil(t, services.StartAndAwaitRunning(context.Background(), i))
+	defer services.StopAndAwaitTerminated(context.Background(), i) //nolint:errcheck
+	ensureIngesterData(ctx, t, start, end, i)
+}
+
+func TestIngesterWALEncryption_Shred(t *testing.T) {
+	walDir := t.TempDir()
+	keyFile := filepath.Join(t.TempDir(), "keys.yaml")
+	writeMasterKeys(t, keyFile, kms.MasterKeysFile{Current: "key-1", Keys: map[string]string{"key-1": newMasterKey(t)}})
+
+	ingesterConfig := defaultIngesterTestConfigWithWAL(t, walDir)
+	ingesterConfig.WAL.Encryption = EncryptionConfig{
+		Enabled: true,
+		KMS:     kms.Config{Provider: kms.ProviderFile, KeyFile: keyFile},
+	}
+
+	limits, err := validation.NewOverrides(defaultLimitsTestConfig(), nil)
+	require.NoError(t, err)
+	store := &mockStore{chunks: map[string][]chunk.Chunk{}}
+
+	i, err := New(ingesterConfig, client.Config{}, store, limits, runtime.DefaultTenantConfigs(), nil, writefailures.Cfg{})
+	require.NoError(t, err)
+	require.Nil(t, services.StartAndAwaitRunning(context.Background(), i))
+	defer services.StopAndAwaitTerminated(context.Background(), i) //nolint:errcheck
+
+	start := time.Now()
+	push := func(labels, line string) {
+		stream := logproto.Stream{Labels: labels}
+		for j := 0; j < 10; j++ {
+			stream.Entries = append(stream.Entries, logproto.Entry{Timestamp: start.Add(time.Duration(j) * time.Second), Line: line})
+		}
+		_, err := i.Push(user.InjectOrgID(context.Background(), "test"), &logproto.PushRequest{Streams: []logproto.Stream{stream}})
+		require.NoError(t, err)
+	}
+	query := func() map[string]int {
+		result := mockQuerierServer{ctx: user.InjectOrgID(context.Background(), "test")}
+		require.NoError(t, i.Query(&logproto.QueryRequest{
+			Selector: `{foo="bar"}`,
+			Limit:    100,
+			Start:    start,
+			End:      start.Add(time.Minute),
+		}, &result))
+		entries := map[string]int{}
+		for _, resp := range result.resps {
+			for _, stream := range resp.Streams {
+				entries[stream.Labels] += len(stream.Entries)
+			}
+		}
+		return entries
+	}
+
+	push(`{foo="bar",bar="baz1"}`, "secret line")
+	expectCheckpoint(t, walDir, true, ingesterConfig.WAL.CheckpointDuration*5)
+
+	// the streams of the tenant are dropped along with its keys
+	w := httptest.NewRecorder()
+	i.ShredWALKeysHandler(w, httptest.NewRequest(http.MethodPost, "/ingester/wal/shred", nil).WithContext(user.InjectOrgID(context.Background(), "test")))
+	require.Equal(t, http.StatusNoContent, w.Code)
+	require.Empty(t, query())
+
+	push(`{foo="bar",bar="baz2"}`, "new line")
+	_, shredCheckpoint, err := lastCheckpoint(walDir)
+	require.NoError(t, err)
+
+	// wait for a checkpoint started after the shred
+	require.Eventually(t, func() bool {
+		_, idx, err := lastCheckpoint(walDir)
+		require.NoError(t, err)
+		return idx > shredCheckpoint+1
+	}, ingesterConfig.WAL.CheckpointDuration*10, ingesterConfig.WAL.CheckpointDuration/10)
+	require.Nil(t, services.StopAndAwaitTerminated(context.Background(), i))
+
+	// only the data pushed after the shred is replayed
+	i, err = New(ingesterConfig, client.Config{}, store, limits, runtime.DefaultTenantConfigs(), nil, writefailures.Cfg{})
+	require.NoError(t, err)
+	require.Nil(t, services.StartAndAwaitRunning(context.Background(), i))
+	defer services.StopAndAwaitTerminated(context.Background(), i) //nolint:errcheck
+	require.Equal(t, map[string]int{`{bar="baz2", foo="bar"}`: 10}, query())
+}
+
+func TestWALEncryptor(t *testing.T) {
+	dir := t.TempDir()
+	keyFile := filepath.Join(t.TempDir(), "keys.yaml")
+	key1, key2 := newMasterKey(t), newMasterKey(t)
+	writeMasterKeys(t, keyFile, kms.MasterKeysFile{Current: "key-1", Keys: map[string]string{"key-1": key1}})
+	cfg := EncryptionConfig{Enabled: true, KMS: kms.Config{Provider: kms.ProviderFile, KeyFile: keyFile}}
+	metrics := newIngesterMetrics(prometheus.NewRegistry())
+
+	e, err := newWALEncryptor(dir, cfg, metrics)
+	require.NoError(t, err)
+
+	rec1, err := e.encrypt("tenant-1", []byte("record of tenant 1"), nil)
+	require.NoError(t, err)
+	rec2, err := e.encrypt("tenant-2", []byte("record of tenant 2"), nil)
+	require.NoError(t, err)
+
+	// records which aren't encrypted are read as is
+	plain, ok, err := e.decrypt([]byte{1, 2, 3}, nil)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, []byte{1, 2, 3}, plain)
+
+	// a corrupted record fails to decrypt
+	corrupted := append([]byte{}, rec1...)
+	corrupted[len(corrupted)-1] ^= 0xff
+	_, _, err = e.decrypt(corrupted, nil)
+	require.Error(t, err)
+
+	// rotate the master key and the data keys
+	writeMasterKeys(t, keyFile, kms.MasterKeysFile{Current: "key-2", Keys: map[string]string{"key-1": key1, "key-2": key2}})
+	e.rotationPeriod = time.Nanosecond
+	rec3, err := e.encrypt("tenant-1", []byte("new record of tenant 1"), nil)
+	require.NoError(t, err)
+	for _, k := range e.keys {
+		require.Equal(t, "key-2", k.MasterKeyID)
+	}
+
+	// the previous master key isn't needed anymore
+	writeMasterKeys(t, keyFile, kms.MasterKeysFile{Current: "key-2", Keys: map[string]string{"key-2": key2}})
+	e, err = newWALEncryptor(dir, cfg, metrics)
+	require.NoError(t, err)
+	for rec, expected := range map[*[]byte]string{
+		&rec1: "record of tenant 1",
+		&rec2: "record of tenant 2",
+		&rec3: "new record of tenant 1",
+	} {
+		plain, ok, err := e.decrypt(*rec, nil)
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, expected, string(plain))
+	}
+
+	// the retired data key of tenant 1 is pruned
+	require.NoError(t, e.prune(time.Now()))
+	_, ok, err = e.decrypt(rec1, nil)
+	require.NoError(t, err)
+	require.False(t, ok)
+	_, ok, err = e.decrypt(rec3, nil)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	// the records of a shredded tenant can't be decrypted anymore, even by a new data key
+	shredded, err := e.shred("tenant-2")
+	require.NoError(t, err)
+	require.Equal(t, 1, shredded)
+	_, err = e.encrypt("tenant-2", []byte("record after shredding"), nil)
+	require.NoError(t, err)
+
+	e, err = newWALEncryptor(dir, cfg, metrics)
+	require.NoError(t, err)
+	_, ok, err = e.decrypt(rec2, nil)
+	require.NoError(t, err)
+	require.False(t, ok)
+	_, ok, err = e.decrypt(rec1, nil)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	// the deleted keys are forgotten once no record can use them anymore
+	require.NoError(t, e.prune(time.Now()))
+	require.Empty(t, e.deleted)
+
+	// the records of a key missing from the keys file fail to decrypt instead of being skipped
+	require.NoError(t, os.Remove(filepath.Join(dir, walKeysFileName)))
+	e, err = newWALEncryptor(dir, cfg, metrics)
+	require.NoError(t, err)
+	_, _, err = e.decrypt(rec3, nil)
+	require.ErrorContains(t, err, "unknown WAL data key")
+}
@@ -3,6 +3,7 @@ package client
 import (
 	"context"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"io"
 	"log"
@@ -34,6 +35,7 @@ const (
 	labelValuesPath   = "/loki/api/v1/label/%s/values"
 	seriesPath        = "/loki/api/v1/series"
 	tailPath          = "/loki/api/v1/tail"
+	exportPath        = "/loki/api/v1/export"
 	statsPath         = "/loki/api/v1/index/stats"
 	volumePath        = "/loki/api/v1/index/volume"
 	volumeRangePath   = "/loki/api/v1/index/volume_range"
@@ -50,6 +52,7 @@ type Client interface {
 	ListLabelValues(name string, quiet bool, start, end time.Time) (*loghttp.LabelResponse, error)
 	Series(matchers []string, start, end time.Time, quiet bool) (*loghttp.SeriesResponse, error)
 	LiveTailQueryConn(queryStr string, delayFor time.Duration, limit int, start time.Time, quiet bool) (*websocket.Conn, error)
+	Export(queryStr string, limit int, start, end time.Time, direction logproto.Direction, cursor string, quiet bool, f func(loghttp.Stream) error) (string, error)
 	GetOrgID() string
 	GetStats(queryStr string, start, end time.Time, quiet bool) (*logproto.IndexStatsResponse, error)
 	GetVolume(query *volume.Query) (*loghttp.QueryResponse, error)
@@ -76,6 +79,7 @@ type DefaultClient struct {
 	Retries         int
 	QueryTags       string
 	AuthHeader      string
+	AllowPartial    bool
 	ProxyURL        string
 	BackoffConfig   BackoffConfig
 }
@@ -169,6 +173,67 @@ func (c *DefaultClient) LiveTailQueryConn(queryStr string, delayFor time.Duratio
 	return c.wsConnect(tailPath, params.Encode(), quiet)
 }
 
+// Export uses the /loki/api/v1/export endpoint to stream all entries of a log query.
+// f is called for each received stream as soon as it is received. The returned
+// cursor can be used to resume the export if it failed.
+func (c *DefaultClient) Export(queryStr string, limit int, start, end time.Time, direction logproto.Direction, cursor string, quiet bool, f func(loghttp.Stream) error) (string, error) {
+	params := util.NewQueryStringBuilder()
+	params.SetString("query", queryStr)
+	params.SetInt32("limit", limit)
+	params.SetInt("start", start.UnixNano())
+	params.SetInt("end", end.UnixNano())
+	params.SetString("direction", direction.String())
+	params.SetString("format", string(loghttp.ExportFormatJSON))
+	if cursor != "" {
+		params.SetString("cursor", cursor)
+	}
+
+	resp, err := c.doHTTPRequest(exportPath, params.Encode(), quiet)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			log.Println("error closing body", err)
+		}
+	}()
+
+	dec := json.NewDecoder(resp.Body)
+	for dec.More() {
+		var stream loghttp.Stream
+		if err := dec.Decode(&stream); err != nil {
+			return cursor, fmt.Errorf("error reading export response: %w", err)
+		}
+		if err := f(stream); err != nil {
+			return cursor, err
+		}
+		// Track the position ourselves in case the response is interrupted before the trailers are received.
+		for _, e := range stream.Entries {
+			cursor = advanceExportCursor(cursor, e.Timestamp)
+		}
+	}
+
+	// The server always sends the cursor once the export ended, its absence means the response was cut off.
+	last := resp.Trailer.Get(loghttp.ExportCursorTrailer)
+	if last == "" {
+		return cursor, errors.New("export response ended unexpectedly")
+	}
+	if msg := resp.Trailer.Get(loghttp.ExportErrorTrailer); msg != "" {
+		return last, errors.New(msg)
+	}
+	return last, nil
+}
+
+// advanceExportCursor returns the cursor after exporting an entry with the given timestamp.
+func advanceExportCursor(cursor string, ts time.Time) string {
+	c, err := loghttp.ParseExportCursor(cursor)
+	if err != nil || !c.Timestamp.Equal(ts) {
+		return loghttp.ExportCursor{Timestamp: ts, Skip: 1}.String()
+	}
+	c.Skip++
+	return c.String()
+}
+
 func (c *DefaultClient) GetOrgID() string {
 	return c.OrgID
 }
@@ -236,22 +301,38 @@ func (c *DefaultClient) doQuery(path string, query string, quiet bool) (*loghttp
 }
 
 func (c *DefaultClient) doRequest(path, query string, quiet bool, out interface{}) error {
-	us, err := buildURL(c.Address, path, query)
+	resp, err := c.doHTTPRequest(path, query, quiet)
 	if err != nil {
 		return err
 	}
+
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			log.Println("error closing body", err)
+		}
+	}()
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// doHTTPRequest sends the request, retrying on failures, and returns the
+// first successful response. The caller must close the response body.
+func (c *DefaultClient) doHTTPRequest(path, query string, quiet bool) (*http.Response, error) {
+	us, err := buildURL(c.Address, path, query)
+	if err != nil {
+		return nil, err
+	}
 	if !quiet {
 		log.Print(us)
 	}
 
 	req, err := http.NewRequest("GET", us, nil)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	h, err := c.getHTTPRequestHeader()
 	if err != nil {
-		return err
+		return nil, err
 	}
 	req.Header = h
 
@@ -263,14 +344,14 @@ func (c *DefaultClient) doRequest(path, query string, quiet bool, out interface{
 	if c.ProxyURL != "" {
 		prox, err := url.Parse(c.ProxyURL)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		clientConfig.ProxyURL = config.URL{URL: prox}
 	}
 
 	client, err := config.NewClientFromConfig(clientConfig, "promtail", config.WithHTTP2Disabled())
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if c.Tripperware != nil {
 		client.Transport = c.Tripperware(client.Transport)
@@ -313,15 +394,10 @@ func (c *DefaultClient) doRequest(path, query string, quiet bool, out interface{
 
 	}
 	if !success {
-		return fmt.Errorf("run out of attempts while querying the server")
+		return nil, fmt.Errorf("run out of attempts while querying the server")
 	}
 
-	defer func() {
-		if err := resp.Body.Close(); err != nil {
-			log.Println("error closing body", err)
-		}
-	}()
-	return json.NewDecoder(resp.Body).Decode(out)
+	return resp, nil
 }
 
 func (c *DefaultClient) getHTTPRequestHeader() (http.Header, error) {
@@ -347,6 +423,10 @@ func (c *DefaultClient) getHTTPRequestHeader() (http.Header, error) {
 		h.Set("X-Query-Tags", c.QueryTags)
 	}
 
+	if c.AllowPartial {
+		h.Set("X-Loki-Allow-Partial-Results", "true")
+	}
+
 	if (c.Username != "" || c.Password != "") && (len(c.BearerToken) > 0 || len(c.BearerTokenFile) > 0) {
 		return nil, fmt.Errorf("at most one of HTTP basic auth (username/password), bearer-token & bearer-token-file is allowed to be configured")
 	}
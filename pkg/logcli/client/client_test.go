@@ -3,9 +3,14 @@ package client
 import (
 	"encoding/base64"
 	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/grafana/loki/pkg/loghttp"
+	"github.com/grafana/loki/pkg/logproto"
 )
 
 func Test_buildURL(t *testing.T) {
@@ -78,3 +83,30 @@ func Test_getHTTPRequestHeader(t *testing.T) {
 		})
 	}
 }
+
+func Test_Export(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/loki/api/v1/export", r.URL.Path)
+		assert.Equal(t, "1-1", r.URL.Query().Get("cursor"))
+
+		w.Header().Set("Trailer", loghttp.ExportCursorTrailer+", "+loghttp.ExportErrorTrailer)
+		_, _ = w.Write([]byte(`{"stream":{"app":"foo"},"values":[["2","line 2"]]}` + "\n"))
+		_, _ = w.Write([]byte(`{"stream":{"app":"foo"},"values":[["3","line 3"]]}` + "\n"))
+		w.Header().Set(loghttp.ExportCursorTrailer, "3-1")
+		w.Header().Set(loghttp.ExportErrorTrailer, "querier unavailable")
+	}))
+	defer server.Close()
+
+	c := &DefaultClient{Address: server.URL}
+
+	var lines []string
+	cursor, err := c.Export(`{app="foo"}`, 0, time.Unix(0, 0), time.Unix(0, 10), logproto.FORWARD, "1-1", true, func(s loghttp.Stream) error {
+		for _, e := range s.Entries {
+			lines = append(lines, e.Line)
+		}
+		return nil
+	})
+	assert.EqualError(t, err, "querier unavailable")
+	assert.Equal(t, "3-1", cursor)
+	assert.Equal(t, []string{"line 2", "line 3"}, lines)
+}
@@ -179,6 +179,10 @@ func (f *FileClient) LiveTailQueryConn(_ string, _ time.Duration, _ int, _ time.
 	return nil, fmt.Errorf("LiveTailQuery: %w", ErrNotSupported)
 }
 
+func (f *FileClient) Export(_ string, _ int, _, _ time.Time, _ logproto.Direction, _ string, _ bool, _ func(loghttp.Stream) error) (string, error) {
+	return "", fmt.Errorf("Export: %w", ErrNotSupported)
+}
+
 func (f *FileClient) GetOrgID() string {
 	return f.orgID
 }
@@ -206,6 +210,10 @@ func (l *limiter) MaxQuerySeries(_ context.Context, _ string) int {
 	return l.n
 }
 
+func (l *limiter) MaxQueryJoinEntries(_ context.Context, _ string) int {
+	return 0
+}
+
 func (l *limiter) MaxQueryRange(_ context.Context, _ string) time.Duration {
 	return 0 * time.Second
 }
@@ -267,24 +275,28 @@ func newFileIterator(
 	processLine := func(line string) {
 		ts := time.Now()
 		parsedLine, parsedLabels, matches := pipeline.ProcessString(ts.UnixNano(), line)
-		if !matches {
-			return
-		}
+		for matches {
+			var stream *logproto.Stream
+			lhash := parsedLabels.Hash()
+			var ok bool
+			if stream, ok = streams[lhash]; !ok {
+				stream = &logproto.Stream{
+					Labels: parsedLabels.String(),
+				}
+				streams[lhash] = stream
+			}
 
-		var stream *logproto.Stream
-		lhash := parsedLabels.Hash()
-		var ok bool
-		if stream, ok = streams[lhash]; !ok {
-			stream = &logproto.Stream{
-				Labels: parsedLabels.String(),
+			stream.Entries = append(stream.Entries, logproto.Entry{
+				Timestamp: ts,
+				Line:      parsedLine,
+			})
+
+			// the line could have been fanned out into several lines.
+			var next []byte
+			if next, parsedLabels, matches = pipeline.Next(); matches {
+				parsedLine = string(next)
 			}
-			streams[lhash] = stream
 		}
-
-		stream.Entries = append(stream.Entries, logproto.Entry{
-			Timestamp: ts,
-			Line:      parsedLine,
-		})
 	}
 
 	if params.Direction == logproto.FORWARD {
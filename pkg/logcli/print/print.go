@@ -165,6 +165,22 @@ func (r *QueryResultPrinter) printStream(streams loghttp.Streams, out output.Log
 	return printed, lel
 }
 
+// PrintStream prints the entries of a single stream in the order they are
+// received, without removing the labels common to all streams.
+func (r *QueryResultPrinter) PrintStream(stream loghttp.Stream, out output.LogOutput) {
+	ls := stream.Labels
+	if len(r.ShowLabelsKey) > 0 {
+		ls = matchLabels(true, ls, r.ShowLabelsKey)
+	}
+	if len(r.IgnoreLabelsKey) > 0 {
+		ls = matchLabels(false, ls, r.IgnoreLabelsKey)
+	}
+
+	for _, e := range stream.Entries {
+		out.FormatAndPrintln(e.Timestamp, ls, r.FixedLabelsLen, e.Line)
+	}
+}
+
 func printMatrix(matrix loghttp.Matrix) {
 	// yes we are effectively unmarshalling and then immediately marshalling this object back to json.  we are doing this b/c
 	// it gives us more flexibility with regard to output types in the future.  initially we are supporting just formatted json but eventually
@@ -212,6 +228,13 @@ func (r *QueryResultPrinter) PrintStats(stats stats.Result) {
 	stats.Log(kvLogger{Writer: writer})
 }
 
+// PrintWarnings prints the warnings of a query response to stderr.
+func (r *QueryResultPrinter) PrintWarnings(warnings []string) {
+	for _, w := range warnings {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", w)
+	}
+}
+
 func matchLabels(on bool, l loghttp.LabelSet, names []string) loghttp.LabelSet {
 	return util.MatchLabels(on, l, names)
 }
@@ -54,6 +54,14 @@ type Query struct {
 	LocalConfig            string
 	FetchSchemaFromStorage bool
 
+	// Params holds the values of the parameters of the query string, e.g. $app.
+	Params map[string]string
+
+	// Stream the entries using the export endpoint instead of querying in batches.
+	Stream bool
+	// Cursor to resume a previously interrupted export from.
+	Cursor string
+
 	// Parallelization parameters.
 
 	// The duration of each part/job.
@@ -126,6 +134,7 @@ func (q *Query) DoQuery(c client.Client, out output.LogOutput, statistics bool)
 		if statistics {
 			result.PrintStats(resp.Data.Statistics)
 		}
+		result.PrintWarnings(resp.Warnings)
 		_, _ = result.PrintResult(resp.Data.Result, out, nil)
 	} else {
 		unlimited := q.Limit == 0
@@ -157,6 +166,7 @@ func (q *Query) DoQuery(c client.Client, out output.LogOutput, statistics bool)
 			if statistics {
 				result.PrintStats(resp.Data.Statistics)
 			}
+			result.PrintWarnings(resp.Warnings)
 
 			resultLength, lastEntry = result.PrintResult(resp.Data.Result, out, lastEntry)
 			// Was not a log stream query, or no results, no more batching
@@ -479,6 +489,7 @@ func (q *Query) DoLocalQuery(out output.LogOutput, statistics bool, orgID string
 	if statistics {
 		resPrinter.PrintStats(result.Statistics)
 	}
+	resPrinter.PrintWarnings(result.Warnings)
 
 	value, err := marshal.NewResultValue(result.Data)
 	if err != nil {
@@ -548,6 +559,35 @@ func (q *Query) isInstant() bool {
 	return q.Start == q.End && q.Step == 0
 }
 
+// DoExport streams all entries of the log query from the export endpoint and
+// prints them as they are received.
+func (q *Query) DoExport(c client.Client, out output.LogOutput) {
+	result := print.NewQueryResultPrinter(q.ShowLabelsKey, q.IgnoreLabelsKey, q.Quiet, q.FixedLabelsLen, q.Forward)
+
+	cursor := q.Cursor
+	for {
+		exported := 0
+		next, err := c.Export(q.QueryString, q.Limit, q.Start, q.End, q.resultsDirection(), cursor, q.Quiet, func(s loghttp.Stream) error {
+			exported++
+			result.PrintStream(s, out)
+			return nil
+		})
+		if err != nil {
+			if next != "" {
+				log.Printf("Resume the export using --cursor=%s", next)
+			}
+			log.Fatalf("Export failed: %+v", err)
+		}
+
+		// Without a limit the server still stops at its max export entries,
+		// so the export is resumed until no entries are left.
+		if q.Limit > 0 || exported == 0 {
+			return
+		}
+		cursor = next
+	}
+}
+
 func (q *Query) resultsDirection() logproto.Direction {
 	if q.Forward {
 		return logproto.FORWARD
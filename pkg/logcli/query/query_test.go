@@ -467,6 +467,10 @@ func (t *testQueryClient) LiveTailQueryConn(_ string, _ time.Duration, _ int, _
 	panic("implement me")
 }
 
+func (t *testQueryClient) Export(_ string, _ int, _, _ time.Time, _ logproto.Direction, _ string, _ bool, _ func(loghttp.Stream) error) (string, error) {
+	panic("implement me")
+}
+
 func (t *testQueryClient) GetOrgID() string {
 	panic("implement me")
 }
@@ -0,0 +1,143 @@
+package loghttp
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/dskit/httpgrpc"
+
+	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/logql/syntax"
+)
+
+const (
+	// ExportCursorTrailer is the HTTP trailer of an export response holding
+	// the cursor to resume the export from.
+	ExportCursorTrailer = "X-Loki-Export-Cursor"
+	// ExportErrorTrailer is the HTTP trailer of an export response holding
+	// the error which terminated the export early.
+	ExportErrorTrailer = "X-Loki-Export-Error"
+)
+
+// ExportFormat is the encoding of the entries of an export response.
+type ExportFormat string
+
+const (
+	// ExportFormatJSON encodes each entry as a single stream of the query API
+	// response, one JSON object per line.
+	ExportFormatJSON ExportFormat = "ndjson"
+	// ExportFormatProtobuf encodes each entry as a logproto.Stream, prefixed
+	// by its size as uvarint.
+	ExportFormatProtobuf ExportFormat = "protobuf"
+)
+
+// ContentType returns the HTTP content type of the export format.
+func (f ExportFormat) ContentType() string {
+	if f == ExportFormatProtobuf {
+		return "application/vnd.google.protobuf"
+	}
+	return "application/x-ndjson"
+}
+
+// ExportCursor is the position of an export in the exported time range.
+// It is the timestamp of the last exported entry and the number of entries
+// with this timestamp which were already exported.
+type ExportCursor struct {
+	Timestamp time.Time
+	Skip      int
+}
+
+// String encodes the cursor as request parameter.
+func (c ExportCursor) String() string {
+	return fmt.Sprintf("%d-%d", c.Timestamp.UnixNano(), c.Skip)
+}
+
+// ParseExportCursor parses a cursor encoded with ExportCursor.String.
+func ParseExportCursor(value string) (ExportCursor, error) {
+	ts, skip, ok := strings.Cut(value, "-")
+	if !ok {
+		return ExportCursor{}, fmt.Errorf("invalid cursor %q", value)
+	}
+	nanos, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return ExportCursor{}, fmt.Errorf("invalid cursor %q: %w", value, err)
+	}
+	n, err := strconv.Atoi(skip)
+	if err != nil || n < 0 {
+		return ExportCursor{}, fmt.Errorf("invalid cursor %q", value)
+	}
+	return ExportCursor{Timestamp: time.Unix(0, nanos), Skip: n}, nil
+}
+
+// ExportQuery defines a log query exported using the export endpoint.
+type ExportQuery struct {
+	Query     string
+	Start     time.Time
+	End       time.Time
+	Limit     uint32
+	Direction logproto.Direction
+	Format    ExportFormat
+	Cursor    *ExportCursor
+}
+
+// ParseExportQuery parses an ExportQuery request from an http request.
+// Unlike range queries, the limit defaults to 0 which exports up to the max export entries limit.
+func ParseExportQuery(r *http.Request) (*ExportQuery, error) {
+	var (
+		result ExportQuery
+		err    error
+	)
+
+	result.Query, err = query(r)
+	if err != nil {
+		return nil, httpgrpc.Errorf(http.StatusBadRequest, err.Error())
+	}
+	if _, err := syntax.ParseLogSelector(result.Query, true); err != nil {
+		return nil, httpgrpc.Errorf(http.StatusBadRequest, "only log queries can be exported: %s", err)
+	}
+
+	result.Start, result.End, err = bounds(r)
+	if err != nil {
+		return nil, err
+	}
+	if result.End.Before(result.Start) {
+		return nil, errEndBeforeStart
+	}
+
+	l, err := parseInt(r.Form.Get("limit"), 0)
+	if err != nil {
+		return nil, err
+	}
+	if l < 0 {
+		return nil, errors.New("limit must not be negative")
+	}
+	result.Limit = uint32(l)
+
+	result.Direction, err = parseDirection(r.Form.Get("direction"), logproto.FORWARD)
+	if err != nil {
+		return nil, err
+	}
+
+	switch format := ExportFormat(r.Form.Get("format")); format {
+	case "", ExportFormatJSON:
+		result.Format = ExportFormatJSON
+	case ExportFormatProtobuf:
+		result.Format = format
+	default:
+		return nil, fmt.Errorf("unsupported export format %q, must be one of %s, %s", format, ExportFormatJSON, ExportFormatProtobuf)
+	}
+
+	if value := r.Form.Get("cursor"); value != "" {
+		cursor, err := ParseExportCursor(value)
+		if err != nil {
+			return nil, err
+		}
+		result.Cursor = &cursor
+	}
+
+	return &result, nil
+}
@@ -0,0 +1,73 @@
+package loghttp
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/pkg/logproto"
+)
+
+func TestParseExportQuery(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		url     string
+		want    *ExportQuery
+		wantErr bool
+	}{
+		{"metric query", `?query=rate({foo="bar"}[1m])&start=0&end=1`, nil, true},
+		{"negative limit", `?query={foo="bar"}&start=0&end=1&limit=-1`, nil, true},
+		{"bad format", `?query={foo="bar"}&start=0&end=1&format=csv`, nil, true},
+		{"bad cursor", `?query={foo="bar"}&start=0&end=1&cursor=foo`, nil, true},
+		{"defaults",
+			`?query={foo="bar"}&start=0&end=10`,
+			&ExportQuery{
+				Query:     `{foo="bar"}`,
+				Start:     time.Unix(0, 0),
+				End:       time.Unix(10, 0),
+				Direction: logproto.FORWARD,
+				Format:    ExportFormatJSON,
+			}, false},
+		{"good",
+			`?query={foo="bar"}&start=0&end=10&limit=100&direction=backward&format=protobuf&cursor=5-2`,
+			&ExportQuery{
+				Query:     `{foo="bar"}`,
+				Start:     time.Unix(0, 0),
+				End:       time.Unix(10, 0),
+				Limit:     100,
+				Direction: logproto.BACKWARD,
+				Format:    ExportFormatProtobuf,
+				Cursor:    &ExportCursor{Timestamp: time.Unix(0, 5), Skip: 2},
+			}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &http.Request{URL: mustParseURL(tt.url)}
+			require.NoError(t, r.ParseForm())
+
+			got, err := ParseExportQuery(r)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestExportCursor(t *testing.T) {
+	cursor := ExportCursor{Timestamp: time.Unix(0, 1700000000000000000), Skip: 3}
+	require.Equal(t, "1700000000000000000-3", cursor.String())
+
+	parsed, err := ParseExportCursor(cursor.String())
+	require.NoError(t, err)
+	require.Equal(t, cursor, parsed)
+
+	_, err = ParseExportCursor("1700000000000000000--1")
+	require.Error(t, err)
+}
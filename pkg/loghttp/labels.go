@@ -83,6 +83,9 @@ func ParseLabelQuery(r *http.Request) (*logproto.LabelRequest, error) {
 	req.Start = &start
 	req.End = &end
 
-	req.Query = query(r)
+	req.Query, err = query(r)
+	if err != nil {
+		return nil, err
+	}
 	return req, nil
 }
@@ -13,6 +13,7 @@ import (
 	"github.com/prometheus/prometheus/model/labels"
 
 	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/logql"
 	"github.com/grafana/loki/pkg/logql/syntax"
 )
 
@@ -20,6 +21,9 @@ const (
 	defaultQueryLimit = 100
 	defaultSince      = 1 * time.Hour
 	defaultDirection  = logproto.BACKWARD
+
+	// queryParamPrefix prefixes the names of the values of the parameters of a query, e.g. param_app for $app.
+	queryParamPrefix = "param_"
 )
 
 func limit(r *http.Request) (uint32, error) {
@@ -33,8 +37,15 @@ func limit(r *http.Request) (uint32, error) {
 	return uint32(l), nil
 }
 
-func query(r *http.Request) string {
-	return r.Form.Get("query")
+// query returns the query of a request, binding the values of its parameters.
+func query(r *http.Request) (string, error) {
+	params := make(map[string]string)
+	for k, v := range r.Form {
+		if name, ok := strings.CutPrefix(k, queryParamPrefix); ok && len(v) > 0 {
+			params[name] = v[0]
+		}
+	}
+	return syntax.BindParams(r.Form.Get("query"), params)
 }
 
 func ts(r *http.Request) (time.Time, error) {
@@ -96,6 +107,26 @@ func step(r *http.Request, start, end time.Time) (time.Duration, error) {
 	return parseSecondsOrDuration(value)
 }
 
+// calendar returns the calendar of a step of day, week or month in the time zone tz, nil for fixed steps.
+func calendar(r *http.Request) (*logql.Calendar, error) {
+	value, tz, ranges := r.Form.Get("step"), r.Form.Get("tz"), r.Form.Get("calendar_ranges")
+	if !logql.IsCalendarUnit(value) {
+		if tz != "" || ranges != "" {
+			return nil, errCalendarParamWithoutCalendar
+		}
+		return nil, nil
+	}
+	coverRanges := false
+	if ranges != "" {
+		var err error
+		coverRanges, err = strconv.ParseBool(ranges)
+		if err != nil {
+			return nil, fmt.Errorf("invalid calendar_ranges %q: %w", ranges, err)
+		}
+	}
+	return logql.ParseCalendar(value, tz, coverRanges)
+}
+
 func interval(r *http.Request) (time.Duration, error) {
 	value := r.Form.Get("interval")
 	if value == "" {
@@ -185,7 +216,10 @@ func parseSecondsOrDuration(value string) (time.Duration, error) {
 // parseRegexQuery parses regex and query querystring from httpRequest and returns the combined LogQL query.
 // This is used only to keep regexp query string support until it gets fully deprecated.
 func parseRegexQuery(httpRequest *http.Request) (string, error) {
-	query := httpRequest.Form.Get("query")
+	query, err := query(httpRequest)
+	if err != nil {
+		return "", err
+	}
 	regexp := httpRequest.Form.Get("regexp")
 	if regexp != "" {
 		expr, err := syntax.ParseLogSelector(query, true)
@@ -15,6 +15,7 @@ import (
 	"github.com/grafana/dskit/httpgrpc"
 
 	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/logql"
 	"github.com/grafana/loki/pkg/logql/syntax"
 	"github.com/grafana/loki/pkg/logqlmodel"
 	"github.com/grafana/loki/pkg/logqlmodel/stats"
@@ -23,11 +24,12 @@ import (
 )
 
 var (
-	errEndBeforeStart     = errors.New("end timestamp must not be before or equal to start time")
-	errZeroOrNegativeStep = errors.New("zero or negative query resolution step widths are not accepted. Try a positive integer")
-	errNegativeStep       = errors.New("negative query resolution step widths are not accepted. Try a positive integer")
-	errStepTooSmall       = errors.New("exceeded maximum resolution of 11,000 points per time series. Try increasing the value of the step parameter")
-	errNegativeInterval   = errors.New("interval must be >= 0")
+	errEndBeforeStart               = errors.New("end timestamp must not be before or equal to start time")
+	errZeroOrNegativeStep           = errors.New("zero or negative query resolution step widths are not accepted. Try a positive integer")
+	errNegativeStep                 = errors.New("negative query resolution step widths are not accepted. Try a positive integer")
+	errStepTooSmall                 = errors.New("exceeded maximum resolution of 11,000 points per time series. Try increasing the value of the step parameter")
+	errCalendarParamWithoutCalendar = errors.New("the tz and calendar_ranges parameters require a step of day, week or month")
+	errNegativeInterval             = errors.New("interval must be >= 0")
 )
 
 // QueryStatus holds the status of a query
@@ -41,8 +43,9 @@ const (
 
 // QueryResponse represents the http json response to a Loki range and instant query
 type QueryResponse struct {
-	Status string            `json:"status"`
-	Data   QueryResponseData `json:"data"`
+	Status   string            `json:"status"`
+	Data     QueryResponseData `json:"data"`
+	Warnings []string          `json:"warnings,omitempty"`
 }
 
 func (q *QueryResponse) UnmarshalJSON(data []byte) error {
@@ -56,6 +59,16 @@ func (q *QueryResponse) UnmarshalJSON(data []byte) error {
 				return err
 			}
 			q.Data = responseData
+		case "warnings":
+			var warnings []string
+			if _, err := jsonparser.ArrayEach(value, func(value []byte, dataType jsonparser.ValueType, _ int, _ error) {
+				if dataType == jsonparser.String {
+					warnings = append(warnings, string(value))
+				}
+			}); err != nil {
+				return err
+			}
+			q.Warnings = warnings
 		}
 		return nil
 	})
@@ -371,9 +384,12 @@ type InstantQuery struct {
 // ParseInstantQuery parses an InstantQuery request from an http request.
 func ParseInstantQuery(r *http.Request) (*InstantQuery, error) {
 	var err error
-	request := &InstantQuery{
-		Query: query(r),
+	request := &InstantQuery{}
+	request.Query, err = query(r)
+	if err != nil {
+		return nil, err
 	}
+
 	request.Limit, err = limit(r)
 	if err != nil {
 		return nil, err
@@ -403,6 +419,8 @@ type RangeQuery struct {
 	Direction logproto.Direction
 	Limit     uint32
 	Shards    []string
+	// Calendar aligns the steps to the days, weeks or months of a time zone, nil for fixed steps.
+	Calendar *logql.Calendar
 }
 
 func NewRangeQueryWithDefaults() *RangeQuery {
@@ -428,7 +446,11 @@ func ParseRangeQuery(r *http.Request) (*RangeQuery, error) {
 	var result RangeQuery
 	var err error
 
-	result.Query = query(r)
+	result.Query, err = query(r)
+	if err != nil {
+		return nil, err
+	}
+
 	result.Start, result.End, err = bounds(r)
 	if err != nil {
 		return nil, err
@@ -448,11 +470,20 @@ func ParseRangeQuery(r *http.Request) (*RangeQuery, error) {
 		return nil, err
 	}
 
-	result.Step, err = step(r, result.Start, result.End)
+	result.Calendar, err = calendar(r)
 	if err != nil {
 		return nil, err
 	}
 
+	if result.Calendar != nil {
+		result.Step = result.Calendar.Step()
+	} else {
+		result.Step, err = step(r, result.Start, result.End)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	if result.Step <= 0 {
 		return nil, errZeroOrNegativeStep
 	}
@@ -12,6 +12,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/logql"
 	"github.com/grafana/loki/pkg/logqlmodel/stats"
 )
 
@@ -71,6 +72,58 @@ func TestParseRangeQuery(t *testing.T) {
 				Limit:     1000,
 			}, false,
 		},
+		{
+			"calendar step",
+			&http.Request{
+				URL: mustParseURL(`?query={foo="bar"}&start=2017-06-10T21:42:24.760738998Z&end=2017-07-10T21:42:24.760738998Z&limit=1000&direction=BACKWARD&step=week&tz=Europe/Paris`),
+			}, &RangeQuery{
+				Step:      7 * 24 * time.Hour,
+				Query:     `{foo="bar"}`,
+				Direction: logproto.BACKWARD,
+				Start:     time.Date(2017, 06, 10, 21, 42, 24, 760738998, time.UTC),
+				End:       time.Date(2017, 07, 10, 21, 42, 24, 760738998, time.UTC),
+				Limit:     1000,
+				Calendar:  &logql.Calendar{Unit: logql.CalendarWeek, Location: mustLoadLocation("Europe/Paris")},
+			}, false,
+		},
+		{
+			"calendar step covering the ranges",
+			&http.Request{
+				URL: mustParseURL(`?query={foo="bar"}&start=2017-06-10T21:42:24.760738998Z&end=2017-07-10T21:42:24.760738998Z&limit=1000&direction=BACKWARD&step=month&calendar_ranges=true`),
+			}, &RangeQuery{
+				Step:      30 * 24 * time.Hour,
+				Query:     `{foo="bar"}`,
+				Direction: logproto.BACKWARD,
+				Start:     time.Date(2017, 06, 10, 21, 42, 24, 760738998, time.UTC),
+				End:       time.Date(2017, 07, 10, 21, 42, 24, 760738998, time.UTC),
+				Limit:     1000,
+				Calendar:  &logql.Calendar{Unit: logql.CalendarMonth, Location: time.UTC, Ranges: true},
+			}, false,
+		},
+		{
+			"bad time zone",
+			&http.Request{
+				URL: mustParseURL(`?query={foo="bar"}&start=2017-06-10T21:42:24.760738998Z&end=2017-07-10T21:42:24.760738998Z&step=day&tz=Mars/Olympus_Mons`),
+			}, nil, true,
+		},
+		{
+			"time zone without calendar step",
+			&http.Request{
+				URL: mustParseURL(`?query={foo="bar"}&start=2017-06-10T21:42:24.760738998Z&end=2017-07-10T21:42:24.760738998Z&step=3600&tz=Europe/Paris`),
+			}, nil, true,
+		},
+		{
+			"calendar ranges without calendar step",
+			&http.Request{
+				URL: mustParseURL(`?query={foo="bar"}&start=2017-06-10T21:42:24.760738998Z&end=2017-07-10T21:42:24.760738998Z&step=3600&calendar_ranges=true`),
+			}, nil, true,
+		},
+		{
+			"bad calendar ranges",
+			&http.Request{
+				URL: mustParseURL(`?query={foo="bar"}&start=2017-06-10T21:42:24.760738998Z&end=2017-07-10T21:42:24.760738998Z&step=day&calendar_ranges=maybe`),
+			}, nil, true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -115,6 +168,19 @@ func TestParseInstantQuery(t *testing.T) {
 				Limit:     1000,
 			}, false,
 		},
+		{
+			"params",
+			&http.Request{
+				URL: mustParseURL(`?query=sum(rate({foo=$foo}|=$line[1m]))>$threshold&param_foo=` + url.QueryEscape(`b"a\r`) + `&param_line=error&param_threshold=10&time=2017-06-10T21:42:24.760738998Z&limit=1000&direction=BACKWARD`),
+			}, &InstantQuery{
+				Query:     `(sum(rate({foo="b\"a\\r"} |= "error"[1m])) > 10)`,
+				Direction: logproto.BACKWARD,
+				Ts:        time.Date(2017, 06, 10, 21, 42, 24, 760738998, time.UTC),
+				Limit:     1000,
+			}, false,
+		},
+		{"unbound param", &http.Request{URL: mustParseURL(`?query={foo=$foo}&param_bar=baz`)}, nil, true},
+		{"bad param", &http.Request{URL: mustParseURL(`?query=sum(rate({foo="bar"}[1m]))>$foo&param_foo=baz`)}, nil, true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -140,6 +206,14 @@ func mustParseURL(u string) *url.URL {
 	return url
 }
 
+func mustLoadLocation(name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		panic(err)
+	}
+	return loc
+}
+
 func TestStreams_ToProto(t *testing.T) {
 	tests := []struct {
 		name string
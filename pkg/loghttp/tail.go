@@ -67,9 +67,7 @@ func (s *DroppedStream) UnmarshalJSON(data []byte) error {
 // ParseTailQuery parses a TailRequest request from an http request.
 func ParseTailQuery(r *http.Request) (*logproto.TailRequest, error) {
 	var err error
-	req := logproto.TailRequest{
-		Query: query(r),
-	}
+	req := logproto.TailRequest{}
 
 	req.Query, err = parseRegexQuery(r)
 	if err != nil {
@@ -215,6 +215,179 @@ func (m *StreamRate) GetPushes() uint32 {
 	return 0
 }
 
+type OutOfOrderStatsRequest struct {
+}
+
+func (m *OutOfOrderStatsRequest) Reset()      { *m = OutOfOrderStatsRequest{} }
+func (*OutOfOrderStatsRequest) ProtoMessage() {}
+func (*OutOfOrderStatsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c28a5f14f1f4c79a, []int{3}
+}
+func (m *OutOfOrderStatsRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *OutOfOrderStatsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_OutOfOrderStatsRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *OutOfOrderStatsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_OutOfOrderStatsRequest.Merge(m, src)
+}
+func (m *OutOfOrderStatsRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *OutOfOrderStatsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_OutOfOrderStatsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_OutOfOrderStatsRequest proto.InternalMessageInfo
+
+type OutOfOrderStatsResponse struct {
+	// Only the streams of the tenant which received out-of-order entries are returned.
+	Streams []*StreamOutOfOrderStats `protobuf:"bytes,1,rep,name=streams,proto3" json:"streams,omitempty"`
+}
+
+func (m *OutOfOrderStatsResponse) Reset()      { *m = OutOfOrderStatsResponse{} }
+func (*OutOfOrderStatsResponse) ProtoMessage() {}
+func (*OutOfOrderStatsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c28a5f14f1f4c79a, []int{4}
+}
+func (m *OutOfOrderStatsResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *OutOfOrderStatsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_OutOfOrderStatsResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *OutOfOrderStatsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_OutOfOrderStatsResponse.Merge(m, src)
+}
+func (m *OutOfOrderStatsResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *OutOfOrderStatsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_OutOfOrderStatsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_OutOfOrderStatsResponse proto.InternalMessageInfo
+
+func (m *OutOfOrderStatsResponse) GetStreams() []*StreamOutOfOrderStats {
+	if m != nil {
+		return m.Streams
+	}
+	return nil
+}
+
+type StreamOutOfOrderStats struct {
+	Labels string `protobuf:"bytes,1,opt,name=labels,proto3" json:"labels,omitempty"`
+	// Accepted entries older than the newest entry of the stream, which the head block has to reorder.
+	OutOfOrderEntries uint64 `protobuf:"varint,2,opt,name=outOfOrderEntries,proto3" json:"outOfOrderEntries,omitempty"`
+	OutOfOrderBytes   uint64 `protobuf:"varint,3,opt,name=outOfOrderBytes,proto3" json:"outOfOrderBytes,omitempty"`
+	// Distances in nanoseconds between the out-of-order entries and the newest entry of the stream.
+	MaxDistance   int64 `protobuf:"varint,4,opt,name=maxDistance,proto3" json:"maxDistance,omitempty"`
+	TotalDistance int64 `protobuf:"varint,5,opt,name=totalDistance,proto3" json:"totalDistance,omitempty"`
+	// Entries rejected for being out of order or too far behind the newest entry of the stream.
+	RejectedEntries uint64 `protobuf:"varint,6,opt,name=rejectedEntries,proto3" json:"rejectedEntries,omitempty"`
+	RejectedBytes   uint64 `protobuf:"varint,7,opt,name=rejectedBytes,proto3" json:"rejectedBytes,omitempty"`
+}
+
+func (m *StreamOutOfOrderStats) Reset()      { *m = StreamOutOfOrderStats{} }
+func (*StreamOutOfOrderStats) ProtoMessage() {}
+func (*StreamOutOfOrderStats) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c28a5f14f1f4c79a, []int{5}
+}
+func (m *StreamOutOfOrderStats) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *StreamOutOfOrderStats) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_StreamOutOfOrderStats.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *StreamOutOfOrderStats) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StreamOutOfOrderStats.Merge(m, src)
+}
+func (m *StreamOutOfOrderStats) XXX_Size() int {
+	return m.Size()
+}
+func (m *StreamOutOfOrderStats) XXX_DiscardUnknown() {
+	xxx_messageInfo_StreamOutOfOrderStats.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StreamOutOfOrderStats proto.InternalMessageInfo
+
+func (m *StreamOutOfOrderStats) GetLabels() string {
+	if m != nil {
+		return m.Labels
+	}
+	return ""
+}
+
+func (m *StreamOutOfOrderStats) GetOutOfOrderEntries() uint64 {
+	if m != nil {
+		return m.OutOfOrderEntries
+	}
+	return 0
+}
+
+func (m *StreamOutOfOrderStats) GetOutOfOrderBytes() uint64 {
+	if m != nil {
+		return m.OutOfOrderBytes
+	}
+	return 0
+}
+
+func (m *StreamOutOfOrderStats) GetMaxDistance() int64 {
+	if m != nil {
+		return m.MaxDistance
+	}
+	return 0
+}
+
+func (m *StreamOutOfOrderStats) GetTotalDistance() int64 {
+	if m != nil {
+		return m.TotalDistance
+	}
+	return 0
+}
+
+func (m *StreamOutOfOrderStats) GetRejectedEntries() uint64 {
+	if m != nil {
+		return m.RejectedEntries
+	}
+	return 0
+}
+
+func (m *StreamOutOfOrderStats) GetRejectedBytes() uint64 {
+	if m != nil {
+		return m.RejectedBytes
+	}
+	return 0
+}
+
 type QueryRequest struct {
 	Selector  string    `protobuf:"bytes,1,opt,name=selector,proto3" json:"selector,omitempty"`
 	Limit     uint32    `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
@@ -228,7 +401,7 @@ type QueryRequest struct {
 func (m *QueryRequest) Reset()      { *m = QueryRequest{} }
 func (*QueryRequest) ProtoMessage() {}
 func (*QueryRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{3}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{6}
 }
 func (m *QueryRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -317,7 +490,7 @@ type SampleQueryRequest struct {
 func (m *SampleQueryRequest) Reset()      { *m = SampleQueryRequest{} }
 func (*SampleQueryRequest) ProtoMessage() {}
 func (*SampleQueryRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{4}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{7}
 }
 func (m *SampleQueryRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -390,7 +563,7 @@ type Delete struct {
 func (m *Delete) Reset()      { *m = Delete{} }
 func (*Delete) ProtoMessage() {}
 func (*Delete) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{5}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{8}
 }
 func (m *Delete) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -448,7 +621,7 @@ type QueryResponse struct {
 func (m *QueryResponse) Reset()      { *m = QueryResponse{} }
 func (*QueryResponse) ProtoMessage() {}
 func (*QueryResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{6}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{9}
 }
 func (m *QueryResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -492,7 +665,7 @@ type SampleQueryResponse struct {
 func (m *SampleQueryResponse) Reset()      { *m = SampleQueryResponse{} }
 func (*SampleQueryResponse) ProtoMessage() {}
 func (*SampleQueryResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{7}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{10}
 }
 func (m *SampleQueryResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -539,7 +712,7 @@ type LabelRequest struct {
 func (m *LabelRequest) Reset()      { *m = LabelRequest{} }
 func (*LabelRequest) ProtoMessage() {}
 func (*LabelRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{8}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{11}
 }
 func (m *LabelRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -610,7 +783,7 @@ type LabelResponse struct {
 func (m *LabelResponse) Reset()      { *m = LabelResponse{} }
 func (*LabelResponse) ProtoMessage() {}
 func (*LabelResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{9}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{12}
 }
 func (m *LabelResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -655,7 +828,7 @@ type Sample struct {
 func (m *Sample) Reset()      { *m = Sample{} }
 func (*Sample) ProtoMessage() {}
 func (*Sample) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{10}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{13}
 }
 func (m *Sample) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -714,7 +887,7 @@ type LegacySample struct {
 func (m *LegacySample) Reset()      { *m = LegacySample{} }
 func (*LegacySample) ProtoMessage() {}
 func (*LegacySample) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{11}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{14}
 }
 func (m *LegacySample) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -766,7 +939,7 @@ type Series struct {
 func (m *Series) Reset()      { *m = Series{} }
 func (*Series) ProtoMessage() {}
 func (*Series) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{12}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{15}
 }
 func (m *Series) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -826,7 +999,7 @@ type TailRequest struct {
 func (m *TailRequest) Reset()      { *m = TailRequest{} }
 func (*TailRequest) ProtoMessage() {}
 func (*TailRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{13}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{16}
 }
 func (m *TailRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -891,7 +1064,7 @@ type TailResponse struct {
 func (m *TailResponse) Reset()      { *m = TailResponse{} }
 func (*TailResponse) ProtoMessage() {}
 func (*TailResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{14}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{17}
 }
 func (m *TailResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -937,7 +1110,7 @@ type SeriesRequest struct {
 func (m *SeriesRequest) Reset()      { *m = SeriesRequest{} }
 func (*SeriesRequest) ProtoMessage() {}
 func (*SeriesRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{15}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{18}
 }
 func (m *SeriesRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1001,7 +1174,7 @@ type SeriesResponse struct {
 func (m *SeriesResponse) Reset()      { *m = SeriesResponse{} }
 func (*SeriesResponse) ProtoMessage() {}
 func (*SeriesResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{16}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{19}
 }
 func (m *SeriesResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1044,7 +1217,7 @@ type SeriesIdentifier struct {
 func (m *SeriesIdentifier) Reset()      { *m = SeriesIdentifier{} }
 func (*SeriesIdentifier) ProtoMessage() {}
 func (*SeriesIdentifier) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{17}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{20}
 }
 func (m *SeriesIdentifier) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1089,7 +1262,7 @@ type DroppedStream struct {
 func (m *DroppedStream) Reset()      { *m = DroppedStream{} }
 func (*DroppedStream) ProtoMessage() {}
 func (*DroppedStream) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{18}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{21}
 }
 func (m *DroppedStream) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1147,7 +1320,7 @@ type LabelPair struct {
 func (m *LabelPair) Reset()      { *m = LabelPair{} }
 func (*LabelPair) ProtoMessage() {}
 func (*LabelPair) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{19}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{22}
 }
 func (m *LabelPair) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1200,7 +1373,7 @@ type LegacyLabelPair struct {
 func (m *LegacyLabelPair) Reset()      { *m = LegacyLabelPair{} }
 func (*LegacyLabelPair) ProtoMessage() {}
 func (*LegacyLabelPair) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{20}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{23}
 }
 func (m *LegacyLabelPair) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1250,7 +1423,7 @@ type Chunk struct {
 func (m *Chunk) Reset()      { *m = Chunk{} }
 func (*Chunk) ProtoMessage() {}
 func (*Chunk) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{21}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{24}
 }
 func (m *Chunk) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1292,7 +1465,7 @@ type TailersCountRequest struct {
 func (m *TailersCountRequest) Reset()      { *m = TailersCountRequest{} }
 func (*TailersCountRequest) ProtoMessage() {}
 func (*TailersCountRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{22}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{25}
 }
 func (m *TailersCountRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1328,7 +1501,7 @@ type TailersCountResponse struct {
 func (m *TailersCountResponse) Reset()      { *m = TailersCountResponse{} }
 func (*TailersCountResponse) ProtoMessage() {}
 func (*TailersCountResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{23}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{26}
 }
 func (m *TailersCountResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1373,7 +1546,7 @@ type GetChunkIDsRequest struct {
 func (m *GetChunkIDsRequest) Reset()      { *m = GetChunkIDsRequest{} }
 func (*GetChunkIDsRequest) ProtoMessage() {}
 func (*GetChunkIDsRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{24}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{27}
 }
 func (m *GetChunkIDsRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1430,7 +1603,7 @@ type GetChunkIDsResponse struct {
 func (m *GetChunkIDsResponse) Reset()      { *m = GetChunkIDsResponse{} }
 func (*GetChunkIDsResponse) ProtoMessage() {}
 func (*GetChunkIDsResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{25}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{28}
 }
 func (m *GetChunkIDsResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1483,7 +1656,7 @@ type ChunkRef struct {
 func (m *ChunkRef) Reset()      { *m = ChunkRef{} }
 func (*ChunkRef) ProtoMessage() {}
 func (*ChunkRef) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{26}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{29}
 }
 func (m *ChunkRef) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1544,7 +1717,7 @@ type LabelValuesForMetricNameRequest struct {
 func (m *LabelValuesForMetricNameRequest) Reset()      { *m = LabelValuesForMetricNameRequest{} }
 func (*LabelValuesForMetricNameRequest) ProtoMessage() {}
 func (*LabelValuesForMetricNameRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{27}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{30}
 }
 func (m *LabelValuesForMetricNameRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1603,7 +1776,7 @@ type LabelNamesForMetricNameRequest struct {
 func (m *LabelNamesForMetricNameRequest) Reset()      { *m = LabelNamesForMetricNameRequest{} }
 func (*LabelNamesForMetricNameRequest) ProtoMessage() {}
 func (*LabelNamesForMetricNameRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{28}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{31}
 }
 func (m *LabelNamesForMetricNameRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1647,7 +1820,7 @@ type LineFilterExpression struct {
 func (m *LineFilterExpression) Reset()      { *m = LineFilterExpression{} }
 func (*LineFilterExpression) ProtoMessage() {}
 func (*LineFilterExpression) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{29}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{32}
 }
 func (m *LineFilterExpression) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1700,7 +1873,7 @@ type GetChunkRefRequest struct {
 func (m *GetChunkRefRequest) Reset()      { *m = GetChunkRefRequest{} }
 func (*GetChunkRefRequest) ProtoMessage() {}
 func (*GetChunkRefRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{30}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{33}
 }
 func (m *GetChunkRefRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1750,7 +1923,7 @@ type GetChunkRefResponse struct {
 func (m *GetChunkRefResponse) Reset()      { *m = GetChunkRefResponse{} }
 func (*GetChunkRefResponse) ProtoMessage() {}
 func (*GetChunkRefResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{31}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{34}
 }
 func (m *GetChunkRefResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1795,7 +1968,7 @@ type GetSeriesRequest struct {
 func (m *GetSeriesRequest) Reset()      { *m = GetSeriesRequest{} }
 func (*GetSeriesRequest) ProtoMessage() {}
 func (*GetSeriesRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{32}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{35}
 }
 func (m *GetSeriesRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1838,7 +2011,7 @@ type GetSeriesResponse struct {
 func (m *GetSeriesResponse) Reset()      { *m = GetSeriesResponse{} }
 func (*GetSeriesResponse) ProtoMessage() {}
 func (*GetSeriesResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{33}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{36}
 }
 func (m *GetSeriesResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1882,7 +2055,7 @@ type IndexSeries struct {
 func (m *IndexSeries) Reset()      { *m = IndexSeries{} }
 func (*IndexSeries) ProtoMessage() {}
 func (*IndexSeries) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{34}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{37}
 }
 func (m *IndexSeries) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1919,7 +2092,7 @@ type QueryIndexResponse struct {
 func (m *QueryIndexResponse) Reset()      { *m = QueryIndexResponse{} }
 func (*QueryIndexResponse) ProtoMessage() {}
 func (*QueryIndexResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{35}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{38}
 }
 func (m *QueryIndexResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1970,7 +2143,7 @@ type Row struct {
 func (m *Row) Reset()      { *m = Row{} }
 func (*Row) ProtoMessage() {}
 func (*Row) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{36}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{39}
 }
 func (m *Row) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2020,7 +2193,7 @@ type QueryIndexRequest struct {
 func (m *QueryIndexRequest) Reset()      { *m = QueryIndexRequest{} }
 func (*QueryIndexRequest) ProtoMessage() {}
 func (*QueryIndexRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{37}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{40}
 }
 func (m *QueryIndexRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2067,7 +2240,7 @@ type IndexQuery struct {
 func (m *IndexQuery) Reset()      { *m = IndexQuery{} }
 func (*IndexQuery) ProtoMessage() {}
 func (*IndexQuery) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{38}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{41}
 }
 func (m *IndexQuery) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2140,7 +2313,7 @@ type IndexStatsRequest struct {
 func (m *IndexStatsRequest) Reset()      { *m = IndexStatsRequest{} }
 func (*IndexStatsRequest) ProtoMessage() {}
 func (*IndexStatsRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{39}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{42}
 }
 func (m *IndexStatsRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2186,7 +2359,7 @@ type IndexStatsResponse struct {
 func (m *IndexStatsResponse) Reset()      { *m = IndexStatsResponse{} }
 func (*IndexStatsResponse) ProtoMessage() {}
 func (*IndexStatsResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{40}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{43}
 }
 func (m *IndexStatsResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2256,7 +2429,7 @@ type VolumeRequest struct {
 func (m *VolumeRequest) Reset()      { *m = VolumeRequest{} }
 func (*VolumeRequest) ProtoMessage() {}
 func (*VolumeRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{41}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{44}
 }
 func (m *VolumeRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2328,7 +2501,7 @@ type VolumeResponse struct {
 func (m *VolumeResponse) Reset()      { *m = VolumeResponse{} }
 func (*VolumeResponse) ProtoMessage() {}
 func (*VolumeResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{42}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{45}
 }
 func (m *VolumeResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2379,7 +2552,7 @@ type Volume struct {
 func (m *Volume) Reset()      { *m = Volume{} }
 func (*Volume) ProtoMessage() {}
 func (*Volume) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{43}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{46}
 }
 func (m *Volume) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2427,6 +2600,9 @@ func init() {
 	proto.RegisterType((*StreamRatesRequest)(nil), "logproto.StreamRatesRequest")
 	proto.RegisterType((*StreamRatesResponse)(nil), "logproto.StreamRatesResponse")
 	proto.RegisterType((*StreamRate)(nil), "logproto.StreamRate")
+	proto.RegisterType((*OutOfOrderStatsRequest)(nil), "logproto.OutOfOrderStatsRequest")
+	proto.RegisterType((*OutOfOrderStatsResponse)(nil), "logproto.OutOfOrderStatsResponse")
+	proto.RegisterType((*StreamOutOfOrderStats)(nil), "logproto.StreamOutOfOrderStats")
 	proto.RegisterType((*QueryRequest)(nil), "logproto.QueryRequest")
 	proto.RegisterType((*SampleQueryRequest)(nil), "logproto.SampleQueryRequest")
 	proto.RegisterType((*Delete)(nil), "logproto.Delete")
@@ -2474,145 +2650,153 @@ func init() {
 func init() { proto.RegisterFile("pkg/logproto/logproto.proto", fileDescriptor_c28a5f14f1f4c79a) }
 
 var fileDescriptor_c28a5f14f1f4c79a = []byte{
-	// 2202 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xd4, 0x19, 0x4d, 0x8f, 0x1b, 0x49,
-	0xd5, 0x6d, 0xb7, 0xbf, 0x9e, 0x3d, 0x93, 0x49, 0x8d, 0x37, 0xb1, 0x9c, 0xc4, 0x9e, 0x94, 0x96,
-	0xec, 0x28, 0x9b, 0xb5, 0x37, 0xb3, 0xb0, 0x64, 0x13, 0x16, 0x88, 0x67, 0xf2, 0x31, 0xc9, 0xe4,
-	0x83, 0x9a, 0x10, 0xd0, 0x0a, 0x14, 0xf5, 0xd8, 0x65, 0xbb, 0x15, 0xb7, 0xdb, 0xe9, 0x2e, 0x6f,
-	0x32, 0x12, 0x07, 0xfe, 0xc0, 0x4a, 0x7b, 0x43, 0x5c, 0x10, 0x07, 0x24, 0x90, 0x10, 0x17, 0x7e,
-	0x00, 0x5c, 0x90, 0x08, 0xb7, 0x70, 0x5b, 0x71, 0x30, 0x64, 0x72, 0x41, 0x73, 0xda, 0x1b, 0x12,
-	0x07, 0x84, 0xea, 0xab, 0xbb, 0xec, 0xf1, 0xec, 0xae, 0x43, 0x24, 0x94, 0x8b, 0xbb, 0xde, 0xab,
-	0x57, 0xaf, 0xde, 0x77, 0xd5, 0x2b, 0xc3, 0x89, 0xe1, 0xc3, 0x6e, 0xa3, 0xef, 0x77, 0x87, 0x81,
-	0xcf, 0xfc, 0x68, 0x50, 0x17, 0xbf, 0x28, 0xa7, 0xe1, 0x4a, 0xa9, 0xeb, 0x77, 0x7d, 0x49, 0xc3,
-	0x47, 0x72, 0xbe, 0x52, 0xeb, 0xfa, 0x7e, 0xb7, 0x4f, 0x1b, 0x02, 0xda, 0x19, 0x75, 0x1a, 0xcc,
-	0xf5, 0x68, 0xc8, 0x1c, 0x6f, 0xa8, 0x08, 0x56, 0x14, 0xf7, 0x47, 0x7d, 0xcf, 0x6f, 0xd3, 0x7e,
-	0x23, 0x64, 0x0e, 0x0b, 0xe5, 0xaf, 0xa2, 0x58, 0xe6, 0x14, 0xc3, 0x51, 0xd8, 0x13, 0x3f, 0x12,
-	0x89, 0x4b, 0x80, 0xb6, 0x59, 0x40, 0x1d, 0x8f, 0x38, 0x8c, 0x86, 0x84, 0x3e, 0x1a, 0xd1, 0x90,
-	0xe1, 0x5b, 0xb0, 0x3c, 0x81, 0x0d, 0x87, 0xfe, 0x20, 0xa4, 0xe8, 0x7d, 0x28, 0x84, 0x31, 0xba,
-	0x6c, 0xad, 0xa4, 0x56, 0x0b, 0x6b, 0xa5, 0x7a, 0xa4, 0x4a, 0xbc, 0x86, 0x98, 0x84, 0xf8, 0x17,
-	0x16, 0x40, 0x3c, 0x87, 0xaa, 0x00, 0x72, 0xf6, 0xba, 0x13, 0xf6, 0xca, 0xd6, 0x8a, 0xb5, 0x6a,
-	0x13, 0x03, 0x83, 0xce, 0xc1, 0xd1, 0x18, 0xba, 0xed, 0x6f, 0xf7, 0x9c, 0xa0, 0x5d, 0x4e, 0x0a,
-	0xb2, 0x83, 0x13, 0x08, 0x81, 0x1d, 0x38, 0x8c, 0x96, 0x53, 0x2b, 0xd6, 0x6a, 0x8a, 0x88, 0x31,
-	0x3a, 0x06, 0x19, 0x46, 0x07, 0xce, 0x80, 0x95, 0xed, 0x15, 0x6b, 0x35, 0x4f, 0x14, 0xc4, 0xf1,
-	0x5c, 0x77, 0x1a, 0x96, 0xd3, 0x2b, 0xd6, 0xea, 0x02, 0x51, 0x10, 0xfe, 0x73, 0x12, 0x8a, 0xdf,
-	0x1b, 0xd1, 0x60, 0x57, 0x19, 0x00, 0x55, 0x20, 0x17, 0xd2, 0x3e, 0x6d, 0x31, 0x3f, 0x10, 0x02,
-	0xe6, 0x49, 0x04, 0xa3, 0x12, 0xa4, 0xfb, 0xae, 0xe7, 0x32, 0x21, 0xd2, 0x02, 0x91, 0x00, 0xba,
-	0x08, 0xe9, 0x90, 0x39, 0x01, 0x13, 0x72, 0x14, 0xd6, 0x2a, 0x75, 0xe9, 0xb0, 0xba, 0x76, 0x58,
-	0xfd, 0x9e, 0x76, 0x58, 0x33, 0xf7, 0x74, 0x5c, 0x4b, 0x7c, 0xfa, 0xf7, 0x9a, 0x45, 0xe4, 0x12,
-	0xf4, 0x3e, 0xa4, 0xe8, 0xa0, 0x2d, 0x64, 0xfd, 0xaa, 0x2b, 0xf9, 0x02, 0x74, 0x1e, 0xf2, 0x6d,
-	0x37, 0xa0, 0x2d, 0xe6, 0xfa, 0x03, 0xa1, 0xd1, 0xe2, 0xda, 0x72, 0xec, 0x8d, 0x0d, 0x3d, 0x45,
-	0x62, 0x2a, 0x74, 0x0e, 0x32, 0x21, 0x37, 0x5b, 0x58, 0xce, 0xae, 0xa4, 0x56, 0xf3, 0xcd, 0xd2,
-	0xfe, 0xb8, 0xb6, 0x24, 0x31, 0xe7, 0x7c, 0xcf, 0x65, 0xd4, 0x1b, 0xb2, 0x5d, 0xa2, 0x68, 0xd0,
-	0x59, 0xc8, 0xb6, 0x69, 0x9f, 0x72, 0x67, 0xe7, 0x84, 0xb3, 0x97, 0x0c, 0xf6, 0x62, 0x82, 0x68,
-	0x82, 0x1b, 0x76, 0x2e, 0xb3, 0x94, 0xc5, 0xff, 0xb1, 0x00, 0x6d, 0x3b, 0xde, 0xb0, 0x4f, 0xbf,
-	0xb2, 0x3d, 0x23, 0xcb, 0x25, 0x5f, 0xda, 0x72, 0xa9, 0x79, 0x2d, 0x17, 0x9b, 0xc1, 0x9e, 0xcf,
-	0x0c, 0xe9, 0x2f, 0x31, 0x03, 0xde, 0x82, 0x8c, 0x44, 0x7d, 0x59, 0x0c, 0xc5, 0x3a, 0xa7, 0xb4,
-	0x36, 0x4b, 0xb1, 0x36, 0x29, 0x21, 0x27, 0xfe, 0xa5, 0x05, 0x0b, 0xca, 0x90, 0x2a, 0x07, 0x77,
-	0x20, 0x2b, 0x73, 0x40, 0xe7, 0xdf, 0xf1, 0xe9, 0xfc, 0xbb, 0xdc, 0x76, 0x86, 0x8c, 0x06, 0xcd,
-	0xc6, 0xd3, 0x71, 0xcd, 0xfa, 0xdb, 0xb8, 0xf6, 0x56, 0xd7, 0x65, 0xbd, 0xd1, 0x4e, 0xbd, 0xe5,
-	0x7b, 0x8d, 0x6e, 0xe0, 0x74, 0x9c, 0x81, 0xd3, 0xe8, 0xfb, 0x0f, 0xdd, 0x86, 0xae, 0x07, 0x3a,
-	0x6f, 0x35, 0x63, 0xf4, 0xb6, 0x90, 0x8e, 0x85, 0xca, 0x23, 0x47, 0xea, 0xb2, 0x8c, 0x6c, 0x0e,
-	0xba, 0x34, 0xe4, 0x9c, 0x6d, 0x6e, 0x4c, 0x22, 0x69, 0xf0, 0x4f, 0x60, 0x79, 0xc2, 0xe1, 0x4a,
-	0xce, 0x0b, 0x90, 0x09, 0x69, 0xe0, 0x46, 0x65, 0xc2, 0x30, 0xd9, 0xb6, 0xc0, 0x37, 0x17, 0x95,
-	0x7c, 0x19, 0x09, 0x13, 0x45, 0x3f, 0xdf, 0xee, 0x7f, 0xb2, 0xa0, 0xb8, 0xe5, 0xec, 0xd0, 0xbe,
-	0x8e, 0x34, 0x04, 0xf6, 0xc0, 0xf1, 0xa8, 0xb2, 0xb8, 0x18, 0xf3, 0xb4, 0xff, 0xd8, 0xe9, 0x8f,
-	0xa8, 0x64, 0x99, 0x23, 0x0a, 0x9a, 0x37, 0x67, 0xad, 0x97, 0xce, 0x59, 0x2b, 0x8e, 0xbc, 0x12,
-	0xa4, 0x1f, 0x71, 0x43, 0x89, 0x7c, 0xcd, 0x13, 0x09, 0xe0, 0xb7, 0x60, 0x41, 0x69, 0xa1, 0xcc,
-	0x17, 0x8b, 0xcc, 0xcd, 0x97, 0xd7, 0x22, 0x63, 0x0f, 0x32, 0xd2, 0xda, 0xe8, 0x4d, 0xc8, 0x47,
-	0x67, 0x80, 0xd0, 0x36, 0xd5, 0xcc, 0xec, 0x8f, 0x6b, 0x49, 0x16, 0x92, 0x78, 0x02, 0xd5, 0x20,
-	0x2d, 0x56, 0x0a, 0xcd, 0xad, 0x66, 0x7e, 0x7f, 0x5c, 0x93, 0x08, 0x22, 0x3f, 0xe8, 0x24, 0xd8,
-	0x3d, 0x5e, 0x86, 0xb9, 0x09, 0xec, 0x66, 0x6e, 0x7f, 0x5c, 0x13, 0x30, 0x11, 0xbf, 0xf8, 0x1a,
-	0x14, 0xb7, 0x68, 0xd7, 0x69, 0xed, 0xaa, 0x4d, 0x4b, 0x9a, 0x1d, 0xdf, 0xd0, 0xd2, 0x3c, 0x4e,
-	0x43, 0x31, 0xda, 0xf1, 0x81, 0x17, 0xaa, 0xa0, 0x2e, 0x44, 0xb8, 0x5b, 0x21, 0xfe, 0xb9, 0x05,
-	0xca, 0xcf, 0x08, 0x43, 0xa6, 0xcf, 0x75, 0x0d, 0xa5, 0x8f, 0x9a, 0xb0, 0x3f, 0xae, 0x29, 0x0c,
-	0x51, 0x5f, 0x74, 0x09, 0xb2, 0xa1, 0xd8, 0x91, 0x33, 0x9b, 0x0e, 0x1f, 0x31, 0xd1, 0x3c, 0xc2,
-	0xc3, 0x60, 0x7f, 0x5c, 0xd3, 0x84, 0x44, 0x0f, 0x50, 0x7d, 0xe2, 0x7c, 0x91, 0x8a, 0x2d, 0xee,
-	0x8f, 0x6b, 0x06, 0xd6, 0x3c, 0x6f, 0xf0, 0xcf, 0x2c, 0x28, 0xdc, 0x73, 0xdc, 0x28, 0x84, 0x22,
-	0x17, 0x59, 0x86, 0x8b, 0x78, 0x3a, 0xb7, 0x69, 0xdf, 0xd9, 0xbd, 0xea, 0x07, 0x82, 0xe7, 0x02,
-	0x89, 0xe0, 0xf8, 0x48, 0xb0, 0x67, 0x1e, 0x09, 0xe9, 0xb9, 0x0b, 0xdb, 0x0d, 0x3b, 0x97, 0x5c,
-	0x4a, 0xe1, 0xdf, 0x59, 0x50, 0x94, 0x92, 0xa9, 0xb0, 0xf8, 0x11, 0x64, 0xa4, 0xe0, 0x42, 0xb6,
-	0x2f, 0x48, 0xfe, 0xb7, 0xe7, 0x49, 0x7c, 0xc5, 0x13, 0x7d, 0x07, 0x16, 0xdb, 0x81, 0x3f, 0x1c,
-	0xd2, 0xf6, 0xb6, 0x2a, 0x31, 0xc9, 0xe9, 0x12, 0xb3, 0x61, 0xce, 0x93, 0x29, 0x72, 0xfc, 0x17,
-	0x0b, 0x16, 0x54, 0x36, 0x2b, 0x5b, 0x46, 0x36, 0xb0, 0x5e, 0xba, 0xb8, 0x27, 0xe7, 0x2d, 0xee,
-	0xc7, 0x20, 0xd3, 0x0d, 0xfc, 0xd1, 0x30, 0x2c, 0xa7, 0x64, 0xee, 0x48, 0x68, 0xbe, 0xa2, 0x8f,
-	0x6f, 0xc0, 0xa2, 0x56, 0xe5, 0x90, 0x92, 0x56, 0x99, 0x2e, 0x69, 0x9b, 0x6d, 0x3a, 0x60, 0x6e,
-	0xc7, 0x8d, 0x8a, 0x94, 0xa2, 0xc7, 0x9f, 0x58, 0xb0, 0x34, 0x4d, 0x82, 0xbe, 0x6d, 0xe4, 0x01,
-	0x67, 0x77, 0xe6, 0x70, 0x76, 0x75, 0x51, 0x1c, 0xc2, 0x2b, 0x03, 0x16, 0xec, 0xea, 0x1c, 0xa9,
-	0x7c, 0x00, 0x05, 0x03, 0xcd, 0x0f, 0x8f, 0x87, 0x54, 0xc7, 0x2c, 0x1f, 0xc6, 0xc9, 0x9a, 0x94,
-	0x71, 0x2c, 0x80, 0x8b, 0xc9, 0x0b, 0x16, 0x8f, 0xf8, 0x85, 0x09, 0x4f, 0xa2, 0x0b, 0x60, 0x77,
-	0x02, 0xdf, 0x9b, 0xcb, 0x4d, 0x62, 0x05, 0xfa, 0x3a, 0x24, 0x99, 0x3f, 0x97, 0x93, 0x92, 0xcc,
-	0xe7, 0x3e, 0x52, 0xca, 0xa7, 0xe4, 0x0d, 0x4d, 0x42, 0xf8, 0x1b, 0x90, 0x17, 0x4a, 0xdd, 0x75,
-	0xdc, 0x60, 0x66, 0x2d, 0x9f, 0xa9, 0x14, 0xbe, 0x04, 0x47, 0x64, 0x9d, 0x9a, 0xbd, 0xb8, 0x38,
-	0x6b, 0x71, 0x51, 0x2f, 0x3e, 0x01, 0xe9, 0xf5, 0xde, 0x68, 0xf0, 0x90, 0x2f, 0x69, 0x3b, 0xcc,
-	0xd1, 0x4b, 0xf8, 0x18, 0xbf, 0x01, 0xcb, 0x3c, 0x03, 0x69, 0x10, 0xae, 0xfb, 0xa3, 0x01, 0xd3,
-	0x37, 0xe4, 0x73, 0x50, 0x9a, 0x44, 0xab, 0x18, 0x29, 0x41, 0xba, 0xc5, 0x11, 0x82, 0xc7, 0x02,
-	0x91, 0x00, 0xfe, 0x95, 0x05, 0xe8, 0x1a, 0x65, 0x62, 0x97, 0xcd, 0x8d, 0xd0, 0xb8, 0x15, 0x79,
-	0x0e, 0x6b, 0xf5, 0x68, 0x10, 0xea, 0x1b, 0x82, 0x86, 0xff, 0x1f, 0xb7, 0x22, 0x7c, 0x1e, 0x96,
-	0x27, 0xa4, 0x54, 0x3a, 0x55, 0x20, 0xd7, 0x52, 0x38, 0x75, 0x1a, 0x45, 0x30, 0xfe, 0x7d, 0x12,
-	0x72, 0x62, 0x01, 0xa1, 0x1d, 0x74, 0x1e, 0x0a, 0x1d, 0x77, 0xd0, 0xa5, 0xc1, 0x30, 0x70, 0x95,
-	0x09, 0xec, 0xe6, 0x91, 0xfd, 0x71, 0xcd, 0x44, 0x13, 0x13, 0x40, 0xef, 0x40, 0x76, 0x14, 0xd2,
-	0xe0, 0x81, 0x2b, 0xf3, 0x3c, 0xdf, 0x2c, 0xed, 0x8d, 0x6b, 0x99, 0xef, 0x87, 0x34, 0xd8, 0xdc,
-	0xe0, 0xe7, 0xc2, 0x48, 0x8c, 0x88, 0xfc, 0xb6, 0xd1, 0x4d, 0x15, 0xa6, 0xe2, 0x8a, 0xd4, 0xfc,
-	0x26, 0x17, 0x7f, 0xaa, 0xd0, 0x0d, 0x03, 0xdf, 0xa3, 0xac, 0x47, 0x47, 0x61, 0xa3, 0xe5, 0x7b,
-	0x9e, 0x3f, 0x68, 0x88, 0x7e, 0x48, 0x28, 0xcd, 0x0f, 0x37, 0xbe, 0x5c, 0x45, 0xee, 0x3d, 0xc8,
-	0xb2, 0x5e, 0xe0, 0x8f, 0xba, 0x3d, 0x51, 0xb7, 0x53, 0xcd, 0x8b, 0xf3, 0xf3, 0xd3, 0x1c, 0x88,
-	0x1e, 0xa0, 0xd3, 0xdc, 0x5a, 0xb4, 0xf5, 0x30, 0x1c, 0x79, 0xb2, 0xcb, 0x68, 0xa6, 0xf7, 0xc7,
-	0x35, 0xeb, 0x1d, 0x12, 0xa1, 0xf1, 0x27, 0x49, 0xa8, 0x89, 0x40, 0xbd, 0x2f, 0x0e, 0xf5, 0xab,
-	0x7e, 0x70, 0x8b, 0xb2, 0xc0, 0x6d, 0xdd, 0x76, 0x3c, 0xaa, 0x63, 0xa3, 0x06, 0x05, 0x4f, 0x20,
-	0x1f, 0x18, 0x29, 0x00, 0x5e, 0x44, 0x87, 0x4e, 0x01, 0x88, 0x9c, 0x91, 0xf3, 0x32, 0x1b, 0xf2,
-	0x02, 0x23, 0xa6, 0xd7, 0x27, 0x2c, 0xd5, 0x98, 0x53, 0x33, 0x65, 0xa1, 0xcd, 0x69, 0x0b, 0xcd,
-	0xcd, 0x27, 0x32, 0x8b, 0x19, 0xeb, 0xe9, 0xc9, 0x58, 0xc7, 0x7f, 0xb5, 0xa0, 0xba, 0xa5, 0x25,
-	0x7f, 0x49, 0x73, 0x68, 0x7d, 0x93, 0xaf, 0x48, 0xdf, 0xd4, 0xff, 0xa6, 0x2f, 0xbe, 0x0e, 0xa5,
-	0x2d, 0x77, 0x40, 0xaf, 0xba, 0x7d, 0x46, 0x83, 0x2b, 0x4f, 0x86, 0x01, 0x0d, 0x43, 0xde, 0x80,
-	0x55, 0x20, 0xe7, 0x0f, 0x69, 0xe0, 0xe8, 0xae, 0x20, 0x45, 0x22, 0x98, 0x17, 0x0f, 0x61, 0x13,
-	0x5d, 0xdb, 0x04, 0x80, 0xff, 0x6d, 0x14, 0x0f, 0x42, 0x3b, 0xda, 0x22, 0xeb, 0x46, 0xc5, 0x7e,
-	0x15, 0x0a, 0x27, 0x5f, 0xa1, 0x83, 0x53, 0x53, 0xc5, 0xec, 0x02, 0x64, 0x3b, 0xc2, 0x10, 0xf2,
-	0xe8, 0x2d, 0xac, 0x55, 0xe3, 0xb3, 0x6e, 0x96, 0x95, 0x88, 0x26, 0xc7, 0x1f, 0xc6, 0x25, 0x49,
-	0xe8, 0xae, 0x4a, 0xd2, 0x19, 0xb0, 0x03, 0xda, 0xd1, 0x27, 0x27, 0x8a, 0xb9, 0x45, 0x94, 0x62,
-	0x1e, 0xff, 0xc1, 0x82, 0xa5, 0x6b, 0x94, 0x4d, 0xde, 0x49, 0x5e, 0x23, 0xcb, 0xe1, 0xeb, 0x70,
-	0xd4, 0x90, 0x5f, 0x69, 0xff, 0xde, 0xd4, 0x45, 0xe4, 0x8d, 0x58, 0xff, 0xcd, 0x41, 0x9b, 0x3e,
-	0x51, 0x0d, 0xd6, 0xe4, 0x1d, 0xe4, 0x2e, 0x14, 0x8c, 0x49, 0x74, 0x79, 0xea, 0xf6, 0x61, 0x3c,
-	0x1c, 0x44, 0x67, 0x68, 0xb3, 0xa4, 0x74, 0x92, 0x2d, 0x96, 0xba, 0x5b, 0x46, 0x67, 0xf5, 0x36,
-	0x20, 0xd1, 0xf3, 0x09, 0xb6, 0xe6, 0x69, 0x21, 0xb0, 0x37, 0xa3, 0xcb, 0x48, 0x04, 0xa3, 0xd3,
-	0x60, 0x07, 0xfe, 0x63, 0x7d, 0xad, 0x5c, 0x88, 0xb7, 0x24, 0xfe, 0x63, 0x22, 0xa6, 0xf0, 0x25,
-	0x48, 0x11, 0xff, 0x31, 0xaa, 0x02, 0x04, 0xce, 0xa0, 0x4b, 0xef, 0x47, 0xdd, 0x46, 0x91, 0x18,
-	0x98, 0x43, 0x4e, 0xf2, 0x75, 0x38, 0x6a, 0x4a, 0x24, 0xdd, 0x5d, 0x87, 0x2c, 0x47, 0xba, 0xb3,
-	0x5e, 0xac, 0x04, 0xa1, 0x6c, 0x5c, 0x35, 0x11, 0x8f, 0x19, 0x88, 0xf1, 0xe8, 0x24, 0xe4, 0x99,
-	0xb3, 0xd3, 0xa7, 0xb7, 0xe3, 0xba, 0x13, 0x23, 0xf8, 0x2c, 0x6f, 0x94, 0xee, 0x1b, 0x57, 0x92,
-	0x18, 0x81, 0xce, 0xc2, 0x52, 0x2c, 0xf3, 0xdd, 0x80, 0x76, 0xdc, 0x27, 0xc2, 0xc3, 0x45, 0x72,
-	0x00, 0x8f, 0x56, 0xe1, 0x48, 0x8c, 0xdb, 0x16, 0x47, 0xbf, 0x2d, 0x48, 0xa7, 0xd1, 0xdc, 0x36,
-	0x42, 0xdd, 0x2b, 0x8f, 0x46, 0x4e, 0x5f, 0x14, 0xd3, 0x22, 0x31, 0x30, 0xf8, 0x8f, 0x16, 0x1c,
-	0x95, 0xae, 0xe6, 0x2d, 0xf2, 0xeb, 0x18, 0xf5, 0xbf, 0xb6, 0x00, 0x99, 0x1a, 0xa8, 0xd0, 0xfa,
-	0x9a, 0xf9, 0xf6, 0xc1, 0xef, 0x16, 0x05, 0xd1, 0xff, 0x49, 0x54, 0xfc, 0x7c, 0x81, 0x21, 0x23,
-	0xee, 0x27, 0xb2, 0x11, 0xb5, 0x65, 0x83, 0x29, 0x31, 0x44, 0x7d, 0x79, 0x5f, 0xbc, 0xb3, 0xcb,
-	0x68, 0xa8, 0xda, 0x43, 0xd1, 0x17, 0x0b, 0x04, 0x91, 0x1f, 0xbe, 0x17, 0x1d, 0x30, 0x11, 0x35,
-	0x76, 0xbc, 0x97, 0x42, 0x11, 0x3d, 0xc0, 0xbf, 0x4d, 0xc2, 0xc2, 0x7d, 0xbf, 0x3f, 0x8a, 0x4f,
-	0xaa, 0xd7, 0xa9, 0x2e, 0x4f, 0xf4, 0xad, 0x69, 0xdd, 0xb7, 0x22, 0xb0, 0x43, 0x46, 0x87, 0x22,
-	0xb2, 0x52, 0x44, 0x8c, 0x11, 0x86, 0x22, 0x73, 0x82, 0x2e, 0x65, 0xb2, 0xe5, 0x28, 0x67, 0xc4,
-	0x3d, 0x70, 0x02, 0x87, 0x56, 0xa0, 0xe0, 0x74, 0xbb, 0x01, 0xed, 0x3a, 0x8c, 0x36, 0x77, 0xcb,
-	0x59, 0xb1, 0x99, 0x89, 0xc2, 0x3f, 0x84, 0x45, 0x6d, 0x2c, 0xe5, 0xd2, 0x77, 0x21, 0xfb, 0xb1,
-	0xc0, 0xcc, 0x78, 0x27, 0x92, 0xa4, 0xaa, 0x8c, 0x69, 0xb2, 0xc9, 0xe7, 0x57, 0x2d, 0x33, 0xbe,
-	0x01, 0x19, 0x49, 0x8e, 0x4e, 0x9a, 0x4d, 0x83, 0x7c, 0xd0, 0xe0, 0xb0, 0xea, 0x00, 0x30, 0x64,
-	0x24, 0x23, 0xe5, 0x78, 0x11, 0x1b, 0x12, 0x43, 0xd4, 0xf7, 0xec, 0x19, 0xc8, 0x47, 0x6f, 0xa7,
-	0xa8, 0x00, 0xd9, 0xab, 0x77, 0xc8, 0x0f, 0x2e, 0x93, 0x8d, 0xa5, 0x04, 0x2a, 0x42, 0xae, 0x79,
-	0x79, 0xfd, 0xa6, 0x80, 0xac, 0xb5, 0x7f, 0xd9, 0xba, 0xb2, 0x04, 0xe8, 0x5b, 0x90, 0x96, 0xe5,
-	0xe2, 0x58, 0x2c, 0xbf, 0xf9, 0x02, 0x5a, 0x39, 0x7e, 0x00, 0x2f, 0x2d, 0x80, 0x13, 0xef, 0x5a,
-	0xe8, 0x36, 0x14, 0x04, 0x52, 0xbd, 0xb2, 0x9c, 0x9c, 0x7e, 0xec, 0x98, 0xe0, 0x74, 0xea, 0x90,
-	0x59, 0x83, 0xdf, 0x45, 0x48, 0x0b, 0x9f, 0x98, 0xd2, 0x98, 0xaf, 0x64, 0xa6, 0x34, 0x13, 0xef,
-	0x4e, 0x38, 0x81, 0x3e, 0x00, 0x9b, 0x77, 0x36, 0xc8, 0x38, 0x54, 0x8c, 0xc7, 0x91, 0xca, 0xb1,
-	0x69, 0xb4, 0xb1, 0xed, 0x87, 0xd1, 0x1b, 0xcf, 0xf1, 0xe9, 0x5e, 0x56, 0x2f, 0x2f, 0x1f, 0x9c,
-	0x88, 0x76, 0xbe, 0x23, 0x1f, 0x3b, 0x74, 0x4f, 0x85, 0x4e, 0x4d, 0x6e, 0x35, 0xd5, 0x82, 0x55,
-	0xaa, 0x87, 0x4d, 0x47, 0x0c, 0xb7, 0xa0, 0x60, 0xf4, 0x33, 0xa6, 0x59, 0x0f, 0x36, 0x63, 0xa6,
-	0x59, 0x67, 0x34, 0x41, 0x38, 0x81, 0xae, 0x41, 0x8e, 0x1f, 0xc5, 0xbc, 0x22, 0xa1, 0x13, 0xd3,
-	0x27, 0xae, 0x51, 0x69, 0x2b, 0x27, 0x67, 0x4f, 0x46, 0x8c, 0xbe, 0x0b, 0xf9, 0x6b, 0x94, 0xa9,
-	0x70, 0x3d, 0x3e, 0x1d, 0xef, 0x33, 0x2c, 0x35, 0x99, 0x33, 0x38, 0xb1, 0xf6, 0x63, 0xfd, 0x7f,
-	0xca, 0x86, 0xc3, 0x1c, 0x74, 0x07, 0x16, 0x85, 0x60, 0xd1, 0x1f, 0x2e, 0x13, 0x01, 0x74, 0xe0,
-	0xdf, 0x9d, 0x89, 0x00, 0x3a, 0xf8, 0x2f, 0x0f, 0x4e, 0x34, 0x3f, 0x7a, 0xf6, 0xbc, 0x9a, 0xf8,
-	0xec, 0x79, 0x35, 0xf1, 0xf9, 0xf3, 0xaa, 0xf5, 0xd3, 0xbd, 0xaa, 0xf5, 0x9b, 0xbd, 0xaa, 0xf5,
-	0x74, 0xaf, 0x6a, 0x3d, 0xdb, 0xab, 0x5a, 0xff, 0xd8, 0xab, 0x5a, 0xff, 0xdc, 0xab, 0x26, 0x3e,
-	0xdf, 0xab, 0x5a, 0x9f, 0xbe, 0xa8, 0x26, 0x9e, 0xbd, 0xa8, 0x26, 0x3e, 0x7b, 0x51, 0x4d, 0x7c,
-	0xf4, 0xe6, 0x17, 0x3d, 0x37, 0xe9, 0x1d, 0x77, 0x32, 0xe2, 0xf3, 0xde, 0x7f, 0x03, 0x00, 0x00,
-	0xff, 0xff, 0xc7, 0xff, 0x87, 0xf1, 0x0e, 0x1b, 0x00, 0x00,
+	// 2330 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xd4, 0x59, 0xcd, 0x8f, 0x1b, 0x49,
+	0x15, 0x9f, 0xf6, 0xb7, 0x9f, 0xed, 0xc9, 0xa4, 0xc6, 0xc9, 0x58, 0x4e, 0x62, 0x4f, 0x4a, 0x21,
+	0x3b, 0xca, 0x66, 0xed, 0xcd, 0x2c, 0x2c, 0xf9, 0x60, 0x81, 0x78, 0x26, 0x1f, 0x93, 0x4c, 0x32,
+	0xd9, 0x9a, 0x10, 0xd0, 0x82, 0x14, 0xf5, 0xd8, 0x65, 0x8f, 0x19, 0xb7, 0xdb, 0xe9, 0x2e, 0x6f,
+	0x32, 0x12, 0x07, 0xfe, 0x81, 0x95, 0xf6, 0x86, 0xb8, 0x00, 0x07, 0x24, 0x90, 0x10, 0x17, 0x8e,
+	0x1c, 0xe0, 0x82, 0x44, 0xb8, 0x85, 0xdb, 0x8a, 0x83, 0x21, 0x93, 0x0b, 0x9a, 0xd3, 0xde, 0x90,
+	0x38, 0x20, 0x54, 0x5f, 0xdd, 0xe5, 0x1e, 0x4f, 0x16, 0x87, 0x48, 0xab, 0x5c, 0xec, 0x7a, 0xbf,
+	0x7a, 0xf5, 0xf1, 0x7e, 0xf5, 0xea, 0x55, 0xd5, 0x6b, 0x38, 0x31, 0xd8, 0xe9, 0xd4, 0x7b, 0x6e,
+	0x67, 0xe0, 0xb9, 0xcc, 0x0d, 0x0a, 0x35, 0xf1, 0x8b, 0x32, 0x5a, 0x2e, 0x17, 0x3b, 0x6e, 0xc7,
+	0x95, 0x3a, 0xbc, 0x24, 0xeb, 0xcb, 0xd5, 0x8e, 0xeb, 0x76, 0x7a, 0xb4, 0x2e, 0xa4, 0xad, 0x61,
+	0xbb, 0xce, 0xba, 0x0e, 0xf5, 0x99, 0xed, 0x0c, 0x94, 0xc2, 0xa2, 0xea, 0xfd, 0x51, 0xcf, 0x71,
+	0x5b, 0xb4, 0x57, 0xf7, 0x99, 0xcd, 0x7c, 0xf9, 0xab, 0x34, 0xe6, 0xb9, 0xc6, 0x60, 0xe8, 0x6f,
+	0x8b, 0x1f, 0x09, 0xe2, 0x22, 0xa0, 0x4d, 0xe6, 0x51, 0xdb, 0x21, 0x36, 0xa3, 0x3e, 0xa1, 0x8f,
+	0x86, 0xd4, 0x67, 0xf8, 0x0e, 0xcc, 0x8f, 0xa1, 0xfe, 0xc0, 0xed, 0xfb, 0x14, 0xbd, 0x0f, 0x39,
+	0x3f, 0x84, 0x4b, 0xd6, 0x62, 0x7c, 0x29, 0xb7, 0x5c, 0xac, 0x05, 0xa6, 0x84, 0x6d, 0x88, 0xa9,
+	0x88, 0x7f, 0x66, 0x01, 0x84, 0x75, 0xa8, 0x02, 0x20, 0x6b, 0x6f, 0xda, 0xfe, 0x76, 0xc9, 0x5a,
+	0xb4, 0x96, 0x12, 0xc4, 0x40, 0xd0, 0x79, 0x38, 0x1a, 0x4a, 0x77, 0xdd, 0xcd, 0x6d, 0xdb, 0x6b,
+	0x95, 0x62, 0x42, 0xed, 0x60, 0x05, 0x42, 0x90, 0xf0, 0x6c, 0x46, 0x4b, 0xf1, 0x45, 0x6b, 0x29,
+	0x4e, 0x44, 0x19, 0x1d, 0x87, 0x14, 0xa3, 0x7d, 0xbb, 0xcf, 0x4a, 0x89, 0x45, 0x6b, 0x29, 0x4b,
+	0x94, 0xc4, 0x71, 0x6e, 0x3b, 0xf5, 0x4b, 0xc9, 0x45, 0x6b, 0xa9, 0x40, 0x94, 0x84, 0x4b, 0x70,
+	0x7c, 0x63, 0xc8, 0x36, 0xda, 0x1b, 0x5e, 0x8b, 0x7a, 0x9b, 0x9c, 0x33, 0xcd, 0xc4, 0x7d, 0x58,
+	0x38, 0x50, 0xa3, 0xd8, 0xb8, 0x04, 0x69, 0x39, 0x1b, 0xcd, 0x44, 0x35, 0xca, 0x44, 0xb4, 0xa5,
+	0xd6, 0xc7, 0x3f, 0x8f, 0xc1, 0xb1, 0x89, 0x2a, 0x7c, 0x86, 0x3d, 0x7b, 0x8b, 0xf6, 0x7c, 0xc1,
+	0x4b, 0x96, 0x28, 0x89, 0x73, 0xe2, 0x06, 0xaa, 0xd7, 0xfa, 0xcc, 0xeb, 0x52, 0x5f, 0x73, 0x72,
+	0xa0, 0x02, 0x2d, 0xc1, 0x91, 0x10, 0x6c, 0xec, 0xf2, 0xc5, 0x8a, 0x0b, 0xdd, 0x28, 0x8c, 0x16,
+	0x21, 0xe7, 0xd8, 0x4f, 0x56, 0xbb, 0x3e, 0xb3, 0xfb, 0x4d, 0x2a, 0xe8, 0x8a, 0x13, 0x13, 0x42,
+	0x67, 0xa0, 0xc0, 0x5c, 0x66, 0xf7, 0x02, 0x9d, 0xa4, 0xd0, 0x19, 0x07, 0xf9, 0x88, 0x1e, 0xfd,
+	0x21, 0x6d, 0x32, 0xda, 0xd2, 0xb3, 0x4b, 0xc9, 0x11, 0x23, 0x30, 0xef, 0x4f, 0x43, 0x72, 0x66,
+	0x69, 0xa1, 0x37, 0x0e, 0xe2, 0x3f, 0xc7, 0x20, 0xff, 0xe1, 0x90, 0x7a, 0xbb, 0x6a, 0x21, 0x50,
+	0x19, 0x32, 0x3e, 0xed, 0xd1, 0x26, 0x73, 0x3d, 0x45, 0x4d, 0x20, 0xa3, 0x22, 0x24, 0x7b, 0x5d,
+	0xa7, 0xcb, 0x04, 0x21, 0x05, 0x22, 0x05, 0x74, 0x19, 0x92, 0x3e, 0xb3, 0x3d, 0x26, 0x4c, 0xcf,
+	0x2d, 0x97, 0x6b, 0x72, 0x0b, 0xd5, 0xf4, 0x16, 0xaa, 0xdd, 0xd7, 0x5b, 0xa8, 0x91, 0x79, 0x3a,
+	0xaa, 0xce, 0x7c, 0xfa, 0xf7, 0xaa, 0x45, 0x64, 0x13, 0xf4, 0x3e, 0xc4, 0x69, 0xbf, 0x55, 0x4a,
+	0x4c, 0xd1, 0x92, 0x37, 0x40, 0x17, 0x20, 0xdb, 0xea, 0x7a, 0xb4, 0xc9, 0xba, 0x6e, 0x5f, 0x10,
+	0x35, 0xbb, 0x3c, 0x1f, 0x7a, 0xc5, 0xaa, 0xae, 0x22, 0xa1, 0x16, 0x3a, 0x0f, 0x29, 0x9f, 0x3b,
+	0x32, 0x27, 0x22, 0xbe, 0x94, 0x6d, 0x14, 0xf7, 0x47, 0xd5, 0x39, 0x89, 0x9c, 0x77, 0x9d, 0x2e,
+	0xa3, 0xce, 0x80, 0xed, 0x12, 0xa5, 0x83, 0xce, 0x41, 0xba, 0x45, 0x7b, 0x94, 0xf3, 0x96, 0x11,
+	0x4e, 0x37, 0x67, 0x74, 0x2f, 0x2a, 0x88, 0x56, 0xb8, 0x95, 0xc8, 0xa4, 0xe6, 0xd2, 0xf8, 0x3f,
+	0x16, 0xa0, 0x4d, 0xdb, 0x19, 0xf4, 0xe8, 0xff, 0xcc, 0x67, 0xc0, 0x5c, 0xec, 0x95, 0x99, 0x8b,
+	0x4f, 0xcb, 0x5c, 0x48, 0x43, 0x62, 0x3a, 0x1a, 0x92, 0x5f, 0x40, 0x03, 0x5e, 0x87, 0x94, 0x84,
+	0xbe, 0xc8, 0x87, 0x42, 0x9b, 0xe3, 0xda, 0x9a, 0xb9, 0xd0, 0x9a, 0xb8, 0x98, 0x27, 0xfe, 0x85,
+	0x05, 0x05, 0x45, 0xa4, 0x8a, 0x03, 0x5b, 0xd1, 0x38, 0xb0, 0x10, 0x8d, 0x03, 0x57, 0x5b, 0xf6,
+	0x80, 0x51, 0xaf, 0x51, 0x7f, 0x3a, 0xaa, 0x5a, 0x7f, 0x1b, 0x55, 0xdf, 0xea, 0x74, 0xd9, 0xf6,
+	0x70, 0xab, 0xd6, 0x74, 0x9d, 0x7a, 0xc7, 0xb3, 0xdb, 0x76, 0xdf, 0xae, 0xf7, 0xdc, 0x9d, 0x6e,
+	0x5d, 0x47, 0x68, 0xd5, 0x2e, 0x08, 0x18, 0xe8, 0x6d, 0x31, 0x3b, 0xe6, 0xab, 0x15, 0x39, 0x52,
+	0x13, 0x52, 0x6d, 0xad, 0xdf, 0xa1, 0x3e, 0xef, 0x39, 0xc1, 0xc9, 0x24, 0x52, 0x07, 0xff, 0x08,
+	0xe6, 0xc7, 0x16, 0x5c, 0xcd, 0xf3, 0x22, 0xa4, 0x7c, 0x2a, 0x76, 0xa6, 0x15, 0xa5, 0x6c, 0x53,
+	0xe0, 0x8d, 0x59, 0x35, 0xbf, 0x94, 0x94, 0x89, 0xd2, 0x9f, 0x6e, 0xf4, 0x3f, 0x59, 0x90, 0x5f,
+	0xe7, 0x41, 0x4b, 0x7b, 0x1a, 0x82, 0x44, 0xdf, 0x76, 0xa8, 0x62, 0x5c, 0x94, 0x79, 0x98, 0xfb,
+	0xd8, 0xee, 0x0d, 0x55, 0x0c, 0xcb, 0x10, 0x25, 0x4d, 0xbb, 0x67, 0xad, 0x57, 0xde, 0xb3, 0x56,
+	0xe8, 0x79, 0x45, 0x48, 0x3e, 0xe2, 0x44, 0x89, 0xfd, 0x9a, 0x25, 0x52, 0xc0, 0x6f, 0x41, 0x41,
+	0x59, 0xa1, 0xe8, 0x0b, 0xa7, 0xcc, 0xe9, 0xcb, 0xea, 0x29, 0x63, 0x07, 0x52, 0x92, 0x6d, 0x74,
+	0x06, 0xb2, 0xc1, 0xa9, 0x2c, 0xac, 0x8d, 0x37, 0x52, 0xfb, 0xa3, 0x6a, 0x8c, 0xf9, 0x24, 0xac,
+	0x40, 0x55, 0x48, 0x8a, 0x96, 0xc2, 0x72, 0xab, 0x91, 0xdd, 0x1f, 0x55, 0x25, 0x40, 0xe4, 0x1f,
+	0x3a, 0x09, 0x89, 0x6d, 0x7e, 0x30, 0x8a, 0x88, 0xdd, 0xc8, 0xec, 0x8f, 0xaa, 0x42, 0x26, 0xe2,
+	0x17, 0xdf, 0x80, 0xfc, 0x3a, 0xed, 0xd8, 0xcd, 0x5d, 0x35, 0x68, 0x51, 0x77, 0xc7, 0x07, 0xb4,
+	0x74, 0x1f, 0xa7, 0x21, 0x1f, 0x8c, 0xf8, 0xd0, 0xf1, 0x95, 0x53, 0xe7, 0x02, 0xec, 0x8e, 0x8f,
+	0x7f, 0x6a, 0x81, 0x5a, 0x67, 0x84, 0xc7, 0x0f, 0x9d, 0x06, 0xec, 0x8f, 0xaa, 0x0a, 0x09, 0x0e,
+	0xa0, 0x2b, 0x90, 0xf6, 0xc5, 0x88, 0xbc, 0xb3, 0xa8, 0xfb, 0x88, 0x8a, 0xc6, 0x11, 0xee, 0x06,
+	0xfb, 0xa3, 0xaa, 0x56, 0x24, 0xba, 0x80, 0x6a, 0x63, 0x27, 0xbe, 0x34, 0x6c, 0x76, 0x7f, 0x54,
+	0x35, 0x50, 0xf3, 0x06, 0x80, 0x7f, 0x62, 0x41, 0xee, 0xbe, 0xdd, 0x0d, 0x5c, 0x28, 0x58, 0x22,
+	0xcb, 0x58, 0x22, 0xbe, 0x9d, 0x5b, 0xb4, 0x67, 0xef, 0x5e, 0x77, 0x3d, 0xd1, 0x67, 0x81, 0x04,
+	0x72, 0x78, 0x24, 0x24, 0x26, 0x1e, 0x09, 0xc9, 0xa9, 0x03, 0xdb, 0xad, 0x44, 0x26, 0x36, 0x17,
+	0xc7, 0xbf, 0xb5, 0x20, 0x2f, 0x67, 0xa6, 0xdc, 0xe2, 0x07, 0x90, 0x92, 0x13, 0x17, 0x73, 0x7b,
+	0xc9, 0xe6, 0x7f, 0x7b, 0x9a, 0x8d, 0xaf, 0xfa, 0x44, 0xdf, 0x82, 0xd9, 0x96, 0xe7, 0x0e, 0x06,
+	0xb4, 0xb5, 0xa9, 0x42, 0x4c, 0x2c, 0x1a, 0x62, 0x56, 0xcd, 0x7a, 0x12, 0x51, 0xc7, 0x7f, 0xb1,
+	0xa0, 0xa0, 0x76, 0xb3, 0xe2, 0x32, 0xe0, 0xc0, 0x7a, 0xe5, 0xe0, 0x1e, 0x9b, 0x36, 0xb8, 0x1f,
+	0x87, 0x54, 0xc7, 0x73, 0x87, 0x03, 0x7e, 0x0d, 0x11, 0x7b, 0x47, 0x4a, 0xd3, 0x05, 0x7d, 0x7c,
+	0x0b, 0x66, 0xb5, 0x29, 0x87, 0x84, 0xb4, 0x72, 0x34, 0xa4, 0xad, 0xb5, 0x68, 0x9f, 0x75, 0xdb,
+	0xdd, 0x20, 0x48, 0x29, 0x7d, 0xfc, 0x89, 0x05, 0x73, 0x51, 0x15, 0xf4, 0x4d, 0x63, 0x1f, 0xf0,
+	0xee, 0xce, 0x1e, 0xde, 0x5d, 0x4d, 0x04, 0x07, 0x9f, 0x5f, 0x6a, 0x76, 0xf5, 0x1e, 0x29, 0x5f,
+	0x82, 0x9c, 0x01, 0xf3, 0xc3, 0x63, 0x87, 0x6a, 0x9f, 0xe5, 0xc5, 0x70, 0xb3, 0xc6, 0xa4, 0x1f,
+	0x0b, 0xe1, 0x72, 0xec, 0xa2, 0xc5, 0x3d, 0xbe, 0x30, 0xb6, 0x92, 0xe8, 0x22, 0x24, 0xda, 0x9e,
+	0xeb, 0x4c, 0xb5, 0x4c, 0xa2, 0x05, 0xfa, 0x2a, 0xc4, 0x98, 0x3b, 0xd5, 0x22, 0xc5, 0x98, 0x6b,
+	0xdc, 0x3c, 0xe3, 0xe6, 0xcd, 0x13, 0x7f, 0x0d, 0xb2, 0xc2, 0xa8, 0x7b, 0x76, 0xd7, 0x9b, 0x18,
+	0xcb, 0x27, 0x1a, 0x85, 0xaf, 0xc0, 0x11, 0x19, 0xa7, 0x26, 0x37, 0xce, 0x4f, 0x6a, 0x9c, 0xd7,
+	0x8d, 0x4f, 0x40, 0x72, 0x65, 0x7b, 0xd8, 0xdf, 0xe1, 0x4d, 0x5a, 0x36, 0xb3, 0x75, 0x13, 0x5e,
+	0xc6, 0xc7, 0x60, 0x9e, 0xef, 0x40, 0xea, 0xf9, 0x2b, 0xee, 0xb0, 0xcf, 0xf4, 0x4d, 0xfd, 0x3c,
+	0x14, 0xc7, 0x61, 0xe5, 0x23, 0x45, 0x48, 0x36, 0x39, 0x20, 0xfa, 0x28, 0x10, 0x29, 0xe0, 0x5f,
+	0x5a, 0x80, 0x6e, 0x50, 0x26, 0x46, 0x59, 0x5b, 0xf5, 0x8d, 0x5b, 0x91, 0x63, 0xb3, 0xe6, 0x36,
+	0xf5, 0xf4, 0x05, 0x3c, 0x90, 0xbf, 0x8c, 0x5b, 0x11, 0xbe, 0x00, 0xf3, 0x63, 0xb3, 0x54, 0x36,
+	0x95, 0x21, 0xd3, 0x54, 0x98, 0x3a, 0x8d, 0x02, 0x19, 0xff, 0x2e, 0x06, 0x19, 0xd1, 0x80, 0xd0,
+	0x36, 0xba, 0x00, 0xb9, 0x76, 0xb7, 0xdf, 0xa1, 0xde, 0xc0, 0xeb, 0x2a, 0x0a, 0x12, 0x8d, 0x23,
+	0xfb, 0xa3, 0xaa, 0x09, 0x13, 0x53, 0x40, 0xef, 0x40, 0x7a, 0xe8, 0x53, 0xef, 0x61, 0x57, 0xee,
+	0xf3, 0x6c, 0xa3, 0xb8, 0x37, 0xaa, 0xa6, 0xbe, 0xe3, 0x53, 0x6f, 0x6d, 0x95, 0x9f, 0x0b, 0x43,
+	0x51, 0x22, 0xf2, 0xbf, 0x85, 0x6e, 0x2b, 0x37, 0x15, 0x57, 0xa4, 0xc6, 0xd7, 0xf9, 0xf4, 0x23,
+	0x81, 0x6e, 0xe0, 0xb9, 0x0e, 0x65, 0xdb, 0x74, 0xe8, 0xd7, 0x9b, 0xae, 0xe3, 0xb8, 0xfd, 0xba,
+	0x78, 0xa1, 0x0a, 0xa3, 0xf9, 0xe1, 0xc6, 0x9b, 0x2b, 0xcf, 0xbd, 0x0f, 0x69, 0xb6, 0xed, 0xb9,
+	0xc3, 0xce, 0xb6, 0x7c, 0x89, 0x34, 0x2e, 0x4f, 0xdf, 0x9f, 0xee, 0x81, 0xe8, 0x02, 0x3a, 0xcd,
+	0xd9, 0xa2, 0xcd, 0x1d, 0x7f, 0xe8, 0xc8, 0x77, 0x5f, 0x23, 0xb9, 0x3f, 0xaa, 0x5a, 0xef, 0x90,
+	0x00, 0xc6, 0x9f, 0xc4, 0xa0, 0x2a, 0x1c, 0xf5, 0x81, 0x38, 0xd4, 0xaf, 0xbb, 0xde, 0x1d, 0xca,
+	0xbc, 0x6e, 0xf3, 0xae, 0xed, 0x50, 0xed, 0x1b, 0x55, 0xc8, 0x39, 0x02, 0x7c, 0x68, 0x6c, 0x01,
+	0x70, 0x02, 0x3d, 0x74, 0x0a, 0x40, 0xec, 0x19, 0x59, 0x2f, 0x77, 0x43, 0x56, 0x20, 0xa2, 0x7a,
+	0x65, 0x8c, 0xa9, 0xfa, 0x94, 0x96, 0x29, 0x86, 0xd6, 0xa2, 0x0c, 0x4d, 0xdd, 0x4f, 0x40, 0x8b,
+	0xe9, 0xeb, 0xc9, 0x71, 0x5f, 0xc7, 0x7f, 0xb5, 0xa0, 0xb2, 0xae, 0x67, 0xfe, 0x8a, 0x74, 0x68,
+	0x7b, 0x63, 0xaf, 0xc9, 0xde, 0xf8, 0xff, 0x67, 0x2f, 0xbe, 0x09, 0xc5, 0xf5, 0x6e, 0x9f, 0x5e,
+	0xef, 0xf6, 0x18, 0xf5, 0xae, 0x3d, 0x19, 0x78, 0xd4, 0xf7, 0xf9, 0x03, 0xac, 0x0c, 0x19, 0x77,
+	0x40, 0x3d, 0x5b, 0xbf, 0x0a, 0xe2, 0x24, 0x90, 0x79, 0xf0, 0x10, 0x9c, 0xe8, 0xd8, 0x26, 0x04,
+	0xfc, 0x6f, 0x23, 0x78, 0x10, 0xda, 0xd6, 0x8c, 0xac, 0x18, 0x11, 0xfb, 0x75, 0x18, 0x1c, 0x7b,
+	0x8d, 0x0b, 0x1c, 0x8f, 0x04, 0xb3, 0x8b, 0x90, 0x6e, 0x0b, 0x22, 0xe4, 0xd1, 0x9b, 0x5b, 0xae,
+	0x84, 0x67, 0xdd, 0x24, 0x96, 0x88, 0x56, 0xc7, 0x1f, 0xc0, 0xfc, 0x98, 0xed, 0x2a, 0x24, 0x9d,
+	0x85, 0x84, 0x47, 0xdb, 0xfa, 0xe4, 0x44, 0x61, 0x6f, 0x81, 0xa6, 0xa8, 0xc7, 0x7f, 0xb0, 0x60,
+	0xee, 0x06, 0x65, 0xe3, 0x77, 0x92, 0x37, 0x88, 0x39, 0x7c, 0x13, 0x8e, 0x1a, 0xf3, 0x57, 0xd6,
+	0xbf, 0x17, 0xb9, 0x88, 0x1c, 0x0b, 0xed, 0x5f, 0xeb, 0xb7, 0xe8, 0x13, 0xf5, 0xc0, 0x1a, 0xbf,
+	0x83, 0xdc, 0x83, 0x9c, 0x51, 0x89, 0xae, 0x46, 0x6e, 0x1f, 0x46, 0xe2, 0x20, 0x38, 0x43, 0x1b,
+	0x45, 0x65, 0x93, 0x7c, 0x62, 0xa9, 0xbb, 0x65, 0x70, 0x56, 0x6f, 0x02, 0x12, 0x6f, 0x3e, 0xd1,
+	0xad, 0x79, 0x5a, 0x08, 0xf4, 0x76, 0x70, 0x19, 0x09, 0x64, 0x74, 0x1a, 0x12, 0x9e, 0xfb, 0x58,
+	0x5f, 0x2b, 0x0b, 0xe1, 0x90, 0xc4, 0x7d, 0x4c, 0x44, 0x15, 0xbe, 0x02, 0x71, 0xe2, 0x3e, 0xe6,
+	0x59, 0x3b, 0xcf, 0xee, 0x77, 0xe8, 0x83, 0xe0, 0xb5, 0x91, 0x27, 0x06, 0x72, 0xc8, 0x49, 0xbe,
+	0x02, 0x47, 0xcd, 0x19, 0xc9, 0xe5, 0xae, 0x41, 0xfa, 0xc3, 0xa1, 0x49, 0x57, 0x31, 0x42, 0x97,
+	0x68, 0x42, 0xb4, 0x12, 0xf7, 0x19, 0x08, 0x71, 0x74, 0x12, 0xb2, 0xcc, 0xde, 0xea, 0xd1, 0xbb,
+	0x61, 0xdc, 0x09, 0x01, 0x5e, 0xcb, 0x1f, 0x4a, 0x0f, 0x8c, 0x2b, 0x49, 0x08, 0xa0, 0x73, 0x30,
+	0x17, 0xce, 0xf9, 0x9e, 0x47, 0xdb, 0xdd, 0x27, 0x62, 0x85, 0xf3, 0xe4, 0x00, 0x2e, 0x72, 0x5a,
+	0x01, 0xb6, 0x29, 0x8e, 0xfe, 0x84, 0x50, 0x8d, 0xc2, 0x9c, 0x1b, 0x61, 0xee, 0xb5, 0x47, 0x43,
+	0xbb, 0x27, 0x82, 0x69, 0x9e, 0x18, 0x08, 0xfe, 0xa3, 0x05, 0x47, 0xe5, 0x52, 0x1b, 0xb9, 0xc5,
+	0x37, 0xca, 0xeb, 0x7f, 0x65, 0x01, 0x32, 0x2d, 0x50, 0xae, 0xf5, 0x15, 0x33, 0xf7, 0xc1, 0xef,
+	0x16, 0x39, 0xf1, 0xfe, 0x93, 0x50, 0x98, 0xbe, 0xc0, 0x90, 0x12, 0xf7, 0x13, 0x95, 0xb2, 0x94,
+	0x0f, 0x4c, 0x89, 0x10, 0xf5, 0xcf, 0xdf, 0xc5, 0x5b, 0x61, 0xa6, 0x52, 0xbe, 0x8b, 0x05, 0x40,
+	0xe4, 0x1f, 0x1f, 0x8b, 0xaa, 0xd4, 0x62, 0x22, 0x1c, 0x4b, 0x41, 0x44, 0x17, 0xf0, 0x6f, 0x62,
+	0x50, 0x78, 0xe0, 0xf6, 0x86, 0x0e, 0x7d, 0x03, 0x79, 0x1e, 0x7f, 0xb7, 0x26, 0xf5, 0xbb, 0x15,
+	0x41, 0xc2, 0x67, 0x74, 0xa0, 0x52, 0xaf, 0xa2, 0x8c, 0x30, 0xe4, 0x99, 0xed, 0x75, 0x28, 0x93,
+	0x4f, 0x8e, 0x52, 0x4a, 0xdc, 0x03, 0xc7, 0x30, 0x9e, 0xdd, 0xb5, 0x3b, 0x1d, 0x8f, 0x76, 0x6c,
+	0x46, 0x1b, 0xbb, 0x22, 0xd3, 0x9a, 0x25, 0x26, 0x84, 0xbf, 0x07, 0xb3, 0x9a, 0x2c, 0xb5, 0xa4,
+	0xef, 0x42, 0xfa, 0x63, 0x81, 0x4c, 0xc8, 0x13, 0x49, 0x55, 0x15, 0xc6, 0xb4, 0xda, 0x78, 0xfa,
+	0x55, 0xcf, 0x19, 0xdf, 0x82, 0x94, 0x54, 0xe7, 0x09, 0x8d, 0xf0, 0x8a, 0x20, 0x13, 0x1a, 0x5c,
+	0x56, 0x2f, 0x00, 0x0c, 0x29, 0xd9, 0x51, 0x29, 0x1e, 0xfa, 0x86, 0x44, 0x88, 0xfa, 0x3f, 0x77,
+	0x16, 0xb2, 0x41, 0xee, 0x14, 0xe5, 0x20, 0x7d, 0x7d, 0x83, 0x7c, 0xf7, 0x2a, 0x59, 0x9d, 0x9b,
+	0x41, 0x79, 0xc8, 0x34, 0xae, 0xae, 0xdc, 0x16, 0x92, 0xb5, 0xfc, 0xaf, 0x84, 0x8e, 0x2c, 0x1e,
+	0xfa, 0x06, 0x24, 0x65, 0xb8, 0x38, 0x1e, 0xce, 0xdf, 0xcc, 0x80, 0x96, 0x17, 0x0e, 0xe0, 0x92,
+	0x01, 0x3c, 0xf3, 0xae, 0x85, 0xee, 0x42, 0x4e, 0x80, 0x2a, 0xcb, 0x72, 0x32, 0x9a, 0xec, 0x18,
+	0xeb, 0xe9, 0xd4, 0x21, 0xb5, 0x46, 0x7f, 0x97, 0x21, 0x29, 0xd6, 0xc4, 0x9c, 0x8d, 0x99, 0x25,
+	0x2b, 0x2f, 0x1c, 0xc0, 0x75, 0x6b, 0x74, 0x09, 0x12, 0xfc, 0x65, 0x83, 0x8c, 0x43, 0xc5, 0x48,
+	0x8e, 0x94, 0x8f, 0x47, 0x61, 0x63, 0xd8, 0x0f, 0x82, 0x1c, 0xcf, 0x42, 0xf4, 0x2d, 0xab, 0x9b,
+	0x97, 0x0e, 0x56, 0x04, 0x23, 0x6f, 0x40, 0xde, 0x7c, 0x53, 0xa1, 0x53, 0xe3, 0x43, 0x45, 0x9e,
+	0x60, 0xe5, 0xca, 0x61, 0xd5, 0x41, 0x87, 0xeb, 0x90, 0x33, 0xde, 0x33, 0x26, 0xad, 0x07, 0x1f,
+	0x63, 0xe5, 0x53, 0x87, 0xd4, 0x06, 0xbd, 0xdd, 0x80, 0x0c, 0x3f, 0x8a, 0xc5, 0x87, 0x93, 0x13,
+	0xd1, 0x13, 0xd7, 0x88, 0xb4, 0xe5, 0x93, 0x93, 0x2b, 0x83, 0x8e, 0xbe, 0x0d, 0xd9, 0x1b, 0x94,
+	0x29, 0x77, 0x5d, 0x88, 0xfa, 0xfb, 0x04, 0xa6, 0xc6, 0xf7, 0x0c, 0x9e, 0x59, 0xfe, 0x7d, 0xf0,
+	0x89, 0x6b, 0xd5, 0x66, 0x36, 0xda, 0x80, 0x59, 0x31, 0xb3, 0xe0, 0x1b, 0xd8, 0x98, 0x07, 0x1d,
+	0xf8, 0xe0, 0x56, 0x3e, 0x75, 0x48, 0x6d, 0x30, 0xc3, 0xef, 0x8b, 0x1b, 0x67, 0xf4, 0x6b, 0xd1,
+	0x62, 0xd8, 0x6c, 0xf2, 0xf7, 0xab, 0xf2, 0xe9, 0x97, 0x68, 0xe8, 0xce, 0x1b, 0x1f, 0x3d, 0x7b,
+	0x5e, 0x99, 0xf9, 0xec, 0x79, 0x65, 0xe6, 0xf3, 0xe7, 0x15, 0xeb, 0xc7, 0x7b, 0x15, 0xeb, 0xd7,
+	0x7b, 0x15, 0xeb, 0xe9, 0x5e, 0xc5, 0x7a, 0xb6, 0x57, 0xb1, 0xfe, 0xb1, 0x57, 0xb1, 0xfe, 0xb9,
+	0x57, 0x99, 0xf9, 0x7c, 0xaf, 0x62, 0x7d, 0xfa, 0xa2, 0x32, 0xf3, 0xec, 0x45, 0x65, 0xe6, 0xb3,
+	0x17, 0x95, 0x99, 0x8f, 0xce, 0xbc, 0x2c, 0x99, 0xa5, 0x47, 0xdd, 0x4a, 0x89, 0xbf, 0xf7, 0xfe,
+	0x3b, 0x00, 0x23, 0x6e, 0x1e, 0xbe, 0xfe, 0x1c, 0x00, 0x00,
 }
 
 func (x Direction) String() string {
@@ -2708,14 +2892,14 @@ func (this *StreamRate) Equal(that interface{}) bool {
 	}
 	return true
 }
-func (this *QueryRequest) Equal(that interface{}) bool {
+func (this *OutOfOrderStatsRequest) Equal(that interface{}) bool {
 	if that == nil {
 		return this == nil
 	}
 
-	that1, ok := that.(*QueryRequest)
+	that1, ok := that.(*OutOfOrderStatsRequest)
 	if !ok {
-		that2, ok := that.(QueryRequest)
+		that2, ok := that.(OutOfOrderStatsRequest)
 		if ok {
 			that1 = &that2
 		} else {
@@ -2727,47 +2911,45 @@ func (this *QueryRequest) Equal(that interface{}) bool {
 	} else if this == nil {
 		return false
 	}
-	if this.Selector != that1.Selector {
-		return false
-	}
-	if this.Limit != that1.Limit {
-		return false
-	}
-	if !this.Start.Equal(that1.Start) {
-		return false
-	}
-	if !this.End.Equal(that1.End) {
-		return false
-	}
-	if this.Direction != that1.Direction {
-		return false
-	}
-	if len(this.Shards) != len(that1.Shards) {
-		return false
+	return true
+}
+func (this *OutOfOrderStatsResponse) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
 	}
-	for i := range this.Shards {
-		if this.Shards[i] != that1.Shards[i] {
+
+	that1, ok := that.(*OutOfOrderStatsResponse)
+	if !ok {
+		that2, ok := that.(OutOfOrderStatsResponse)
+		if ok {
+			that1 = &that2
+		} else {
 			return false
 		}
 	}
-	if len(this.Deletes) != len(that1.Deletes) {
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
 		return false
 	}
-	for i := range this.Deletes {
-		if !this.Deletes[i].Equal(that1.Deletes[i]) {
+	if len(this.Streams) != len(that1.Streams) {
+		return false
+	}
+	for i := range this.Streams {
+		if !this.Streams[i].Equal(that1.Streams[i]) {
 			return false
 		}
 	}
 	return true
 }
-func (this *SampleQueryRequest) Equal(that interface{}) bool {
+func (this *StreamOutOfOrderStats) Equal(that interface{}) bool {
 	if that == nil {
 		return this == nil
 	}
 
-	that1, ok := that.(*SampleQueryRequest)
+	that1, ok := that.(*StreamOutOfOrderStats)
 	if !ok {
-		that2, ok := that.(SampleQueryRequest)
+		that2, ok := that.(StreamOutOfOrderStats)
 		if ok {
 			that1 = &that2
 		} else {
@@ -2779,41 +2961,37 @@ func (this *SampleQueryRequest) Equal(that interface{}) bool {
 	} else if this == nil {
 		return false
 	}
-	if this.Selector != that1.Selector {
+	if this.Labels != that1.Labels {
 		return false
 	}
-	if !this.Start.Equal(that1.Start) {
+	if this.OutOfOrderEntries != that1.OutOfOrderEntries {
 		return false
 	}
-	if !this.End.Equal(that1.End) {
+	if this.OutOfOrderBytes != that1.OutOfOrderBytes {
 		return false
 	}
-	if len(this.Shards) != len(that1.Shards) {
+	if this.MaxDistance != that1.MaxDistance {
 		return false
 	}
-	for i := range this.Shards {
-		if this.Shards[i] != that1.Shards[i] {
-			return false
-		}
+	if this.TotalDistance != that1.TotalDistance {
+		return false
 	}
-	if len(this.Deletes) != len(that1.Deletes) {
+	if this.RejectedEntries != that1.RejectedEntries {
 		return false
 	}
-	for i := range this.Deletes {
-		if !this.Deletes[i].Equal(that1.Deletes[i]) {
-			return false
-		}
+	if this.RejectedBytes != that1.RejectedBytes {
+		return false
 	}
 	return true
 }
-func (this *Delete) Equal(that interface{}) bool {
+func (this *QueryRequest) Equal(that interface{}) bool {
 	if that == nil {
 		return this == nil
 	}
 
-	that1, ok := that.(*Delete)
+	that1, ok := that.(*QueryRequest)
 	if !ok {
-		that2, ok := that.(Delete)
+		that2, ok := that.(QueryRequest)
 		if ok {
 			that1 = &that2
 		} else {
@@ -2828,7 +3006,105 @@ func (this *Delete) Equal(that interface{}) bool {
 	if this.Selector != that1.Selector {
 		return false
 	}
-	if this.Start != that1.Start {
+	if this.Limit != that1.Limit {
+		return false
+	}
+	if !this.Start.Equal(that1.Start) {
+		return false
+	}
+	if !this.End.Equal(that1.End) {
+		return false
+	}
+	if this.Direction != that1.Direction {
+		return false
+	}
+	if len(this.Shards) != len(that1.Shards) {
+		return false
+	}
+	for i := range this.Shards {
+		if this.Shards[i] != that1.Shards[i] {
+			return false
+		}
+	}
+	if len(this.Deletes) != len(that1.Deletes) {
+		return false
+	}
+	for i := range this.Deletes {
+		if !this.Deletes[i].Equal(that1.Deletes[i]) {
+			return false
+		}
+	}
+	return true
+}
+func (this *SampleQueryRequest) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*SampleQueryRequest)
+	if !ok {
+		that2, ok := that.(SampleQueryRequest)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Selector != that1.Selector {
+		return false
+	}
+	if !this.Start.Equal(that1.Start) {
+		return false
+	}
+	if !this.End.Equal(that1.End) {
+		return false
+	}
+	if len(this.Shards) != len(that1.Shards) {
+		return false
+	}
+	for i := range this.Shards {
+		if this.Shards[i] != that1.Shards[i] {
+			return false
+		}
+	}
+	if len(this.Deletes) != len(that1.Deletes) {
+		return false
+	}
+	for i := range this.Deletes {
+		if !this.Deletes[i].Equal(that1.Deletes[i]) {
+			return false
+		}
+	}
+	return true
+}
+func (this *Delete) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*Delete)
+	if !ok {
+		that2, ok := that.(Delete)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Selector != that1.Selector {
+		return false
+	}
+	if this.Start != that1.Start {
 		return false
 	}
 	if this.End != that1.End {
@@ -4059,6 +4335,43 @@ func (this *StreamRate) GoString() string {
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
+func (this *OutOfOrderStatsRequest) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 4)
+	s = append(s, "&logproto.OutOfOrderStatsRequest{")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *OutOfOrderStatsResponse) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 5)
+	s = append(s, "&logproto.OutOfOrderStatsResponse{")
+	if this.Streams != nil {
+		s = append(s, "Streams: "+fmt.Sprintf("%#v", this.Streams)+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *StreamOutOfOrderStats) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 11)
+	s = append(s, "&logproto.StreamOutOfOrderStats{")
+	s = append(s, "Labels: "+fmt.Sprintf("%#v", this.Labels)+",\n")
+	s = append(s, "OutOfOrderEntries: "+fmt.Sprintf("%#v", this.OutOfOrderEntries)+",\n")
+	s = append(s, "OutOfOrderBytes: "+fmt.Sprintf("%#v", this.OutOfOrderBytes)+",\n")
+	s = append(s, "MaxDistance: "+fmt.Sprintf("%#v", this.MaxDistance)+",\n")
+	s = append(s, "TotalDistance: "+fmt.Sprintf("%#v", this.TotalDistance)+",\n")
+	s = append(s, "RejectedEntries: "+fmt.Sprintf("%#v", this.RejectedEntries)+",\n")
+	s = append(s, "RejectedBytes: "+fmt.Sprintf("%#v", this.RejectedBytes)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
 func (this *QueryRequest) GoString() string {
 	if this == nil {
 		return "nil"
@@ -5061,6 +5374,7 @@ var _Querier_serviceDesc = grpc.ServiceDesc{
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
 type StreamDataClient interface {
 	GetStreamRates(ctx context.Context, in *StreamRatesRequest, opts ...grpc.CallOption) (*StreamRatesResponse, error)
+	GetOutOfOrderStats(ctx context.Context, in *OutOfOrderStatsRequest, opts ...grpc.CallOption) (*OutOfOrderStatsResponse, error)
 }
 
 type streamDataClient struct {
@@ -5080,9 +5394,19 @@ func (c *streamDataClient) GetStreamRates(ctx context.Context, in *StreamRatesRe
 	return out, nil
 }
 
+func (c *streamDataClient) GetOutOfOrderStats(ctx context.Context, in *OutOfOrderStatsRequest, opts ...grpc.CallOption) (*OutOfOrderStatsResponse, error) {
+	out := new(OutOfOrderStatsResponse)
+	err := c.cc.Invoke(ctx, "/logproto.StreamData/GetOutOfOrderStats", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // StreamDataServer is the server API for StreamData service.
 type StreamDataServer interface {
 	GetStreamRates(context.Context, *StreamRatesRequest) (*StreamRatesResponse, error)
+	GetOutOfOrderStats(context.Context, *OutOfOrderStatsRequest) (*OutOfOrderStatsResponse, error)
 }
 
 // UnimplementedStreamDataServer can be embedded to have forward compatible implementations.
@@ -5092,6 +5416,9 @@ type UnimplementedStreamDataServer struct {
 func (*UnimplementedStreamDataServer) GetStreamRates(ctx context.Context, req *StreamRatesRequest) (*StreamRatesResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetStreamRates not implemented")
 }
+func (*UnimplementedStreamDataServer) GetOutOfOrderStats(ctx context.Context, req *OutOfOrderStatsRequest) (*OutOfOrderStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOutOfOrderStats not implemented")
+}
 
 func RegisterStreamDataServer(s *grpc.Server, srv StreamDataServer) {
 	s.RegisterService(&_StreamData_serviceDesc, srv)
@@ -5115,6 +5442,24 @@ func _StreamData_GetStreamRates_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _StreamData_GetOutOfOrderStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OutOfOrderStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StreamDataServer).GetOutOfOrderStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/logproto.StreamData/GetOutOfOrderStats",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StreamDataServer).GetOutOfOrderStats(ctx, req.(*OutOfOrderStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _StreamData_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "logproto.StreamData",
 	HandlerType: (*StreamDataServer)(nil),
@@ -5123,6 +5468,10 @@ var _StreamData_serviceDesc = grpc.ServiceDesc{
 			MethodName: "GetStreamRates",
 			Handler:    _StreamData_GetStreamRates_Handler,
 		},
+		{
+			MethodName: "GetOutOfOrderStats",
+			Handler:    _StreamData_GetOutOfOrderStats_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "pkg/logproto/logproto.proto",
@@ -5238,6 +5587,126 @@ func (m *StreamRate) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *OutOfOrderStatsRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *OutOfOrderStatsRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *OutOfOrderStatsRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	return len(dAtA) - i, nil
+}
+
+func (m *OutOfOrderStatsResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *OutOfOrderStatsResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *OutOfOrderStatsResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Streams) > 0 {
+		for iNdEx := len(m.Streams) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Streams[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintLogproto(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *StreamOutOfOrderStats) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *StreamOutOfOrderStats) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *StreamOutOfOrderStats) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.RejectedBytes != 0 {
+		i = encodeVarintLogproto(dAtA, i, uint64(m.RejectedBytes))
+		i--
+		dAtA[i] = 0x38
+	}
+	if m.RejectedEntries != 0 {
+		i = encodeVarintLogproto(dAtA, i, uint64(m.RejectedEntries))
+		i--
+		dAtA[i] = 0x30
+	}
+	if m.TotalDistance != 0 {
+		i = encodeVarintLogproto(dAtA, i, uint64(m.TotalDistance))
+		i--
+		dAtA[i] = 0x28
+	}
+	if m.MaxDistance != 0 {
+		i = encodeVarintLogproto(dAtA, i, uint64(m.MaxDistance))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.OutOfOrderBytes != 0 {
+		i = encodeVarintLogproto(dAtA, i, uint64(m.OutOfOrderBytes))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.OutOfOrderEntries != 0 {
+		i = encodeVarintLogproto(dAtA, i, uint64(m.OutOfOrderEntries))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Labels) > 0 {
+		i -= len(m.Labels)
+		copy(dAtA[i:], m.Labels)
+		i = encodeVarintLogproto(dAtA, i, uint64(len(m.Labels)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
 func (m *QueryRequest) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
@@ -7099,24 +7568,79 @@ func (m *StreamRate) Size() (n int) {
 	return n
 }
 
-func (m *QueryRequest) Size() (n int) {
+func (m *OutOfOrderStatsRequest) Size() (n int) {
 	if m == nil {
 		return 0
 	}
 	var l int
 	_ = l
-	l = len(m.Selector)
-	if l > 0 {
-		n += 1 + l + sovLogproto(uint64(l))
-	}
-	if m.Limit != 0 {
-		n += 1 + sovLogproto(uint64(m.Limit))
+	return n
+}
+
+func (m *OutOfOrderStatsResponse) Size() (n int) {
+	if m == nil {
+		return 0
 	}
-	l = github_com_gogo_protobuf_types.SizeOfStdTime(m.Start)
-	n += 1 + l + sovLogproto(uint64(l))
-	l = github_com_gogo_protobuf_types.SizeOfStdTime(m.End)
-	n += 1 + l + sovLogproto(uint64(l))
-	if m.Direction != 0 {
+	var l int
+	_ = l
+	if len(m.Streams) > 0 {
+		for _, e := range m.Streams {
+			l = e.Size()
+			n += 1 + l + sovLogproto(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *StreamOutOfOrderStats) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Labels)
+	if l > 0 {
+		n += 1 + l + sovLogproto(uint64(l))
+	}
+	if m.OutOfOrderEntries != 0 {
+		n += 1 + sovLogproto(uint64(m.OutOfOrderEntries))
+	}
+	if m.OutOfOrderBytes != 0 {
+		n += 1 + sovLogproto(uint64(m.OutOfOrderBytes))
+	}
+	if m.MaxDistance != 0 {
+		n += 1 + sovLogproto(uint64(m.MaxDistance))
+	}
+	if m.TotalDistance != 0 {
+		n += 1 + sovLogproto(uint64(m.TotalDistance))
+	}
+	if m.RejectedEntries != 0 {
+		n += 1 + sovLogproto(uint64(m.RejectedEntries))
+	}
+	if m.RejectedBytes != 0 {
+		n += 1 + sovLogproto(uint64(m.RejectedBytes))
+	}
+	return n
+}
+
+func (m *QueryRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Selector)
+	if l > 0 {
+		n += 1 + l + sovLogproto(uint64(l))
+	}
+	if m.Limit != 0 {
+		n += 1 + sovLogproto(uint64(m.Limit))
+	}
+	l = github_com_gogo_protobuf_types.SizeOfStdTime(m.Start)
+	n += 1 + l + sovLogproto(uint64(l))
+	l = github_com_gogo_protobuf_types.SizeOfStdTime(m.End)
+	n += 1 + l + sovLogproto(uint64(l))
+	if m.Direction != 0 {
 		n += 1 + sovLogproto(uint64(m.Direction))
 	}
 	if len(m.Shards) > 0 {
@@ -7937,6 +8461,46 @@ func (this *StreamRate) String() string {
 	}, "")
 	return s
 }
+func (this *OutOfOrderStatsRequest) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&OutOfOrderStatsRequest{`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *OutOfOrderStatsResponse) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForStreams := "[]*StreamOutOfOrderStats{"
+	for _, f := range this.Streams {
+		repeatedStringForStreams += strings.Replace(f.String(), "StreamOutOfOrderStats", "StreamOutOfOrderStats", 1) + ","
+	}
+	repeatedStringForStreams += "}"
+	s := strings.Join([]string{`&OutOfOrderStatsResponse{`,
+		`Streams:` + repeatedStringForStreams + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *StreamOutOfOrderStats) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&StreamOutOfOrderStats{`,
+		`Labels:` + fmt.Sprintf("%v", this.Labels) + `,`,
+		`OutOfOrderEntries:` + fmt.Sprintf("%v", this.OutOfOrderEntries) + `,`,
+		`OutOfOrderBytes:` + fmt.Sprintf("%v", this.OutOfOrderBytes) + `,`,
+		`MaxDistance:` + fmt.Sprintf("%v", this.MaxDistance) + `,`,
+		`TotalDistance:` + fmt.Sprintf("%v", this.TotalDistance) + `,`,
+		`RejectedEntries:` + fmt.Sprintf("%v", this.RejectedEntries) + `,`,
+		`RejectedBytes:` + fmt.Sprintf("%v", this.RejectedBytes) + `,`,
+		`}`,
+	}, "")
+	return s
+}
 func (this *QueryRequest) String() string {
 	if this == nil {
 		return "nil"
@@ -8791,6 +9355,345 @@ func (m *StreamRate) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *OutOfOrderStatsRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowLogproto
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: OutOfOrderStatsRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: OutOfOrderStatsRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		default:
+			iNdEx = preIndex
+			skippy, err := skipLogproto(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *OutOfOrderStatsResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowLogproto
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: OutOfOrderStatsResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: OutOfOrderStatsResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Streams", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLogproto
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Streams = append(m.Streams, &StreamOutOfOrderStats{})
+			if err := m.Streams[len(m.Streams)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipLogproto(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *StreamOutOfOrderStats) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowLogproto
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: StreamOutOfOrderStats: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: StreamOutOfOrderStats: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Labels", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLogproto
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Labels = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field OutOfOrderEntries", wireType)
+			}
+			m.OutOfOrderEntries = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLogproto
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.OutOfOrderEntries |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field OutOfOrderBytes", wireType)
+			}
+			m.OutOfOrderBytes = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLogproto
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.OutOfOrderBytes |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxDistance", wireType)
+			}
+			m.MaxDistance = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLogproto
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MaxDistance |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TotalDistance", wireType)
+			}
+			m.TotalDistance = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLogproto
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.TotalDistance |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RejectedEntries", wireType)
+			}
+			m.RejectedEntries = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLogproto
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.RejectedEntries |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RejectedBytes", wireType)
+			}
+			m.RejectedBytes = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLogproto
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.RejectedBytes |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipLogproto(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func (m *QueryRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
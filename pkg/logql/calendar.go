@@ -0,0 +1,130 @@
+package logql
+
+import (
+	"fmt"
+	"time"
+)
+
+// CalendarUnit is the unit of the steps of a range query aligned to the calendar.
+type CalendarUnit string
+
+const (
+	CalendarDay   CalendarUnit = "day"
+	CalendarWeek  CalendarUnit = "week"
+	CalendarMonth CalendarUnit = "month"
+)
+
+// Calendar aligns the steps of a range query to the starts of the days, weeks or months of a time zone.
+// Unlike fixed steps, the durations between the steps vary with the daylight saving time and the lengths
+// of the months. Weeks start on Monday.
+type Calendar struct {
+	Unit     CalendarUnit
+	Location *time.Location
+	// Ranges makes the range aggregations over the nominal duration of the steps cover the periods of the calendar.
+	Ranges bool
+}
+
+// IsCalendarUnit tells whether s is the unit of a calendar step.
+func IsCalendarUnit(s string) bool {
+	switch CalendarUnit(s) {
+	case CalendarDay, CalendarWeek, CalendarMonth:
+		return true
+	}
+	return false
+}
+
+// ParseCalendar returns the calendar of the given unit in the given IANA time zone, UTC if empty,
+// whose periods are covered by the range aggregations over the nominal duration of the steps if ranges is set.
+func ParseCalendar(unit, tz string, ranges bool) (*Calendar, error) {
+	if !IsCalendarUnit(unit) {
+		return nil, fmt.Errorf("invalid calendar step %q, expected one of day, week or month", unit)
+	}
+	loc := time.UTC
+	if tz != "" {
+		var err error
+		loc, err = time.LoadLocation(tz)
+		if err != nil {
+			return nil, fmt.Errorf("invalid time zone %q: %w", tz, err)
+		}
+	}
+	return &Calendar{Unit: CalendarUnit(unit), Location: loc, Ranges: ranges}, nil
+}
+
+// Step returns the nominal duration of a step of the calendar: 1d, 7d or 30d.
+// It sizes the query, and a range aggregation over this range covers exactly the period ending at each step
+// if the calendar covers the ranges.
+func (c *Calendar) Step() time.Duration {
+	switch c.Unit {
+	case CalendarWeek:
+		return 7 * 24 * time.Hour
+	case CalendarMonth:
+		return 30 * 24 * time.Hour
+	default:
+		return 24 * time.Hour
+	}
+}
+
+// TimeZone returns the name of the time zone of the calendar.
+func (c *Calendar) TimeZone() string {
+	return c.Location.String()
+}
+
+func (c *Calendar) String() string {
+	if c.Ranges {
+		return string(c.Unit) + "@" + c.TimeZone() + "+ranges"
+	}
+	return string(c.Unit) + "@" + c.TimeZone()
+}
+
+// Floor returns the start of the period containing t.
+func (c *Calendar) Floor(t time.Time) time.Time {
+	y, m, d := t.In(c.Location).Date()
+	switch c.Unit {
+	case CalendarWeek:
+		// time.Weekday starts on Sunday.
+		d -= (int(time.Date(y, m, d, 0, 0, 0, 0, c.Location).Weekday()) + 6) % 7
+	case CalendarMonth:
+		d = 1
+	}
+	return time.Date(y, m, d, 0, 0, 0, 0, c.Location)
+}
+
+// Ceil returns the start of the first period starting at or after t.
+func (c *Calendar) Ceil(t time.Time) time.Time {
+	f := c.Floor(t)
+	if f.Equal(t) {
+		return t
+	}
+	return c.add(f)
+}
+
+// Next returns the first step strictly after the timestamp ts in nanoseconds.
+func (c *Calendar) Next(ts int64) int64 {
+	return c.add(c.Floor(time.Unix(0, ts))).UnixNano()
+}
+
+// Prev returns the last step strictly before the timestamp ts in nanoseconds.
+func (c *Calendar) Prev(ts int64) int64 {
+	return c.Floor(time.Unix(0, ts-1)).UnixNano()
+}
+
+// Covers tells whether a range aggregation over selRange evaluated at the steps of the calendar
+// covers exactly the period ending at each step instead of a fixed duration.
+// Only the ranges of the nominal duration of the steps do, and only if the calendar covers the ranges.
+func (c *Calendar) Covers(selRange time.Duration) bool {
+	return c.Ranges && selRange == c.Step()
+}
+
+// add returns the start of the period following the one starting at f.
+func (c *Calendar) add(f time.Time) time.Time {
+	y, m, d := f.Date()
+	switch c.Unit {
+	case CalendarWeek:
+		d += 7
+	case CalendarMonth:
+		m++
+	default:
+		d++
+	}
+	return time.Date(y, m, d, 0, 0, 0, 0, c.Location)
+}
@@ -0,0 +1,88 @@
+package logql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCalendar(t *testing.T) {
+	cal, err := ParseCalendar("week", "", false)
+	require.NoError(t, err)
+	require.Equal(t, "week@UTC", cal.String())
+	require.False(t, cal.Covers(7*24*time.Hour))
+
+	cal, err = ParseCalendar("month", "Europe/Paris", true)
+	require.NoError(t, err)
+	require.Equal(t, "month@Europe/Paris+ranges", cal.String())
+	require.True(t, cal.Covers(30*24*time.Hour))
+	require.False(t, cal.Covers(31*24*time.Hour))
+
+	_, err = ParseCalendar("year", "", false)
+	require.Error(t, err)
+
+	_, err = ParseCalendar("day", "Mars/Olympus_Mons", false)
+	require.Error(t, err)
+}
+
+func TestCalendar(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		unit       CalendarUnit
+		t          time.Time
+		floor      time.Time
+		next, prev time.Time
+	}{
+		{
+			unit:  CalendarDay,
+			t:     time.Date(2023, 3, 26, 12, 0, 0, 0, paris),
+			floor: time.Date(2023, 3, 26, 0, 0, 0, 0, paris),
+			next:  time.Date(2023, 3, 27, 0, 0, 0, 0, paris),
+			prev:  time.Date(2023, 3, 26, 0, 0, 0, 0, paris),
+		},
+		{
+			unit:  CalendarDay,
+			t:     time.Date(2023, 3, 27, 0, 0, 0, 0, paris),
+			floor: time.Date(2023, 3, 27, 0, 0, 0, 0, paris),
+			next:  time.Date(2023, 3, 28, 0, 0, 0, 0, paris),
+			prev:  time.Date(2023, 3, 26, 0, 0, 0, 0, paris),
+		},
+		{
+			// the 1st of January 2023 is a Sunday.
+			unit:  CalendarWeek,
+			t:     time.Date(2023, 1, 1, 23, 0, 0, 0, paris),
+			floor: time.Date(2022, 12, 26, 0, 0, 0, 0, paris),
+			next:  time.Date(2023, 1, 2, 0, 0, 0, 0, paris),
+			prev:  time.Date(2022, 12, 26, 0, 0, 0, 0, paris),
+		},
+		{
+			unit:  CalendarMonth,
+			t:     time.Date(2023, 2, 1, 0, 0, 0, 0, paris),
+			floor: time.Date(2023, 2, 1, 0, 0, 0, 0, paris),
+			next:  time.Date(2023, 3, 1, 0, 0, 0, 0, paris),
+			prev:  time.Date(2023, 1, 1, 0, 0, 0, 0, paris),
+		},
+		{
+			unit:  CalendarMonth,
+			t:     time.Date(2023, 12, 31, 23, 30, 0, 0, time.UTC),
+			floor: time.Date(2024, 1, 1, 0, 0, 0, 0, paris),
+			next:  time.Date(2024, 2, 1, 0, 0, 0, 0, paris),
+			prev:  time.Date(2024, 1, 1, 0, 0, 0, 0, paris),
+		},
+	} {
+		t.Run(string(tc.unit)+" "+tc.t.String(), func(t *testing.T) {
+			cal := &Calendar{Unit: tc.unit, Location: paris}
+			require.True(t, tc.floor.Equal(cal.Floor(tc.t)), cal.Floor(tc.t))
+			require.Equal(t, tc.next.UnixNano(), cal.Next(tc.t.UnixNano()))
+			require.Equal(t, tc.prev.UnixNano(), cal.Prev(tc.t.UnixNano()))
+			if tc.floor.Equal(tc.t) {
+				require.True(t, tc.t.Equal(cal.Ceil(tc.t)))
+			} else {
+				require.True(t, tc.next.Equal(cal.Ceil(tc.t)))
+			}
+		})
+	}
+}
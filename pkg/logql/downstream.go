@@ -232,6 +232,13 @@ func (ev DownstreamEvaluator) Downstream(ctx context.Context, queries []Downstre
 		}
 	}
 
+	for _, res := range results {
+		if err := metadata.AddWarnings(ctx, res.Warnings...); err != nil {
+			level.Warn(util_log.Logger).Log("msg", "unable to add warnings to results context", "error", err)
+			break
+		}
+	}
+
 	return results, nil
 }
 
@@ -445,7 +452,7 @@ func NewResultStepEvaluator(res logqlmodel.Result, params Params) (StepEvaluator
 	case promql.Vector:
 		return NewVectorStepEvaluator(start, data), nil
 	case promql.Matrix:
-		return NewMatrixStepEvaluator(start, end, step, data), nil
+		return NewMatrixStepEvaluator(start, end, step, params.Calendar(), data), nil
 	default:
 		return nil, fmt.Errorf("unexpected type (%s) uncoercible to StepEvaluator", data.Type())
 	}
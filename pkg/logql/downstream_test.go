@@ -53,6 +53,8 @@ func TestMappingEquivalence(t *testing.T) {
 		{`sum(rate({a=~".+"} |= "foo" != "foo"[1s]) or vector(1))`, false},
 		{`avg_over_time({a=~".+"} | logfmt | unwrap value [1s])`, false},
 		{`avg_over_time({a=~".+"} | logfmt | unwrap value [1s]) by (a)`, true},
+		{`max_over_time(sum by (a) (rate({a=~".+"}[1s]))[5s:1s])`, false},
+		{`count_over_time(rate({a=~".+"}[1s])[5s:2s] offset 1s)`, false},
 		// topk prefers already-seen values in tiebreakers. Since the test data generates
 		// the same log lines for each series & the resulting promql.Vectors aren't deterministically
 		// sorted by labels, we don't expect this to pass.
@@ -261,6 +261,7 @@ func (q *query) Exec(ctx context.Context) (logqlmodel.Result, error) {
 		Data:       data,
 		Statistics: statResult,
 		Headers:    metadataCtx.Headers(),
+		Warnings:   metadataCtx.Warnings(),
 	}, err
 }
 
@@ -285,11 +286,20 @@ func (q *query) Eval(ctx context.Context) (promql_parser.Value, error) {
 		value, err := q.evalSample(ctx, e)
 		return value, err
 
+	case *syntax.JoinExpr:
+		return q.evalJoin(ctx, e)
+
 	case syntax.LogSelectorExpr:
+		mergeStages, err := syntax.MergeStages(e)
+		if err != nil {
+			return nil, err
+		}
 		iter, err := q.evaluator.NewIterator(ctx, e, q.params)
 		if err != nil {
 			return nil, err
 		}
+		// the results of the ingesters and the store, or of the shards, are merged here.
+		iter = newMergeStagesIterator(iter, mergeStages, q.params.Start(), q.params.End())
 
 		defer util.LogErrorWithContext(ctx, "closing iterator", iter.Close)
 		streams, err := readStreams(iter, q.params.Limit(), q.params.Direction(), q.params.Interval())
@@ -431,6 +441,11 @@ func (q *query) checkIntervalLimit(expr syntax.SampleExpr, limit time.Duration)
 				return
 			}
 			err = fmt.Errorf("%w: [%s] > [%s]", logqlmodel.ErrIntervalLimit, model.Duration(e.Left.Interval), model.Duration(limit))
+		case *syntax.SubqueryExpr:
+			if e.Range <= limit {
+				return
+			}
+			err = fmt.Errorf("%w: [%s] > [%s]", logqlmodel.ErrIntervalLimit, model.Duration(e.Range), model.Duration(limit))
 		}
 	})
 	return err
@@ -489,7 +504,11 @@ func PopulateMatrixFromScalar(data promql.Scalar, params Params) promql.Matrix {
 		}
 	)
 
-	for ts := start; !ts.After(end); ts = ts.Add(step) {
+	ts, cal := start, params.Calendar()
+	if cal != nil {
+		ts = cal.Ceil(start)
+	}
+	for ; !ts.After(end); ts = time.Unix(0, nextStep(ts.UnixNano(), step.Nanoseconds(), 0, cal)) {
 		series.Floats = append(series.Floats, promql.FPoint{
 			T: ts.UnixNano() / int64(time.Millisecond),
 			F: data.V,
@@ -618,6 +618,89 @@ func TestEngine_LogsInstantQuery(t *testing.T) {
 				{T: 60 * 1000, F: 1.1, Metric: labels.FromStrings("app", "foo")},
 			},
 		},
+
+		{
+			`group(rate(({app=~"foo|bar"} |~".+bar")[1m]))`, time.Unix(60, 0), logproto.FORWARD, 100,
+			[][]logproto.Series{
+				{
+					newSeries(testSize, factor(10, identity), `{app="foo"}`), newSeries(testSize, offset(46, identity), `{app="bar"}`),
+					newSeries(testSize, factor(5, identity), `{app="fuzz"}`), newSeries(testSize, identity, `{app="buzz"}`),
+				},
+			},
+			[]SelectSampleParams{
+				{&logproto.SampleQueryRequest{Start: time.Unix(0, 0), End: time.Unix(60, 0), Selector: `rate({app=~"foo|bar"}|~".+bar"[1m])`}},
+			},
+			promql.Vector{
+				{T: 60 * 1000, F: 1, Metric: labels.Labels{}},
+			},
+		},
+		{
+			`quantile(0.5, rate(({app=~"foo|bar"} |~".+bar")[1m]))`, time.Unix(60, 0), logproto.FORWARD, 100,
+			[][]logproto.Series{
+				{
+					newSeries(testSize, factor(10, identity), `{app="foo"}`), newSeries(testSize, offset(46, identity), `{app="bar"}`),
+					newSeries(testSize, factor(5, identity), `{app="fuzz"}`), newSeries(testSize, identity, `{app="buzz"}`),
+				},
+			},
+			[]SelectSampleParams{
+				{&logproto.SampleQueryRequest{Start: time.Unix(0, 0), End: time.Unix(60, 0), Selector: `rate({app=~"foo|bar"}|~".+bar"[1m])`}},
+			},
+			promql.Vector{
+				{T: 60 * 1000, F: 0.225, Metric: labels.Labels{}},
+			},
+		},
+		{
+			`count_values("value", clamp_max(rate(({app=~"foo|bar"} |~".+bar")[1m]), 0.2))`, time.Unix(60, 0), logproto.FORWARD, 100,
+			[][]logproto.Series{
+				{
+					newSeries(testSize, factor(10, identity), `{app="foo"}`), newSeries(testSize, offset(46, identity), `{app="bar"}`),
+					newSeries(testSize, factor(5, identity), `{app="fuzz"}`), newSeries(testSize, identity, `{app="buzz"}`),
+				},
+			},
+			[]SelectSampleParams{
+				{&logproto.SampleQueryRequest{Start: time.Unix(0, 0), End: time.Unix(60, 0), Selector: `rate({app=~"foo|bar"}|~".+bar"[1m])`}},
+			},
+			promql.Vector{
+				{T: 60 * 1000, F: 1, Metric: labels.FromStrings("value", "0.1")},
+				{T: 60 * 1000, F: 3, Metric: labels.FromStrings("value", "0.2")},
+			},
+		},
+		{
+			`abs(rate(({app=~"foo|bar"} |~".+bar")[1m]) - 1)`, time.Unix(60, 0), logproto.FORWARD, 100,
+			[][]logproto.Series{
+				{
+					newSeries(testSize, factor(10, identity), `{app="foo"}`), newSeries(testSize, offset(46, identity), `{app="bar"}`),
+					newSeries(testSize, factor(5, identity), `{app="fuzz"}`), newSeries(testSize, identity, `{app="buzz"}`),
+				},
+			},
+			[]SelectSampleParams{
+				{&logproto.SampleQueryRequest{Start: time.Unix(0, 0), End: time.Unix(60, 0), Selector: `rate({app=~"foo|bar"}|~".+bar"[1m])`}},
+			},
+			promql.Vector{
+				{T: 60 * 1000, F: 0.75, Metric: labels.FromStrings("app", "bar")},
+				{T: 60 * 1000, F: 0, Metric: labels.FromStrings("app", "buzz")},
+				{T: 60 * 1000, F: 0.9, Metric: labels.FromStrings("app", "foo")},
+				{T: 60 * 1000, F: 0.8, Metric: labels.FromStrings("app", "fuzz")},
+			},
+		},
+		{
+			`label_join(rate(({app=~"foo|bar"} |~".+bar")[1m]), "dst", "-", "app", "app")`, time.Unix(60, 0), logproto.FORWARD, 100,
+			[][]logproto.Series{
+				{
+					newSeries(testSize, factor(10, identity), `{app="foo"}`), newSeries(testSize, offset(46, identity), `{app="bar"}`),
+					newSeries(testSize, factor(5, identity), `{app="fuzz"}`), newSeries(testSize, identity, `{app="buzz"}`),
+				},
+			},
+			[]SelectSampleParams{
+				{&logproto.SampleQueryRequest{Start: time.Unix(0, 0), End: time.Unix(60, 0), Selector: `rate({app=~"foo|bar"}|~".+bar"[1m])`}},
+			},
+			promql.Vector{
+				{T: 60 * 1000, F: 0.25, Metric: labels.FromStrings("app", "bar", "dst", "bar-bar")},
+				{T: 60 * 1000, F: 1, Metric: labels.FromStrings("app", "buzz", "dst", "buzz-buzz")},
+				{T: 60 * 1000, F: 0.1, Metric: labels.FromStrings("app", "foo", "dst", "foo-foo")},
+				{T: 60 * 1000, F: 0.2, Metric: labels.FromStrings("app", "fuzz", "dst", "fuzz-fuzz")},
+			},
+		},
 		{
 			// healthcheck
 			`1+1`, time.Unix(60, 0), logproto.FORWARD, 100,
@@ -989,6 +1072,28 @@ func TestEngine_RangeQuery(t *testing.T) {
 			},
 			logqlmodel.Streams([]logproto.Stream{newStream(10, identity, `{app="foo"}`)}),
 		},
+		{
+			// the dedup stage is applied again when merging the results of the queriers.
+			`{app="foo"} | dedup`, time.Unix(0, 0), time.Unix(30, 0), time.Second, 0, logproto.FORWARD, 10,
+			[][]logproto.Stream{
+				{newStream(testSize, identity, `{app="foo", replica="a"}`), newStream(testSize, identity, `{app="foo", replica="b"}`)},
+			},
+			[]SelectLogParams{
+				{&logproto.QueryRequest{Direction: logproto.FORWARD, Start: time.Unix(0, 0), End: time.Unix(30, 0), Limit: 10, Selector: `{app="foo"} | dedup`}},
+			},
+			logqlmodel.Streams([]logproto.Stream{newStream(10, identity, `{app="foo", replica="a"}`)}),
+		},
+		{
+			// the limit stage is applied when merging the results of the queriers.
+			`{app="foo"} | limit 3 by (app)`, time.Unix(0, 0), time.Unix(30, 0), time.Second, 0, logproto.FORWARD, 10,
+			[][]logproto.Stream{
+				{newStream(testSize, identity, `{app="foo", replica="a"}`), newStream(testSize, identity, `{app="foo", replica="b"}`)},
+			},
+			[]SelectLogParams{
+				{&logproto.QueryRequest{Direction: logproto.FORWARD, Start: time.Unix(0, 0), End: time.Unix(30, 0), Limit: 10, Selector: `{app="foo"} | limit 3 by (app)`}},
+			},
+			logqlmodel.Streams([]logproto.Stream{newStream(2, identity, `{app="foo", replica="a"}`), newStream(1, identity, `{app="foo", replica="b"}`)}),
+		},
 		{
 			`{app="food"}`, time.Unix(0, 0), time.Unix(30, 0), 0, 2 * time.Second, logproto.FORWARD, 10,
 			[][]logproto.Stream{
@@ -1059,6 +1164,36 @@ func TestEngine_RangeQuery(t *testing.T) {
 				},
 			},
 		},
+		{
+			`max_over_time(sum(count_over_time({app="foo"}[10s]))[30s:10s])`, time.Unix(60, 0), time.Unix(120, 0), 30 * time.Second, 0, logproto.FORWARD, 10,
+			[][]logproto.Series{
+				{newSeries(testSize, identity, `{app="foo"}`)},
+			},
+			[]SelectSampleParams{
+				{&logproto.SampleQueryRequest{Start: time.Unix(20, 0), End: time.Unix(120, 0), Selector: `sum(count_over_time({app="foo"}[10s]))`}},
+			},
+			promql.Matrix{
+				promql.Series{
+					Metric: labels.EmptyLabels(),
+					Floats: []promql.FPoint{{T: 60 * 1000, F: 10}, {T: 90 * 1000, F: 10}, {T: 120 * 1000, F: 10}},
+				},
+			},
+		},
+		{
+			`count_over_time(count_over_time({app="foo"}[10s])[35s:10s] offset 5s)`, time.Unix(60, 0), time.Unix(120, 0), 30 * time.Second, 0, logproto.FORWARD, 10,
+			[][]logproto.Series{
+				{newSeries(testSize, identity, `{app="foo"}`)},
+			},
+			[]SelectSampleParams{
+				{&logproto.SampleQueryRequest{Start: time.Unix(10, 0), End: time.Unix(115, 0), Selector: `count_over_time({app="foo"}[10s])`}},
+			},
+			promql.Matrix{
+				promql.Series{
+					Metric: labels.FromStrings("app", "foo"),
+					Floats: []promql.FPoint{{T: 60 * 1000, F: 3}, {T: 90 * 1000, F: 3}, {T: 120 * 1000, F: 3}},
+				},
+			},
+		},
 		{
 			`count_over_time({app="foo"} |~".+bar" [1m])`, time.Unix(60, 0), time.Unix(120, 0), 30 * time.Second, 0, logproto.BACKWARD, 10,
 			[][]logproto.Series{
@@ -1583,6 +1718,88 @@ func TestEngine_RangeQuery(t *testing.T) {
 					Floats: []promql.FPoint{{T: 60000, F: 0.03333333333333333}, {T: 80000, F: 0.06666666666666667}, {T: 100000, F: 0.06666666666666667}, {T: 120000, F: 0.03333333333333333}, {T: 180000, F: 0.03333333333333333}}},
 			},
 		},
+		{
+			`time()`,
+			time.Unix(60, 0), time.Unix(180, 0), 60 * time.Second, 0, logproto.FORWARD, 100,
+			nil,
+			nil,
+			promql.Matrix{
+				promql.Series{
+					Metric: labels.Labels(nil),
+					Floats: []promql.FPoint{{T: 60 * 1000, F: 60}, {T: 120 * 1000, F: 120}, {T: 180 * 1000, F: 180}},
+				},
+			},
+		},
+		{
+			`count_over_time({app="foo"}[1m]) / scalar(sum(count_over_time({app=~"foo|bar"}[1m])))`,
+			time.Unix(60, 0), time.Unix(180, 0), 60 * time.Second, 0, logproto.FORWARD, 100,
+			[][]logproto.Series{
+				{newSeries(testSize, identity, `{app="foo"}`)},
+				{newSeries(testSize, identity, `{app="foo"}`), newSeries(testSize, factor(2, identity), `{app="bar"}`)},
+			},
+			[]SelectSampleParams{
+				{&logproto.SampleQueryRequest{Start: time.Unix(0, 0), End: time.Unix(180, 0), Selector: `count_over_time({app="foo"}[1m])`}},
+				{&logproto.SampleQueryRequest{Start: time.Unix(0, 0), End: time.Unix(180, 0), Selector: `sum(count_over_time({app=~"foo|bar"}[1m]))`}},
+			},
+			promql.Matrix{
+				promql.Series{
+					Metric: labels.FromStrings("app", "foo"),
+					Floats: []promql.FPoint{{T: 60 * 1000, F: 0.6666666666666666}, {T: 120 * 1000, F: 0.6666666666666666}, {T: 180 * 1000, F: 0.6666666666666666}},
+				},
+			},
+		},
+		{
+			`absent(count_over_time({app="foo"}[1m]))`,
+			time.Unix(60, 0), time.Unix(180, 0), 60 * time.Second, 0, logproto.FORWARD, 100,
+			[][]logproto.Series{
+				{newSeries(70, identity, `{app="foo"}`)},
+			},
+			[]SelectSampleParams{
+				{&logproto.SampleQueryRequest{Start: time.Unix(0, 0), End: time.Unix(180, 0), Selector: `count_over_time({app="foo"}[1m])`}},
+			},
+			promql.Matrix{
+				promql.Series{
+					Metric: labels.FromStrings("app", "foo"),
+					Floats: []promql.FPoint{{T: 180 * 1000, F: 1}},
+				},
+			},
+		},
+		{
+			`histogram_quantile(0.5, sum by (le) (count_over_time({app="foo"} | logfmt [1m])))`,
+			time.Unix(60, 0), time.Unix(120, 0), 60 * time.Second, 0, logproto.FORWARD, 100,
+			[][]logproto.Series{
+				{
+					newSeries(testSize, factor(4, identity), `{app="foo", le="0.5"}`),
+					newSeries(testSize, factor(2, identity), `{app="foo", le="1"}`),
+					newSeries(testSize, identity, `{app="foo", le="+Inf"}`),
+				},
+			},
+			[]SelectSampleParams{
+				{&logproto.SampleQueryRequest{Start: time.Unix(0, 0), End: time.Unix(120, 0), Selector: `sum by (le) (count_over_time({app="foo"} | logfmt [1m]))`}},
+			},
+			promql.Matrix{
+				promql.Series{
+					Metric: labels.Labels{},
+					Floats: []promql.FPoint{{T: 60 * 1000, F: 1}, {T: 120 * 1000, F: 1}},
+				},
+			},
+		},
+		{
+			`deriv(({app="foo"} | unwrap foo)[1m])`,
+			time.Unix(60, 0), time.Unix(120, 0), 60 * time.Second, 0, logproto.FORWARD, 100,
+			[][]logproto.Series{
+				{newSeries(testSize, factor(2, incValue(1)), `{app="foo"}`)},
+			},
+			[]SelectSampleParams{
+				{&logproto.SampleQueryRequest{Start: time.Unix(0, 0), End: time.Unix(120, 0), Selector: `deriv({app="foo"}|unwrap foo[1m])`}},
+			},
+			promql.Matrix{
+				promql.Series{
+					Metric: labels.FromStrings("app", "foo"),
+					Floats: []promql.FPoint{{T: 60 * 1000, F: 1}, {T: 120 * 1000, F: 1}},
+				},
+			},
+		},
 		{
 			`
 			rate({app=~"foo|bar"}[1m]) and
@@ -2472,6 +2689,88 @@ func TestEngine_MaxRangeInterval(t *testing.T) {
 	}
 }
 
+func TestEngine_RangeQuery_Calendar(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	require.NoError(t, err)
+	cal := &Calendar{Unit: CalendarDay, Location: paris, Ranges: true}
+	fixed := &Calendar{Unit: CalendarDay, Location: paris}
+
+	// a sample per hour from 2023-03-24T00:00:00Z, the 26th of March lasts 23 hours in Paris.
+	first := time.Date(2023, 3, 24, 0, 0, 0, 0, time.UTC)
+	querier := &querierRecorder{
+		series: map[string][]logproto.Series{
+			"": {newSeries(96, offset(first.Unix()/3600, factor(3600, identity)), `{app="foo"}`)},
+		},
+	}
+	eng := NewEngine(EngineOpts{}, querier, NoLimits, log.NewNopLogger())
+
+	steps := []int64{
+		time.Date(2023, 3, 25, 0, 0, 0, 0, paris).UnixMilli(),
+		time.Date(2023, 3, 26, 0, 0, 0, 0, paris).UnixMilli(),
+		time.Date(2023, 3, 27, 0, 0, 0, 0, paris).UnixMilli(),
+	}
+	for _, test := range []struct {
+		qs       string
+		cal      *Calendar
+		expected promql.Matrix
+	}{
+		{
+			`count_over_time({app="foo"}[1d])`,
+			cal,
+			promql.Matrix{promql.Series{
+				Metric: labels.FromStrings("app", "foo"),
+				Floats: []promql.FPoint{{T: steps[0], F: 24}, {T: steps[1], F: 24}, {T: steps[2], F: 23}},
+			}},
+		},
+		{
+			`sum(count_over_time({app="foo"}[1d]))`,
+			cal,
+			promql.Matrix{promql.Series{
+				Metric: labels.EmptyLabels(),
+				Floats: []promql.FPoint{{T: steps[0], F: 24}, {T: steps[1], F: 24}, {T: steps[2], F: 23}},
+			}},
+		},
+		{
+			`count_over_time({app="foo"}[1h])`,
+			cal,
+			promql.Matrix{promql.Series{
+				Metric: labels.FromStrings("app", "foo"),
+				Floats: []promql.FPoint{{T: steps[0], F: 1}, {T: steps[1], F: 1}, {T: steps[2], F: 1}},
+			}},
+		},
+		{
+			`vector(1)`,
+			cal,
+			promql.Matrix{promql.Series{
+				Floats: []promql.FPoint{{T: steps[0], F: 1}, {T: steps[1], F: 1}, {T: steps[2], F: 1}},
+			}},
+		},
+		{
+			// without covering the ranges, the last range still lasts 24 hours.
+			`count_over_time({app="foo"}[1d])`,
+			fixed,
+			promql.Matrix{promql.Series{
+				Metric: labels.FromStrings("app", "foo"),
+				Floats: []promql.FPoint{{T: steps[0], F: 24}, {T: steps[1], F: 24}, {T: steps[2], F: 24}},
+			}},
+		},
+	} {
+		t.Run(test.qs+"@"+test.cal.String(), func(t *testing.T) {
+			q := eng.Query(LiteralParams{
+				qs:        test.qs,
+				start:     time.Date(2023, 3, 25, 0, 0, 0, 0, paris),
+				end:       time.Date(2023, 3, 27, 0, 0, 0, 0, paris),
+				step:      cal.Step(),
+				direction: logproto.FORWARD,
+				limit:     1000,
+			}.WithCalendar(test.cal))
+			res, err := q.Exec(user.InjectOrgID(context.Background(), "fake"))
+			require.NoError(t, err)
+			require.Equal(t, test.expected, res.Data)
+		})
+	}
+}
+
 // go test -mod=vendor ./pkg/logql/ -bench=.  -benchmem -memprofile memprofile.out -cpuprofile cpuprofile.out
 func BenchmarkRangeQuery100000(b *testing.B) {
 	benchmarkRangeQuery(int64(100000), b)
@@ -6,9 +6,12 @@ import (
 	"fmt"
 	"math"
 	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
+	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/model/labels"
 	"github.com/prometheus/prometheus/promql"
 	"golang.org/x/sync/errgroup"
@@ -16,6 +19,7 @@ import (
 	"github.com/grafana/loki/pkg/iter"
 	"github.com/grafana/loki/pkg/logproto"
 	"github.com/grafana/loki/pkg/logql/syntax"
+	"github.com/grafana/loki/pkg/logql/vector"
 	"github.com/grafana/loki/pkg/logqlmodel"
 	"github.com/grafana/loki/pkg/util"
 )
@@ -39,6 +43,8 @@ type Params interface {
 	Limit() uint32
 	Direction() logproto.Direction
 	Shards() []string
+	// Calendar returns the calendar the steps of the query are aligned to, nil for fixed steps.
+	Calendar() *Calendar
 }
 
 func NewLiteralParams(
@@ -69,6 +75,7 @@ type LiteralParams struct {
 	direction      logproto.Direction
 	limit          uint32
 	shards         []string
+	calendar       *Calendar
 }
 
 func (p LiteralParams) Copy() LiteralParams { return p }
@@ -97,6 +104,15 @@ func (p LiteralParams) Direction() logproto.Direction { return p.direction }
 // Shards impls Params
 func (p LiteralParams) Shards() []string { return p.shards }
 
+// Calendar impls Params
+func (p LiteralParams) Calendar() *Calendar { return p.calendar }
+
+// WithCalendar returns the params with their steps aligned to the calendar.
+func (p LiteralParams) WithCalendar(c *Calendar) LiteralParams {
+	p.calendar = c
+	return p
+}
+
 // GetRangeType returns whether a query is an instant query or range query
 func GetRangeType(q Params) QueryRangeType {
 	if q.Start() == q.End() && q.Step() == 0 {
@@ -199,7 +215,7 @@ func (ev *DefaultEvaluator) NewStepEvaluator(
 			nextEvFactory = SampleEvaluatorFunc(func(ctx context.Context, _ SampleEvaluatorFactory, _ syntax.SampleExpr, _ Params) (StepEvaluator, error) {
 				it, err := ev.querier.SelectSamples(ctx, SelectSampleParams{
 					&logproto.SampleQueryRequest{
-						Start:    q.Start().Add(-rangExpr.Left.Interval).Add(-rangExpr.Left.Offset),
+						Start:    rangeSelectStart(q, rangExpr.Left.Interval, rangExpr.Left.Offset),
 						End:      q.End().Add(-rangExpr.Left.Offset),
 						Selector: e.String(), // intentionally send the vector for reducing labels.
 						Shards:   q.Shards(),
@@ -215,7 +231,7 @@ func (ev *DefaultEvaluator) NewStepEvaluator(
 	case *syntax.RangeAggregationExpr:
 		it, err := ev.querier.SelectSamples(ctx, SelectSampleParams{
 			&logproto.SampleQueryRequest{
-				Start:    q.Start().Add(-e.Left.Interval).Add(-e.Left.Offset),
+				Start:    rangeSelectStart(q, e.Left.Interval, e.Left.Offset),
 				End:      q.End().Add(-e.Left.Offset),
 				Selector: expr.String(),
 				Shards:   q.Shards(),
@@ -225,16 +241,24 @@ func (ev *DefaultEvaluator) NewStepEvaluator(
 			return nil, err
 		}
 		return newRangeAggEvaluator(iter.NewPeekingSampleIterator(it), e, q, e.Left.Offset)
+	case *syntax.SubqueryAggregationExpr:
+		return newSubqueryAggEvaluator(ctx, nextEvFactory, e, q)
 	case *syntax.BinOpExpr:
 		return newBinOpStepEvaluator(ctx, nextEvFactory, e, q)
 	case *syntax.LabelReplaceExpr:
 		return newLabelReplaceEvaluator(ctx, nextEvFactory, e, q)
+	case *syntax.LabelJoinExpr:
+		return newLabelJoinEvaluator(ctx, nextEvFactory, e, q)
+	case *syntax.FunctionExpr:
+		return newFunctionEvaluator(ctx, nextEvFactory, e, q)
 	case *syntax.VectorExpr:
 		val, err := e.Value()
 		if err != nil {
 			return nil, err
 		}
-		return newVectorIterator(val, q.Step().Milliseconds(), q.Start().UnixMilli(), q.End().UnixMilli()), nil
+		return newVectorIterator(val, q.Step().Milliseconds(), q.Start().UnixMilli(), q.End().UnixMilli(), q.Calendar()), nil
+	case *syntax.TimeExpr:
+		return newTimeIterator(q.Step().Milliseconds(), q.Start().UnixMilli(), q.End().UnixMilli(), q.Calendar()), nil
 	default:
 		return nil, EvaluatorUnsupportedType(e, ev)
 	}
@@ -255,9 +279,17 @@ func newVectorAggEvaluator(
 	}
 	sort.Strings(expr.Grouping.Groups)
 
+	grouping := expr.Grouping
+	if expr.Operation == syntax.OpTypeCountValues && !grouping.Without {
+		// the label holding the counted values is always part of the groups.
+		grouping = &syntax.Grouping{Groups: append([]string{expr.Label}, expr.Grouping.Groups...)}
+		sort.Strings(grouping.Groups)
+	}
+
 	return &VectorAggEvaluator{
 		nextEvaluator: nextEvaluator,
 		expr:          expr,
+		grouping:      grouping,
 		buf:           make([]byte, 0, 1024),
 		lb:            labels.NewBuilder(nil),
 	}, nil
@@ -266,6 +298,7 @@ func newVectorAggEvaluator(
 type VectorAggEvaluator struct {
 	nextEvaluator StepEvaluator
 	expr          *syntax.VectorAggregationExpr
+	grouping      *syntax.Grouping
 	buf           []byte
 	lb            *labels.Builder
 }
@@ -285,27 +318,32 @@ func (e *VectorAggEvaluator) Next() (bool, int64, StepResult) {
 	}
 	for _, s := range vec {
 		metric := s.Metric
+		if e.expr.Operation == syntax.OpTypeCountValues {
+			e.lb.Reset(metric)
+			e.lb.Set(e.expr.Label, strconv.FormatFloat(s.F, 'f', -1, 64))
+			metric = e.lb.Labels()
+		}
 
 		var groupingKey uint64
-		if e.expr.Grouping.Without {
-			groupingKey, e.buf = metric.HashWithoutLabels(e.buf, e.expr.Grouping.Groups...)
+		if e.grouping.Without {
+			groupingKey, e.buf = metric.HashWithoutLabels(e.buf, e.grouping.Groups...)
 		} else {
-			groupingKey, e.buf = metric.HashForLabels(e.buf, e.expr.Grouping.Groups...)
+			groupingKey, e.buf = metric.HashForLabels(e.buf, e.grouping.Groups...)
 		}
 		group, ok := result[groupingKey]
 		// Add a new group if it doesn't exist.
 		if !ok {
 			var m labels.Labels
 
-			if e.expr.Grouping.Without {
+			if e.grouping.Without {
 				e.lb.Reset(metric)
-				e.lb.Del(e.expr.Grouping.Groups...)
+				e.lb.Del(e.grouping.Groups...)
 				e.lb.Del(labels.MetricName)
 				m = e.lb.Labels()
 			} else {
-				m = make(labels.Labels, 0, len(e.expr.Grouping.Groups))
+				m = make(labels.Labels, 0, len(e.grouping.Groups))
 				for _, l := range metric {
-					for _, n := range e.expr.Grouping.Groups {
+					for _, n := range e.grouping.Groups {
 						if l.Name == n {
 							m = append(m, l)
 							break
@@ -352,6 +390,8 @@ func (e *VectorAggEvaluator) Next() (bool, int64, StepResult) {
 					F:      s.F,
 					Metric: s.Metric,
 				})
+			} else if e.expr.Operation == syntax.OpTypeQuantile {
+				result[groupingKey].heap = vectorByValueHeap{promql.Sample{F: s.F}}
 			}
 			continue
 		}
@@ -373,9 +413,15 @@ func (e *VectorAggEvaluator) Next() (bool, int64, StepResult) {
 				group.value = s.F
 			}
 
-		case syntax.OpTypeCount:
+		case syntax.OpTypeCount, syntax.OpTypeCountValues:
 			group.groupCount++
 
+		case syntax.OpTypeGroup:
+			// the value of a group is always 1.
+
+		case syntax.OpTypeQuantile:
+			group.heap = append(group.heap, promql.Sample{F: s.F})
+
 		case syntax.OpTypeStddev, syntax.OpTypeStdvar:
 			group.groupCount++
 			delta := s.F - group.mean
@@ -423,9 +469,15 @@ func (e *VectorAggEvaluator) Next() (bool, int64, StepResult) {
 		case syntax.OpTypeAvg:
 			aggr.value = aggr.mean
 
-		case syntax.OpTypeCount:
+		case syntax.OpTypeCount, syntax.OpTypeCountValues:
 			aggr.value = float64(aggr.groupCount)
 
+		case syntax.OpTypeGroup:
+			aggr.value = 1
+
+		case syntax.OpTypeQuantile:
+			aggr.value = Quantile(e.expr.Quantile, vector.HeapByMaxValue(aggr.heap))
+
 		case syntax.OpTypeStddev:
 			aggr.value = math.Sqrt(aggr.value / float64(aggr.groupCount))
 
@@ -485,7 +537,7 @@ func newRangeAggEvaluator(
 		it, expr,
 		expr.Left.Interval.Nanoseconds(),
 		q.Step().Nanoseconds(),
-		q.Start().UnixNano(), q.End().UnixNano(), o.Nanoseconds(),
+		q.Start().UnixNano(), q.End().UnixNano(), o.Nanoseconds(), q.Calendar(),
 	)
 	if err != nil {
 		return nil, err
@@ -505,6 +557,129 @@ func newRangeAggEvaluator(
 	}, nil
 }
 
+// rangeSelectStart returns the start of the samples selected by a range aggregation over selRange shifted by offset.
+// Aligned to a calendar, the range can cover a period ending at the first step longer than selRange.
+func rangeSelectStart(q Params, selRange, offset time.Duration) time.Time {
+	start := q.Start().Add(-selRange)
+	if cal := q.Calendar(); cal != nil && cal.Covers(selRange) {
+		if first := time.Unix(0, cal.Prev(cal.Ceil(q.Start()).UnixNano())); first.Before(start) {
+			start = first
+		}
+	}
+	return start.Add(-offset)
+}
+
+// defaultSubqueryStep is the resolution of subqueries without step within instant queries.
+const defaultSubqueryStep = time.Minute
+
+// newSubqueryAggEvaluator evaluates the subquery expression at the resolution of the subquery,
+// and aggregates the resulting samples over the range of the subquery at each step of the query.
+func newSubqueryAggEvaluator(
+	ctx context.Context,
+	evFactory SampleEvaluatorFactory,
+	expr *syntax.SubqueryAggregationExpr,
+	q Params,
+) (StepEvaluator, error) {
+	step := expr.Left.Step
+	if step == 0 {
+		step = q.Step()
+	}
+	if step == 0 {
+		step = defaultSubqueryStep
+	}
+	// without a step, a subquery inherits the steps of the query aligned to the calendar.
+	cal := q.Calendar()
+	if expr.Left.Step != 0 {
+		cal = nil
+	}
+	start := rangeSelectStart(q, expr.Left.Range, expr.Left.Offset).UnixNano()
+	if cal == nil {
+		// like in PromQL, the steps of a subquery are aligned to multiples of the step.
+		start -= start % step.Nanoseconds()
+	}
+	end := q.End().Add(-expr.Left.Offset)
+	subqueryParams := NewLiteralParams(expr.Left.Left.String(), time.Unix(0, start), end, step, q.Interval(), q.Direction(), q.Limit(), q.Shards()).WithCalendar(cal)
+
+	nextEvaluator, err := evFactory.NewStepEvaluator(ctx, evFactory, expr.Left.Left, subqueryParams)
+	if err != nil {
+		return nil, err
+	}
+
+	// the range vector iterators only use the operation, the parameter and the range of the aggregation.
+	rangeExpr := &syntax.RangeAggregationExpr{
+		Left:      &syntax.LogRange{Interval: expr.Left.Range},
+		Operation: expr.Operation,
+		Params:    expr.Params,
+	}
+	iter, err := newRangeVectorIterator(
+		iter.NewPeekingSampleIterator(&stepEvaluatorSampleIterator{nextEvaluator: nextEvaluator}), rangeExpr,
+		expr.Left.Range.Nanoseconds(),
+		q.Step().Nanoseconds(),
+		q.Start().UnixNano(), q.End().UnixNano(), expr.Left.Offset.Nanoseconds(), q.Calendar(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	var rangeEvaluator StepEvaluator = &RangeVectorEvaluator{iter: iter}
+	if expr.Operation == syntax.OpRangeTypeAbsent {
+		absentLabels, err := absentLabels(expr)
+		if err != nil {
+			return nil, err
+		}
+		rangeEvaluator = &AbsentRangeVectorEvaluator{
+			iter: iter,
+			lbs:  absentLabels,
+		}
+	}
+	return &SubqueryEvaluator{
+		StepEvaluator: rangeEvaluator,
+		nextEvaluator: nextEvaluator,
+		expr:          expr,
+	}, nil
+}
+
+// SubqueryEvaluator is the range aggregation of the samples of a subquery.
+type SubqueryEvaluator struct {
+	StepEvaluator
+	nextEvaluator StepEvaluator
+	expr          *syntax.SubqueryAggregationExpr
+}
+
+// stepEvaluatorSampleIterator iterates over the samples of all the steps of a StepEvaluator.
+type stepEvaluatorSampleIterator struct {
+	nextEvaluator StepEvaluator
+	ts            int64
+	vec           promql.Vector
+	cur           int
+}
+
+func (it *stepEvaluatorSampleIterator) Next() bool {
+	it.cur++
+	for it.cur >= len(it.vec) {
+		next, ts, r := it.nextEvaluator.Next()
+		if !next {
+			return false
+		}
+		it.ts, it.vec, it.cur = ts, r.SampleVector(), 0
+	}
+	return true
+}
+
+func (it *stepEvaluatorSampleIterator) Sample() logproto.Sample {
+	s := it.vec[it.cur]
+	return logproto.Sample{
+		// the step evaluators work with milliseconds whereas the iterators work with nanoseconds.
+		Timestamp: it.ts * int64(time.Millisecond),
+		Value:     s.F,
+		Hash:      s.Metric.Hash(),
+	}
+}
+
+func (it *stepEvaluatorSampleIterator) Labels() string     { return it.vec[it.cur].Metric.String() }
+func (it *stepEvaluatorSampleIterator) StreamHash() uint64 { return it.vec[it.cur].Metric.Hash() }
+func (it *stepEvaluatorSampleIterator) Error() error       { return it.nextEvaluator.Error() }
+func (it *stepEvaluatorSampleIterator) Close() error       { return it.nextEvaluator.Close() }
+
 type RangeVectorEvaluator struct {
 	iter RangeVectorIterator
 
@@ -647,6 +822,14 @@ func newBinOpStepEvaluator(
 		return nil, err
 	}
 
+	// like literals, scalars are merged with all labels in the other leg
+	if isScalarExpr(expr.SampleExpr) {
+		return newScalarStepEvaluator(expr.Op, lse, rse, false, expr.Opts.ReturnBool), nil
+	}
+	if isScalarExpr(expr.RHS) {
+		return newScalarStepEvaluator(expr.Op, rse, lse, true, expr.Opts.ReturnBool), nil
+	}
+
 	return &BinOpStepEvaluator{
 		rse:  rse,
 		lse:  lse,
@@ -941,8 +1124,41 @@ func newLiteralStepEvaluator(
 	}, nil
 }
 
+// newScalarStepEvaluator merges the value of a scalar expression, e.g. time(), with a StepEvaluator
+// at each step. Since order matters in non-commutative operations, inverted should be true when
+// the scalar is not the left argument.
+func newScalarStepEvaluator(
+	op string,
+	scalarEv StepEvaluator,
+	nextEv StepEvaluator,
+	inverted bool,
+	returnBool bool,
+) *LiteralStepEvaluator {
+	return &LiteralStepEvaluator{
+		nextEv:     nextEv,
+		scalarEv:   scalarEv,
+		inverted:   inverted,
+		op:         op,
+		returnBool: returnBool,
+	}
+}
+
+// isScalarExpr tells if a sample expression returns a scalar, i.e. a single sample without labels at each step.
+func isScalarExpr(expr syntax.SampleExpr) bool {
+	switch e := expr.(type) {
+	case *syntax.TimeExpr:
+		return true
+	case *syntax.FunctionExpr:
+		return e.Function == syntax.OpFuncScalar
+	default:
+		return false
+	}
+}
+
 type LiteralStepEvaluator struct {
-	nextEv     StepEvaluator
+	nextEv StepEvaluator
+	// scalarEv, if set, yields the value to merge at each step instead of val.
+	scalarEv   StepEvaluator
 	mergeErr   error
 	val        float64
 	inverted   bool
@@ -955,6 +1171,15 @@ func (e *LiteralStepEvaluator) Next() (bool, int64, StepResult) {
 	if !ok {
 		return ok, ts, r
 	}
+	val := e.val
+	if e.scalarEv != nil {
+		val = math.NaN()
+		if next, _, sr := e.scalarEv.Next(); next {
+			if scalar := sr.SampleVector(); len(scalar) == 1 {
+				val = scalar[0].F
+			}
+		}
+	}
 	vec := r.SampleVector()
 	results := make(promql.Vector, 0, len(vec))
 	for _, sample := range vec {
@@ -962,7 +1187,7 @@ func (e *LiteralStepEvaluator) Next() (bool, int64, StepResult) {
 		literalPoint := promql.Sample{
 			Metric: sample.Metric,
 			T:      ts,
-			F:      e.val,
+			F:      val,
 		}
 
 		left, right := &literalPoint, &sample
@@ -989,6 +1214,12 @@ func (e *LiteralStepEvaluator) Next() (bool, int64, StepResult) {
 }
 
 func (e *LiteralStepEvaluator) Close() error {
+	if e.scalarEv != nil {
+		if err := e.scalarEv.Close(); err != nil {
+			_ = e.nextEv.Close()
+			return err
+		}
+	}
 	return e.nextEv.Close()
 }
 
@@ -996,30 +1227,41 @@ func (e *LiteralStepEvaluator) Error() error {
 	if e.mergeErr != nil {
 		return e.mergeErr
 	}
+	if e.scalarEv != nil {
+		if err := e.scalarEv.Error(); err != nil {
+			return err
+		}
+	}
 	return e.nextEv.Error()
 }
 
 // VectorIterator return simple vector like (1).
 type VectorIterator struct {
 	stepMs, endMs, currentMs int64
+	cal                      *Calendar
 	val                      float64
 }
 
 func newVectorIterator(val float64,
-	stepMs, startMs, endMs int64) *VectorIterator {
+	stepMs, startMs, endMs int64, cal *Calendar) *VectorIterator {
 	if stepMs == 0 {
 		stepMs = 1
 	}
+	currentMs := startMs - stepMs
+	if cal != nil {
+		currentMs = startMs - 1
+	}
 	return &VectorIterator{
 		val:       val,
 		stepMs:    stepMs,
 		endMs:     endMs,
-		currentMs: startMs - stepMs,
+		currentMs: currentMs,
+		cal:       cal,
 	}
 }
 
 func (r *VectorIterator) Next() (bool, int64, StepResult) {
-	r.currentMs = r.currentMs + r.stepMs
+	r.currentMs = nextStep(r.currentMs*1e6, r.stepMs*1e6, 0, r.cal) / 1e6
 	if r.currentMs > r.endMs {
 		return false, 0, nil
 	}
@@ -1037,6 +1279,23 @@ func (r *VectorIterator) Error() error {
 	return nil
 }
 
+// TimeIterator returns the timestamp in seconds of each step, like time().
+type TimeIterator struct {
+	VectorIterator
+}
+
+func newTimeIterator(stepMs, startMs, endMs int64, cal *Calendar) *TimeIterator {
+	return &TimeIterator{VectorIterator: *newVectorIterator(0, stepMs, startMs, endMs, cal)}
+}
+
+func (r *TimeIterator) Next() (bool, int64, StepResult) {
+	next, ts, _ := r.VectorIterator.Next()
+	if !next {
+		return false, 0, nil
+	}
+	return true, ts, SampleVector{promql.Sample{T: ts, F: float64(ts) / 1e3}}
+}
+
 // newLabelReplaceEvaluator
 func newLabelReplaceEvaluator(
 	ctx context.Context,
@@ -1106,6 +1365,282 @@ func (e *LabelReplaceEvaluator) Error() error {
 	return e.nextEvaluator.Error()
 }
 
+// newLabelJoinEvaluator
+func newLabelJoinEvaluator(
+	ctx context.Context,
+	evFactory SampleEvaluatorFactory,
+	expr *syntax.LabelJoinExpr,
+	q Params,
+) (*LabelJoinEvaluator, error) {
+	nextEvaluator, err := evFactory.NewStepEvaluator(ctx, evFactory, expr.Left, q)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LabelJoinEvaluator{
+		nextEvaluator: nextEvaluator,
+		expr:          expr,
+		buf:           make([]byte, 0, 1024),
+	}, nil
+}
+
+type LabelJoinEvaluator struct {
+	nextEvaluator StepEvaluator
+	labelCache    map[uint64]labels.Labels
+	expr          *syntax.LabelJoinExpr
+	buf           []byte
+}
+
+func (e *LabelJoinEvaluator) Next() (bool, int64, StepResult) {
+	next, ts, r := e.nextEvaluator.Next()
+	if !next {
+		return false, 0, SampleVector{}
+	}
+	vec := r.SampleVector()
+	if e.labelCache == nil {
+		e.labelCache = make(map[uint64]labels.Labels, len(vec))
+	}
+	var hash uint64
+	values := make([]string, len(e.expr.Src))
+	for i, s := range vec {
+		hash, e.buf = s.Metric.HashWithoutLabels(e.buf)
+		if labels, ok := e.labelCache[hash]; ok {
+			vec[i].Metric = labels
+			continue
+		}
+		for j, src := range e.expr.Src {
+			values[j] = s.Metric.Get(src)
+		}
+		lb := labels.NewBuilder(s.Metric).Del(e.expr.Dst)
+		if joined := strings.Join(values, e.expr.Separator); joined != "" {
+			lb.Set(e.expr.Dst, joined)
+		}
+		outLbs := lb.Labels()
+		e.labelCache[hash] = outLbs
+		vec[i].Metric = outLbs
+	}
+	return next, ts, SampleVector(vec)
+}
+
+func (e *LabelJoinEvaluator) Close() error {
+	return e.nextEvaluator.Close()
+}
+
+func (e *LabelJoinEvaluator) Error() error {
+	return e.nextEvaluator.Error()
+}
+
+// newFunctionEvaluator
+func newFunctionEvaluator(
+	ctx context.Context,
+	evFactory SampleEvaluatorFactory,
+	expr *syntax.FunctionExpr,
+	q Params,
+) (*FunctionEvaluator, error) {
+	nextEvaluator, err := evFactory.NewStepEvaluator(ctx, evFactory, expr.Left, q)
+	if err != nil {
+		return nil, err
+	}
+	ev := &FunctionEvaluator{
+		nextEvaluator: nextEvaluator,
+		expr:          expr,
+	}
+	if expr.Function == syntax.OpFuncAbsent {
+		// like absent_over_time, the labels of the missing series are taken from the selector of a range aggregation.
+		ev.absentLabels = labels.Labels{}
+		if _, ok := expr.Left.(*syntax.RangeAggregationExpr); ok {
+			ev.absentLabels, err = absentLabels(expr.Left)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return ev, nil
+}
+
+// FunctionEvaluator applies a function to the samples of each step.
+type FunctionEvaluator struct {
+	nextEvaluator StepEvaluator
+	expr          *syntax.FunctionExpr
+	absentLabels  labels.Labels
+}
+
+func (e *FunctionEvaluator) Next() (bool, int64, StepResult) {
+	next, ts, r := e.nextEvaluator.Next()
+	if !next {
+		return false, 0, SampleVector{}
+	}
+	vec := r.SampleVector()
+	switch e.expr.Function {
+	case syntax.OpFuncScalar:
+		// scalar returns NaN unless there is exactly one sample.
+		value := math.NaN()
+		if len(vec) == 1 {
+			value = vec[0].F
+		}
+		return next, ts, SampleVector{promql.Sample{T: ts, F: value}}
+	case syntax.OpFuncAbsent:
+		if len(vec) > 0 {
+			return next, ts, SampleVector{}
+		}
+		return next, ts, SampleVector{promql.Sample{T: ts, F: 1, Metric: e.absentLabels}}
+	case syntax.OpFuncHistogramQuantile:
+		return next, ts, SampleVector(histogramQuantile(*e.expr.Params, vec, ts))
+	}
+	results := make(promql.Vector, 0, len(vec))
+	for _, s := range vec {
+		results = append(results, promql.Sample{
+			Metric: s.Metric,
+			T:      ts,
+			F:      e.apply(s.F, ts),
+		})
+	}
+	return next, ts, SampleVector(results)
+}
+
+// apply returns the result of an element-wise function on a sample value at the given timestamp in milliseconds.
+func (e *FunctionEvaluator) apply(v float64, ts int64) float64 {
+	switch e.expr.Function {
+	case syntax.OpFuncAbs:
+		return math.Abs(v)
+	case syntax.OpFuncCeil:
+		return math.Ceil(v)
+	case syntax.OpFuncFloor:
+		return math.Floor(v)
+	case syntax.OpFuncLn:
+		return math.Log(v)
+	case syntax.OpFuncRound:
+		toNearest := 1.
+		if e.expr.Params != nil {
+			toNearest = *e.expr.Params
+		}
+		// Invert as it seems to cause fewer floating point accuracy issues, like in PromQL.
+		toNearestInverse := 1.0 / toNearest
+		return math.Floor(v*toNearestInverse+0.5) / toNearestInverse
+	case syntax.OpFuncClampMin:
+		return math.Max(*e.expr.Params, v)
+	case syntax.OpFuncClampMax:
+		return math.Min(*e.expr.Params, v)
+	case syntax.OpFuncTimestamp:
+		return float64(ts) / 1e3
+	default:
+		return v
+	}
+}
+
+func (e *FunctionEvaluator) Close() error {
+	return e.nextEvaluator.Close()
+}
+
+func (e *FunctionEvaluator) Error() error {
+	return e.nextEvaluator.Error()
+}
+
+type histogramBucket struct {
+	upperBound float64
+	count      float64
+}
+
+type histogram struct {
+	labels  labels.Labels
+	buckets []histogramBucket
+}
+
+// histogramQuantile calculates the φ-quantile of the histograms of a vector, whose buckets are the series
+// with the same labels except for the `le` label holding the upper bound of the bucket.
+func histogramQuantile(q float64, vec promql.Vector, ts int64) promql.Vector {
+	histograms := map[uint64]*histogram{}
+	buf := make([]byte, 0, 1024)
+	for _, s := range vec {
+		upperBound, err := strconv.ParseFloat(s.Metric.Get(model.BucketLabel), 64)
+		if err != nil {
+			// series without a valid upper bound are not histogram buckets.
+			continue
+		}
+		var hash uint64
+		hash, buf = s.Metric.HashWithoutLabels(buf, model.BucketLabel)
+		h, ok := histograms[hash]
+		if !ok {
+			h = &histogram{labels: labels.NewBuilder(s.Metric).Del(model.BucketLabel).Labels()}
+			histograms[hash] = h
+		}
+		h.buckets = append(h.buckets, histogramBucket{upperBound: upperBound, count: s.F})
+	}
+
+	results := make(promql.Vector, 0, len(histograms))
+	for _, h := range histograms {
+		results = append(results, promql.Sample{
+			Metric: h.labels,
+			T:      ts,
+			F:      bucketQuantile(q, h.buckets),
+		})
+	}
+	return results
+}
+
+// bucketQuantile calculates the quantile q of cumulative histogram buckets with linear interpolation,
+// like the histogram_quantile function of Prometheus.
+func bucketQuantile(q float64, buckets []histogramBucket) float64 {
+	if math.IsNaN(q) {
+		return math.NaN()
+	}
+	if q < 0 {
+		return math.Inf(-1)
+	}
+	if q > 1 {
+		return math.Inf(+1)
+	}
+	sort.Slice(buckets, func(i, j int) bool { return buckets[i].upperBound < buckets[j].upperBound })
+	if !math.IsInf(buckets[len(buckets)-1].upperBound, +1) {
+		return math.NaN()
+	}
+
+	// merge the buckets with the same upper bound, and make the counts monotonic.
+	merged := buckets[:1]
+	for _, b := range buckets[1:] {
+		last := &merged[len(merged)-1]
+		if b.upperBound == last.upperBound {
+			last.count += b.count
+			continue
+		}
+		merged = append(merged, b)
+	}
+	for i := 1; i < len(merged); i++ {
+		if merged[i].count < merged[i-1].count {
+			merged[i].count = merged[i-1].count
+		}
+	}
+	buckets = merged
+
+	if len(buckets) < 2 {
+		return math.NaN()
+	}
+	observations := buckets[len(buckets)-1].count
+	if observations == 0 {
+		return math.NaN()
+	}
+	rank := q * observations
+	b := sort.Search(len(buckets)-1, func(i int) bool { return buckets[i].count >= rank })
+
+	if b == len(buckets)-1 {
+		return buckets[len(buckets)-2].upperBound
+	}
+	if b == 0 && buckets[0].upperBound <= 0 {
+		return buckets[0].upperBound
+	}
+	var (
+		bucketStart float64
+		bucketEnd   = buckets[b].upperBound
+		count       = buckets[b].count
+	)
+	if b > 0 {
+		bucketStart = buckets[b-1].upperBound
+		count -= buckets[b-1].count
+		rank -= buckets[b-1].count
+	}
+	return bucketStart + (bucketEnd-bucketStart)*(rank/count)
+}
+
 // This is to replace missing timeseries during absent_over_time aggregation.
 func absentLabels(expr syntax.SampleExpr) (labels.Labels, error) {
 	m := labels.Labels{}
@@ -2,6 +2,9 @@ package logql
 
 func (e *LiteralStepEvaluator) Explain(parent Node) {
 	b := parent.Child("Literal")
+	if e.scalarEv != nil {
+		e.scalarEv.Explain(b)
+	}
 	e.nextEv.Explain(b)
 }
 
@@ -10,6 +13,16 @@ func (e *LabelReplaceEvaluator) Explain(parent Node) {
 	e.nextEvaluator.Explain(b)
 }
 
+func (e *LabelJoinEvaluator) Explain(parent Node) {
+	b := parent.Childf("%s LabelJoin", e.expr.Dst)
+	e.nextEvaluator.Explain(b)
+}
+
+func (e *FunctionEvaluator) Explain(parent Node) {
+	b := parent.Childf("%s Function", e.expr.Function)
+	e.nextEvaluator.Explain(b)
+}
+
 func (e *VectorAggEvaluator) Explain(parent Node) {
 	b := parent.Childf("[%s, %s] VectorAgg", e.expr.Operation, e.expr.Grouping)
 	e.nextEvaluator.Explain(b)
@@ -53,3 +66,12 @@ func (e *BinOpStepEvaluator) Explain(parent Node) {
 func (i *VectorIterator) Explain(parent Node) {
 	parent.Childf("%f vectorIterator", i.val)
 }
+
+func (i *TimeIterator) Explain(parent Node) {
+	parent.Child("timeIterator")
+}
+
+func (e *SubqueryEvaluator) Explain(parent Node) {
+	b := parent.Childf("[%s, %s] Subquery", e.expr.Operation, e.expr.Left)
+	e.nextEvaluator.Explain(b)
+}
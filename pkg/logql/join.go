@@ -0,0 +1,271 @@
+package logql
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/grafana/dskit/tenant"
+	"github.com/prometheus/prometheus/model/labels"
+	promql_parser "github.com/prometheus/prometheus/promql/parser"
+
+	"github.com/grafana/loki/pkg/iter"
+	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/logql/syntax"
+	"github.com/grafana/loki/pkg/logqlmodel"
+	"github.com/grafana/loki/pkg/util"
+	"github.com/grafana/loki/pkg/util/validation"
+)
+
+const (
+	joinLeft = iota
+	joinRight
+)
+
+// maxJoinValues is the maximum number of streams whose value of the label of a join is cached.
+const maxJoinValues = 100000
+
+// joinSideParams reads all the log lines of a side of a join, the limit of the query applies to the joined lines.
+type joinSideParams struct {
+	Params
+}
+
+func (joinSideParams) Limit() uint32 { return math.MaxUint32 }
+
+// evalJoin evaluates a join of two log queries. The number of keys buffered within the tolerance
+// of the join is limited by the maximum of series of a query, and the number of log lines by the
+// maximum of join entries.
+func (q *query) evalJoin(ctx context.Context, expr *syntax.JoinExpr) (promql_parser.Value, error) {
+	tenantIDs, err := tenant.TenantIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	maxSeriesCapture := func(id string) int { return q.limits.MaxQuerySeries(ctx, id) }
+	maxSeries := validation.SmallestPositiveIntPerTenant(tenantIDs, maxSeriesCapture)
+	maxEntriesCapture := func(id string) int { return q.limits.MaxQueryJoinEntries(ctx, id) }
+	maxEntries := validation.SmallestPositiveIntPerTenant(tenantIDs, maxEntriesCapture)
+
+	var its [2]iter.EntryIterator
+	for i, side := range []syntax.LogSelectorExpr{expr.Left, expr.Right} {
+		it, err := q.joinSideIterator(ctx, side)
+		if err != nil {
+			if i == joinRight {
+				util.LogErrorWithContext(ctx, "closing iterator", its[joinLeft].Close)
+			}
+			return nil, err
+		}
+		its[i] = it
+	}
+	it := newJoinIterator(its[joinLeft], its[joinRight], expr.Label, expr.Tolerance, q.params.Direction(), maxSeries, maxEntries)
+	defer util.LogErrorWithContext(ctx, "closing iterator", it.Close)
+	return readStreams(it, q.params.Limit(), q.params.Direction(), q.params.Interval())
+}
+
+func (q *query) joinSideIterator(ctx context.Context, expr syntax.LogSelectorExpr) (iter.EntryIterator, error) {
+	mergeStages, err := syntax.MergeStages(expr)
+	if err != nil {
+		return nil, err
+	}
+	it, err := q.evaluator.NewIterator(ctx, expr, joinSideParams{q.params})
+	if err != nil {
+		return nil, err
+	}
+	return newMergeStagesIterator(it, mergeStages, q.params.Start(), q.params.End()), nil
+}
+
+type joinEntry struct {
+	key   string
+	side  int
+	entry logproto.Entry
+}
+
+type joinedEntry struct {
+	labels string
+	hash   uint64
+	entry  logproto.Entry
+}
+
+// joinIterator joins the entries of two iterators having the same value for a label, whose timestamps
+// are within a tolerance of each other. Both iterators are read in the order of the query and only the
+// entries within the tolerance of the last entry read are buffered.
+//
+// Each pair of entries is joined into an entry of the stream of its key, at the timestamp of the entry read last,
+// e.g. `{request_id="42"} latency=1.2s left="..." right="..."`, the latency being the time from the left
+// entry to the right entry.
+type joinIterator struct {
+	its       [2]iter.PeekingEntryIterator
+	label     string
+	tolerance time.Duration
+	direction logproto.Direction
+	// maxKeys and maxEntries are the maximum number of keys and entries buffered, zero for no limit.
+	maxKeys    int
+	maxEntries int
+
+	// window holds the buffered entries in the order they were read,
+	// keys holds the same entries for each key and side.
+	window []*joinEntry
+	keys   map[string]*[2][]*joinEntry
+	// values caches the value of the label of the streams of both iterators.
+	values map[string]string
+
+	pending []joinedEntry
+	cur     joinedEntry
+	err     error
+}
+
+func newJoinIterator(left, right iter.EntryIterator, label string, tolerance time.Duration, direction logproto.Direction, maxKeys, maxEntries int) *joinIterator {
+	return &joinIterator{
+		its:        [2]iter.PeekingEntryIterator{iter.NewPeekingIterator(left), iter.NewPeekingIterator(right)},
+		label:      label,
+		tolerance:  tolerance,
+		direction:  direction,
+		maxKeys:    maxKeys,
+		maxEntries: maxEntries,
+		keys:       make(map[string]*[2][]*joinEntry),
+		values:     make(map[string]string),
+	}
+}
+
+func (it *joinIterator) Next() bool {
+	for len(it.pending) == 0 {
+		if it.err != nil || !it.read() {
+			return false
+		}
+	}
+	it.cur = it.pending[0]
+	it.pending = it.pending[1:]
+	return true
+}
+
+// read reads the next entry of both iterators and joins it with the buffered entries of the other side.
+func (it *joinIterator) read() bool {
+	side := -1
+	var next time.Time
+	for i, s := range it.its {
+		if _, e, ok := s.Peek(); ok && (side < 0 || it.first(e.Timestamp, next)) {
+			side, next = i, e.Timestamp
+		}
+	}
+	if side < 0 {
+		return false
+	}
+	s := it.its[side]
+	s.Next()
+	entry := s.Entry()
+
+	it.evict(entry.Timestamp)
+	key, err := it.value(s.Labels())
+	if err != nil {
+		it.err = err
+		return false
+	}
+	if key == "" {
+		return true
+	}
+
+	buf, ok := it.keys[key]
+	if !ok {
+		if it.maxKeys > 0 && len(it.keys) >= it.maxKeys {
+			it.err = logqlmodel.NewSeriesLimitError(it.maxKeys)
+			return false
+		}
+		buf = &[2][]*joinEntry{}
+		it.keys[key] = buf
+	}
+	if len(buf[1-side]) > 0 {
+		lbs := labels.FromStrings(it.label, key)
+		for _, other := range buf[1-side] {
+			left, right := other.entry, entry
+			if side == joinLeft {
+				left, right = entry, other.entry
+			}
+			it.pending = append(it.pending, joinedEntry{
+				labels: lbs.String(),
+				hash:   lbs.Hash(),
+				entry: logproto.Entry{
+					Timestamp: entry.Timestamp,
+					Line:      fmt.Sprintf("latency=%s left=%s right=%s", right.Timestamp.Sub(left.Timestamp), strconv.Quote(left.Line), strconv.Quote(right.Line)),
+				},
+			})
+		}
+	}
+
+	if it.maxEntries > 0 && len(it.window) >= it.maxEntries {
+		it.err = logqlmodel.NewJoinEntriesLimitError(it.maxEntries)
+		return false
+	}
+	e := &joinEntry{key: key, side: side, entry: entry}
+	buf[side] = append(buf[side], e)
+	it.window = append(it.window, e)
+	return true
+}
+
+// first returns true if a is read before b in the order of the query.
+func (it *joinIterator) first(a, b time.Time) bool {
+	if it.direction == logproto.BACKWARD {
+		return a.After(b)
+	}
+	return a.Before(b)
+}
+
+// evict forgets the buffered entries farther than the tolerance from the timestamp.
+func (it *joinIterator) evict(ts time.Time) {
+	for len(it.window) > 0 {
+		e := it.window[0]
+		if d := ts.Sub(e.entry.Timestamp); d <= it.tolerance && d >= -it.tolerance {
+			return
+		}
+		it.window[0] = nil
+		it.window = it.window[1:]
+		buf := it.keys[e.key]
+		buf[e.side] = buf[e.side][1:]
+		if len(buf[joinLeft]) == 0 && len(buf[joinRight]) == 0 {
+			delete(it.keys, e.key)
+		}
+	}
+}
+
+// value returns the value of the label of a stream, the values of at most maxJoinValues streams are cached.
+func (it *joinIterator) value(stream string) (string, error) {
+	if v, ok := it.values[stream]; ok {
+		return v, nil
+	}
+	lbs, err := syntax.ParseLabels(stream)
+	if err != nil {
+		return "", err
+	}
+	if len(it.values) >= maxJoinValues {
+		it.values = make(map[string]string)
+	}
+	v := lbs.Get(it.label)
+	it.values[stream] = v
+	return v, nil
+}
+
+func (it *joinIterator) Entry() logproto.Entry { return it.cur.entry }
+
+func (it *joinIterator) Labels() string { return it.cur.labels }
+
+func (it *joinIterator) StreamHash() uint64 { return it.cur.hash }
+
+func (it *joinIterator) Error() error {
+	if it.err != nil {
+		return it.err
+	}
+	for _, s := range it.its {
+		if err := s.Error(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (it *joinIterator) Close() error {
+	var errs util.MultiError
+	for _, s := range it.its {
+		errs.Add(s.Close())
+	}
+	return errs.Err()
+}
@@ -0,0 +1,153 @@
+package logql
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/user"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/pkg/iter"
+	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/logqlmodel"
+)
+
+func joinTestStreams() []logproto.Stream {
+	return []logproto.Stream{
+		{
+			Labels: `{app="gateway", request_id="1"}`,
+			Entries: []logproto.Entry{
+				{Timestamp: time.Unix(1, 0), Line: "GET /a"},
+			},
+		},
+		{
+			Labels: `{app="gateway", request_id="2"}`,
+			Entries: []logproto.Entry{
+				{Timestamp: time.Unix(3, 0), Line: "GET /b"},
+			},
+		},
+		{
+			Labels: `{app="backend", request_id="1"}`,
+			Entries: []logproto.Entry{
+				{Timestamp: time.Unix(1, 500000000), Line: `select "a"`},
+			},
+		},
+		{
+			Labels: `{app="backend", request_id="2"}`,
+			Entries: []logproto.Entry{
+				{Timestamp: time.Unix(8, 0), Line: `select "b"`},
+			},
+		},
+		{
+			Labels: `{app="backend", request_id="3"}`,
+			Entries: []logproto.Entry{
+				{Timestamp: time.Unix(4, 0), Line: `select "c"`},
+			},
+		},
+		{
+			Labels: `{app="backend"}`,
+			Entries: []logproto.Entry{
+				{Timestamp: time.Unix(1, 0), Line: "no request"},
+			},
+		},
+	}
+}
+
+func TestEngine_Join(t *testing.T) {
+	for _, tc := range []struct {
+		direction logproto.Direction
+		want      logqlmodel.Streams
+	}{
+		{
+			logproto.FORWARD,
+			logqlmodel.Streams{
+				{
+					Labels: `{request_id="1"}`,
+					Entries: []logproto.Entry{
+						{Timestamp: time.Unix(1, 500000000), Line: `latency=500ms left="GET /a" right="select \"a\""`},
+					},
+				},
+			},
+		},
+		{
+			logproto.BACKWARD,
+			logqlmodel.Streams{
+				{
+					Labels: `{request_id="1"}`,
+					Entries: []logproto.Entry{
+						{Timestamp: time.Unix(1, 0), Line: `latency=500ms left="GET /a" right="select \"a\""`},
+					},
+				},
+			},
+		},
+	} {
+		t.Run(tc.direction.String(), func(t *testing.T) {
+			eng := NewEngine(EngineOpts{}, NewMockQuerier(1, joinTestStreams()), NoLimits, log.NewNopLogger())
+			q := eng.Query(LiteralParams{
+				qs:        `join({app="gateway"}, {app="backend"}, "request_id", 2s)`,
+				start:     time.Unix(0, 0),
+				end:       time.Unix(10, 0),
+				step:      time.Second,
+				direction: tc.direction,
+				limit:     100,
+			})
+			res, err := q.Exec(user.InjectOrgID(context.Background(), "fake"))
+			require.NoError(t, err)
+			require.Equal(t, tc.want, res.Data)
+		})
+	}
+}
+
+func Test_joinIterator(t *testing.T) {
+	stream := func(lbs string, ts ...int64) logproto.Stream {
+		s := logproto.Stream{Labels: lbs}
+		for _, t := range ts {
+			s.Entries = append(s.Entries, logproto.Entry{Timestamp: time.Unix(t, 0), Line: lbs})
+		}
+		return s
+	}
+	left := []logproto.Stream{stream(`{id="a", side="left"}`, 1, 2), stream(`{id="b", side="left"}`, 5)}
+	right := []logproto.Stream{stream(`{id="a", side="right"}`, 2, 10), stream(`{id="b", side="right"}`, 4)}
+
+	it := newJoinIterator(
+		iter.NewStreamsIterator(left, logproto.FORWARD),
+		iter.NewStreamsIterator(right, logproto.FORWARD),
+		"id", time.Second, logproto.FORWARD, 0, 0,
+	)
+	var got []string
+	for it.Next() {
+		got = append(got, it.Labels()+" "+it.Entry().Timestamp.Format(time.TimeOnly)+" "+it.Entry().Line)
+	}
+	require.NoError(t, it.Error())
+	require.NoError(t, it.Close())
+	utc := func(sec int64) string { return time.Unix(sec, 0).Format(time.TimeOnly) }
+	require.Equal(t, []string{
+		`{id="a"} ` + utc(2) + ` latency=1s left="{id=\"a\", side=\"left\"}" right="{id=\"a\", side=\"right\"}"`,
+		`{id="a"} ` + utc(2) + ` latency=0s left="{id=\"a\", side=\"left\"}" right="{id=\"a\", side=\"right\"}"`,
+		`{id="b"} ` + utc(5) + ` latency=-1s left="{id=\"b\", side=\"left\"}" right="{id=\"b\", side=\"right\"}"`,
+	}, got)
+}
+
+func Test_joinIteratorLimits(t *testing.T) {
+	left := []logproto.Stream{
+		{Labels: `{id="a"}`, Entries: []logproto.Entry{{Timestamp: time.Unix(1, 0)}}},
+		{Labels: `{id="b"}`, Entries: []logproto.Entry{{Timestamp: time.Unix(2, 0)}}},
+	}
+
+	// the keys are forgotten past the tolerance.
+	it := newJoinIterator(iter.NewStreamsIterator(left, logproto.FORWARD), iter.NoopIterator, "id", 500*time.Millisecond, logproto.FORWARD, 1, 1)
+	require.False(t, it.Next())
+	require.NoError(t, it.Error())
+
+	it = newJoinIterator(iter.NewStreamsIterator(left, logproto.FORWARD), iter.NoopIterator, "id", 5*time.Second, logproto.FORWARD, 1, 0)
+	require.False(t, it.Next())
+	require.True(t, errors.Is(it.Error(), logqlmodel.ErrLimit))
+
+	// the log lines are limited within the tolerance.
+	it = newJoinIterator(iter.NewStreamsIterator(left, logproto.FORWARD), iter.NoopIterator, "id", 5*time.Second, logproto.FORWARD, 0, 1)
+	require.False(t, it.Next())
+	require.EqualError(t, it.Error(), logqlmodel.NewJoinEntriesLimitError(1).Error())
+}
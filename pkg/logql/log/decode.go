@@ -0,0 +1,227 @@
+package log
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"unicode/utf8"
+
+	"github.com/buger/jsonparser"
+
+	"github.com/grafana/loki/pkg/logql/log/jsonexpr"
+)
+
+const (
+	EncodingBase64 = "base64"
+	EncodingGzip   = "gzip"
+	EncodingURL    = "url"
+
+	// maxDecodedSize is the maximum size of a decoded line or label, to protect against decompression bombs.
+	maxDecodedSize = 1 << 20
+)
+
+var (
+	_ Stage = &Decoder{}
+	_ Stage = &JSONUnescaper{}
+
+	errDecodedTooLarge = fmt.Errorf("decoded content is larger than %d bytes", maxDecodedSize)
+	errDecodedNotUTF8  = errors.New("decoded content is not valid UTF-8")
+
+	rawStdEncoding = base64.StdEncoding.WithPadding(base64.NoPadding)
+	rawURLEncoding = base64.URLEncoding.WithPadding(base64.NoPadding)
+)
+
+// Decoder decodes the log line, or the value of a label, which was encoded with one or more encodings.
+type Decoder struct {
+	encodings []string
+	label     string
+
+	// the decoded content is written alternatively to each buffer.
+	bufs [2][]byte
+	gz   *gzip.Reader
+	rd   bytes.Reader
+}
+
+// NewDecoder creates a new Decoder for the given encodings, in the order they were applied to the content,
+// e.g. `gzip`, `base64` for a gzipped content encoded in base64. The log line is decoded when the label is empty.
+func NewDecoder(encodings []string, label string) (*Decoder, error) {
+	if len(encodings) == 0 {
+		return nil, errors.New("at least one encoding must be supplied")
+	}
+	for _, enc := range encodings {
+		switch enc {
+		case EncodingBase64, EncodingGzip, EncodingURL:
+		default:
+			return nil, fmt.Errorf("unsupported encoding '%s'", enc)
+		}
+	}
+	return &Decoder{
+		encodings: encodings,
+		label:     label,
+	}, nil
+}
+
+func (d *Decoder) Process(_ int64, line []byte, lbs *LabelsBuilder) ([]byte, bool) {
+	if d.label == "" {
+		decoded, err := d.decode(line)
+		if err != nil {
+			addErrLabel(errDecode, err, lbs)
+			return line, true
+		}
+		return decoded, true
+	}
+
+	value, ok := lbs.Get(d.label)
+	if !ok {
+		return line, true
+	}
+	decoded, err := d.decode(unsafeGetBytes(value))
+	if err != nil {
+		addErrLabel(errDecode, err, lbs)
+		return line, true
+	}
+	lbs.Set(d.label, string(decoded))
+	return line, true
+}
+
+// decode decodes the content with the encodings, starting with the last one applied.
+func (d *Decoder) decode(content []byte) ([]byte, error) {
+	var err error
+	for i := len(d.encodings) - 1; i >= 0; i-- {
+		buf := d.bufs[i%2][:0]
+		switch d.encodings[i] {
+		case EncodingBase64:
+			buf, err = decodeBase64(buf, content)
+		case EncodingGzip:
+			buf, err = d.gunzip(buf, content)
+		case EncodingURL:
+			var s string
+			if s, err = url.QueryUnescape(unsafeGetString(content)); err == nil {
+				buf = append(buf, s...)
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("cannot decode %s: %w", d.encodings[i], err)
+		}
+		d.bufs[i%2] = buf
+		content = buf
+	}
+	if !utf8.Valid(content) {
+		return nil, errDecodedNotUTF8
+	}
+	return content, nil
+}
+
+func (d *Decoder) gunzip(dst, src []byte) ([]byte, error) {
+	d.rd.Reset(src)
+	if d.gz == nil {
+		gz, err := gzip.NewReader(&d.rd)
+		if err != nil {
+			return nil, err
+		}
+		d.gz = gz
+	} else if err := d.gz.Reset(&d.rd); err != nil {
+		return nil, err
+	}
+
+	w := bytes.NewBuffer(dst)
+	n, err := io.Copy(w, io.LimitReader(d.gz, maxDecodedSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if n > maxDecodedSize {
+		return nil, errDecodedTooLarge
+	}
+	return w.Bytes(), nil
+}
+
+// decodeBase64 decodes standard or URL-safe base64, with or without padding.
+func decodeBase64(dst, src []byte) ([]byte, error) {
+	src = bytes.TrimSpace(src)
+	enc := base64.StdEncoding
+	if bytes.ContainsAny(src, "-_") {
+		enc = base64.URLEncoding
+		if len(src)%4 != 0 {
+			enc = rawURLEncoding
+		}
+	} else if len(src)%4 != 0 {
+		enc = rawStdEncoding
+	}
+
+	n := enc.DecodedLen(len(src))
+	if n > maxDecodedSize {
+		return nil, errDecodedTooLarge
+	}
+	if cap(dst) < n {
+		dst = make([]byte, n)
+	}
+	n, err := enc.Decode(dst[:n], src)
+	if err != nil {
+		return nil, err
+	}
+	return dst[:n], nil
+}
+
+func (d *Decoder) RequiredLabelNames() []string {
+	if d.label == "" {
+		return []string{}
+	}
+	return []string{d.label}
+}
+
+// JSONUnescaper replaces a json log line with the value of one of its fields,
+// which is usually an escaped json string embedded in the line, e.g. `{"log":"{\"level\":\"info\"}"}`.
+// The line is unchanged when the field doesn't exist.
+type JSONUnescaper struct {
+	path []string
+	buf  []byte
+}
+
+// NewJSONUnescaper creates a new JSONUnescaper for the field at the given json path, e.g. `log` or `request.body`.
+func NewJSONUnescaper(field string) (*JSONUnescaper, error) {
+	path, err := jsonexpr.Parse(field, false)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse json_unescape field [%s]: %w", field, err)
+	}
+	if len(path) == 0 {
+		return nil, errors.New("json_unescape field cannot be empty")
+	}
+	if hasWildcard(path) {
+		return nil, fmt.Errorf("cannot unescape wildcard field [%s]", field)
+	}
+	return &JSONUnescaper{
+		path: pathsToString(path),
+	}, nil
+}
+
+func (u *JSONUnescaper) Process(_ int64, line []byte, lbs *LabelsBuilder) ([]byte, bool) {
+	if len(line) == 0 || !isValidJSONStart(line) {
+		addErrLabel(errJSON, nil, lbs)
+		return line, true
+	}
+	value, typ, _, err := jsonparser.Get(line, u.path...)
+	if err != nil {
+		if !errors.Is(err, jsonparser.KeyPathNotFoundError) {
+			addErrLabel(errJSON, err, lbs)
+		}
+		return line, true
+	}
+	if typ != jsonparser.String {
+		return value, true
+	}
+	if cap(u.buf) < len(value) {
+		u.buf = make([]byte, len(value))
+	}
+	unescaped, err := jsonparser.Unescape(value, u.buf)
+	if err != nil {
+		addErrLabel(errJSON, err, lbs)
+		return line, true
+	}
+	return unescaped, true
+}
+
+func (u *JSONUnescaper) RequiredLabelNames() []string { return []string{} }
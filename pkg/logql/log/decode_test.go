@@ -0,0 +1,224 @@
+package log
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"testing"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/pkg/logqlmodel"
+)
+
+func gzipBase64(t *testing.T, s string) string {
+	t.Helper()
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	_, err := w.Write([]byte(s))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return base64.StdEncoding.EncodeToString(buf.Bytes())
+}
+
+func Test_Decoder(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		encodings []string
+		label     string
+		line      string
+		lbs       labels.Labels
+		wantLine  string
+		wantLbs   labels.Labels
+	}{
+		{
+			"base64",
+			[]string{EncodingBase64},
+			"",
+			`eyJsZXZlbCI6ImluZm8ifQ==`,
+			labels.EmptyLabels(),
+			`{"level":"info"}`,
+			labels.EmptyLabels(),
+		},
+		{
+			"unpadded url safe base64",
+			[]string{EncodingBase64},
+			"",
+			`Pz8_Pg`,
+			labels.EmptyLabels(),
+			`???>`,
+			labels.EmptyLabels(),
+		},
+		{
+			"gzip and base64",
+			[]string{EncodingGzip, EncodingBase64},
+			"",
+			gzipBase64(t, `level=info msg="hello"`),
+			labels.EmptyLabels(),
+			`level=info msg="hello"`,
+			labels.EmptyLabels(),
+		},
+		{
+			"url",
+			[]string{EncodingURL},
+			"",
+			`q=foo%20bar+baz`,
+			labels.EmptyLabels(),
+			`q=foo bar baz`,
+			labels.EmptyLabels(),
+		},
+		{
+			"label",
+			[]string{EncodingURL, EncodingBase64},
+			"payload",
+			`line`,
+			labels.FromStrings("payload", base64.StdEncoding.EncodeToString([]byte("a%2Fb"))),
+			`line`,
+			labels.FromStrings("payload", "a/b"),
+		},
+		{
+			"missing label",
+			[]string{EncodingBase64},
+			"payload",
+			`line`,
+			labels.EmptyLabels(),
+			`line`,
+			labels.EmptyLabels(),
+		},
+		{
+			"invalid base64",
+			[]string{EncodingBase64},
+			"",
+			`not base64!`,
+			labels.EmptyLabels(),
+			`not base64!`,
+			labels.FromStrings(
+				logqlmodel.ErrorLabel, errDecode,
+				logqlmodel.ErrorDetailsLabel, "cannot decode base64: illegal base64 data at input byte 3",
+			),
+		},
+		{
+			"invalid gzip",
+			[]string{EncodingGzip, EncodingBase64},
+			"",
+			`eyJsZXZlbCI6ImluZm8ifQ==`,
+			labels.EmptyLabels(),
+			`eyJsZXZlbCI6ImluZm8ifQ==`,
+			labels.FromStrings(
+				logqlmodel.ErrorLabel, errDecode,
+				logqlmodel.ErrorDetailsLabel, "cannot decode gzip: gzip: invalid header",
+			),
+		},
+		{
+			"binary content",
+			[]string{EncodingBase64},
+			"",
+			`//79`,
+			labels.EmptyLabels(),
+			`//79`,
+			labels.FromStrings(
+				logqlmodel.ErrorLabel, errDecode,
+				logqlmodel.ErrorDetailsLabel, errDecodedNotUTF8.Error(),
+			),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			d, err := NewDecoder(tc.encodings, tc.label)
+			require.NoError(t, err)
+
+			b := NewBaseLabelsBuilder().ForLabels(tc.lbs, tc.lbs.Hash())
+			b.Reset()
+			line, ok := d.Process(0, []byte(tc.line), b)
+			require.True(t, ok)
+			require.Equal(t, tc.wantLine, string(line))
+			require.Equal(t, tc.wantLbs, b.LabelsResult().Labels())
+		})
+	}
+}
+
+func Test_DecoderReuse(t *testing.T) {
+	d, err := NewDecoder([]string{EncodingGzip, EncodingBase64}, "")
+	require.NoError(t, err)
+	b := NewBaseLabelsBuilder().ForLabels(labels.EmptyLabels(), 0)
+
+	for _, s := range []string{"first line", "second", "the third line"} {
+		b.Reset()
+		line, ok := d.Process(0, []byte(gzipBase64(t, s)), b)
+		require.True(t, ok)
+		require.Equal(t, s, string(line))
+		require.False(t, b.HasErr())
+	}
+}
+
+func TestNewDecoderFailures(t *testing.T) {
+	_, err := NewDecoder(nil, "")
+	require.Error(t, err)
+	_, err = NewDecoder([]string{EncodingBase64, "zstd"}, "")
+	require.Error(t, err)
+}
+
+func Test_JSONUnescaper(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		field    string
+		line     string
+		wantLine string
+		wantLbs  labels.Labels
+	}{
+		{
+			"escaped json",
+			"log",
+			`{"log":"{\"level\":\"info\",\"msg\":\"hello \\\"world\\\"\"}","stream":"stdout"}`,
+			`{"level":"info","msg":"hello \"world\""}`,
+			labels.EmptyLabels(),
+		},
+		{
+			"nested field",
+			`request["body"]`,
+			`{"request":{"body":"a=1"}}`,
+			`a=1`,
+			labels.EmptyLabels(),
+		},
+		{
+			"object",
+			"request",
+			`{"request":{"body":"a=1"}}`,
+			`{"body":"a=1"}`,
+			labels.EmptyLabels(),
+		},
+		{
+			"missing field",
+			"log",
+			`{"msg":"hello"}`,
+			`{"msg":"hello"}`,
+			labels.EmptyLabels(),
+		},
+		{
+			"not json",
+			"log",
+			`log=hello`,
+			`log=hello`,
+			labels.FromStrings(logqlmodel.ErrorLabel, errJSON),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			u, err := NewJSONUnescaper(tc.field)
+			require.NoError(t, err)
+
+			b := NewBaseLabelsBuilder().ForLabels(labels.EmptyLabels(), 0)
+			b.Reset()
+			line, ok := u.Process(0, []byte(tc.line), b)
+			require.True(t, ok)
+			require.Equal(t, tc.wantLine, string(line))
+			require.Equal(t, tc.wantLbs, b.LabelsResult().Labels())
+		})
+	}
+}
+
+func TestNewJSONUnescaperFailures(t *testing.T) {
+	for _, field := range []string{"", "items[*]", "items["} {
+		_, err := NewJSONUnescaper(field)
+		require.Error(t, err, field)
+	}
+}
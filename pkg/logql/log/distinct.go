@@ -0,0 +1,136 @@
+package log
+
+import (
+	"sync"
+	"time"
+
+	"github.com/cespare/xxhash/v2"
+)
+
+const (
+	// MaxDistinctEntries is the maximum number of keys remembered by the distinct, dedup and limit stages.
+	// When it is reached the oldest keys are forgotten, which bounds the memory used by a query
+	// at the cost of possibly letting through lines that were already seen a long time ago.
+	MaxDistinctEntries = 100000
+
+	// DefaultDedupTolerance is the time tolerance of the dedup stage when none is given.
+	DefaultDedupTolerance = time.Second
+)
+
+// keyRing remembers the order in which keys were added, up to a maximum number of keys.
+type keyRing struct {
+	keys []uint64
+	next int
+}
+
+// add records a key and returns the oldest key to forget when the ring is full.
+func (r *keyRing) add(key uint64) (uint64, bool) {
+	if len(r.keys) < MaxDistinctEntries {
+		r.keys = append(r.keys, key)
+		return 0, false
+	}
+	evicted := r.keys[r.next]
+	r.keys[r.next] = key
+	r.next = (r.next + 1) % len(r.keys)
+	return evicted, true
+}
+
+// DistinctFilter keeps only the first line for each combination of values of a set of labels.
+// Lines missing any of the labels are always kept.
+type DistinctFilter struct {
+	labels []string
+
+	mu   sync.Mutex
+	seen map[uint64]struct{}
+	ring keyRing
+	buf  []byte
+}
+
+// NewDistinctFilter creates a new DistinctFilter for the given label names.
+func NewDistinctFilter(labels []string) *DistinctFilter {
+	return &DistinctFilter{
+		labels: labels,
+		seen:   make(map[uint64]struct{}),
+	}
+}
+
+func (d *DistinctFilter) Process(_ int64, line []byte, lbs *LabelsBuilder) ([]byte, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.buf = d.buf[:0]
+	for _, name := range d.labels {
+		v, ok := lbs.Get(name)
+		if !ok {
+			return line, true
+		}
+		d.buf = append(d.buf, v...)
+		d.buf = append(d.buf, '\xff')
+	}
+	key := xxhash.Sum64(d.buf)
+	if _, ok := d.seen[key]; ok {
+		return line, false
+	}
+	d.seen[key] = struct{}{}
+	if evicted, ok := d.ring.add(key); ok {
+		delete(d.seen, evicted)
+	}
+	return line, true
+}
+
+func (d *DistinctFilter) RequiredLabelNames() []string { return d.labels }
+
+type dedupEntry struct {
+	ts     int64
+	stream uint64
+}
+
+// DedupFilter drops lines identical to a line of another stream whose timestamp is within a time tolerance.
+// Identical lines of the same stream are always kept.
+type DedupFilter struct {
+	tolerance int64
+
+	mu   sync.Mutex
+	seen map[uint64][]dedupEntry
+	ring keyRing
+}
+
+// NewDedupFilter creates a new DedupFilter with the given time tolerance.
+func NewDedupFilter(tolerance time.Duration) *DedupFilter {
+	if tolerance <= 0 {
+		tolerance = DefaultDedupTolerance
+	}
+	return &DedupFilter{
+		tolerance: tolerance.Nanoseconds(),
+		seen:      make(map[uint64][]dedupEntry),
+	}
+}
+
+func (d *DedupFilter) Process(ts int64, line []byte, lbs *LabelsBuilder) ([]byte, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	key := xxhash.Sum64(line)
+	stream := lbs.currentResult.Hash()
+	entries := d.seen[key]
+	for _, e := range entries {
+		if e.stream == stream {
+			continue
+		}
+		if diff := ts - e.ts; diff <= d.tolerance && diff >= -d.tolerance {
+			return line, false
+		}
+	}
+	d.seen[key] = append(entries, dedupEntry{ts: ts, stream: stream})
+	if evicted, ok := d.ring.add(key); ok {
+		// entries of a key are appended in the order of the ring, so the oldest one comes first.
+		if rest := d.seen[evicted][1:]; len(rest) > 0 {
+			d.seen[evicted] = rest
+		} else {
+			delete(d.seen, evicted)
+		}
+	}
+	return line, true
+}
+
+func (d *DedupFilter) RequiredLabelNames() []string { return []string{} }
@@ -0,0 +1,133 @@
+package log
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_DistinctFilter(t *testing.T) {
+	type line struct {
+		lbs  labels.Labels
+		line string
+	}
+	for _, tc := range []struct {
+		name   string
+		labels []string
+		lines  []line
+		want   []bool
+	}{
+		{
+			"single label",
+			[]string{"id"},
+			[]line{
+				{labels.FromStrings("app", "foo", "id", "1"), "a"},
+				{labels.FromStrings("app", "bar", "id", "1"), "b"},
+				{labels.FromStrings("app", "foo", "id", "2"), "c"},
+			},
+			[]bool{true, false, true},
+		},
+		{
+			"combination of labels",
+			[]string{"id", "app"},
+			[]line{
+				{labels.FromStrings("app", "foo", "id", "1"), "a"},
+				{labels.FromStrings("app", "bar", "id", "1"), "b"},
+				{labels.FromStrings("app", "foo", "id", "1"), "c"},
+			},
+			[]bool{true, true, false},
+		},
+		{
+			"missing label",
+			[]string{"id"},
+			[]line{
+				{labels.FromStrings("app", "foo"), "a"},
+				{labels.FromStrings("app", "foo"), "b"},
+			},
+			[]bool{true, true},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p := NewPipeline([]Stage{NewDistinctFilter(tc.labels)})
+			got := make([]bool, 0, len(tc.lines))
+			for i, l := range tc.lines {
+				_, _, ok := p.ForStream(l.lbs).ProcessString(int64(i), l.line)
+				got = append(got, ok)
+			}
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func Test_DedupFilter(t *testing.T) {
+	var (
+		foo = labels.FromStrings("app", "foo", "replica", "a")
+		bar = labels.FromStrings("app", "foo", "replica", "b")
+	)
+	type line struct {
+		lbs  labels.Labels
+		ts   time.Duration
+		line string
+	}
+	for _, tc := range []struct {
+		name      string
+		tolerance time.Duration
+		lines     []line
+		want      []bool
+	}{
+		{
+			"identical lines across streams",
+			0,
+			[]line{
+				{foo, 0, "a"},
+				{bar, 500 * time.Millisecond, "a"},
+				{bar, 500 * time.Millisecond, "b"},
+			},
+			[]bool{true, false, true},
+		},
+		{
+			"outside of the tolerance",
+			time.Second,
+			[]line{
+				{foo, 0, "a"},
+				{bar, 2 * time.Second, "a"},
+				{foo, 10 * time.Second, "a"},
+				{bar, 9 * time.Second, "a"},
+			},
+			[]bool{true, true, true, false},
+		},
+		{
+			"identical lines of the same stream are kept",
+			time.Second,
+			[]line{
+				{foo, 0, "a"},
+				{foo, 0, "a"},
+				{bar, 0, "a"},
+			},
+			[]bool{true, true, false},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p := NewPipeline([]Stage{NewDedupFilter(tc.tolerance)})
+			got := make([]bool, 0, len(tc.lines))
+			for _, l := range tc.lines {
+				_, _, ok := p.ForStream(l.lbs).ProcessString(l.ts.Nanoseconds(), l.line)
+				got = append(got, ok)
+			}
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func Test_DistinctFilterBoundedMemory(t *testing.T) {
+	d := NewDistinctFilter([]string{"id"})
+	p := NewPipeline([]Stage{d})
+	for i := 0; i < MaxDistinctEntries+10; i++ {
+		_, _, ok := p.ForStream(labels.FromStrings("id", strconv.Itoa(i))).ProcessString(int64(i), "")
+		require.True(t, ok)
+	}
+	require.Len(t, d.seen, MaxDistinctEntries)
+}
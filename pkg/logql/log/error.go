@@ -4,6 +4,9 @@ var (
 	// Possible errors thrown by a log pipeline.
 	errJSON             = "JSONParserErr"
 	errLogfmt           = "LogfmtParserErr"
+	errCSV              = "CSVParserErr"
+	errXML              = "XMLParserErr"
+	errDecode           = "DecodeErr"
 	errSampleExtraction = "SampleExtractionErr"
 	errLabelFilter      = "LabelFilterErr"
 	errTemplateFormat   = "TemplateFormatErr"
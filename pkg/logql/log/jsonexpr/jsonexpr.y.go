@@ -24,9 +24,10 @@ type JSONExprSymType struct {
 const DOT = 57346
 const LSB = 57347
 const RSB = 57348
-const STRING = 57349
-const FIELD = 57350
-const INDEX = 57351
+const WILDCARD = 57349
+const STRING = 57350
+const FIELD = 57351
+const INDEX = 57352
 
 var JSONExprToknames = [...]string{
 	"$end",
@@ -35,6 +36,7 @@ var JSONExprToknames = [...]string{
 	"DOT",
 	"LSB",
 	"RSB",
+	"WILDCARD",
 	"STRING",
 	"FIELD",
 	"INDEX",
@@ -47,7 +49,7 @@ const JSONExprErrCode = 2
 const JSONExprInitialStackSize = 16
 
 //line yacctab:1
-var JSONExprExca = [...]int{
+var JSONExprExca = [...]int8{
 	-1, 1,
 	1, -1,
 	-2, 0,
@@ -55,51 +57,55 @@ var JSONExprExca = [...]int{
 
 const JSONExprPrivate = 57344
 
-const JSONExprLast = 19
+const JSONExprLast = 23
 
-var JSONExprAct = [...]int{
-	3, 13, 7, 14, 6, 6, 17, 16, 10, 7,
-	4, 15, 5, 8, 1, 9, 2, 11, 12,
+var JSONExprAct = [...]int8{
+	3, 15, 16, 8, 17, 7, 21, 7, 20, 19,
+	12, 8, 4, 18, 6, 9, 5, 11, 1, 10,
+	2, 13, 14,
 }
 
-var JSONExprPact = [...]int{
-	-3, -1000, 4, -1000, -1000, -1000, -1000, -6, -1000, -1000,
-	-4, 1, 0, -1000, -1000, -1000, -1000, -1000,
+var JSONExprPact = [...]int16{
+	-2, -1000, 6, -1000, -1000, -1000, -1000, -1000, -6, -1000,
+	-1000, -1000, -4, 3, 2, 0, -1000, -1000, -1000, -1000,
+	-1000, -1000,
 }
 
-var JSONExprPgo = [...]int{
-	0, 18, 12, 0, 17, 10, 16, 14,
+var JSONExprPgo = [...]int8{
+	0, 22, 16, 14, 0, 21, 12, 20, 18,
 }
 
-var JSONExprR1 = [...]int{
-	0, 7, 6, 6, 6, 6, 6, 6, 5, 2,
-	3, 4, 1,
+var JSONExprR1 = [...]int8{
+	0, 8, 7, 7, 7, 7, 7, 7, 7, 7,
+	6, 2, 3, 4, 5, 1,
 }
 
-var JSONExprR2 = [...]int{
-	0, 1, 1, 1, 1, 2, 2, 3, 3, 3,
-	1, 1, 1,
+var JSONExprR2 = [...]int8{
+	0, 1, 1, 1, 1, 1, 2, 2, 2, 3,
+	3, 3, 3, 1, 1, 1,
 }
 
-var JSONExprChk = [...]int{
-	-1000, -7, -6, -3, -5, -2, 8, 5, -5, -2,
-	4, -4, -1, 7, 9, -3, 6, 6,
+var JSONExprChk = [...]int16{
+	-1000, -8, -7, -4, -6, -2, -3, 9, 5, -6,
+	-2, -3, 4, -5, -1, 7, 8, 10, -4, 6,
+	6, 6,
 }
 
-var JSONExprDef = [...]int{
-	0, -2, 1, 2, 3, 4, 10, 0, 5, 6,
-	0, 0, 0, 11, 12, 7, 8, 9,
+var JSONExprDef = [...]int8{
+	0, -2, 1, 2, 3, 4, 5, 13, 0, 6,
+	7, 8, 0, 0, 0, 0, 14, 15, 9, 10,
+	11, 12,
 }
 
-var JSONExprTok1 = [...]int{
+var JSONExprTok1 = [...]int8{
 	1,
 }
 
-var JSONExprTok2 = [...]int{
-	2, 3, 4, 5, 6, 7, 8, 9,
+var JSONExprTok2 = [...]int8{
+	2, 3, 4, 5, 6, 7, 8, 9, 10,
 }
 
-var JSONExprTok3 = [...]int{
+var JSONExprTok3 = [...]int8{
 	0,
 }
 
@@ -181,9 +187,9 @@ func JSONExprErrorMessage(state, lookAhead int) string {
 	expected := make([]int, 0, 4)
 
 	// Look for shiftable tokens.
-	base := JSONExprPact[state]
+	base := int(JSONExprPact[state])
 	for tok := TOKSTART; tok-1 < len(JSONExprToknames); tok++ {
-		if n := base + tok; n >= 0 && n < JSONExprLast && JSONExprChk[JSONExprAct[n]] == tok {
+		if n := base + tok; n >= 0 && n < JSONExprLast && int(JSONExprChk[int(JSONExprAct[n])]) == tok {
 			if len(expected) == cap(expected) {
 				return res
 			}
@@ -193,13 +199,13 @@ func JSONExprErrorMessage(state, lookAhead int) string {
 
 	if JSONExprDef[state] == -2 {
 		i := 0
-		for JSONExprExca[i] != -1 || JSONExprExca[i+1] != state {
+		for JSONExprExca[i] != -1 || int(JSONExprExca[i+1]) != state {
 			i += 2
 		}
 
 		// Look for tokens that we accept or reduce.
 		for i += 2; JSONExprExca[i] >= 0; i += 2 {
-			tok := JSONExprExca[i]
+			tok := int(JSONExprExca[i])
 			if tok < TOKSTART || JSONExprExca[i+1] == 0 {
 				continue
 			}
@@ -230,30 +236,30 @@ func JSONExprlex1(lex JSONExprLexer, lval *JSONExprSymType) (char, token int) {
 	token = 0
 	char = lex.Lex(lval)
 	if char <= 0 {
-		token = JSONExprTok1[0]
+		token = int(JSONExprTok1[0])
 		goto out
 	}
 	if char < len(JSONExprTok1) {
-		token = JSONExprTok1[char]
+		token = int(JSONExprTok1[char])
 		goto out
 	}
 	if char >= JSONExprPrivate {
 		if char < JSONExprPrivate+len(JSONExprTok2) {
-			token = JSONExprTok2[char-JSONExprPrivate]
+			token = int(JSONExprTok2[char-JSONExprPrivate])
 			goto out
 		}
 	}
 	for i := 0; i < len(JSONExprTok3); i += 2 {
-		token = JSONExprTok3[i+0]
+		token = int(JSONExprTok3[i+0])
 		if token == char {
-			token = JSONExprTok3[i+1]
+			token = int(JSONExprTok3[i+1])
 			goto out
 		}
 	}
 
 out:
 	if token == 0 {
-		token = JSONExprTok2[1] /* unknown char */
+		token = int(JSONExprTok2[1]) /* unknown char */
 	}
 	if JSONExprDebug >= 3 {
 		__yyfmt__.Printf("lex %s(%d)\n", JSONExprTokname(token), uint(char))
@@ -308,7 +314,7 @@ JSONExprstack:
 	JSONExprS[JSONExprp].yys = JSONExprstate
 
 JSONExprnewstate:
-	JSONExprn = JSONExprPact[JSONExprstate]
+	JSONExprn = int(JSONExprPact[JSONExprstate])
 	if JSONExprn <= JSONExprFlag {
 		goto JSONExprdefault /* simple state */
 	}
@@ -319,8 +325,8 @@ JSONExprnewstate:
 	if JSONExprn < 0 || JSONExprn >= JSONExprLast {
 		goto JSONExprdefault
 	}
-	JSONExprn = JSONExprAct[JSONExprn]
-	if JSONExprChk[JSONExprn] == JSONExprtoken { /* valid shift */
+	JSONExprn = int(JSONExprAct[JSONExprn])
+	if int(JSONExprChk[JSONExprn]) == JSONExprtoken { /* valid shift */
 		JSONExprrcvr.char = -1
 		JSONExprtoken = -1
 		JSONExprVAL = JSONExprrcvr.lval
@@ -333,7 +339,7 @@ JSONExprnewstate:
 
 JSONExprdefault:
 	/* default state action */
-	JSONExprn = JSONExprDef[JSONExprstate]
+	JSONExprn = int(JSONExprDef[JSONExprstate])
 	if JSONExprn == -2 {
 		if JSONExprrcvr.char < 0 {
 			JSONExprrcvr.char, JSONExprtoken = JSONExprlex1(JSONExprlex, &JSONExprrcvr.lval)
@@ -342,18 +348,18 @@ JSONExprdefault:
 		/* look through exception table */
 		xi := 0
 		for {
-			if JSONExprExca[xi+0] == -1 && JSONExprExca[xi+1] == JSONExprstate {
+			if JSONExprExca[xi+0] == -1 && int(JSONExprExca[xi+1]) == JSONExprstate {
 				break
 			}
 			xi += 2
 		}
 		for xi += 2; ; xi += 2 {
-			JSONExprn = JSONExprExca[xi+0]
+			JSONExprn = int(JSONExprExca[xi+0])
 			if JSONExprn < 0 || JSONExprn == JSONExprtoken {
 				break
 			}
 		}
-		JSONExprn = JSONExprExca[xi+1]
+		JSONExprn = int(JSONExprExca[xi+1])
 		if JSONExprn < 0 {
 			goto ret0
 		}
@@ -375,10 +381,10 @@ JSONExprdefault:
 
 			/* find a state where "error" is a legal shift action */
 			for JSONExprp >= 0 {
-				JSONExprn = JSONExprPact[JSONExprS[JSONExprp].yys] + JSONExprErrCode
+				JSONExprn = int(JSONExprPact[JSONExprS[JSONExprp].yys]) + JSONExprErrCode
 				if JSONExprn >= 0 && JSONExprn < JSONExprLast {
-					JSONExprstate = JSONExprAct[JSONExprn] /* simulate a shift of "error" */
-					if JSONExprChk[JSONExprstate] == JSONExprErrCode {
+					JSONExprstate = int(JSONExprAct[JSONExprn]) /* simulate a shift of "error" */
+					if int(JSONExprChk[JSONExprstate]) == JSONExprErrCode {
 						goto JSONExprstack
 					}
 				}
@@ -414,7 +420,7 @@ JSONExprdefault:
 	JSONExprpt := JSONExprp
 	_ = JSONExprpt // guard against "declared and not used"
 
-	JSONExprp -= JSONExprR2[JSONExprn]
+	JSONExprp -= int(JSONExprR2[JSONExprn])
 	// JSONExprp is now the index of $0. Perform the default action. Iff the
 	// reduced production is ε, $1 is possibly out of range.
 	if JSONExprp+1 >= len(JSONExprS) {
@@ -425,16 +431,16 @@ JSONExprdefault:
 	JSONExprVAL = JSONExprS[JSONExprp+1]
 
 	/* consult goto table to find next state */
-	JSONExprn = JSONExprR1[JSONExprn]
-	JSONExprg := JSONExprPgo[JSONExprn]
+	JSONExprn = int(JSONExprR1[JSONExprn])
+	JSONExprg := int(JSONExprPgo[JSONExprn])
 	JSONExprj := JSONExprg + JSONExprS[JSONExprp].yys + 1
 
 	if JSONExprj >= JSONExprLast {
-		JSONExprstate = JSONExprAct[JSONExprg]
+		JSONExprstate = int(JSONExprAct[JSONExprg])
 	} else {
-		JSONExprstate = JSONExprAct[JSONExprj]
-		if JSONExprChk[JSONExprstate] != -JSONExprn {
-			JSONExprstate = JSONExprAct[JSONExprg]
+		JSONExprstate = int(JSONExprAct[JSONExprj])
+		if int(JSONExprChk[JSONExprstate]) != -JSONExprn {
+			JSONExprstate = int(JSONExprAct[JSONExprg])
 		}
 	}
 	// dummy call; replaced with literal code
@@ -442,73 +448,91 @@ JSONExprdefault:
 
 	case 1:
 		JSONExprDollar = JSONExprS[JSONExprpt-1 : JSONExprpt+1]
-//line pkg/logql/log/jsonexpr/jsonexpr.y:32
+//line pkg/logql/log/jsonexpr/jsonexpr.y:33
 		{
 			setScannerData(JSONExprlex, JSONExprDollar[1].list)
 		}
 	case 2:
 		JSONExprDollar = JSONExprS[JSONExprpt-1 : JSONExprpt+1]
-//line pkg/logql/log/jsonexpr/jsonexpr.y:35
+//line pkg/logql/log/jsonexpr/jsonexpr.y:36
 		{
 			JSONExprVAL.list = []interface{}{JSONExprDollar[1].str}
 		}
 	case 3:
 		JSONExprDollar = JSONExprS[JSONExprpt-1 : JSONExprpt+1]
-//line pkg/logql/log/jsonexpr/jsonexpr.y:36
+//line pkg/logql/log/jsonexpr/jsonexpr.y:37
 		{
 			JSONExprVAL.list = []interface{}{JSONExprDollar[1].str}
 		}
 	case 4:
 		JSONExprDollar = JSONExprS[JSONExprpt-1 : JSONExprpt+1]
-//line pkg/logql/log/jsonexpr/jsonexpr.y:37
+//line pkg/logql/log/jsonexpr/jsonexpr.y:38
 		{
 			JSONExprVAL.list = []interface{}{JSONExprDollar[1].int}
 		}
 	case 5:
+		JSONExprDollar = JSONExprS[JSONExprpt-1 : JSONExprpt+1]
+//line pkg/logql/log/jsonexpr/jsonexpr.y:39
+		{
+			JSONExprVAL.list = []interface{}{Wildcard{}}
+		}
+	case 6:
 		JSONExprDollar = JSONExprS[JSONExprpt-2 : JSONExprpt+1]
-//line pkg/logql/log/jsonexpr/jsonexpr.y:38
+//line pkg/logql/log/jsonexpr/jsonexpr.y:40
 		{
 			JSONExprVAL.list = append(JSONExprDollar[1].list, JSONExprDollar[2].str)
 		}
-	case 6:
+	case 7:
 		JSONExprDollar = JSONExprS[JSONExprpt-2 : JSONExprpt+1]
-//line pkg/logql/log/jsonexpr/jsonexpr.y:39
+//line pkg/logql/log/jsonexpr/jsonexpr.y:41
 		{
 			JSONExprVAL.list = append(JSONExprDollar[1].list, JSONExprDollar[2].int)
 		}
-	case 7:
+	case 8:
+		JSONExprDollar = JSONExprS[JSONExprpt-2 : JSONExprpt+1]
+//line pkg/logql/log/jsonexpr/jsonexpr.y:42
+		{
+			JSONExprVAL.list = append(JSONExprDollar[1].list, Wildcard{})
+		}
+	case 9:
 		JSONExprDollar = JSONExprS[JSONExprpt-3 : JSONExprpt+1]
-//line pkg/logql/log/jsonexpr/jsonexpr.y:40
+//line pkg/logql/log/jsonexpr/jsonexpr.y:43
 		{
 			JSONExprVAL.list = append(JSONExprDollar[1].list, JSONExprDollar[3].str)
 		}
-	case 8:
+	case 10:
 		JSONExprDollar = JSONExprS[JSONExprpt-3 : JSONExprpt+1]
-//line pkg/logql/log/jsonexpr/jsonexpr.y:44
+//line pkg/logql/log/jsonexpr/jsonexpr.y:47
 		{
 			JSONExprVAL.str = JSONExprDollar[2].str
 		}
-	case 9:
+	case 11:
 		JSONExprDollar = JSONExprS[JSONExprpt-3 : JSONExprpt+1]
-//line pkg/logql/log/jsonexpr/jsonexpr.y:47
+//line pkg/logql/log/jsonexpr/jsonexpr.y:50
 		{
 			JSONExprVAL.int = JSONExprDollar[2].int
 		}
-	case 10:
+	case 12:
+		JSONExprDollar = JSONExprS[JSONExprpt-3 : JSONExprpt+1]
+//line pkg/logql/log/jsonexpr/jsonexpr.y:53
+		{
+			JSONExprVAL.empty = JSONExprDollar[2].empty
+		}
+	case 13:
 		JSONExprDollar = JSONExprS[JSONExprpt-1 : JSONExprpt+1]
-//line pkg/logql/log/jsonexpr/jsonexpr.y:50
+//line pkg/logql/log/jsonexpr/jsonexpr.y:56
 		{
 			JSONExprVAL.str = JSONExprDollar[1].field
 		}
-	case 11:
+	case 14:
 		JSONExprDollar = JSONExprS[JSONExprpt-1 : JSONExprpt+1]
-//line pkg/logql/log/jsonexpr/jsonexpr.y:53
+//line pkg/logql/log/jsonexpr/jsonexpr.y:59
 		{
 			JSONExprVAL.str = JSONExprDollar[1].str
 		}
-	case 12:
+	case 15:
 		JSONExprDollar = JSONExprS[JSONExprpt-1 : JSONExprpt+1]
-//line pkg/logql/log/jsonexpr/jsonexpr.y:56
+//line pkg/logql/log/jsonexpr/jsonexpr.y:62
 		{
 			JSONExprVAL.int = JSONExprDollar[1].int
 		}
@@ -76,6 +76,18 @@ func TestJSONExpressionParser(t *testing.T) {
 			[]interface{}{"pod", "deployment", "params", 0, "param"},
 			nil,
 		},
+		{
+			"wildcard array access",
+			`pod.deployment.params[*].param`,
+			[]interface{}{"pod", "deployment", "params", Wildcard{}, "param"},
+			nil,
+		},
+		{
+			"top-level wildcard array access",
+			`[*]`,
+			[]interface{}{Wildcard{}},
+			nil,
+		},
 		{
 			"empty",
 			``,
@@ -8,6 +8,9 @@ func init() {
 	JSONExprErrorVerbose = true
 }
 
+// Wildcard is the element of a parsed path matching every element of an array, e.g. in `items[*].sku`.
+type Wildcard struct{}
+
 func Parse(expr string, debug bool) ([]interface{}, error) {
 	s := NewScanner(strings.NewReader(expr), debug)
 	JSONExprParse(s)
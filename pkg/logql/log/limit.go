@@ -0,0 +1,117 @@
+package log
+
+import (
+	"encoding/binary"
+	"math/bits"
+	"sync"
+	"time"
+
+	"github.com/cespare/xxhash/v2"
+)
+
+// QueryRangeStage is a stage which depends on the time range of the query.
+type QueryRangeStage interface {
+	Stage
+	SetQueryRange(start, end time.Time)
+}
+
+// SetQueryRange gives the time range of the query to the stages of the pipeline which depend on it.
+func SetQueryRange(p Pipeline, start, end time.Time) {
+	ap, ok := p.(AnalyzablePipeline)
+	if !ok {
+		return
+	}
+	for _, s := range ap.Stages() {
+		if s, ok := s.(QueryRangeStage); ok {
+			s.SetQueryRange(start, end)
+		}
+	}
+}
+
+// LimitByFilter keeps at most a number of lines for each combination of values of a set of labels,
+// the first ones it processes, i.e. the newest ones of a backward query.
+// Lines missing some of the labels are grouped as if the labels were empty.
+//
+// When sampling, the time range of the query is divided into intervals and at most one line
+// is kept per interval, which spreads the lines of a combination across the range.
+// The intervals are a power of two nanoseconds aligned on the epoch, so that the intervals
+// of a part of the range, like a split of the query, are nested in the ones of the whole range.
+// Without a time range the stage doesn't sample.
+type LimitByFilter struct {
+	limit  int
+	labels []string
+	sample bool
+
+	mu sync.Mutex
+	// the intervals are 1<<shift nanoseconds long, zero when not sampling.
+	shift uint
+	// counts holds the number of lines kept for each combination,
+	// and the intervals in which a line was kept when sampling.
+	counts map[uint64]int
+	ring   keyRing
+	buf    []byte
+}
+
+// NewLimitByFilter creates a new LimitByFilter keeping up to limit lines for each combination of values of labels.
+// The lines must be processed in the order of the query, so the filter is applied to the merged streams of a query.
+func NewLimitByFilter(limit int, labels []string, sample bool) *LimitByFilter {
+	return &LimitByFilter{
+		limit:  limit,
+		labels: labels,
+		sample: sample,
+		counts: make(map[uint64]int),
+	}
+}
+
+// SetQueryRange sets the intervals used for sampling to the smallest power of two nanoseconds
+// dividing the time range in at most limit intervals.
+func (l *LimitByFilter) SetQueryRange(start, end time.Time) {
+	if !l.sample {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.shift = 0
+	if step := (end.Sub(start).Nanoseconds() + int64(l.limit) - 1) / int64(l.limit); step > 1 {
+		l.shift = uint(bits.Len64(uint64(step - 1)))
+	}
+}
+
+func (l *LimitByFilter) Process(ts int64, line []byte, lbs *LabelsBuilder) ([]byte, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.buf = l.buf[:0]
+	for _, name := range l.labels {
+		v, _ := lbs.Get(name)
+		l.buf = append(l.buf, v...)
+		l.buf = append(l.buf, '\xff')
+	}
+	group := xxhash.Sum64(l.buf)
+	if l.counts[group] >= l.limit {
+		return line, false
+	}
+	if l.shift > 0 {
+		l.buf = binary.LittleEndian.AppendUint64(l.buf, uint64(ts>>l.shift))
+		interval := xxhash.Sum64(l.buf)
+		if _, ok := l.counts[interval]; ok {
+			return line, false
+		}
+		l.add(interval)
+	}
+	l.add(group)
+	return line, true
+}
+
+// add increments the count of a key, forgetting the oldest key when too many are remembered.
+func (l *LimitByFilter) add(key uint64) {
+	if _, ok := l.counts[key]; !ok {
+		if evicted, ok := l.ring.add(key); ok {
+			delete(l.counts, evicted)
+		}
+	}
+	l.counts[key]++
+}
+
+func (l *LimitByFilter) RequiredLabelNames() []string { return l.labels }
@@ -0,0 +1,89 @@
+package log
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_LimitByFilter(t *testing.T) {
+	type line struct {
+		ts  int64
+		lbs labels.Labels
+	}
+	var (
+		podA  = labels.FromStrings("app", "foo", "pod", "a")
+		podB  = labels.FromStrings("app", "foo", "pod", "b")
+		podA2 = labels.FromStrings("app", "foo", "pod", "a", "container", "sidecar")
+	)
+	for _, tc := range []struct {
+		name   string
+		limit  int
+		labels []string
+		sample bool
+		lines  []line
+		want   []bool
+	}{
+		{
+			"limit by label",
+			2,
+			[]string{"pod"},
+			false,
+			[]line{{0, podA}, {1, podB}, {2, podA}, {3, podA2}, {4, podB}, {5, podB}},
+			[]bool{true, true, true, false, true, false},
+		},
+		{
+			"missing label",
+			1,
+			[]string{"namespace"},
+			false,
+			[]line{{0, podA}, {1, podB}},
+			[]bool{true, false},
+		},
+		{
+			"sample",
+			4,
+			[]string{"pod"},
+			true,
+			// the range of 64ns is divided into intervals of 16ns.
+			[]line{{0, podA}, {5, podA}, {16, podA}, {17, podB}, {40, podA}, {50, podA}, {63, podA}},
+			[]bool{true, false, true, true, true, true, false},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p := NewPipeline([]Stage{NewLimitByFilter(tc.limit, tc.labels, tc.sample)})
+			SetQueryRange(p, time.Unix(0, 0), time.Unix(0, 64))
+			got := make([]bool, 0, len(tc.lines))
+			for _, l := range tc.lines {
+				_, _, ok := p.ForStream(l.lbs).ProcessString(l.ts, "line")
+				got = append(got, ok)
+			}
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestLimitByFilter_SetQueryRange(t *testing.T) {
+	for _, tc := range []struct {
+		limit int
+		rng   time.Duration
+		want  uint
+	}{
+		{10, 0, 0},
+		{10, 10, 0},
+		{10, 20, 1},
+		{4, 64, 4},
+		{4, 65, 5},
+		{100, time.Hour, 36},
+	} {
+		f := NewLimitByFilter(tc.limit, nil, true)
+		f.SetQueryRange(time.Unix(0, 0), time.Unix(0, int64(tc.rng)))
+		require.Equal(t, tc.want, f.shift, "limit %d range %s", tc.limit, tc.rng)
+	}
+
+	f := NewLimitByFilter(10, nil, false)
+	f.SetQueryRange(time.Unix(0, 0), time.Unix(0, int64(time.Hour)))
+	require.Zero(t, f.shift)
+}
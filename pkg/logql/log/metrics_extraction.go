@@ -36,12 +36,18 @@ type StreamSampleExtractor interface {
 	BaseLabels() LabelsResult
 	Process(ts int64, line []byte, structuredMetadata ...labels.Label) (float64, LabelsResult, bool)
 	ProcessString(ts int64, line string, structuredMetadata ...labels.Label) (float64, LabelsResult, bool)
+	// Next returns the next sample of the last processed log line when it was fanned out into several lines,
+	// like the unnest stage does. It must be called until it returns false before processing the next log line.
+	Next() (float64, LabelsResult, bool)
 }
 
 type lineSampleExtractor struct {
 	Stage
 	LineExtractor
 
+	unnest     *Unnester
+	postUnnest []Stage
+
 	baseBuilder      *BaseLabelsBuilder
 	streamExtractors map[uint64]StreamSampleExtractor
 }
@@ -49,11 +55,14 @@ type lineSampleExtractor struct {
 // NewLineSampleExtractor creates a SampleExtractor from a LineExtractor.
 // Multiple log stages are run before converting the log line.
 func NewLineSampleExtractor(ex LineExtractor, stages []Stage, groups []string, without, noLabels bool) (SampleExtractor, error) {
-	s := ReduceStages(stages)
-	hints := NewParserHint(s.RequiredLabelNames(), groups, without, noLabels, "", stages)
+	pre, unnest, post := splitUnnest(stages)
+	s := ReduceStages(pre)
+	hints := NewParserHint(ReduceStages(stages).RequiredLabelNames(), groups, without, noLabels, "", stages)
 	return &lineSampleExtractor{
 		Stage:            s,
 		LineExtractor:    ex,
+		unnest:           unnest,
+		postUnnest:       post,
 		baseBuilder:      NewBaseLabelsBuilderWithGrouping(groups, hints, without, noLabels),
 		streamExtractors: make(map[uint64]StreamSampleExtractor),
 	}, nil
@@ -70,6 +79,9 @@ func (l *lineSampleExtractor) ForStream(labels labels.Labels) StreamSampleExtrac
 		LineExtractor: l.LineExtractor,
 		builder:       l.baseBuilder.ForLabels(labels, hash),
 	}
+	if l.unnest != nil {
+		res.unnest = newUnnestState(l.unnest, l.postUnnest, res.builder)
+	}
 	l.streamExtractors[hash] = res
 	return res
 }
@@ -78,6 +90,7 @@ type streamLineSampleExtractor struct {
 	Stage
 	LineExtractor
 	builder *LabelsBuilder
+	unnest  *unnestState
 }
 
 func (l *streamLineSampleExtractor) Process(ts int64, line []byte, structuredMetadata ...labels.Label) (float64, LabelsResult, bool) {
@@ -85,7 +98,7 @@ func (l *streamLineSampleExtractor) Process(ts int64, line []byte, structuredMet
 	l.builder.Add(structuredMetadata...)
 
 	// short circuit.
-	if l.Stage == NoopStage {
+	if l.Stage == NoopStage && l.unnest == nil {
 		return l.LineExtractor(line), l.builder.GroupedLabels(), true
 	}
 
@@ -93,6 +106,22 @@ func (l *streamLineSampleExtractor) Process(ts int64, line []byte, structuredMet
 	if !ok {
 		return 0, nil, false
 	}
+	if l.unnest != nil {
+		if line, ok = l.unnest.start(ts, line); !ok {
+			return 0, nil, false
+		}
+	}
+	return l.LineExtractor(line), l.builder.GroupedLabels(), true
+}
+
+func (l *streamLineSampleExtractor) Next() (float64, LabelsResult, bool) {
+	if l.unnest == nil {
+		return 0, nil, false
+	}
+	line, ok := l.unnest.nextLine()
+	if !ok {
+		return 0, nil, false
+	}
 	return l.LineExtractor(line), l.builder.GroupedLabels(), true
 }
 
@@ -107,6 +136,8 @@ type convertionFn func(value string) (float64, error)
 
 type labelSampleExtractor struct {
 	preStage     Stage
+	unnest       *Unnester
+	postUnnest   []Stage
 	postFilter   Stage
 	labelName    string
 	conversionFn convertionFn
@@ -140,10 +171,13 @@ func LabelExtractorWithStages(
 		groups = append(groups, labelName)
 		sort.Strings(groups)
 	}
-	preStage := ReduceStages(preStages)
-	hints := NewParserHint(append(preStage.RequiredLabelNames(), postFilter.RequiredLabelNames()...), groups, without, noLabels, labelName, append(preStages, postFilter))
+	pre, unnest, post := splitUnnest(preStages)
+	preStage := ReduceStages(pre)
+	hints := NewParserHint(append(ReduceStages(preStages).RequiredLabelNames(), postFilter.RequiredLabelNames()...), groups, without, noLabels, labelName, append(preStages, postFilter))
 	return &labelSampleExtractor{
 		preStage:         preStage,
+		unnest:           unnest,
+		postUnnest:       post,
 		conversionFn:     convFn,
 		labelName:        labelName,
 		postFilter:       postFilter,
@@ -155,6 +189,7 @@ func LabelExtractorWithStages(
 type streamLabelSampleExtractor struct {
 	*labelSampleExtractor
 	builder *LabelsBuilder
+	unnest  *unnestState
 }
 
 func (l *labelSampleExtractor) ForStream(labels labels.Labels) StreamSampleExtractor {
@@ -167,6 +202,9 @@ func (l *labelSampleExtractor) ForStream(labels labels.Labels) StreamSampleExtra
 		labelSampleExtractor: l,
 		builder:              l.baseBuilder.ForLabels(labels, hash),
 	}
+	if l.unnest != nil {
+		res.unnest = newUnnestState(l.unnest, l.postUnnest, res.builder)
+	}
 	l.streamExtractors[hash] = res
 	return res
 }
@@ -179,6 +217,31 @@ func (l *streamLabelSampleExtractor) Process(ts int64, line []byte, structuredMe
 	if !ok {
 		return 0, nil, false
 	}
+	if l.unnest == nil {
+		return l.extract(ts, line)
+	}
+	for line, ok = l.unnest.start(ts, line); ok; line, ok = l.unnest.nextLine() {
+		if v, lbs, ok := l.extract(ts, line); ok {
+			return v, lbs, true
+		}
+	}
+	return 0, nil, false
+}
+
+func (l *streamLabelSampleExtractor) Next() (float64, LabelsResult, bool) {
+	if l.unnest == nil {
+		return 0, nil, false
+	}
+	for line, ok := l.unnest.nextLine(); ok; line, ok = l.unnest.nextLine() {
+		if v, lbs, ok := l.extract(l.unnest.ts, line); ok {
+			return v, lbs, true
+		}
+	}
+	return 0, nil, false
+}
+
+// extract converts the label value and applies the post filters.
+func (l *streamLabelSampleExtractor) extract(ts int64, line []byte) (float64, LabelsResult, bool) {
 	// convert the label value.
 	var v float64
 	stringValue, _ := l.builder.Get(l.labelName)
@@ -196,7 +259,7 @@ func (l *streamLabelSampleExtractor) Process(ts int64, line []byte, structuredMe
 	}
 
 	// post filters
-	if _, ok = l.postFilter.Process(ts, line, l.builder); !ok {
+	if _, ok := l.postFilter.Process(ts, line, l.builder); !ok {
 		return 0, nil, false
 	}
 	return v, l.builder.GroupedLabels(), true
@@ -281,6 +344,10 @@ func (sp *filteringStreamExtractor) ProcessString(ts int64, line string, structu
 	return sp.extractor.ProcessString(ts, line)
 }
 
+func (sp *filteringStreamExtractor) Next() (float64, LabelsResult, bool) {
+	return sp.extractor.Next()
+}
+
 func convertFloat(v string) (float64, error) {
 	return strconv.ParseFloat(v, 64)
 }
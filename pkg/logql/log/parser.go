@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"strings"
 	"unicode/utf8"
 
 	"github.com/buger/jsonparser"
@@ -32,6 +33,8 @@ var (
 	_ Stage = &JSONParser{}
 	_ Stage = &RegexpParser{}
 	_ Stage = &LogfmtParser{}
+	_ Stage = &CSVParser{}
+	_ Stage = &KVParser{}
 
 	trueBytes = []byte("true")
 
@@ -544,11 +547,17 @@ type JSONExpressionParser struct {
 	ids   []string
 	paths [][]string
 	keys  internedStringSet
+
+	// paths with a wildcard, like `items[*].sku`, are looked up separately.
+	wildcardIDs   []string
+	wildcardPaths [][]interface{}
+	values        []string
 }
 
 func NewJSONExpressionParser(expressions []LabelExtractionExpr) (*JSONExpressionParser, error) {
-	var ids []string
+	var ids, wildcardIDs []string
 	var paths [][]string
+	var wildcardPaths [][]interface{}
 	for _, exp := range expressions {
 		path, err := jsonexpr.Parse(exp.Expression, false)
 		if err != nil {
@@ -559,17 +568,33 @@ func NewJSONExpressionParser(expressions []LabelExtractionExpr) (*JSONExpression
 			return nil, fmt.Errorf("invalid extracted label name '%s'", exp.Identifier)
 		}
 
+		if hasWildcard(path) {
+			wildcardIDs = append(wildcardIDs, exp.Identifier)
+			wildcardPaths = append(wildcardPaths, path)
+			continue
+		}
 		ids = append(ids, exp.Identifier)
 		paths = append(paths, pathsToString(path))
 	}
 
 	return &JSONExpressionParser{
-		ids:   ids,
-		paths: paths,
-		keys:  internedStringSet{},
+		ids:           ids,
+		paths:         paths,
+		keys:          internedStringSet{},
+		wildcardIDs:   wildcardIDs,
+		wildcardPaths: wildcardPaths,
 	}, nil
 }
 
+func hasWildcard(path []interface{}) bool {
+	for _, p := range path {
+		if _, ok := p.(jsonexpr.Wildcard); ok {
+			return true
+		}
+	}
+	return false
+}
+
 func pathsToString(paths []interface{}) []string {
 	stingPaths := make([]string, 0, len(paths))
 	for _, p := range paths {
@@ -597,29 +622,24 @@ func (j *JSONExpressionParser) Process(_ int64, line []byte, lbs *LabelsBuilder)
 	}
 
 	var matches int
-	jsonparser.EachKey(line, func(idx int, data []byte, typ jsonparser.ValueType, err error) {
-		if err != nil {
-			addErrLabel(errJSON, err, lbs)
-			return
-		}
-
-		identifier := j.ids[idx]
-		key, _ := j.keys.Get(unsafeGetBytes(identifier), func() (string, bool) {
-			if lbs.BaseHas(identifier) {
-				identifier = identifier + duplicateSuffix
+	if len(j.paths) > 0 {
+		jsonparser.EachKey(line, func(idx int, data []byte, typ jsonparser.ValueType, err error) {
+			if err != nil {
+				addErrLabel(errJSON, err, lbs)
+				return
 			}
-			return identifier, true
-		})
 
-		switch typ {
-		case jsonparser.Null:
-			lbs.Set(key, "")
-		default:
-			lbs.Set(key, unescapeJSONString(data))
-		}
+			key := j.labelKey(j.ids[idx], lbs)
+			switch typ {
+			case jsonparser.Null:
+				lbs.Set(key, "")
+			default:
+				lbs.Set(key, unescapeJSONString(data))
+			}
 
-		matches++
-	}, j.paths...)
+			matches++
+		}, j.paths...)
+	}
 
 	// Ensure there's a label for every value
 	if matches < len(j.ids) {
@@ -630,9 +650,79 @@ func (j *JSONExpressionParser) Process(_ int64, line []byte, lbs *LabelsBuilder)
 		}
 	}
 
+	for i, path := range j.wildcardPaths {
+		var err error
+		j.values, err = collectJSONValues(line, path, j.values[:0])
+		if err != nil {
+			addErrLabel(errJSON, err, lbs)
+			continue
+		}
+		// the values of all the matching array elements are joined with a comma.
+		lbs.Set(j.labelKey(j.wildcardIDs[i], lbs), strings.Join(j.values, ","))
+	}
+
 	return line, true
 }
 
+func (j *JSONExpressionParser) labelKey(identifier string, lbs *LabelsBuilder) string {
+	key, _ := j.keys.Get(unsafeGetBytes(identifier), func() (string, bool) {
+		if lbs.BaseHas(identifier) {
+			identifier = identifier + duplicateSuffix
+		}
+		return identifier, true
+	})
+	return key
+}
+
+// collectJSONValues appends the values found at the given path to values.
+// Every element of an array is looked up when the path contains a wildcard.
+// A path which does not exist yields no value.
+func collectJSONValues(data []byte, path []interface{}, values []string) ([]string, error) {
+	i := 0
+	for ; i < len(path); i++ {
+		if _, ok := path[i].(jsonexpr.Wildcard); ok {
+			break
+		}
+	}
+
+	value, typ, _, err := jsonparser.Get(data, pathsToString(path[:i])...)
+	if err != nil {
+		if errors.Is(err, jsonparser.KeyPathNotFoundError) {
+			return values, nil
+		}
+		return values, err
+	}
+	if i == len(path) {
+		return appendJSONValue(values, value, typ), nil
+	}
+	if typ != jsonparser.Array {
+		return values, nil
+	}
+
+	var innerErr error
+	_, err = jsonparser.ArrayEach(value, func(elem []byte, typ jsonparser.ValueType, _ int, _ error) {
+		rest := path[i+1:]
+		switch {
+		case innerErr != nil:
+		case len(rest) == 0:
+			values = appendJSONValue(values, elem, typ)
+		case typ == jsonparser.Object || typ == jsonparser.Array:
+			values, innerErr = collectJSONValues(elem, rest, values)
+		}
+	})
+	if err != nil {
+		return values, err
+	}
+	return values, innerErr
+}
+
+func appendJSONValue(values []string, value []byte, typ jsonparser.ValueType) []string {
+	if typ == jsonparser.Null {
+		return append(values, "")
+	}
+	return append(values, unescapeJSONString(value))
+}
+
 func isValidJSONStart(data []byte) bool {
 	switch data[0] {
 	case '"', '{', '[':
@@ -754,3 +844,210 @@ func (u *UnpackParser) unpack(entry []byte, lbs *LabelsBuilder) ([]byte, error)
 	}
 	return entry, nil
 }
+
+var errCSVQuote = errors.New("extraneous or missing \" in quoted-field")
+
+type CSVParser struct {
+	columns []string
+	sep     []byte
+	buf     []byte
+	keys    internedStringSet
+}
+
+// NewCSVParser creates a log stage that can extract labels from a csv log line, using the given header to name the columns.
+// The header is a csv line using the same separator, columns with an empty name are not extracted.
+func NewCSVParser(header, sep string) (*CSVParser, error) {
+	if utf8.RuneCountInString(sep) != 1 || sep == `"` || sep == "\n" || sep == "\r" {
+		return nil, fmt.Errorf("invalid csv separator '%s'", sep)
+	}
+	p := &CSVParser{
+		sep:  []byte(sep),
+		keys: internedStringSet{},
+	}
+	uniqueNames := map[string]struct{}{}
+	for rest := []byte(header); rest != nil; {
+		var (
+			column []byte
+			err    error
+		)
+		if column, rest, err = p.nextField(rest); err != nil {
+			return nil, fmt.Errorf("invalid csv header: %w", err)
+		}
+		name := sanitizeLabelKey(string(column), true)
+		if name != "" {
+			if _, ok := uniqueNames[name]; ok {
+				return nil, fmt.Errorf("duplicate csv column name '%s'", name)
+			}
+			uniqueNames[name] = struct{}{}
+		}
+		p.columns = append(p.columns, name)
+	}
+	if len(uniqueNames) == 0 {
+		return nil, errors.New("at least one csv column name must be supplied")
+	}
+	return p, nil
+}
+
+func (c *CSVParser) Process(_ int64, line []byte, lbs *LabelsBuilder) ([]byte, bool) {
+	parserHints := lbs.ParserLabelHints()
+	if parserHints.NoLabels() {
+		return line, true
+	}
+
+	rest := line
+	for i := 0; i < len(c.columns) && rest != nil; i++ {
+		var (
+			field []byte
+			err   error
+		)
+		if field, rest, err = c.nextField(rest); err != nil {
+			addErrLabel(errCSV, err, lbs)
+			if !parserHints.ShouldContinueParsingLine(logqlmodel.ErrorLabel, lbs) {
+				return line, false
+			}
+			return line, true
+		}
+		name := c.columns[i]
+		if name == "" || len(field) == 0 {
+			continue
+		}
+		key, ok := c.keys.Get(unsafeGetBytes(name), func() (string, bool) {
+			if lbs.BaseHas(name) {
+				name = name + duplicateSuffix
+			}
+			if !parserHints.ShouldExtract(name) {
+				return "", false
+			}
+			return name, true
+		})
+		if !ok {
+			continue
+		}
+
+		lbs.Set(key, string(field))
+		if !parserHints.ShouldContinueParsingLine(key, lbs) {
+			return line, false
+		}
+		if parserHints.AllRequiredExtracted() {
+			break
+		}
+	}
+	return line, true
+}
+
+// nextField returns the next field of a csv line and the rest of the line, which is nil after the last field.
+// A quoted field is unquoted into the parser buffer.
+func (c *CSVParser) nextField(line []byte) ([]byte, []byte, error) {
+	if len(line) == 0 || line[0] != '"' {
+		if i := bytes.Index(line, c.sep); i >= 0 {
+			return line[:i], line[i+len(c.sep):], nil
+		}
+		return line, nil, nil
+	}
+
+	c.buf = c.buf[:0]
+	line = line[1:]
+	for {
+		i := bytes.IndexByte(line, '"')
+		if i < 0 {
+			return nil, nil, errCSVQuote
+		}
+		c.buf = append(c.buf, line[:i]...)
+		line = line[i+1:]
+		// a double quote inside a quoted field is escaped by another double quote.
+		if len(line) == 0 || line[0] != '"' {
+			break
+		}
+		c.buf = append(c.buf, '"')
+		line = line[1:]
+	}
+	if len(line) == 0 {
+		return c.buf, nil, nil
+	}
+	if !bytes.HasPrefix(line, c.sep) {
+		return nil, nil, errCSVQuote
+	}
+	return c.buf, line[len(c.sep):], nil
+}
+
+func (c *CSVParser) RequiredLabelNames() []string { return []string{} }
+
+type KVParser struct {
+	sep    []byte
+	assign []byte
+	keys   internedStringSet
+}
+
+// NewKVParser creates a log stage that can extract labels from key-value pairs separated by sep,
+// each key being separated from its value by assign. e.g. `key:value;key:value`.
+func NewKVParser(sep, assign string) (*KVParser, error) {
+	if sep == "" || assign == "" {
+		return nil, errors.New("key-value separators cannot be empty")
+	}
+	if strings.Contains(sep, assign) || strings.Contains(assign, sep) {
+		return nil, fmt.Errorf("key-value separators '%s' and '%s' are ambiguous", sep, assign)
+	}
+	return &KVParser{
+		sep:    []byte(sep),
+		assign: []byte(assign),
+		keys:   internedStringSet{},
+	}, nil
+}
+
+func (k *KVParser) Process(_ int64, line []byte, lbs *LabelsBuilder) ([]byte, bool) {
+	parserHints := lbs.ParserLabelHints()
+	if parserHints.NoLabels() {
+		return line, true
+	}
+
+	for rest := line; len(rest) > 0; {
+		pair := rest
+		if i := bytes.Index(rest, k.sep); i >= 0 {
+			pair, rest = rest[:i], rest[i+len(k.sep):]
+		} else {
+			rest = nil
+		}
+
+		rawKey, val, ok := bytes.Cut(pair, k.assign)
+		if !ok {
+			continue
+		}
+		rawKey = bytes.TrimSpace(rawKey)
+		val = bytes.TrimSpace(val)
+		if len(val) > 1 && val[0] == '"' && val[len(val)-1] == '"' {
+			val = val[1 : len(val)-1]
+		}
+		// the rune error replacement is rejected by Prometheus, so we skip it.
+		if len(rawKey) == 0 || len(val) == 0 || bytes.ContainsRune(val, utf8.RuneError) {
+			continue
+		}
+
+		key, ok := k.keys.Get(rawKey, func() (string, bool) {
+			sanitized := sanitizeLabelKey(string(rawKey), true)
+			if len(sanitized) == 0 {
+				return "", false
+			}
+			if lbs.BaseHas(sanitized) {
+				sanitized = sanitized + duplicateSuffix
+			}
+			if !parserHints.ShouldExtract(sanitized) {
+				return "", false
+			}
+			return sanitized, true
+		})
+		if !ok {
+			continue
+		}
+
+		lbs.Set(key, string(val))
+		if !parserHints.ShouldContinueParsingLine(key, lbs) {
+			return line, false
+		}
+		if parserHints.AllRequiredExtracted() {
+			break
+		}
+	}
+	return line, true
+}
+
+func (k *KVParser) RequiredLabelNames() []string { return []string{} }
@@ -471,6 +471,36 @@ func TestJSONExpressionParser(t *testing.T) {
 			labels.FromStrings("foo", "bar"),
 			noParserHints,
 		},
+		{
+			"wildcard array access",
+			[]byte(`{"items":[{"sku":"a","qty":1},{"sku":"b","qty":2},{"qty":3}],"tags":["x","y"]}`),
+			[]LabelExtractionExpr{
+				NewLabelExtractionExpr("sku", `items[*].sku`),
+				NewLabelExtractionExpr("tags", `tags[*]`),
+				NewLabelExtractionExpr("first", `items[0].sku`),
+			},
+			labels.FromStrings("foo", "bar"),
+			labels.FromStrings("foo", "bar",
+				"first", "a",
+				"sku", "a,b",
+				"tags", "x,y",
+			),
+			noParserHints,
+		},
+		{
+			"wildcard access on missing array",
+			[]byte(`{"items":{"sku":"a"}}`),
+			[]LabelExtractionExpr{
+				NewLabelExtractionExpr("sku", `items[*].sku`),
+				NewLabelExtractionExpr("missing", `missing[*]`),
+			},
+			labels.FromStrings("foo", "bar"),
+			labels.FromStrings("foo", "bar",
+				"missing", "",
+				"sku", "",
+			),
+			noParserHints,
+		},
 		{
 			"nested escaped object",
 			[]byte(`{"app":"{ \"key\": \"value\", \"key2\":\"value2\"}"}`),
@@ -1360,6 +1390,154 @@ func Test_PatternParser(t *testing.T) {
 	}
 }
 
+func Test_CSVParser(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		sep    string
+		line   []byte
+		lbs    labels.Labels
+		want   labels.Labels
+	}{
+		{
+			"simple",
+			"type,time,elb,client",
+			",",
+			[]byte(`https,2018-07-02T22:23:00.186641Z,app/my-loadbalancer,192.168.131.39:2817`),
+			labels.FromStrings("foo", "bar"),
+			labels.FromStrings("foo", "bar",
+				"type", "https",
+				"time", "2018-07-02T22:23:00.186641Z",
+				"elb", "app/my-loadbalancer",
+				"client", "192.168.131.39:2817",
+			),
+		},
+		{
+			"quoted fields and skipped columns",
+			`method,,"user agent",status`,
+			",",
+			[]byte(`GET,/index.html,"Mozilla/5.0 (X11; Linux), ""quoted""",200`),
+			labels.FromStrings("status", "500"),
+			labels.FromStrings("status", "500",
+				"method", "GET",
+				"user_agent", `Mozilla/5.0 (X11; Linux), "quoted"`,
+				"status_extracted", "200",
+			),
+		},
+		{
+			"tabs with missing and extra fields",
+			"a\tb\tc",
+			"\t",
+			[]byte("1\t\t"),
+			labels.EmptyLabels(),
+			labels.FromStrings("a", "1"),
+		},
+		{
+			"extra fields",
+			"a;b",
+			";",
+			[]byte("1;2;3;4"),
+			labels.EmptyLabels(),
+			labels.FromStrings("a", "1", "b", "2"),
+		},
+		{
+			"unterminated quote",
+			"a,b",
+			",",
+			[]byte(`1,"2`),
+			labels.EmptyLabels(),
+			labels.FromStrings("a", "1",
+				logqlmodel.ErrorLabel, errCSV,
+				logqlmodel.ErrorDetailsLabel, errCSVQuote.Error(),
+			),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			b := NewBaseLabelsBuilder().ForLabels(tt.lbs, tt.lbs.Hash())
+			b.Reset()
+			p, err := NewCSVParser(tt.header, tt.sep)
+			require.NoError(t, err)
+			_, _ = p.Process(0, tt.line, b)
+			require.Equal(t, tt.want, b.LabelsResult().Labels())
+		})
+	}
+}
+
+func TestNewCSVParserFailures(t *testing.T) {
+	for _, tc := range []struct{ header, sep string }{
+		{"a,b", ""},
+		{"a,b", ",,"},
+		{"a,b", `"`},
+		{",", ","},
+		{"a,a", ","},
+		{`a,"b`, ","},
+	} {
+		_, err := NewCSVParser(tc.header, tc.sep)
+		require.Error(t, err, tc)
+	}
+}
+
+func Test_KVParser(t *testing.T) {
+	tests := []struct {
+		name   string
+		sep    string
+		assign string
+		line   []byte
+		lbs    labels.Labels
+		want   labels.Labels
+	}{
+		{
+			"custom separators",
+			";",
+			":",
+			[]byte(`user:bob; status : 200;duration:"1.2s";path`),
+			labels.FromStrings("status", "500"),
+			labels.FromStrings("status", "500",
+				"user", "bob",
+				"status_extracted", "200",
+				"duration", "1.2s",
+			),
+		},
+		{
+			"multi characters separators",
+			", ",
+			"=>",
+			[]byte(`user-name=>bob, empty=>, ids=>1,2`),
+			labels.EmptyLabels(),
+			labels.FromStrings("user_name", "bob", "ids", "1,2"),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			b := NewBaseLabelsBuilder().ForLabels(tt.lbs, tt.lbs.Hash())
+			b.Reset()
+			p, err := NewKVParser(tt.sep, tt.assign)
+			require.NoError(t, err)
+			_, _ = p.Process(0, tt.line, b)
+			require.Equal(t, tt.want, b.LabelsResult().Labels())
+		})
+	}
+}
+
+func TestNewKVParserFailures(t *testing.T) {
+	for _, tc := range []struct{ sep, assign string }{
+		{"", "="},
+		{",", ""},
+		{"=", "="},
+		{"==", "="},
+	} {
+		_, err := NewKVParser(tc.sep, tc.assign)
+		require.Error(t, err, tc)
+	}
+}
+
 func BenchmarkJsonExpressionParser(b *testing.B) {
 	simpleJsn := []byte(`{
       "data": "Click Here",
@@ -25,6 +25,10 @@ type StreamPipeline interface {
 	// The buffer returned for the log line can be reused on subsequent calls to Process and therefore must be copied.
 	Process(ts int64, line []byte, structuredMetadata ...labels.Label) (resultLine []byte, resultLabels LabelsResult, matches bool)
 	ProcessString(ts int64, line string, structuredMetadata ...labels.Label) (resultLine string, resultLabels LabelsResult, matches bool)
+	// Next returns the next line of the last processed log line when it was fanned out into several lines,
+	// like the unnest stage does. It must be called until it returns false before processing the next log line.
+	// The buffer returned for the log line follows the same rules as for Process.
+	Next() (resultLine []byte, resultLabels LabelsResult, matches bool)
 }
 
 // Stage is a single step of a Pipeline.
@@ -98,6 +102,8 @@ func (n noopStreamPipeline) ProcessString(ts int64, line string, structuredMetad
 	return line, lr, ok
 }
 
+func (n noopStreamPipeline) Next() ([]byte, LabelsResult, bool) { return nil, nil, false }
+
 func (n noopStreamPipeline) BaseLabels() LabelsResult { return n.builder.currentResult }
 
 type noopStage struct{}
@@ -166,10 +172,16 @@ func NewPipeline(stages []Stage) Pipeline {
 type streamPipeline struct {
 	stages  []Stage
 	builder *LabelsBuilder
+	unnest  *unnestState
 }
 
 func NewStreamPipeline(stages []Stage, labelsBuilder *LabelsBuilder) StreamPipeline {
-	return &streamPipeline{stages, labelsBuilder}
+	stages, unnester, post := splitUnnest(stages)
+	p := &streamPipeline{stages: stages, builder: labelsBuilder}
+	if unnester != nil {
+		p.unnest = newUnnestState(unnester, post, labelsBuilder)
+	}
+	return p
 }
 
 func (p *pipeline) ForStream(labels labels.Labels) StreamPipeline {
@@ -212,6 +224,22 @@ func (p *streamPipeline) Process(ts int64, line []byte, structuredMetadata ...la
 			return nil, nil, false
 		}
 	}
+	if p.unnest != nil {
+		if line, ok = p.unnest.start(ts, line); !ok {
+			return nil, nil, false
+		}
+	}
+	return line, p.builder.LabelsResult(), true
+}
+
+func (p *streamPipeline) Next() ([]byte, LabelsResult, bool) {
+	if p.unnest == nil {
+		return nil, nil, false
+	}
+	line, ok := p.unnest.nextLine()
+	if !ok {
+		return nil, nil, false
+	}
 	return line, p.builder.LabelsResult(), true
 }
 
@@ -326,6 +354,10 @@ func (sp *filteringStreamPipeline) ProcessString(ts int64, line string, structur
 	return sp.pipeline.ProcessString(ts, line, structuredMetadata...)
 }
 
+func (sp *filteringStreamPipeline) Next() ([]byte, LabelsResult, bool) {
+	return sp.pipeline.Next()
+}
+
 // ReduceStages reduces multiple stages into one.
 func ReduceStages(stages []Stage) Stage {
 	if len(stages) == 0 {
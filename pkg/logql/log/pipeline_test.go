@@ -256,6 +256,10 @@ func (p *stubStreamPipeline) ProcessString(_ int64, _ string, _ ...labels.Label)
 	return "", nil, true
 }
 
+func (p *stubStreamPipeline) Next() ([]byte, LabelsResult, bool) {
+	return nil, nil, false
+}
+
 var (
 	resMatches    bool
 	resLine       []byte
@@ -0,0 +1,225 @@
+package log
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/buger/jsonparser"
+	"github.com/prometheus/prometheus/model/labels"
+
+	"github.com/grafana/loki/pkg/logql/log/jsonexpr"
+)
+
+var _ Stage = &Unnester{}
+
+type jsonElement struct {
+	value []byte
+	typ   jsonparser.ValueType
+}
+
+// Unnester fans out a json log line into one line per element of an array field.
+// Each line is the json of its element and the fields of the element are added as labels,
+// nested fields being flattened like the json parser does. An element which is not an object or
+// an array is added as a label named after the field, and its value is the line.
+// Lines without the field, or with an empty array, are filtered out, and a field which is not
+// an array is unnested as an array with a single element.
+//
+// Only a StreamPipeline or a StreamSampleExtractor can fan out a line, the Process method of the stage
+// only returns the first element of the array.
+type Unnester struct {
+	path  []string
+	label string
+
+	prefixBuffer []byte
+	keys         internedStringSet
+	elems        []jsonElement
+}
+
+// NewUnnester creates a new Unnester for the array at the given json path, e.g. `items` or `request.items`.
+func NewUnnester(field string) (*Unnester, error) {
+	path, err := jsonexpr.Parse(field, false)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse unnest field [%s]: %w", field, err)
+	}
+	if len(path) == 0 {
+		return nil, errors.New("unnest field cannot be empty")
+	}
+	if hasWildcard(path) {
+		return nil, fmt.Errorf("cannot unnest wildcard field [%s]", field)
+	}
+	label := field
+	if last, ok := path[len(path)-1].(string); ok {
+		label = last
+	}
+	return &Unnester{
+		path:         pathsToString(path),
+		label:        sanitizeLabelKey(label, true),
+		prefixBuffer: make([]byte, 0, 1024),
+		keys:         internedStringSet{},
+	}, nil
+}
+
+func (u *Unnester) Process(_ int64, line []byte, lbs *LabelsBuilder) ([]byte, bool) {
+	var ok bool
+	u.elems, ok = u.elements(line, u.elems[:0], lbs)
+	if !ok {
+		return line, true
+	}
+	if len(u.elems) == 0 {
+		return nil, false
+	}
+	return u.element(u.elems[0], lbs), true
+}
+
+func (u *Unnester) RequiredLabelNames() []string { return []string{} }
+
+// elements appends the elements of the array of the line to elems.
+// It returns false when the line is not valid json, in which case the error label is set.
+func (u *Unnester) elements(line []byte, elems []jsonElement, lbs *LabelsBuilder) ([]jsonElement, bool) {
+	if len(line) == 0 || !isValidJSONStart(line) {
+		addErrLabel(errJSON, nil, lbs)
+		return elems, false
+	}
+	value, typ, _, err := jsonparser.Get(line, u.path...)
+	if err != nil {
+		if errors.Is(err, jsonparser.KeyPathNotFoundError) {
+			return elems, true
+		}
+		addErrLabel(errJSON, err, lbs)
+		return elems, false
+	}
+	if typ != jsonparser.Array {
+		return append(elems, jsonElement{value: value, typ: typ}), true
+	}
+	if _, err := jsonparser.ArrayEach(value, func(v []byte, typ jsonparser.ValueType, _ int, _ error) {
+		elems = append(elems, jsonElement{value: v, typ: typ})
+	}); err != nil {
+		addErrLabel(errJSON, err, lbs)
+		return elems, false
+	}
+	return elems, true
+}
+
+// element adds the labels of an element and returns its line.
+func (u *Unnester) element(e jsonElement, lbs *LabelsBuilder) []byte {
+	switch e.typ {
+	case jsonparser.Object:
+		u.prefixBuffer = u.prefixBuffer[:0]
+		_ = jsonparser.ObjectEach(e.value, func(key, value []byte, typ jsonparser.ValueType, _ int) error {
+			u.addLabels(key, value, typ, lbs)
+			return nil
+		})
+		return e.value
+	case jsonparser.Array:
+		return e.value
+	default:
+		value := readValue(e.value, e.typ)
+		lbs.Set(u.labelKey(u.label, lbs), value)
+		return unsafeGetBytes(value)
+	}
+}
+
+func (u *Unnester) addLabels(key, value []byte, typ jsonparser.ValueType, lbs *LabelsBuilder) {
+	prefixLen := len(u.prefixBuffer)
+	defer func() {
+		// rollback the prefix as we exit the current field.
+		u.prefixBuffer = u.prefixBuffer[:prefixLen]
+	}()
+	if prefixLen != 0 {
+		u.prefixBuffer = append(u.prefixBuffer, byte(jsonSpacer))
+	}
+	u.prefixBuffer = appendSanitized(u.prefixBuffer, key)
+
+	switch typ {
+	case jsonparser.String, jsonparser.Number, jsonparser.Boolean, jsonparser.Null:
+		lbs.Set(u.labelKey(unsafeGetString(u.prefixBuffer), lbs), readValue(value, typ))
+	case jsonparser.Object:
+		_ = jsonparser.ObjectEach(value, func(key, value []byte, typ jsonparser.ValueType, _ int) error {
+			u.addLabels(key, value, typ, lbs)
+			return nil
+		})
+	}
+}
+
+func (u *Unnester) labelKey(name string, lbs *LabelsBuilder) string {
+	key, _ := u.keys.Get(unsafeGetBytes(name), func() (string, bool) {
+		field := strings.Clone(name)
+		if lbs.BaseHas(field) {
+			field = field + duplicateSuffix
+		}
+		return field, true
+	})
+	return key
+}
+
+// splitUnnest splits stages around their unnest stage, if any.
+func splitUnnest(stages []Stage) ([]Stage, *Unnester, []Stage) {
+	for i, s := range stages {
+		if u, ok := s.(*Unnester); ok {
+			return stages[:i], u, stages[i+1:]
+		}
+	}
+	return stages, nil, nil
+}
+
+// unnestState fans out the lines processed by a stream pipeline or a stream sample extractor at its unnest stage.
+// It keeps the elements of the last line which were not returned yet.
+type unnestState struct {
+	unnester *Unnester
+	post     Stage
+	builder  *LabelsBuilder
+
+	ts    int64
+	elems []jsonElement
+	next  int
+
+	// the labels of the line before it was fanned out.
+	add        []labels.Label
+	del        []string
+	err        string
+	errDetails string
+}
+
+func newUnnestState(unnester *Unnester, post []Stage, builder *LabelsBuilder) *unnestState {
+	return &unnestState{
+		unnester: unnester,
+		post:     ReduceStages(post),
+		builder:  builder,
+	}
+}
+
+// start fans out a line which was processed by the stages before the unnest stage,
+// and returns the first element passing the stages after it.
+func (u *unnestState) start(ts int64, line []byte) ([]byte, bool) {
+	var ok bool
+	u.ts, u.next = ts, 0
+	u.elems, ok = u.unnester.elements(line, u.elems[:0], u.builder)
+	if !ok {
+		return u.post.Process(ts, line, u.builder)
+	}
+
+	u.add = append(u.add[:0], u.builder.add...)
+	u.del = append(u.del[:0], u.builder.del...)
+	u.err, u.errDetails = u.builder.err, u.builder.errDetails
+	return u.nextLine()
+}
+
+// nextLine returns the next element of the last line passing the stages after the unnest stage.
+func (u *unnestState) nextLine() ([]byte, bool) {
+	for u.next < len(u.elems) {
+		e := u.elems[u.next]
+		u.next++
+
+		// the labels builder is shared, so its state is restored for every element.
+		u.builder.add = append(u.builder.add[:0], u.add...)
+		u.builder.del = append(u.builder.del[:0], u.del...)
+		u.builder.err, u.builder.errDetails = u.err, u.errDetails
+		u.builder.parserKeyHints.Reset()
+
+		if line, ok := u.post.Process(u.ts, u.unnester.element(e, u.builder), u.builder); ok {
+			return line, true
+		}
+	}
+	return nil, false
+}
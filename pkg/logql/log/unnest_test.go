@@ -0,0 +1,158 @@
+package log
+
+import (
+	"testing"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/require"
+)
+
+func mustUnnester(t *testing.T, field string) *Unnester {
+	t.Helper()
+	u, err := NewUnnester(field)
+	require.NoError(t, err)
+	return u
+}
+
+func Test_UnnestPipeline(t *testing.T) {
+	type result struct {
+		line string
+		lbs  labels.Labels
+	}
+	lbs := labels.FromStrings("app", "foo")
+	for _, tc := range []struct {
+		name   string
+		stages func(t *testing.T) []Stage
+		line   string
+		want   []result
+	}{
+		{
+			"array of objects",
+			func(t *testing.T) []Stage { return []Stage{mustUnnester(t, "items")} },
+			`{"id":1,"items":[{"sku":"a","price":{"amount":2}},{"sku":"b"}]}`,
+			[]result{
+				{`{"sku":"a","price":{"amount":2}}`, labels.FromStrings("app", "foo", "price_amount", "2", "sku", "a")},
+				{`{"sku":"b"}`, labels.FromStrings("app", "foo", "sku", "b")},
+			},
+		},
+		{
+			"array of values with nested field",
+			func(t *testing.T) []Stage { return []Stage{mustUnnester(t, "order.tags")} },
+			`{"order":{"tags":["x","y"]}}`,
+			[]result{
+				{`x`, labels.FromStrings("app", "foo", "tags", "x")},
+				{`y`, labels.FromStrings("app", "foo", "tags", "y")},
+			},
+		},
+		{
+			"labels before the unnest stage are kept and filters after it are applied to each element",
+			func(t *testing.T) []Stage {
+				return []Stage{
+					NewJSONParser(),
+					mustUnnester(t, "items"),
+					NewStringLabelFilter(labels.MustNewMatcher(labels.MatchNotEqual, "sku", "b")),
+				}
+			},
+			`{"id":1,"items":[{"sku":"a"},{"sku":"b"},{"sku":"c"}]}`,
+			[]result{
+				{`{"sku":"a"}`, labels.FromStrings("app", "foo", "id", "1", "sku", "a")},
+				{`{"sku":"c"}`, labels.FromStrings("app", "foo", "id", "1", "sku", "c")},
+			},
+		},
+		{
+			"missing field",
+			func(t *testing.T) []Stage { return []Stage{mustUnnester(t, "items")} },
+			`{"id":1}`,
+			nil,
+		},
+		{
+			"empty array",
+			func(t *testing.T) []Stage { return []Stage{mustUnnester(t, "items")} },
+			`{"items":[]}`,
+			nil,
+		},
+		{
+			"not an array",
+			func(t *testing.T) []Stage { return []Stage{mustUnnester(t, "items")} },
+			`{"items":{"sku":"a"}}`,
+			[]result{
+				{`{"sku":"a"}`, labels.FromStrings("app", "foo", "sku", "a")},
+			},
+		},
+		{
+			"invalid json",
+			func(t *testing.T) []Stage { return []Stage{mustUnnester(t, "items")} },
+			`items=a`,
+			[]result{
+				{`items=a`, labels.FromStrings("app", "foo", "__error__", errJSON)},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			sp := NewPipeline(tc.stages(t)).ForStream(lbs)
+			var got []result
+			for line, res, ok := sp.ProcessString(0, tc.line); ok; {
+				got = append(got, result{line, res.Labels()})
+				var next []byte
+				if next, res, ok = sp.Next(); ok {
+					line = string(next)
+				}
+			}
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func Test_UnnestSampleExtractor(t *testing.T) {
+	type sample struct {
+		value float64
+		lbs   labels.Labels
+	}
+	line := []byte(`{"items":[{"sku":"a","qty":2},{"sku":"b","qty":3},{"sku":"a","qty":4}]}`)
+	for _, tc := range []struct {
+		name string
+		ex   func(t *testing.T) SampleExtractor
+		want []sample
+	}{
+		{
+			"count by sku",
+			func(t *testing.T) SampleExtractor {
+				return mustSampleExtractor(NewLineSampleExtractor(CountExtractor, []Stage{mustUnnester(t, "items")}, []string{"sku"}, false, false))
+			},
+			[]sample{
+				{1, labels.FromStrings("sku", "a")},
+				{1, labels.FromStrings("sku", "b")},
+				{1, labels.FromStrings("sku", "a")},
+			},
+		},
+		{
+			"unwrap qty",
+			func(t *testing.T) SampleExtractor {
+				return mustSampleExtractor(LabelExtractorWithStages(
+					"qty", ConvertFloat, []string{"sku"}, false, false,
+					[]Stage{mustUnnester(t, "items")}, NewStringLabelFilter(labels.MustNewMatcher(labels.MatchEqual, "sku", "a")),
+				))
+			},
+			[]sample{
+				{2, labels.FromStrings("sku", "a")},
+				{4, labels.FromStrings("sku", "a")},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			sp := tc.ex(t).ForStream(labels.FromStrings("app", "foo"))
+			var got []sample
+			for v, lbs, ok := sp.Process(0, line); ok; v, lbs, ok = sp.Next() {
+				got = append(got, sample{v, lbs.Labels()})
+			}
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func Test_NewUnnester(t *testing.T) {
+	for _, field := range []string{"", "items[*]", "items["} {
+		_, err := NewUnnester(field)
+		require.Error(t, err, field)
+	}
+}
@@ -0,0 +1,460 @@
+package log
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/loki/pkg/logqlmodel"
+)
+
+var (
+	_ Stage = &XMLParser{}
+	_ Stage = &XMLExpressionParser{}
+
+	errXMLNoElement    = errors.New("expecting an xml element, but there is none")
+	errXMLTrailingData = errors.New("unexpected data after the xml root element")
+)
+
+// xmlVisitor is called by xmlWalker for each xml element of a log line.
+type xmlVisitor interface {
+	// start is called when an element is opened at the given depth, the root element being at depth 0.
+	start(depth int, e xml.StartElement) error
+	// end is called when an element is closed with the text directly within it, which is only valid during the call.
+	end(depth int, text []byte) error
+}
+
+// xmlWalker walks the elements of xml log lines.
+type xmlWalker struct {
+	text   []byte
+	starts []int
+}
+
+func (w *xmlWalker) walk(line []byte, v xmlVisitor) error {
+	line = bytes.TrimSpace(line)
+	if len(line) == 0 || line[0] != '<' {
+		return errXMLNoElement
+	}
+
+	w.text = w.text[:0]
+	w.starts = w.starts[:0]
+	var found bool
+	dec := xml.NewDecoder(bytes.NewReader(line))
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if len(w.starts) == 0 && found {
+				return errXMLTrailingData
+			}
+			found = true
+			if err := v.start(len(w.starts), t); err != nil {
+				return err
+			}
+			w.starts = append(w.starts, len(w.text))
+		case xml.EndElement:
+			depth := len(w.starts) - 1
+			start := w.starts[depth]
+			if err := v.end(depth, bytes.TrimSpace(w.text[start:])); err != nil {
+				return err
+			}
+			w.text = w.text[:start]
+			w.starts = w.starts[:depth]
+		case xml.CharData:
+			if len(w.starts) == 0 {
+				if len(bytes.TrimSpace(t)) != 0 {
+					return errXMLTrailingData
+				}
+				continue
+			}
+			w.text = append(w.text, t...)
+		}
+	}
+	if !found {
+		return errXMLNoElement
+	}
+	return nil
+}
+
+// XMLParser extracts the text and the attributes of every element of an xml log line as labels.
+// Label names are the path of the element from the root element joined with `_`, e.g. `Event_System_EventID`,
+// attributes being suffixed with their name. Only the first element of a repeated path is extracted.
+type XMLParser struct {
+	walker       xmlWalker
+	prefixBuffer []byte // buffer used to build label names
+	prefixes     []int  // length of the prefix buffer at each depth
+	extracted    []string
+
+	lbs         *LabelsBuilder
+	parserHints ParserHint
+	keys        internedStringSet
+}
+
+// NewXMLParser creates a log stage that can parse an xml log line and add elements and attributes as labels.
+func NewXMLParser() *XMLParser {
+	return &XMLParser{
+		prefixBuffer: make([]byte, 0, 1024),
+		keys:         internedStringSet{},
+	}
+}
+
+func (x *XMLParser) Process(_ int64, line []byte, lbs *LabelsBuilder) ([]byte, bool) {
+	parserHints := lbs.ParserLabelHints()
+	if parserHints.NoLabels() {
+		return line, true
+	}
+
+	// reset the state.
+	x.prefixBuffer = x.prefixBuffer[:0]
+	x.prefixes = x.prefixes[:0]
+	x.extracted = x.extracted[:0]
+	x.lbs = lbs
+	x.parserHints = parserHints
+
+	if err := x.walker.walk(line, x); err != nil {
+		if errors.Is(err, errFoundAllLabels) {
+			// Short-circuited
+			return line, true
+		}
+
+		if errors.Is(err, errLabelDoesNotMatch) {
+			// one of the label matchers does not match. The whole line can be thrown away
+			return line, false
+		}
+
+		addErrLabel(errXML, err, lbs)
+
+		if !parserHints.ShouldContinueParsingLine(logqlmodel.ErrorLabel, lbs) {
+			return line, false
+		}
+		return line, true
+	}
+	return line, true
+}
+
+func (x *XMLParser) start(_ int, e xml.StartElement) error {
+	x.prefixes = append(x.prefixes, len(x.prefixBuffer))
+	if len(x.prefixBuffer) != 0 {
+		x.prefixBuffer = append(x.prefixBuffer, byte(jsonSpacer))
+	}
+	x.prefixBuffer = appendSanitized(x.prefixBuffer, []byte(e.Name.Local))
+
+	for _, attr := range e.Attr {
+		// namespace declarations are not attributes of the element.
+		if attr.Name.Space == "xmlns" || attr.Name.Local == "xmlns" {
+			continue
+		}
+		prefixLen := len(x.prefixBuffer)
+		x.prefixBuffer = append(x.prefixBuffer, byte(jsonSpacer))
+		x.prefixBuffer = appendSanitized(x.prefixBuffer, []byte(attr.Name.Local))
+		err := x.setLabel(attr.Value)
+		x.prefixBuffer = x.prefixBuffer[:prefixLen]
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (x *XMLParser) end(depth int, text []byte) error {
+	var err error
+	if len(text) != 0 {
+		err = x.setLabel(string(text))
+	}
+	// rollback the prefix as we exit the current element.
+	x.prefixBuffer = x.prefixBuffer[:x.prefixes[depth]]
+	x.prefixes = x.prefixes[:depth]
+	return err
+}
+
+// setLabel sets the label named after the prefix buffer, unless it was already extracted from the line.
+func (x *XMLParser) setLabel(value string) error {
+	key, ok := x.keys.Get(x.prefixBuffer, func() (string, bool) {
+		field := string(x.prefixBuffer)
+		if x.lbs.BaseHas(field) {
+			field = field + duplicateSuffix
+		}
+		if !x.parserHints.ShouldExtract(field) {
+			return "", false
+		}
+		return field, true
+	})
+	if !ok {
+		return nil
+	}
+	for _, k := range x.extracted {
+		if k == key {
+			return nil
+		}
+	}
+	x.extracted = append(x.extracted, key)
+
+	x.lbs.Set(key, value)
+	if !x.parserHints.ShouldContinueParsingLine(key, x.lbs) {
+		return errLabelDoesNotMatch
+	}
+	if x.parserHints.AllRequiredExtracted() {
+		// Not actually an error. Parsing can be short-circuited
+		return errFoundAllLabels
+	}
+	return nil
+}
+
+func (x *XMLParser) RequiredLabelNames() []string { return []string{} }
+
+// xmlStep is a step of an xml path, matching an element by its name and optional predicates.
+type xmlStep struct {
+	name string // `*` matches any element.
+
+	// attribute predicate, e.g. `[@name='value']`.
+	attr, attrValue string
+	hasAttr         bool
+
+	// position predicate among the matching siblings, starting at 1, e.g. `[2]`. 0 when not set.
+	index int
+}
+
+func (s xmlStep) matches(e xml.StartElement) bool {
+	if s.name != "*" && s.name != e.Name.Local {
+		return false
+	}
+	if !s.hasAttr {
+		return true
+	}
+	for _, attr := range e.Attr {
+		if attr.Name.Local == s.attr {
+			return attr.Value == s.attrValue
+		}
+	}
+	return false
+}
+
+// xmlPath is a compiled XPath-lite expression, e.g. `/Event/EventData/Data[@Name='User']` or `/Event/System/Provider/@Name`.
+type xmlPath struct {
+	steps []xmlStep
+	attr  string // the attribute selected by the path, if any.
+
+	// matching state of the path for the line being parsed.
+	matched int   // number of steps matched by the open elements.
+	counts  []int // number of siblings matched by each step.
+	found   bool
+}
+
+// parseXMLPath parses an XPath-lite expression. Paths start at the root element and are made of element
+// names or `*`, each optionally followed by `[@attr='value']` or `[n]` predicates,
+// and can end with `@attr` to select an attribute instead of the text of the element.
+func parseXMLPath(expr string) (*xmlPath, error) {
+	if strings.HasPrefix(expr, "//") {
+		return nil, errors.New("descendant paths are not supported")
+	}
+	p := &xmlPath{}
+	s := strings.TrimPrefix(expr, "/")
+	for {
+		if strings.HasPrefix(s, "@") {
+			p.attr = s[1:]
+			if len(p.steps) == 0 || p.attr == "" || strings.ContainsAny(p.attr, "/[]@") {
+				return nil, fmt.Errorf("invalid attribute selector '%s'", s)
+			}
+			break
+		}
+
+		end := strings.IndexAny(s, "/[")
+		if end < 0 {
+			end = len(s)
+		}
+		step := xmlStep{name: s[:end]}
+		if step.name == "" || strings.ContainsAny(step.name, "]@'\"=") {
+			return nil, fmt.Errorf("invalid element name '%s'", step.name)
+		}
+		s = s[end:]
+
+		for strings.HasPrefix(s, "[") {
+			var err error
+			if s, err = parseXMLPredicate(s, &step); err != nil {
+				return nil, err
+			}
+		}
+		p.steps = append(p.steps, step)
+
+		if s == "" {
+			break
+		}
+		if s[0] != '/' {
+			return nil, fmt.Errorf("unexpected '%s'", s)
+		}
+		s = s[1:]
+	}
+	p.counts = make([]int, len(p.steps))
+	return p, nil
+}
+
+// parseXMLPredicate parses the predicate at the start of s into the step, and returns the rest of s.
+func parseXMLPredicate(s string, step *xmlStep) (string, error) {
+	if !strings.HasPrefix(s, "[@") {
+		end := strings.IndexByte(s, ']')
+		if end < 0 {
+			return "", fmt.Errorf("missing closing ']' in '%s'", s)
+		}
+		index, err := strconv.Atoi(s[1:end])
+		if err != nil || index < 1 || step.index != 0 {
+			return "", fmt.Errorf("invalid position predicate '%s'", s[:end+1])
+		}
+		step.index = index
+		return s[end+1:], nil
+	}
+
+	eq := strings.IndexByte(s, '=')
+	if eq < 0 || eq+1 == len(s) || (s[eq+1] != '\'' && s[eq+1] != '"') || step.hasAttr {
+		return "", fmt.Errorf("invalid attribute predicate '%s'", s)
+	}
+	quote := s[eq+1]
+	end := strings.IndexByte(s[eq+2:], quote)
+	if end < 0 || !strings.HasPrefix(s[eq+2+end+1:], "]") {
+		return "", fmt.Errorf("invalid attribute predicate '%s'", s)
+	}
+	step.attr = strings.TrimSpace(s[2:eq])
+	step.attrValue = s[eq+2 : eq+2+end]
+	step.hasAttr = true
+	if step.attr == "" {
+		return "", fmt.Errorf("invalid attribute predicate '%s'", s)
+	}
+	return s[eq+2+end+2:], nil
+}
+
+// reset resets the matching state of the path for a new line.
+func (p *xmlPath) reset() {
+	p.matched = 0
+	p.counts[0] = 0
+	p.found = false
+}
+
+// XMLExpressionParser extracts labels from an xml log line using XPath-lite expressions.
+// Only the first element matching an expression is extracted, and the label is empty when there is none.
+type XMLExpressionParser struct {
+	ids    []string
+	paths  []*xmlPath
+	walker xmlWalker
+
+	lbs  *LabelsBuilder
+	keys internedStringSet
+}
+
+func NewXMLExpressionParser(expressions []LabelExtractionExpr) (*XMLExpressionParser, error) {
+	var ids []string
+	var paths []*xmlPath
+	for _, exp := range expressions {
+		path, err := parseXMLPath(exp.Expression)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse expression [%s]: %w", exp.Expression, err)
+		}
+
+		if !model.LabelName(exp.Identifier).IsValid() {
+			return nil, fmt.Errorf("invalid extracted label name '%s'", exp.Identifier)
+		}
+
+		ids = append(ids, exp.Identifier)
+		paths = append(paths, path)
+	}
+
+	return &XMLExpressionParser{
+		ids:   ids,
+		paths: paths,
+		keys:  internedStringSet{},
+	}, nil
+}
+
+func (x *XMLExpressionParser) Process(_ int64, line []byte, lbs *LabelsBuilder) ([]byte, bool) {
+	if len(line) == 0 || lbs.ParserLabelHints().NoLabels() {
+		return line, true
+	}
+
+	x.lbs = lbs
+	for _, p := range x.paths {
+		p.reset()
+	}
+	if err := x.walker.walk(line, x); err != nil {
+		addErrLabel(errXML, err, lbs)
+	}
+
+	// Ensure there's a label for every expression
+	for i, p := range x.paths {
+		if !p.found {
+			if _, ok := lbs.Get(x.ids[i]); !ok {
+				lbs.Set(x.ids[i], "")
+			}
+		}
+	}
+	return line, true
+}
+
+func (x *XMLExpressionParser) start(depth int, e xml.StartElement) error {
+	for i, p := range x.paths {
+		if p.found || p.matched != depth || depth >= len(p.steps) {
+			continue
+		}
+		step := p.steps[depth]
+		if !step.matches(e) {
+			continue
+		}
+		p.counts[depth]++
+		if step.index != 0 && p.counts[depth] != step.index {
+			continue
+		}
+		p.matched++
+		if p.matched < len(p.steps) {
+			// start counting the children of the element.
+			p.counts[p.matched] = 0
+			continue
+		}
+		if p.attr == "" {
+			// the text of the element is extracted when it's closed.
+			continue
+		}
+		for _, attr := range e.Attr {
+			if attr.Name.Local == p.attr {
+				x.lbs.Set(x.labelKey(x.ids[i]), attr.Value)
+				p.found = true
+				break
+			}
+		}
+	}
+	return nil
+}
+
+func (x *XMLExpressionParser) end(depth int, text []byte) error {
+	for i, p := range x.paths {
+		if p.found || p.matched != depth+1 {
+			continue
+		}
+		p.matched = depth
+		if p.attr == "" && p.matched+1 == len(p.steps) {
+			x.lbs.Set(x.labelKey(x.ids[i]), string(text))
+			p.found = true
+		}
+	}
+	return nil
+}
+
+func (x *XMLExpressionParser) labelKey(identifier string) string {
+	key, _ := x.keys.Get(unsafeGetBytes(identifier), func() (string, bool) {
+		if x.lbs.BaseHas(identifier) {
+			identifier = identifier + duplicateSuffix
+		}
+		return identifier, true
+	})
+	return key
+}
+
+func (x *XMLExpressionParser) RequiredLabelNames() []string { return []string{} }
@@ -0,0 +1,203 @@
+package log
+
+import (
+	"testing"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/pkg/logqlmodel"
+)
+
+var windowsEvent = []byte(`<Event xmlns="http://schemas.microsoft.com/win/2004/08/events/event">
+  <System>
+    <Provider Name="Microsoft-Windows-Security-Auditing"/>
+    <EventID>4624</EventID>
+    <Level>0</Level>
+  </System>
+  <EventData>
+    <Data Name="SubjectUserName">-</Data>
+    <Data Name="TargetUserName">bob</Data>
+    <Data Name="LogonType"><![CDATA[3]]></Data>
+  </EventData>
+</Event>`)
+
+func Test_XMLParser(t *testing.T) {
+	tests := []struct {
+		name string
+		line []byte
+		lbs  labels.Labels
+		want labels.Labels
+	}{
+		{
+			"windows event",
+			windowsEvent,
+			labels.FromStrings("level", "info"),
+			labels.FromStrings("level", "info",
+				"Event_System_Provider_Name", "Microsoft-Windows-Security-Auditing",
+				"Event_System_EventID", "4624",
+				"Event_System_Level", "0",
+				"Event_EventData_Data_Name", "SubjectUserName",
+				"Event_EventData_Data", "-",
+			),
+		},
+		{
+			"soap envelope",
+			[]byte(`<?xml version="1.0"?><soap:Envelope xmlns:soap="http://www.w3.org/2003/05/soap-envelope"><soap:Body><m:GetPrice xmlns:m="https://example.com/prices"><m:Item>Apples &amp; pears</m:Item></m:GetPrice></soap:Body></soap:Envelope>`),
+			labels.FromStrings("Envelope_Body_GetPrice_Item", "foo"),
+			labels.FromStrings("Envelope_Body_GetPrice_Item", "foo",
+				"Envelope_Body_GetPrice_Item_extracted", "Apples & pears",
+			),
+		},
+		{
+			"not xml",
+			[]byte(`level=info msg="hello"`),
+			labels.EmptyLabels(),
+			labels.FromStrings(
+				logqlmodel.ErrorLabel, errXML,
+				logqlmodel.ErrorDetailsLabel, errXMLNoElement.Error(),
+			),
+		},
+		{
+			"unclosed element",
+			[]byte(`<a><b>1</b>`),
+			labels.EmptyLabels(),
+			labels.FromStrings(
+				"a_b", "1",
+				logqlmodel.ErrorLabel, errXML,
+				logqlmodel.ErrorDetailsLabel, "XML syntax error on line 1: unexpected EOF",
+			),
+		},
+		{
+			"trailing data",
+			[]byte(`<a>1</a>2`),
+			labels.EmptyLabels(),
+			labels.FromStrings(
+				"a", "1",
+				logqlmodel.ErrorLabel, errXML,
+				logqlmodel.ErrorDetailsLabel, errXMLTrailingData.Error(),
+			),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			b := NewBaseLabelsBuilder().ForLabels(tt.lbs, tt.lbs.Hash())
+			b.Reset()
+			_, _ = NewXMLParser().Process(0, tt.line, b)
+			require.Equal(t, tt.want, b.LabelsResult().Labels())
+		})
+	}
+}
+
+func TestXMLParser_Hints(t *testing.T) {
+	hints := NewParserHint([]string{"Event_System_EventID", "Event_System_Level"}, nil, false, true, "", nil)
+	b := NewBaseLabelsBuilderWithGrouping(nil, hints, false, false).ForLabels(labels.EmptyLabels(), 0)
+	b.Reset()
+
+	_, ok := NewXMLParser().Process(0, windowsEvent, b)
+	require.True(t, ok)
+	require.Equal(t, labels.FromStrings("Event_System_EventID", "4624", "Event_System_Level", "0"), b.LabelsResult().Labels())
+}
+
+func TestXMLExpressionParser(t *testing.T) {
+	tests := []struct {
+		name        string
+		expressions []LabelExtractionExpr
+		line        []byte
+		want        labels.Labels
+	}{
+		{
+			"element text",
+			[]LabelExtractionExpr{NewLabelExtractionExpr("id", "/Event/System/EventID")},
+			windowsEvent,
+			labels.FromStrings("id", "4624"),
+		},
+		{
+			"attribute",
+			[]LabelExtractionExpr{NewLabelExtractionExpr("provider", "Event/System/Provider/@Name")},
+			windowsEvent,
+			labels.FromStrings("provider", "Microsoft-Windows-Security-Auditing"),
+		},
+		{
+			"attribute predicate",
+			[]LabelExtractionExpr{
+				NewLabelExtractionExpr("user", "/Event/EventData/Data[@Name='TargetUserName']"),
+				NewLabelExtractionExpr("logon", `/Event/EventData/Data[@Name="LogonType"]`),
+			},
+			windowsEvent,
+			labels.FromStrings("user", "bob", "logon", "3"),
+		},
+		{
+			"position predicate and wildcard",
+			[]LabelExtractionExpr{
+				NewLabelExtractionExpr("second", "/Event/*/Data[2]"),
+				NewLabelExtractionExpr("name", "/Event/EventData/Data[3]/@Name"),
+			},
+			windowsEvent,
+			labels.FromStrings("second", "bob", "name", "LogonType"),
+		},
+		{
+			"first match",
+			[]LabelExtractionExpr{NewLabelExtractionExpr("data", "/Event/EventData/Data")},
+			windowsEvent,
+			labels.FromStrings("data", "-"),
+		},
+		{
+			"missing",
+			[]LabelExtractionExpr{
+				NewLabelExtractionExpr("missing", "/Event/System/Missing"),
+				NewLabelExtractionExpr("attr", "/Event/System/EventID/@Missing"),
+				NewLabelExtractionExpr("root", "/Other/System/EventID"),
+			},
+			windowsEvent,
+			labels.FromStrings("missing", "", "attr", "", "root", ""),
+		},
+		{
+			"not xml",
+			[]LabelExtractionExpr{NewLabelExtractionExpr("id", "/Event/System/EventID")},
+			[]byte(`{"id": 1}`),
+			labels.FromStrings("id", "",
+				logqlmodel.ErrorLabel, errXML,
+				logqlmodel.ErrorDetailsLabel, errXMLNoElement.Error(),
+			),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			p, err := NewXMLExpressionParser(tt.expressions)
+			require.NoError(t, err)
+
+			b := NewBaseLabelsBuilder().ForLabels(labels.EmptyLabels(), 0)
+			b.Reset()
+			_, _ = p.Process(0, tt.line, b)
+			require.Equal(t, tt.want, b.LabelsResult().Labels())
+		})
+	}
+}
+
+func TestXMLExpressionParserFailures(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"//Event",
+		"/Event//System",
+		"/@Name",
+		"/Event/@",
+		"/Event[0]",
+		"/Event[a]",
+		"/Event[@Name]",
+		"/Event[@Name='foo'",
+		"/Event[1]foo",
+	} {
+		_, err := NewXMLExpressionParser([]LabelExtractionExpr{NewLabelExtractionExpr("foo", expr)})
+		require.Error(t, err, expr)
+	}
+
+	_, err := NewXMLExpressionParser([]LabelExtractionExpr{NewLabelExtractionExpr("foo-bar", "/Event")})
+	require.Error(t, err)
+}
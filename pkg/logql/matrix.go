@@ -18,21 +18,27 @@ import (
 type MatrixStepEvaluator struct {
 	start, end, ts time.Time
 	step           time.Duration
+	cal            *Calendar
 	m              promql.Matrix
 }
 
-func NewMatrixStepEvaluator(start, end time.Time, step time.Duration, m promql.Matrix) *MatrixStepEvaluator {
+func NewMatrixStepEvaluator(start, end time.Time, step time.Duration, cal *Calendar, m promql.Matrix) *MatrixStepEvaluator {
+	ts := start.Add(-step) // will be corrected on first Next() call
+	if cal != nil {
+		ts = start.Add(-time.Nanosecond)
+	}
 	return &MatrixStepEvaluator{
 		start: start,
 		end:   end,
-		ts:    start.Add(-step), // will be corrected on first Next() call
+		ts:    ts,
 		step:  step,
+		cal:   cal,
 		m:     m,
 	}
 }
 
 func (m *MatrixStepEvaluator) Next() (bool, int64, StepResult) {
-	m.ts = m.ts.Add(m.step)
+	m.ts = time.Unix(0, nextStep(m.ts.UnixNano(), m.step.Nanoseconds(), 0, m.cal))
 	if m.ts.After(m.end) {
 		return false, 0, nil
 	}
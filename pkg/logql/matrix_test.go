@@ -37,7 +37,7 @@ func TestMatrixStepper(t *testing.T) {
 		},
 	}
 
-	s := NewMatrixStepEvaluator(start, end, step, m)
+	s := NewMatrixStepEvaluator(start, end, step, nil, m)
 
 	expected := []promql.Vector{
 		{
@@ -115,7 +115,7 @@ func Test_SingleStepMatrix(t *testing.T) {
 		},
 	}
 
-	s := NewMatrixStepEvaluator(start, end, step, m)
+	s := NewMatrixStepEvaluator(start, end, step, nil, m)
 
 	ok, ts, vec := s.Next()
 	require.True(t, ok)
@@ -0,0 +1,110 @@
+package logql
+
+import (
+	"math"
+	"time"
+
+	"github.com/prometheus/prometheus/model/labels"
+
+	"github.com/grafana/loki/pkg/iter"
+	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/logql/log"
+	"github.com/grafana/loki/pkg/logql/syntax"
+	"github.com/grafana/loki/pkg/logqlmodel"
+)
+
+// mergeStagesIterator applies the merge stages of a log query, like distinct and dedup,
+// to the entries of an iterator merging the results of several queries.
+// The stages only filter entries, the labels and lines of the entries are unchanged.
+type mergeStagesIterator struct {
+	iter.EntryIterator
+	pipeline log.Pipeline
+	streams  map[string]log.StreamPipeline
+	err      error
+}
+
+// newMergeStagesIterator returns the iterator unchanged when there are no merge stages.
+// The start and end are the time range of the merged query.
+func newMergeStagesIterator(it iter.EntryIterator, stages []log.Stage, start, end time.Time) iter.EntryIterator {
+	return newMergeStagesPipelineIterator(it, newMergeStagesPipeline(stages, start, end))
+}
+
+// newMergeStagesPipeline returns nil when there are no merge stages.
+func newMergeStagesPipeline(stages []log.Stage, start, end time.Time) log.Pipeline {
+	if len(stages) == 0 {
+		return nil
+	}
+	pipeline := log.NewPipeline(stages)
+	log.SetQueryRange(pipeline, start, end)
+	return pipeline
+}
+
+// newMergeStagesPipelineIterator returns the iterator unchanged when the pipeline is nil.
+func newMergeStagesPipelineIterator(it iter.EntryIterator, pipeline log.Pipeline) iter.EntryIterator {
+	if pipeline == nil {
+		return it
+	}
+	return &mergeStagesIterator{
+		EntryIterator: it,
+		pipeline:      pipeline,
+		streams:       make(map[string]log.StreamPipeline),
+	}
+}
+
+func (it *mergeStagesIterator) Next() bool {
+	for it.EntryIterator.Next() {
+		sp, ok := it.streams[it.EntryIterator.Labels()]
+		if !ok {
+			var lbs labels.Labels
+			lbs, it.err = syntax.ParseLabels(it.EntryIterator.Labels())
+			if it.err != nil {
+				return false
+			}
+			sp = it.pipeline.ForStream(lbs)
+			it.streams[it.EntryIterator.Labels()] = sp
+		}
+		entry := it.EntryIterator.Entry()
+		if _, _, ok := sp.ProcessString(entry.Timestamp.UnixNano(), entry.Line); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (it *mergeStagesIterator) Error() error {
+	if it.err != nil {
+		return it.err
+	}
+	return it.EntryIterator.Error()
+}
+
+// NewMergeStagesIterator applies the merge stages of a log query to an iterator merging the streams
+// of the query in the order of the query, like in the ingesters and the store, which reduces the lines
+// to send back while the stages are applied again when merging the results of the sources.
+// The start and end are the time range of the query.
+func NewMergeStagesIterator(it iter.EntryIterator, expr syntax.LogSelectorExpr, start, end time.Time) (iter.EntryIterator, error) {
+	stages, err := syntax.MergeStages(expr)
+	if err != nil {
+		return nil, err
+	}
+	return newMergeStagesIterator(it, stages, start, end), nil
+}
+
+// NewMergeStagesPipeline returns the pipeline of the merge stages of a log query, like distinct and dedup,
+// or nil when the query has none. The start and end are the time range of the query.
+func NewMergeStagesPipeline(expr syntax.LogSelectorExpr, start, end time.Time) (log.Pipeline, error) {
+	stages, err := syntax.MergeStages(expr)
+	if err != nil {
+		return nil, err
+	}
+	return newMergeStagesPipeline(stages, start, end), nil
+}
+
+// ApplyMergeStages applies the pipeline of the merge stages of a log query to the streams of a result of the query.
+// The pipeline keeps the state of the stages, so the results of several queries applied one after the other in the
+// order of the query, e.g. the splits of a query, are filtered as if they were merged.
+func ApplyMergeStages(pipeline log.Pipeline, streams []logproto.Stream, direction logproto.Direction) (logqlmodel.Streams, error) {
+	it := newMergeStagesPipelineIterator(iter.NewStreamsIterator(streams, direction), pipeline)
+	defer it.Close()
+	return readStreams(it, math.MaxUint32, direction, 0)
+}
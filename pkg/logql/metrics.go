@@ -29,6 +29,7 @@ const (
 	QueryTypeLabels  = "labels"
 	QueryTypeSeries  = "series"
 	QueryTypeVolume  = "volume"
+	QueryTypeJoin    = "join"
 
 	latencyTypeSlow = "slow"
 	latencyTypeFast = "fast"
@@ -137,6 +138,7 @@ func RecordRangeAndInstantQueryMetrics(
 		"store_chunks_download_time", stats.ChunksDownloadTime(),
 		"queue_time", logql_stats.ConvertSecondsToNanoseconds(stats.Summary.QueueTime),
 		"splits", stats.Summary.Splits,
+		"split_interval", logql_stats.ConvertSecondsToNanoseconds(stats.Summary.SplitInterval),
 		"shards", stats.Summary.Shards,
 		"chunk_refs_fetch_time", stats.ChunkRefsFetchTime(),
 		"cache_chunk_req", stats.Caches.Chunk.EntriesRequested,
@@ -156,6 +158,8 @@ func RecordRangeAndInstantQueryMetrics(
 		"cache_result_req", stats.Caches.Result.EntriesRequested,
 		"cache_result_hit", stats.Caches.Result.EntriesFound,
 		"cache_result_download_time", stats.Caches.Result.CacheDownloadTime(),
+		"cache_log_result_req", stats.Caches.LogResult.EntriesRequested,
+		"cache_log_result_hit", stats.Caches.LogResult.EntriesFound,
 	}...)
 
 	logValues = append(logValues, tagsToKeyValues(queryTags)...)
@@ -356,6 +360,8 @@ func QueryType(query string) (string, error) {
 			return QueryTypeFilter, nil
 		}
 		return QueryTypeLimited, nil
+	case *syntax.JoinExpr:
+		return QueryTypeJoin, nil
 	default:
 		return "", nil
 	}
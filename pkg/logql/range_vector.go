@@ -42,7 +42,7 @@ type RangeVectorIterator interface {
 func newRangeVectorIterator(
 	it iter.PeekingSampleIterator,
 	expr *syntax.RangeAggregationExpr,
-	selRange, step, start, end, offset int64) (RangeVectorIterator, error) {
+	selRange, step, start, end, offset int64, cal *Calendar) (RangeVectorIterator, error) {
 	// forces at least one step.
 	if step == 0 {
 		step = 1
@@ -51,12 +51,17 @@ func newRangeVectorIterator(
 		start = start - offset
 		end = end - offset
 	}
+	// first loop iteration will set current to the first step.
+	current := start - step
+	if cal != nil {
+		current = start - 1
+	}
 	var overlap bool
 	if selRange >= step && start != end {
 		overlap = true
 	}
 	if !overlap {
-		_, err := streamingAggregator(expr)
+		_, err := streamingAggregator(expr, nil)
 		if err != nil {
 			return nil, err
 		}
@@ -67,25 +72,51 @@ func newRangeVectorIterator(
 			selRange: selRange,
 			metrics:  map[string]labels.Labels{},
 			r:        expr,
-			current:  start - step, // first loop iteration will set it to start
+			current:  current,
+			cal:      cal,
 			offset:   offset,
 		}, nil
 	}
-	vectorAggregator, err := aggregator(expr)
-	if err != nil {
-		return nil, err
-	}
-	return &batchRangeVectorIterator{
+	batch := &batchRangeVectorIterator{
 		iter:     it,
 		step:     step,
 		end:      end,
 		selRange: selRange,
 		metrics:  map[string]labels.Labels{},
 		window:   map[string]*promql.Series{},
-		agg:      vectorAggregator,
-		current:  start - step, // first loop iteration will set it to start
+		current:  current,
+		cal:      cal,
 		offset:   offset,
-	}, nil
+	}
+	vectorAggregator, err := aggregator(expr, &batch.bounds)
+	if err != nil {
+		return nil, err
+	}
+	batch.agg = vectorAggregator
+	return batch, nil
+}
+
+// rangeBounds are the start, not inclusive, and the end of the range of the current step, in the time of the samples.
+type rangeBounds struct {
+	start, end int64
+}
+
+// nextStep returns the step following current, both shifted by offset.
+// The steps aligned to a calendar are its boundaries in the time of the query.
+func nextStep(current, step, offset int64, cal *Calendar) int64 {
+	if cal == nil {
+		return current + step
+	}
+	return cal.Next(current+offset) - offset
+}
+
+// stepRangeStart returns the start, not inclusive, of the range of selRange ending at rangeEnd shifted by offset.
+// Aligned to a calendar, a range of the nominal duration of its steps covers the whole period ending at rangeEnd.
+func stepRangeStart(rangeEnd, selRange, offset int64, cal *Calendar) int64 {
+	if cal != nil && cal.Covers(time.Duration(selRange)) {
+		return cal.Prev(rangeEnd+offset) - offset
+	}
+	return rangeEnd - selRange
 }
 
 //batch
@@ -93,6 +124,8 @@ func newRangeVectorIterator(
 type batchRangeVectorIterator struct {
 	iter                                 iter.PeekingSampleIterator
 	selRange, step, end, current, offset int64
+	cal                                  *Calendar
+	bounds                               rangeBounds
 	window                               map[string]*promql.Series
 	metrics                              map[string]labels.Labels
 	at                                   []promql.Sample
@@ -101,12 +134,13 @@ type batchRangeVectorIterator struct {
 
 func (r *batchRangeVectorIterator) Next() bool {
 	// slides the range window to the next position
-	r.current = r.current + r.step
+	r.current = nextStep(r.current, r.step, r.offset, r.cal)
 	if r.current > r.end {
 		return false
 	}
 	rangeEnd := r.current
-	rangeStart := rangeEnd - r.selRange
+	rangeStart := stepRangeStart(rangeEnd, r.selRange, r.offset, r.cal)
+	r.bounds = rangeBounds{start: rangeStart, end: rangeEnd}
 	// load samples
 	r.popBack(rangeStart)
 	r.load(rangeStart, rangeEnd)
@@ -220,7 +254,9 @@ func putSeries(s *promql.Series) {
 	seriesPool.Put(s)
 }
 
-func aggregator(r *syntax.RangeAggregationExpr) (BatchRangeVectorAggregator, error) {
+// aggregator returns the aggregator of the range aggregation, the ones extrapolating their result to the bounds
+// of the range read them from bounds, which the iterator updates at each step.
+func aggregator(r *syntax.RangeAggregationExpr, bounds *rangeBounds) (BatchRangeVectorAggregator, error) {
 	switch r.Operation {
 	case syntax.OpRangeTypeRate:
 		return rateLogs(r.Left.Interval, r.Left.Unwrap != nil), nil
@@ -250,6 +286,12 @@ func aggregator(r *syntax.RangeAggregationExpr) (BatchRangeVectorAggregator, err
 		return last, nil
 	case syntax.OpRangeTypeAbsent:
 		return one, nil
+	case syntax.OpRangeTypeDelta:
+		return delta(bounds), nil
+	case syntax.OpRangeTypeDeriv:
+		return deriv, nil
+	case syntax.OpRangeTypePredictLinear:
+		return predictLinear(*r.Params), nil
 	default:
 		return nil, fmt.Errorf(syntax.UnsupportedErr, r.Operation)
 	}
@@ -504,10 +546,131 @@ func one(_ []promql.FPoint) float64 {
 	return 1.0
 }
 
+// delta calculates the difference between the first and last values
+// extracted from log lines, extrapolated to the boundaries of the range.
+func delta(bounds *rangeBounds) func(samples []promql.FPoint) float64 {
+	return func(samples []promql.FPoint) float64 {
+		return extrapolatedDelta(samples, bounds.start, bounds.end)
+	}
+}
+
+// extrapolatedDelta is the non-counter, non-rate case of extrapolatedRate from prometheus code promql/functions.go,
+// with the timestamps of the samples and the bounds of the range in nanoseconds. Like in PromQL, the difference
+// between the first and the last samples is extrapolated to the bounds of the range if the samples are close to
+// them, or by half the average duration between the samples otherwise.
+func extrapolatedDelta(samples []promql.FPoint, rangeStart, rangeEnd int64) float64 {
+	// No sense in trying to compute a delta without at least two points.
+	if len(samples) < 2 {
+		return 0
+	}
+	firstSample, lastSample := samples[0], samples[len(samples)-1]
+	resultValue := lastSample.F - firstSample.F
+
+	sampledInterval := float64(lastSample.T-firstSample.T) / 1e9
+	if sampledInterval == 0 {
+		return resultValue
+	}
+	averageDurationBetweenSamples := sampledInterval / float64(len(samples)-1)
+	extrapolationThreshold := averageDurationBetweenSamples * 1.1
+
+	// Duration between first/last samples and boundary of range.
+	durationToStart := float64(firstSample.T-rangeStart) / 1e9
+	durationToEnd := float64(rangeEnd-lastSample.T) / 1e9
+	if durationToStart >= extrapolationThreshold {
+		durationToStart = averageDurationBetweenSamples / 2
+	}
+	if durationToEnd >= extrapolationThreshold {
+		durationToEnd = averageDurationBetweenSamples / 2
+	}
+	return resultValue * ((sampledInterval + durationToStart + durationToEnd) / sampledInterval)
+}
+
+// deriv calculates the per-second derivative of the values extracted from
+// log lines, using a simple linear regression.
+func deriv(samples []promql.FPoint) float64 {
+	// No sense in trying to compute a derivative without at least two points.
+	if len(samples) < 2 {
+		return 0
+	}
+	slope, _ := linearRegression(samples, samples[0].T)
+	return slope
+}
+
+// predictLinear predicts the value extracted from log lines t seconds after
+// the last sample of the range, using a simple linear regression.
+func predictLinear(t float64) func(samples []promql.FPoint) float64 {
+	return func(samples []promql.FPoint) float64 {
+		if len(samples) < 2 {
+			return 0
+		}
+		slope, intercept := linearRegression(samples, samples[len(samples)-1].T)
+		return slope*t + intercept
+	}
+}
+
+// linearRegression function is taken from prometheus code promql/functions.go
+// It returns the slope per second and the intercept at interceptTime of the
+// least squares fit of the samples, whose timestamps are in nanoseconds.
+func linearRegression(samples []promql.FPoint, interceptTime int64) (slope, intercept float64) {
+	var (
+		n          float64
+		sumX, cX   float64
+		sumY, cY   float64
+		sumXY, cXY float64
+		sumX2, cX2 float64
+		initY      float64
+		constY     bool
+	)
+	initY = samples[0].F
+	constY = true
+	for i, sample := range samples {
+		// Set constY to false if any new y values are encountered.
+		if constY && i > 0 && sample.F != initY {
+			constY = false
+		}
+		n += 1.0
+		x := float64(sample.T-interceptTime) / 1e9
+		sumX, cX = kahanSumInc(x, sumX, cX)
+		sumY, cY = kahanSumInc(sample.F, sumY, cY)
+		sumXY, cXY = kahanSumInc(x*sample.F, sumXY, cXY)
+		sumX2, cX2 = kahanSumInc(x*x, sumX2, cX2)
+	}
+	if constY {
+		if math.IsInf(initY, 0) {
+			return math.NaN(), math.NaN()
+		}
+		return 0, initY
+	}
+	sumX += cX
+	sumY += cY
+	sumXY += cXY
+	sumX2 += cX2
+
+	covXY := sumXY - sumX*sumY/n
+	varX := sumX2 - sumX*sumX/n
+
+	slope = covXY / varX
+	intercept = sumY/n - slope*sumX/n
+	return slope, intercept
+}
+
+func kahanSumInc(inc, sum, c float64) (newSum, newC float64) {
+	t := sum + inc
+	// Using Neumaier improvement, swap if next term larger than sum.
+	if math.Abs(sum) >= math.Abs(inc) {
+		c += (sum - t) + inc
+	} else {
+		c += (inc - t) + sum
+	}
+	return t, c
+}
+
 // streaming range agg
 type streamRangeVectorIterator struct {
 	iter                                 iter.PeekingSampleIterator
 	selRange, step, end, current, offset int64
+	cal                                  *Calendar
+	bounds                               rangeBounds
 	windowRangeAgg                       map[string]RangeStreamingAgg
 	r                                    *syntax.RangeAggregationExpr
 	metrics                              map[string]labels.Labels
@@ -517,12 +680,13 @@ type streamRangeVectorIterator struct {
 
 func (r *streamRangeVectorIterator) Next() bool {
 	// slides the range window to the next position
-	r.current = r.current + r.step
+	r.current = nextStep(r.current, r.step, r.offset, r.cal)
 	if r.current > r.end {
 		return false
 	}
 	rangeEnd := r.current
-	rangeStart := rangeEnd - r.selRange
+	rangeStart := stepRangeStart(rangeEnd, r.selRange, r.offset, r.cal)
+	r.bounds = rangeBounds{start: rangeStart, end: rangeEnd}
 	// load samples
 
 	r.windowRangeAgg = make(map[string]RangeStreamingAgg, 0)
@@ -568,7 +732,7 @@ func (r *streamRangeVectorIterator) load(start, end int64) {
 			}
 
 			// never err here ,we have check error at evaluator.go rangeAggEvaluator() func
-			rangeAgg, _ = streamingAggregator(r.r)
+			rangeAgg, _ = streamingAggregator(r.r, &r.bounds)
 			r.windowRangeAgg[lbs] = rangeAgg
 		}
 		p := promql.FPoint{
@@ -597,7 +761,8 @@ func (r *streamRangeVectorIterator) At() (int64, StepResult) {
 	return ts, SampleVector(r.at)
 }
 
-func streamingAggregator(r *syntax.RangeAggregationExpr) (RangeStreamingAgg, error) {
+// streamingAggregator returns the streaming aggregator of the range aggregation, see aggregator for bounds.
+func streamingAggregator(r *syntax.RangeAggregationExpr, bounds *rangeBounds) (RangeStreamingAgg, error) {
 	switch r.Operation {
 	case syntax.OpRangeTypeRate:
 		return newRateLogs(r.Left.Interval, r.Left.Unwrap != nil), nil
@@ -627,6 +792,12 @@ func streamingAggregator(r *syntax.RangeAggregationExpr) (RangeStreamingAgg, err
 		return &LastOverTime{}, nil
 	case syntax.OpRangeTypeAbsent:
 		return &OneOverTime{}, nil
+	case syntax.OpRangeTypeDelta:
+		return &DeltaOverTime{bounds: bounds, samples: make([]promql.FPoint, 0)}, nil
+	case syntax.OpRangeTypeDeriv:
+		return &DerivOverTime{samples: make([]promql.FPoint, 0)}, nil
+	case syntax.OpRangeTypePredictLinear:
+		return &PredictLinearOverTime{duration: *r.Params, samples: make([]promql.FPoint, 0)}, nil
 	default:
 		return nil, fmt.Errorf(syntax.UnsupportedErr, r.Operation)
 	}
@@ -851,3 +1022,41 @@ func (a *OneOverTime) agg(_ promql.FPoint) {
 func (a *OneOverTime) at() float64 {
 	return 1.0
 }
+
+type DeltaOverTime struct {
+	samples []promql.FPoint
+	bounds  *rangeBounds
+}
+
+func (a *DeltaOverTime) agg(sample promql.FPoint) {
+	a.samples = append(a.samples, sample)
+}
+
+func (a *DeltaOverTime) at() float64 {
+	return extrapolatedDelta(a.samples, a.bounds.start, a.bounds.end)
+}
+
+type DerivOverTime struct {
+	samples []promql.FPoint
+}
+
+func (a *DerivOverTime) agg(sample promql.FPoint) {
+	a.samples = append(a.samples, sample)
+}
+
+func (a *DerivOverTime) at() float64 {
+	return deriv(a.samples)
+}
+
+type PredictLinearOverTime struct {
+	samples  []promql.FPoint
+	duration float64
+}
+
+func (a *PredictLinearOverTime) agg(sample promql.FPoint) {
+	a.samples = append(a.samples, sample)
+}
+
+func (a *PredictLinearOverTime) at() float64 {
+	return predictLinear(a.duration)(a.samples)
+}
@@ -136,7 +136,7 @@ func Benchmark_RangeVectorIteratorCompare(b *testing.B) {
 			end = end - offset
 		}
 
-		vectorAggregator, err := aggregator(expr)
+		vectorAggregator, err := aggregator(expr, nil)
 		if err != nil {
 			return nil, err
 		}
@@ -201,7 +201,7 @@ func Benchmark_RangeVectorIterator(b *testing.B) {
 		i := 0
 		it, err := newRangeVectorIterator(newfakePeekingSampleIterator(samples),
 			&syntax.RangeAggregationExpr{Operation: syntax.OpRangeTypeCount}, tt.selRange,
-			tt.step, tt.start.UnixNano(), tt.end.UnixNano(), tt.offset)
+			tt.step, tt.start.UnixNano(), tt.end.UnixNano(), tt.offset, nil)
 		if err != nil {
 			panic(err)
 		}
@@ -338,7 +338,7 @@ func Test_RangeVectorIterator(t *testing.T) {
 			func(t *testing.T) {
 				it, err := newRangeVectorIterator(newfakePeekingSampleIterator(samples),
 					&syntax.RangeAggregationExpr{Operation: syntax.OpRangeTypeCount}, tt.selRange,
-					tt.step, tt.start.UnixNano(), tt.end.UnixNano(), tt.offset)
+					tt.step, tt.start.UnixNano(), tt.end.UnixNano(), tt.offset, nil)
 				require.NoError(t, err)
 
 				i := 0
@@ -362,7 +362,7 @@ func Test_RangeVectorIteratorBadLabels(t *testing.T) {
 		}))
 	it, err := newRangeVectorIterator(badIterator,
 		&syntax.RangeAggregationExpr{Operation: syntax.OpRangeTypeCount}, (30 * time.Second).Nanoseconds(),
-		(30 * time.Second).Nanoseconds(), time.Unix(10, 0).UnixNano(), time.Unix(100, 0).UnixNano(), 0)
+		(30 * time.Second).Nanoseconds(), time.Unix(10, 0).UnixNano(), time.Unix(100, 0).UnixNano(), 0, nil)
 	require.NoError(t, err)
 
 	ctx, cancel := context.WithCancel(context.Background())
@@ -400,6 +400,9 @@ func Test_InstantQueryRangeVectorAggregations(t *testing.T) {
 		{"first", 1., syntax.OpRangeTypeFirst, false},
 		{"last", 3., syntax.OpRangeTypeLast, false},
 		{"absent", 1., syntax.OpRangeTypeAbsent, false},
+		{"delta", 3., syntax.OpRangeTypeDelta, false},
+		{"deriv", 1.0000000000000001e+09, syntax.OpRangeTypeDeriv, false},
+		{"predict linear", 9.900000030000006e+08, syntax.OpRangeTypePredictLinear, false},
 	}
 
 	var start, end int64 = 4, 4 // Instant query
@@ -407,7 +410,7 @@ func Test_InstantQueryRangeVectorAggregations(t *testing.T) {
 		t.Run(fmt.Sprintf("testing aggregation %s", tt.name), func(t *testing.T) {
 			it, err := newRangeVectorIterator(sampleIter(tt.negative),
 				&syntax.RangeAggregationExpr{Left: &syntax.LogRange{Interval: 2}, Params: proto.Float64(0.99), Operation: tt.op},
-				3, 1, start, end, 0)
+				3, 1, start, end, 0, nil)
 			require.NoError(t, err)
 
 			for it.Next() {
@@ -418,6 +421,50 @@ func Test_InstantQueryRangeVectorAggregations(t *testing.T) {
 	}
 }
 
+func Test_RangeVectorIteratorDelta(t *testing.T) {
+	deltaSamples := []logproto.Sample{
+		{Timestamp: time.Unix(12, 0).UnixNano(), Hash: 1, Value: 1.},
+		{Timestamp: time.Unix(22, 0).UnixNano(), Hash: 2, Value: 2.},
+		{Timestamp: time.Unix(32, 0).UnixNano(), Hash: 3, Value: 3.},
+	}
+	selRange := (50 * time.Second).Nanoseconds()
+	// The samples are 10s apart, the delta of 2 is extrapolated by the duration to a bound of the range when it
+	// is less than 11s, and by half the average duration between the samples, 5s, otherwise.
+	expected := map[int64]float64{
+		time.Unix(35, 0).UnixNano() / 1e+6: 2. * (20. + 5. + 3.) / 20.,
+		time.Unix(60, 0).UnixNano() / 1e+6: 2. * (20. + 2. + 5.) / 20.,
+	}
+
+	for _, tt := range []struct {
+		name       string
+		start, end int64
+		step       int64
+	}{
+		{"range query", time.Unix(35, 0).UnixNano(), time.Unix(60, 0).UnixNano(), (25 * time.Second).Nanoseconds()},
+		{"instant query at 35s", time.Unix(35, 0).UnixNano(), time.Unix(35, 0).UnixNano(), 0},
+		{"instant query at 60s", time.Unix(60, 0).UnixNano(), time.Unix(60, 0).UnixNano(), 0},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			it, err := newRangeVectorIterator(newfakePeekingSampleIterator(deltaSamples),
+				&syntax.RangeAggregationExpr{Operation: syntax.OpRangeTypeDelta}, selRange,
+				tt.step, tt.start, tt.end, 0, nil)
+			require.NoError(t, err)
+
+			steps := 0
+			for it.Next() {
+				ts, v := it.At()
+				vec := v.SampleVector()
+				require.Len(t, vec, 2)
+				for _, s := range vec {
+					require.InDelta(t, expected[ts], s.F, 1e-9)
+				}
+				steps++
+			}
+			require.Equal(t, int((tt.end-tt.start)/(25*time.Second).Nanoseconds())+1, steps)
+		})
+	}
+}
+
 func sampleIter(negative bool) iter.PeekingSampleIterator {
 	return iter.NewPeekingSampleIterator(
 		iter.NewSortSampleIterator([]iter.SampleIterator{
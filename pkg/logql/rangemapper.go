@@ -161,10 +161,34 @@ func (m RangeMapper) Map(expr syntax.SampleExpr, vectorAggrPushdown *syntax.Vect
 		}
 		e.Left = lhsMapped
 		return e, nil
+	case *syntax.LabelJoinExpr:
+		// the outer vector aggregation is not pushed down, as it must apply
+		// to the labels returned by the function.
+		lhsMapped, err := m.Map(e.Left, nil, recorder)
+		if err != nil {
+			return nil, err
+		}
+		e.Left = lhsMapped
+		return e, nil
+	case *syntax.FunctionExpr:
+		// the outer vector aggregation is not pushed down, as it must apply
+		// to the values returned by the function.
+		lhsMapped, err := m.Map(e.Left, nil, recorder)
+		if err != nil {
+			return nil, err
+		}
+		e.Left = lhsMapped
+		return e, nil
+	case *syntax.SubqueryAggregationExpr:
+		// subqueries are not split, as the subquery expression is evaluated
+		// at its own resolution over the range of the subquery.
+		return e, nil
 	case *syntax.LiteralExpr:
 		return e, nil
 	case *syntax.VectorExpr:
 		return e, nil
+	case *syntax.TimeExpr:
+		return e, nil
 	default:
 		// ConcatSampleExpr and DownstreamSampleExpr are not supported input expression types
 		return nil, errors.Errorf("unexpected expr type (%T) for ASTMapper type (%T) ", expr, m)
@@ -187,17 +211,17 @@ func getRangeInterval(expr syntax.SampleExpr) time.Duration {
 }
 
 // hasLabelExtractionStage returns true if an expression contains a stage for label extraction,
-// such as `| json` or `| logfmt`, that would result in an exploding amount of series in downstream queries.
+// such as `| json`, `| logfmt`, `| xml`, `| kv` or `| unnest`, that would result in an exploding amount of series in downstream queries.
 func hasLabelExtractionStage(expr syntax.SampleExpr) bool {
 	found := false
 	expr.Walk(func(e interface{}) {
 		switch concrete := e.(type) {
-		case *syntax.LogfmtParserExpr:
+		case *syntax.LogfmtParserExpr, *syntax.KVParserExpr, *syntax.UnnestExpr:
 			found = true
 		case *syntax.LabelParserExpr:
 			// It will **not** return true for `regexp`, `unpack` and `pattern`, since these label extraction
 			// stages can control how many labels, and therefore the resulting amount of series, are extracted.
-			if concrete.Op == syntax.OpParserTypeJSON {
+			if concrete.Op == syntax.OpParserTypeJSON || concrete.Op == syntax.OpParserTypeXML {
 				found = true
 			}
 		}
@@ -485,7 +509,11 @@ func isSplittableByRange(expr syntax.SampleExpr) bool {
 		return isSplittableByRange(e.SampleExpr) || literalLHS && isSplittableByRange(e.RHS) || literalRHS
 	case *syntax.LabelReplaceExpr:
 		return isSplittableByRange(e.Left)
-	case *syntax.VectorExpr:
+	case *syntax.LabelJoinExpr:
+		return isSplittableByRange(e.Left)
+	case *syntax.FunctionExpr:
+		return isSplittableByRange(e.Left)
+	case *syntax.VectorExpr, *syntax.TimeExpr, *syntax.SubqueryAggregationExpr:
 		return false
 	default:
 		return false
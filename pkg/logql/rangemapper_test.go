@@ -1732,6 +1732,35 @@ func Test_SplitRangeVectorMapping(t *testing.T) {
 			)`,
 			3,
 		},
+
+		// functions
+		{
+			`abs(sum by (baz) (count_over_time({app="foo"}[3m])))`,
+			`abs(
+				sum by (baz) (
+					sum without () (
+						downstream<sum by (baz) (count_over_time({app="foo"} [1m] offset 2m0s)), shard=<nil>>
+						++ downstream<sum by (baz) (count_over_time({app="foo"} [1m] offset 1m0s)), shard=<nil>>
+						++ downstream<sum by (baz) (count_over_time({app="foo"} [1m])), shard=<nil>>
+					)
+				)
+			)`,
+			3,
+		},
+		{
+			`label_join(sum by (baz) (count_over_time({app="foo"}[3m])), "x", "-", "a", "b")`,
+			`label_join(
+				sum by (baz) (
+					sum without () (
+						downstream<sum by (baz) (count_over_time({app="foo"} [1m] offset 2m0s)), shard=<nil>>
+						++ downstream<sum by (baz) (count_over_time({app="foo"} [1m] offset 1m0s)), shard=<nil>>
+						++ downstream<sum by (baz) (count_over_time({app="foo"} [1m])), shard=<nil>>
+					)
+				),
+				"x", "-", "a", "b"
+			)`,
+			3,
+		},
 	} {
 		tc := tc
 		t.Run(tc.expr, func(t *testing.T) {
@@ -1909,6 +1938,16 @@ func Test_SplitRangeVectorMapping_Noop(t *testing.T) {
 			`(sum(last_over_time({app="foo"} | logfmt | unwrap total_count [1d]) by (foo)) or vector(0.000000))`,
 		},
 
+		// should be noop if subquery, even with a splittable subquery expression
+		{
+			`max_over_time(sum by (foo) (count_over_time({app="foo"}[3m]))[1h:5m])`,
+			`max_over_time(sum by (foo) (count_over_time({app="foo"}[3m]))[1h:5m])`,
+		},
+		{
+			`sum by (foo) (count_over_time({app="foo"}[3m])) / max_over_time(sum by (foo) (count_over_time({app="foo"}[3m]))[1h:])`,
+			`(sum by (foo) (count_over_time({app="foo"}[3m])) / max_over_time(sum by (foo) (count_over_time({app="foo"}[3m]))[1h:]))`,
+		},
+
 		// should be noop if literal expression
 		{
 			`5`,
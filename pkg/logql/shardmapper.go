@@ -81,14 +81,22 @@ func (m ShardMapper) Map(expr syntax.Expr, r *downstreamRecorder) (syntax.Expr,
 		return e, 0, nil
 	case *syntax.VectorExpr:
 		return e, 0, nil
+	case *syntax.TimeExpr:
+		return e, 0, nil
 	case *syntax.MatchersExpr, *syntax.PipelineExpr:
 		return m.mapLogSelectorExpr(e.(syntax.LogSelectorExpr), r)
 	case *syntax.VectorAggregationExpr:
 		return m.mapVectorAggregationExpr(e, r)
 	case *syntax.LabelReplaceExpr:
 		return m.mapLabelReplaceExpr(e, r)
+	case *syntax.LabelJoinExpr:
+		return m.mapLabelJoinExpr(e, r)
+	case *syntax.FunctionExpr:
+		return m.mapFunctionExpr(e, r)
 	case *syntax.RangeAggregationExpr:
 		return m.mapRangeAggregationExpr(e, r)
+	case *syntax.SubqueryAggregationExpr:
+		return m.mapSubqueryAggregationExpr(e, r)
 	case *syntax.BinOpExpr:
 		lhsMapped, lhsBytesPerShard, err := m.Map(e.SampleExpr, r)
 		if err != nil {
@@ -206,7 +214,7 @@ func (m ShardMapper) mapVectorAggregationExpr(expr *syntax.VectorAggregationExpr
 			// sum(x) -> sum(sum(x, shard=1) ++ sum(x, shard=2)...)
 			return m.wrappedShardedVectorAggr(expr, r)
 
-		case syntax.OpTypeMin, syntax.OpTypeMax:
+		case syntax.OpTypeMin, syntax.OpTypeMax, syntax.OpTypeGroup:
 			if syntax.ReducesLabels(expr.Left) {
 				// skip sharding optimizations at this level. If labels are reduced,
 				// the same series may exist on multiple shards and must be aggregated
@@ -215,6 +223,7 @@ func (m ShardMapper) mapVectorAggregationExpr(expr *syntax.VectorAggregationExpr
 			}
 			// max(x) -> max(max(x, shard=1) ++ max(x, shard=2)...)
 			// min(x) -> min(min(x, shard=1) ++ min(x, shard=2)...)
+			// group(x) -> group(group(x, shard=1) ++ group(x, shard=2)...)
 			return m.wrappedShardedVectorAggr(expr, r)
 
 		case syntax.OpTypeAvg:
@@ -297,6 +306,8 @@ func (m ShardMapper) mapVectorAggregationExpr(expr *syntax.VectorAggregationExpr
 		Grouping:  expr.Grouping,
 		Params:    expr.Params,
 		Operation: expr.Operation,
+		Quantile:  expr.Quantile,
+		Label:     expr.Label,
 	}, bytesPerShard, nil
 
 }
@@ -311,6 +322,44 @@ func (m ShardMapper) mapLabelReplaceExpr(expr *syntax.LabelReplaceExpr, r *downs
 	return &cpy, bytesPerShard, nil
 }
 
+func (m ShardMapper) mapLabelJoinExpr(expr *syntax.LabelJoinExpr, r *downstreamRecorder) (syntax.SampleExpr, uint64, error) {
+	subMapped, bytesPerShard, err := m.Map(expr.Left, r)
+	if err != nil {
+		return nil, 0, err
+	}
+	cpy := *expr
+	cpy.Left = subMapped.(syntax.SampleExpr)
+	return &cpy, bytesPerShard, nil
+}
+
+// mapFunctionExpr shards the inner expression of a function. Functions applied to
+// each sample independently could also be pushed down to the shards, but this is
+// equivalent to applying them on the merged shards of their inner expression.
+func (m ShardMapper) mapFunctionExpr(expr *syntax.FunctionExpr, r *downstreamRecorder) (syntax.SampleExpr, uint64, error) {
+	subMapped, bytesPerShard, err := m.Map(expr.Left, r)
+	if err != nil {
+		return nil, 0, err
+	}
+	cpy := *expr
+	cpy.Left = subMapped.(syntax.SampleExpr)
+	return &cpy, bytesPerShard, nil
+}
+
+// mapSubqueryAggregationExpr shards the subquery expression, which is evaluated
+// at the resolution of the subquery, whereas the aggregation over time of the
+// subquery samples happens after merging the shards.
+func (m ShardMapper) mapSubqueryAggregationExpr(expr *syntax.SubqueryAggregationExpr, r *downstreamRecorder) (syntax.SampleExpr, uint64, error) {
+	subMapped, bytesPerShard, err := m.Map(expr.Left.Left, r)
+	if err != nil {
+		return nil, 0, err
+	}
+	subquery := *expr.Left
+	subquery.Left = subMapped.(syntax.SampleExpr)
+	cpy := *expr
+	cpy.Left = &subquery
+	return &cpy, bytesPerShard, nil
+}
+
 // These functions require a different merge strategy than the default
 // concatenation.
 // This is because the same label sets may exist on multiple shards when label-reducing parsing is applied or when
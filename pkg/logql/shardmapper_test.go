@@ -135,6 +135,13 @@ func TestMappingStrings(t *testing.T) {
 				++ downstream<sum(rate({foo="bar"}[1m])), shard=1_of_2>
 			)`,
 		},
+		{
+			in: `max_over_time(sum(rate({foo="bar"}[1m]))[1h:5m])`,
+			out: `max_over_time(sum(
+				downstream<sum(rate({foo="bar"}[1m])), shard=0_of_2>
+				++ downstream<sum(rate({foo="bar"}[1m])), shard=1_of_2>
+			)[1h:5m])`,
+		},
 		{
 			in: `max(count(rate({foo="bar"}[5m]))) / 2`,
 			out: `(max(
@@ -404,6 +411,37 @@ func TestMappingStrings(t *testing.T) {
 			in:  `count by (foo) (sum by (foo, bar) (rate({job="bar"}[1m])))`,
 			out: `countby(foo)(sumby(foo,bar)(downstream<sumby(foo,bar)(rate({job="bar"}[1m])),shard=0_of_2>++downstream<sumby(foo,bar)(rate({job="bar"}[1m])),shard=1_of_2>))`,
 		},
+		{
+			in:  `group by (foo) (rate({job="bar"}[1m]))`,
+			out: `groupby(foo)(downstream<groupby(foo)(rate({job="bar"}[1m])),shard=0_of_2>++downstream<groupby(foo)(rate({job="bar"}[1m])),shard=1_of_2>)`,
+		},
+		{
+			in:  `abs(rate({job="bar"}[1m]))`,
+			out: `abs(downstream<rate({job="bar"}[1m]),shard=0_of_2>++downstream<rate({job="bar"}[1m]),shard=1_of_2>)`,
+		},
+		{
+			in:  `sum by (foo) (abs(rate({job="bar"}[1m])))`,
+			out: `sumby(foo)(downstream<sumby(foo)(abs(rate({job="bar"}[1m]))),shard=0_of_2>++downstream<sumby(foo)(abs(rate({job="bar"}[1m]))),shard=1_of_2>)`,
+		},
+		{
+			// histogram_quantile needs all the buckets of a histogram
+			in:  `histogram_quantile(0.9, sum by (le) (rate({job="bar"}[1m])))`,
+			out: `histogram_quantile(0.9,sumby(le)(downstream<sumby(le)(rate({job="bar"}[1m])),shard=0_of_2>++downstream<sumby(le)(rate({job="bar"}[1m])),shard=1_of_2>))`,
+		},
+		{
+			in:  `label_join(rate({job="bar"}[1m]), "foo", "-", "bar")`,
+			out: `label_join(downstream<rate({job="bar"}[1m]),shard=0_of_2>++downstream<rate({job="bar"}[1m]),shard=1_of_2>,"foo","-","bar")`,
+		},
+		{
+			in:  `time()`,
+			out: `time()`,
+		},
+		{
+			// log queries with distinct are still sharded, the stage is applied again by the frontend
+			in: `{foo="bar"} | logfmt | distinct id`,
+			out: `downstream<{foo="bar"} | logfmt | distinct id, shard=0_of_2>
+					++ downstream<{foo="bar"} | logfmt | distinct id, shard=1_of_2>`,
+		},
 	} {
 		t.Run(tc.in, func(t *testing.T) {
 			ast, err := syntax.ParseExpr(tc.in)
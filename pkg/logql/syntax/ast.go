@@ -14,6 +14,7 @@ import (
 	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/model/labels"
 	"github.com/prometheus/prometheus/promql"
+	"golang.org/x/exp/slices"
 
 	"github.com/grafana/regexp/syntax"
 
@@ -115,10 +116,9 @@ func (m MultiStageExpr) reorderStages() []StageExpr {
 		switch f := s.(type) {
 		case *LineFilterExpr:
 			filters = append(filters, f)
-		case *LineFmtExpr:
-			// line_format modifies the contents of the line so any line filter
-			// originally after a line_format must still be after the same
-			// line_format.
+		case *LineFmtExpr, *UnnestExpr, *DecodeExpr, *JSONUnescapeExpr:
+			// line_format, unnest and decoding stages modify the contents of the line so any line filter
+			// originally after them must still be after the same stage.
 
 			rest = append(rest, f)
 
@@ -284,7 +284,7 @@ func (e *PipelineExpr) Pipeline() (log.Pipeline, error) {
 func (e *PipelineExpr) HasFilter() bool {
 	for _, p := range e.MultiStages {
 		switch p.(type) {
-		case *LineFilterExpr, *LabelFilterExpr:
+		case *LineFilterExpr, *LabelFilterExpr, *UnnestExpr:
 			return true
 		default:
 			continue
@@ -372,14 +372,19 @@ func (e *LineFilterExpr) String() string {
 		sb.WriteString("!=")
 	}
 	sb.WriteString(" ")
-	if e.Op == "" {
-		sb.WriteString(strconv.Quote(e.Match))
-		return sb.String()
+	for or := e; or != nil; or = or.Or {
+		if or != e {
+			sb.WriteString(" or ")
+		}
+		if or.Op == "" {
+			sb.WriteString(strconv.Quote(or.Match))
+			continue
+		}
+		sb.WriteString(or.Op)
+		sb.WriteString("(")
+		sb.WriteString(strconv.Quote(or.Match))
+		sb.WriteString(")")
 	}
-	sb.WriteString(e.Op)
-	sb.WriteString("(")
-	sb.WriteString(strconv.Quote(e.Match))
-	sb.WriteString(")")
 	return sb.String()
 }
 
@@ -536,6 +541,8 @@ func (e *LabelParserExpr) Stage() (log.Stage, error) {
 		return log.NewUnpackParser(), nil
 	case OpParserTypePattern:
 		return log.NewPatternParser(e.Param)
+	case OpParserTypeXML:
+		return log.NewXMLParser(), nil
 	default:
 		return nil, fmt.Errorf("unknown parser operator: %s", e.Op)
 	}
@@ -692,6 +699,253 @@ func (e *KeepLabelsExpr) String() string {
 
 func (e *KeepLabelsExpr) Walk(f WalkFn) { f(e) }
 
+// DistinctFilterExpr keeps only the first line for each combination of values of labels.
+type DistinctFilterExpr struct {
+	labels []string
+	implicit
+}
+
+func newDistinctFilterExpr(labels []string) *DistinctFilterExpr {
+	return &DistinctFilterExpr{labels: labels}
+}
+
+// Shardable returns false as the lines of a combination can be spread across shards.
+// Log queries are still sharded, the stage is applied again when merging the results of the shards.
+func (e *DistinctFilterExpr) Shardable() bool { return false }
+
+// Stage returns a noop stage as the first lines of a combination are those in the order of the query,
+// which is only the case where the streams are merged, see MergeStages.
+func (e *DistinctFilterExpr) Stage() (log.Stage, error) {
+	return log.NoopStage, nil
+}
+
+func (e *DistinctFilterExpr) String() string {
+	return fmt.Sprintf("%s %s %s", OpPipe, OpDistinct, strings.Join(e.labels, ","))
+}
+
+func (e *DistinctFilterExpr) Walk(f WalkFn) { f(e) }
+
+// DedupExpr drops lines identical to a line of another stream within a time tolerance.
+type DedupExpr struct {
+	// Tolerance is the time tolerance, zero means log.DefaultDedupTolerance.
+	Tolerance time.Duration
+	implicit
+}
+
+func newDedupExpr(tolerance time.Duration) *DedupExpr {
+	return &DedupExpr{Tolerance: tolerance}
+}
+
+// Shardable returns false as identical lines can be spread across shards.
+// Log queries are still sharded, the stage is applied again when merging the results of the shards.
+func (e *DedupExpr) Shardable() bool { return false }
+
+// Stage returns a noop stage as identical lines are only seen together where the streams are merged,
+// see MergeStages.
+func (e *DedupExpr) Stage() (log.Stage, error) {
+	return log.NoopStage, nil
+}
+
+func (e *DedupExpr) String() string {
+	if e.Tolerance == 0 {
+		return fmt.Sprintf("%s %s", OpPipe, OpDedup)
+	}
+	return fmt.Sprintf("%s %s %s", OpPipe, OpDedup, model.Duration(e.Tolerance))
+}
+
+func (e *DedupExpr) Walk(f WalkFn) { f(e) }
+
+// LimitByExpr keeps at most a number of lines for each combination of values of labels,
+// optionally sampled across the time range of the query.
+type LimitByExpr struct {
+	Limit  int
+	Labels []string
+	Sample bool
+	implicit
+}
+
+func newLimitByExpr(limit string, labels []string, mode string) *LimitByExpr {
+	n, err := strconv.Atoi(limit)
+	if err != nil || n <= 0 {
+		panic(logqlmodel.NewParseError(fmt.Sprintf("invalid limit %s, it must be a positive integer", limit), 0, 0))
+	}
+	if mode != "" && mode != OpLimitSample {
+		panic(logqlmodel.NewParseError(fmt.Sprintf("unexpected %s after limit, only %s is supported", mode, OpLimitSample), 0, 0))
+	}
+	return &LimitByExpr{Limit: n, Labels: labels, Sample: mode == OpLimitSample}
+}
+
+// Shardable returns false as the lines of a combination can be spread across shards.
+// Log queries are still sharded, the stage is applied again when merging the results of the shards.
+func (e *LimitByExpr) Shardable() bool { return false }
+
+// Stage returns a noop stage as the lines of a combination must be limited in the order of the query,
+// which is only the case where the streams are merged, see MergeStages.
+func (e *LimitByExpr) Stage() (log.Stage, error) {
+	return log.NoopStage, nil
+}
+
+func (e *LimitByExpr) String() string {
+	s := fmt.Sprintf("%s %s %d by (%s)", OpPipe, OpLimit, e.Limit, strings.Join(e.Labels, ","))
+	if e.Sample {
+		s += " " + OpLimitSample
+	}
+	return s
+}
+
+func (e *LimitByExpr) Walk(f WalkFn) { f(e) }
+
+// MergeStages returns the stages of a log query that need to be applied again
+// when merging the results of several queries, i.e. the distinct, dedup and limit stages.
+// It returns no stage when the query doesn't have any.
+func MergeStages(expr LogSelectorExpr) ([]log.Stage, error) {
+	var (
+		stages []log.Stage
+		err    error
+	)
+	expr.Walk(func(e interface{}) {
+		if err != nil {
+			return
+		}
+		switch e := e.(type) {
+		case *DistinctFilterExpr:
+			stages = append(stages, log.NewDistinctFilter(e.labels))
+		case *DedupExpr:
+			stages = append(stages, log.NewDedupFilter(e.Tolerance))
+		case *LimitByExpr:
+			stages = append(stages, log.NewLimitByFilter(e.Limit, e.Labels, e.Sample))
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return stages, nil
+}
+
+// JoinExpr joins the log lines of two log queries having the same value for a label,
+// whose timestamps are within a time tolerance of each other.
+type JoinExpr struct {
+	Left, Right LogSelectorExpr
+	Label       string
+	Tolerance   time.Duration
+	implicit
+}
+
+func newJoinExpr(left, right LogSelectorExpr, label string, tolerance time.Duration) *JoinExpr {
+	if !model.LabelName(label).IsValid() {
+		panic(logqlmodel.NewParseError(fmt.Sprintf("invalid join label %s", strconv.Quote(label)), 0, 0))
+	}
+	if tolerance <= 0 {
+		panic(logqlmodel.NewParseError(fmt.Sprintf("invalid join tolerance %s, it must be positive", model.Duration(tolerance)), 0, 0))
+	}
+	return &JoinExpr{Left: left, Right: right, Label: label, Tolerance: tolerance}
+}
+
+// Shardable returns false as the lines of a key can be spread across shards.
+func (e *JoinExpr) Shardable() bool { return false }
+
+func (e *JoinExpr) String() string {
+	return fmt.Sprintf("%s(%s, %s, %s, %s)", OpJoin, joinSideString(e.Left), joinSideString(e.Right), strconv.Quote(e.Label), model.Duration(e.Tolerance))
+}
+
+// joinSideString wraps the pipelines in parentheses, since the last stage of a pipeline could be a list, e.g. `| distinct id, host`.
+func joinSideString(e LogSelectorExpr) string {
+	if _, ok := e.(*PipelineExpr); ok {
+		return "(" + e.String() + ")"
+	}
+	return e.String()
+}
+
+func (e *JoinExpr) Walk(f WalkFn) {
+	f(e)
+	walkAll(f, e.Left, e.Right)
+}
+
+// UnnestExpr fans out a json log line into one line per element of an array field.
+type UnnestExpr struct {
+	Field string
+	implicit
+}
+
+func newUnnestExpr(field string) *UnnestExpr {
+	return &UnnestExpr{Field: field}
+}
+
+func (e *UnnestExpr) Shardable() bool { return true }
+
+func (e *UnnestExpr) Stage() (log.Stage, error) {
+	return log.NewUnnester(e.Field)
+}
+
+func (e *UnnestExpr) String() string {
+	if model.LabelName(e.Field).IsValid() {
+		return fmt.Sprintf("%s %s %s", OpPipe, OpUnnest, e.Field)
+	}
+	return fmt.Sprintf("%s %s %s", OpPipe, OpUnnest, strconv.Quote(e.Field))
+}
+
+func (e *UnnestExpr) Walk(f WalkFn) { f(e) }
+
+// DecodeExpr decodes the log line, or the value of a label, with one or more encodings.
+type DecodeExpr struct {
+	Encodings []string
+	Label     string
+	implicit
+}
+
+func newDecodeExpr(encodings []string, label string) *DecodeExpr {
+	if _, err := log.NewDecoder(encodings, label); err != nil {
+		panic(logqlmodel.NewParseError(fmt.Sprintf("invalid decode stage: %s", err.Error()), 0, 0))
+	}
+	return &DecodeExpr{Encodings: encodings, Label: label}
+}
+
+func (e *DecodeExpr) Shardable() bool { return true }
+
+func (e *DecodeExpr) Stage() (log.Stage, error) {
+	return log.NewDecoder(e.Encodings, e.Label)
+}
+
+func (e *DecodeExpr) String() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%s %s %s", OpPipe, OpDecode, strings.Join(e.Encodings, OpTypeAdd)))
+	if e.Label != "" {
+		sb.WriteString(" ")
+		sb.WriteString(e.Label)
+	}
+	return sb.String()
+}
+
+func (e *DecodeExpr) Walk(f WalkFn) { f(e) }
+
+// JSONUnescapeExpr replaces a json log line with the value of one of its fields.
+type JSONUnescapeExpr struct {
+	Field string
+	implicit
+}
+
+func newJSONUnescapeExpr(field string) *JSONUnescapeExpr {
+	if _, err := log.NewJSONUnescaper(field); err != nil {
+		panic(logqlmodel.NewParseError(fmt.Sprintf("invalid json_unescape stage: %s", err.Error()), 0, 0))
+	}
+	return &JSONUnescapeExpr{Field: field}
+}
+
+func (e *JSONUnescapeExpr) Shardable() bool { return true }
+
+func (e *JSONUnescapeExpr) Stage() (log.Stage, error) {
+	return log.NewJSONUnescaper(e.Field)
+}
+
+func (e *JSONUnescapeExpr) String() string {
+	if model.LabelName(e.Field).IsValid() {
+		return fmt.Sprintf("%s %s %s", OpPipe, OpJSONUnescape, e.Field)
+	}
+	return fmt.Sprintf("%s %s %s", OpPipe, OpJSONUnescape, strconv.Quote(e.Field))
+}
+
+func (e *JSONUnescapeExpr) Walk(f WalkFn) { f(e) }
+
 func (e *LineFmtExpr) Shardable() bool { return true }
 
 func (e *LineFmtExpr) Walk(f WalkFn) { f(e) }
@@ -844,6 +1098,151 @@ func (l *LogfmtExpressionParser) String() string {
 	return sb.String()
 }
 
+type XMLExpressionParser struct {
+	Expressions []log.LabelExtractionExpr
+
+	implicit
+}
+
+func newXMLExpressionParser(expressions []log.LabelExtractionExpr) *XMLExpressionParser {
+	if _, err := log.NewXMLExpressionParser(expressions); err != nil {
+		panic(logqlmodel.NewParseError(fmt.Sprintf("invalid xml parser: %s", err.Error()), 0, 0))
+	}
+	return &XMLExpressionParser{
+		Expressions: expressions,
+	}
+}
+
+func (x *XMLExpressionParser) Shardable() bool { return true }
+
+func (x *XMLExpressionParser) Walk(f WalkFn) { f(x) }
+
+func (x *XMLExpressionParser) Stage() (log.Stage, error) {
+	return log.NewXMLExpressionParser(x.Expressions)
+}
+
+func (x *XMLExpressionParser) String() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%s %s ", OpPipe, OpParserTypeXML))
+	for i, exp := range x.Expressions {
+		sb.WriteString(exp.Identifier)
+		sb.WriteString("=")
+		sb.WriteString(strconv.Quote(exp.Expression))
+
+		if i+1 != len(x.Expressions) {
+			sb.WriteString(",")
+		}
+	}
+	return sb.String()
+}
+
+const (
+	defaultCSVSeparator = ","
+	defaultKVSeparator  = ","
+	defaultKVAssign     = "="
+)
+
+// parserOptions validates the options of a parser and returns them by name.
+func parserOptions(op string, options []log.LabelExtractionExpr, allowed ...string) map[string]string {
+	res := make(map[string]string, len(options))
+	for _, opt := range options {
+		if !slices.Contains(allowed, opt.Identifier) {
+			panic(logqlmodel.NewParseError(fmt.Sprintf("invalid %s parser option: %s", op, opt.Identifier), 0, 0))
+		}
+		if _, ok := res[opt.Identifier]; ok {
+			panic(logqlmodel.NewParseError(fmt.Sprintf("duplicate %s parser option: %s", op, opt.Identifier), 0, 0))
+		}
+		res[opt.Identifier] = opt.Expression
+	}
+	return res
+}
+
+// CSVParserExpr extracts the columns of a csv log line, named by a header.
+type CSVParserExpr struct {
+	Header    string
+	Separator string
+
+	implicit
+}
+
+func newCSVParserExpr(header string, options []log.LabelExtractionExpr) *CSVParserExpr {
+	e := &CSVParserExpr{
+		Header:    header,
+		Separator: defaultCSVSeparator,
+	}
+	if sep, ok := parserOptions(OpParserTypeCSV, options, OpOptionSeparator)[OpOptionSeparator]; ok {
+		e.Separator = sep
+	}
+	if _, err := log.NewCSVParser(e.Header, e.Separator); err != nil {
+		panic(logqlmodel.NewParseError(fmt.Sprintf("invalid csv parser: %s", err.Error()), 0, 0))
+	}
+	return e
+}
+
+func (e *CSVParserExpr) Shardable() bool { return true }
+
+func (e *CSVParserExpr) Walk(f WalkFn) { f(e) }
+
+func (e *CSVParserExpr) Stage() (log.Stage, error) {
+	return log.NewCSVParser(e.Header, e.Separator)
+}
+
+func (e *CSVParserExpr) String() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%s %s ", OpPipe, OpParserTypeCSV))
+	if e.Separator != defaultCSVSeparator {
+		sb.WriteString(fmt.Sprintf("%s=%s ", OpOptionSeparator, strconv.Quote(e.Separator)))
+	}
+	sb.WriteString(strconv.Quote(e.Header))
+	return sb.String()
+}
+
+// KVParserExpr extracts the key-value pairs of a log line using custom separators.
+type KVParserExpr struct {
+	Separator string
+	Assign    string
+
+	implicit
+}
+
+func newKVParserExpr(options []log.LabelExtractionExpr) *KVParserExpr {
+	e := &KVParserExpr{
+		Separator: defaultKVSeparator,
+		Assign:    defaultKVAssign,
+	}
+	opts := parserOptions(OpParserTypeKV, options, OpOptionSeparator, OpOptionAssign)
+	if sep, ok := opts[OpOptionSeparator]; ok {
+		e.Separator = sep
+	}
+	if assign, ok := opts[OpOptionAssign]; ok {
+		e.Assign = assign
+	}
+	if _, err := log.NewKVParser(e.Separator, e.Assign); err != nil {
+		panic(logqlmodel.NewParseError(fmt.Sprintf("invalid kv parser: %s", err.Error()), 0, 0))
+	}
+	return e
+}
+
+func (e *KVParserExpr) Shardable() bool { return true }
+
+func (e *KVParserExpr) Walk(f WalkFn) { f(e) }
+
+func (e *KVParserExpr) Stage() (log.Stage, error) {
+	return log.NewKVParser(e.Separator, e.Assign)
+}
+
+func (e *KVParserExpr) String() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%s %s", OpPipe, OpParserTypeKV))
+	if e.Separator != defaultKVSeparator {
+		sb.WriteString(fmt.Sprintf(" %s=%s", OpOptionSeparator, strconv.Quote(e.Separator)))
+	}
+	if e.Assign != defaultKVAssign {
+		sb.WriteString(fmt.Sprintf(" %s=%s", OpOptionAssign, strconv.Quote(e.Assign)))
+	}
+	return sb.String()
+}
+
 func mustNewMatcher(t labels.MatchType, n, v string) *labels.Matcher {
 	m, err := labels.NewMatcher(t, n, v)
 	if err != nil {
@@ -984,17 +1383,20 @@ func newOffsetExpr(offset time.Duration) *OffsetExpr {
 
 const (
 	// vector ops
-	OpTypeSum      = "sum"
-	OpTypeAvg      = "avg"
-	OpTypeMax      = "max"
-	OpTypeMin      = "min"
-	OpTypeCount    = "count"
-	OpTypeStddev   = "stddev"
-	OpTypeStdvar   = "stdvar"
-	OpTypeBottomK  = "bottomk"
-	OpTypeTopK     = "topk"
-	OpTypeSort     = "sort"
-	OpTypeSortDesc = "sort_desc"
+	OpTypeSum         = "sum"
+	OpTypeAvg         = "avg"
+	OpTypeMax         = "max"
+	OpTypeMin         = "min"
+	OpTypeCount       = "count"
+	OpTypeStddev      = "stddev"
+	OpTypeStdvar      = "stdvar"
+	OpTypeBottomK     = "bottomk"
+	OpTypeTopK        = "topk"
+	OpTypeSort        = "sort"
+	OpTypeSortDesc    = "sort_desc"
+	OpTypeGroup       = "group"
+	OpTypeQuantile    = "quantile"
+	OpTypeCountValues = "count_values"
 
 	// range vector ops
 	OpRangeTypeCount       = "count_over_time"
@@ -1013,9 +1415,28 @@ const (
 	OpRangeTypeLast        = "last_over_time"
 	OpRangeTypeAbsent      = "absent_over_time"
 
+	// range vector ops from PromQL
+	OpRangeTypeDelta         = "delta"
+	OpRangeTypeDeriv         = "deriv"
+	OpRangeTypePredictLinear = "predict_linear"
+
 	//vector
 	OpTypeVector = "vector"
 
+	// functions
+	OpFuncAbs               = "abs"
+	OpFuncCeil              = "ceil"
+	OpFuncFloor             = "floor"
+	OpFuncLn                = "ln"
+	OpFuncRound             = "round"
+	OpFuncClampMin          = "clamp_min"
+	OpFuncClampMax          = "clamp_max"
+	OpFuncTimestamp         = "timestamp"
+	OpFuncScalar            = "scalar"
+	OpFuncAbsent            = "absent"
+	OpFuncHistogramQuantile = "histogram_quantile"
+	OpFuncTime              = "time"
+
 	// binops - logical/set
 	OpTypeOr     = "or"
 	OpTypeAnd    = "and"
@@ -1043,6 +1464,9 @@ const (
 	OpParserTypeRegexp  = "regexp"
 	OpParserTypeUnpack  = "unpack"
 	OpParserTypePattern = "pattern"
+	OpParserTypeCSV     = "csv"
+	OpParserTypeXML     = "xml"
+	OpParserTypeKV      = "kv"
 
 	OpFmtLine    = "line_format"
 	OpFmtLabel   = "label_format"
@@ -1064,6 +1488,7 @@ const (
 	OpConvDurationSeconds = "duration_seconds"
 
 	OpLabelReplace = "label_replace"
+	OpLabelJoin    = "label_join"
 
 	// function filters
 	OpFilterIP = "ip"
@@ -1074,9 +1499,31 @@ const (
 	// keep labels
 	OpKeep = "keep"
 
+	// distinct and dedup filters
+	OpDistinct = "distinct"
+	OpDedup    = "dedup"
+
+	// limit by filter
+	OpLimit       = "limit"
+	OpLimitSample = "sample"
+
+	// log joins
+	OpJoin = "join"
+
+	// unnest
+	OpUnnest = "unnest"
+
+	// decoding
+	OpDecode       = "decode"
+	OpJSONUnescape = "json_unescape"
+
 	// parser flags
 	OpStrict    = "--strict"
 	OpKeepEmpty = "--keep-empty"
+
+	// parser options
+	OpOptionSeparator = "sep"
+	OpOptionAssign    = "assign"
 )
 
 func IsComparisonOperator(op string) bool {
@@ -1122,7 +1569,7 @@ type RangeAggregationExpr struct {
 func newRangeAggregationExpr(left *LogRange, operation string, gr *Grouping, stringParams *string) SampleExpr {
 	var params *float64
 	if stringParams != nil {
-		if operation != OpRangeTypeQuantile {
+		if !rangeOpHasParameter(operation) {
 			return &RangeAggregationExpr{err: logqlmodel.NewParseError(fmt.Sprintf("parameter %s not supported for operation %s", *stringParams, operation), 0, 0)}
 		}
 		var err error
@@ -1133,7 +1580,7 @@ func newRangeAggregationExpr(left *LogRange, operation string, gr *Grouping, str
 		}
 
 	} else {
-		if operation == OpRangeTypeQuantile {
+		if rangeOpHasParameter(operation) {
 			return &RangeAggregationExpr{err: logqlmodel.NewParseError(fmt.Sprintf("parameter required for operation %s", operation), 0, 0)}
 		}
 	}
@@ -1149,6 +1596,30 @@ func newRangeAggregationExpr(left *LogRange, operation string, gr *Grouping, str
 	return e
 }
 
+// rangeOpHasParameter tells if a range vector operation requires a scalar parameter.
+func rangeOpHasParameter(operation string) bool {
+	return operation == OpRangeTypeQuantile || operation == OpRangeTypePredictLinear
+}
+
+// writeRangeOpArguments writes the arguments of a range vector operation,
+// the parameter of predict_linear comes last like in PromQL.
+func writeRangeOpArguments(sb *strings.Builder, operation string, params *float64, left string) {
+	if params == nil {
+		sb.WriteString(left)
+		return
+	}
+	param := strconv.FormatFloat(*params, 'f', -1, 64)
+	if operation == OpRangeTypePredictLinear {
+		sb.WriteString(left)
+		sb.WriteString(",")
+		sb.WriteString(param)
+		return
+	}
+	sb.WriteString(param)
+	sb.WriteString(",")
+	sb.WriteString(left)
+}
+
 func (e *RangeAggregationExpr) Selector() (LogSelectorExpr, error) {
 	if e.err != nil {
 		return nil, e.err
@@ -1176,7 +1647,8 @@ func (e *RangeAggregationExpr) MatcherGroups() ([]MatcherRange, error) {
 func (e RangeAggregationExpr) validate() error {
 	if e.Grouping != nil {
 		switch e.Operation {
-		case OpRangeTypeAvg, OpRangeTypeStddev, OpRangeTypeStdvar, OpRangeTypeQuantile, OpRangeTypeMax, OpRangeTypeMin, OpRangeTypeFirst, OpRangeTypeLast:
+		case OpRangeTypeAvg, OpRangeTypeStddev, OpRangeTypeStdvar, OpRangeTypeQuantile, OpRangeTypeMax, OpRangeTypeMin, OpRangeTypeFirst, OpRangeTypeLast,
+			OpRangeTypeDelta, OpRangeTypeDeriv, OpRangeTypePredictLinear:
 		default:
 			return fmt.Errorf("grouping not allowed for %s aggregation", e.Operation)
 		}
@@ -1185,7 +1657,7 @@ func (e RangeAggregationExpr) validate() error {
 		switch e.Operation {
 		case OpRangeTypeAvg, OpRangeTypeSum, OpRangeTypeMax, OpRangeTypeMin, OpRangeTypeStddev,
 			OpRangeTypeStdvar, OpRangeTypeQuantile, OpRangeTypeRate, OpRangeTypeRateCounter,
-			OpRangeTypeAbsent, OpRangeTypeFirst, OpRangeTypeLast:
+			OpRangeTypeAbsent, OpRangeTypeFirst, OpRangeTypeLast, OpRangeTypeDelta, OpRangeTypeDeriv, OpRangeTypePredictLinear:
 			return nil
 		default:
 			return fmt.Errorf("invalid aggregation %s with unwrap", e.Operation)
@@ -1208,11 +1680,7 @@ func (e *RangeAggregationExpr) String() string {
 	var sb strings.Builder
 	sb.WriteString(e.Operation)
 	sb.WriteString("(")
-	if e.Params != nil {
-		sb.WriteString(strconv.FormatFloat(*e.Params, 'f', -1, 64))
-		sb.WriteString(",")
-	}
-	sb.WriteString(e.Left.String())
+	writeRangeOpArguments(&sb, e.Operation, e.Params, e.Left.String())
 	sb.WriteString(")")
 	if e.Grouping != nil {
 		sb.WriteString(e.Grouping.String())
@@ -1233,6 +1701,156 @@ func (e *RangeAggregationExpr) Walk(f WalkFn) {
 	e.Left.Walk(f)
 }
 
+// SubqueryExpr evaluates a metric expression at a fixed resolution over a range: <expr>[<range>:<step>].
+// A zero step means the step of the query.
+type SubqueryExpr struct {
+	Left   SampleExpr
+	Range  time.Duration
+	Step   time.Duration
+	Offset time.Duration
+
+	implicit
+}
+
+func newSubqueryExpr(left SampleExpr, r subqueryRange, o *OffsetExpr) *SubqueryExpr {
+	var offset time.Duration
+	if o != nil {
+		offset = o.Offset
+	}
+	return &SubqueryExpr{
+		Left:   left,
+		Range:  r.Range,
+		Step:   r.Step,
+		Offset: offset,
+	}
+}
+
+// impls Stringer
+func (e SubqueryExpr) String() string {
+	var sb strings.Builder
+	sb.WriteString(e.Left.String())
+	sb.WriteString(fmt.Sprintf("[%v:", model.Duration(e.Range)))
+	if e.Step != 0 {
+		sb.WriteString(model.Duration(e.Step).String())
+	}
+	sb.WriteString("]")
+	if e.Offset != 0 {
+		offsetExpr := OffsetExpr{Offset: e.Offset}
+		sb.WriteString(offsetExpr.String())
+	}
+	return sb.String()
+}
+
+func (e *SubqueryExpr) Shardable() bool { return e.Left.Shardable() }
+
+func (e *SubqueryExpr) Walk(f WalkFn) {
+	f(e)
+	if e.Left == nil {
+		return
+	}
+	e.Left.Walk(f)
+}
+
+// SubqueryAggregationExpr aggregates the samples of a subquery over time, e.g. max_over_time(<expr>[1h:1m]).
+type SubqueryAggregationExpr struct {
+	Left      *SubqueryExpr
+	Operation string
+
+	Params *float64
+	err    error
+	implicit
+}
+
+func newSubqueryAggregationExpr(left *SubqueryExpr, operation string, stringParams *string) SampleExpr {
+	var params *float64
+	if stringParams != nil {
+		if !rangeOpHasParameter(operation) {
+			return &SubqueryAggregationExpr{err: logqlmodel.NewParseError(fmt.Sprintf("parameter %s not supported for operation %s", *stringParams, operation), 0, 0)}
+		}
+		var err error
+		params = new(float64)
+		*params, err = strconv.ParseFloat(*stringParams, 64)
+		if err != nil {
+			return &SubqueryAggregationExpr{err: logqlmodel.NewParseError(fmt.Sprintf("invalid parameter for operation %s: %s", operation, err), 0, 0)}
+		}
+	} else if rangeOpHasParameter(operation) {
+		return &SubqueryAggregationExpr{err: logqlmodel.NewParseError(fmt.Sprintf("parameter required for operation %s", operation), 0, 0)}
+	}
+	switch operation {
+	case OpRangeTypeCount, OpRangeTypeAvg, OpRangeTypeSum, OpRangeTypeMax, OpRangeTypeMin, OpRangeTypeStddev,
+		OpRangeTypeStdvar, OpRangeTypeQuantile, OpRangeTypeFirst, OpRangeTypeLast, OpRangeTypeAbsent,
+		OpRangeTypeDelta, OpRangeTypeDeriv, OpRangeTypePredictLinear:
+	default:
+		return &SubqueryAggregationExpr{err: logqlmodel.NewParseError(fmt.Sprintf("invalid aggregation %s of a subquery", operation), 0, 0)}
+	}
+	if left.Range <= 0 {
+		return &SubqueryAggregationExpr{err: logqlmodel.NewParseError("subquery range must be positive", 0, 0)}
+	}
+	if left.Step < 0 {
+		return &SubqueryAggregationExpr{err: logqlmodel.NewParseError("subquery step must be positive", 0, 0)}
+	}
+	return &SubqueryAggregationExpr{
+		Left:      left,
+		Operation: operation,
+		Params:    params,
+	}
+}
+
+func (e *SubqueryAggregationExpr) Selector() (LogSelectorExpr, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	return e.Left.Left.Selector()
+}
+
+// MatcherGroups returns the matcher groups of the subquery expression,
+// widened by the range and the offset of the subquery.
+func (e *SubqueryAggregationExpr) MatcherGroups() ([]MatcherRange, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	groups, err := e.Left.Left.MatcherGroups()
+	if err != nil {
+		return nil, err
+	}
+	for i := range groups {
+		groups[i].Interval += e.Left.Range
+		groups[i].Offset += e.Left.Offset
+	}
+	return groups, nil
+}
+
+func (e *SubqueryAggregationExpr) Extractor() (SampleExtractor, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	return e.Left.Left.Extractor()
+}
+
+// impls Stringer
+func (e *SubqueryAggregationExpr) String() string {
+	var sb strings.Builder
+	sb.WriteString(e.Operation)
+	sb.WriteString("(")
+	writeRangeOpArguments(&sb, e.Operation, e.Params, e.Left.String())
+	sb.WriteString(")")
+	return sb.String()
+}
+
+// Shardable is always false as the aggregation over time happens on the
+// subquery samples, only the subquery expression itself may be sharded.
+func (e *SubqueryAggregationExpr) Shardable() bool {
+	return false
+}
+
+func (e *SubqueryAggregationExpr) Walk(f WalkFn) {
+	f(e)
+	if e.Left == nil {
+		return
+	}
+	e.Left.Walk(f)
+}
+
 // Grouping struct represents the grouping by/without label(s) for vector aggregators and range vector aggregators.
 // The representation is as follows:
 //   - No Grouping (labels dismissed): <operation> (<expr>) => Grouping{Without: false, Groups: nil}
@@ -1281,14 +1899,36 @@ type VectorAggregationExpr struct {
 	Grouping  *Grouping
 	Params    int
 	Operation string
-	err       error
+	// Quantile is the φ parameter of the quantile operation.
+	Quantile float64
+	// Label is the name of the label holding the counted values of the count_values operation.
+	Label string
+	err   error
 	implicit
 }
 
 func mustNewVectorAggregationExpr(left SampleExpr, operation string, gr *Grouping, params *string) SampleExpr {
 	var p int
+	var q float64
+	var lbl string
 	var err error
 	switch operation {
+	case OpTypeQuantile:
+		if params == nil {
+			return &VectorAggregationExpr{err: logqlmodel.NewParseError(fmt.Sprintf("parameter required for operation %s", operation), 0, 0)}
+		}
+		q, err = strconv.ParseFloat(*params, 64)
+		if err != nil {
+			return &VectorAggregationExpr{err: logqlmodel.NewParseError(fmt.Sprintf("invalid parameter %s(%s,", operation, *params), 0, 0)}
+		}
+	case OpTypeCountValues:
+		if params == nil {
+			return &VectorAggregationExpr{err: logqlmodel.NewParseError(fmt.Sprintf("parameter required for operation %s", operation), 0, 0)}
+		}
+		if !model.LabelName(*params).IsValid() {
+			return &VectorAggregationExpr{err: logqlmodel.NewParseError(fmt.Sprintf("invalid label name %q for operation %s", *params, operation), 0, 0)}
+		}
+		lbl = *params
 	case OpTypeBottomK, OpTypeTopK:
 		if params == nil {
 			return &VectorAggregationExpr{err: logqlmodel.NewParseError(fmt.Sprintf("parameter required for operation %s", operation), 0, 0)}
@@ -1314,6 +1954,8 @@ func mustNewVectorAggregationExpr(left SampleExpr, operation string, gr *Groupin
 		Operation: operation,
 		Grouping:  gr,
 		Params:    p,
+		Quantile:  q,
+		Label:     lbl,
 	}
 }
 
@@ -1365,6 +2007,10 @@ func (e *VectorAggregationExpr) String() string {
 	// bottomK and topk can have first parameter as 0
 	case OpTypeBottomK, OpTypeTopK:
 		params = []string{fmt.Sprintf("%d", e.Params), e.Left.String()}
+	case OpTypeQuantile:
+		params = []string{strconv.FormatFloat(e.Quantile, 'f', -1, 64), e.Left.String()}
+	case OpTypeCountValues:
+		params = []string{strconv.Quote(e.Label), e.Left.String()}
 	default:
 		if e.Params != 0 {
 			params = []string{fmt.Sprintf("%d", e.Params), e.Left.String()}
@@ -1394,7 +2040,7 @@ func (e *VectorAggregationExpr) Shardable() bool {
 
 		return shardable
 
-	case OpTypeMax, OpTypeMin:
+	case OpTypeMax, OpTypeMin, OpTypeGroup:
 		// max(<range_aggr>) can be sharded by pushing down the max|min aggregation,
 		// but max(<vector_aggr>) cannot. It needs to perform the
 		// aggregation on the total result set, and then pick the max|min.
@@ -1417,7 +2063,7 @@ func (e *VectorAggregationExpr) Shardable() bool {
 		// does not
 		if child, ok := e.Left.(*VectorAggregationExpr); ok {
 			switch child.Operation {
-			case OpTypeMin, OpTypeMax:
+			case OpTypeMin, OpTypeMax, OpTypeGroup:
 				return false
 			}
 		}
@@ -1980,6 +2626,202 @@ func (e *LabelReplaceExpr) String() string {
 	return sb.String()
 }
 
+// FunctionExpr applies a function to the samples of a metric expression at each step,
+// e.g. abs(<expr>), clamp_min(<expr>, 0) or histogram_quantile(0.99, <expr>).
+// Params holds the scalar parameter of round, clamp_min, clamp_max and histogram_quantile.
+type FunctionExpr struct {
+	Left     SampleExpr
+	Function string
+
+	Params *float64
+	err    error
+	implicit
+}
+
+func newFunctionExpr(left SampleExpr, function string, param *LiteralExpr) SampleExpr {
+	var params *float64
+	if param != nil {
+		switch function {
+		case OpFuncRound, OpFuncClampMin, OpFuncClampMax, OpFuncHistogramQuantile:
+		default:
+			return &FunctionExpr{err: logqlmodel.NewParseError(fmt.Sprintf("parameter %s not supported for function %s", param, function), 0, 0)}
+		}
+		v, err := param.Value()
+		if err != nil {
+			return &FunctionExpr{err: err}
+		}
+		params = &v
+	} else {
+		switch function {
+		case OpFuncClampMin, OpFuncClampMax, OpFuncHistogramQuantile:
+			return &FunctionExpr{err: logqlmodel.NewParseError(fmt.Sprintf("parameter required for function %s", function), 0, 0)}
+		}
+	}
+	return &FunctionExpr{
+		Left:     left,
+		Function: function,
+		Params:   params,
+	}
+}
+
+func (e *FunctionExpr) Selector() (LogSelectorExpr, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	return e.Left.Selector()
+}
+
+func (e *FunctionExpr) MatcherGroups() ([]MatcherRange, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	return e.Left.MatcherGroups()
+}
+
+func (e *FunctionExpr) Extractor() (SampleExtractor, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	return e.Left.Extractor()
+}
+
+// Shardable is true for the functions applied to each sample independently,
+// when the result of the inner range aggregation can be concatenated across shards.
+func (e *FunctionExpr) Shardable() bool {
+	if !shardableOps[e.Function] {
+		return false
+	}
+	r, ok := e.Left.(*RangeAggregationExpr)
+	return ok && r.Shardable() && !ReducesLabels(r)
+}
+
+func (e *FunctionExpr) Walk(f WalkFn) {
+	f(e)
+	if e.Left == nil {
+		return
+	}
+	e.Left.Walk(f)
+}
+
+func (e *FunctionExpr) String() string {
+	var sb strings.Builder
+	sb.WriteString(e.Function)
+	sb.WriteString("(")
+	if e.Function == OpFuncHistogramQuantile {
+		sb.WriteString(strconv.FormatFloat(*e.Params, 'f', -1, 64))
+		sb.WriteString(",")
+		sb.WriteString(e.Left.String())
+	} else {
+		sb.WriteString(e.Left.String())
+		if e.Params != nil {
+			sb.WriteString(",")
+			sb.WriteString(strconv.FormatFloat(*e.Params, 'f', -1, 64))
+		}
+	}
+	sb.WriteString(")")
+	return sb.String()
+}
+
+// TimeExpr is the time() function, which returns the timestamp of each step in seconds.
+type TimeExpr struct {
+	implicit
+}
+
+func newTimeExpr() *TimeExpr {
+	return &TimeExpr{}
+}
+
+func (e *TimeExpr) String() string {
+	return OpFuncTime + "()"
+}
+
+// TimeExpr impls SampleExpr & LogSelectorExpr like VectorExpr, it has no selector.
+func (e *TimeExpr) Selector() (LogSelectorExpr, error)      { return e, nil }
+func (e *TimeExpr) HasFilter() bool                         { return false }
+func (e *TimeExpr) Shardable() bool                         { return true }
+func (e *TimeExpr) Walk(f WalkFn)                           { f(e) }
+func (e *TimeExpr) Pipeline() (log.Pipeline, error)         { return log.NewNoopPipeline(), nil }
+func (e *TimeExpr) Matchers() []*labels.Matcher             { return nil }
+func (e *TimeExpr) MatcherGroups() ([]MatcherRange, error)  { return nil, nil }
+func (e *TimeExpr) Extractor() (log.SampleExtractor, error) { return nil, nil }
+
+// LabelJoinExpr joins the values of the Src labels with Separator into the Dst label.
+type LabelJoinExpr struct {
+	Left      SampleExpr
+	Dst       string
+	Separator string
+	Src       []string
+	err       error
+
+	implicit
+}
+
+func mustNewLabelJoinExpr(left SampleExpr, dst, separator string, src []string) *LabelJoinExpr {
+	for _, name := range append([]string{dst}, src...) {
+		if !model.LabelName(name).IsValid() {
+			return &LabelJoinExpr{
+				err: logqlmodel.NewParseError(fmt.Sprintf("invalid label name in label_join: %q", name), 0, 0),
+			}
+		}
+	}
+	return &LabelJoinExpr{
+		Left:      left,
+		Dst:       dst,
+		Separator: separator,
+		Src:       src,
+	}
+}
+
+func (e *LabelJoinExpr) Selector() (LogSelectorExpr, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	return e.Left.Selector()
+}
+
+func (e *LabelJoinExpr) MatcherGroups() ([]MatcherRange, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	return e.Left.MatcherGroups()
+}
+
+func (e *LabelJoinExpr) Extractor() (SampleExtractor, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	return e.Left.Extractor()
+}
+
+func (e *LabelJoinExpr) Shardable() bool {
+	return false
+}
+
+func (e *LabelJoinExpr) Walk(f WalkFn) {
+	f(e)
+	if e.Left == nil {
+		return
+	}
+	e.Left.Walk(f)
+}
+
+func (e *LabelJoinExpr) String() string {
+	var sb strings.Builder
+	sb.WriteString(OpLabelJoin)
+	sb.WriteString("(")
+	sb.WriteString(e.Left.String())
+	sb.WriteString(",")
+	sb.WriteString(strconv.Quote(e.Dst))
+	sb.WriteString(",")
+	sb.WriteString(strconv.Quote(e.Separator))
+	for _, src := range e.Src {
+		sb.WriteString(",")
+		sb.WriteString(strconv.Quote(src))
+	}
+	sb.WriteString(")")
+	return sb.String()
+}
+
 // shardableOps lists the operations which may be sharded, but are not
 // guaranteed to be. See the `Shardable()` implementations
 // on the respective expr types for more details.
@@ -2007,6 +2849,18 @@ var shardableOps = map[string]bool{
 	OpTypeCount: true,
 	OpTypeMax:   true,
 	OpTypeMin:   true,
+	// group is idempotent, so it can be pushed down like max & min.
+	OpTypeGroup: true,
+
+	// functions applied to each sample independently
+	OpFuncAbs:       true,
+	OpFuncCeil:      true,
+	OpFuncFloor:     true,
+	OpFuncLn:        true,
+	OpFuncRound:     true,
+	OpFuncClampMin:  true,
+	OpFuncClampMax:  true,
+	OpFuncTimestamp: true,
 
 	// range vector ops
 	OpRangeTypeAvg:       true,
@@ -46,6 +46,23 @@ func Test_logSelectorExpr_String(t *testing.T) {
 		{`{foo="bar"} |= "baz" |~ "blip" != "flip" !~ "flap" | logfmt | b=ip("127.0.0.1") | level="error" | c=ip("::1")`, true}, // chain inside label filters.
 		{`{foo="bar"} |= "baz" |~ "blip" != "flip" !~ "flap" | regexp "(?P<foo>foo|bar)"`, true},
 		{`{foo="bar"} |= "baz" |~ "blip" != "flip" !~ "flap" | regexp "(?P<foo>foo|bar)" | ( ( foo<5.01 , bar>20ms ) or foo="bar" ) | line_format "blip{{.boop}}bap" | label_format foo=bar,bar="blip{{.blop}}"`, true},
+		{`{foo="bar"} | json | distinct id,host`, true},
+		{`{foo="bar"} | json | distinct id | dedup`, true},
+		{`{foo="bar"} | dedup 5s`, true},
+		{`{foo="bar"} | json | limit 10 by (pod)`, true},
+		{`{foo="bar"} | limit 5 by (pod,container) sample`, true},
+		{`{foo="bar"} | unnest items | sku="a"`, true},
+		{`{foo="bar"} | unnest "order.items" | json`, true},
+		{`{foo="bar"} | csv "type,time,elb" | type="https"`, true},
+		{`{foo="bar"} | csv sep="\t" "type\ttime" | type="https"`, true},
+		{`{foo="bar"} | kv | level="error"`, true},
+		{`{foo="bar"} | decode gzip+base64 |= "error" | json`, true},
+		{`{foo="bar"} | json | decode base64 payload | payload="error"`, true},
+		{`{foo="bar"} | json_unescape log | json`, true},
+		{`{foo="bar"} | json_unescape "kubernetes.log" | logfmt`, true},
+		{`{foo="bar"} | kv sep=";" assign=":" | level="error"`, true},
+		{`{foo="bar"} | xml | Event_System_EventID="4624"`, true},
+		{`{foo="bar"} | xml id="/Event/System/EventID",user="/Event/EventData/Data[@Name='TargetUserName']"`, true},
 	}
 
 	for _, tt := range tests {
@@ -183,6 +200,18 @@ func Test_SampleExpr_String(t *testing.T) {
 				or on ()  
 				((sum by(typename,pool,commandname,colo) (sum_over_time({_namespace_="appspace", _schema_="appspace-1h", pool=~"r1testlvs", colo=~"slc|lvs|rno", env!~"(pre-production|sandbox)"} | logfmt | status!="0" | ( ( type=~"(?i)^(Error|Exception|Fatal|ERRPAGE|ValidationError)$" or typename=~"(?i)^(Error|Exception|Fatal|ERRPAGE|ValidationError)$" ) or status=~"(?i)^(Error|Exception|Fatal|ERRPAGE|ValidationError)$" ) | commandname=~"(?i).*|UNSET" | unwrap sumcount[5m])) / 60) / 60))`,
 		`{app="foo"} | logfmt code="response.code", IPAddress="host"`,
+		`label_join(sum by (job, instance) (rate({job="mysql"}[5m])), "target", ":", "job", "instance")`,
+		`clamp_max(abs(sum(rate({job="mysql"}[5m])) - 10), 5)`,
+		`round(sum(rate({job="mysql"}[5m])), 0.5) + time() - timestamp(vector(1))`,
+		`histogram_quantile(0.99, sum by (le) (count_over_time({job="mysql"} | logfmt [5m])))`,
+		`absent(count_over_time({job="mysql"}[5m])) or scalar(vector(1))`,
+		`group by (job) (rate({job="mysql"}[5m]))`,
+		`quantile by (job) (0.9, rate({job="mysql"}[5m]))`,
+		`count_values without (instance) ("value", rate({job="mysql"}[5m]))`,
+		`delta(({job="mysql"} | unwrap bytes [5m])) by (job)`,
+		`deriv(({job="mysql"} | unwrap bytes [5m]))`,
+		`predict_linear(({job="mysql"} | unwrap bytes [5m]), 3600)`,
+		`predict_linear(max by (job) (rate({job="mysql"}[5m]))[1h:5m], 3600)`,
 	} {
 		t.Run(tc, func(t *testing.T) {
 			expr, err := ParseExpr(tc)
@@ -474,6 +503,18 @@ func TestStringer(t *testing.T) {
 			in:  `0 > count_over_time({foo="bar"}[1m])`,
 			out: `(0 > count_over_time({foo="bar"}[1m]))`,
 		},
+		{
+			in:  `join({app="gateway"} | json, {app="backend"}, "request_id", 5s)`,
+			out: `join(({app="gateway"} | json), {app="backend"}, "request_id", 5s)`,
+		},
+		{
+			in:  `join(({app="gateway"} | distinct id,host), {app="backend"}, "request_id", 5s)`,
+			out: `join(({app="gateway"} | distinct id,host), {app="backend"}, "request_id", 5s)`,
+		},
+		{
+			in:  `{app="foo"} |= "a" or "b" or ip("1.2.3.4") != "c"`,
+			out: `{app="foo"} |= "a" or "b" or ip("1.2.3.4") != "c"`,
+		},
 	} {
 		t.Run(tc.in, func(t *testing.T) {
 			expr, err := ParseExpr(tc.in)
@@ -700,6 +741,16 @@ func TestFilterReodering(t *testing.T) {
 		require.Len(t, stages, 5)
 		require.Equal(t, `|= "06497595" | unpack != "message" | json | line_format "new log: {{.foo}}"`, MultiStageExpr(stages).String())
 	})
+
+	t.Run("decode test", func(t *testing.T) {
+		logExpr := `{container_name="app"} |= "foo" | decode base64 |= "bar" | json | json_unescape log |= "baz"`
+		l, err := ParseExpr(logExpr)
+		require.NoError(t, err)
+
+		stages := l.(*PipelineExpr).MultiStages.reorderStages()
+		require.Len(t, stages, 6)
+		require.Equal(t, `|= "foo" | decode base64 |= "bar" | json | json_unescape log |= "baz"`, MultiStageExpr(stages).String())
+	})
 }
 
 var result bool
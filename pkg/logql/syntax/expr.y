@@ -16,6 +16,7 @@ import (
   Labels                  []string
   LogExpr                 LogSelectorExpr
   LogRangeExpr            *LogRange
+  SubqueryExpr            *SubqueryExpr
   Matcher                 *labels.Matcher
   Matchers                []*labels.Matcher
   RangeAggregationExpr    SampleExpr
@@ -30,10 +31,14 @@ import (
   FilterOp                string
   BinOpExpr               SampleExpr
   LabelReplaceExpr        SampleExpr
+  LabelJoinExpr           SampleExpr
+  FunctionExpr            SampleExpr
+  FunctionOp              string
   binOp                   string
   bytes                   uint64
   str                     string
   duration                time.Duration
+  subqueryRange           subqueryRange
   LiteralExpr             *LiteralExpr
   BinOpModifier           *BinOpOptions
   BoolModifier            *BinOpOptions
@@ -76,12 +81,14 @@ import (
 %start root
 
 %type <Expr>                  expr
+%type <Expr>                  joinExpr
 %type <Filter>                filter
 %type <Grouping>              grouping
 %type <Labels>                labels
 %type <LogExpr>               logExpr
 %type <MetricExpr>            metricExpr
 %type <LogRangeExpr>          logRangeExpr
+%type <SubqueryExpr>          subqueryExpr
 %type <Matcher>               matcher
 %type <Matchers>              matchers
 %type <RangeAggregationExpr>  rangeAggregationExpr
@@ -96,6 +103,10 @@ import (
 %type <BinOpExpr>             binOpExpr
 %type <LiteralExpr>           literalExpr
 %type <LabelReplaceExpr>      labelReplaceExpr
+%type <LabelJoinExpr>         labelJoinExpr
+%type <Labels>                labelJoinSources
+%type <FunctionExpr>          functionExpr
+%type <FunctionOp>            functionOp
 %type <BinOpModifier>         binOpModifier
 %type <BoolModifier>          boolModifier
 %type <OnOrIgnoringModifier>  onOrIgnoringModifier
@@ -119,6 +130,15 @@ import (
 %type <KeepLabelsExpr>        keepLabelsExpr
 %type <KeepLabels>            keepLabels
 %type <KeepLabel>             keepLabel
+%type <PipelineStage>         distinctFilterExpr
+%type <PipelineStage>         unnestExpr
+%type <PipelineStage>         csvParser kvParser xmlExpressionParser
+%type <PipelineStage>         decodeExpr jsonUnescapeExpr
+%type <Labels>                encodings
+%type <LabelExtractionExpression>        parserOption
+%type <LabelExtractionExpressionList>    parserOptions
+%type <PipelineStage>         dedupExpr
+%type <PipelineStage>         limitByExpr
 %type <LabelFormatExpr>       labelFormatExpr
 %type <LabelFormat>           labelFormat
 %type <LabelsFormat>          labelsFormat
@@ -130,16 +150,19 @@ import (
 %type <UnitFilter>            unitFilter
 %type <IPLabelFilter>         ipLabelFilter
 %type <OffsetExpr>            offsetExpr
+%type <str>                   stringValue
 
 %token <bytes> BYTES
-%token <str>      IDENTIFIER STRING NUMBER PARSER_FLAG
+%token <str>      IDENTIFIER STRING NUMBER PARSER_FLAG PARAM
 %token <duration> DURATION RANGE
+%token <subqueryRange> SUBQUERY_RANGE
 %token <val>      MATCHERS LABELS EQ RE NRE OPEN_BRACE CLOSE_BRACE OPEN_BRACKET CLOSE_BRACKET COMMA DOT PIPE_MATCH PIPE_EXACT
                   OPEN_PARENTHESIS CLOSE_PARENTHESIS BY WITHOUT COUNT_OVER_TIME RATE RATE_COUNTER SUM SORT SORT_DESC AVG MAX MIN COUNT STDDEV STDVAR BOTTOMK TOPK
                   BYTES_OVER_TIME BYTES_RATE BOOL JSON REGEXP LOGFMT PIPE LINE_FMT LABEL_FMT UNWRAP AVG_OVER_TIME SUM_OVER_TIME MIN_OVER_TIME
                   MAX_OVER_TIME STDVAR_OVER_TIME STDDEV_OVER_TIME QUANTILE_OVER_TIME BYTES_CONV DURATION_CONV DURATION_SECONDS_CONV
                   FIRST_OVER_TIME LAST_OVER_TIME ABSENT_OVER_TIME VECTOR LABEL_REPLACE UNPACK OFFSET PATTERN IP ON IGNORING GROUP_LEFT GROUP_RIGHT
-                  DECOLORIZE DROP KEEP
+                  DECOLORIZE DROP KEEP ABS CEIL FLOOR LN ROUND CLAMP_MIN CLAMP_MAX TIMESTAMP SCALAR ABSENT HISTOGRAM_QUANTILE TIME
+                  LABEL_JOIN GROUP QUANTILE COUNT_VALUES DELTA DERIV PREDICT_LINEAR DISTINCT DEDUP UNNEST CSV XML KV DECODE JSON_UNESCAPE LIMIT JOIN
 
 // Operators are listed with increasing precedence.
 %left <binOp> OR
@@ -156,6 +179,11 @@ root: expr { exprlex.(*parser).expr = $1 };
 expr:
       logExpr                                      { $$ = $1 }
     | metricExpr                                   { $$ = $1 }
+    | joinExpr                                     { $$ = $1 }
+    ;
+
+joinExpr:
+      JOIN OPEN_PARENTHESIS logExpr COMMA logExpr COMMA STRING COMMA DURATION CLOSE_PARENTHESIS { $$ = newJoinExpr($3, $5, $7, $9) }
     ;
 
 metricExpr:
@@ -164,6 +192,8 @@ metricExpr:
     | binOpExpr                                     { $$ = $1 }
     | literalExpr                                   { $$ = $1 }
     | labelReplaceExpr                              { $$ = $1 }
+    | labelJoinExpr                                 { $$ = $1 }
+    | functionExpr                                  { $$ = $1 }
     | vectorExpr                                    { $$ = $1 }
     | OPEN_PARENTHESIS metricExpr CLOSE_PARENTHESIS { $$ = $2 }
     ;
@@ -203,6 +233,11 @@ logRangeExpr:
     | logRangeExpr error
     ;
 
+subqueryExpr:
+      metricExpr SUBQUERY_RANGE             { $$ = newSubqueryExpr($1, $2, nil) }
+    | metricExpr SUBQUERY_RANGE offsetExpr  { $$ = newSubqueryExpr($1, $2, $3) }
+    ;
+
 unwrapExpr:
     PIPE UNWRAP IDENTIFIER                                                   { $$ = newUnwrapExpr($3, "")}
   | PIPE UNWRAP convOp OPEN_PARENTHESIS IDENTIFIER CLOSE_PARENTHESIS         { $$ = newUnwrapExpr($5, $3)}
@@ -220,6 +255,12 @@ rangeAggregationExpr:
     | rangeOp OPEN_PARENTHESIS NUMBER COMMA logRangeExpr CLOSE_PARENTHESIS           { $$ = newRangeAggregationExpr($5, $1, nil, &$3) }
     | rangeOp OPEN_PARENTHESIS logRangeExpr CLOSE_PARENTHESIS grouping               { $$ = newRangeAggregationExpr($3, $1, $5, nil) }
     | rangeOp OPEN_PARENTHESIS NUMBER COMMA logRangeExpr CLOSE_PARENTHESIS grouping  { $$ = newRangeAggregationExpr($5, $1, $7, &$3) }
+    | rangeOp OPEN_PARENTHESIS subqueryExpr CLOSE_PARENTHESIS                        { $$ = newSubqueryAggregationExpr($3, $1, nil) }
+    | rangeOp OPEN_PARENTHESIS NUMBER COMMA subqueryExpr CLOSE_PARENTHESIS           { $$ = newSubqueryAggregationExpr($5, $1, &$3) }
+    // predict_linear takes its parameter last, like in PromQL.
+    | PREDICT_LINEAR OPEN_PARENTHESIS logRangeExpr COMMA NUMBER CLOSE_PARENTHESIS            { $$ = newRangeAggregationExpr($3, OpRangeTypePredictLinear, nil, &$5) }
+    | PREDICT_LINEAR OPEN_PARENTHESIS logRangeExpr COMMA NUMBER CLOSE_PARENTHESIS grouping   { $$ = newRangeAggregationExpr($3, OpRangeTypePredictLinear, $7, &$5) }
+    | PREDICT_LINEAR OPEN_PARENTHESIS subqueryExpr COMMA NUMBER CLOSE_PARENTHESIS            { $$ = newSubqueryAggregationExpr($3, OpRangeTypePredictLinear, &$5) }
     ;
 
 vectorAggregationExpr:
@@ -231,6 +272,10 @@ vectorAggregationExpr:
     | vectorOp OPEN_PARENTHESIS NUMBER COMMA metricExpr CLOSE_PARENTHESIS                 { $$ = mustNewVectorAggregationExpr($5, $1, nil, &$3) }
     | vectorOp OPEN_PARENTHESIS NUMBER COMMA metricExpr CLOSE_PARENTHESIS grouping        { $$ = mustNewVectorAggregationExpr($5, $1, $7, &$3) }
     | vectorOp grouping OPEN_PARENTHESIS NUMBER COMMA metricExpr CLOSE_PARENTHESIS        { $$ = mustNewVectorAggregationExpr($6, $1, $2, &$4) }
+    // count_values takes the name of the label holding the counted values.
+    | COUNT_VALUES OPEN_PARENTHESIS STRING COMMA metricExpr CLOSE_PARENTHESIS              { $$ = mustNewVectorAggregationExpr($5, OpTypeCountValues, nil, &$3) }
+    | COUNT_VALUES OPEN_PARENTHESIS STRING COMMA metricExpr CLOSE_PARENTHESIS grouping     { $$ = mustNewVectorAggregationExpr($5, OpTypeCountValues, $7, &$3) }
+    | COUNT_VALUES grouping OPEN_PARENTHESIS STRING COMMA metricExpr CLOSE_PARENTHESIS     { $$ = mustNewVectorAggregationExpr($6, OpTypeCountValues, $2, &$4) }
     ;
 
 labelReplaceExpr:
@@ -238,6 +283,23 @@ labelReplaceExpr:
       { $$ = mustNewLabelReplaceExpr($3, $5, $7, $9, $11)}
     ;
 
+labelJoinExpr:
+      LABEL_JOIN OPEN_PARENTHESIS metricExpr COMMA STRING COMMA STRING CLOSE_PARENTHESIS                         { $$ = mustNewLabelJoinExpr($3, $5, $7, nil) }
+    | LABEL_JOIN OPEN_PARENTHESIS metricExpr COMMA STRING COMMA STRING COMMA labelJoinSources CLOSE_PARENTHESIS  { $$ = mustNewLabelJoinExpr($3, $5, $7, $9) }
+    ;
+
+labelJoinSources:
+      STRING                         { $$ = []string{ $1 } }
+    | labelJoinSources COMMA STRING  { $$ = append($1, $3) }
+    ;
+
+functionExpr:
+      functionOp OPEN_PARENTHESIS metricExpr CLOSE_PARENTHESIS                           { $$ = newFunctionExpr($3, $1, nil) }
+    | functionOp OPEN_PARENTHESIS metricExpr COMMA literalExpr CLOSE_PARENTHESIS         { $$ = newFunctionExpr($3, $1, $5) }
+    | HISTOGRAM_QUANTILE OPEN_PARENTHESIS NUMBER COMMA metricExpr CLOSE_PARENTHESIS      { $$ = newFunctionExpr($5, OpFuncHistogramQuantile, mustNewLiteralExpr($3, false)) }
+    | TIME OPEN_PARENTHESIS CLOSE_PARENTHESIS                                            { $$ = newTimeExpr() }
+    ;
+
 filter:
       PIPE_MATCH                       { $$ = labels.MatchRegexp }
     | PIPE_EXACT                       { $$ = labels.MatchEqual }
@@ -257,10 +319,15 @@ matchers:
     ;
 
 matcher:
-      IDENTIFIER EQ STRING             { $$ = mustNewMatcher(labels.MatchEqual, $1, $3) }
-    | IDENTIFIER NEQ STRING            { $$ = mustNewMatcher(labels.MatchNotEqual, $1, $3) }
-    | IDENTIFIER RE STRING             { $$ = mustNewMatcher(labels.MatchRegexp, $1, $3) }
-    | IDENTIFIER NRE STRING            { $$ = mustNewMatcher(labels.MatchNotRegexp, $1, $3) }
+      IDENTIFIER EQ stringValue        { $$ = mustNewMatcher(labels.MatchEqual, $1, $3) }
+    | IDENTIFIER NEQ stringValue       { $$ = mustNewMatcher(labels.MatchNotEqual, $1, $3) }
+    | IDENTIFIER RE stringValue        { $$ = mustNewMatcher(labels.MatchRegexp, $1, $3) }
+    | IDENTIFIER NRE stringValue       { $$ = mustNewMatcher(labels.MatchNotRegexp, $1, $3) }
+    ;
+
+stringValue:
+      STRING    { $$ = $1 }
+    | PARAM     { $$ = $1 }
     ;
 
 pipelineExpr:
@@ -280,6 +347,15 @@ pipelineStage:
   | PIPE labelFormatExpr         { $$ = $2 }
   | PIPE dropLabelsExpr          { $$ = $2 }
   | PIPE keepLabelsExpr          { $$ = $2 }
+  | PIPE distinctFilterExpr      { $$ = $2 }
+  | PIPE dedupExpr               { $$ = $2 }
+  | PIPE limitByExpr             { $$ = $2 }
+  | PIPE unnestExpr              { $$ = $2 }
+  | PIPE csvParser               { $$ = $2 }
+  | PIPE kvParser                { $$ = $2 }
+  | PIPE xmlExpressionParser     { $$ = $2 }
+  | PIPE decodeExpr              { $$ = $2 }
+  | PIPE jsonUnescapeExpr        { $$ = $2 }
   ;
 
 filterOp:
@@ -287,15 +363,15 @@ filterOp:
   ;
 
 orFilter:
-    STRING                                              { $$ = newLineFilterExpr(labels.MatchEqual, "", $1) }
-  | filterOp OPEN_PARENTHESIS STRING CLOSE_PARENTHESIS	{ $$ = newLineFilterExpr(labels.MatchEqual, $1, $3) }
-  | STRING OR orFilter                                  { $$ = newOrLineFilter(newLineFilterExpr(labels.MatchEqual, "", $1), $3) }
+    stringValue                                              { $$ = newLineFilterExpr(labels.MatchEqual, "", $1) }
+  | filterOp OPEN_PARENTHESIS stringValue CLOSE_PARENTHESIS	{ $$ = newLineFilterExpr(labels.MatchEqual, $1, $3) }
+  | stringValue OR orFilter                                  { $$ = newOrLineFilter(newLineFilterExpr(labels.MatchEqual, "", $1), $3) }
   ;
 
 lineFilter:
-    filter STRING                                                   { $$ = newLineFilterExpr($1, "", $2) }
-  | filter filterOp OPEN_PARENTHESIS STRING CLOSE_PARENTHESIS       { $$ = newLineFilterExpr($1, $2, $4) }
-  | filter STRING OR orFilter                                       { $$ = newOrLineFilter(newLineFilterExpr($1, "", $2), $4) }
+    filter stringValue                                                   { $$ = newLineFilterExpr($1, "", $2) }
+  | filter filterOp OPEN_PARENTHESIS stringValue CLOSE_PARENTHESIS       { $$ = newLineFilterExpr($1, $2, $4) }
+  | filter stringValue OR orFilter                                       { $$ = newOrLineFilter(newLineFilterExpr($1, "", $2), $4) }
   ;
 
 lineFilters:
@@ -319,6 +395,30 @@ labelParser:
   | REGEXP STRING       { $$ = newLabelParserExpr(OpParserTypeRegexp, $2) }
   | UNPACK              { $$ = newLabelParserExpr(OpParserTypeUnpack, "") }
   | PATTERN STRING      { $$ = newLabelParserExpr(OpParserTypePattern, $2) }
+  | XML                 { $$ = newLabelParserExpr(OpParserTypeXML, "") }
+  ;
+
+xmlExpressionParser:
+    XML labelExtractionExpressionList { $$ = newXMLExpressionParser($2) }
+  ;
+
+parserOption:
+    IDENTIFIER EQ STRING { $$ = log.NewLabelExtractionExpr($1, $3) }
+  ;
+
+parserOptions:
+    parserOption                { $$ = []log.LabelExtractionExpr{$1} }
+  | parserOptions parserOption  { $$ = append($1, $2) }
+  ;
+
+csvParser:
+    CSV STRING                { $$ = newCSVParserExpr($2, nil) }
+  | CSV parserOptions STRING  { $$ = newCSVParserExpr($3, $2) }
+  ;
+
+kvParser:
+    KV                { $$ = newKVParserExpr(nil) }
+  | KV parserOptions  { $$ = newKVParserExpr($2) }
   ;
 
 jsonExpressionParser:
@@ -405,6 +505,11 @@ numberFilter:
     | IDENTIFIER NEQ NUMBER     { $$ = log.NewNumericLabelFilter(log.LabelFilterNotEqual, $1, mustNewFloat($3))}
     | IDENTIFIER EQ NUMBER      { $$ = log.NewNumericLabelFilter(log.LabelFilterEqual, $1, mustNewFloat($3))}
     | IDENTIFIER CMP_EQ NUMBER  { $$ = log.NewNumericLabelFilter(log.LabelFilterEqual, $1, mustNewFloat($3))}
+    | IDENTIFIER GT PARAM       { $$ = log.NewNumericLabelFilter(log.LabelFilterGreaterThan, $1, mustNewFloat($3))}
+    | IDENTIFIER GTE PARAM      { $$ = log.NewNumericLabelFilter(log.LabelFilterGreaterThanOrEqual, $1, mustNewFloat($3))}
+    | IDENTIFIER LT PARAM       { $$ = log.NewNumericLabelFilter(log.LabelFilterLesserThan, $1, mustNewFloat($3))}
+    | IDENTIFIER LTE PARAM      { $$ = log.NewNumericLabelFilter(log.LabelFilterLesserThanOrEqual, $1, mustNewFloat($3))}
+    | IDENTIFIER CMP_EQ PARAM   { $$ = log.NewNumericLabelFilter(log.LabelFilterEqual, $1, mustNewFloat($3))}
     ;
 
 dropLabel:
@@ -429,6 +534,38 @@ keepLabels:
 
 keepLabelsExpr: KEEP keepLabels { $$ = newKeepLabelsExpr($2) }
 
+distinctFilterExpr: DISTINCT labels { $$ = newDistinctFilterExpr($2) }
+
+dedupExpr:
+      DEDUP          { $$ = newDedupExpr(0) }
+    | DEDUP DURATION { $$ = newDedupExpr($2) }
+    ;
+
+limitByExpr:
+      LIMIT NUMBER BY OPEN_PARENTHESIS labels CLOSE_PARENTHESIS            { $$ = newLimitByExpr($2, $5, "") }
+    | LIMIT NUMBER BY OPEN_PARENTHESIS labels CLOSE_PARENTHESIS IDENTIFIER { $$ = newLimitByExpr($2, $5, $7) }
+    ;
+
+unnestExpr:
+      UNNEST IDENTIFIER { $$ = newUnnestExpr($2) }
+    | UNNEST STRING     { $$ = newUnnestExpr($2) }
+    ;
+
+encodings:
+      IDENTIFIER                 { $$ = []string{ $1 } }
+    | encodings ADD IDENTIFIER   { $$ = append($1, $3) }
+    ;
+
+decodeExpr:
+      DECODE encodings             { $$ = newDecodeExpr($2, "") }
+    | DECODE encodings IDENTIFIER  { $$ = newDecodeExpr($2, $3) }
+    ;
+
+jsonUnescapeExpr:
+      JSON_UNESCAPE IDENTIFIER { $$ = newJSONUnescapeExpr($2) }
+    | JSON_UNESCAPE STRING     { $$ = newJSONUnescapeExpr($2) }
+    ;
+
 // Operator precedence only works if each of these is listed separately.
 binOpExpr:
          expr OR binOpModifier expr          { $$ = mustNewBinOpExpr("or", $3, $1, $4) }
@@ -522,6 +659,7 @@ literalExpr:
            NUMBER         { $$ = mustNewLiteralExpr( $1, false ) }
            | ADD NUMBER   { $$ = mustNewLiteralExpr( $2, false ) }
            | SUB NUMBER   { $$ = mustNewLiteralExpr( $2, true ) }
+           | PARAM        { $$ = mustNewLiteralExpr( $1, false ) }
            ;
 
 vectorExpr:
@@ -543,6 +681,21 @@ vectorOp:
       | TOPK    { $$ = OpTypeTopK }
       | SORT    { $$ = OpTypeSort }
       | SORT_DESC    { $$ = OpTypeSortDesc }
+      | GROUP     { $$ = OpTypeGroup }
+      | QUANTILE  { $$ = OpTypeQuantile }
+      ;
+
+functionOp:
+        ABS         { $$ = OpFuncAbs }
+      | CEIL        { $$ = OpFuncCeil }
+      | FLOOR       { $$ = OpFuncFloor }
+      | LN          { $$ = OpFuncLn }
+      | ROUND       { $$ = OpFuncRound }
+      | CLAMP_MIN   { $$ = OpFuncClampMin }
+      | CLAMP_MAX   { $$ = OpFuncClampMax }
+      | TIMESTAMP   { $$ = OpFuncTimestamp }
+      | SCALAR      { $$ = OpFuncScalar }
+      | ABSENT      { $$ = OpFuncAbsent }
       ;
 
 rangeOp:
@@ -561,6 +714,8 @@ rangeOp:
     | FIRST_OVER_TIME    { $$ = OpRangeTypeFirst }
     | LAST_OVER_TIME     { $$ = OpRangeTypeLast }
     | ABSENT_OVER_TIME   { $$ = OpRangeTypeAbsent }
+    | DELTA              { $$ = OpRangeTypeDelta }
+    | DERIV              { $$ = OpRangeTypeDeriv }
     ;
 
 offsetExpr:
@@ -19,6 +19,7 @@ type exprSymType struct {
 	Labels                []string
 	LogExpr               LogSelectorExpr
 	LogRangeExpr          *LogRange
+	SubqueryExpr          *SubqueryExpr
 	Matcher               *labels.Matcher
 	Matchers              []*labels.Matcher
 	RangeAggregationExpr  SampleExpr
@@ -33,10 +34,14 @@ type exprSymType struct {
 	FilterOp              string
 	BinOpExpr             SampleExpr
 	LabelReplaceExpr      SampleExpr
+	LabelJoinExpr         SampleExpr
+	FunctionExpr          SampleExpr
+	FunctionOp            string
 	binOp                 string
 	bytes                 uint64
 	str                   string
 	duration              time.Duration
+	subqueryRange         subqueryRange
 	LiteralExpr           *LiteralExpr
 	BinOpModifier         *BinOpOptions
 	BoolModifier          *BinOpOptions
@@ -81,90 +86,121 @@ const IDENTIFIER = 57347
 const STRING = 57348
 const NUMBER = 57349
 const PARSER_FLAG = 57350
-const DURATION = 57351
-const RANGE = 57352
-const MATCHERS = 57353
-const LABELS = 57354
-const EQ = 57355
-const RE = 57356
-const NRE = 57357
-const OPEN_BRACE = 57358
-const CLOSE_BRACE = 57359
-const OPEN_BRACKET = 57360
-const CLOSE_BRACKET = 57361
-const COMMA = 57362
-const DOT = 57363
-const PIPE_MATCH = 57364
-const PIPE_EXACT = 57365
-const OPEN_PARENTHESIS = 57366
-const CLOSE_PARENTHESIS = 57367
-const BY = 57368
-const WITHOUT = 57369
-const COUNT_OVER_TIME = 57370
-const RATE = 57371
-const RATE_COUNTER = 57372
-const SUM = 57373
-const SORT = 57374
-const SORT_DESC = 57375
-const AVG = 57376
-const MAX = 57377
-const MIN = 57378
-const COUNT = 57379
-const STDDEV = 57380
-const STDVAR = 57381
-const BOTTOMK = 57382
-const TOPK = 57383
-const BYTES_OVER_TIME = 57384
-const BYTES_RATE = 57385
-const BOOL = 57386
-const JSON = 57387
-const REGEXP = 57388
-const LOGFMT = 57389
-const PIPE = 57390
-const LINE_FMT = 57391
-const LABEL_FMT = 57392
-const UNWRAP = 57393
-const AVG_OVER_TIME = 57394
-const SUM_OVER_TIME = 57395
-const MIN_OVER_TIME = 57396
-const MAX_OVER_TIME = 57397
-const STDVAR_OVER_TIME = 57398
-const STDDEV_OVER_TIME = 57399
-const QUANTILE_OVER_TIME = 57400
-const BYTES_CONV = 57401
-const DURATION_CONV = 57402
-const DURATION_SECONDS_CONV = 57403
-const FIRST_OVER_TIME = 57404
-const LAST_OVER_TIME = 57405
-const ABSENT_OVER_TIME = 57406
-const VECTOR = 57407
-const LABEL_REPLACE = 57408
-const UNPACK = 57409
-const OFFSET = 57410
-const PATTERN = 57411
-const IP = 57412
-const ON = 57413
-const IGNORING = 57414
-const GROUP_LEFT = 57415
-const GROUP_RIGHT = 57416
-const DECOLORIZE = 57417
-const DROP = 57418
-const KEEP = 57419
-const OR = 57420
-const AND = 57421
-const UNLESS = 57422
-const CMP_EQ = 57423
-const NEQ = 57424
-const LT = 57425
-const LTE = 57426
-const GT = 57427
-const GTE = 57428
-const ADD = 57429
-const SUB = 57430
-const MUL = 57431
-const DIV = 57432
-const MOD = 57433
-const POW = 57434
+const PARAM = 57351
+const DURATION = 57352
+const RANGE = 57353
+const SUBQUERY_RANGE = 57354
+const MATCHERS = 57355
+const LABELS = 57356
+const EQ = 57357
+const RE = 57358
+const NRE = 57359
+const OPEN_BRACE = 57360
+const CLOSE_BRACE = 57361
+const OPEN_BRACKET = 57362
+const CLOSE_BRACKET = 57363
+const COMMA = 57364
+const DOT = 57365
+const PIPE_MATCH = 57366
+const PIPE_EXACT = 57367
+const OPEN_PARENTHESIS = 57368
+const CLOSE_PARENTHESIS = 57369
+const BY = 57370
+const WITHOUT = 57371
+const COUNT_OVER_TIME = 57372
+const RATE = 57373
+const RATE_COUNTER = 57374
+const SUM = 57375
+const SORT = 57376
+const SORT_DESC = 57377
+const AVG = 57378
+const MAX = 57379
+const MIN = 57380
+const COUNT = 57381
+const STDDEV = 57382
+const STDVAR = 57383
+const BOTTOMK = 57384
+const TOPK = 57385
+const BYTES_OVER_TIME = 57386
+const BYTES_RATE = 57387
+const BOOL = 57388
+const JSON = 57389
+const REGEXP = 57390
+const LOGFMT = 57391
+const PIPE = 57392
+const LINE_FMT = 57393
+const LABEL_FMT = 57394
+const UNWRAP = 57395
+const AVG_OVER_TIME = 57396
+const SUM_OVER_TIME = 57397
+const MIN_OVER_TIME = 57398
+const MAX_OVER_TIME = 57399
+const STDVAR_OVER_TIME = 57400
+const STDDEV_OVER_TIME = 57401
+const QUANTILE_OVER_TIME = 57402
+const BYTES_CONV = 57403
+const DURATION_CONV = 57404
+const DURATION_SECONDS_CONV = 57405
+const FIRST_OVER_TIME = 57406
+const LAST_OVER_TIME = 57407
+const ABSENT_OVER_TIME = 57408
+const VECTOR = 57409
+const LABEL_REPLACE = 57410
+const UNPACK = 57411
+const OFFSET = 57412
+const PATTERN = 57413
+const IP = 57414
+const ON = 57415
+const IGNORING = 57416
+const GROUP_LEFT = 57417
+const GROUP_RIGHT = 57418
+const DECOLORIZE = 57419
+const DROP = 57420
+const KEEP = 57421
+const ABS = 57422
+const CEIL = 57423
+const FLOOR = 57424
+const LN = 57425
+const ROUND = 57426
+const CLAMP_MIN = 57427
+const CLAMP_MAX = 57428
+const TIMESTAMP = 57429
+const SCALAR = 57430
+const ABSENT = 57431
+const HISTOGRAM_QUANTILE = 57432
+const TIME = 57433
+const LABEL_JOIN = 57434
+const GROUP = 57435
+const QUANTILE = 57436
+const COUNT_VALUES = 57437
+const DELTA = 57438
+const DERIV = 57439
+const PREDICT_LINEAR = 57440
+const DISTINCT = 57441
+const DEDUP = 57442
+const UNNEST = 57443
+const CSV = 57444
+const XML = 57445
+const KV = 57446
+const DECODE = 57447
+const JSON_UNESCAPE = 57448
+const LIMIT = 57449
+const JOIN = 57450
+const OR = 57451
+const AND = 57452
+const UNLESS = 57453
+const CMP_EQ = 57454
+const NEQ = 57455
+const LT = 57456
+const LTE = 57457
+const GT = 57458
+const GTE = 57459
+const ADD = 57460
+const SUB = 57461
+const MUL = 57462
+const DIV = 57463
+const MOD = 57464
+const POW = 57465
 
 var exprToknames = [...]string{
 	"$end",
@@ -175,8 +211,10 @@ var exprToknames = [...]string{
 	"STRING",
 	"NUMBER",
 	"PARSER_FLAG",
+	"PARAM",
 	"DURATION",
 	"RANGE",
+	"SUBQUERY_RANGE",
 	"MATCHERS",
 	"LABELS",
 	"EQ",
@@ -244,6 +282,35 @@ var exprToknames = [...]string{
 	"DECOLORIZE",
 	"DROP",
 	"KEEP",
+	"ABS",
+	"CEIL",
+	"FLOOR",
+	"LN",
+	"ROUND",
+	"CLAMP_MIN",
+	"CLAMP_MAX",
+	"TIMESTAMP",
+	"SCALAR",
+	"ABSENT",
+	"HISTOGRAM_QUANTILE",
+	"TIME",
+	"LABEL_JOIN",
+	"GROUP",
+	"QUANTILE",
+	"COUNT_VALUES",
+	"DELTA",
+	"DERIV",
+	"PREDICT_LINEAR",
+	"DISTINCT",
+	"DEDUP",
+	"UNNEST",
+	"CSV",
+	"XML",
+	"KV",
+	"DECODE",
+	"JSON_UNESCAPE",
+	"LIMIT",
+	"JOIN",
 	"OR",
 	"AND",
 	"UNLESS",
@@ -260,6 +327,7 @@ var exprToknames = [...]string{
 	"MOD",
 	"POW",
 }
+
 var exprStatenames = [...]string{}
 
 const exprEofCode = 1
@@ -267,7 +335,7 @@ const exprErrCode = 2
 const exprInitialStackSize = 16
 
 
-var exprExca = [...]int{
+var exprExca = [...]int8{
 	-1, 1,
 	1, -1,
 	-2, 0,
@@ -275,264 +343,371 @@ var exprExca = [...]int{
 
 const exprPrivate = 57344
 
-const exprLast = 592
-
-var exprAct = [...]int{
-
-	287, 226, 82, 4, 212, 64, 180, 124, 202, 187,
-	73, 198, 195, 63, 235, 5, 150, 185, 75, 2,
-	56, 78, 48, 49, 50, 57, 58, 61, 62, 59,
-	60, 51, 52, 53, 54, 55, 56, 49, 50, 57,
-	58, 61, 62, 59, 60, 51, 52, 53, 54, 55,
-	56, 57, 58, 61, 62, 59, 60, 51, 52, 53,
-	54, 55, 56, 51, 52, 53, 54, 55, 56, 107,
-	146, 148, 149, 113, 53, 54, 55, 56, 205, 148,
-	149, 281, 215, 137, 290, 154, 164, 165, 213, 293,
-	138, 159, 67, 293, 71, 214, 152, 295, 71, 162,
-	163, 69, 70, 292, 345, 69, 70, 364, 161, 364,
-	92, 384, 166, 167, 168, 169, 170, 171, 172, 173,
-	174, 175, 176, 177, 178, 179, 71, 228, 379, 290,
-	337, 228, 304, 69, 70, 192, 134, 354, 189, 147,
-	200, 204, 83, 84, 372, 367, 211, 206, 209, 210,
-	207, 208, 140, 217, 140, 128, 134, 291, 108, 228,
-	233, 72, 371, 337, 139, 72, 227, 291, 292, 229,
-	230, 182, 344, 238, 222, 128, 120, 121, 119, 225,
-	129, 131, 295, 81, 71, 83, 84, 369, 246, 247,
-	248, 69, 70, 72, 296, 292, 222, 329, 122, 225,
-	123, 292, 250, 357, 71, 292, 130, 132, 133, 347,
-	304, 69, 70, 304, 71, 353, 71, 228, 352, 299,
-	283, 69, 70, 69, 70, 134, 285, 288, 328, 294,
-	181, 297, 237, 107, 300, 113, 301, 228, 71, 289,
-	152, 286, 237, 298, 128, 69, 70, 66, 304, 228,
-	222, 72, 314, 351, 302, 134, 237, 308, 310, 313,
-	315, 316, 312, 241, 200, 204, 323, 318, 322, 290,
-	182, 72, 231, 223, 128, 253, 311, 134, 134, 304,
-	338, 72, 237, 72, 306, 304, 326, 142, 141, 330,
-	305, 332, 334, 182, 336, 107, 128, 128, 361, 335,
-	346, 331, 309, 237, 107, 72, 264, 348, 219, 265,
-	237, 263, 134, 325, 324, 282, 13, 120, 121, 119,
-	245, 129, 131, 239, 153, 244, 382, 182, 183, 181,
-	236, 128, 358, 359, 340, 341, 342, 107, 360, 122,
-	243, 123, 242, 216, 362, 363, 158, 130, 132, 133,
-	368, 183, 181, 151, 157, 260, 156, 218, 261, 16,
-	259, 88, 13, 87, 374, 80, 375, 376, 13, 378,
-	153, 255, 262, 350, 251, 303, 6, 257, 380, 256,
-	21, 22, 23, 36, 45, 46, 37, 39, 40, 38,
-	41, 42, 43, 44, 24, 25, 254, 240, 232, 224,
-	252, 79, 234, 377, 26, 27, 28, 29, 30, 31,
-	32, 13, 366, 77, 33, 34, 35, 47, 19, 6,
-	365, 258, 343, 21, 22, 23, 36, 45, 46, 37,
-	39, 40, 38, 41, 42, 43, 44, 24, 25, 17,
-	18, 279, 333, 160, 280, 155, 278, 26, 27, 28,
-	29, 30, 31, 32, 13, 89, 86, 33, 34, 35,
-	47, 19, 6, 320, 321, 383, 21, 22, 23, 36,
-	45, 46, 37, 39, 40, 38, 41, 42, 43, 44,
-	24, 25, 17, 18, 276, 85, 373, 277, 381, 275,
-	26, 27, 28, 29, 30, 31, 32, 370, 356, 355,
-	33, 34, 35, 47, 19, 93, 94, 95, 96, 97,
-	98, 99, 100, 101, 102, 103, 104, 105, 106, 144,
-	188, 125, 327, 249, 273, 17, 18, 274, 270, 272,
-	349, 271, 317, 269, 143, 267, 3, 145, 268, 188,
-	266, 319, 186, 74, 196, 126, 307, 284, 221, 220,
-	219, 218, 193, 191, 190, 203, 199, 188, 79, 196,
-	111, 112, 194, 116, 201, 118, 197, 117, 115, 114,
-	184, 65, 135, 127, 136, 109, 110, 91, 90, 11,
-	10, 9, 20, 12, 15, 8, 339, 14, 7, 76,
-	68, 1,
+const exprLast = 1076
+
+var exprAct = [...]int16{
+	395, 311, 109, 89, 271, 294, 245, 293, 268, 88,
+	4, 167, 261, 264, 11, 279, 250, 6, 98, 252,
+	278, 100, 2, 205, 113, 207, 81, 356, 385, 104,
+	3, 76, 77, 78, 79, 80, 81, 296, 99, 73,
+	74, 75, 82, 83, 86, 87, 84, 85, 76, 77,
+	78, 79, 80, 81, 74, 75, 82, 83, 86, 87,
+	84, 85, 76, 77, 78, 79, 80, 81, 82, 83,
+	86, 87, 84, 85, 76, 77, 78, 79, 80, 81,
+	78, 79, 80, 81, 188, 398, 22, 295, 25, 229,
+	230, 401, 140, 286, 203, 204, 400, 393, 146, 189,
+	227, 228, 393, 96, 201, 203, 204, 493, 96, 125,
+	94, 95, 305, 462, 92, 94, 95, 210, 210, 213,
+	110, 111, 305, 191, 208, 208, 192, 220, 221, 222,
+	351, 211, 196, 212, 524, 96, 312, 447, 96, 523,
+	355, 312, 94, 95, 226, 94, 95, 405, 231, 232,
+	233, 234, 235, 236, 237, 238, 239, 240, 241, 242,
+	243, 244, 310, 185, 527, 185, 521, 363, 96, 191,
+	364, 312, 192, 362, 272, 94, 95, 516, 258, 254,
+	247, 190, 247, 257, 171, 345, 171, 266, 270, 193,
+	292, 287, 290, 291, 288, 289, 427, 23, 24, 97,
+	310, 312, 202, 281, 97, 141, 96, 301, 302, 303,
+	304, 450, 300, 94, 95, 457, 403, 399, 309, 513,
+	98, 322, 324, 504, 512, 493, 320, 313, 299, 503,
+	96, 97, 499, 314, 97, 361, 96, 94, 95, 312,
+	99, 450, 350, 94, 95, 398, 359, 502, 191, 360,
+	400, 192, 358, 338, 339, 340, 400, 490, 497, 399,
+	185, 483, 475, 312, 97, 272, 342, 248, 246, 91,
+	246, 459, 460, 461, 305, 465, 112, 247, 110, 111,
+	400, 171, 108, 398, 110, 111, 272, 425, 474, 185,
+	350, 122, 301, 302, 352, 479, 350, 352, 400, 306,
+	350, 478, 97, 388, 387, 477, 247, 470, 424, 390,
+	171, 394, 396, 140, 357, 404, 406, 350, 210, 146,
+	397, 409, 476, 402, 468, 208, 97, 272, 467, 389,
+	410, 391, 97, 392, 272, 272, 466, 415, 448, 421,
+	423, 426, 428, 420, 445, 350, 419, 350, 331, 422,
+	414, 411, 413, 330, 195, 333, 325, 323, 436, 431,
+	266, 270, 435, 429, 248, 246, 126, 127, 128, 129,
+	130, 131, 132, 133, 134, 135, 136, 137, 138, 139,
+	17, 185, 318, 308, 224, 194, 488, 442, 197, 441,
+	438, 386, 444, 443, 449, 337, 336, 451, 335, 453,
+	455, 140, 171, 334, 463, 452, 140, 456, 297, 219,
+	217, 216, 215, 121, 120, 119, 118, 117, 116, 107,
+	106, 101, 199, 469, 305, 522, 511, 471, 505, 473,
+	472, 446, 343, 416, 412, 350, 349, 348, 346, 198,
+	332, 329, 200, 480, 316, 328, 326, 319, 317, 307,
+	485, 486, 298, 105, 487, 354, 140, 347, 344, 315,
+	508, 494, 489, 464, 515, 491, 492, 103, 454, 495,
+	273, 496, 382, 498, 408, 383, 378, 384, 381, 379,
+	374, 380, 377, 375, 407, 376, 373, 22, 274, 25,
+	507, 370, 225, 509, 371, 510, 372, 369, 17, 366,
+	191, 514, 367, 192, 368, 365, 7, 433, 434, 517,
+	32, 33, 34, 49, 58, 59, 50, 52, 53, 51,
+	54, 55, 56, 57, 35, 36, 253, 253, 506, 341,
+	251, 280, 353, 526, 37, 38, 39, 40, 41, 42,
+	43, 284, 285, 525, 44, 45, 46, 72, 26, 280,
+	277, 275, 276, 168, 223, 115, 114, 520, 518, 501,
+	62, 63, 64, 65, 66, 67, 68, 69, 70, 71,
+	29, 30, 27, 60, 61, 21, 47, 48, 19, 500,
+	272, 22, 484, 25, 482, 481, 439, 432, 16, 430,
+	262, 440, 17, 418, 417, 327, 259, 256, 23, 24,
+	209, 255, 218, 437, 32, 33, 34, 49, 58, 59,
+	50, 52, 53, 51, 54, 55, 56, 57, 35, 36,
+	269, 265, 253, 280, 105, 283, 262, 169, 37, 38,
+	39, 40, 41, 42, 43, 144, 145, 260, 44, 45,
+	46, 72, 26, 149, 154, 153, 282, 160, 159, 158,
+	157, 156, 155, 152, 62, 63, 64, 65, 66, 67,
+	68, 69, 70, 71, 29, 30, 27, 60, 61, 21,
+	47, 48, 19, 267, 151, 321, 263, 25, 150, 148,
+	147, 249, 16, 90, 186, 170, 17, 187, 142, 143,
+	124, 123, 23, 24, 7, 28, 14, 519, 32, 33,
+	34, 49, 58, 59, 50, 52, 53, 51, 54, 55,
+	56, 57, 35, 36, 13, 12, 10, 31, 15, 20,
+	9, 458, 37, 38, 39, 40, 41, 42, 43, 18,
+	8, 102, 44, 45, 46, 72, 26, 93, 5, 1,
+	0, 0, 0, 0, 0, 0, 0, 0, 62, 63,
+	64, 65, 66, 67, 68, 69, 70, 71, 29, 30,
+	27, 60, 61, 21, 47, 48, 19, 0, 0, 214,
+	0, 25, 0, 0, 0, 0, 16, 0, 0, 0,
+	17, 0, 0, 0, 0, 0, 23, 24, 7, 0,
+	0, 0, 32, 33, 34, 49, 58, 59, 50, 52,
+	53, 51, 54, 55, 56, 57, 35, 36, 0, 0,
+	0, 0, 0, 0, 0, 0, 37, 38, 39, 40,
+	41, 42, 43, 0, 0, 0, 44, 45, 46, 72,
+	26, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 62, 63, 64, 65, 66, 67, 68, 69,
+	70, 71, 29, 30, 27, 60, 61, 21, 47, 48,
+	19, 0, 0, 206, 0, 25, 0, 0, 0, 0,
+	16, 0, 0, 0, 17, 0, 0, 0, 0, 0,
+	23, 24, 209, 0, 0, 0, 32, 33, 34, 49,
+	58, 59, 50, 52, 53, 51, 54, 55, 56, 57,
+	35, 36, 0, 0, 0, 0, 0, 0, 0, 0,
+	37, 38, 39, 40, 41, 42, 43, 0, 0, 0,
+	44, 45, 46, 72, 26, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 185, 0, 62, 63, 64, 65,
+	66, 67, 68, 69, 70, 71, 29, 30, 27, 60,
+	61, 21, 47, 48, 19, 171, 0, 0, 0, 0,
+	0, 0, 0, 0, 16, 0, 0, 0, 0, 0,
+	0, 0, 0, 185, 23, 24, 162, 163, 161, 0,
+	172, 174, 401, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 171, 0, 0, 0, 164, 0,
+	165, 0, 0, 0, 0, 0, 173, 175, 176, 0,
+	0, 0, 0, 0, 0, 162, 163, 161, 0, 172,
+	174, 0, 0, 0, 0, 0, 0, 0, 177, 178,
+	180, 181, 166, 182, 183, 184, 179, 164, 0, 165,
+	0, 0, 0, 0, 0, 173, 175, 176, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 177, 178, 180,
+	181, 166, 182, 183, 184, 179,
 }
-var exprPact = [...]int{
 
-	352, -1000, -56, -1000, -1000, 199, 352, -1000, -1000, -1000,
-	-1000, -1000, -1000, 396, 341, 159, -1000, 478, 449, 339,
-	337, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
+var exprPact = [...]int16{
+	480, -1000, -70, -1000, -1000, -1000, 219, 480, -1000, -1000,
+	-1000, -1000, -1000, -1000, -1000, -1000, 395, 448, 394, 393,
+	256, 250, -1000, 549, 548, -1000, 392, 391, 390, 389,
+	388, 387, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
+	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
+	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
 	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
-	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, 66, 66,
-	66, 66, 66, 66, 66, 66, 66, 66, 66, 66,
-	66, 66, 66, 199, -1000, 223, 272, 5, 84, -1000,
-	-1000, -1000, -1000, 263, 262, -56, 517, -1000, -1000, 57,
-	346, 438, 332, 330, 322, -1000, -1000, 352, 436, 352,
-	28, 13, -1000, 352, 352, 352, 352, 352, 352, 352,
-	352, 352, 352, 352, 352, 352, 352, -1000, -1000, -1000,
-	-1000, -1000, -1000, 273, -1000, -1000, -1000, -1000, -1000, 534,
-	552, 548, -1000, 547, -1000, -1000, -1000, -1000, 220, 546,
-	-1000, 554, 551, 550, 65, -1000, -1000, 82, 4, 319,
-	-1000, -1000, -1000, -1000, -1000, 553, 545, 544, 543, 542,
-	248, 379, 189, 300, 247, 378, 395, 305, 298, 377,
-	238, -42, 318, 316, 301, 296, -30, -30, -15, -15,
-	-72, -72, -72, -72, -24, -24, -24, -24, -24, -24,
-	273, 220, 220, 220, 515, 354, -1000, -1000, 387, 354,
-	-1000, -1000, 250, -1000, 376, -1000, 358, 359, -1000, 57,
-	-1000, 357, -1000, 57, -1000, 351, 302, 531, 524, 520,
-	480, 437, -1000, 3, 291, 82, 541, -1000, -1000, -1000,
-	-1000, -1000, -1000, 116, 300, 201, 157, 83, 131, 169,
-	194, 116, 352, 229, 355, 265, -1000, -1000, 259, -1000,
-	540, -1000, 277, 251, 237, 227, 307, 273, 151, -1000,
-	354, 552, 526, -1000, 539, 458, 551, 550, 290, -1000,
-	-1000, -1000, 289, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
+	-1000, -1000, -1000, 63, 63, 63, 63, 63, 63, 63,
+	63, 63, 63, 63, 63, 63, 63, 63, 219, -1000,
+	118, 968, -25, 117, -1000, -1000, -1000, -1000, 358, 327,
+	-70, 362, 420, -1000, -1000, 89, 856, 574, 762, 386,
+	385, 384, 596, 383, -1000, -1000, 480, 480, 480, 547,
+	357, 485, 480, 27, 14, -1000, 480, 480, 480, 480,
+	480, 480, 480, 480, 480, 480, 480, 480, 480, 480,
+	-1000, -1000, -1000, -1000, -1000, -1000, 255, -1000, -1000, -1000,
 	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
-	-1000, 82, 516, -1000, 203, -1000, 172, 111, 55, 111,
-	433, 16, 220, 16, 153, 275, 412, 147, 79, -1000,
-	-1000, 184, -1000, 352, 525, -1000, -1000, 353, 228, -1000,
-	193, -1000, -1000, 190, -1000, 112, -1000, -1000, -1000, -1000,
-	-1000, -1000, -1000, -1000, 493, 492, -1000, 178, -1000, 116,
-	55, 111, 55, -1000, -1000, 273, -1000, 16, -1000, 274,
-	-1000, -1000, -1000, 61, 410, 402, 120, 116, 162, -1000,
-	491, -1000, -1000, -1000, -1000, 137, 119, -1000, -1000, 55,
-	-1000, 481, 59, 55, 46, 16, 16, 393, -1000, -1000,
-	349, -1000, -1000, 103, 55, -1000, -1000, 16, 482, -1000,
-	-1000, 306, 459, 86, -1000,
+	-1000, 522, 617, 595, -1000, 591, 617, -1000, -1000, -1000,
+	-1000, 376, 590, -1000, 621, 616, 615, 575, 460, 481,
+	546, 544, 618, 620, 536, 78, -1000, -1000, 117, -72,
+	382, -1000, -1000, -1000, -1000, -1000, 430, 362, -1000, -1000,
+	619, 494, 494, 494, 494, 272, 427, 356, 151, 574,
+	447, 422, 426, 355, 425, 668, 330, 329, 424, 589,
+	423, 419, 326, 418, -1000, 328, -56, 377, 372, 370,
+	369, -44, -44, -40, -40, -97, -97, -97, -97, -87,
+	-87, -87, -87, -87, -87, 255, 376, 376, 376, 521,
+	410, -1000, -1000, 443, 410, -1000, -1000, 410, 158, -1000,
+	416, -1000, 442, 415, -1000, 89, -1000, 414, -1000, 89,
+	-1000, 413, -1000, -1000, 102, -1000, -1000, -1000, 526, -1000,
+	440, 618, 22, -1000, -1000, -1000, 242, 163, 495, 487,
+	476, 472, 468, -1000, -81, 365, 117, 494, 362, 327,
+	-1000, -1000, -1000, -1000, -1000, -1000, 92, 574, -1000, 91,
+	213, 206, 929, 189, 120, 15, 477, 467, 92, 480,
+	324, 412, 325, -1000, 323, -1000, 480, 411, 588, 587,
+	-1000, 79, 480, -1000, 322, 281, 260, 169, 284, 255,
+	160, -1000, 410, 617, 583, -1000, 585, 502, 616, 615,
+	598, 364, -1000, -1000, 580, 586, -1000, 363, -1000, -1000,
+	-1000, 361, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
+	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
+	-1000, -1000, -1000, -1000, -1000, 117, 494, -1000, 317, 409,
+	-1000, 110, 311, 15, 200, 121, 46, 121, 458, 15,
+	376, 210, 86, 452, 248, -1000, -1000, 309, 301, -1000,
+	297, -1000, 480, -1000, -1000, 280, 480, 408, 407, 261,
+	235, 295, -1000, 278, -1000, -1000, 274, -1000, 268, -1000,
+	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, 575, -1000,
+	-1000, 579, 578, -1000, 234, -1000, 576, 92, -1000, -1000,
+	15, 46, 121, 46, -1000, -1000, 255, -1000, 360, -1000,
+	-1000, -1000, 451, 230, 175, 450, 92, -1000, 92, 231,
+	92, 205, 573, 553, -1000, -1000, -1000, -1000, -1000, -1000,
+	220, 202, 196, -1000, 406, -1000, -1000, 46, 523, 15,
+	449, 57, 46, 38, 15, -1000, -1000, -1000, -1000, -1000,
+	404, 197, 496, -1000, -1000, 454, 150, -1000, 15, 46,
+	-1000, 552, -1000, 551, -1000, 139, -1000, -1000, 403, 112,
+	-1000, -1000, 537, -1000, 527, 137, -1000, -1000,
 }
-var exprPgo = [...]int{
-
-	0, 591, 18, 590, 2, 14, 536, 3, 16, 7,
-	589, 588, 587, 586, 15, 585, 584, 583, 582, 95,
-	581, 580, 579, 455, 578, 577, 576, 575, 13, 5,
-	574, 573, 572, 6, 571, 92, 4, 570, 569, 568,
-	567, 566, 11, 565, 564, 8, 563, 12, 562, 9,
-	17, 561, 560, 1, 545, 521, 0,
+
+var exprPgo = [...]int16{
+	0, 739, 21, 738, 737, 2, 4, 30, 10, 23,
+	25, 11, 731, 730, 729, 721, 17, 720, 719, 718,
+	717, 87, 716, 14, 715, 714, 697, 696, 695, 291,
+	691, 690, 689, 688, 9, 3, 687, 685, 684, 6,
+	683, 114, 7, 681, 680, 679, 678, 676, 13, 674,
+	673, 8, 653, 652, 651, 650, 649, 648, 647, 646,
+	15, 20, 645, 644, 643, 12, 637, 19, 16, 636,
+	635, 1, 627, 553, 0, 5,
 }
-var exprR1 = [...]int{
-
-	0, 1, 2, 2, 7, 7, 7, 7, 7, 7,
-	7, 6, 6, 6, 8, 8, 8, 8, 8, 8,
-	8, 8, 8, 8, 8, 8, 8, 8, 8, 8,
-	8, 8, 8, 8, 8, 8, 8, 8, 8, 8,
-	53, 53, 53, 13, 13, 13, 11, 11, 11, 11,
-	15, 15, 15, 15, 15, 15, 22, 3, 3, 3,
-	3, 14, 14, 14, 10, 10, 9, 9, 9, 9,
-	28, 28, 29, 29, 29, 29, 29, 29, 29, 29,
-	29, 29, 29, 19, 36, 36, 36, 35, 35, 35,
-	34, 34, 34, 37, 37, 27, 27, 26, 26, 26,
-	26, 52, 51, 51, 38, 39, 47, 47, 48, 48,
-	48, 46, 33, 33, 33, 33, 33, 33, 33, 33,
-	33, 49, 49, 50, 50, 55, 55, 54, 54, 32,
-	32, 32, 32, 32, 32, 32, 30, 30, 30, 30,
-	30, 30, 30, 31, 31, 31, 31, 31, 31, 31,
-	42, 42, 41, 41, 40, 45, 45, 44, 44, 43,
-	20, 20, 20, 20, 20, 20, 20, 20, 20, 20,
-	20, 20, 20, 20, 20, 24, 24, 25, 25, 25,
-	25, 23, 23, 23, 23, 23, 23, 23, 23, 21,
-	21, 21, 17, 18, 16, 16, 16, 16, 16, 16,
-	16, 16, 16, 16, 16, 12, 12, 12, 12, 12,
-	12, 12, 12, 12, 12, 12, 12, 12, 12, 12,
-	56, 5, 5, 4, 4, 4, 4,
+
+var exprR1 = [...]int8{
+	0, 1, 2, 2, 2, 3, 8, 8, 8, 8,
+	8, 8, 8, 8, 8, 7, 7, 7, 9, 9,
+	9, 9, 9, 9, 9, 9, 9, 9, 9, 9,
+	9, 9, 9, 9, 9, 9, 9, 9, 9, 9,
+	9, 9, 9, 9, 10, 10, 71, 71, 71, 15,
+	15, 15, 13, 13, 13, 13, 13, 13, 13, 13,
+	13, 17, 17, 17, 17, 17, 17, 17, 17, 17,
+	24, 25, 25, 26, 26, 27, 27, 27, 27, 4,
+	4, 4, 4, 16, 16, 16, 12, 12, 11, 11,
+	11, 11, 75, 75, 34, 34, 35, 35, 35, 35,
+	35, 35, 35, 35, 35, 35, 35, 35, 35, 35,
+	35, 35, 35, 35, 35, 35, 21, 42, 42, 42,
+	41, 41, 41, 40, 40, 40, 43, 43, 33, 33,
+	32, 32, 32, 32, 32, 56, 60, 61, 61, 54,
+	54, 55, 55, 70, 69, 69, 44, 45, 65, 65,
+	66, 66, 66, 64, 39, 39, 39, 39, 39, 39,
+	39, 39, 39, 67, 67, 68, 68, 73, 73, 72,
+	72, 38, 38, 38, 38, 38, 38, 38, 36, 36,
+	36, 36, 36, 36, 36, 37, 37, 37, 37, 37,
+	37, 37, 37, 37, 37, 37, 37, 48, 48, 47,
+	47, 46, 51, 51, 50, 50, 49, 52, 62, 62,
+	63, 63, 53, 53, 59, 59, 57, 57, 58, 58,
+	22, 22, 22, 22, 22, 22, 22, 22, 22, 22,
+	22, 22, 22, 22, 22, 30, 30, 31, 31, 31,
+	31, 29, 29, 29, 29, 29, 29, 29, 29, 23,
+	23, 23, 23, 19, 20, 18, 18, 18, 18, 18,
+	18, 18, 18, 18, 18, 18, 18, 18, 28, 28,
+	28, 28, 28, 28, 28, 28, 28, 28, 14, 14,
+	14, 14, 14, 14, 14, 14, 14, 14, 14, 14,
+	14, 14, 14, 14, 14, 74, 6, 6, 5, 5,
+	5, 5,
 }
-var exprR2 = [...]int{
-
-	0, 1, 1, 1, 1, 1, 1, 1, 1, 1,
-	3, 1, 2, 3, 2, 3, 4, 5, 3, 4,
-	5, 6, 3, 4, 5, 6, 3, 4, 5, 6,
-	4, 5, 6, 7, 3, 4, 4, 5, 3, 2,
-	3, 6, 3, 1, 1, 1, 4, 6, 5, 7,
-	4, 5, 5, 6, 7, 7, 12, 1, 1, 1,
-	1, 3, 3, 2, 1, 3, 3, 3, 3, 3,
-	1, 2, 1, 2, 2, 2, 2, 2, 2, 2,
-	2, 2, 2, 1, 1, 4, 3, 2, 5, 4,
-	1, 3, 2, 1, 2, 1, 2, 1, 2, 1,
-	2, 2, 3, 2, 2, 1, 3, 3, 1, 3,
-	3, 2, 1, 1, 1, 1, 3, 2, 3, 3,
-	3, 3, 1, 1, 3, 6, 6, 1, 1, 3,
-	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
+
+var exprR2 = [...]int8{
+	0, 1, 1, 1, 1, 10, 1, 1, 1, 1,
+	1, 1, 1, 1, 3, 1, 2, 3, 2, 3,
+	4, 5, 3, 4, 5, 6, 3, 4, 5, 6,
+	3, 4, 5, 6, 4, 5, 6, 7, 3, 4,
+	4, 5, 3, 2, 2, 3, 3, 6, 3, 1,
+	1, 1, 4, 6, 5, 7, 4, 6, 6, 7,
+	6, 4, 5, 5, 6, 7, 7, 6, 7, 7,
+	12, 8, 10, 1, 3, 4, 6, 6, 3, 1,
+	1, 1, 1, 3, 3, 2, 1, 3, 3, 3,
+	3, 3, 1, 1, 1, 2, 1, 2, 2, 2,
+	2, 2, 2, 2, 2, 2, 2, 2, 2, 2,
+	2, 2, 2, 2, 2, 2, 1, 1, 4, 3,
+	2, 5, 4, 1, 3, 2, 1, 2, 1, 2,
+	1, 2, 1, 2, 1, 2, 3, 1, 2, 2,
+	3, 1, 2, 2, 3, 2, 2, 1, 3, 3,
+	1, 3, 3, 2, 1, 1, 1, 1, 3, 2,
+	3, 3, 3, 3, 1, 1, 3, 6, 6, 1,
+	1, 3, 3, 3, 3, 3, 3, 3, 3, 3,
 	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
-	1, 1, 1, 3, 2, 1, 1, 1, 3, 2,
+	3, 3, 3, 3, 3, 3, 3, 1, 1, 1,
+	3, 2, 1, 1, 1, 3, 2, 2, 1, 2,
+	6, 7, 2, 2, 1, 3, 2, 3, 2, 2,
 	4, 4, 4, 4, 4, 4, 4, 4, 4, 4,
 	4, 4, 4, 4, 4, 0, 1, 5, 4, 5,
 	4, 1, 1, 2, 4, 5, 2, 4, 5, 1,
-	2, 2, 4, 1, 1, 1, 1, 1, 1, 1,
+	2, 2, 1, 4, 1, 1, 1, 1, 1, 1,
+	1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
 	1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
 	1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
-	2, 1, 3, 4, 4, 3, 3,
+	1, 1, 1, 1, 1, 2, 1, 3, 4, 4,
+	3, 3,
 }
-var exprChk = [...]int{
-
-	-1000, -1, -2, -6, -7, -14, 24, -11, -15, -20,
-	-21, -22, -17, 16, -12, -16, 7, 87, 88, 66,
-	-18, 28, 29, 30, 42, 43, 52, 53, 54, 55,
-	56, 57, 58, 62, 63, 64, 31, 34, 37, 35,
-	36, 38, 39, 40, 41, 32, 33, 65, 78, 79,
-	80, 87, 88, 89, 90, 91, 92, 81, 82, 85,
-	86, 83, 84, -28, -29, -34, 48, -35, -3, 22,
-	23, 15, 82, -7, -6, -2, -10, 17, -9, 5,
-	24, 24, -4, 26, 27, 7, 7, 24, 24, -23,
-	-24, -25, 44, -23, -23, -23, -23, -23, -23, -23,
-	-23, -23, -23, -23, -23, -23, -23, -29, -35, -27,
-	-26, -52, -51, -33, -38, -39, -46, -40, -43, 47,
-	45, 46, 67, 69, -9, -55, -54, -31, 24, 49,
-	75, 50, 76, 77, 5, -32, -30, 78, 6, -19,
-	70, 25, 25, 17, 2, 20, 13, 82, 14, 15,
-	-8, 7, -14, 24, -7, 7, 24, 24, 24, -7,
-	7, -2, 71, 72, 73, 74, -2, -2, -2, -2,
-	-2, -2, -2, -2, -2, -2, -2, -2, -2, -2,
-	-33, 79, 20, 78, -37, -50, 8, -49, 5, -50,
-	6, 6, -33, 6, -48, -47, 5, -41, -42, 5,
-	-9, -44, -45, 5, -9, 13, 82, 85, 86, 83,
-	84, 81, -36, 6, -19, 78, 24, -9, 6, 6,
-	6, 6, 2, 25, 20, 10, -53, -28, 48, -14,
-	-8, 25, 20, -7, 7, -5, 25, 5, -5, 25,
-	20, 25, 24, 24, 24, 24, -33, -33, -33, 8,
-	-50, 20, 13, 25, 20, 13, 20, 20, 70, 9,
-	4, 7, 70, 9, 4, 7, 9, 4, 7, 9,
-	4, 7, 9, 4, 7, 9, 4, 7, 9, 4,
-	7, 78, 24, -36, 6, -4, -8, -56, -53, -28,
-	68, 10, 48, 10, -53, 51, 25, -53, -28, 25,
-	-4, -7, 25, 20, 20, 25, 25, 6, -5, 25,
-	-5, 25, 25, -5, 25, -5, -49, 6, -47, 2,
-	5, 6, -42, -45, 24, 24, -36, 6, 25, 25,
-	-53, -28, -53, 9, -56, -33, -56, 10, 5, -13,
-	59, 60, 61, 10, 25, 25, -53, 25, -7, 5,
-	20, 25, 25, 25, 25, 6, 6, 25, -4, -53,
-	-56, 24, -56, -53, 48, 10, 10, 25, -4, 25,
-	6, 25, 25, 5, -53, -56, -56, 10, 20, 25,
-	-56, 6, 20, 6, 25,
+
+var exprChk = [...]int16{
+	-1000, -1, -2, -7, -8, -3, -16, 26, -13, -17,
+	-22, -23, -24, -25, -27, -19, 108, 18, -14, 98,
+	-18, 95, 7, 118, 119, 9, 68, 92, -28, 90,
+	91, -20, 30, 31, 32, 44, 45, 54, 55, 56,
+	57, 58, 59, 60, 64, 65, 66, 96, 97, 33,
+	36, 39, 37, 38, 40, 41, 42, 43, 34, 35,
+	93, 94, 80, 81, 82, 83, 84, 85, 86, 87,
+	88, 89, 67, 109, 110, 111, 118, 119, 120, 121,
+	122, 123, 112, 113, 116, 117, 114, 115, -34, -35,
+	-40, 50, -41, -4, 24, 25, 17, 113, -8, -7,
+	-2, 26, -12, 19, -11, 5, 26, 26, 26, -5,
+	28, 29, 26, -5, 7, 7, 26, 26, 26, 26,
+	26, 26, -29, -30, -31, 46, -29, -29, -29, -29,
+	-29, -29, -29, -29, -29, -29, -29, -29, -29, -29,
+	-35, -41, -33, -32, -70, -69, -39, -44, -45, -64,
+	-46, -49, -52, -62, -63, -53, -54, -55, -56, -57,
+	-58, 49, 47, 48, 69, 71, 103, -11, -73, -72,
+	-37, 26, 51, 77, 52, 78, 79, 99, 100, 107,
+	101, 102, 104, 105, 106, 5, -38, -36, 109, -75,
+	-21, 6, 9, 72, 27, 27, -7, 26, 19, 2,
+	22, 15, 113, 16, 17, -9, 7, -10, -16, 26,
+	-8, -9, -10, -8, 7, 26, 26, 26, 6, 26,
+	-8, -8, -8, 7, 27, 7, -2, 73, 74, 75,
+	76, -2, -2, -2, -2, -2, -2, -2, -2, -2,
+	-2, -2, -2, -2, -2, -39, 110, 22, 109, -43,
+	-68, 8, -67, 5, -68, 6, 6, -68, -39, 6,
+	-66, -65, 5, -47, -48, 5, -11, -50, -51, 5,
+	-11, -6, 5, 10, 7, 5, 6, 6, -61, -60,
+	5, -61, -59, 5, 5, 6, 15, 113, 116, 117,
+	114, 115, 112, -42, -75, -21, 109, 26, 22, -7,
+	-11, -75, -75, -75, -75, 2, 27, 22, 27, -34,
+	11, -71, 50, -16, -9, 12, 22, 22, 27, 22,
+	-8, 7, -6, 27, -6, 27, 22, 6, 22, 22,
+	27, 22, 22, 27, 26, 26, 26, 26, -39, -39,
+	-39, 8, -68, 22, 15, 27, 22, 15, 22, 22,
+	22, 28, -60, 6, 15, 118, 5, 72, 10, 4,
+	7, 72, 10, 4, 7, 10, 4, 7, 9, 10,
+	4, 7, 9, 10, 4, 7, 9, 10, 4, 7,
+	9, 10, 4, 7, 9, 109, 26, -42, -75, -7,
+	-5, -9, -10, 11, -71, -74, -71, -34, 70, 11,
+	50, 53, -34, 27, -71, 27, -74, 7, 7, -5,
+	-8, 27, 22, 27, 27, -8, 22, 6, 6, -23,
+	-8, -6, 27, -6, 27, 27, -6, 27, -6, -67,
+	6, -65, 2, 5, 6, -48, -51, 5, 26, 6,
+	5, 26, 26, -42, -75, 27, 22, 27, 27, -74,
+	11, -71, -34, -71, 10, -74, -39, 5, -15, 61,
+	62, 63, 27, -71, 11, 27, 27, 27, 27, -8,
+	27, -8, 22, 22, 27, 27, 27, 27, 27, 27,
+	-6, 6, 6, 27, 6, -5, -74, -71, 26, 11,
+	27, -74, -71, 50, 11, -5, -5, 27, -5, 27,
+	6, 6, 27, 27, 27, 22, 5, -74, 11, -71,
+	-74, 22, 27, 22, 5, 10, 27, -74, 6, -26,
+	6, 27, 22, 27, 22, 6, 6, 27,
 }
-var exprDef = [...]int{
-
-	0, -2, 1, 2, 3, 11, 0, 4, 5, 6,
-	7, 8, 9, 0, 0, 0, 189, 0, 0, 0,
-	0, 205, 206, 207, 208, 209, 210, 211, 212, 213,
-	214, 215, 216, 217, 218, 219, 194, 195, 196, 197,
-	198, 199, 200, 201, 202, 203, 204, 193, 175, 175,
-	175, 175, 175, 175, 175, 175, 175, 175, 175, 175,
-	175, 175, 175, 12, 70, 72, 0, 90, 0, 57,
-	58, 59, 60, 3, 2, 0, 0, 63, 64, 0,
-	0, 0, 0, 0, 0, 190, 191, 0, 0, 0,
-	181, 182, 176, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 0, 71, 92, 73,
-	74, 75, 76, 77, 78, 79, 80, 81, 82, 95,
-	97, 0, 99, 0, 112, 113, 114, 115, 0, 0,
-	105, 0, 0, 0, 0, 127, 128, 0, 87, 0,
-	83, 10, 13, 61, 62, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 3, 189, 0, 0, 0, 3,
-	0, 160, 0, 0, 183, 186, 161, 162, 163, 164,
-	165, 166, 167, 168, 169, 170, 171, 172, 173, 174,
-	117, 0, 0, 0, 96, 103, 93, 123, 122, 101,
-	98, 100, 0, 104, 111, 108, 0, 154, 152, 150,
-	151, 159, 157, 155, 156, 0, 0, 0, 0, 0,
-	0, 0, 91, 84, 0, 0, 0, 65, 66, 67,
-	68, 69, 39, 46, 0, 14, 0, 0, 0, 0,
-	0, 50, 0, 3, 189, 0, 225, 221, 0, 226,
-	0, 192, 0, 0, 0, 0, 118, 119, 120, 94,
-	102, 0, 0, 116, 0, 0, 0, 0, 0, 134,
-	141, 148, 0, 133, 140, 147, 129, 136, 143, 130,
-	137, 144, 131, 138, 145, 132, 139, 146, 135, 142,
-	149, 0, 0, 89, 0, 48, 0, 15, 18, 34,
-	0, 22, 0, 26, 0, 0, 0, 0, 0, 38,
-	52, 3, 51, 0, 0, 223, 224, 0, 0, 178,
-	0, 180, 184, 0, 187, 0, 124, 121, 109, 110,
-	106, 107, 153, 158, 0, 0, 86, 0, 88, 47,
-	19, 35, 36, 220, 23, 42, 27, 30, 40, 0,
-	43, 44, 45, 16, 0, 0, 0, 53, 3, 222,
-	0, 177, 179, 185, 188, 0, 0, 85, 49, 37,
-	31, 0, 17, 20, 0, 24, 28, 0, 54, 55,
-	0, 125, 126, 0, 21, 25, 29, 32, 0, 41,
-	33, 0, 0, 0, 56,
+
+var exprDef = [...]int16{
+	0, -2, 1, 2, 3, 4, 15, 0, 6, 7,
+	8, 9, 10, 11, 12, 13, 0, 0, 0, 0,
+	0, 0, 249, 0, 0, 252, 0, 0, 0, 0,
+	0, 0, 278, 279, 280, 281, 282, 283, 284, 285,
+	286, 287, 288, 289, 290, 291, 292, 293, 294, 255,
+	256, 257, 258, 259, 260, 261, 262, 263, 264, 265,
+	266, 267, 268, 269, 270, 271, 272, 273, 274, 275,
+	276, 277, 254, 235, 235, 235, 235, 235, 235, 235,
+	235, 235, 235, 235, 235, 235, 235, 235, 16, 94,
+	96, 0, 123, 0, 79, 80, 81, 82, 3, 2,
+	0, 0, 0, 85, 86, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 250, 251, 0, 0, 0, 0,
+	0, 0, 0, 241, 242, 236, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	95, 125, 97, 98, 99, 100, 101, 102, 103, 104,
+	105, 106, 107, 108, 109, 110, 111, 112, 113, 114,
+	115, 128, 130, 0, 132, 0, 134, 154, 155, 156,
+	157, 0, 0, 147, 0, 0, 0, 0, 208, 0,
+	0, 0, 141, 0, 0, 0, 169, 170, 0, 120,
+	0, 92, 93, 116, 14, 17, 0, 0, 83, 84,
+	0, 0, 0, 0, 0, 0, 249, 0, 15, 0,
+	3, 0, 0, 3, 249, 0, 0, 0, 0, 0,
+	3, 3, 3, 0, 78, 0, 220, 0, 0, 243,
+	246, 221, 222, 223, 224, 225, 226, 227, 228, 229,
+	230, 231, 232, 233, 234, 159, 0, 0, 0, 129,
+	145, 126, 165, 164, 143, 131, 133, 135, 0, 146,
+	153, 150, 0, 201, 199, 197, 198, 206, 204, 202,
+	203, 207, 296, 209, 0, 212, 213, 139, 0, 137,
+	0, 142, 216, 214, 218, 219, 0, 0, 0, 0,
+	0, 0, 0, 124, 117, 0, 0, 0, 0, 0,
+	87, 88, 89, 90, 91, 43, 52, 0, 56, 16,
+	18, 0, 0, 15, 0, 44, 0, 0, 61, 0,
+	3, 249, 0, 300, 0, 301, 0, 0, 0, 0,
+	75, 0, 0, 253, 0, 0, 0, 0, 160, 161,
+	162, 127, 144, 0, 0, 158, 0, 0, 0, 0,
+	0, 0, 138, 140, 0, 0, 217, 0, 176, 183,
+	190, 0, 175, 182, 189, 171, 178, 185, 192, 172,
+	179, 186, 193, 173, 180, 187, 194, 174, 181, 188,
+	195, 177, 184, 191, 196, 0, 0, 122, 0, 0,
+	54, 0, 0, 30, 0, 19, 22, 38, 0, 26,
+	0, 0, 16, 0, 0, 42, 45, 0, 0, 63,
+	3, 62, 0, 298, 299, 3, 0, 0, 0, 0,
+	3, 0, 238, 0, 240, 244, 0, 247, 0, 166,
+	163, 151, 152, 148, 149, 200, 205, 297, 0, 136,
+	215, 0, 0, 119, 0, 121, 0, 53, 57, 31,
+	34, 23, 39, 40, 295, 27, 48, 46, 0, 49,
+	50, 51, 0, 0, 20, 0, 58, 60, 64, 3,
+	67, 3, 0, 0, 76, 77, 237, 239, 245, 248,
+	0, 0, 0, 118, 0, 55, 35, 41, 0, 32,
+	0, 21, 24, 0, 28, 59, 65, 66, 68, 69,
+	0, 0, 210, 167, 168, 0, 0, 33, 36, 25,
+	29, 0, 71, 0, 211, 0, 47, 37, 0, 0,
+	73, 5, 0, 72, 0, 0, 74, 70,
 }
-var exprTok1 = [...]int{
 
+var exprTok1 = [...]int8{
 	1,
 }
-var exprTok2 = [...]int{
 
+var exprTok2 = [...]int8{
 	2, 3, 4, 5, 6, 7, 8, 9, 10, 11,
 	12, 13, 14, 15, 16, 17, 18, 19, 20, 21,
 	22, 23, 24, 25, 26, 27, 28, 29, 30, 31,
@@ -542,9 +717,13 @@ var exprTok2 = [...]int{
 	62, 63, 64, 65, 66, 67, 68, 69, 70, 71,
 	72, 73, 74, 75, 76, 77, 78, 79, 80, 81,
 	82, 83, 84, 85, 86, 87, 88, 89, 90, 91,
-	92,
+	92, 93, 94, 95, 96, 97, 98, 99, 100, 101,
+	102, 103, 104, 105, 106, 107, 108, 109, 110, 111,
+	112, 113, 114, 115, 116, 117, 118, 119, 120, 121,
+	122, 123,
 }
-var exprTok3 = [...]int{
+
+var exprTok3 = [...]int8{
 	0,
 }
 
@@ -625,9 +804,9 @@ func exprErrorMessage(state, lookAhead int) string {
 	expected := make([]int, 0, 4)
 
 	// Look for shiftable tokens.
-	base := exprPact[state]
+	base := int(exprPact[state])
 	for tok := TOKSTART; tok-1 < len(exprToknames); tok++ {
-		if n := base + tok; n >= 0 && n < exprLast && exprChk[exprAct[n]] == tok {
+		if n := base + tok; n >= 0 && n < exprLast && int(exprChk[int(exprAct[n])]) == tok {
 			if len(expected) == cap(expected) {
 				return res
 			}
@@ -637,13 +816,13 @@ func exprErrorMessage(state, lookAhead int) string {
 
 	if exprDef[state] == -2 {
 		i := 0
-		for exprExca[i] != -1 || exprExca[i+1] != state {
+		for exprExca[i] != -1 || int(exprExca[i+1]) != state {
 			i += 2
 		}
 
 		// Look for tokens that we accept or reduce.
 		for i += 2; exprExca[i] >= 0; i += 2 {
-			tok := exprExca[i]
+			tok := int(exprExca[i])
 			if tok < TOKSTART || exprExca[i+1] == 0 {
 				continue
 			}
@@ -674,30 +853,30 @@ func exprlex1(lex exprLexer, lval *exprSymType) (char, token int) {
 	token = 0
 	char = lex.Lex(lval)
 	if char <= 0 {
-		token = exprTok1[0]
+		token = int(exprTok1[0])
 		goto out
 	}
 	if char < len(exprTok1) {
-		token = exprTok1[char]
+		token = int(exprTok1[char])
 		goto out
 	}
 	if char >= exprPrivate {
 		if char < exprPrivate+len(exprTok2) {
-			token = exprTok2[char-exprPrivate]
+			token = int(exprTok2[char-exprPrivate])
 			goto out
 		}
 	}
 	for i := 0; i < len(exprTok3); i += 2 {
-		token = exprTok3[i+0]
+		token = int(exprTok3[i+0])
 		if token == char {
-			token = exprTok3[i+1]
+			token = int(exprTok3[i+1])
 			goto out
 		}
 	}
 
 out:
 	if token == 0 {
-		token = exprTok2[1] /* unknown char */
+		token = int(exprTok2[1]) /* unknown char */
 	}
 	if exprDebug >= 3 {
 		__yyfmt__.Printf("lex %s(%d)\n", exprTokname(token), uint(char))
@@ -752,7 +931,7 @@ exprstack:
 	exprS[exprp].yys = exprstate
 
 exprnewstate:
-	exprn = exprPact[exprstate]
+	exprn = int(exprPact[exprstate])
 	if exprn <= exprFlag {
 		goto exprdefault /* simple state */
 	}
@@ -763,8 +942,8 @@ exprnewstate:
 	if exprn < 0 || exprn >= exprLast {
 		goto exprdefault
 	}
-	exprn = exprAct[exprn]
-	if exprChk[exprn] == exprtoken { /* valid shift */
+	exprn = int(exprAct[exprn])
+	if int(exprChk[exprn]) == exprtoken { /* valid shift */
 		exprrcvr.char = -1
 		exprtoken = -1
 		exprVAL = exprrcvr.lval
@@ -777,7 +956,7 @@ exprnewstate:
 
 exprdefault:
 	/* default state action */
-	exprn = exprDef[exprstate]
+	exprn = int(exprDef[exprstate])
 	if exprn == -2 {
 		if exprrcvr.char < 0 {
 			exprrcvr.char, exprtoken = exprlex1(exprlex, &exprrcvr.lval)
@@ -786,18 +965,18 @@ exprdefault:
 		/* look through exception table */
 		xi := 0
 		for {
-			if exprExca[xi+0] == -1 && exprExca[xi+1] == exprstate {
+			if exprExca[xi+0] == -1 && int(exprExca[xi+1]) == exprstate {
 				break
 			}
 			xi += 2
 		}
 		for xi += 2; ; xi += 2 {
-			exprn = exprExca[xi+0]
+			exprn = int(exprExca[xi+0])
 			if exprn < 0 || exprn == exprtoken {
 				break
 			}
 		}
-		exprn = exprExca[xi+1]
+		exprn = int(exprExca[xi+1])
 		if exprn < 0 {
 			goto ret0
 		}
@@ -819,10 +998,10 @@ exprdefault:
 
 			/* find a state where "error" is a legal shift action */
 			for exprp >= 0 {
-				exprn = exprPact[exprS[exprp].yys] + exprErrCode
+				exprn = int(exprPact[exprS[exprp].yys]) + exprErrCode
 				if exprn >= 0 && exprn < exprLast {
-					exprstate = exprAct[exprn] /* simulate a shift of "error" */
-					if exprChk[exprstate] == exprErrCode {
+					exprstate = int(exprAct[exprn]) /* simulate a shift of "error" */
+					if int(exprChk[exprstate]) == exprErrCode {
 						goto exprstack
 					}
 				}
@@ -858,7 +1037,7 @@ exprdefault:
 	exprpt := exprp
 	_ = exprpt // guard against "declared and not used"
 
-	exprp -= exprR2[exprn]
+	exprp -= int(exprR2[exprn])
 	// exprp is now the index of $0. Perform the default action. Iff the
 	// reduced production is ε, $1 is possibly out of range.
 	if exprp+1 >= len(exprS) {
@@ -869,16 +1048,16 @@ exprdefault:
 	exprVAL = exprS[exprp+1]
 
 	/* consult goto table to find next state */
-	exprn = exprR1[exprn]
-	exprg := exprPgo[exprn]
+	exprn = int(exprR1[exprn])
+	exprg := int(exprPgo[exprn])
 	exprj := exprg + exprS[exprp].yys + 1
 
 	if exprj >= exprLast {
-		exprstate = exprAct[exprg]
+		exprstate = int(exprAct[exprg])
 	} else {
-		exprstate = exprAct[exprj]
-		if exprChk[exprstate] != -exprn {
-			exprstate = exprAct[exprg]
+		exprstate = int(exprAct[exprj])
+		if int(exprChk[exprstate]) != -exprn {
+			exprstate = int(exprAct[exprg])
 		}
 	}
 	// dummy call; replaced with literal code
@@ -902,1115 +1081,1490 @@ exprdefault:
 	case 4:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
-			exprVAL.MetricExpr = exprDollar[1].RangeAggregationExpr
+			exprVAL.Expr = exprDollar[1].Expr
 		}
 	case 5:
+		exprDollar = exprS[exprpt-10 : exprpt+1]
+		{
+			exprVAL.Expr = newJoinExpr(exprDollar[3].LogExpr, exprDollar[5].LogExpr, exprDollar[7].str, exprDollar[9].duration)
+		}
+	case 6:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+		{
+			exprVAL.MetricExpr = exprDollar[1].RangeAggregationExpr
+		}
+	case 7:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.MetricExpr = exprDollar[1].VectorAggregationExpr
 		}
-	case 6:
+	case 8:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.MetricExpr = exprDollar[1].BinOpExpr
 		}
-	case 7:
+	case 9:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.MetricExpr = exprDollar[1].LiteralExpr
 		}
-	case 8:
+	case 10:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.MetricExpr = exprDollar[1].LabelReplaceExpr
 		}
-	case 9:
+	case 11:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+		{
+			exprVAL.MetricExpr = exprDollar[1].LabelJoinExpr
+		}
+	case 12:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+		{
+			exprVAL.MetricExpr = exprDollar[1].FunctionExpr
+		}
+	case 13:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.MetricExpr = exprDollar[1].VectorExpr
 		}
-	case 10:
+	case 14:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.MetricExpr = exprDollar[2].MetricExpr
 		}
-	case 11:
+	case 15:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.LogExpr = newMatcherExpr(exprDollar[1].Selector)
 		}
-	case 12:
+	case 16:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
 		{
 			exprVAL.LogExpr = newPipelineExpr(newMatcherExpr(exprDollar[1].Selector), exprDollar[2].PipelineExpr)
 		}
-	case 13:
+	case 17:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.LogExpr = exprDollar[2].LogExpr
 		}
-	case 14:
+	case 18:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
 		{
 			exprVAL.LogRangeExpr = newLogRange(newMatcherExpr(exprDollar[1].Selector), exprDollar[2].duration, nil, nil)
 		}
-	case 15:
+	case 19:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.LogRangeExpr = newLogRange(newMatcherExpr(exprDollar[1].Selector), exprDollar[2].duration, nil, exprDollar[3].OffsetExpr)
 		}
-	case 16:
+	case 20:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
 		{
 			exprVAL.LogRangeExpr = newLogRange(newMatcherExpr(exprDollar[2].Selector), exprDollar[4].duration, nil, nil)
 		}
-	case 17:
+	case 21:
 		exprDollar = exprS[exprpt-5 : exprpt+1]
 		{
 			exprVAL.LogRangeExpr = newLogRange(newMatcherExpr(exprDollar[2].Selector), exprDollar[4].duration, nil, exprDollar[5].OffsetExpr)
 		}
-	case 18:
+	case 22:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.LogRangeExpr = newLogRange(newMatcherExpr(exprDollar[1].Selector), exprDollar[2].duration, exprDollar[3].UnwrapExpr, nil)
 		}
-	case 19:
+	case 23:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
 		{
 			exprVAL.LogRangeExpr = newLogRange(newMatcherExpr(exprDollar[1].Selector), exprDollar[2].duration, exprDollar[4].UnwrapExpr, exprDollar[3].OffsetExpr)
 		}
-	case 20:
+	case 24:
 		exprDollar = exprS[exprpt-5 : exprpt+1]
 		{
 			exprVAL.LogRangeExpr = newLogRange(newMatcherExpr(exprDollar[2].Selector), exprDollar[4].duration, exprDollar[5].UnwrapExpr, nil)
 		}
-	case 21:
+	case 25:
 		exprDollar = exprS[exprpt-6 : exprpt+1]
 		{
 			exprVAL.LogRangeExpr = newLogRange(newMatcherExpr(exprDollar[2].Selector), exprDollar[4].duration, exprDollar[6].UnwrapExpr, exprDollar[5].OffsetExpr)
 		}
-	case 22:
+	case 26:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.LogRangeExpr = newLogRange(newMatcherExpr(exprDollar[1].Selector), exprDollar[3].duration, exprDollar[2].UnwrapExpr, nil)
 		}
-	case 23:
+	case 27:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
 		{
 			exprVAL.LogRangeExpr = newLogRange(newMatcherExpr(exprDollar[1].Selector), exprDollar[3].duration, exprDollar[2].UnwrapExpr, exprDollar[4].OffsetExpr)
 		}
-	case 24:
+	case 28:
 		exprDollar = exprS[exprpt-5 : exprpt+1]
 		{
 			exprVAL.LogRangeExpr = newLogRange(newMatcherExpr(exprDollar[2].Selector), exprDollar[5].duration, exprDollar[3].UnwrapExpr, nil)
 		}
-	case 25:
+	case 29:
 		exprDollar = exprS[exprpt-6 : exprpt+1]
 		{
 			exprVAL.LogRangeExpr = newLogRange(newMatcherExpr(exprDollar[2].Selector), exprDollar[5].duration, exprDollar[3].UnwrapExpr, exprDollar[6].OffsetExpr)
 		}
-	case 26:
+	case 30:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.LogRangeExpr = newLogRange(newPipelineExpr(newMatcherExpr(exprDollar[1].Selector), exprDollar[2].PipelineExpr), exprDollar[3].duration, nil, nil)
 		}
-	case 27:
+	case 31:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
 		{
 			exprVAL.LogRangeExpr = newLogRange(newPipelineExpr(newMatcherExpr(exprDollar[1].Selector), exprDollar[2].PipelineExpr), exprDollar[3].duration, nil, exprDollar[4].OffsetExpr)
 		}
-	case 28:
+	case 32:
 		exprDollar = exprS[exprpt-5 : exprpt+1]
 		{
 			exprVAL.LogRangeExpr = newLogRange(newPipelineExpr(newMatcherExpr(exprDollar[2].Selector), exprDollar[3].PipelineExpr), exprDollar[5].duration, nil, nil)
 		}
-	case 29:
+	case 33:
 		exprDollar = exprS[exprpt-6 : exprpt+1]
 		{
 			exprVAL.LogRangeExpr = newLogRange(newPipelineExpr(newMatcherExpr(exprDollar[2].Selector), exprDollar[3].PipelineExpr), exprDollar[5].duration, nil, exprDollar[6].OffsetExpr)
 		}
-	case 30:
+	case 34:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
 		{
 			exprVAL.LogRangeExpr = newLogRange(newPipelineExpr(newMatcherExpr(exprDollar[1].Selector), exprDollar[2].PipelineExpr), exprDollar[4].duration, exprDollar[3].UnwrapExpr, nil)
 		}
-	case 31:
+	case 35:
 		exprDollar = exprS[exprpt-5 : exprpt+1]
 		{
 			exprVAL.LogRangeExpr = newLogRange(newPipelineExpr(newMatcherExpr(exprDollar[1].Selector), exprDollar[2].PipelineExpr), exprDollar[4].duration, exprDollar[3].UnwrapExpr, exprDollar[5].OffsetExpr)
 		}
-	case 32:
+	case 36:
 		exprDollar = exprS[exprpt-6 : exprpt+1]
 		{
 			exprVAL.LogRangeExpr = newLogRange(newPipelineExpr(newMatcherExpr(exprDollar[2].Selector), exprDollar[3].PipelineExpr), exprDollar[6].duration, exprDollar[4].UnwrapExpr, nil)
 		}
-	case 33:
+	case 37:
 		exprDollar = exprS[exprpt-7 : exprpt+1]
 		{
 			exprVAL.LogRangeExpr = newLogRange(newPipelineExpr(newMatcherExpr(exprDollar[2].Selector), exprDollar[3].PipelineExpr), exprDollar[6].duration, exprDollar[4].UnwrapExpr, exprDollar[7].OffsetExpr)
 		}
-	case 34:
+	case 38:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.LogRangeExpr = newLogRange(newPipelineExpr(newMatcherExpr(exprDollar[1].Selector), exprDollar[3].PipelineExpr), exprDollar[2].duration, nil, nil)
 		}
-	case 35:
+	case 39:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
 		{
 			exprVAL.LogRangeExpr = newLogRange(newPipelineExpr(newMatcherExpr(exprDollar[1].Selector), exprDollar[4].PipelineExpr), exprDollar[2].duration, nil, exprDollar[3].OffsetExpr)
 		}
-	case 36:
+	case 40:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
 		{
 			exprVAL.LogRangeExpr = newLogRange(newPipelineExpr(newMatcherExpr(exprDollar[1].Selector), exprDollar[3].PipelineExpr), exprDollar[2].duration, exprDollar[4].UnwrapExpr, nil)
 		}
-	case 37:
+	case 41:
 		exprDollar = exprS[exprpt-5 : exprpt+1]
 		{
 			exprVAL.LogRangeExpr = newLogRange(newPipelineExpr(newMatcherExpr(exprDollar[1].Selector), exprDollar[4].PipelineExpr), exprDollar[2].duration, exprDollar[5].UnwrapExpr, exprDollar[3].OffsetExpr)
 		}
-	case 38:
+	case 42:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.LogRangeExpr = exprDollar[2].LogRangeExpr
 		}
-	case 40:
+	case 44:
+		exprDollar = exprS[exprpt-2 : exprpt+1]
+		{
+			exprVAL.SubqueryExpr = newSubqueryExpr(exprDollar[1].MetricExpr, exprDollar[2].subqueryRange, nil)
+		}
+	case 45:
+		exprDollar = exprS[exprpt-3 : exprpt+1]
+		{
+			exprVAL.SubqueryExpr = newSubqueryExpr(exprDollar[1].MetricExpr, exprDollar[2].subqueryRange, exprDollar[3].OffsetExpr)
+		}
+	case 46:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.UnwrapExpr = newUnwrapExpr(exprDollar[3].str, "")
 		}
-	case 41:
+	case 47:
 		exprDollar = exprS[exprpt-6 : exprpt+1]
 		{
 			exprVAL.UnwrapExpr = newUnwrapExpr(exprDollar[5].str, exprDollar[3].ConvOp)
 		}
-	case 42:
+	case 48:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.UnwrapExpr = exprDollar[1].UnwrapExpr.addPostFilter(exprDollar[3].LabelFilter)
 		}
-	case 43:
+	case 49:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.ConvOp = OpConvBytes
 		}
-	case 44:
+	case 50:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.ConvOp = OpConvDuration
 		}
-	case 45:
+	case 51:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.ConvOp = OpConvDurationSeconds
 		}
-	case 46:
+	case 52:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
 		{
 			exprVAL.RangeAggregationExpr = newRangeAggregationExpr(exprDollar[3].LogRangeExpr, exprDollar[1].RangeOp, nil, nil)
 		}
-	case 47:
+	case 53:
 		exprDollar = exprS[exprpt-6 : exprpt+1]
 		{
 			exprVAL.RangeAggregationExpr = newRangeAggregationExpr(exprDollar[5].LogRangeExpr, exprDollar[1].RangeOp, nil, &exprDollar[3].str)
 		}
-	case 48:
+	case 54:
 		exprDollar = exprS[exprpt-5 : exprpt+1]
 		{
 			exprVAL.RangeAggregationExpr = newRangeAggregationExpr(exprDollar[3].LogRangeExpr, exprDollar[1].RangeOp, exprDollar[5].Grouping, nil)
 		}
-	case 49:
+	case 55:
 		exprDollar = exprS[exprpt-7 : exprpt+1]
 		{
 			exprVAL.RangeAggregationExpr = newRangeAggregationExpr(exprDollar[5].LogRangeExpr, exprDollar[1].RangeOp, exprDollar[7].Grouping, &exprDollar[3].str)
 		}
-	case 50:
+	case 56:
+		exprDollar = exprS[exprpt-4 : exprpt+1]
+		{
+			exprVAL.RangeAggregationExpr = newSubqueryAggregationExpr(exprDollar[3].SubqueryExpr, exprDollar[1].RangeOp, nil)
+		}
+	case 57:
+		exprDollar = exprS[exprpt-6 : exprpt+1]
+		{
+			exprVAL.RangeAggregationExpr = newSubqueryAggregationExpr(exprDollar[5].SubqueryExpr, exprDollar[1].RangeOp, &exprDollar[3].str)
+		}
+	case 58:
+		exprDollar = exprS[exprpt-6 : exprpt+1]
+		{
+			exprVAL.RangeAggregationExpr = newRangeAggregationExpr(exprDollar[3].LogRangeExpr, OpRangeTypePredictLinear, nil, &exprDollar[5].str)
+		}
+	case 59:
+		exprDollar = exprS[exprpt-7 : exprpt+1]
+		{
+			exprVAL.RangeAggregationExpr = newRangeAggregationExpr(exprDollar[3].LogRangeExpr, OpRangeTypePredictLinear, exprDollar[7].Grouping, &exprDollar[5].str)
+		}
+	case 60:
+		exprDollar = exprS[exprpt-6 : exprpt+1]
+		{
+			exprVAL.RangeAggregationExpr = newSubqueryAggregationExpr(exprDollar[3].SubqueryExpr, OpRangeTypePredictLinear, &exprDollar[5].str)
+		}
+	case 61:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
 		{
 			exprVAL.VectorAggregationExpr = mustNewVectorAggregationExpr(exprDollar[3].MetricExpr, exprDollar[1].VectorOp, nil, nil)
 		}
-	case 51:
+	case 62:
 		exprDollar = exprS[exprpt-5 : exprpt+1]
 		{
 			exprVAL.VectorAggregationExpr = mustNewVectorAggregationExpr(exprDollar[4].MetricExpr, exprDollar[1].VectorOp, exprDollar[2].Grouping, nil)
 		}
-	case 52:
+	case 63:
 		exprDollar = exprS[exprpt-5 : exprpt+1]
 		{
 			exprVAL.VectorAggregationExpr = mustNewVectorAggregationExpr(exprDollar[3].MetricExpr, exprDollar[1].VectorOp, exprDollar[5].Grouping, nil)
 		}
-	case 53:
+	case 64:
 		exprDollar = exprS[exprpt-6 : exprpt+1]
 		{
 			exprVAL.VectorAggregationExpr = mustNewVectorAggregationExpr(exprDollar[5].MetricExpr, exprDollar[1].VectorOp, nil, &exprDollar[3].str)
 		}
-	case 54:
+	case 65:
 		exprDollar = exprS[exprpt-7 : exprpt+1]
 		{
 			exprVAL.VectorAggregationExpr = mustNewVectorAggregationExpr(exprDollar[5].MetricExpr, exprDollar[1].VectorOp, exprDollar[7].Grouping, &exprDollar[3].str)
 		}
-	case 55:
+	case 66:
 		exprDollar = exprS[exprpt-7 : exprpt+1]
 		{
 			exprVAL.VectorAggregationExpr = mustNewVectorAggregationExpr(exprDollar[6].MetricExpr, exprDollar[1].VectorOp, exprDollar[2].Grouping, &exprDollar[4].str)
 		}
-	case 56:
+	case 67:
+		exprDollar = exprS[exprpt-6 : exprpt+1]
+		{
+			exprVAL.VectorAggregationExpr = mustNewVectorAggregationExpr(exprDollar[5].MetricExpr, OpTypeCountValues, nil, &exprDollar[3].str)
+		}
+	case 68:
+		exprDollar = exprS[exprpt-7 : exprpt+1]
+		{
+			exprVAL.VectorAggregationExpr = mustNewVectorAggregationExpr(exprDollar[5].MetricExpr, OpTypeCountValues, exprDollar[7].Grouping, &exprDollar[3].str)
+		}
+	case 69:
+		exprDollar = exprS[exprpt-7 : exprpt+1]
+		{
+			exprVAL.VectorAggregationExpr = mustNewVectorAggregationExpr(exprDollar[6].MetricExpr, OpTypeCountValues, exprDollar[2].Grouping, &exprDollar[4].str)
+		}
+	case 70:
 		exprDollar = exprS[exprpt-12 : exprpt+1]
 		{
 			exprVAL.LabelReplaceExpr = mustNewLabelReplaceExpr(exprDollar[3].MetricExpr, exprDollar[5].str, exprDollar[7].str, exprDollar[9].str, exprDollar[11].str)
 		}
-	case 57:
+	case 71:
+		exprDollar = exprS[exprpt-8 : exprpt+1]
+		{
+			exprVAL.LabelJoinExpr = mustNewLabelJoinExpr(exprDollar[3].MetricExpr, exprDollar[5].str, exprDollar[7].str, nil)
+		}
+	case 72:
+		exprDollar = exprS[exprpt-10 : exprpt+1]
+		{
+			exprVAL.LabelJoinExpr = mustNewLabelJoinExpr(exprDollar[3].MetricExpr, exprDollar[5].str, exprDollar[7].str, exprDollar[9].Labels)
+		}
+	case 73:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+		{
+			exprVAL.Labels = []string{exprDollar[1].str}
+		}
+	case 74:
+		exprDollar = exprS[exprpt-3 : exprpt+1]
+		{
+			exprVAL.Labels = append(exprDollar[1].Labels, exprDollar[3].str)
+		}
+	case 75:
+		exprDollar = exprS[exprpt-4 : exprpt+1]
+		{
+			exprVAL.FunctionExpr = newFunctionExpr(exprDollar[3].MetricExpr, exprDollar[1].FunctionOp, nil)
+		}
+	case 76:
+		exprDollar = exprS[exprpt-6 : exprpt+1]
+		{
+			exprVAL.FunctionExpr = newFunctionExpr(exprDollar[3].MetricExpr, exprDollar[1].FunctionOp, exprDollar[5].LiteralExpr)
+		}
+	case 77:
+		exprDollar = exprS[exprpt-6 : exprpt+1]
+		{
+			exprVAL.FunctionExpr = newFunctionExpr(exprDollar[5].MetricExpr, OpFuncHistogramQuantile, mustNewLiteralExpr(exprDollar[3].str, false))
+		}
+	case 78:
+		exprDollar = exprS[exprpt-3 : exprpt+1]
+		{
+			exprVAL.FunctionExpr = newTimeExpr()
+		}
+	case 79:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.Filter = labels.MatchRegexp
 		}
-	case 58:
+	case 80:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.Filter = labels.MatchEqual
 		}
-	case 59:
+	case 81:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.Filter = labels.MatchNotRegexp
 		}
-	case 60:
+	case 82:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.Filter = labels.MatchNotEqual
 		}
-	case 61:
+	case 83:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.Selector = exprDollar[2].Matchers
 		}
-	case 62:
+	case 84:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.Selector = exprDollar[2].Matchers
 		}
-	case 63:
+	case 85:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
 		{
 		}
-	case 64:
+	case 86:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.Matchers = []*labels.Matcher{exprDollar[1].Matcher}
 		}
-	case 65:
+	case 87:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.Matchers = append(exprDollar[1].Matchers, exprDollar[3].Matcher)
 		}
-	case 66:
+	case 88:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.Matcher = mustNewMatcher(labels.MatchEqual, exprDollar[1].str, exprDollar[3].str)
 		}
-	case 67:
+	case 89:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.Matcher = mustNewMatcher(labels.MatchNotEqual, exprDollar[1].str, exprDollar[3].str)
 		}
-	case 68:
+	case 90:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.Matcher = mustNewMatcher(labels.MatchRegexp, exprDollar[1].str, exprDollar[3].str)
 		}
-	case 69:
+	case 91:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.Matcher = mustNewMatcher(labels.MatchNotRegexp, exprDollar[1].str, exprDollar[3].str)
 		}
-	case 70:
+	case 92:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+		{
+			exprVAL.str = exprDollar[1].str
+		}
+	case 93:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+		{
+			exprVAL.str = exprDollar[1].str
+		}
+	case 94:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.PipelineExpr = MultiStageExpr{exprDollar[1].PipelineStage}
 		}
-	case 71:
+	case 95:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
 		{
 			exprVAL.PipelineExpr = append(exprDollar[1].PipelineExpr, exprDollar[2].PipelineStage)
 		}
-	case 72:
+	case 96:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.PipelineStage = exprDollar[1].LineFilters
 		}
-	case 73:
+	case 97:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
 		{
 			exprVAL.PipelineStage = exprDollar[2].LogfmtParser
 		}
-	case 74:
+	case 98:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
 		{
 			exprVAL.PipelineStage = exprDollar[2].LabelParser
 		}
-	case 75:
+	case 99:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
 		{
 			exprVAL.PipelineStage = exprDollar[2].JSONExpressionParser
 		}
-	case 76:
+	case 100:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
 		{
 			exprVAL.PipelineStage = exprDollar[2].LogfmtExpressionParser
 		}
-	case 77:
+	case 101:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
 		{
 			exprVAL.PipelineStage = &LabelFilterExpr{LabelFilterer: exprDollar[2].LabelFilter}
 		}
-	case 78:
+	case 102:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
 		{
 			exprVAL.PipelineStage = exprDollar[2].LineFormatExpr
 		}
-	case 79:
+	case 103:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
 		{
 			exprVAL.PipelineStage = exprDollar[2].DecolorizeExpr
 		}
-	case 80:
+	case 104:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
 		{
 			exprVAL.PipelineStage = exprDollar[2].LabelFormatExpr
 		}
-	case 81:
+	case 105:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
 		{
 			exprVAL.PipelineStage = exprDollar[2].DropLabelsExpr
 		}
-	case 82:
+	case 106:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
 		{
 			exprVAL.PipelineStage = exprDollar[2].KeepLabelsExpr
 		}
-	case 83:
+	case 107:
+		exprDollar = exprS[exprpt-2 : exprpt+1]
+		{
+			exprVAL.PipelineStage = exprDollar[2].PipelineStage
+		}
+	case 108:
+		exprDollar = exprS[exprpt-2 : exprpt+1]
+		{
+			exprVAL.PipelineStage = exprDollar[2].PipelineStage
+		}
+	case 109:
+		exprDollar = exprS[exprpt-2 : exprpt+1]
+		{
+			exprVAL.PipelineStage = exprDollar[2].PipelineStage
+		}
+	case 110:
+		exprDollar = exprS[exprpt-2 : exprpt+1]
+		{
+			exprVAL.PipelineStage = exprDollar[2].PipelineStage
+		}
+	case 111:
+		exprDollar = exprS[exprpt-2 : exprpt+1]
+		{
+			exprVAL.PipelineStage = exprDollar[2].PipelineStage
+		}
+	case 112:
+		exprDollar = exprS[exprpt-2 : exprpt+1]
+		{
+			exprVAL.PipelineStage = exprDollar[2].PipelineStage
+		}
+	case 113:
+		exprDollar = exprS[exprpt-2 : exprpt+1]
+		{
+			exprVAL.PipelineStage = exprDollar[2].PipelineStage
+		}
+	case 114:
+		exprDollar = exprS[exprpt-2 : exprpt+1]
+		{
+			exprVAL.PipelineStage = exprDollar[2].PipelineStage
+		}
+	case 115:
+		exprDollar = exprS[exprpt-2 : exprpt+1]
+		{
+			exprVAL.PipelineStage = exprDollar[2].PipelineStage
+		}
+	case 116:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.FilterOp = OpFilterIP
 		}
-	case 84:
+	case 117:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.OrFilter = newLineFilterExpr(labels.MatchEqual, "", exprDollar[1].str)
 		}
-	case 85:
+	case 118:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
 		{
 			exprVAL.OrFilter = newLineFilterExpr(labels.MatchEqual, exprDollar[1].FilterOp, exprDollar[3].str)
 		}
-	case 86:
+	case 119:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.OrFilter = newOrLineFilter(newLineFilterExpr(labels.MatchEqual, "", exprDollar[1].str), exprDollar[3].OrFilter)
 		}
-	case 87:
+	case 120:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
 		{
 			exprVAL.LineFilter = newLineFilterExpr(exprDollar[1].Filter, "", exprDollar[2].str)
 		}
-	case 88:
+	case 121:
 		exprDollar = exprS[exprpt-5 : exprpt+1]
 		{
 			exprVAL.LineFilter = newLineFilterExpr(exprDollar[1].Filter, exprDollar[2].FilterOp, exprDollar[4].str)
 		}
-	case 89:
+	case 122:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
 		{
 			exprVAL.LineFilter = newOrLineFilter(newLineFilterExpr(exprDollar[1].Filter, "", exprDollar[2].str), exprDollar[4].OrFilter)
 		}
-	case 90:
+	case 123:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.LineFilters = exprDollar[1].LineFilter
 		}
-	case 91:
+	case 124:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.LineFilters = newOrLineFilter(exprDollar[1].LineFilter, exprDollar[3].OrFilter)
 		}
-	case 92:
+	case 125:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
 		{
 			exprVAL.LineFilters = newNestedLineFilterExpr(exprDollar[1].LineFilters, exprDollar[2].LineFilter)
 		}
-	case 93:
+	case 126:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.ParserFlags = []string{exprDollar[1].str}
 		}
-	case 94:
+	case 127:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
 		{
 			exprVAL.ParserFlags = append(exprDollar[1].ParserFlags, exprDollar[2].str)
 		}
-	case 95:
+	case 128:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.LogfmtParser = newLogfmtParserExpr(nil)
 		}
-	case 96:
+	case 129:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
 		{
 			exprVAL.LogfmtParser = newLogfmtParserExpr(exprDollar[2].ParserFlags)
 		}
-	case 97:
+	case 130:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.LabelParser = newLabelParserExpr(OpParserTypeJSON, "")
 		}
-	case 98:
+	case 131:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
 		{
 			exprVAL.LabelParser = newLabelParserExpr(OpParserTypeRegexp, exprDollar[2].str)
 		}
-	case 99:
+	case 132:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.LabelParser = newLabelParserExpr(OpParserTypeUnpack, "")
 		}
-	case 100:
+	case 133:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
 		{
 			exprVAL.LabelParser = newLabelParserExpr(OpParserTypePattern, exprDollar[2].str)
 		}
-	case 101:
+	case 134:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+		{
+			exprVAL.LabelParser = newLabelParserExpr(OpParserTypeXML, "")
+		}
+	case 135:
+		exprDollar = exprS[exprpt-2 : exprpt+1]
+		{
+			exprVAL.PipelineStage = newXMLExpressionParser(exprDollar[2].LabelExtractionExpressionList)
+		}
+	case 136:
+		exprDollar = exprS[exprpt-3 : exprpt+1]
+		{
+			exprVAL.LabelExtractionExpression = log.NewLabelExtractionExpr(exprDollar[1].str, exprDollar[3].str)
+		}
+	case 137:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+		{
+			exprVAL.LabelExtractionExpressionList = []log.LabelExtractionExpr{exprDollar[1].LabelExtractionExpression}
+		}
+	case 138:
+		exprDollar = exprS[exprpt-2 : exprpt+1]
+		{
+			exprVAL.LabelExtractionExpressionList = append(exprDollar[1].LabelExtractionExpressionList, exprDollar[2].LabelExtractionExpression)
+		}
+	case 139:
+		exprDollar = exprS[exprpt-2 : exprpt+1]
+		{
+			exprVAL.PipelineStage = newCSVParserExpr(exprDollar[2].str, nil)
+		}
+	case 140:
+		exprDollar = exprS[exprpt-3 : exprpt+1]
+		{
+			exprVAL.PipelineStage = newCSVParserExpr(exprDollar[3].str, exprDollar[2].LabelExtractionExpressionList)
+		}
+	case 141:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+		{
+			exprVAL.PipelineStage = newKVParserExpr(nil)
+		}
+	case 142:
+		exprDollar = exprS[exprpt-2 : exprpt+1]
+		{
+			exprVAL.PipelineStage = newKVParserExpr(exprDollar[2].LabelExtractionExpressionList)
+		}
+	case 143:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
 		{
 			exprVAL.JSONExpressionParser = newJSONExpressionParser(exprDollar[2].LabelExtractionExpressionList)
 		}
-	case 102:
+	case 144:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.LogfmtExpressionParser = newLogfmtExpressionParser(exprDollar[3].LabelExtractionExpressionList, exprDollar[2].ParserFlags)
 		}
-	case 103:
+	case 145:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
 		{
 			exprVAL.LogfmtExpressionParser = newLogfmtExpressionParser(exprDollar[2].LabelExtractionExpressionList, nil)
 		}
-	case 104:
+	case 146:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
 		{
 			exprVAL.LineFormatExpr = newLineFmtExpr(exprDollar[2].str)
 		}
-	case 105:
+	case 147:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.DecolorizeExpr = newDecolorizeExpr()
 		}
-	case 106:
+	case 148:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.LabelFormat = log.NewRenameLabelFmt(exprDollar[1].str, exprDollar[3].str)
 		}
-	case 107:
+	case 149:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.LabelFormat = log.NewTemplateLabelFmt(exprDollar[1].str, exprDollar[3].str)
 		}
-	case 108:
+	case 150:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.LabelsFormat = []log.LabelFmt{exprDollar[1].LabelFormat}
 		}
-	case 109:
+	case 151:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.LabelsFormat = append(exprDollar[1].LabelsFormat, exprDollar[3].LabelFormat)
 		}
-	case 111:
+	case 153:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
 		{
 			exprVAL.LabelFormatExpr = newLabelFmtExpr(exprDollar[2].LabelsFormat)
 		}
-	case 112:
+	case 154:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.LabelFilter = log.NewStringLabelFilter(exprDollar[1].Matcher)
 		}
-	case 113:
+	case 155:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.LabelFilter = exprDollar[1].IPLabelFilter
 		}
-	case 114:
+	case 156:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.LabelFilter = exprDollar[1].UnitFilter
 		}
-	case 115:
+	case 157:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.LabelFilter = exprDollar[1].NumberFilter
 		}
-	case 116:
+	case 158:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.LabelFilter = exprDollar[2].LabelFilter
 		}
-	case 117:
+	case 159:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
 		{
 			exprVAL.LabelFilter = log.NewAndLabelFilter(exprDollar[1].LabelFilter, exprDollar[2].LabelFilter)
 		}
-	case 118:
+	case 160:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.LabelFilter = log.NewAndLabelFilter(exprDollar[1].LabelFilter, exprDollar[3].LabelFilter)
 		}
-	case 119:
+	case 161:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.LabelFilter = log.NewAndLabelFilter(exprDollar[1].LabelFilter, exprDollar[3].LabelFilter)
 		}
-	case 120:
+	case 162:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.LabelFilter = log.NewOrLabelFilter(exprDollar[1].LabelFilter, exprDollar[3].LabelFilter)
 		}
-	case 121:
+	case 163:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.LabelExtractionExpression = log.NewLabelExtractionExpr(exprDollar[1].str, exprDollar[3].str)
 		}
-	case 122:
+	case 164:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.LabelExtractionExpression = log.NewLabelExtractionExpr(exprDollar[1].str, exprDollar[1].str)
 		}
-	case 123:
+	case 165:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.LabelExtractionExpressionList = []log.LabelExtractionExpr{exprDollar[1].LabelExtractionExpression}
 		}
-	case 124:
+	case 166:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.LabelExtractionExpressionList = append(exprDollar[1].LabelExtractionExpressionList, exprDollar[3].LabelExtractionExpression)
 		}
-	case 125:
+	case 167:
 		exprDollar = exprS[exprpt-6 : exprpt+1]
 		{
 			exprVAL.IPLabelFilter = log.NewIPLabelFilter(exprDollar[5].str, exprDollar[1].str, log.LabelFilterEqual)
 		}
-	case 126:
+	case 168:
 		exprDollar = exprS[exprpt-6 : exprpt+1]
 		{
 			exprVAL.IPLabelFilter = log.NewIPLabelFilter(exprDollar[5].str, exprDollar[1].str, log.LabelFilterNotEqual)
 		}
-	case 127:
+	case 169:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.UnitFilter = exprDollar[1].DurationFilter
 		}
-	case 128:
+	case 170:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.UnitFilter = exprDollar[1].BytesFilter
 		}
-	case 129:
+	case 171:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.DurationFilter = log.NewDurationLabelFilter(log.LabelFilterGreaterThan, exprDollar[1].str, exprDollar[3].duration)
 		}
-	case 130:
+	case 172:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.DurationFilter = log.NewDurationLabelFilter(log.LabelFilterGreaterThanOrEqual, exprDollar[1].str, exprDollar[3].duration)
 		}
-	case 131:
+	case 173:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.DurationFilter = log.NewDurationLabelFilter(log.LabelFilterLesserThan, exprDollar[1].str, exprDollar[3].duration)
 		}
-	case 132:
+	case 174:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.DurationFilter = log.NewDurationLabelFilter(log.LabelFilterLesserThanOrEqual, exprDollar[1].str, exprDollar[3].duration)
 		}
-	case 133:
+	case 175:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.DurationFilter = log.NewDurationLabelFilter(log.LabelFilterNotEqual, exprDollar[1].str, exprDollar[3].duration)
 		}
-	case 134:
+	case 176:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.DurationFilter = log.NewDurationLabelFilter(log.LabelFilterEqual, exprDollar[1].str, exprDollar[3].duration)
 		}
-	case 135:
+	case 177:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.DurationFilter = log.NewDurationLabelFilter(log.LabelFilterEqual, exprDollar[1].str, exprDollar[3].duration)
 		}
-	case 136:
+	case 178:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.BytesFilter = log.NewBytesLabelFilter(log.LabelFilterGreaterThan, exprDollar[1].str, exprDollar[3].bytes)
 		}
-	case 137:
+	case 179:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.BytesFilter = log.NewBytesLabelFilter(log.LabelFilterGreaterThanOrEqual, exprDollar[1].str, exprDollar[3].bytes)
 		}
-	case 138:
+	case 180:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.BytesFilter = log.NewBytesLabelFilter(log.LabelFilterLesserThan, exprDollar[1].str, exprDollar[3].bytes)
 		}
-	case 139:
+	case 181:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.BytesFilter = log.NewBytesLabelFilter(log.LabelFilterLesserThanOrEqual, exprDollar[1].str, exprDollar[3].bytes)
 		}
-	case 140:
+	case 182:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.BytesFilter = log.NewBytesLabelFilter(log.LabelFilterNotEqual, exprDollar[1].str, exprDollar[3].bytes)
 		}
-	case 141:
+	case 183:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.BytesFilter = log.NewBytesLabelFilter(log.LabelFilterEqual, exprDollar[1].str, exprDollar[3].bytes)
 		}
-	case 142:
+	case 184:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.BytesFilter = log.NewBytesLabelFilter(log.LabelFilterEqual, exprDollar[1].str, exprDollar[3].bytes)
 		}
-	case 143:
+	case 185:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.NumberFilter = log.NewNumericLabelFilter(log.LabelFilterGreaterThan, exprDollar[1].str, mustNewFloat(exprDollar[3].str))
 		}
-	case 144:
+	case 186:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.NumberFilter = log.NewNumericLabelFilter(log.LabelFilterGreaterThanOrEqual, exprDollar[1].str, mustNewFloat(exprDollar[3].str))
 		}
-	case 145:
+	case 187:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.NumberFilter = log.NewNumericLabelFilter(log.LabelFilterLesserThan, exprDollar[1].str, mustNewFloat(exprDollar[3].str))
 		}
-	case 146:
+	case 188:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.NumberFilter = log.NewNumericLabelFilter(log.LabelFilterLesserThanOrEqual, exprDollar[1].str, mustNewFloat(exprDollar[3].str))
 		}
-	case 147:
+	case 189:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.NumberFilter = log.NewNumericLabelFilter(log.LabelFilterNotEqual, exprDollar[1].str, mustNewFloat(exprDollar[3].str))
 		}
-	case 148:
+	case 190:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.NumberFilter = log.NewNumericLabelFilter(log.LabelFilterEqual, exprDollar[1].str, mustNewFloat(exprDollar[3].str))
 		}
-	case 149:
+	case 191:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.NumberFilter = log.NewNumericLabelFilter(log.LabelFilterEqual, exprDollar[1].str, mustNewFloat(exprDollar[3].str))
 		}
-	case 150:
+	case 192:
+		exprDollar = exprS[exprpt-3 : exprpt+1]
+		{
+			exprVAL.NumberFilter = log.NewNumericLabelFilter(log.LabelFilterGreaterThan, exprDollar[1].str, mustNewFloat(exprDollar[3].str))
+		}
+	case 193:
+		exprDollar = exprS[exprpt-3 : exprpt+1]
+		{
+			exprVAL.NumberFilter = log.NewNumericLabelFilter(log.LabelFilterGreaterThanOrEqual, exprDollar[1].str, mustNewFloat(exprDollar[3].str))
+		}
+	case 194:
+		exprDollar = exprS[exprpt-3 : exprpt+1]
+		{
+			exprVAL.NumberFilter = log.NewNumericLabelFilter(log.LabelFilterLesserThan, exprDollar[1].str, mustNewFloat(exprDollar[3].str))
+		}
+	case 195:
+		exprDollar = exprS[exprpt-3 : exprpt+1]
+		{
+			exprVAL.NumberFilter = log.NewNumericLabelFilter(log.LabelFilterLesserThanOrEqual, exprDollar[1].str, mustNewFloat(exprDollar[3].str))
+		}
+	case 196:
+		exprDollar = exprS[exprpt-3 : exprpt+1]
+		{
+			exprVAL.NumberFilter = log.NewNumericLabelFilter(log.LabelFilterEqual, exprDollar[1].str, mustNewFloat(exprDollar[3].str))
+		}
+	case 197:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.DropLabel = log.NewDropLabel(nil, exprDollar[1].str)
 		}
-	case 151:
+	case 198:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.DropLabel = log.NewDropLabel(exprDollar[1].Matcher, "")
 		}
-	case 152:
+	case 199:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.DropLabels = []log.DropLabel{exprDollar[1].DropLabel}
 		}
-	case 153:
+	case 200:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.DropLabels = append(exprDollar[1].DropLabels, exprDollar[3].DropLabel)
 		}
-	case 154:
+	case 201:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
 		{
 			exprVAL.DropLabelsExpr = newDropLabelsExpr(exprDollar[2].DropLabels)
 		}
-	case 155:
+	case 202:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.KeepLabel = log.NewKeepLabel(nil, exprDollar[1].str)
 		}
-	case 156:
+	case 203:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.KeepLabel = log.NewKeepLabel(exprDollar[1].Matcher, "")
 		}
-	case 157:
+	case 204:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.KeepLabels = []log.KeepLabel{exprDollar[1].KeepLabel}
 		}
-	case 158:
+	case 205:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.KeepLabels = append(exprDollar[1].KeepLabels, exprDollar[3].KeepLabel)
 		}
-	case 159:
+	case 206:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
 		{
 			exprVAL.KeepLabelsExpr = newKeepLabelsExpr(exprDollar[2].KeepLabels)
 		}
-	case 160:
+	case 207:
+		exprDollar = exprS[exprpt-2 : exprpt+1]
+		{
+			exprVAL.PipelineStage = newDistinctFilterExpr(exprDollar[2].Labels)
+		}
+	case 208:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+		{
+			exprVAL.PipelineStage = newDedupExpr(0)
+		}
+	case 209:
+		exprDollar = exprS[exprpt-2 : exprpt+1]
+		{
+			exprVAL.PipelineStage = newDedupExpr(exprDollar[2].duration)
+		}
+	case 210:
+		exprDollar = exprS[exprpt-6 : exprpt+1]
+		{
+			exprVAL.PipelineStage = newLimitByExpr(exprDollar[2].str, exprDollar[5].Labels, "")
+		}
+	case 211:
+		exprDollar = exprS[exprpt-7 : exprpt+1]
+		{
+			exprVAL.PipelineStage = newLimitByExpr(exprDollar[2].str, exprDollar[5].Labels, exprDollar[7].str)
+		}
+	case 212:
+		exprDollar = exprS[exprpt-2 : exprpt+1]
+		{
+			exprVAL.PipelineStage = newUnnestExpr(exprDollar[2].str)
+		}
+	case 213:
+		exprDollar = exprS[exprpt-2 : exprpt+1]
+		{
+			exprVAL.PipelineStage = newUnnestExpr(exprDollar[2].str)
+		}
+	case 214:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+		{
+			exprVAL.Labels = []string{exprDollar[1].str}
+		}
+	case 215:
+		exprDollar = exprS[exprpt-3 : exprpt+1]
+		{
+			exprVAL.Labels = append(exprDollar[1].Labels, exprDollar[3].str)
+		}
+	case 216:
+		exprDollar = exprS[exprpt-2 : exprpt+1]
+		{
+			exprVAL.PipelineStage = newDecodeExpr(exprDollar[2].Labels, "")
+		}
+	case 217:
+		exprDollar = exprS[exprpt-3 : exprpt+1]
+		{
+			exprVAL.PipelineStage = newDecodeExpr(exprDollar[2].Labels, exprDollar[3].str)
+		}
+	case 218:
+		exprDollar = exprS[exprpt-2 : exprpt+1]
+		{
+			exprVAL.PipelineStage = newJSONUnescapeExpr(exprDollar[2].str)
+		}
+	case 219:
+		exprDollar = exprS[exprpt-2 : exprpt+1]
+		{
+			exprVAL.PipelineStage = newJSONUnescapeExpr(exprDollar[2].str)
+		}
+	case 220:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr("or", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 161:
+	case 221:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr("and", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 162:
+	case 222:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr("unless", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 163:
+	case 223:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr("+", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 164:
+	case 224:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr("-", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 165:
+	case 225:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr("*", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 166:
+	case 226:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr("/", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 167:
+	case 227:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr("%", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 168:
+	case 228:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr("^", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 169:
+	case 229:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr("==", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 170:
+	case 230:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr("!=", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 171:
+	case 231:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr(">", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 172:
+	case 232:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr(">=", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 173:
+	case 233:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr("<", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 174:
+	case 234:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr("<=", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 175:
+	case 235:
 		exprDollar = exprS[exprpt-0 : exprpt+1]
 		{
 			exprVAL.BoolModifier = &BinOpOptions{VectorMatching: &VectorMatching{Card: CardOneToOne}}
 		}
-	case 176:
+	case 236:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.BoolModifier = &BinOpOptions{VectorMatching: &VectorMatching{Card: CardOneToOne}, ReturnBool: true}
 		}
-	case 177:
+	case 237:
 		exprDollar = exprS[exprpt-5 : exprpt+1]
 		{
 			exprVAL.OnOrIgnoringModifier = exprDollar[1].BoolModifier
 			exprVAL.OnOrIgnoringModifier.VectorMatching.On = true
 			exprVAL.OnOrIgnoringModifier.VectorMatching.MatchingLabels = exprDollar[4].Labels
 		}
-	case 178:
+	case 238:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
 		{
 			exprVAL.OnOrIgnoringModifier = exprDollar[1].BoolModifier
 			exprVAL.OnOrIgnoringModifier.VectorMatching.On = true
 		}
-	case 179:
+	case 239:
 		exprDollar = exprS[exprpt-5 : exprpt+1]
 		{
 			exprVAL.OnOrIgnoringModifier = exprDollar[1].BoolModifier
 			exprVAL.OnOrIgnoringModifier.VectorMatching.MatchingLabels = exprDollar[4].Labels
 		}
-	case 180:
+	case 240:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
 		{
 			exprVAL.OnOrIgnoringModifier = exprDollar[1].BoolModifier
 		}
-	case 181:
+	case 241:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.BinOpModifier = exprDollar[1].BoolModifier
 		}
-	case 182:
+	case 242:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.BinOpModifier = exprDollar[1].OnOrIgnoringModifier
 		}
-	case 183:
+	case 243:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
 		{
 			exprVAL.BinOpModifier = exprDollar[1].OnOrIgnoringModifier
 			exprVAL.BinOpModifier.VectorMatching.Card = CardManyToOne
 		}
-	case 184:
+	case 244:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
 		{
 			exprVAL.BinOpModifier = exprDollar[1].OnOrIgnoringModifier
 			exprVAL.BinOpModifier.VectorMatching.Card = CardManyToOne
 		}
-	case 185:
+	case 245:
 		exprDollar = exprS[exprpt-5 : exprpt+1]
 		{
 			exprVAL.BinOpModifier = exprDollar[1].OnOrIgnoringModifier
 			exprVAL.BinOpModifier.VectorMatching.Card = CardManyToOne
 			exprVAL.BinOpModifier.VectorMatching.Include = exprDollar[4].Labels
 		}
-	case 186:
+	case 246:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
 		{
 			exprVAL.BinOpModifier = exprDollar[1].OnOrIgnoringModifier
 			exprVAL.BinOpModifier.VectorMatching.Card = CardOneToMany
 		}
-	case 187:
+	case 247:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
 		{
 			exprVAL.BinOpModifier = exprDollar[1].OnOrIgnoringModifier
 			exprVAL.BinOpModifier.VectorMatching.Card = CardOneToMany
 		}
-	case 188:
+	case 248:
 		exprDollar = exprS[exprpt-5 : exprpt+1]
 		{
 			exprVAL.BinOpModifier = exprDollar[1].OnOrIgnoringModifier
 			exprVAL.BinOpModifier.VectorMatching.Card = CardOneToMany
 			exprVAL.BinOpModifier.VectorMatching.Include = exprDollar[4].Labels
 		}
-	case 189:
+	case 249:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.LiteralExpr = mustNewLiteralExpr(exprDollar[1].str, false)
 		}
-	case 190:
+	case 250:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
 		{
 			exprVAL.LiteralExpr = mustNewLiteralExpr(exprDollar[2].str, false)
 		}
-	case 191:
+	case 251:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
 		{
 			exprVAL.LiteralExpr = mustNewLiteralExpr(exprDollar[2].str, true)
 		}
-	case 192:
+	case 252:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+		{
+			exprVAL.LiteralExpr = mustNewLiteralExpr(exprDollar[1].str, false)
+		}
+	case 253:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
 		{
 			exprVAL.VectorExpr = NewVectorExpr(exprDollar[3].str)
 		}
-	case 193:
+	case 254:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.Vector = OpTypeVector
 		}
-	case 194:
+	case 255:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.VectorOp = OpTypeSum
 		}
-	case 195:
+	case 256:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.VectorOp = OpTypeAvg
 		}
-	case 196:
+	case 257:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.VectorOp = OpTypeCount
 		}
-	case 197:
+	case 258:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.VectorOp = OpTypeMax
 		}
-	case 198:
+	case 259:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.VectorOp = OpTypeMin
 		}
-	case 199:
+	case 260:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.VectorOp = OpTypeStddev
 		}
-	case 200:
+	case 261:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.VectorOp = OpTypeStdvar
 		}
-	case 201:
+	case 262:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.VectorOp = OpTypeBottomK
 		}
-	case 202:
+	case 263:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.VectorOp = OpTypeTopK
 		}
-	case 203:
+	case 264:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.VectorOp = OpTypeSort
 		}
-	case 204:
+	case 265:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.VectorOp = OpTypeSortDesc
 		}
-	case 205:
+	case 266:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+		{
+			exprVAL.VectorOp = OpTypeGroup
+		}
+	case 267:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+		{
+			exprVAL.VectorOp = OpTypeQuantile
+		}
+	case 268:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+		{
+			exprVAL.FunctionOp = OpFuncAbs
+		}
+	case 269:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+		{
+			exprVAL.FunctionOp = OpFuncCeil
+		}
+	case 270:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+		{
+			exprVAL.FunctionOp = OpFuncFloor
+		}
+	case 271:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+		{
+			exprVAL.FunctionOp = OpFuncLn
+		}
+	case 272:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+		{
+			exprVAL.FunctionOp = OpFuncRound
+		}
+	case 273:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+		{
+			exprVAL.FunctionOp = OpFuncClampMin
+		}
+	case 274:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+		{
+			exprVAL.FunctionOp = OpFuncClampMax
+		}
+	case 275:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+		{
+			exprVAL.FunctionOp = OpFuncTimestamp
+		}
+	case 276:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+		{
+			exprVAL.FunctionOp = OpFuncScalar
+		}
+	case 277:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+		{
+			exprVAL.FunctionOp = OpFuncAbsent
+		}
+	case 278:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.RangeOp = OpRangeTypeCount
 		}
-	case 206:
+	case 279:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.RangeOp = OpRangeTypeRate
 		}
-	case 207:
+	case 280:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.RangeOp = OpRangeTypeRateCounter
 		}
-	case 208:
+	case 281:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.RangeOp = OpRangeTypeBytes
 		}
-	case 209:
+	case 282:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.RangeOp = OpRangeTypeBytesRate
 		}
-	case 210:
+	case 283:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.RangeOp = OpRangeTypeAvg
 		}
-	case 211:
+	case 284:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.RangeOp = OpRangeTypeSum
 		}
-	case 212:
+	case 285:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.RangeOp = OpRangeTypeMin
 		}
-	case 213:
+	case 286:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.RangeOp = OpRangeTypeMax
 		}
-	case 214:
+	case 287:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.RangeOp = OpRangeTypeStdvar
 		}
-	case 215:
+	case 288:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.RangeOp = OpRangeTypeStddev
 		}
-	case 216:
+	case 289:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.RangeOp = OpRangeTypeQuantile
 		}
-	case 217:
+	case 290:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.RangeOp = OpRangeTypeFirst
 		}
-	case 218:
+	case 291:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.RangeOp = OpRangeTypeLast
 		}
-	case 219:
+	case 292:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.RangeOp = OpRangeTypeAbsent
 		}
-	case 220:
+	case 293:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+		{
+			exprVAL.RangeOp = OpRangeTypeDelta
+		}
+	case 294:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+		{
+			exprVAL.RangeOp = OpRangeTypeDeriv
+		}
+	case 295:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
 		{
 			exprVAL.OffsetExpr = newOffsetExpr(exprDollar[2].duration)
 		}
-	case 221:
+	case 296:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.Labels = []string{exprDollar[1].str}
 		}
-	case 222:
+	case 297:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.Labels = append(exprDollar[1].Labels, exprDollar[3].str)
 		}
-	case 223:
+	case 298:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
 		{
 			exprVAL.Grouping = &Grouping{Without: false, Groups: exprDollar[3].Labels}
 		}
-	case 224:
+	case 299:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
 		{
 			exprVAL.Grouping = &Grouping{Without: true, Groups: exprDollar[3].Labels}
 		}
-	case 225:
+	case 300:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.Grouping = &Grouping{Without: false, Groups: nil}
 		}
-	case 226:
+	case 301:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.Grouping = &Grouping{Without: true, Groups: nil}
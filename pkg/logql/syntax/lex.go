@@ -1,6 +1,7 @@
 package syntax
 
 import (
+	"fmt"
 	"strings"
 	"text/scanner"
 	"time"
@@ -64,6 +65,9 @@ var tokens = map[string]int{
 	OpParserTypeLogfmt:  LOGFMT,
 	OpParserTypeUnpack:  UNPACK,
 	OpParserTypePattern: PATTERN,
+	OpParserTypeCSV:     CSV,
+	OpParserTypeXML:     XML,
+	OpParserTypeKV:      KV,
 
 	// fmt
 	OpFmtLabel: LABEL_FMT,
@@ -78,6 +82,20 @@ var tokens = map[string]int{
 
 	// keep labels
 	OpKeep: KEEP,
+
+	// distinct and dedup filters
+	OpDistinct: DISTINCT,
+	OpDedup:    DEDUP,
+
+	// limit by filter
+	OpLimit: LIMIT,
+
+	// unnest
+	OpUnnest: UNNEST,
+
+	// decoding
+	OpDecode:       DECODE,
+	OpJSONUnescape: JSON_UNESCAPE,
 }
 
 var parserFlags = map[string]struct{}{
@@ -88,36 +106,60 @@ var parserFlags = map[string]struct{}{
 // functionTokens are tokens that needs to be suffixes with parenthesis
 var functionTokens = map[string]int{
 	// range vec ops
-	OpRangeTypeRate:        RATE,
-	OpRangeTypeRateCounter: RATE_COUNTER,
-	OpRangeTypeCount:       COUNT_OVER_TIME,
-	OpRangeTypeBytesRate:   BYTES_RATE,
-	OpRangeTypeBytes:       BYTES_OVER_TIME,
-	OpRangeTypeAvg:         AVG_OVER_TIME,
-	OpRangeTypeSum:         SUM_OVER_TIME,
-	OpRangeTypeMin:         MIN_OVER_TIME,
-	OpRangeTypeMax:         MAX_OVER_TIME,
-	OpRangeTypeStdvar:      STDVAR_OVER_TIME,
-	OpRangeTypeStddev:      STDDEV_OVER_TIME,
-	OpRangeTypeQuantile:    QUANTILE_OVER_TIME,
-	OpRangeTypeFirst:       FIRST_OVER_TIME,
-	OpRangeTypeLast:        LAST_OVER_TIME,
-	OpRangeTypeAbsent:      ABSENT_OVER_TIME,
-	OpTypeVector:           VECTOR,
+	OpRangeTypeRate:          RATE,
+	OpRangeTypeRateCounter:   RATE_COUNTER,
+	OpRangeTypeCount:         COUNT_OVER_TIME,
+	OpRangeTypeBytesRate:     BYTES_RATE,
+	OpRangeTypeBytes:         BYTES_OVER_TIME,
+	OpRangeTypeAvg:           AVG_OVER_TIME,
+	OpRangeTypeSum:           SUM_OVER_TIME,
+	OpRangeTypeMin:           MIN_OVER_TIME,
+	OpRangeTypeMax:           MAX_OVER_TIME,
+	OpRangeTypeStdvar:        STDVAR_OVER_TIME,
+	OpRangeTypeStddev:        STDDEV_OVER_TIME,
+	OpRangeTypeQuantile:      QUANTILE_OVER_TIME,
+	OpRangeTypeFirst:         FIRST_OVER_TIME,
+	OpRangeTypeLast:          LAST_OVER_TIME,
+	OpRangeTypeAbsent:        ABSENT_OVER_TIME,
+	OpRangeTypeDelta:         DELTA,
+	OpRangeTypeDeriv:         DERIV,
+	OpRangeTypePredictLinear: PREDICT_LINEAR,
+	OpTypeVector:             VECTOR,
 
 	// vec ops
-	OpTypeSum:      SUM,
-	OpTypeAvg:      AVG,
-	OpTypeMax:      MAX,
-	OpTypeMin:      MIN,
-	OpTypeCount:    COUNT,
-	OpTypeStddev:   STDDEV,
-	OpTypeStdvar:   STDVAR,
-	OpTypeBottomK:  BOTTOMK,
-	OpTypeTopK:     TOPK,
-	OpTypeSort:     SORT,
-	OpTypeSortDesc: SORT_DESC,
-	OpLabelReplace: LABEL_REPLACE,
+	OpTypeSum:         SUM,
+	OpTypeAvg:         AVG,
+	OpTypeMax:         MAX,
+	OpTypeMin:         MIN,
+	OpTypeCount:       COUNT,
+	OpTypeStddev:      STDDEV,
+	OpTypeStdvar:      STDVAR,
+	OpTypeBottomK:     BOTTOMK,
+	OpTypeTopK:        TOPK,
+	OpTypeSort:        SORT,
+	OpTypeSortDesc:    SORT_DESC,
+	OpTypeGroup:       GROUP,
+	OpTypeQuantile:    QUANTILE,
+	OpTypeCountValues: COUNT_VALUES,
+	OpLabelReplace:    LABEL_REPLACE,
+	OpLabelJoin:       LABEL_JOIN,
+
+	// log joins
+	OpJoin: JOIN,
+
+	// functions
+	OpFuncAbs:               ABS,
+	OpFuncCeil:              CEIL,
+	OpFuncFloor:             FLOOR,
+	OpFuncLn:                LN,
+	OpFuncRound:             ROUND,
+	OpFuncClampMin:          CLAMP_MIN,
+	OpFuncClampMax:          CLAMP_MAX,
+	OpFuncTimestamp:         TIMESTAMP,
+	OpFuncScalar:            SCALAR,
+	OpFuncAbsent:            ABSENT,
+	OpFuncHistogramQuantile: HISTOGRAM_QUANTILE,
+	OpFuncTime:              TIME,
 
 	// conversion Op
 	OpConvBytes:           BYTES_CONV,
@@ -132,6 +174,8 @@ type lexer struct {
 	Scanner
 	errs    []logqlmodel.ParseError
 	builder strings.Builder
+	// params holds the values bound to the parameters of the query, e.g. $app.
+	params map[string]string
 }
 
 func (l *lexer) Lex(lval *exprSymType) int {
@@ -180,6 +224,18 @@ func (l *lexer) Lex(lval *exprSymType) int {
 			return DURATION
 		}
 
+	case '$':
+		line, col := l.Line, l.Column
+		if name, ok := tryScanParam(&l.Scanner); ok {
+			v, ok := l.params[name]
+			if !ok {
+				l.errs = append(l.errs, logqlmodel.NewParseError(fmt.Sprintf("parameter $%s is not bound", name), line, col))
+				return 0
+			}
+			lval.str = v
+			return PARAM
+		}
+
 	case scanner.String, scanner.RawString:
 		var err error
 		tokenText := l.TokenText()
@@ -200,6 +256,15 @@ func (l *lexer) Lex(lval *exprSymType) int {
 		l.builder.Reset()
 		for r := l.Next(); r != scanner.EOF; r = l.Next() {
 			if r == ']' {
+				if rng, step, ok := strings.Cut(l.builder.String(), ":"); ok {
+					sr, err := parseSubqueryRange(rng, step)
+					if err != nil {
+						l.Error(err.Error())
+						return 0
+					}
+					lval.subqueryRange = sr
+					return SUBQUERY_RANGE
+				}
 				i, err := model.ParseDuration(l.builder.String())
 				if err != nil {
 					l.Error(err.Error())
@@ -334,6 +399,29 @@ func parseDuration(d string) (time.Duration, error) {
 	return duration, nil
 }
 
+// subqueryRange is the `[<range>:<step>]` of a subquery, the step is optional.
+type subqueryRange struct {
+	Range, Step time.Duration
+}
+
+func parseSubqueryRange(rng, step string) (subqueryRange, error) {
+	var sr subqueryRange
+	r, err := model.ParseDuration(rng)
+	if err != nil {
+		return sr, err
+	}
+	sr.Range = time.Duration(r)
+	if step == "" {
+		return sr, nil
+	}
+	s, err := model.ParseDuration(step)
+	if err != nil {
+		return sr, err
+	}
+	sr.Step = time.Duration(s)
+	return sr, nil
+}
+
 func isDurationRune(r rune) bool {
 	// "ns", "us" (or "µs"), "ms", "s", "m", "h", "d", "w", "y".
 	switch r {
@@ -374,6 +462,20 @@ func tryScanBytes(number string, l *Scanner) (uint64, bool) {
 	return b, true
 }
 
+// tryScanParam scans the name of a parameter following a $,
+// it advances the scanner only if a valid name is found.
+func tryScanParam(l *Scanner) (string, bool) {
+	var sb strings.Builder
+	for r := l.Peek(); isParamRune(r, sb.Len() == 0); r = l.Peek() {
+		_, _ = sb.WriteRune(l.Next())
+	}
+	return sb.String(), sb.Len() > 0
+}
+
+func isParamRune(r rune, first bool) bool {
+	return r == '_' || ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z') || (!first && '0' <= r && r <= '9')
+}
+
 func isBytesSizeRune(r rune) bool {
 	// Accept: B, kB, MB, GB, TB, PB, KB, KiB, MiB, GiB, TiB, PiB
 	// Do not accept: EB, ZB, YB, PiB, ZiB and YiB. They are not supported since the value migh not be represented in an uint64
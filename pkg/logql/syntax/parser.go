@@ -64,7 +64,15 @@ func (p *parser) Parse() (Expr, error) {
 
 // ParseExpr parses a string and returns an Expr.
 func ParseExpr(input string) (Expr, error) {
-	expr, err := ParseExprWithoutValidation(input)
+	return ParseExprWithParams(input, nil)
+}
+
+// ParseExprWithParams parses a string binding the values of its parameters and returns an Expr.
+// A parameter is written $name, a string parameter can be the value of a matcher or a line filter and
+// a number parameter the number of a label filter or a literal, e.g. `{app=$app} |= $needle | status >= $status`.
+// The values are bound as is, without being quoted or escaped.
+func ParseExprWithParams(input string, params map[string]string) (Expr, error) {
+	expr, err := parseExpr(input, params)
 	if err != nil {
 		return nil, err
 	}
@@ -74,7 +82,24 @@ func ParseExpr(input string) (Expr, error) {
 	return expr, nil
 }
 
+// BindParams binds the values of the parameters of a query and returns the resulting query.
+// The query is returned unchanged when there are no parameters.
+func BindParams(input string, params map[string]string) (string, error) {
+	if len(params) == 0 {
+		return input, nil
+	}
+	expr, err := ParseExprWithParams(input, params)
+	if err != nil {
+		return "", err
+	}
+	return expr.String(), nil
+}
+
 func ParseExprWithoutValidation(input string) (expr Expr, err error) {
+	return parseExpr(input, nil)
+}
+
+func parseExpr(input string, params map[string]string) (expr Expr, err error) {
 	if len(input) >= maxInputSize {
 		return nil, logqlmodel.NewParseError(fmt.Sprintf("input size too long (%d > %d)", len(input), maxInputSize), 0, 0)
 	}
@@ -96,6 +121,8 @@ func ParseExprWithoutValidation(input string) (expr Expr, err error) {
 
 	p.Reader.Reset(input)
 	p.lexer.Init(p.Reader)
+	p.lexer.params = params
+	defer func() { p.lexer.params = nil }()
 	return p.Parse()
 }
 
@@ -105,6 +132,11 @@ func validateExpr(expr Expr) error {
 		return validateSampleExpr(e)
 	case LogSelectorExpr:
 		return validateLogSelectorExpression(e)
+	case *JoinExpr:
+		if err := validateLogSelectorExpression(e.Left); err != nil {
+			return err
+		}
+		return validateLogSelectorExpression(e.Right)
 	default:
 		return logqlmodel.NewParseError(fmt.Sprintf("unexpected expression type: %v", e), 0, 0)
 	}
@@ -191,24 +223,115 @@ func validateSampleExpr(expr SampleExpr) error {
 			}
 		}
 		return validateSampleExpr(e.Left)
+	case *SubqueryAggregationExpr:
+		if e.err != nil {
+			return e.err
+		}
+		return validateSampleExpr(e.Left.Left)
+	case *FunctionExpr:
+		if e.err != nil {
+			return e.err
+		}
+		return validateSampleExpr(e.Left)
+	case *LabelJoinExpr:
+		if e.err != nil {
+			return e.err
+		}
+		return validateSampleExpr(e.Left)
+	case *TimeExpr:
+		return nil
 	default:
 		selector, err := e.Selector()
 		if err != nil {
 			return err
 		}
+		if err := ValidateNoMergeStages(selector); err != nil {
+			return err
+		}
 		return validateLogSelectorExpression(selector)
 	}
 }
 
 func validateLogSelectorExpression(expr LogSelectorExpr) error {
 	switch e := expr.(type) {
-	case *VectorExpr:
+	case *VectorExpr, *TimeExpr:
 		return nil
 	default:
+		if err := validateUnnest(e); err != nil {
+			return err
+		}
+		if err := validateMergeStages(e); err != nil {
+			return err
+		}
 		return validateMatchers(e.Matchers())
 	}
 }
 
+// mergeStageName returns the name of the distinct, dedup and limit stages, which are only applied where the streams
+// of a log query are merged, or an empty string for the other stages.
+func mergeStageName(e interface{}) string {
+	switch e.(type) {
+	case *DistinctFilterExpr:
+		return OpDistinct
+	case *DedupExpr:
+		return OpDedup
+	case *LimitByExpr:
+		return OpLimit
+	}
+	return ""
+}
+
+// ValidateNoMergeStages rejects the distinct, dedup and limit stages where the streams of a log query are not merged,
+// like in metric queries, ingestion pipelines, retention filters or delete requests, as they would do nothing.
+func ValidateNoMergeStages(expr LogSelectorExpr) error {
+	var name string
+	expr.Walk(func(e interface{}) {
+		if n := mergeStageName(e); n != "" && name == "" {
+			name = n
+		}
+	})
+	if name != "" {
+		return logqlmodel.NewParseError(fmt.Sprintf("the %s stage is only supported in log queries", name), 0, 0)
+	}
+	return nil
+}
+
+// validateMergeStages only allows the distinct, dedup and limit stages at the end of a pipeline, as they are applied
+// to the merged streams of the query, and the limit stage last.
+func validateMergeStages(expr LogSelectorExpr) error {
+	p, ok := expr.(*PipelineExpr)
+	if !ok {
+		return nil
+	}
+	for i, s := range p.MultiStages {
+		if _, ok := s.(*LimitByExpr); ok && i != len(p.MultiStages)-1 {
+			return logqlmodel.NewParseError("the limit stage must be the last stage of the pipeline", 0, 0)
+		}
+		if name := mergeStageName(s); name != "" {
+			for _, next := range p.MultiStages[i+1:] {
+				if mergeStageName(next) == "" {
+					return logqlmodel.NewParseError(fmt.Sprintf("the %s stage can only be followed by the distinct, dedup and limit stages", name), 0, 0)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// validateUnnest prevents a log pipeline from fanning out lines more than once.
+func validateUnnest(expr LogSelectorExpr) error {
+	var count int
+	expr.Walk(func(e interface{}) {
+		if _, ok := e.(*UnnestExpr); ok {
+			count++
+		}
+	})
+	if count > 1 {
+		return logqlmodel.NewParseError("only one unnest stage is allowed per query", 0, 0)
+	}
+	return nil
+}
+
 // validateSortGrouping prevent by|without groupings on sort operations.
 // This will keep compatibility with promql and allowing sort by (foo) doesn't make much sense anyway when sort orders by value instead of labels.
 func validateSortGrouping(grouping *Grouping) error {
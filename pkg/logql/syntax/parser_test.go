@@ -52,6 +52,176 @@ func TestParse(t *testing.T) {
 				},
 			),
 		},
+		{
+			in: `{ foo = "bar" } | logfmt | distinct id, host | dedup`,
+			exp: newPipelineExpr(
+				newMatcherExpr([]*labels.Matcher{mustNewMatcher(labels.MatchEqual, "foo", "bar")}),
+				MultiStageExpr{
+					newLogfmtParserExpr(nil),
+					newDistinctFilterExpr([]string{"id", "host"}),
+					newDedupExpr(0),
+				},
+			),
+		},
+		{
+			in: `{ foo = "bar" } | unnest items | sku = "a"`,
+			exp: newPipelineExpr(
+				newMatcherExpr([]*labels.Matcher{mustNewMatcher(labels.MatchEqual, "foo", "bar")}),
+				MultiStageExpr{
+					newUnnestExpr("items"),
+					&LabelFilterExpr{LabelFilterer: log.NewStringLabelFilter(mustNewMatcher(labels.MatchEqual, "sku", "a"))},
+				},
+			),
+		},
+		{
+			in: `sum by (sku) (count_over_time({ foo = "bar" } | unnest "order.items" [5m]))`,
+			exp: mustNewVectorAggregationExpr(
+				newRangeAggregationExpr(
+					newLogRange(newPipelineExpr(
+						newMatcherExpr([]*labels.Matcher{mustNewMatcher(labels.MatchEqual, "foo", "bar")}),
+						MultiStageExpr{
+							newUnnestExpr("order.items"),
+						},
+					),
+						5*time.Minute,
+						nil, nil),
+					OpRangeTypeCount, nil, nil,
+				),
+				OpTypeSum,
+				&Grouping{Groups: []string{"sku"}},
+				nil,
+			),
+		},
+		{
+			in: `{ foo = "bar" } | csv sep=";" "type;time" | kv | xml | xml id="/Event/@Id"`,
+			exp: newPipelineExpr(
+				newMatcherExpr([]*labels.Matcher{mustNewMatcher(labels.MatchEqual, "foo", "bar")}),
+				MultiStageExpr{
+					&CSVParserExpr{Header: "type;time", Separator: ";"},
+					&KVParserExpr{Separator: ",", Assign: "="},
+					newLabelParserExpr(OpParserTypeXML, ""),
+					newXMLExpressionParser([]log.LabelExtractionExpr{log.NewLabelExtractionExpr("id", "/Event/@Id")}),
+				},
+			),
+		},
+		{
+			in: `sum by (status) (count_over_time({ foo = "bar" } | kv sep=";" assign=":" [5m]))`,
+			exp: mustNewVectorAggregationExpr(
+				newRangeAggregationExpr(
+					newLogRange(newPipelineExpr(
+						newMatcherExpr([]*labels.Matcher{mustNewMatcher(labels.MatchEqual, "foo", "bar")}),
+						MultiStageExpr{
+							&KVParserExpr{Separator: ";", Assign: ":"},
+						},
+					),
+						5*time.Minute,
+						nil, nil),
+					OpRangeTypeCount, nil, nil,
+				),
+				OpTypeSum,
+				&Grouping{Groups: []string{"status"}},
+				nil,
+			),
+		},
+		{
+			in: `{ foo = "bar" } | decode gzip+base64 |= "error" | json | decode url path | json_unescape "request.body"`,
+			exp: newPipelineExpr(
+				newMatcherExpr([]*labels.Matcher{mustNewMatcher(labels.MatchEqual, "foo", "bar")}),
+				MultiStageExpr{
+					newDecodeExpr([]string{"gzip", "base64"}, ""),
+					newLineFilterExpr(labels.MatchEqual, "", "error"),
+					newLabelParserExpr(OpParserTypeJSON, ""),
+					newDecodeExpr([]string{"url"}, "path"),
+					newJSONUnescapeExpr("request.body"),
+				},
+			),
+		},
+		{
+			in:  `{ foo = "bar" } | decode zstd`,
+			err: logqlmodel.NewParseError("invalid decode stage: unsupported encoding 'zstd'", 0, 0),
+		},
+		{
+			in:  `{ foo = "bar" } | csv "a,a"`,
+			err: logqlmodel.NewParseError("invalid csv parser: duplicate csv column name 'a'", 0, 0),
+		},
+		{
+			in:  `{ foo = "bar" } | csv quote="'" "a,b"`,
+			err: logqlmodel.NewParseError("invalid csv parser option: quote", 0, 0),
+		},
+		{
+			in:  `{ foo = "bar" } | kv sep=";" sep=","`,
+			err: logqlmodel.NewParseError("duplicate kv parser option: sep", 0, 0),
+		},
+		{
+			in:  `{ foo = "bar" } | xml id="//EventID"`,
+			err: logqlmodel.NewParseError("invalid xml parser: cannot parse expression [//EventID]: descendant paths are not supported", 0, 0),
+		},
+		{
+			in:  `{ foo = "bar" } | unnest items | unnest skus`,
+			err: logqlmodel.NewParseError("only one unnest stage is allowed per query", 0, 0),
+		},
+		{
+			in: `{ foo = "bar" } | json | limit 20 by (pod, container) sample`,
+			exp: newPipelineExpr(
+				newMatcherExpr([]*labels.Matcher{mustNewMatcher(labels.MatchEqual, "foo", "bar")}),
+				MultiStageExpr{
+					newLabelParserExpr(OpParserTypeJSON, ""),
+					&LimitByExpr{Limit: 20, Labels: []string{"pod", "container"}, Sample: true},
+				},
+			),
+		},
+		{
+			in: `join({ app = "gateway" } | json, { app = "backend" }, "request_id", 5s)`,
+			exp: &JoinExpr{
+				Left: newPipelineExpr(
+					newMatcherExpr([]*labels.Matcher{mustNewMatcher(labels.MatchEqual, "app", "gateway")}),
+					MultiStageExpr{newLabelParserExpr(OpParserTypeJSON, "")},
+				),
+				Right:     newMatcherExpr([]*labels.Matcher{mustNewMatcher(labels.MatchEqual, "app", "backend")}),
+				Label:     "request_id",
+				Tolerance: 5 * time.Second,
+			},
+		},
+		{
+			in:  `join({ app = "gateway" }, { app = "backend" }, "request-id", 5s)`,
+			err: logqlmodel.NewParseError(`invalid join label "request-id"`, 0, 0),
+		},
+		{
+			in:  `join({ app = "gateway" }, { app = "backend" }, "request_id", 0s)`,
+			err: logqlmodel.NewParseError("invalid join tolerance 0s, it must be positive", 0, 0),
+		},
+		{
+			in:  `join({ app = "gateway" }, count_over_time({ app = "backend" }[5m]), "request_id", 5s)`,
+			err: logqlmodel.NewParseError("syntax error: unexpected COUNT_OVER_TIME, expecting { or (", 1, 27),
+		},
+		{
+			in:  `{ foo = "bar" } | limit 0 by (pod)`,
+			err: logqlmodel.NewParseError("invalid limit 0, it must be a positive integer", 0, 0),
+		},
+		{
+			in:  `{ foo = "bar" } | limit 2.5 by (pod)`,
+			err: logqlmodel.NewParseError("invalid limit 2.5, it must be a positive integer", 0, 0),
+		},
+		{
+			in:  `{ foo = "bar" } | limit 5 by (pod) newest`,
+			err: logqlmodel.NewParseError("unexpected newest after limit, only sample is supported", 0, 0),
+		},
+		{
+			in:  `{ foo = "bar" } | limit 5 by (pod) | json`,
+			err: logqlmodel.NewParseError("the limit stage must be the last stage of the pipeline", 0, 0),
+		},
+		{
+			in:  `count_over_time({ foo = "bar" } | limit 5 by (pod) [5m])`,
+			err: logqlmodel.NewParseError("the limit stage is only supported in log queries", 0, 0),
+		},
+		{
+			in:  `count_over_time({ foo = "bar" } | dedup 500ms [5m])`,
+			err: logqlmodel.NewParseError("the dedup stage is only supported in log queries", 0, 0),
+		},
+		{
+			in:  `{ foo = "bar" } | distinct id | level="error"`,
+			err: logqlmodel.NewParseError("the distinct stage can only be followed by the distinct, dedup and limit stages", 0, 0),
+		},
 		{
 			// test [12h] before filter expr
 			in: `count_over_time({foo="bar"}[12h] |= "error")`,
@@ -386,6 +556,55 @@ func TestParse(t *testing.T) {
 			in:  `min({ foo = "bar" }[5m])`,
 			err: logqlmodel.NewParseError("syntax error: unexpected RANGE", 0, 20),
 		},
+		{
+			in: `max_over_time(sum by (svc) (rate({env="prod"} |= "error" [1m]))[1h:1m])`,
+			exp: &SubqueryAggregationExpr{
+				Operation: OpRangeTypeMax,
+				Left: &SubqueryExpr{
+					Left: mustNewVectorAggregationExpr(
+						newRangeAggregationExpr(
+							newLogRange(newPipelineExpr(
+								newMatcherExpr([]*labels.Matcher{mustNewMatcher(labels.MatchEqual, "env", "prod")}),
+								MultiStageExpr{newLineFilterExpr(labels.MatchEqual, "", "error")},
+							), time.Minute, nil, nil),
+							OpRangeTypeRate, nil, nil,
+						),
+						OpTypeSum, &Grouping{Groups: []string{"svc"}}, nil,
+					),
+					Range: time.Hour,
+					Step:  time.Minute,
+				},
+			},
+		},
+		{
+			in: `quantile_over_time(0.99, (count_over_time({ foo = "bar" }[5m]) / 2)[1d:] offset 1h)`,
+			exp: newSubqueryAggregationExpr(
+				&SubqueryExpr{
+					Left: mustNewBinOpExpr(OpTypeDiv, &BinOpOptions{VectorMatching: &VectorMatching{Card: CardOneToOne}},
+						newRangeAggregationExpr(
+							newLogRange(newMatcherExpr([]*labels.Matcher{mustNewMatcher(labels.MatchEqual, "foo", "bar")}), 5*time.Minute, nil, nil),
+							OpRangeTypeCount, nil, nil,
+						),
+						mustNewLiteralExpr("2", false),
+					),
+					Range:  24 * time.Hour,
+					Offset: time.Hour,
+				},
+				OpRangeTypeQuantile, NewStringLabelFilter("0.99"),
+			),
+		},
+		{
+			in:  `rate(sum(rate({ foo = "bar" }[5m]))[1h:1m])`,
+			err: logqlmodel.NewParseError("invalid aggregation rate of a subquery", 0, 0),
+		},
+		{
+			in:  `sum(rate({ foo = "bar" }[5m]))[1h:1m]`,
+			err: logqlmodel.NewParseError("syntax error: unexpected SUBQUERY_RANGE", 0, 31),
+		},
+		{
+			in:  `max_over_time(sum(rate({ foo = "bar" }[5m]))[1h:1minute])`,
+			err: logqlmodel.NewParseError(`unknown unit "minute" in duration "1minute"`, 0, 45),
+		},
 		// line filter for ip-matcher
 		{
 			in: `{foo="bar"} |= "baz" |= ip("123.123.123.123")`,
@@ -500,19 +719,19 @@ func TestParse(t *testing.T) {
 		// label filter for ip-matcher
 		{
 			in:  `{ foo = "bar" }|logfmt|addr>=ip("1.2.3.4")`,
-			err: logqlmodel.NewParseError("syntax error: unexpected ip, expecting BYTES or NUMBER or DURATION", 1, 30),
+			err: logqlmodel.NewParseError("syntax error: unexpected ip, expecting BYTES or NUMBER or PARAM or DURATION", 1, 30),
 		},
 		{
 			in:  `{ foo = "bar" }|logfmt|addr>ip("1.2.3.4")`,
-			err: logqlmodel.NewParseError("syntax error: unexpected ip, expecting BYTES or NUMBER or DURATION", 1, 29),
+			err: logqlmodel.NewParseError("syntax error: unexpected ip, expecting BYTES or NUMBER or PARAM or DURATION", 1, 29),
 		},
 		{
 			in:  `{ foo = "bar" }|logfmt|addr<=ip("1.2.3.4")`,
-			err: logqlmodel.NewParseError("syntax error: unexpected ip, expecting BYTES or NUMBER or DURATION", 1, 30),
+			err: logqlmodel.NewParseError("syntax error: unexpected ip, expecting BYTES or NUMBER or PARAM or DURATION", 1, 30),
 		},
 		{
 			in:  `{ foo = "bar" }|logfmt|addr<ip("1.2.3.4")`,
-			err: logqlmodel.NewParseError("syntax error: unexpected ip, expecting BYTES or NUMBER or DURATION", 1, 29),
+			err: logqlmodel.NewParseError("syntax error: unexpected ip, expecting BYTES or NUMBER or PARAM or DURATION", 1, 29),
 		},
 		{
 			in: `{ foo = "bar" }|logfmt|addr=ip("1.2.3.4")`,
@@ -1026,7 +1245,7 @@ func TestParse(t *testing.T) {
 
 		{
 			in:  `{foo="bar"} |~`,
-			err: logqlmodel.NewParseError("syntax error: unexpected $end, expecting STRING or ip", 1, 15),
+			err: logqlmodel.NewParseError("syntax error: unexpected $end, expecting STRING or PARAM or ip", 1, 15),
 		},
 
 		{
@@ -2891,7 +3110,7 @@ func TestParse(t *testing.T) {
 		},
 		{
 			in:  `quantile_over_time(foo,{namespace="tns"} |= "level=error" | json |foo>=5,bar<25ms| unwrap latency [5m])`,
-			err: logqlmodel.NewParseError("syntax error: unexpected IDENTIFIER, expecting NUMBER or { or (", 1, 20),
+			err: logqlmodel.NewParseError("syntax error: unexpected IDENTIFIER", 1, 20),
 		},
 		{
 			in:  `vector(abc)`,
@@ -2905,6 +3124,38 @@ func TestParse(t *testing.T) {
 			in:  `label_replace(vector(0), "foo", "bar", "", "")`,
 			exp: mustNewLabelReplaceExpr(&VectorExpr{Val: 0, err: nil}, "foo", "bar", "", ""),
 		},
+		{
+			in:  `label_join(vector(0), "foo", "-", "bar", "buzz")`,
+			exp: mustNewLabelJoinExpr(&VectorExpr{Val: 0, err: nil}, "foo", "-", []string{"bar", "buzz"}),
+		},
+		{
+			in:  `label_join(vector(0), "foo", "-", "0bar")`,
+			err: logqlmodel.NewParseError(`invalid label name in label_join: "0bar"`, 0, 0),
+		},
+		{
+			in:  `clamp_min(vector(0), 1)`,
+			exp: newFunctionExpr(&VectorExpr{Val: 0, err: nil}, OpFuncClampMin, mustNewLiteralExpr("1", false)),
+		},
+		{
+			in:  `clamp_min(vector(0))`,
+			err: logqlmodel.NewParseError("parameter required for function clamp_min", 0, 0),
+		},
+		{
+			in:  `abs(vector(0), 1)`,
+			err: logqlmodel.NewParseError("parameter 1 not supported for function abs", 0, 0),
+		},
+		{
+			in:  `time()`,
+			exp: &TimeExpr{},
+		},
+		{
+			in:  `count_values("0bar", vector(0))`,
+			err: logqlmodel.NewParseError(`invalid label name "0bar" for operation count_values`, 0, 0),
+		},
+		{
+			in:  `deriv({foo="bar"}[5m])`,
+			err: logqlmodel.NewParseError("invalid aggregation deriv without unwrap", 0, 0),
+		},
 		{
 			in: `sum(vector(0))`,
 			exp: &VectorAggregationExpr{
@@ -3466,6 +3717,69 @@ func TestNoOpLabelToString(t *testing.T) {
 	require.Len(t, stages, 0)
 }
 
+func TestParseExprWithParams(t *testing.T) {
+	params := map[string]string{
+		"app":    `a"b\`,
+		"needle": "error",
+		"re":     `\d+`,
+		"status": "500",
+		"limit":  "0.5",
+	}
+	for _, tc := range []struct {
+		in  string
+		out string
+		err error
+	}{
+		{
+			in:  `{app=$app, env!=$needle} |= $needle or $app`,
+			out: `{app="a\"b\\", env!="error"} |= "error" or "a\"b\\"`,
+		},
+		{
+			in:  `{app=~$re} |~ $re | logfmt | status >= $status | msg = $needle`,
+			out: `{app=~"\\d+"} |~ "\\d+" | logfmt | status>=500 | msg="error"`,
+		},
+		{
+			in:  `sum(rate({app=$app}[5m])) > $limit`,
+			out: `(sum(rate({app="a\"b\\"}[5m])) > 0.5)`,
+		},
+		{
+			in:  `{app=$app} | status > $needle`,
+			err: logqlmodel.NewParseError(`unable to parse float: strconv.ParseFloat: parsing "error": invalid syntax`, 0, 0),
+		},
+		{
+			in:  `{app=$app} |= $missing`,
+			err: logqlmodel.NewParseError("parameter $missing is not bound", 1, 15),
+		},
+		{
+			in:  `{app=$ app}`,
+			err: logqlmodel.NewParseError("syntax error: unexpected IDENTIFIER, expecting STRING or PARAM", 1, 6),
+		},
+	} {
+		t.Run(tc.in, func(t *testing.T) {
+			expr, err := ParseExprWithParams(tc.in, params)
+			require.Equal(t, tc.err, err)
+			if tc.err != nil {
+				return
+			}
+			require.Equal(t, tc.out, expr.String())
+
+			// the bound query parses to the same expression.
+			bound, err := BindParams(tc.in, params)
+			require.NoError(t, err)
+			expected, err := ParseExpr(bound)
+			require.NoError(t, err)
+			require.Equal(t, expected, expr)
+		})
+	}
+
+	_, err := ParseExpr(`{app=$app}`)
+	require.Equal(t, logqlmodel.NewParseError("parameter $app is not bound", 1, 6), err)
+
+	q, err := BindParams(`{app=$app}`, nil)
+	require.NoError(t, err)
+	require.Equal(t, `{app=$app}`, q)
+}
+
 func TestParseSampleExpr_String(t *testing.T) {
 	t.Run("it doesn't add escape characters when after getting parsed", func(t *testing.T) {
 		query := `sum(rate({cluster="beep", namespace="boop"} | msg=~` + "`" + `.*?(GET|POST|PUT|DELETE|PATCH|HEAD|OPTIONS) /loki/api/(?i)(\d+[a-z]|[a-z]+\d)\w*/query_range` + "`" + `[1d]))`
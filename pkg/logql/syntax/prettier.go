@@ -109,6 +109,7 @@ func (e *LogfmtParserExpr) Pretty(level int) string {
 // `| regexp`
 // `| pattern`
 // `| unpack`
+// `| xml`
 func (e *LabelParserExpr) Pretty(level int) string {
 	return commonPrefixIndent(level, e)
 }
@@ -121,6 +122,36 @@ func (e *KeepLabelsExpr) Pretty(level int) string {
 	return commonPrefixIndent(level, e)
 }
 
+// e.g: | distinct cluster, namespace
+func (e *DistinctFilterExpr) Pretty(level int) string {
+	return commonPrefixIndent(level, e)
+}
+
+// e.g: | dedup 1s
+func (e *DedupExpr) Pretty(level int) string {
+	return commonPrefixIndent(level, e)
+}
+
+// e.g: | limit 10 by (pod) sample
+func (e *LimitByExpr) Pretty(level int) string {
+	return commonPrefixIndent(level, e)
+}
+
+// e.g: | unnest items
+func (e *UnnestExpr) Pretty(level int) string {
+	return commonPrefixIndent(level, e)
+}
+
+// e.g: | decode gzip+base64
+func (e *DecodeExpr) Pretty(level int) string {
+	return commonPrefixIndent(level, e)
+}
+
+// e.g: | json_unescape log
+func (e *JSONUnescapeExpr) Pretty(level int) string {
+	return commonPrefixIndent(level, e)
+}
+
 // e.g: | level!="error"
 func (e *LabelFilterExpr) Pretty(level int) string {
 	return commonPrefixIndent(level, e)
@@ -151,6 +182,21 @@ func (e *LogfmtExpressionParser) Pretty(level int) string {
 	return commonPrefixIndent(level, e)
 }
 
+// e.g: | xml label="/path/to/element", another="/path/to/@attribute"
+func (e *XMLExpressionParser) Pretty(level int) string {
+	return commonPrefixIndent(level, e)
+}
+
+// e.g: | csv sep=";" "column1;column2"
+func (e *CSVParserExpr) Pretty(level int) string {
+	return commonPrefixIndent(level, e)
+}
+
+// e.g: | kv sep=";" assign=":"
+func (e *KVParserExpr) Pretty(level int) string {
+	return commonPrefixIndent(level, e)
+}
+
 // e.g: sum_over_time({foo="bar"} | logfmt | unwrap bytes_processed [5m])
 func (e *UnwrapExpr) Pretty(level int) string {
 	s := indent(level)
@@ -210,13 +256,18 @@ func (e *RangeAggregationExpr) Pretty(level int) string {
 	s += "(\n"
 
 	// print args to the function.
-	if e.Params != nil {
+	if e.Params != nil && e.Operation != OpRangeTypePredictLinear {
 		s = fmt.Sprintf("%s%s%s,", s, indent(level+1), fmt.Sprint(*e.Params))
 		s += "\n"
 	}
 
 	s += e.Left.Pretty(level + 1)
 
+	// predict_linear takes its parameter last.
+	if e.Params != nil && e.Operation == OpRangeTypePredictLinear {
+		s = fmt.Sprintf("%s,\n%s%s", s, indent(level+1), fmt.Sprint(*e.Params))
+	}
+
 	s += "\n" + indent(level) + ")"
 
 	if e.Grouping != nil {
@@ -226,6 +277,53 @@ func (e *RangeAggregationExpr) Pretty(level int) string {
 	return s
 }
 
+// e.g: sum(rate({foo="bar"}[5m]))[1h:1m]
+func (e *SubqueryExpr) Pretty(level int) string {
+	s := e.Left.Pretty(level)
+
+	s = fmt.Sprintf("%s [%s:", s, model.Duration(e.Range))
+	if e.Step != 0 {
+		s += model.Duration(e.Step).String()
+	}
+	s += "]"
+
+	if e.Offset != 0 {
+		oe := OffsetExpr{Offset: e.Offset}
+		s += oe.Pretty(level)
+	}
+
+	return s
+}
+
+// e.g: max_over_time(sum(rate({foo="bar"}[5m]))[1h:1m])
+func (e *SubqueryAggregationExpr) Pretty(level int) string {
+	s := indent(level)
+	if !needSplit(e) {
+		return s + e.String()
+	}
+
+	s += e.Operation // e.g: max_over_time
+
+	s += "(\n"
+
+	// print args to the function.
+	if e.Params != nil && e.Operation != OpRangeTypePredictLinear {
+		s = fmt.Sprintf("%s%s%s,", s, indent(level+1), fmt.Sprint(*e.Params))
+		s += "\n"
+	}
+
+	s += e.Left.Pretty(level + 1)
+
+	// predict_linear takes its parameter last.
+	if e.Params != nil && e.Operation == OpRangeTypePredictLinear {
+		s = fmt.Sprintf("%s,\n%s%s", s, indent(level+1), fmt.Sprint(*e.Params))
+	}
+
+	s += "\n" + indent(level) + ")"
+
+	return s
+}
+
 // e.g:
 // sum(count_over_time({foo="bar"}[5m])) by (container)
 // topk(10, count_over_time({foo="bar"}[5m])) by (container)
@@ -250,6 +348,10 @@ func (e *VectorAggregationExpr) Pretty(level int) string {
 	// e.Params default value (0) can mean a legit param for topk and bottomk
 	case OpTypeBottomK, OpTypeTopK:
 		params = []string{fmt.Sprintf("%s%d", indent(level+1), e.Params), left}
+	case OpTypeQuantile:
+		params = []string{fmt.Sprintf("%s%s", indent(level+1), fmt.Sprint(e.Quantile)), left}
+	case OpTypeCountValues:
+		params = []string{fmt.Sprintf("%s%s", indent(level+1), strconv.Quote(e.Label)), left}
 
 	default:
 		if e.Params != 0 {
@@ -343,6 +445,114 @@ func (e *VectorExpr) Pretty(level int) string {
 	return commonPrefixIndent(level, e)
 }
 
+// e.g: time()
+func (e *TimeExpr) Pretty(level int) string {
+	return commonPrefixIndent(level, e)
+}
+
+// e.g: clamp_min(sum(rate({foo="bar"}[5m])), 0)
+// e.g: histogram_quantile(0.99, sum by (le) (rate({foo="bar"}[5m])))
+func (e *FunctionExpr) Pretty(level int) string {
+	s := indent(level)
+
+	if !needSplit(e) {
+		return s + e.String()
+	}
+
+	s += e.Function + "(\n"
+
+	params := []string{e.Left.Pretty(level + 1)}
+	if e.Params != nil {
+		param := indent(level+1) + fmt.Sprint(*e.Params)
+		if e.Function == OpFuncHistogramQuantile {
+			params = []string{param, params[0]}
+		} else {
+			params = append(params, param)
+		}
+	}
+
+	for i, v := range params {
+		s += v
+		// LogQL doesn't allow `,` at the end of last argument.
+		if i < len(params)-1 {
+			s += ","
+		}
+		s += "\n"
+	}
+
+	s += indent(level) + ")"
+
+	return s
+}
+
+// e.g: label_join(rate({foo="bar"}[5m]), "foo", ",", "bar", "baz")
+func (e *LabelJoinExpr) Pretty(level int) string {
+	s := indent(level)
+
+	if !needSplit(e) {
+		return s + e.String()
+	}
+
+	s += OpLabelJoin
+
+	s += "(\n"
+
+	params := []string{
+		e.Left.Pretty(level + 1),
+		indent(level+1) + strconv.Quote(e.Dst),
+		indent(level+1) + strconv.Quote(e.Separator),
+	}
+	for _, src := range e.Src {
+		params = append(params, indent(level+1)+strconv.Quote(src))
+	}
+
+	for i, v := range params {
+		s += v
+		// LogQL doesn't allow `,` at the end of last argument.
+		if i < len(params)-1 {
+			s += ","
+		}
+		s += "\n"
+	}
+
+	s += indent(level) + ")"
+
+	return s
+}
+
+// e.g: join({app="gateway"} | json, {app="backend"} | json, "request_id", 5s)
+func (e *JoinExpr) Pretty(level int) string {
+	s := indent(level)
+
+	if !needSplit(e) {
+		return s + e.String()
+	}
+
+	s += OpJoin
+
+	s += "(\n"
+
+	params := []string{
+		indent(level+1) + joinSideString(e.Left),
+		indent(level+1) + joinSideString(e.Right),
+		indent(level+1) + strconv.Quote(e.Label),
+		indent(level+1) + model.Duration(e.Tolerance).String(),
+	}
+
+	for i, v := range params {
+		s += v
+		// LogQL doesn't allow `,` at the end of last argument.
+		if i < len(params)-1 {
+			s += ","
+		}
+		s += "\n"
+	}
+
+	s += indent(level) + ")"
+
+	return s
+}
+
 // Grouping is technically not expression type. But used in both range and vector aggregations (`by` and `without` clause)
 // So by implenting `Pretty` for Grouping, we can re use it for both.
 // NOTE: indent is ignored for `Grouping`, because grouping always stays in the same line of it's parent expression.
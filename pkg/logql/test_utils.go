@@ -102,7 +102,7 @@ func processStream(in []logproto.Stream, pipeline log.Pipeline) []logproto.Strea
 	for _, stream := range in {
 		for _, e := range stream.Entries {
 			sp := pipeline.ForStream(mustParseLabels(stream.Labels))
-			if l, out, matches := sp.Process(e.Timestamp.UnixNano(), []byte(e.Line)); matches {
+			for l, out, matches := sp.Process(e.Timestamp.UnixNano(), []byte(e.Line)); matches; l, out, matches = sp.Next() {
 				var s *logproto.Stream
 				var found bool
 				s, found = resByStream[out.String()]
@@ -130,7 +130,8 @@ func processSeries(in []logproto.Stream, ex log.SampleExtractor) []logproto.Seri
 	for _, stream := range in {
 		for _, e := range stream.Entries {
 			exs := ex.ForStream(mustParseLabels(stream.Labels))
-			if f, lbs, ok := exs.Process(e.Timestamp.UnixNano(), []byte(e.Line)); ok {
+			hash := xxhash.Sum64([]byte(e.Line))
+			for f, lbs, ok := exs.Process(e.Timestamp.UnixNano(), []byte(e.Line)); ok; f, lbs, ok = exs.Next() {
 				var s *logproto.Series
 				var found bool
 				s, found = resBySeries[lbs.String()]
@@ -141,8 +142,9 @@ func processSeries(in []logproto.Stream, ex log.SampleExtractor) []logproto.Seri
 				s.Samples = append(s.Samples, logproto.Sample{
 					Timestamp: e.Timestamp.UnixNano(),
 					Value:     f,
-					Hash:      xxhash.Sum64([]byte(e.Line)),
+					Hash:      hash,
 				})
+				hash++
 			}
 		}
 	}
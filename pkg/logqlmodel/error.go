@@ -90,6 +90,12 @@ func NewSeriesLimitError(limit int) *LimitError {
 	}
 }
 
+func NewJoinEntriesLimitError(limit int) *LimitError {
+	return &LimitError{
+		error: fmt.Errorf("maximum of log lines (%d) to join within the tolerance reached, reduce the tolerance or filter more log lines", limit),
+	}
+}
+
 // Is allows to use errors.Is(err,ErrLimit) on this error.
 func (e LimitError) Is(target error) bool {
 	return target == ErrLimit
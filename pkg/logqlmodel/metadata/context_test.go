@@ -56,3 +56,18 @@ func TestHeadersNoKey(t *testing.T) {
 
 	require.True(t, errors.Is(err, ErrNoCtxData))
 }
+
+func TestWarnings(t *testing.T) {
+	metadata, ctx := NewContext(context.Background())
+	require.Nil(t, metadata.Warnings())
+
+	require.NoError(t, AddWarnings(ctx, "shard 1_of_2 failed"))
+	require.NoError(t, AddWarnings(ctx, "ingester 10.0.0.1 failed", "shard 1_of_2 failed"))
+
+	require.Equal(t, []string{"ingester 10.0.0.1 failed", "shard 1_of_2 failed"}, metadata.Warnings())
+}
+
+func TestWarningsNoKey(t *testing.T) {
+	err := AddWarnings(context.Background(), "warning")
+	require.True(t, errors.Is(err, ErrNoCtxData))
+}
@@ -60,6 +60,7 @@ const (
 	ResultCache                 = "result"
 	StatsResultCache            = "stats-result"
 	VolumeResultCache           = "volume-result"
+	LogResultCache              = "log-result"
 	WriteDedupeCache            = "write-dedupe"
 )
 
@@ -98,6 +99,7 @@ func (c *Context) Caches() Caches {
 		Result:       c.caches.Result,
 		StatsResult:  c.caches.StatsResult,
 		VolumeResult: c.caches.VolumeResult,
+		LogResult:    c.caches.LogResult,
 	}
 }
 
@@ -190,6 +192,9 @@ func (s *Store) Merge(m Store) {
 func (s *Summary) Merge(m Summary) {
 	s.Splits += m.Splits
 	s.Shards += m.Shards
+	if m.SplitInterval > s.SplitInterval {
+		s.SplitInterval = m.SplitInterval
+	}
 }
 
 func (q *Querier) Merge(m Querier) {
@@ -210,6 +215,7 @@ func (c *Caches) Merge(m Caches) {
 	c.Result.Merge(m.Result)
 	c.StatsResult.Merge(m.StatsResult)
 	c.VolumeResult.Merge(m.VolumeResult)
+	c.LogResult.Merge(m.LogResult)
 }
 
 func (c *Cache) Merge(m Cache) {
@@ -418,6 +424,13 @@ func (c *Context) AddSplitQueries(num int64) {
 	atomic.AddInt64(&c.result.Summary.Splits, num)
 }
 
+// SetSplitInterval records the interval the query was split by in time.
+func (c *Context) SetSplitInterval(interval time.Duration) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.result.Summary.SplitInterval = interval.Seconds()
+}
+
 func (c *Context) getCacheStatsByType(t CacheType) *Cache {
 	var stats *Cache
 	switch t {
@@ -431,6 +444,8 @@ func (c *Context) getCacheStatsByType(t CacheType) *Cache {
 		stats = &c.caches.StatsResult
 	case VolumeResultCache:
 		stats = &c.caches.VolumeResult
+	case LogResultCache:
+		stats = &c.caches.LogResult
 	default:
 		return nil
 	}
@@ -481,6 +496,7 @@ func (s Summary) Log(log log.Logger) {
 		"Summary.PostFilterLines", s.TotalPostFilterLines,
 		"Summary.ExecTime", ConvertSecondsToNanoseconds(s.ExecTime),
 		"Summary.QueueTime", ConvertSecondsToNanoseconds(s.QueueTime),
+		"Summary.SplitInterval", ConvertSecondsToNanoseconds(s.SplitInterval),
 	)
 }
 
@@ -512,6 +528,8 @@ func (c Caches) Log(log log.Logger) {
 		"Cache.VolumeResult.EntriesStored", c.VolumeResult.EntriesStored,
 		"Cache.VolumeResult.BytesSent", humanize.Bytes(uint64(c.VolumeResult.BytesSent)),
 		"Cache.VolumeResult.BytesReceived", humanize.Bytes(uint64(c.VolumeResult.BytesReceived)),
+		"Cache.LogResult.EntriesRequested", c.LogResult.EntriesRequested,
+		"Cache.LogResult.EntriesFound", c.LogResult.EntriesFound,
 		"Cache.Result.DownloadTime", c.Result.CacheDownloadTime(),
 		"Cache.Result.Requests", c.Result.Requests,
 		"Cache.Result.EntriesRequested", c.Result.EntriesRequested,
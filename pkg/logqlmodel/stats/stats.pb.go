@@ -100,6 +100,7 @@ type Caches struct {
 	Result       Cache `protobuf:"bytes,3,opt,name=result,proto3" json:"result"`
 	StatsResult  Cache `protobuf:"bytes,4,opt,name=statsResult,proto3" json:"statsResult"`
 	VolumeResult Cache `protobuf:"bytes,5,opt,name=volumeResult,proto3" json:"volumeResult"`
+	LogResult    Cache `protobuf:"bytes,6,opt,name=logResult,proto3" json:"logResult"`
 }
 
 func (m *Caches) Reset()      { *m = Caches{} }
@@ -169,6 +170,13 @@ func (m *Caches) GetVolumeResult() Cache {
 	return Cache{}
 }
 
+func (m *Caches) GetLogResult() Cache {
+	if m != nil {
+		return m.LogResult
+	}
+	return Cache{}
+}
+
 // Summary is the summary of a query statistics.
 type Summary struct {
 	// Total bytes processed per second.
@@ -200,6 +208,9 @@ type Summary struct {
 	TotalPostFilterLines int64 `protobuf:"varint,11,opt,name=totalPostFilterLines,proto3" json:"totalPostFilterLines"`
 	// Total bytes processed of metadata.
 	TotalStructuredMetadataBytesProcessed int64 `protobuf:"varint,12,opt,name=totalStructuredMetadataBytesProcessed,proto3" json:"totalStructuredMetadataBytesProcessed"`
+	// Interval in seconds the query was split by in time.
+	// It is adapted to the volume of the query if a split target size is configured.
+	SplitInterval float64 `protobuf:"fixed64,13,opt,name=splitInterval,proto3" json:"splitInterval,omitempty"`
 }
 
 func (m *Summary) Reset()      { *m = Summary{} }
@@ -318,6 +329,13 @@ func (m *Summary) GetTotalStructuredMetadataBytesProcessed() int64 {
 	return 0
 }
 
+func (m *Summary) GetSplitInterval() float64 {
+	if m != nil {
+		return m.SplitInterval
+	}
+	return 0
+}
+
 type Querier struct {
 	Store Store `protobuf:"bytes,1,opt,name=store,proto3" json:"store"`
 }
@@ -740,78 +758,81 @@ func init() {
 func init() { proto.RegisterFile("pkg/logqlmodel/stats/stats.proto", fileDescriptor_6cdfe5d2aea33ebb) }
 
 var fileDescriptor_6cdfe5d2aea33ebb = []byte{
-	// 1127 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x57, 0x4f, 0x6f, 0xe3, 0x44,
-	0x14, 0x4f, 0x52, 0x9c, 0x74, 0xa7, 0x7f, 0x77, 0xda, 0x65, 0x03, 0x48, 0x76, 0x15, 0x40, 0x2c,
-	0x02, 0x35, 0xe2, 0x8f, 0x84, 0x40, 0xac, 0x84, 0xdc, 0xa5, 0x52, 0xa5, 0x5d, 0x51, 0x5e, 0xe1,
-	0xc2, 0xcd, 0xb5, 0xa7, 0x89, 0x55, 0xc7, 0x4e, 0xed, 0xf1, 0xb2, 0x3d, 0xc1, 0x47, 0xe0, 0x63,
-	0x70, 0xe1, 0x80, 0x38, 0xf1, 0x0d, 0xf6, 0xd8, 0x1b, 0x7b, 0xb2, 0x68, 0x7a, 0x41, 0x3e, 0xad,
-	0xc4, 0x1d, 0xa1, 0x79, 0x33, 0xb1, 0x3d, 0x8e, 0xa3, 0xed, 0x25, 0x9e, 0xf7, 0x7b, 0xbf, 0xdf,
-	0x7b, 0xe3, 0x99, 0x79, 0xcf, 0x13, 0xb2, 0x37, 0x3d, 0x1f, 0x0d, 0x83, 0x68, 0x74, 0x11, 0x4c,
-	0x22, 0x8f, 0x05, 0xc3, 0x84, 0x3b, 0x3c, 0x91, 0xbf, 0xfb, 0xd3, 0x38, 0xe2, 0x11, 0x35, 0xd0,
-	0x78, 0x73, 0x77, 0x14, 0x8d, 0x22, 0x44, 0x86, 0x62, 0x24, 0x9d, 0x83, 0x7f, 0xdb, 0xa4, 0x0b,
-	0x2c, 0x49, 0x03, 0x4e, 0x3f, 0x27, 0xbd, 0x24, 0x9d, 0x4c, 0x9c, 0xf8, 0xb2, 0xdf, 0xde, 0x6b,
-	0x3f, 0x58, 0xfb, 0x78, 0x73, 0x5f, 0x86, 0x39, 0x91, 0xa8, 0xbd, 0xf5, 0x3c, 0xb3, 0x5a, 0x79,
-	0x66, 0xcd, 0x69, 0x30, 0x1f, 0x08, 0xe9, 0x45, 0xca, 0x62, 0x9f, 0xc5, 0xfd, 0x8e, 0x26, 0xfd,
-	0x56, 0xa2, 0xa5, 0x54, 0xd1, 0x60, 0x3e, 0xa0, 0x0f, 0xc9, 0xaa, 0x1f, 0x8e, 0x58, 0xc2, 0x59,
-	0xdc, 0x5f, 0x41, 0xed, 0x96, 0xd2, 0x1e, 0x29, 0xd8, 0xde, 0x56, 0xe2, 0x82, 0x08, 0xc5, 0x88,
-	0x7e, 0x4a, 0xba, 0xae, 0xe3, 0x8e, 0x59, 0xd2, 0x7f, 0x0d, 0xc5, 0x1b, 0x4a, 0x7c, 0x80, 0xa0,
-	0xbd, 0xa1, 0xa4, 0x06, 0x92, 0x40, 0x71, 0x07, 0xbf, 0x77, 0x48, 0x57, 0x32, 0xe8, 0x47, 0xc4,
-	0x70, 0xc7, 0x69, 0x78, 0xae, 0xde, 0x79, 0xbd, 0xaa, 0xaf, 0xc8, 0x05, 0x05, 0xe4, 0x43, 0x48,
-	0xfc, 0xd0, 0x63, 0xcf, 0xd4, 0xbb, 0x2e, 0x91, 0x20, 0x05, 0xe4, 0x43, 0x4c, 0x33, 0xc6, 0x55,
-	0x56, 0xef, 0xa8, 0x6b, 0x36, 0x95, 0x46, 0x71, 0x40, 0x3d, 0xe9, 0x01, 0x59, 0x43, 0x9a, 0xdc,
-	0x20, 0xf5, 0x86, 0xba, 0x74, 0x47, 0x49, 0xab, 0x44, 0xa8, 0x1a, 0xf4, 0x90, 0xac, 0x3f, 0x8d,
-	0x82, 0x74, 0xc2, 0x54, 0x14, 0xa3, 0x21, 0xca, 0xae, 0x8a, 0xa2, 0x31, 0x41, 0xb3, 0x06, 0x7f,
-	0x76, 0x49, 0x4f, 0x9d, 0x04, 0xfa, 0x3d, 0xb9, 0x7f, 0x7a, 0xc9, 0x59, 0x72, 0x1c, 0x47, 0x2e,
-	0x4b, 0x12, 0xe6, 0x1d, 0xb3, 0xf8, 0x84, 0xb9, 0x51, 0xe8, 0xe1, 0x32, 0xae, 0xd8, 0x6f, 0xe5,
-	0x99, 0xb5, 0x8c, 0x02, 0xcb, 0x1c, 0x22, 0x6c, 0xe0, 0x87, 0x8d, 0x61, 0x3b, 0x65, 0xd8, 0x25,
-	0x14, 0x58, 0xe6, 0xa0, 0x47, 0x64, 0x87, 0x47, 0xdc, 0x09, 0x6c, 0x2d, 0x2d, 0xee, 0xc4, 0x8a,
-	0x7d, 0x3f, 0xcf, 0xac, 0x26, 0x37, 0x34, 0x81, 0x45, 0xa8, 0xc7, 0x5a, 0x2a, 0xdc, 0x99, 0x6a,
-	0x28, 0xdd, 0x0d, 0x4d, 0x20, 0x7d, 0x40, 0x56, 0xd9, 0x33, 0xe6, 0x7e, 0xe7, 0x4f, 0x18, 0xee,
-	0x49, 0xdb, 0x5e, 0x17, 0x67, 0x7c, 0x8e, 0x41, 0x31, 0xa2, 0x1f, 0x90, 0x3b, 0x17, 0x29, 0x4b,
-	0x19, 0x52, 0xbb, 0x48, 0xdd, 0xc8, 0x33, 0xab, 0x04, 0xa1, 0x1c, 0xd2, 0x7d, 0x42, 0x92, 0xf4,
-	0x54, 0x56, 0x57, 0xd2, 0xef, 0xe1, 0xc4, 0x36, 0xf3, 0xcc, 0xaa, 0xa0, 0x50, 0x19, 0xd3, 0xc7,
-	0x64, 0x17, 0x67, 0xf7, 0x75, 0xc8, 0xd1, 0xc7, 0x78, 0x1a, 0x87, 0xcc, 0xeb, 0xaf, 0xa2, 0xb2,
-	0x9f, 0x67, 0x56, 0xa3, 0x1f, 0x1a, 0x51, 0x3a, 0x20, 0xdd, 0x64, 0x1a, 0xf8, 0x3c, 0xe9, 0xdf,
-	0x41, 0x3d, 0x11, 0xa7, 0x5a, 0x22, 0xa0, 0x9e, 0xc8, 0x19, 0x3b, 0xb1, 0x97, 0xf4, 0x49, 0x85,
-	0x83, 0x08, 0xa8, 0x67, 0x31, 0xab, 0xe3, 0x28, 0xe1, 0x87, 0x7e, 0xc0, 0x59, 0x8c, 0xab, 0xd7,
-	0x5f, 0xab, 0xcd, 0xaa, 0xe6, 0x87, 0x46, 0x94, 0xfe, 0x44, 0xde, 0x45, 0xfc, 0x84, 0xc7, 0xa9,
-	0xcb, 0xd3, 0x98, 0x79, 0x4f, 0x18, 0x77, 0x3c, 0x87, 0x3b, 0xb5, 0x23, 0xb1, 0x8e, 0xe1, 0xdf,
-	0xcf, 0x33, 0xeb, 0x76, 0x02, 0xb8, 0x1d, 0x6d, 0xf0, 0x25, 0xe9, 0xa9, 0x4e, 0x28, 0x9a, 0x47,
-	0xc2, 0xa3, 0x98, 0xd5, 0xfa, 0xcd, 0x89, 0xc0, 0xca, 0xe6, 0x81, 0x14, 0x90, 0x8f, 0xc1, 0x6f,
-	0x1d, 0xb2, 0x7a, 0x54, 0x36, 0xbc, 0x75, 0xcc, 0x09, 0x4c, 0x54, 0xae, 0xac, 0x37, 0xc3, 0xde,
-	0x16, 0xc5, 0x5b, 0xc5, 0x41, 0xb3, 0xe8, 0x21, 0xa1, 0x68, 0x1f, 0x88, 0x06, 0x96, 0x3c, 0x71,
-	0x38, 0x6a, 0x65, 0x51, 0xbd, 0x9e, 0x67, 0x56, 0x83, 0x17, 0x1a, 0xb0, 0x22, 0xbb, 0x8d, 0x76,
-	0xa2, 0x6a, 0xa8, 0xcc, 0xae, 0x70, 0xd0, 0x2c, 0xfa, 0x05, 0xd9, 0x2c, 0x2b, 0xe0, 0x84, 0x85,
-	0x5c, 0x15, 0x0c, 0xcd, 0x33, 0xab, 0xe6, 0x81, 0x9a, 0x5d, 0xae, 0x97, 0x71, 0xeb, 0xf5, 0xfa,
-	0xab, 0x43, 0x0c, 0xf4, 0x17, 0x89, 0xe5, 0x4b, 0x00, 0x3b, 0x53, 0xed, 0xa9, 0x4c, 0x5c, 0x78,
-	0xa0, 0x66, 0xd3, 0x6f, 0xc8, 0xbd, 0x0a, 0xf2, 0x28, 0xfa, 0x31, 0x0c, 0x22, 0xc7, 0x2b, 0x56,
-	0xed, 0x8d, 0x3c, 0xb3, 0x9a, 0x09, 0xd0, 0x0c, 0x8b, 0x3d, 0x70, 0x35, 0x0c, 0xeb, 0x79, 0xa5,
-	0xdc, 0x83, 0x45, 0x2f, 0x34, 0x60, 0xe5, 0x17, 0xab, 0xf6, 0x3d, 0x10, 0xd8, 0x92, 0x2f, 0xd6,
-	0x3c, 0x35, 0xb0, 0xb3, 0xe4, 0x90, 0x71, 0x77, 0x5c, 0x74, 0x9d, 0x6a, 0x6a, 0xcd, 0x0b, 0x0d,
-	0xd8, 0xe0, 0x0f, 0x83, 0x18, 0x98, 0x47, 0xac, 0xec, 0x98, 0x39, 0x9e, 0x4c, 0x2a, 0x0e, 0x7b,
-	0x75, 0x4b, 0x75, 0x0f, 0xd4, 0x6c, 0x4d, 0x2b, 0xcb, 0xda, 0x68, 0xd0, 0xca, 0x82, 0xae, 0xd9,
-	0xf4, 0x80, 0xdc, 0xf5, 0x98, 0x1b, 0x4d, 0xa6, 0x31, 0x56, 0x96, 0x4c, 0xdd, 0x45, 0xf9, 0xbd,
-	0x3c, 0xb3, 0x16, 0x9d, 0xb0, 0x08, 0xd5, 0x83, 0xc8, 0x39, 0xf4, 0x9a, 0x83, 0xc8, 0x69, 0x2c,
-	0x42, 0xf4, 0x21, 0xd9, 0xaa, 0xcf, 0x43, 0xf6, 0xcc, 0x9d, 0x3c, 0xb3, 0xea, 0x2e, 0xa8, 0x03,
-	0x42, 0x8e, 0xc7, 0xe4, 0x51, 0x3a, 0x0d, 0x7c, 0xd7, 0x11, 0xf2, 0x3b, 0xa5, 0xbc, 0xe6, 0x82,
-	0x3a, 0x20, 0xe4, 0xd3, 0x5a, 0x6f, 0x24, 0xa5, 0xbc, 0xe6, 0x82, 0x3a, 0x40, 0xa7, 0x64, 0xaf,
-	0x58, 0xd8, 0x25, 0xdd, 0x4b, 0xf5, 0xda, 0x77, 0xf2, 0xcc, 0x7a, 0x25, 0x17, 0x5e, 0xc9, 0xa0,
-	0x97, 0xe4, 0xed, 0xea, 0x1a, 0x2e, 0x4b, 0x2a, 0x3b, 0xf0, 0x7b, 0x79, 0x66, 0xdd, 0x86, 0x0e,
-	0xb7, 0x21, 0x0d, 0xfe, 0xeb, 0x10, 0x03, 0xef, 0x39, 0xa2, 0x7d, 0x31, 0xf9, 0xc5, 0x3a, 0x8c,
-	0xd2, 0x50, 0x6b, 0x9e, 0x55, 0x1c, 0x34, 0x8b, 0x7e, 0x45, 0xb6, 0xd9, 0xfc, 0x3b, 0x77, 0x91,
-	0x8a, 0x36, 0x2c, 0x9b, 0x80, 0x61, 0xef, 0xe6, 0x99, 0xb5, 0xe0, 0x83, 0x05, 0x84, 0x7e, 0x46,
-	0x36, 0x14, 0x86, 0x7d, 0x49, 0xde, 0x3d, 0x0c, 0xfb, 0x6e, 0x9e, 0x59, 0xba, 0x03, 0x74, 0x53,
-	0x08, 0xf1, 0xb2, 0x04, 0xcc, 0x65, 0xfe, 0xd3, 0xe2, 0xa6, 0x81, 0x42, 0xcd, 0x01, 0xba, 0x29,
-	0xee, 0x0c, 0x08, 0x60, 0xb7, 0x95, 0xe5, 0x85, 0x77, 0x86, 0x02, 0x84, 0x72, 0x28, 0xae, 0x22,
-	0xb1, 0x9c, 0xab, 0xac, 0x25, 0x43, 0x5e, 0x45, 0xe6, 0x18, 0x14, 0x23, 0xb1, 0x80, 0x5e, 0xb5,
-	0x7b, 0xf5, 0xca, 0xfe, 0x5f, 0xc5, 0x41, 0xb3, 0xec, 0xd3, 0xab, 0x6b, 0xb3, 0xf5, 0xe2, 0xda,
-	0x6c, 0xbd, 0xbc, 0x36, 0xdb, 0x3f, 0xcf, 0xcc, 0xf6, 0xaf, 0x33, 0xb3, 0xfd, 0x7c, 0x66, 0xb6,
-	0xaf, 0x66, 0x66, 0xfb, 0xef, 0x99, 0xd9, 0xfe, 0x67, 0x66, 0xb6, 0x5e, 0xce, 0xcc, 0xf6, 0x2f,
-	0x37, 0x66, 0xeb, 0xea, 0xc6, 0x6c, 0xbd, 0xb8, 0x31, 0x5b, 0x3f, 0x7c, 0x38, 0xf2, 0xf9, 0x38,
-	0x3d, 0xdd, 0x77, 0xa3, 0xc9, 0x70, 0x14, 0x3b, 0x67, 0x4e, 0xe8, 0x0c, 0x83, 0xe8, 0xdc, 0x1f,
-	0x36, 0xfd, 0xe3, 0x39, 0xed, 0xe2, 0xff, 0x99, 0x4f, 0xfe, 0x0f, 0x00, 0x00, 0xff, 0xff, 0x14,
-	0xee, 0x83, 0x9b, 0x10, 0x0d, 0x00, 0x00,
+	// 1170 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x57, 0xcf, 0x6f, 0xdc, 0xc4,
+	0x17, 0x5f, 0x6f, 0xea, 0xdd, 0x74, 0x9a, 0x4d, 0xdb, 0x69, 0xfa, 0xad, 0xbf, 0x20, 0xd9, 0xd5,
+	0x02, 0xa2, 0x88, 0x2a, 0x2b, 0x7e, 0x48, 0x08, 0x44, 0x25, 0x70, 0x4a, 0xa4, 0x48, 0xad, 0x08,
+	0x2f, 0x70, 0xe1, 0xe6, 0xd8, 0x93, 0x5d, 0x2b, 0x5e, 0x7b, 0x63, 0x8f, 0x43, 0x73, 0x82, 0x3f,
+	0x81, 0x3f, 0x83, 0x0b, 0x27, 0xfe, 0x89, 0x1e, 0x73, 0xa3, 0x27, 0x8b, 0x6c, 0x2e, 0xc8, 0xa7,
+	0x48, 0x5c, 0x11, 0x42, 0xf3, 0x66, 0xd6, 0xbf, 0xd6, 0x4b, 0x73, 0x89, 0xe7, 0x7d, 0x7e, 0xbc,
+	0x99, 0x9d, 0xf1, 0x7b, 0x9e, 0x90, 0x87, 0xb3, 0xe3, 0xf1, 0x28, 0x88, 0xc6, 0x27, 0xc1, 0x34,
+	0xf2, 0x58, 0x30, 0x4a, 0xb8, 0xc3, 0x13, 0xf9, 0x77, 0x7b, 0x16, 0x47, 0x3c, 0xa2, 0x3a, 0x06,
+	0x6f, 0x6c, 0x8d, 0xa3, 0x71, 0x84, 0xc8, 0x48, 0x8c, 0x24, 0x39, 0xfc, 0x4b, 0x23, 0x3d, 0x60,
+	0x49, 0x1a, 0x70, 0xfa, 0x29, 0xe9, 0x27, 0xe9, 0x74, 0xea, 0xc4, 0x67, 0x86, 0xf6, 0x50, 0x7b,
+	0x74, 0xeb, 0xc3, 0xcd, 0x6d, 0x99, 0xe6, 0x40, 0xa2, 0xf6, 0xed, 0x97, 0x99, 0xd5, 0xc9, 0x33,
+	0x6b, 0x21, 0x83, 0xc5, 0x40, 0x58, 0x4f, 0x52, 0x16, 0xfb, 0x2c, 0x36, 0xba, 0x35, 0xeb, 0x37,
+	0x12, 0x2d, 0xad, 0x4a, 0x06, 0x8b, 0x01, 0x7d, 0x42, 0xd6, 0xfd, 0x70, 0xcc, 0x12, 0xce, 0x62,
+	0x63, 0x0d, 0xbd, 0xb7, 0x95, 0x77, 0x4f, 0xc1, 0xf6, 0x1d, 0x65, 0x2e, 0x84, 0x50, 0x8c, 0xe8,
+	0xc7, 0xa4, 0xe7, 0x3a, 0xee, 0x84, 0x25, 0xc6, 0x0d, 0x34, 0x0f, 0x94, 0x79, 0x07, 0x41, 0x7b,
+	0xa0, 0xac, 0x3a, 0x8a, 0x40, 0x69, 0x87, 0x57, 0x5d, 0xd2, 0x93, 0x0a, 0xfa, 0x01, 0xd1, 0xdd,
+	0x49, 0x1a, 0x1e, 0xab, 0xdf, 0xbc, 0x51, 0xf5, 0x57, 0xec, 0x42, 0x02, 0xf2, 0x21, 0x2c, 0x7e,
+	0xe8, 0xb1, 0x17, 0x46, 0xf7, 0xbf, 0x2c, 0x28, 0x01, 0xf9, 0x10, 0xcb, 0x8c, 0x71, 0x97, 0x8d,
+	0xb5, 0x16, 0xcf, 0xa6, 0xf2, 0x28, 0x0d, 0xa8, 0x27, 0xdd, 0x21, 0xb7, 0x50, 0x26, 0x0f, 0xc8,
+	0xb8, 0xd1, 0x62, 0xbd, 0xa7, 0xac, 0x55, 0x21, 0x54, 0x03, 0xba, 0x4b, 0x36, 0x4e, 0xa3, 0x20,
+	0x9d, 0x32, 0x95, 0x45, 0x6f, 0xc9, 0xb2, 0xa5, 0xb2, 0xd4, 0x94, 0x50, 0x8b, 0xe8, 0x13, 0x72,
+	0x33, 0x88, 0xc6, 0x2a, 0x49, 0xaf, 0x25, 0xc9, 0x5d, 0x95, 0xa4, 0x94, 0x41, 0x39, 0x1c, 0xfe,
+	0xdd, 0x23, 0x7d, 0xf5, 0x22, 0xd1, 0xef, 0xc8, 0x83, 0xc3, 0x33, 0xce, 0x92, 0xfd, 0x38, 0x72,
+	0x59, 0x92, 0x30, 0x6f, 0x9f, 0xc5, 0x07, 0xcc, 0x8d, 0x42, 0x0f, 0x4f, 0x61, 0xcd, 0x7e, 0x33,
+	0xcf, 0xac, 0x55, 0x12, 0x58, 0x45, 0x88, 0xb4, 0x81, 0x1f, 0xb6, 0xa6, 0xed, 0x96, 0x69, 0x57,
+	0x48, 0x60, 0x15, 0x41, 0xf7, 0xc8, 0x3d, 0x1e, 0x71, 0x27, 0xb0, 0x6b, 0xd3, 0xe2, 0x41, 0xae,
+	0xd9, 0x0f, 0xf2, 0xcc, 0x6a, 0xa3, 0xa1, 0x0d, 0x2c, 0x52, 0x3d, 0xab, 0x4d, 0x65, 0xdc, 0x68,
+	0xa4, 0xaa, 0xd3, 0xd0, 0x06, 0xd2, 0x47, 0x64, 0x9d, 0xbd, 0x60, 0xee, 0xb7, 0xfe, 0x94, 0xe1,
+	0x91, 0x6a, 0xf6, 0x86, 0x28, 0x91, 0x05, 0x06, 0xc5, 0x88, 0xbe, 0x4f, 0x6e, 0x9e, 0xa4, 0x2c,
+	0x65, 0x28, 0xed, 0xa1, 0x74, 0x20, 0x8e, 0xa9, 0x00, 0xa1, 0x1c, 0xd2, 0x6d, 0x42, 0x92, 0xf4,
+	0x50, 0x16, 0x67, 0x62, 0xf4, 0x71, 0x61, 0x9b, 0x79, 0x66, 0x55, 0x50, 0xa8, 0x8c, 0xe9, 0x33,
+	0xb2, 0x85, 0xab, 0xfb, 0x2a, 0xe4, 0xc8, 0x31, 0x9e, 0xc6, 0x21, 0xf3, 0x8c, 0x75, 0x74, 0x1a,
+	0x79, 0x66, 0xb5, 0xf2, 0xd0, 0x8a, 0xd2, 0x21, 0xe9, 0x25, 0xb3, 0xc0, 0xe7, 0x89, 0x71, 0x13,
+	0xfd, 0x44, 0x14, 0x85, 0x44, 0x40, 0x3d, 0x51, 0x33, 0x71, 0x62, 0x2f, 0x31, 0x48, 0x45, 0x83,
+	0x08, 0xa8, 0x67, 0xb1, 0xaa, 0xfd, 0x28, 0xe1, 0xbb, 0x7e, 0xc0, 0x59, 0x8c, 0xbb, 0x67, 0xdc,
+	0x6a, 0xac, 0xaa, 0xc1, 0x43, 0x2b, 0x4a, 0x7f, 0x24, 0xef, 0x20, 0x7e, 0xc0, 0xe3, 0xd4, 0xe5,
+	0x69, 0xcc, 0xbc, 0xe7, 0x8c, 0x3b, 0x9e, 0xc3, 0x9d, 0xc6, 0x2b, 0xb1, 0x81, 0xe9, 0xdf, 0xcb,
+	0x33, 0xeb, 0x7a, 0x06, 0xb8, 0x9e, 0x8c, 0x7e, 0x49, 0x06, 0xf8, 0xe3, 0xf7, 0x42, 0xce, 0xe2,
+	0x53, 0x27, 0x30, 0x06, 0x78, 0x8a, 0xf8, 0x3a, 0xd7, 0x88, 0xc7, 0xd1, 0xd4, 0xe7, 0x6c, 0x3a,
+	0xe3, 0x67, 0x50, 0x77, 0x0c, 0x3f, 0x27, 0x7d, 0xd5, 0x8b, 0x45, 0xfb, 0x4a, 0x78, 0x14, 0xb3,
+	0x46, 0xc7, 0x3b, 0x10, 0x58, 0xd9, 0xbe, 0x50, 0x02, 0xf2, 0x31, 0xfc, 0xb5, 0x4b, 0xd6, 0xf7,
+	0xca, 0x96, 0xbb, 0x81, 0xcb, 0x06, 0x26, 0xca, 0x5e, 0x96, 0xac, 0x6e, 0xdf, 0x11, 0xed, 0xa3,
+	0x8a, 0x43, 0x2d, 0xa2, 0xbb, 0x84, 0x62, 0xbc, 0x23, 0x5a, 0x68, 0xf2, 0xdc, 0xe1, 0xe8, 0x95,
+	0x75, 0xf9, 0xbf, 0x3c, 0xb3, 0x5a, 0x58, 0x68, 0xc1, 0x8a, 0xd9, 0x6d, 0x8c, 0x13, 0x55, 0x86,
+	0xe5, 0xec, 0x0a, 0x87, 0x5a, 0x44, 0x3f, 0x23, 0x9b, 0x65, 0x11, 0x1d, 0xb0, 0x90, 0xab, 0x9a,
+	0xa3, 0x79, 0x66, 0x35, 0x18, 0x68, 0xc4, 0xe5, 0x7e, 0xe9, 0xd7, 0xde, 0xaf, 0xdf, 0xbb, 0x44,
+	0x47, 0xbe, 0x98, 0x58, 0xfe, 0x08, 0x60, 0x47, 0x86, 0xd6, 0x98, 0xb8, 0x60, 0xa0, 0x11, 0xd3,
+	0xaf, 0xc9, 0xfd, 0x0a, 0xf2, 0x34, 0xfa, 0x21, 0x0c, 0x22, 0xc7, 0x2b, 0x76, 0xed, 0xff, 0x79,
+	0x66, 0xb5, 0x0b, 0xa0, 0x1d, 0x16, 0x67, 0xe0, 0xd6, 0x30, 0x6c, 0x09, 0x6b, 0xe5, 0x19, 0x2c,
+	0xb3, 0xd0, 0x82, 0x95, 0xdf, 0xcc, 0xc6, 0x17, 0x49, 0x60, 0x2b, 0xbe, 0x99, 0x8b, 0xa9, 0x81,
+	0x1d, 0x25, 0xbb, 0x8c, 0xbb, 0x93, 0xa2, 0x71, 0x55, 0xa7, 0xae, 0xb1, 0xd0, 0x82, 0x0d, 0x7f,
+	0xd3, 0x89, 0x8e, 0xf3, 0x88, 0x9d, 0x9d, 0x30, 0xc7, 0x93, 0x93, 0x8a, 0x7a, 0xa9, 0x1e, 0x69,
+	0x9d, 0x81, 0x46, 0x5c, 0xf3, 0xca, 0xce, 0xa0, 0xb7, 0x78, 0x91, 0x81, 0x46, 0x4c, 0x77, 0xc8,
+	0x5d, 0x8f, 0xb9, 0xd1, 0x74, 0x16, 0x63, 0x71, 0xca, 0xa9, 0x7b, 0x68, 0xbf, 0x9f, 0x67, 0xd6,
+	0x32, 0x09, 0xcb, 0x50, 0x33, 0x89, 0x5c, 0x43, 0xbf, 0x3d, 0x89, 0x5c, 0xc6, 0x32, 0x44, 0x9f,
+	0x90, 0xdb, 0xcd, 0x75, 0xc8, 0xb6, 0x7b, 0x2f, 0xcf, 0xac, 0x26, 0x05, 0x4d, 0x40, 0xd8, 0xf1,
+	0x35, 0x79, 0x9a, 0xce, 0x02, 0xdf, 0x75, 0x38, 0x5b, 0x74, 0x5d, 0xb4, 0x37, 0x28, 0x68, 0x02,
+	0xc2, 0x3e, 0x6b, 0xb4, 0x57, 0x52, 0xda, 0x1b, 0x14, 0x34, 0x01, 0x3a, 0x23, 0x0f, 0x8b, 0x8d,
+	0x5d, 0xd1, 0x00, 0x55, 0xbb, 0x7e, 0x3b, 0xcf, 0xac, 0xd7, 0x6a, 0xe1, 0xb5, 0x0a, 0x7a, 0x46,
+	0xde, 0xaa, 0xee, 0xe1, 0xaa, 0x49, 0x65, 0x13, 0x7f, 0x37, 0xcf, 0xac, 0xeb, 0xc8, 0xe1, 0x3a,
+	0xa2, 0xe1, 0x3f, 0x5d, 0xa2, 0xe3, 0x25, 0x49, 0xb4, 0x2f, 0x26, 0x3f, 0x7a, 0xbb, 0x51, 0x1a,
+	0xd6, 0x9a, 0x67, 0x15, 0x87, 0x5a, 0x44, 0xbf, 0x20, 0x77, 0xd8, 0xe2, 0x53, 0x79, 0x92, 0xb2,
+	0x84, 0xab, 0x26, 0xa0, 0xdb, 0x5b, 0x79, 0x66, 0x2d, 0x71, 0xb0, 0x84, 0xd0, 0x4f, 0xc8, 0x40,
+	0x61, 0xd8, 0x97, 0xe4, 0xf5, 0x45, 0xb7, 0xef, 0xe6, 0x99, 0x55, 0x27, 0xa0, 0x1e, 0x0a, 0x23,
+	0xde, 0xb7, 0x80, 0xb9, 0xcc, 0x3f, 0x2d, 0x2e, 0x2b, 0x68, 0xac, 0x11, 0x50, 0x0f, 0xc5, 0xb5,
+	0x03, 0x01, 0xec, 0xb6, 0xb2, 0xbc, 0xf0, 0xda, 0x51, 0x80, 0x50, 0x0e, 0xc5, 0x6d, 0x26, 0x96,
+	0x6b, 0x95, 0xb5, 0xa4, 0xcb, 0xdb, 0xcc, 0x02, 0x83, 0x62, 0x24, 0x36, 0xd0, 0xab, 0x76, 0xaf,
+	0x7e, 0xd9, 0xff, 0xab, 0x38, 0xd4, 0x22, 0xfb, 0xf0, 0xfc, 0xc2, 0xec, 0xbc, 0xba, 0x30, 0x3b,
+	0x57, 0x17, 0xa6, 0xf6, 0xd3, 0xdc, 0xd4, 0x7e, 0x99, 0x9b, 0xda, 0xcb, 0xb9, 0xa9, 0x9d, 0xcf,
+	0x4d, 0xed, 0x8f, 0xb9, 0xa9, 0xfd, 0x39, 0x37, 0x3b, 0x57, 0x73, 0x53, 0xfb, 0xf9, 0xd2, 0xec,
+	0x9c, 0x5f, 0x9a, 0x9d, 0x57, 0x97, 0x66, 0xe7, 0xfb, 0xc7, 0x63, 0x9f, 0x4f, 0xd2, 0xc3, 0x6d,
+	0x37, 0x9a, 0x8e, 0xc6, 0xb1, 0x73, 0xe4, 0x84, 0xce, 0x28, 0x88, 0x8e, 0xfd, 0x51, 0xdb, 0xff,
+	0x5c, 0x87, 0x3d, 0xfc, 0x8f, 0xea, 0xa3, 0x7f, 0x07, 0x00, 0x0e, 0xc2, 0xe1, 0x90, 0x92, 0x0d,
+	0x00, 0x00,
 }
 
 func (this *Result) Equal(that interface{}) bool {
@@ -881,6 +902,9 @@ func (this *Caches) Equal(that interface{}) bool {
 	if !this.VolumeResult.Equal(&that1.VolumeResult) {
 		return false
 	}
+	if !this.LogResult.Equal(&that1.LogResult) {
+		return false
+	}
 	return true
 }
 func (this *Summary) Equal(that interface{}) bool {
@@ -938,6 +962,9 @@ func (this *Summary) Equal(that interface{}) bool {
 	if this.TotalStructuredMetadataBytesProcessed != that1.TotalStructuredMetadataBytesProcessed {
 		return false
 	}
+	if this.SplitInterval != that1.SplitInterval {
+		return false
+	}
 	return true
 }
 func (this *Querier) Equal(that interface{}) bool {
@@ -1143,13 +1170,14 @@ func (this *Caches) GoString() string {
 	if this == nil {
 		return "nil"
 	}
-	s := make([]string, 0, 9)
+	s := make([]string, 0, 10)
 	s = append(s, "&stats.Caches{")
 	s = append(s, "Chunk: "+strings.Replace(this.Chunk.GoString(), `&`, ``, 1)+",\n")
 	s = append(s, "Index: "+strings.Replace(this.Index.GoString(), `&`, ``, 1)+",\n")
 	s = append(s, "Result: "+strings.Replace(this.Result.GoString(), `&`, ``, 1)+",\n")
 	s = append(s, "StatsResult: "+strings.Replace(this.StatsResult.GoString(), `&`, ``, 1)+",\n")
 	s = append(s, "VolumeResult: "+strings.Replace(this.VolumeResult.GoString(), `&`, ``, 1)+",\n")
+	s = append(s, "LogResult: "+strings.Replace(this.LogResult.GoString(), `&`, ``, 1)+",\n")
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
@@ -1157,7 +1185,7 @@ func (this *Summary) GoString() string {
 	if this == nil {
 		return "nil"
 	}
-	s := make([]string, 0, 16)
+	s := make([]string, 0, 17)
 	s = append(s, "&stats.Summary{")
 	s = append(s, "BytesProcessedPerSecond: "+fmt.Sprintf("%#v", this.BytesProcessedPerSecond)+",\n")
 	s = append(s, "LinesProcessedPerSecond: "+fmt.Sprintf("%#v", this.LinesProcessedPerSecond)+",\n")
@@ -1171,6 +1199,7 @@ func (this *Summary) GoString() string {
 	s = append(s, "Shards: "+fmt.Sprintf("%#v", this.Shards)+",\n")
 	s = append(s, "TotalPostFilterLines: "+fmt.Sprintf("%#v", this.TotalPostFilterLines)+",\n")
 	s = append(s, "TotalStructuredMetadataBytesProcessed: "+fmt.Sprintf("%#v", this.TotalStructuredMetadataBytesProcessed)+",\n")
+	s = append(s, "SplitInterval: "+fmt.Sprintf("%#v", this.SplitInterval)+",\n")
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
@@ -1337,6 +1366,16 @@ func (m *Caches) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	{
+		size, err := m.LogResult.MarshalToSizedBuffer(dAtA[:i])
+		if err != nil {
+			return 0, err
+		}
+		i -= size
+		i = encodeVarintStats(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0x32
 	{
 		size, err := m.VolumeResult.MarshalToSizedBuffer(dAtA[:i])
 		if err != nil {
@@ -1410,6 +1449,12 @@ func (m *Summary) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.SplitInterval != 0 {
+		i -= 8
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.SplitInterval))))
+		i--
+		dAtA[i] = 0x69
+	}
 	if m.TotalStructuredMetadataBytesProcessed != 0 {
 		i = encodeVarintStats(dAtA, i, uint64(m.TotalStructuredMetadataBytesProcessed))
 		i--
@@ -1784,6 +1829,8 @@ func (m *Caches) Size() (n int) {
 	n += 1 + l + sovStats(uint64(l))
 	l = m.VolumeResult.Size()
 	n += 1 + l + sovStats(uint64(l))
+	l = m.LogResult.Size()
+	n += 1 + l + sovStats(uint64(l))
 	return n
 }
 
@@ -1829,6 +1876,9 @@ func (m *Summary) Size() (n int) {
 	if m.TotalStructuredMetadataBytesProcessed != 0 {
 		n += 1 + sovStats(uint64(m.TotalStructuredMetadataBytesProcessed))
 	}
+	if m.SplitInterval != 0 {
+		n += 9
+	}
 	return n
 }
 
@@ -1984,6 +2034,7 @@ func (this *Caches) String() string {
 		`Result:` + strings.Replace(strings.Replace(this.Result.String(), "Cache", "Cache", 1), `&`, ``, 1) + `,`,
 		`StatsResult:` + strings.Replace(strings.Replace(this.StatsResult.String(), "Cache", "Cache", 1), `&`, ``, 1) + `,`,
 		`VolumeResult:` + strings.Replace(strings.Replace(this.VolumeResult.String(), "Cache", "Cache", 1), `&`, ``, 1) + `,`,
+		`LogResult:` + strings.Replace(strings.Replace(this.LogResult.String(), "Cache", "Cache", 1), `&`, ``, 1) + `,`,
 		`}`,
 	}, "")
 	return s
@@ -2005,6 +2056,7 @@ func (this *Summary) String() string {
 		`Shards:` + fmt.Sprintf("%v", this.Shards) + `,`,
 		`TotalPostFilterLines:` + fmt.Sprintf("%v", this.TotalPostFilterLines) + `,`,
 		`TotalStructuredMetadataBytesProcessed:` + fmt.Sprintf("%v", this.TotalStructuredMetadataBytesProcessed) + `,`,
+		`SplitInterval:` + fmt.Sprintf("%v", this.SplitInterval) + `,`,
 		`}`,
 	}, "")
 	return s
@@ -2468,6 +2520,39 @@ func (m *Caches) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LogResult", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthStats
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthStats
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.LogResult.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipStats(dAtA[iNdEx:])
@@ -2733,6 +2818,17 @@ func (m *Summary) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 13:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SplitInterval", wireType)
+			}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.SplitInterval = float64(math.Float64frombits(v))
 		default:
 			iNdEx = preIndex
 			skippy, err := skipStats(dAtA[iNdEx:])
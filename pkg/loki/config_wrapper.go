@@ -613,15 +613,17 @@ func betterTSDBShipperDefaults(cfg, defaults *ConfigWrapper, period config.Perio
 
 // applyEmbeddedCacheConfig turns on Embedded cache for the chunk store, query range results,
 // index stats and volume results only if no other cache storage is configured (redis or memcache).
+// The disk cache is meant to be a second tier behind the embedded cache, so the embedded cache
+// is also turned on when the disk cache is the only one configured.
 // Not applicable for the index queries cache or for the write dedupe cache.
 func applyEmbeddedCacheConfig(r *ConfigWrapper) {
 	chunkCacheConfig := r.ChunkStoreConfig.ChunkCacheConfig
-	if !cache.IsCacheConfigured(chunkCacheConfig) {
+	if !cache.IsCacheConfigured(chunkCacheConfig) || isOnlyDiskCacheSet(chunkCacheConfig) {
 		r.ChunkStoreConfig.ChunkCacheConfig.EmbeddedCache.Enabled = true
 	}
 
 	resultsCacheConfig := r.QueryRange.ResultsCacheConfig.CacheConfig
-	if !cache.IsCacheConfigured(resultsCacheConfig) {
+	if !cache.IsCacheConfigured(resultsCacheConfig) || isOnlyDiskCacheSet(resultsCacheConfig) {
 		r.QueryRange.ResultsCacheConfig.CacheConfig.EmbeddedCache.Enabled = true
 	}
 
@@ -629,15 +631,23 @@ func applyEmbeddedCacheConfig(r *ConfigWrapper) {
 	if !cache.IsCacheConfigured(indexStatsCacheConfig) {
 		// We use the same config as the query range results cache.
 		r.QueryRange.StatsCacheConfig.CacheConfig = r.QueryRange.ResultsCacheConfig.CacheConfig
+	} else if isOnlyDiskCacheSet(indexStatsCacheConfig) {
+		r.QueryRange.StatsCacheConfig.CacheConfig.EmbeddedCache.Enabled = true
 	}
 
 	volumeCacheConfig := r.QueryRange.VolumeCacheConfig.CacheConfig
 	if !cache.IsCacheConfigured(volumeCacheConfig) {
 		// We use the same config as the query range results cache.
 		r.QueryRange.VolumeCacheConfig.CacheConfig = r.QueryRange.ResultsCacheConfig.CacheConfig
+	} else if isOnlyDiskCacheSet(volumeCacheConfig) {
+		r.QueryRange.VolumeCacheConfig.CacheConfig.EmbeddedCache.Enabled = true
 	}
 }
 
+func isOnlyDiskCacheSet(cfg cache.Config) bool {
+	return cache.IsDiskCacheSet(cfg) && !cache.IsMemcacheSet(cfg) && !cache.IsRedisSet(cfg) && !cache.IsSpecificImplementationSet(cfg)
+}
+
 func applyIngesterFinalSleep(cfg *ConfigWrapper) {
 	cfg.Ingester.LifecyclerConfig.FinalSleep = 0 * time.Second
 }
@@ -977,6 +977,20 @@ query_range:
 			config, _, _ := configWrapperFromYAML(t, minimalConfig, nil)
 			assert.True(t, config.QueryRange.ResultsCacheConfig.CacheConfig.EmbeddedCache.Enabled)
 		})
+
+		t.Run("embedded cache is enabled by default in front of the disk cache", func(t *testing.T) {
+			configFileString := `---
+query_range:
+  results_cache:
+    cache:
+      disk_cache:
+        enabled: true
+        directory: /tmp/loki/results-cache`
+
+			config, _, _ := configWrapperFromYAML(t, configFileString, nil)
+			assert.True(t, config.QueryRange.ResultsCacheConfig.CacheConfig.DiskCache.Enabled)
+			assert.True(t, config.QueryRange.ResultsCacheConfig.CacheConfig.EmbeddedCache.Enabled)
+		})
 	})
 
 	t.Run("for the index stats results cache config", func(t *testing.T) {
@@ -1011,6 +1025,20 @@ query_range:
 			assert.True(t, config.QueryRange.StatsCacheConfig.CacheConfig.EmbeddedCache.Enabled)
 		})
 
+		t.Run("embedded cache is enabled by default in front of the disk cache", func(t *testing.T) {
+			configFileString := `---
+query_range:
+  index_stats_results_cache:
+    cache:
+      disk_cache:
+        enabled: true
+        directory: /tmp/loki/index_stats_results_cache`
+
+			config, _, _ := configWrapperFromYAML(t, configFileString, nil)
+			assert.True(t, config.QueryRange.StatsCacheConfig.CacheConfig.DiskCache.Enabled)
+			assert.True(t, config.QueryRange.StatsCacheConfig.CacheConfig.EmbeddedCache.Enabled)
+		})
+
 		t.Run("gets results cache config if not configured directly", func(t *testing.T) {
 			config, _, _ := configWrapperFromYAML(t, defaultResulsCacheString, nil)
 			assert.EqualValues(t, "memcached.host.org", config.QueryRange.StatsCacheConfig.CacheConfig.MemcacheClient.Host)
@@ -1050,6 +1078,20 @@ query_range:
 			assert.True(t, config.QueryRange.VolumeCacheConfig.CacheConfig.EmbeddedCache.Enabled)
 		})
 
+		t.Run("embedded cache is enabled by default in front of the disk cache", func(t *testing.T) {
+			configFileString := `---
+query_range:
+  volume_results_cache:
+    cache:
+      disk_cache:
+        enabled: true
+        directory: /tmp/loki/volume_results_cache`
+
+			config, _, _ := configWrapperFromYAML(t, configFileString, nil)
+			assert.True(t, config.QueryRange.VolumeCacheConfig.CacheConfig.DiskCache.Enabled)
+			assert.True(t, config.QueryRange.VolumeCacheConfig.CacheConfig.EmbeddedCache.Enabled)
+		})
+
 		t.Run("gets results cache config if not configured directly", func(t *testing.T) {
 			config, _, _ := configWrapperFromYAML(t, defaultResulsCacheString, nil)
 			assert.EqualValues(t, "memcached.host.org", config.QueryRange.VolumeCacheConfig.CacheConfig.MemcacheClient.Host)
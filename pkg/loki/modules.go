@@ -334,6 +334,18 @@ func (t *Loki) initDistributor() (services.Service, error) {
 
 	t.Server.HTTP.Path("/api/prom/push").Methods("POST").Handler(pushHandler)
 	t.Server.HTTP.Path("/loki/api/v1/push").Methods("POST").Handler(pushHandler)
+
+	dryRunHandler := middleware.Merge(
+		serverutil.RecoveryHTTPMiddleware,
+		t.HTTPAuthMiddleware,
+	).Wrap(http.HandlerFunc(t.distributor.IngestionPipelinesDryRunHandler))
+	t.Server.HTTP.Path("/distributor/ingestion_pipelines/dry_run").Methods("POST").Handler(dryRunHandler)
+
+	outOfOrderStatsHandler := middleware.Merge(
+		serverutil.RecoveryHTTPMiddleware,
+		t.HTTPAuthMiddleware,
+	).Wrap(http.HandlerFunc(t.distributor.OutOfOrderStatsHandler))
+	t.Server.HTTP.Path("/distributor/out_of_order_stats").Methods("GET").Handler(outOfOrderStatsHandler)
 	return t.distributor, nil
 }
 
@@ -375,6 +387,7 @@ func (t *Loki) initQuerier() (services.Service, error) {
 	toMerge := []middleware.Interface{
 		httpreq.ExtractQueryMetricsMiddleware(),
 		httpreq.ExtractQueryTagsMiddleware(),
+		httpreq.PropagateHeadersMiddleware(httpreq.LokiAllowPartialResultsHeader),
 		serverutil.RecoveryHTTPMiddleware,
 		t.HTTPAuthMiddleware,
 		serverutil.NewPrepopulateMiddleware(),
@@ -491,6 +504,10 @@ func (t *Loki) initQuerier() (services.Service, error) {
 	t.Server.HTTP.Path("/loki/api/v1/tail").Methods("GET", "POST").Handler(httpMiddleware.Wrap(http.HandlerFunc(t.querierAPI.TailHandler)))
 	t.Server.HTTP.Path("/api/prom/tail").Methods("GET", "POST").Handler(httpMiddleware.Wrap(http.HandlerFunc(t.querierAPI.TailHandler)))
 
+	// Export requests stream their response to the client and are therefore
+	// registered externally like tail requests instead of being sent through the frontend.
+	t.Server.HTTP.Path("/loki/api/v1/export").Methods("GET", "POST").Handler(httpMiddleware.Wrap(http.HandlerFunc(t.querierAPI.ExportHandler)))
+
 	// Default codec
 	if t.Codec == nil {
 		t.Codec = queryrange.DefaultCodec
@@ -522,6 +539,21 @@ func (t *Loki) initIngester() (_ services.Service, err error) {
 		level.Warn(util_log.Logger).Log("msg", "The config setting shutdown marker path is not set. The /ingester/prepare_shutdown endpoint won't work")
 	}
 
+	if snapshotCfg := &t.Cfg.Ingester.WAL.Snapshot; snapshotCfg.Enabled && snapshotCfg.ObjectClient == nil {
+		objectStore := snapshotCfg.ObjectStore
+		if objectStore == "" {
+			period, err := t.Cfg.SchemaConfig.SchemaForTime(model.Now())
+			if err != nil {
+				return nil, err
+			}
+			objectStore = period.ObjectType
+		}
+		snapshotCfg.ObjectClient, err = storage.NewObjectClient(objectStore, t.Cfg.StorageConfig, t.clientMetrics)
+		if err != nil {
+			return nil, fmt.Errorf("creating object client for the WAL snapshots: %w", err)
+		}
+	}
+
 	t.Ingester, err = ingester.New(t.Cfg.Ingester, t.Cfg.IngesterClient, t.Store, t.Overrides, t.tenantConfigs, prometheus.DefaultRegisterer, t.Cfg.Distributor.WriteFailuresLogging)
 	if err != nil {
 		return
@@ -547,6 +579,15 @@ func (t *Loki) initIngester() (_ services.Service, err error) {
 	t.Server.HTTP.Methods("POST").Path("/ingester/shutdown").Handler(
 		httpMiddleware.Wrap(http.HandlerFunc(t.Ingester.ShutdownHandler)),
 	)
+	t.Server.HTTP.Methods("POST").Path("/ingester/snapshot").Handler(
+		httpMiddleware.Wrap(http.HandlerFunc(t.Ingester.SnapshotHandler)),
+	)
+	t.Server.HTTP.Methods("POST").Path("/ingester/wal/shred").Handler(
+		middleware.Merge(httpMiddleware, t.HTTPAuthMiddleware).Wrap(http.HandlerFunc(t.Ingester.ShredWALKeysHandler)),
+	)
+	t.Server.HTTP.Methods("GET").Path("/ingester/out_of_order_stats").Handler(
+		middleware.Merge(httpMiddleware, t.HTTPAuthMiddleware).Wrap(http.HandlerFunc(t.Ingester.OutOfOrderStatsHandler)),
+	)
 	return t.Ingester, nil
 }
 
@@ -884,7 +925,7 @@ func (t *Loki) initQueryFrontend() (_ services.Service, err error) {
 
 	toMerge := []middleware.Interface{
 		httpreq.ExtractQueryTagsMiddleware(),
-		httpreq.PropagateHeadersMiddleware(httpreq.LokiActorPathHeader),
+		httpreq.PropagateHeadersMiddleware(httpreq.LokiActorPathHeader, httpreq.LokiAllowPartialResultsHeader),
 		serverutil.RecoveryHTTPMiddleware,
 		t.HTTPAuthMiddleware,
 		queryrange.StatsHTTPMiddleware,
@@ -954,6 +995,12 @@ func (t *Loki) initQueryFrontend() (_ services.Service, err error) {
 		t.Server.HTTP.Path("/api/prom/tail").Methods("GET", "POST").Handler(defaultHandler)
 	}
 
+	// Export responses are streamed and must not be buffered by the frontend, so
+	// they can only be proxied to the queriers using the tail proxy.
+	if !t.isModuleActive(Querier) && t.Cfg.Frontend.TailProxyURL != "" {
+		t.Server.HTTP.Path("/loki/api/v1/export").Methods("GET", "POST").Handler(defaultHandler)
+	}
+
 	if t.frontend == nil {
 		return services.NewIdleService(nil, func(_ error) error {
 			if t.stopper != nil {
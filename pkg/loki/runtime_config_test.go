@@ -71,7 +71,7 @@ overrides:
               period: 24h
               priority: 10
 `))
-	require.Equal(t, "invalid override for tenant 29: invalid labels matchers: parse error at line 1, col 6: syntax error: unexpected IDENTIFIER, expecting STRING", err.Error())
+	require.Equal(t, "invalid override for tenant 29: invalid labels matchers: parse error at line 1, col 6: syntax error: unexpected IDENTIFIER, expecting STRING or PARAM", err.Error())
 	_, err = loadRuntimeConfig(strings.NewReader(
 		`
 overrides:
@@ -0,0 +1,340 @@
+package querier
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/httpgrpc"
+	"github.com/grafana/dskit/tenant"
+
+	"github.com/grafana/loki/pkg/loghttp"
+	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/logql"
+	"github.com/grafana/loki/pkg/logql/syntax"
+	"github.com/grafana/loki/pkg/querier/queryrange"
+	util_log "github.com/grafana/loki/pkg/util/log"
+	"github.com/grafana/loki/pkg/util/marshal"
+	serverutil "github.com/grafana/loki/pkg/util/server"
+	util_validation "github.com/grafana/loki/pkg/util/validation"
+)
+
+// exportFlushEntries is the number of entries after which the export response is flushed to the client.
+const exportFlushEntries = 100
+
+// ExportHandler is a http.HandlerFunc for exporting all entries of a log query.
+// Entries are read split interval by split interval and written to the client
+// as they are produced, so the response is never buffered as a whole. Slow
+// clients apply backpressure on the query as writes block.
+// The response carries the cursor to resume the export from as HTTP trailer.
+// Each interval must be read and written within the query timeout of the tenant.
+func (q *QuerierAPI) ExportHandler(w http.ResponseWriter, r *http.Request) {
+	logger := util_log.WithContext(r.Context(), util_log.Logger)
+
+	req, err := loghttp.ParseExportQuery(r)
+	if err != nil {
+		serverutil.WriteError(httpgrpc.Errorf(http.StatusBadRequest, err.Error()), w)
+		return
+	}
+
+	tenantIDs, err := tenant.TenantIDs(r.Context())
+	if err != nil {
+		serverutil.WriteError(httpgrpc.Errorf(http.StatusBadRequest, err.Error()), w)
+		return
+	}
+	if err := q.validateExportQuery(r.Context(), tenantIDs, req); err != nil {
+		serverutil.WriteError(err, w)
+		return
+	}
+	splitInterval := util_validation.SmallestPositiveNonZeroDurationPerTenant(tenantIDs, q.limits.QuerySplitDuration)
+	timeoutCapture := func(id string) time.Duration { return q.limits.QueryTimeout(r.Context(), id) }
+	queryTimeout := util_validation.SmallestPositiveNonZeroDurationPerTenant(tenantIDs, timeoutCapture)
+
+	w.Header().Set("Content-Type", req.Format.ContentType())
+	w.Header().Set("Trailer", loghttp.ExportCursorTrailer+", "+loghttp.ExportErrorTrailer)
+
+	ew := &exportWriter{w: w, rc: http.NewResponseController(w)}
+	exp := exporter{
+		querier:  q.querier,
+		interval: splitInterval,
+		timeout:  queryTimeout,
+		encode:   exportEncoder(req.Format, ew),
+		flush:    ew.flush,
+		// Exports run for much longer than the server's write timeout, so the
+		// write deadline is extended by the query timeout for each interval instead.
+		deadline: func(deadline time.Time) {
+			if err := ew.rc.SetWriteDeadline(deadline); err != nil && !errors.Is(err, http.ErrNotSupported) {
+				level.Warn(logger).Log("msg", "failed to extend write deadline for export", "err", err)
+			}
+		},
+	}
+
+	level.Info(logger).Log("msg", "starting export", "query", req.Query, "start", req.Start, "end", req.End, "direction", req.Direction, "format", req.Format)
+	cursor, exported, err := exp.export(r.Context(), req)
+	level.Info(logger).Log("msg", "finished export", "query", req.Query, "entries", exported, "cursor", cursor, "err", err)
+
+	// Nothing was sent yet, so the error can still be returned with the appropriate status code.
+	if err != nil && !ew.written {
+		w.Header().Del("Trailer")
+		serverutil.WriteError(err, w)
+		return
+	}
+
+	w.Header().Set(loghttp.ExportCursorTrailer, cursor.String())
+	if err != nil {
+		w.Header().Set(loghttp.ExportErrorTrailer, err.Error())
+	}
+}
+
+// validateExportQuery applies the query limits of the tenants to the export query.
+// Without a limit, the export is limited to the max export entries of the tenants.
+func (q *QuerierAPI) validateExportQuery(ctx context.Context, tenantIDs []string, req *loghttp.ExportQuery) error {
+	for _, id := range tenantIDs {
+		start, end, err := validateQueryTimeRangeLimits(ctx, id, q.limits, req.Start, req.End)
+		if err != nil {
+			return err
+		}
+		req.Start, req.End = start, end
+	}
+
+	expr, err := syntax.ParseLogSelector(req.Query, true)
+	if err != nil {
+		return httpgrpc.Errorf(http.StatusBadRequest, err.Error())
+	}
+	if err := queryrange.ValidateMatchers(ctx, q.limits, expr.Matchers()); err != nil {
+		return httpgrpc.Errorf(http.StatusBadRequest, err.Error())
+	}
+
+	maxEntriesCapture := func(id string) int { return q.limits.MaxExportEntriesPerQuery(ctx, id) }
+	maxEntries := util_validation.SmallestPositiveNonZeroIntPerTenant(tenantIDs, maxEntriesCapture)
+	if maxEntries == 0 {
+		return nil
+	}
+	if req.Limit == 0 {
+		req.Limit = uint32(maxEntries)
+	}
+	if int(req.Limit) > maxEntries {
+		return httpgrpc.Errorf(http.StatusBadRequest,
+			"max export entries limit exceeded, limit > max_export_entries_per_query (%d > %d)", req.Limit, maxEntries)
+	}
+	return nil
+}
+
+// exporter walks the time range of an export query in intervals and encodes
+// all entries it selects.
+type exporter struct {
+	querier  logql.Querier
+	interval time.Duration
+	// timeout bounds the time to read and write each interval, 0 disables it.
+	timeout  time.Duration
+	encode   func(logproto.Stream) error
+	flush    func()
+	deadline func(time.Time)
+}
+
+// export encodes all entries of the query and returns the cursor to resume the
+// export from along with the number of exported entries.
+func (e *exporter) export(ctx context.Context, req *loghttp.ExportQuery) (loghttp.ExportCursor, int, error) {
+	start, end := req.Start, req.End
+
+	// The cursor is the position of the last entry which was exported.
+	cursor := loghttp.ExportCursor{Timestamp: start}
+	if req.Direction == logproto.BACKWARD {
+		cursor.Timestamp = end.Add(-1)
+	}
+
+	var resume *loghttp.ExportCursor
+	if req.Cursor != nil {
+		if req.Cursor.Timestamp.Before(start) || !req.Cursor.Timestamp.Before(end) {
+			return cursor, 0, httpgrpc.Errorf(http.StatusBadRequest, "cursor %s is outside of the requested time range", req.Cursor)
+		}
+		resume, cursor = req.Cursor, *req.Cursor
+		if req.Direction == logproto.FORWARD {
+			start = resume.Timestamp
+		} else {
+			end = resume.Timestamp.Add(1)
+		}
+	}
+
+	var (
+		exported int
+		skipped  int
+	)
+	for _, iv := range splitExportInterval(start, end, e.interval, req.Direction) {
+		limit := uint32(math.MaxInt32)
+		if req.Limit > 0 {
+			limit = req.Limit - uint32(exported)
+			// The entries skipped when resuming are selected as well.
+			if resume != nil {
+				limit += uint32(resume.Skip - skipped)
+			}
+		}
+
+		err := e.exportInterval(ctx, req, iv, limit, func(labels string, entry logproto.Entry) (bool, error) {
+			// Skip the entries which were exported before the export was resumed.
+			if resume != nil && skipped < resume.Skip && entry.Timestamp.Equal(resume.Timestamp) {
+				skipped++
+				return true, nil
+			}
+
+			if err := e.encode(logproto.Stream{Labels: labels, Entries: []logproto.Entry{entry}}); err != nil {
+				return false, err
+			}
+
+			if entry.Timestamp.Equal(cursor.Timestamp) {
+				cursor.Skip++
+			} else {
+				cursor = loghttp.ExportCursor{Timestamp: entry.Timestamp, Skip: 1}
+			}
+
+			exported++
+			if exported%exportFlushEntries == 0 {
+				e.flush()
+			}
+			return req.Limit == 0 || exported < int(req.Limit), nil
+		})
+		if err != nil {
+			return cursor, exported, err
+		}
+
+		e.flush()
+		if req.Limit > 0 && exported >= int(req.Limit) {
+			break
+		}
+	}
+
+	return cursor, exported, nil
+}
+
+// exportInterval selects the entries of the query within the interval and passes
+// them to fn until it returns false. Selecting and writing the entries must finish
+// within the timeout of the exporter.
+func (e *exporter) exportInterval(ctx context.Context, req *loghttp.ExportQuery, iv interval, limit uint32, fn func(string, logproto.Entry) (bool, error)) error {
+	if e.timeout > 0 {
+		deadline := time.Now().Add(e.timeout)
+		if e.deadline != nil {
+			e.deadline(deadline)
+		}
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, deadline)
+		defer cancel()
+	}
+
+	it, err := e.querier.SelectLogs(ctx, logql.SelectLogParams{
+		QueryRequest: &logproto.QueryRequest{
+			Selector:  req.Query,
+			Limit:     limit,
+			Start:     iv.start,
+			End:       iv.end,
+			Direction: req.Direction,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	for it.Next() {
+		more, err := fn(it.Labels(), it.Entry())
+		if err != nil {
+			_ = it.Close()
+			return err
+		}
+		if !more {
+			break
+		}
+	}
+
+	err = it.Error()
+	if closeErr := it.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	return ctx.Err()
+}
+
+// splitExportInterval splits the time range into consecutive intervals of the
+// given length, ordered by the direction of the export.
+func splitExportInterval(start, end time.Time, length time.Duration, direction logproto.Direction) []interval {
+	if length <= 0 || end.Sub(start) <= length {
+		return []interval{{start: start, end: end}}
+	}
+
+	intervals := make([]interval, 0, end.Sub(start)/length+1)
+	if direction == logproto.FORWARD {
+		for s := start; s.Before(end); s = s.Add(length) {
+			intervals = append(intervals, interval{start: s, end: minTime(s.Add(length), end)})
+		}
+		return intervals
+	}
+
+	for e := end; e.After(start); e = e.Add(-length) {
+		intervals = append(intervals, interval{start: maxTime(e.Add(-length), start), end: e})
+	}
+	return intervals
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+// exportEncoder returns the function encoding a single exported stream in the given format.
+func exportEncoder(format loghttp.ExportFormat, w io.Writer) func(logproto.Stream) error {
+	if format == loghttp.ExportFormatProtobuf {
+		var size [binary.MaxVarintLen64]byte
+		return func(stream logproto.Stream) error {
+			buf, err := stream.Marshal()
+			if err != nil {
+				return err
+			}
+			n := binary.PutUvarint(size[:], uint64(len(buf)))
+			if _, err := w.Write(size[:n]); err != nil {
+				return err
+			}
+			_, err = w.Write(buf)
+			return err
+		}
+	}
+
+	return func(stream logproto.Stream) error {
+		return marshal.WriteExportStreamJSON(stream, w)
+	}
+}
+
+// exportWriter tracks whether anything was written to the response and
+// flushes the response to the client on demand.
+type exportWriter struct {
+	w       http.ResponseWriter
+	rc      *http.ResponseController
+	written bool
+}
+
+func (w *exportWriter) Write(p []byte) (int, error) {
+	w.written = true
+	return w.w.Write(p)
+}
+
+func (w *exportWriter) flush() {
+	if !w.written {
+		return
+	}
+	if err := w.rc.Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		level.Debug(util_log.Logger).Log("msg", "failed to flush export response", "err", err)
+	}
+}
@@ -0,0 +1,213 @@
+package querier
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/user"
+	json "github.com/json-iterator/go"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/pkg/iter"
+	"github.com/grafana/loki/pkg/loghttp"
+	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/logql"
+	"github.com/grafana/loki/pkg/validation"
+)
+
+// exportQuerier selects the entries of its streams within the requested time range.
+type exportQuerier struct {
+	*querierMock
+	streams []logproto.Stream
+}
+
+func (q exportQuerier) SelectLogs(_ context.Context, params logql.SelectLogParams) (iter.EntryIterator, error) {
+	streams := make([]logproto.Stream, 0, len(q.streams))
+	for _, s := range q.streams {
+		var entries []logproto.Entry
+		for _, e := range s.Entries {
+			if !e.Timestamp.Before(params.Start) && e.Timestamp.Before(params.End) {
+				entries = append(entries, e)
+			}
+		}
+		if params.Direction == logproto.BACKWARD {
+			sort.Slice(entries, func(i, j int) bool { return entries[i].Timestamp.After(entries[j].Timestamp) })
+		}
+		streams = append(streams, logproto.Stream{Labels: s.Labels, Entries: entries})
+	}
+
+	it := iter.NewStreamsIterator(streams, params.Direction)
+	var limited []logproto.Stream
+	for i := uint32(0); i < params.Limit && it.Next(); i++ {
+		limited = append(limited, logproto.Stream{Labels: it.Labels(), Entries: []logproto.Entry{it.Entry()}})
+	}
+	return iter.NewStreamsIterator(limited, params.Direction), nil
+}
+
+func newExportQuerier() exportQuerier {
+	return exportQuerier{
+		querierMock: newQuerierMock(),
+		streams: []logproto.Stream{
+			mockStreamWithLabels(0, 10, `{app="foo"}`),
+			mockStreamWithLabels(5, 2, `{app="bar"}`),
+		},
+	}
+}
+
+func runExport(t *testing.T, req *loghttp.ExportQuery) ([]logproto.Entry, loghttp.ExportCursor) {
+	var entries []logproto.Entry
+	exp := exporter{
+		querier:  newExportQuerier(),
+		interval: 3 * time.Second,
+		encode: func(s logproto.Stream) error {
+			entries = append(entries, s.Entries...)
+			return nil
+		},
+		flush: func() {},
+	}
+	cursor, exported, err := exp.export(context.Background(), req)
+	require.NoError(t, err)
+	require.Len(t, entries, exported)
+	return entries, cursor
+}
+
+func TestExporter(t *testing.T) {
+	for _, direction := range []logproto.Direction{logproto.FORWARD, logproto.BACKWARD} {
+		t.Run(direction.String(), func(t *testing.T) {
+			req := &loghttp.ExportQuery{
+				Query:     `{app=~".+"}`,
+				Start:     time.Unix(0, 0),
+				End:       time.Unix(10, 0),
+				Direction: direction,
+			}
+
+			all, _ := runExport(t, req)
+			require.Len(t, all, 12)
+			for i := 1; i < len(all); i++ {
+				if direction == logproto.FORWARD {
+					require.False(t, all[i].Timestamp.Before(all[i-1].Timestamp))
+				} else {
+					require.False(t, all[i].Timestamp.After(all[i-1].Timestamp))
+				}
+			}
+
+			// Interrupt the export between two entries sharing the same timestamp and resume it.
+			limited := *req
+			limited.Limit = 6
+			first, cursor := runExport(t, &limited)
+			require.Len(t, first, 6)
+			require.Equal(t, 1, cursor.Skip)
+
+			resumed := *req
+			resumed.Cursor = &cursor
+			rest, _ := runExport(t, &resumed)
+			require.Equal(t, all, append(first, rest...))
+
+			// The entries skipped when resuming don't count towards the limit.
+			resumed.Limit = 2
+			next, _ := runExport(t, &resumed)
+			require.Equal(t, all[6:8], next)
+		})
+	}
+}
+
+func TestSplitExportInterval(t *testing.T) {
+	start, end := time.Unix(0, 0), time.Unix(10, 0)
+
+	require.Equal(t, []interval{
+		{start: time.Unix(0, 0), end: time.Unix(4, 0)},
+		{start: time.Unix(4, 0), end: time.Unix(8, 0)},
+		{start: time.Unix(8, 0), end: time.Unix(10, 0)},
+	}, splitExportInterval(start, end, 4*time.Second, logproto.FORWARD))
+
+	require.Equal(t, []interval{
+		{start: time.Unix(6, 0), end: time.Unix(10, 0)},
+		{start: time.Unix(2, 0), end: time.Unix(6, 0)},
+		{start: time.Unix(0, 0), end: time.Unix(2, 0)},
+	}, splitExportInterval(start, end, 4*time.Second, logproto.BACKWARD))
+
+	require.Equal(t, []interval{{start: start, end: end}}, splitExportInterval(start, end, 0, logproto.FORWARD))
+}
+
+func TestExportHandler(t *testing.T) {
+	limitsCfg := defaultLimitsTestConfig()
+	limitsCfg.MaxExportEntriesPerQuery = 10
+	limitsCfg.RequiredLabels = []string{"app"}
+	limitsCfg.MaxQueryLength = model.Duration(time.Hour)
+	limits, err := validation.NewOverrides(limitsCfg, nil)
+	require.NoError(t, err)
+	api := NewQuerierAPI(mockQuerierConfig(), newExportQuerier(), limits, log.NewNopLogger())
+
+	export := func(query string) *http.Response {
+		req := httptest.NewRequest("GET", "/loki/api/v1/export?"+query, nil)
+		req = req.WithContext(user.InjectOrgID(req.Context(), "test"))
+		require.NoError(t, req.ParseForm())
+
+		rr := httptest.NewRecorder()
+		api.ExportHandler(rr, req)
+		return rr.Result()
+	}
+
+	resp := export(`query={app="foo"}&start=0&end=10000000000&limit=3`)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "application/x-ndjson", resp.Header.Get("Content-Type"))
+
+	var lines []loghttp.Stream
+	dec := json.NewDecoder(resp.Body)
+	for dec.More() {
+		var s loghttp.Stream
+		require.NoError(t, dec.Decode(&s))
+		lines = append(lines, s)
+	}
+	require.Len(t, lines, 3)
+	require.Equal(t, loghttp.LabelSet{"app": "foo"}, lines[0].Labels)
+	require.Equal(t, time.Unix(2, 0), lines[2].Entries[0].Timestamp)
+	require.Equal(t, "2000000000-1", resp.Trailer.Get(loghttp.ExportCursorTrailer))
+	require.Empty(t, resp.Trailer.Get(loghttp.ExportErrorTrailer))
+
+	// Without a limit, at most the max export entries are exported.
+	resp = export(`query={app=~".+"}&start=0&end=10000000000`)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	lines = lines[:0]
+	dec = json.NewDecoder(resp.Body)
+	for dec.More() {
+		var s loghttp.Stream
+		require.NoError(t, dec.Decode(&s))
+		lines = append(lines, s)
+	}
+	require.Len(t, lines, 10)
+	require.Equal(t, "7000000000-1", resp.Trailer.Get(loghttp.ExportCursorTrailer))
+
+	for _, query := range []string{
+		// Metric queries can't be exported.
+		`query=count_over_time({app="foo"}[1m])`,
+		// The limits of the tenant apply.
+		`query={app="foo"}&start=0&end=10000000000&limit=11`,
+		`query={env="prod"}&start=0&end=10000000000`,
+		`query={app="foo"}&start=0&end=7200000000000`,
+	} {
+		resp = export(query)
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode, query)
+	}
+
+	// The protobuf format prefixes each stream with its size.
+	resp = export(`query={app="foo"}&start=0&end=10000000000&limit=1&format=protobuf`)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var body bytes.Buffer
+	_, err = body.ReadFrom(resp.Body)
+	require.NoError(t, err)
+	size, err := binary.ReadUvarint(&body)
+	require.NoError(t, err)
+	var stream logproto.Stream
+	require.NoError(t, stream.Unmarshal(body.Next(int(size))))
+	require.Equal(t, `{app="foo"}`, stream.Labels)
+	require.Zero(t, body.Len())
+}
@@ -2,13 +2,17 @@ package querier
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/grafana/loki/pkg/storage/stores/index/seriesvolume"
 
+	"github.com/go-kit/log/level"
 	"github.com/gogo/status"
+	"github.com/grafana/dskit/concurrency"
 	"github.com/grafana/dskit/httpgrpc"
 	"github.com/grafana/dskit/ring"
 	ring_client "github.com/grafana/dskit/ring/client"
@@ -24,8 +28,10 @@ import (
 	"github.com/grafana/loki/pkg/logproto"
 	"github.com/grafana/loki/pkg/logql"
 	"github.com/grafana/loki/pkg/logql/syntax"
+	"github.com/grafana/loki/pkg/logqlmodel/metadata"
 	"github.com/grafana/loki/pkg/logqlmodel/stats"
 	index_stats "github.com/grafana/loki/pkg/storage/stores/index/stats"
+	"github.com/grafana/loki/pkg/util/httpreq"
 	util_log "github.com/grafana/loki/pkg/util/log"
 )
 
@@ -79,6 +85,10 @@ func (q *IngesterQuerier) forAllIngesters(ctx context.Context, f func(context.Co
 
 // forGivenIngesters runs f, in parallel, for given ingesters
 func (q *IngesterQuerier) forGivenIngesters(ctx context.Context, replicationSet ring.ReplicationSet, f func(context.Context, logproto.QuerierClient) (interface{}, error)) ([]responseFromIngesters, error) {
+	if httpreq.AllowPartialResults(ctx) {
+		return q.forGivenIngestersPartial(ctx, replicationSet, f)
+	}
+
 	cfg := ring.DoUntilQuorumConfig{
 		// Nothing here
 	}
@@ -108,6 +118,74 @@ func (q *IngesterQuerier) forGivenIngesters(ctx context.Context, replicationSet
 	return responses, err
 }
 
+// forGivenIngestersPartial runs f, in parallel, for all given ingesters and
+// tolerates failing ingesters as long as at least one of them responds.
+// If more ingesters fail than the replication set can tolerate, the failures
+// are added as warnings to the query metadata.
+func (q *IngesterQuerier) forGivenIngestersPartial(ctx context.Context, replicationSet ring.ReplicationSet, f func(context.Context, logproto.QuerierClient) (interface{}, error)) ([]responseFromIngesters, error) {
+	var (
+		mtx       sync.Mutex
+		responses = make([]responseFromIngesters, 0, len(replicationSet.Instances))
+		failed    = make([]*ring.InstanceDesc, 0)
+		errs      = make([]error, 0)
+	)
+
+	err := concurrency.ForEachJob(ctx, len(replicationSet.Instances), len(replicationSet.Instances), func(ctx context.Context, idx int) error {
+		ingester := &replicationSet.Instances[idx]
+
+		var resp interface{}
+		client, err := q.pool.GetClientFor(ingester.Addr)
+		if err == nil {
+			resp, err = f(ctx, client.(logproto.QuerierClient))
+		}
+
+		mtx.Lock()
+		defer mtx.Unlock()
+		if err != nil {
+			failed = append(failed, ingester)
+			errs = append(errs, err)
+			return nil
+		}
+		responses = append(responses, responseFromIngesters{ingester.Addr, resp})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(failed) == 0 {
+		return responses, nil
+	}
+	if len(responses) == 0 || ctx.Err() != nil {
+		return nil, errs[0]
+	}
+
+	if !withinReplicationTolerance(replicationSet, failed) {
+		warnings := make([]string, 0, len(failed))
+		for i, ingester := range failed {
+			warnings = append(warnings, fmt.Sprintf("partial result: ingester %s failed: %s", ingester.Addr, errs[i]))
+		}
+		if err := metadata.AddWarnings(ctx, warnings...); err != nil {
+			level.Warn(util_log.WithContext(ctx, util_log.Logger)).Log("msg", "unable to add partial result warnings", "err", err)
+		}
+	}
+
+	return responses, nil
+}
+
+// withinReplicationTolerance returns whether the failed instances are still
+// within the number of failures the replication set tolerates without losing data.
+func withinReplicationTolerance(replicationSet ring.ReplicationSet, failed []*ring.InstanceDesc) bool {
+	if replicationSet.MaxUnavailableZones > 0 {
+		zones := map[string]struct{}{}
+		for _, ingester := range failed {
+			zones[ingester.Zone] = struct{}{}
+		}
+		return len(zones) <= replicationSet.MaxUnavailableZones
+	}
+	return len(failed) <= replicationSet.MaxErrors
+}
+
 func (q *IngesterQuerier) SelectLogs(ctx context.Context, params logql.SelectLogParams) ([]iter.EntryIterator, error) {
 	resps, err := q.forAllIngesters(ctx, func(_ context.Context, client logproto.QuerierClient) (interface{}, error) {
 		stats.FromContext(ctx).AddIngesterReached(1)
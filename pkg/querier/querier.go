@@ -30,6 +30,7 @@ import (
 	"github.com/grafana/loki/pkg/storage"
 	"github.com/grafana/loki/pkg/storage/stores/index/stats"
 	listutil "github.com/grafana/loki/pkg/util"
+	"github.com/grafana/loki/pkg/util/httpreq"
 	"github.com/grafana/loki/pkg/util/spanlogger"
 	util_validation "github.com/grafana/loki/pkg/util/validation"
 )
@@ -99,6 +100,11 @@ type Limits interface {
 	MaxStreamsMatchersPerQuery(context.Context, string) int
 	MaxConcurrentTailRequests(context.Context, string) int
 	MaxEntriesLimitPerQuery(context.Context, string) int
+	MaxExportEntriesPerQuery(context.Context, string) int
+	RequiredLabels(context.Context, string) []string
+	RequiredNumberLabels(context.Context, string) int
+	AllowPartialResults(context.Context, string) bool
+	QuerySplitDuration(string) time.Duration
 }
 
 // Store is the store interface we need on the querier.
@@ -148,6 +154,7 @@ func (q *SingleTenantQuerier) SelectLogs(ctx context.Context, params logql.Selec
 	}
 
 	ingesterQueryInterval, storeQueryInterval := q.buildQueryIntervals(params.Start, params.End)
+	ctx = q.withPartialResults(ctx)
 
 	iters := []iter.EntryIterator{}
 	if !q.cfg.QueryStoreOnly && ingesterQueryInterval != nil {
@@ -202,6 +209,7 @@ func (q *SingleTenantQuerier) SelectSamples(ctx context.Context, params logql.Se
 	}
 
 	ingesterQueryInterval, storeQueryInterval := q.buildQueryIntervals(params.Start, params.End)
+	ctx = q.withPartialResults(ctx)
 
 	iters := []iter.SampleIterator{}
 	if !q.cfg.QueryStoreOnly && ingesterQueryInterval != nil {
@@ -236,6 +244,16 @@ func (q *SingleTenantQuerier) SelectSamples(ctx context.Context, params logql.Se
 	return iter.NewMergeSampleIterator(ctx, iters), nil
 }
 
+// withPartialResults returns a derived context which allows partial results
+// from the ingesters if the tenant opted into them by default.
+func (q *SingleTenantQuerier) withPartialResults(ctx context.Context) context.Context {
+	userID, err := tenant.TenantID(ctx)
+	if err != nil || !q.limits.AllowPartialResults(ctx, userID) {
+		return ctx
+	}
+	return httpreq.InjectAllowPartialResults(ctx)
+}
+
 func (q *SingleTenantQuerier) deletesForUser(ctx context.Context, startT, endT time.Time) ([]*logproto.Delete, error) {
 	userID, err := tenant.TenantID(ctx)
 	if err != nil {
@@ -371,6 +389,7 @@ func (q *SingleTenantQuerier) Label(ctx context.Context, req *logproto.LabelRequ
 	if err != nil {
 		return nil, err
 	}
+	ctx = q.withPartialResults(ctx)
 
 	if *req.Start, *req.End, err = validateQueryTimeRangeLimits(ctx, userID, q.limits, *req.Start, *req.End); err != nil {
 		return nil, err
@@ -445,6 +464,7 @@ func (q *SingleTenantQuerier) Tail(ctx context.Context, req *logproto.TailReques
 	if err != nil {
 		return nil, err
 	}
+	ctx = q.withPartialResults(ctx)
 
 	deletes, err := q.deletesForUser(ctx, req.Start, time.Now())
 	if err != nil {
@@ -512,6 +532,7 @@ func (q *SingleTenantQuerier) Series(ctx context.Context, req *logproto.SeriesRe
 	if err != nil {
 		return nil, err
 	}
+	ctx = q.withPartialResults(ctx)
 
 	if req.Start, req.End, err = validateQueryTimeRangeLimits(ctx, userID, q.limits, req.Start, req.End); err != nil {
 		return nil, err
@@ -730,6 +751,7 @@ func (q *SingleTenantQuerier) IndexStats(ctx context.Context, req *loghttp.Range
 	if err != nil {
 		return nil, err
 	}
+	ctx = q.withPartialResults(ctx)
 
 	start, end, err := validateQueryTimeRangeLimits(ctx, userID, q.limits, req.Start, req.End)
 	if err != nil {
@@ -763,6 +785,7 @@ func (q *SingleTenantQuerier) Volume(ctx context.Context, req *logproto.VolumeRe
 	if err != nil {
 		return nil, err
 	}
+	ctx = q.withPartialResults(ctx)
 
 	matchers, err := syntax.ParseMatchers(req.Matchers, true)
 	if err != nil && req.Matchers != seriesvolume.MatchAny {
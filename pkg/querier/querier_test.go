@@ -3,6 +3,7 @@ package querier
 import (
 	"context"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"testing"
@@ -80,6 +81,59 @@ func TestQuerier_Label_QueryTimeoutConfigFlag(t *testing.T) {
 	store.AssertExpectations(t)
 }
 
+func TestQuerier_Label_PartialResultsDefault(t *testing.T) {
+	startTime := time.Now().Add(-1 * time.Minute)
+	endTime := time.Now()
+
+	request := logproto.LabelRequest{
+		Name:   "test",
+		Values: true,
+		Start:  &startTime,
+		End:    &endTime,
+	}
+
+	healthy := newQuerierClientMock()
+	healthy.On("Label", mock.Anything, &request, mock.Anything).Return(mockLabelResponse([]string{"foo"}), nil)
+	failing := newQuerierClientMock()
+	failing.On("Label", mock.Anything, &request, mock.Anything).Return(nil, errors.New("ingester unavailable"))
+	clientFactory := ring_client.PoolAddrFunc(func(addr string) (ring_client.PoolClient, error) {
+		if addr == "2.2.2.2" {
+			return failing, nil
+		}
+		return healthy, nil
+	})
+
+	store := newStoreMock()
+	store.On("LabelValuesForMetricName", mock.Anything, "test", mock.Anything, mock.Anything, "logs", "test").Return([]string{"bar"}, nil)
+
+	for _, allow := range []bool{false, true} {
+		t.Run(fmt.Sprintf("allow_partial_results=%t", allow), func(t *testing.T) {
+			limitsCfg := defaultLimitsTestConfig()
+			limitsCfg.AllowPartialResults = allow
+			limits, err := validation.NewOverrides(limitsCfg, nil)
+			require.NoError(t, err)
+
+			q, err := newQuerier(
+				mockQuerierConfig(),
+				mockIngesterClientConfig(),
+				clientFactory,
+				newReadRingMock([]ring.InstanceDesc{mockInstanceDesc("1.1.1.1", ring.ACTIVE), mockInstanceDesc("2.2.2.2", ring.ACTIVE)}, 0),
+				&mockDeleteGettter{},
+				store, limits)
+			require.NoError(t, err)
+
+			ctx := user.InjectOrgID(context.Background(), "test")
+			resp, err := q.Label(ctx, &request)
+			if !allow {
+				require.EqualError(t, err, "ingester unavailable")
+				return
+			}
+			require.NoError(t, err)
+			require.ElementsMatch(t, []string{"foo", "bar"}, resp.Values)
+		})
+	}
+}
+
 func TestQuerier_Tail_QueryTimeoutConfigFlag(t *testing.T) {
 	request := logproto.TailRequest{
 		Query:    "{type=\"test\"}",
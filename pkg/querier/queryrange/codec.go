@@ -79,6 +79,38 @@ func (r *LokiRequest) WithShards(shards logql.Shards) *LokiRequest {
 	return &clone
 }
 
+// WithCalendar returns the request with its steps aligned to the calendar, nil for fixed steps.
+func (r *LokiRequest) WithCalendar(cal *logql.Calendar) *LokiRequest {
+	clone := *r
+	clone.Calendar, clone.TimeZone, clone.CalendarRanges = "", "", false
+	if cal != nil {
+		clone.Calendar, clone.TimeZone, clone.CalendarRanges = string(cal.Unit), cal.TimeZone(), cal.Ranges
+	}
+	return &clone
+}
+
+// AlignToSteps implements queryrangebase.StepAligner by flooring the start and the end to the steps of the calendar.
+func (r *LokiRequest) AlignToSteps() (queryrangebase.Request, bool) {
+	cal := r.calendar()
+	if cal == nil {
+		return r, false
+	}
+	return r.WithStartEndTime(cal.Floor(r.StartTs), cal.Floor(r.EndTs)), true
+}
+
+// calendar returns the calendar the steps of the request are aligned to, nil for fixed steps.
+// The calendar is validated when the request is decoded.
+func (r *LokiRequest) calendar() *logql.Calendar {
+	if r.GetCalendar() == "" {
+		return nil
+	}
+	cal, err := logql.ParseCalendar(r.GetCalendar(), r.GetTimeZone(), r.GetCalendarRanges())
+	if err != nil {
+		return nil
+	}
+	return cal
+}
+
 func (r *LokiRequest) LogToSpan(sp opentracing.Span) {
 	sp.LogFields(
 		otlog.String("query", r.GetQuery()),
@@ -89,6 +121,9 @@ func (r *LokiRequest) LogToSpan(sp opentracing.Span) {
 		otlog.Int64("limit", int64(r.GetLimit())),
 		otlog.String("direction", r.GetDirection().String()),
 		otlog.String("shards", strings.Join(r.GetShards(), ",")),
+		otlog.String("calendar", r.GetCalendar()),
+		otlog.String("time zone", r.GetTimeZone()),
+		otlog.Bool("calendar ranges", r.GetCalendarRanges()),
 	)
 }
 
@@ -258,7 +293,7 @@ func (Codec) DecodeRequest(_ context.Context, r *http.Request, _ []string) (quer
 			return nil, httpgrpc.Errorf(http.StatusBadRequest, err.Error())
 		}
 
-		return &LokiRequest{
+		return (&LokiRequest{
 			Query:     rangeQuery.Query,
 			Limit:     rangeQuery.Limit,
 			Direction: rangeQuery.Direction,
@@ -268,7 +303,7 @@ func (Codec) DecodeRequest(_ context.Context, r *http.Request, _ []string) (quer
 			Interval:  rangeQuery.Interval.Milliseconds(),
 			Path:      r.URL.Path,
 			Shards:    rangeQuery.Shards,
-		}, nil
+		}).WithCalendar(rangeQuery.Calendar), nil
 	case InstantQueryOp:
 		req, err := loghttp.ParseInstantQuery(r)
 		if err != nil {
@@ -386,7 +421,7 @@ func (Codec) DecodeHTTPGrpcRequest(ctx context.Context, r *httpgrpc.HTTPRequest)
 		if err != nil {
 			return nil, ctx, httpgrpc.Errorf(http.StatusBadRequest, err.Error())
 		}
-		return &LokiRequest{
+		return (&LokiRequest{
 			Query:     req.Query,
 			Limit:     req.Limit,
 			Direction: req.Direction,
@@ -396,7 +431,7 @@ func (Codec) DecodeHTTPGrpcRequest(ctx context.Context, r *httpgrpc.HTTPRequest)
 			Interval:  req.Interval.Milliseconds(),
 			Path:      r.Url,
 			Shards:    req.Shards,
-		}, ctx, nil
+		}).WithCalendar(req.Calendar), ctx, nil
 	case InstantQueryOp:
 		req, err := loghttp.ParseInstantQuery(httpReq)
 		if err != nil {
@@ -515,6 +550,10 @@ func (c Codec) EncodeRequest(ctx context.Context, r queryrangebase.Request) (*ht
 		header.Set(httpreq.LokiActorPathHeader, actor)
 	}
 
+	if httpreq.AllowPartialResults(ctx) {
+		header.Set(httpreq.LokiAllowPartialResultsHeader, "true")
+	}
+
 	switch request := r.(type) {
 	case *LokiRequest:
 		params := url.Values{
@@ -527,7 +566,13 @@ func (c Codec) EncodeRequest(ctx context.Context, r queryrangebase.Request) (*ht
 		if len(request.Shards) > 0 {
 			params["shards"] = request.Shards
 		}
-		if request.Step != 0 {
+		if request.Calendar != "" {
+			params["step"] = []string{request.Calendar}
+			params["tz"] = []string{request.TimeZone}
+			if request.CalendarRanges {
+				params["calendar_ranges"] = []string{"true"}
+			}
+		} else if request.Step != 0 {
 			params["step"] = []string{fmt.Sprintf("%f", float64(request.Step)/float64(1e3))}
 		}
 		if request.Interval != 0 {
@@ -773,7 +818,8 @@ func decodeResponseJSON(r *http.Response, req queryrangebase.Request) (queryrang
 						ResultType: loghttp.ResultTypeMatrix,
 						Result:     toProtoMatrix(resp.Data.Result.(loghttp.Matrix)),
 					},
-					Headers: convertPrometheusResponseHeadersToPointers(httpResponseHeadersToPromResponseHeaders(r.Header)),
+					Headers:  convertPrometheusResponseHeadersToPointers(httpResponseHeadersToPromResponseHeaders(r.Header)),
+					Warnings: resp.Warnings,
 				},
 				Statistics: resp.Data.Statistics,
 			}, nil
@@ -803,7 +849,8 @@ func decodeResponseJSON(r *http.Response, req queryrangebase.Request) (queryrang
 					ResultType: loghttp.ResultTypeStream,
 					Result:     resp.Data.Result.(loghttp.Streams).ToProto(),
 				},
-				Headers: httpResponseHeadersToPromResponseHeaders(r.Header),
+				Headers:  httpResponseHeadersToPromResponseHeaders(r.Header),
+				Warnings: resp.Warnings,
 			}, nil
 		case loghttp.ResultTypeVector:
 			return &LokiPromResponse{
@@ -813,7 +860,8 @@ func decodeResponseJSON(r *http.Response, req queryrangebase.Request) (queryrang
 						ResultType: loghttp.ResultTypeVector,
 						Result:     toProtoVector(resp.Data.Result.(loghttp.Vector)),
 					},
-					Headers: convertPrometheusResponseHeadersToPointers(httpResponseHeadersToPromResponseHeaders(r.Header)),
+					Headers:  convertPrometheusResponseHeadersToPointers(httpResponseHeadersToPromResponseHeaders(r.Header)),
+					Warnings: resp.Warnings,
 				},
 				Statistics: resp.Data.Statistics,
 			}, nil
@@ -825,7 +873,8 @@ func decodeResponseJSON(r *http.Response, req queryrangebase.Request) (queryrang
 						ResultType: loghttp.ResultTypeScalar,
 						Result:     toProtoScalar(resp.Data.Result.(loghttp.Scalar)),
 					},
-					Headers: convertPrometheusResponseHeadersToPointers(httpResponseHeadersToPromResponseHeaders(r.Header)),
+					Headers:  convertPrometheusResponseHeadersToPointers(httpResponseHeadersToPromResponseHeaders(r.Header)),
+					Warnings: resp.Warnings,
 				},
 				Statistics: resp.Data.Statistics,
 			}, nil
@@ -936,7 +985,7 @@ func encodeResponseJSONTo(version loghttp.Version, res queryrangebase.Response,
 				return err
 			}
 		} else {
-			if err := marshal.WriteQueryResponseJSON(logqlmodel.Streams(streams), response.Statistics, w); err != nil {
+			if err := marshal.WriteQueryResponseJSON(logqlmodel.Streams(streams), response.Warnings, response.Statistics, w); err != nil {
 				return err
 			}
 		}
@@ -1374,6 +1423,9 @@ func (p paramsRangeWrapper) Limit() uint32 { return p.LokiRequest.Limit }
 func (p paramsRangeWrapper) Shards() []string {
 	return p.GetShards()
 }
+func (p paramsRangeWrapper) Calendar() *logql.Calendar {
+	return p.LokiRequest.calendar()
+}
 
 type paramsInstantWrapper struct {
 	*LokiInstantRequest
@@ -1402,6 +1454,7 @@ func (p paramsInstantWrapper) Limit() uint32 { return p.LokiInstantRequest.Limit
 func (p paramsInstantWrapper) Shards() []string {
 	return p.GetShards()
 }
+func (p paramsInstantWrapper) Calendar() *logql.Calendar { return nil }
 
 type paramsSeriesWrapper struct {
 	*LokiSeriesRequest
@@ -1430,6 +1483,7 @@ func (p paramsSeriesWrapper) Limit() uint32 { return 0 }
 func (p paramsSeriesWrapper) Shards() []string {
 	return p.GetShards()
 }
+func (p paramsSeriesWrapper) Calendar() *logql.Calendar { return nil }
 
 type paramsLabelWrapper struct {
 	*LabelRequest
@@ -1458,6 +1512,7 @@ func (p paramsLabelWrapper) Limit() uint32 { return 0 }
 func (p paramsLabelWrapper) Shards() []string {
 	return make([]string, 0)
 }
+func (p paramsLabelWrapper) Calendar() *logql.Calendar { return nil }
 
 func httpResponseHeadersToPromResponseHeaders(httpHeaders http.Header) []queryrangebase.PrometheusResponseHeader {
 	var promHeaders []queryrangebase.PrometheusResponseHeader
@@ -1528,12 +1583,14 @@ func mergeLokiResponse(responses ...queryrangebase.Response) *LokiResponse {
 		lokiRes       = responses[0].(*LokiResponse)
 		mergedStats   stats.Result
 		lokiResponses = make([]*LokiResponse, 0, len(responses))
+		warnings      []string
 	)
 
 	for _, res := range responses {
 		lokiResult := res.(*LokiResponse)
 		mergedStats.MergeSplit(lokiResult.Statistics)
 		lokiResponses = append(lokiResponses, lokiResult)
+		warnings = append(warnings, lokiResult.Warnings...)
 	}
 
 	return &LokiResponse{
@@ -1548,5 +1605,6 @@ func mergeLokiResponse(responses ...queryrangebase.Response) *LokiResponse {
 			ResultType: loghttp.ResultTypeStream,
 			Result:     mergeOrderedNonOverlappingStreams(lokiResponses, lokiRes.Limit, lokiRes.Direction),
 		},
+		Warnings: queryrangebase.MergeWarnings(warnings),
 	}
 }
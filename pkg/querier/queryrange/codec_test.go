@@ -72,6 +72,32 @@ func Test_codec_EncodeDecodeRequest(t *testing.T) {
 			StartTs:   start,
 			EndTs:     end,
 		}, false},
+		{"query_range with params", func() (*http.Request, error) {
+			return http.NewRequest(http.MethodGet,
+				fmt.Sprintf(`/query_range?start=%d&end=%d&query={foo=$foo}|=$line&param_foo=%s&param_line=err&step=10&limit=200&direction=FORWARD`, start.UnixNano(), end.UnixNano(), url.QueryEscape(`b"ar`)), nil)
+		}, &LokiRequest{
+			Query:     `{foo="b\"ar"} |= "err"`,
+			Limit:     200,
+			Step:      10000, // step is expected in ms
+			Direction: logproto.FORWARD,
+			Path:      "/query_range",
+			StartTs:   start,
+			EndTs:     end,
+		}, false},
+		{"query_range with calendar step", func() (*http.Request, error) {
+			return http.NewRequest(http.MethodGet,
+				fmt.Sprintf(`/query_range?start=%d&end=%d&query={foo="bar"}&step=day&tz=Europe/Paris&limit=200&direction=FORWARD`, start.UnixNano(), end.UnixNano()), nil)
+		}, &LokiRequest{
+			Query:     `{foo="bar"}`,
+			Limit:     200,
+			Step:      86400000, // step is expected in ms
+			Direction: logproto.FORWARD,
+			Path:      "/query_range",
+			StartTs:   start,
+			EndTs:     end,
+			Calendar:  "day",
+			TimeZone:  "Europe/Paris",
+		}, false},
 		{"series", func() (*http.Request, error) {
 			return http.NewRequest(http.MethodGet,
 				fmt.Sprintf(`/series?start=%d&end=%d&match={foo="bar"}`, start.UnixNano(), end.UnixNano()), nil)
@@ -592,6 +618,52 @@ func Test_codec_EncodeRequest(t *testing.T) {
 	require.Equal(t, "/loki/api/v1/query_range", req.(*LokiRequest).Path)
 }
 
+func Test_codec_EncodeRequest_Calendar(t *testing.T) {
+	toEncode := &LokiRequest{
+		Query:          `count_over_time({foo="bar"}[1w])`,
+		Limit:          200,
+		Step:           7 * 86400000,
+		Direction:      logproto.FORWARD,
+		Path:           "/query_range",
+		StartTs:        start,
+		EndTs:          end,
+		Calendar:       "week",
+		TimeZone:       "America/New_York",
+		CalendarRanges: true,
+	}
+	got, err := DefaultCodec.EncodeRequest(context.Background(), toEncode)
+	require.NoError(t, err)
+	require.Equal(t, "week", got.URL.Query().Get("step"))
+	require.Equal(t, "America/New_York", got.URL.Query().Get("tz"))
+	require.Equal(t, "true", got.URL.Query().Get("calendar_ranges"))
+
+	// testing a full roundtrip
+	req, err := DefaultCodec.DecodeRequest(context.TODO(), got, nil)
+	require.NoError(t, err)
+	require.Equal(t, toEncode.Step, req.(*LokiRequest).Step)
+	require.Equal(t, toEncode.Calendar, req.(*LokiRequest).Calendar)
+	require.Equal(t, toEncode.TimeZone, req.(*LokiRequest).TimeZone)
+	require.Equal(t, toEncode.CalendarRanges, req.(*LokiRequest).CalendarRanges)
+}
+
+func Test_LokiRequest_AlignToSteps(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	require.NoError(t, err)
+
+	req := &LokiRequest{
+		StartTs: time.Date(2023, 3, 25, 12, 0, 0, 0, paris),
+		EndTs:   time.Date(2023, 3, 27, 12, 0, 0, 0, paris),
+		Step:    86400000,
+	}
+	_, ok := req.AlignToSteps()
+	require.False(t, ok)
+
+	aligned, ok := req.WithCalendar(&logql.Calendar{Unit: logql.CalendarDay, Location: paris}).AlignToSteps()
+	require.True(t, ok)
+	require.True(t, time.Date(2023, 3, 25, 0, 0, 0, 0, paris).Equal(aligned.(*LokiRequest).StartTs))
+	require.True(t, time.Date(2023, 3, 27, 0, 0, 0, 0, paris).Equal(aligned.(*LokiRequest).EndTs))
+}
+
 func Test_codec_series_EncodeRequest(t *testing.T) {
 	got, err := DefaultCodec.EncodeRequest(context.TODO(), &queryrangebase.PrometheusRequest{})
 	require.Error(t, err)
@@ -1441,6 +1513,15 @@ var (
 				"requests": 0,
 				"downloadTime": 0
 			},
+			"logResult": {
+				"entriesFound": 0,
+				"entriesRequested": 0,
+				"entriesStored": 0,
+				"bytesReceived": 0,
+				"bytesSent": 0,
+				"requests": 0,
+				"downloadTime": 0
+			},
 			"result": {
 				"entriesFound": 0,
 				"entriesRequested": 0,
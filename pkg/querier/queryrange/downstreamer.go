@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"reflect"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/go-kit/log/level"
@@ -14,16 +15,19 @@ import (
 	"github.com/prometheus/prometheus/model/labels"
 	"github.com/prometheus/prometheus/promql"
 	"github.com/prometheus/prometheus/promql/parser"
+	"go.uber.org/atomic"
 
 	"github.com/grafana/loki/pkg/loghttp"
 	"github.com/grafana/loki/pkg/logproto"
 	"github.com/grafana/loki/pkg/logql"
 	"github.com/grafana/loki/pkg/logql/sketch"
+	"github.com/grafana/loki/pkg/logql/syntax"
 	"github.com/grafana/loki/pkg/logqlmodel"
 	"github.com/grafana/loki/pkg/logqlmodel/metadata"
 	"github.com/grafana/loki/pkg/logqlmodel/stats"
 	"github.com/grafana/loki/pkg/querier/queryrange/queryrangebase"
 	"github.com/grafana/loki/pkg/querier/queryrange/queryrangebase/definitions"
+	"github.com/grafana/loki/pkg/util/httpreq"
 	"github.com/grafana/loki/pkg/util/spanlogger"
 )
 
@@ -47,7 +51,7 @@ func ParamsToLokiRequest(params logql.Params, shards logql.Shards) queryrangebas
 			Shards:    shards.Encode(),
 		}
 	}
-	return &LokiRequest{
+	return (&LokiRequest{
 		Query:     params.Query(),
 		Limit:     params.Limit(),
 		Step:      params.Step().Milliseconds(),
@@ -57,7 +61,7 @@ func ParamsToLokiRequest(params logql.Params, shards logql.Shards) queryrangebas
 		Direction: params.Direction(),
 		Path:      "/loki/api/v1/query_range", // TODO(owen-d): make this derivable
 		Shards:    shards.Encode(),
-	}
+	}).WithCalendar(params.Calendar())
 }
 
 // Note: After the introduction of the LimitedRoundTripper,
@@ -98,7 +102,13 @@ type instance struct {
 }
 
 func (in instance) Downstream(ctx context.Context, queries []logql.DownstreamQuery) ([]logqlmodel.Result, error) {
-	return in.For(ctx, queries, func(qry logql.DownstreamQuery) (logqlmodel.Result, error) {
+	var (
+		allowPartial = httpreq.AllowPartialResults(ctx)
+		failed       atomic.Int32
+		firstErr     atomic.Error
+	)
+
+	results, err := in.For(ctx, queries, func(qry logql.DownstreamQuery) (logqlmodel.Result, error) {
 		req := ParamsToLokiRequest(qry.Params, qry.Shards).WithQuery(qry.Expr.String())
 		sp, ctx := opentracing.StartSpanFromContext(ctx, "DownstreamHandler.instance")
 		defer sp.Finish()
@@ -108,10 +118,63 @@ func (in instance) Downstream(ctx context.Context, queries []logql.DownstreamQue
 
 		res, err := in.handler.Do(ctx, req)
 		if err != nil {
-			return logqlmodel.Result{}, err
+			// Only tolerate failures of individual shards, a canceled query must still fail.
+			if !allowPartial || ctx.Err() != nil {
+				return logqlmodel.Result{}, err
+			}
+			if failed.Inc() == 1 {
+				firstErr.Store(err)
+			}
+			level.Warn(logger).Log("msg", "downstream query failed, returning partial results", "shards", fmt.Sprintf("%+v", qry.Shards), "err", err)
+			return partialResult(qry, err), nil
 		}
 		return ResponseToResult(res)
 	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Without a single successful query there is nothing partial to return,
+	// fail with the error of the first failed query to keep its status code.
+	if int(failed.Load()) == len(queries) {
+		return nil, firstErr.Load()
+	}
+	return results, nil
+}
+
+// partialResult returns an empty result of the type expected for the query,
+// carrying a warning about the failed downstream query.
+func partialResult(qry logql.DownstreamQuery, err error) logqlmodel.Result {
+	var data parser.Value
+	switch {
+	case isLogSelector(qry.Expr):
+		data = logqlmodel.Streams{}
+	case logql.GetRangeType(qry.Params) == logql.InstantType:
+		data = promql.Vector{}
+	default:
+		data = promql.Matrix{}
+	}
+
+	shards := "all shards"
+	if len(qry.Shards) > 0 {
+		shards = "shard " + strings.Join(qry.Shards.Encode(), ",")
+	}
+
+	return logqlmodel.Result{
+		Data: data,
+		Warnings: []string{fmt.Sprintf(
+			"partial result: %s failed for time range %s to %s: %s",
+			shards,
+			qry.Params.Start().UTC().Format(time.RFC3339Nano),
+			qry.Params.End().UTC().Format(time.RFC3339Nano),
+			err,
+		)},
+	}
+}
+
+func isLogSelector(expr syntax.Expr) bool {
+	_, ok := expr.(syntax.LogSelectorExpr)
+	return ok
 }
 
 // For runs a function against a list of queries, collecting the results or returning an error. The indices are preserved such that input[i] maps to output[i].
@@ -234,6 +297,7 @@ func ResponseToResult(resp queryrangebase.Response) (logqlmodel.Result, error) {
 			Statistics: r.Statistics,
 			Data:       streams,
 			Headers:    resp.GetHeaders(),
+			Warnings:   r.Warnings,
 		}, nil
 
 	case *LokiPromResponse:
@@ -245,12 +309,14 @@ func ResponseToResult(resp queryrangebase.Response) (logqlmodel.Result, error) {
 				Statistics: r.Statistics,
 				Data:       sampleStreamToVector(r.Response.Data.Result),
 				Headers:    resp.GetHeaders(),
+				Warnings:   r.Response.Warnings,
 			}, nil
 		}
 		return logqlmodel.Result{
 			Statistics: r.Statistics,
 			Data:       sampleStreamToMatrix(r.Response.Data.Result),
 			Headers:    resp.GetHeaders(),
+			Warnings:   r.Response.Warnings,
 		}, nil
 	case *TopKSketchesResponse:
 		matrix, err := sketch.TopKMatrixFromProto(r.Response)
@@ -372,8 +438,9 @@ type accumulatedStreams struct {
 	streams      []*logproto.Stream
 	order        logproto.Direction
 
-	stats   stats.Result        // for accumulating statistics from downstream requests
-	headers map[string][]string // for accumulating headers from downstream requests
+	stats    stats.Result        // for accumulating statistics from downstream requests
+	headers  map[string][]string // for accumulating headers from downstream requests
+	warnings []string            // for accumulating warnings from downstream requests
 }
 
 func newStreamAccumulator(order logproto.Direction, limit int) *accumulatedStreams {
@@ -610,6 +677,7 @@ func (acc *accumulatedStreams) Result() []logqlmodel.Result {
 		Data:       streams,
 		Statistics: acc.stats,
 		Headers:    make([]*definitions.PrometheusResponseHeader, 0, len(acc.headers)),
+		Warnings:   queryrangebase.MergeWarnings(acc.warnings),
 	}
 
 	for name, vals := range acc.headers {
@@ -635,6 +703,7 @@ func (acc *accumulatedStreams) Accumulate(x logqlmodel.Result, _ int) error {
 	}
 	acc.stats.Merge(x.Statistics)
 	metadata.ExtendHeaders(acc.headers, x.Headers)
+	acc.warnings = append(acc.warnings, x.Warnings...)
 
 	switch got := x.Data.(type) {
 	case logqlmodel.Streams:
@@ -20,6 +20,7 @@ import (
 	"github.com/grafana/loki/pkg/logqlmodel"
 	"github.com/grafana/loki/pkg/logqlmodel/stats"
 	"github.com/grafana/loki/pkg/querier/queryrange/queryrangebase"
+	"github.com/grafana/loki/pkg/util/httpreq"
 )
 
 func testSampleStreams() []queryrangebase.SampleStream {
@@ -374,6 +375,71 @@ func TestInstanceDownstream(t *testing.T) {
 	require.Equal(t, expected.Data, results[0].Data)
 }
 
+func TestInstanceDownstreamPartialResults(t *testing.T) {
+	params := logql.NewLiteralParams(
+		"",
+		time.Unix(0, 0),
+		time.Unix(3600, 0),
+		0,
+		0,
+		logproto.BACKWARD,
+		1000,
+		nil,
+	)
+	expr, err := syntax.ParseExpr(`{foo="bar"}`)
+	require.Nil(t, err)
+
+	queries := []logql.DownstreamQuery{
+		{Expr: expr, Params: params, Shards: logql.Shards{{Shard: 0, Of: 2}}},
+		{Expr: expr, Params: params, Shards: logql.Shards{{Shard: 1, Of: 2}}},
+	}
+
+	mkHandler := func(failing ...string) queryrangebase.Handler {
+		return queryrangebase.HandlerFunc(
+			func(_ context.Context, req queryrangebase.Request) (queryrangebase.Response, error) {
+				for _, shard := range failing {
+					if req.(*LokiRequest).Shards[0] == shard {
+						return nil, errors.New("ingester unavailable")
+					}
+				}
+				return &LokiResponse{
+					Data: LokiData{
+						Result: []logproto.Stream{{
+							Labels:  `{foo="bar"}`,
+							Entries: []logproto.Entry{{Timestamp: time.Unix(0, 0), Line: "foo"}},
+						}},
+					},
+				}, nil
+			},
+		)
+	}
+
+	downstream := func(ctx context.Context, handler queryrangebase.Handler) ([]logqlmodel.Result, error) {
+		return DownstreamHandler{
+			limits: fakeLimits{},
+			next:   handler,
+		}.Downstreamer(ctx).Downstream(ctx, queries)
+	}
+
+	// Without opting in a failing shard fails the whole query.
+	_, err = downstream(context.Background(), mkHandler("1_of_2"))
+	require.EqualError(t, err, "ingester unavailable")
+
+	ctx := httpreq.InjectAllowPartialResults(context.Background())
+
+	results, err := downstream(ctx, mkHandler("1_of_2"))
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Len(t, results[0].Data.(logqlmodel.Streams), 1)
+	require.Equal(t, []string{
+		"partial result: shard 1_of_2 failed for time range 1970-01-01T00:00:00Z to 1970-01-01T01:00:00Z: ingester unavailable",
+	}, results[0].Warnings)
+
+	// The query still fails if no shard succeeded.
+	_, err = downstream(ctx, mkHandler("0_of_2", "1_of_2"))
+	require.EqualError(t, err, "ingester unavailable")
+}
+
 func TestCancelWhileWaitingResponse(t *testing.T) {
 	mkIn := func() *instance {
 		return DownstreamHandler{
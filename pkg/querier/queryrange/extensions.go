@@ -42,6 +42,13 @@ func (m *LokiPromResponse) GetHeaders() []*queryrangebase.PrometheusResponseHead
 	return nil
 }
 
+func (m *LokiPromResponse) GetWarnings() []string {
+	if m != nil {
+		return m.Response.GetWarnings()
+	}
+	return nil
+}
+
 func (m *LokiPromResponse) WithHeaders(h []queryrangebase.PrometheusResponseHeader) queryrangebase.Response {
 	m.Response.Headers = convertPrometheusResponseHeadersToPointers(h)
 	return m
@@ -54,6 +54,9 @@ type Limits interface {
 	queryrangebase.Limits
 	logql.Limits
 	QuerySplitDuration(string) time.Duration
+	// QuerySplitTargetBytes returns the number of bytes each split of a query
+	// should read, used to adapt the split interval to the query volume.
+	QuerySplitTargetBytes(string) int
 	MaxQuerySeries(context.Context, string) int
 	MaxEntriesLimitPerQuery(context.Context, string) int
 	MinShardingLookback(string) time.Duration
@@ -69,6 +72,7 @@ type Limits interface {
 	MaxQuerierBytesRead(context.Context, string) int
 	MaxStatsCacheFreshness(context.Context, string) time.Duration
 	VolumeEnabled(string) bool
+	AllowPartialResults(context.Context, string) bool
 }
 
 type limits struct {
@@ -137,7 +141,16 @@ func (l cacheKeyLimits) GenerateCacheKey(ctx context.Context, userID string, r q
 
 	// include both the currentInterval and the split duration in key to ensure
 	// a cache key can't be reused when an interval changes
-	return fmt.Sprintf("%s:%s:%d:%d:%d", userID, r.GetQuery(), r.GetStep(), currentInterval, split)
+	key := fmt.Sprintf("%s:%s:%d:%d:%d", userID, r.GetQuery(), r.GetStep(), currentInterval, split)
+
+	// the steps aligned to the calendar depend on its time zone, and the ranges on whether they cover its periods.
+	if lokiReq, ok := r.(*LokiRequest); ok && lokiReq.GetCalendar() != "" {
+		key += ":" + lokiReq.GetCalendar() + "@" + lokiReq.GetTimeZone()
+		if lokiReq.GetCalendarRanges() {
+			key += "+ranges"
+		}
+	}
+	return key
 }
 
 type limitsMiddleware struct {
@@ -703,8 +716,15 @@ func validateMaxEntriesLimits(req *http.Request, reqLimit uint32, limits Limits)
 	return nil
 }
 
-func validateMatchers(req *http.Request, limits Limits, matchers []*labels.Matcher) error {
-	tenants, err := tenant.TenantIDs(req.Context())
+// RequiredLabelsLimits are the limits on the label matchers of the stream selectors of a query.
+type RequiredLabelsLimits interface {
+	RequiredLabels(context.Context, string) []string
+	RequiredNumberLabels(context.Context, string) int
+}
+
+// ValidateMatchers checks the label matchers of a stream selector against the required labels limits of the tenants.
+func ValidateMatchers(ctx context.Context, limits RequiredLabelsLimits, matchers []*labels.Matcher) error {
+	tenants, err := tenant.TenantIDs(ctx)
 	if err != nil {
 		return err
 	}
@@ -718,7 +738,7 @@ func validateMatchers(req *http.Request, limits Limits, matchers []*labels.Match
 
 	// Enforce RequiredLabels limit
 	for _, tenant := range tenants {
-		required := limits.RequiredLabels(req.Context(), tenant)
+		required := limits.RequiredLabels(ctx, tenant)
 		var missing []string
 		for _, label := range required {
 			if _, found := actual[label]; !found {
@@ -735,7 +755,7 @@ func validateMatchers(req *http.Request, limits Limits, matchers []*labels.Match
 	// The reason to enforce this one after RequiredLabels is to avoid users
 	// from adding enough label matchers to pass the RequiredNumberLabels limit but then
 	// having to modify them to use the ones required by RequiredLabels.
-	requiredNumberLabelsCapture := func(id string) int { return limits.RequiredNumberLabels(req.Context(), id) }
+	requiredNumberLabelsCapture := func(id string) int { return limits.RequiredNumberLabels(ctx, id) }
 	if requiredNumberLabels := validation.SmallestPositiveNonZeroIntPerTenant(tenants, requiredNumberLabelsCapture); requiredNumberLabels > 0 {
 		if len(present) < requiredNumberLabels {
 			return fmt.Errorf(requiredNumberLabelsErrTmpl, strings.Join(present, ", "), len(present), requiredNumberLabels)
@@ -104,7 +104,7 @@ func Test_seriesLimiter(t *testing.T) {
 		}()
 		// first time returns  a single series
 		if *c == 0 {
-			if err := marshal.WriteQueryResponseJSON(matrix, stats.Result{}, rw); err != nil {
+			if err := marshal.WriteQueryResponseJSON(matrix, nil, stats.Result{}, rw); err != nil {
 				panic(err)
 			}
 			return
@@ -131,6 +131,7 @@ func Test_seriesLimiter(t *testing.T) {
 					},
 				},
 			},
+			nil,
 			stats.Result{},
 			rw); err != nil {
 			panic(err)
@@ -295,6 +296,30 @@ func Test_GenerateCacheKey_NoDivideZero(t *testing.T) {
 	)
 }
 
+func Test_GenerateCacheKey_Calendar(t *testing.T) {
+	l := cacheKeyLimits{WithSplitByLimits(nil, 0), nil}
+	r := &LokiRequest{
+		Query:    "qry",
+		StartTs:  time.Now(),
+		Step:     int64(24 * time.Hour / time.Millisecond),
+		Calendar: "day",
+		TimeZone: "Europe/Paris",
+	}
+
+	require.Equal(
+		t,
+		fmt.Sprintf("foo:qry:%d:0:0:day@Europe/Paris", r.GetStep()),
+		l.GenerateCacheKey(context.Background(), "foo", r),
+	)
+
+	r.CalendarRanges = true
+	require.Equal(
+		t,
+		fmt.Sprintf("foo:qry:%d:0:0:day@Europe/Paris+ranges", r.GetStep()),
+		l.GenerateCacheKey(context.Background(), "foo", r),
+	)
+}
+
 func Test_WeightedParallelism(t *testing.T) {
 	limits := &fakeLimits{
 		tsdbMaxQueryParallelism: 100,
@@ -20,6 +20,8 @@ import (
 
 	"github.com/grafana/loki/pkg/loghttp"
 	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/logql/syntax"
+	"github.com/grafana/loki/pkg/logqlmodel"
 	"github.com/grafana/loki/pkg/logqlmodel/stats"
 	"github.com/grafana/loki/pkg/querier/queryrange/queryrangebase"
 	"github.com/grafana/loki/pkg/storage/chunk/cache"
@@ -47,24 +49,25 @@ func NewLogResultCacheMetrics(registerer prometheus.Registerer) *LogResultCacheM
 }
 
 // NewLogResultCache creates a new log result cache middleware.
-// Currently it only caches empty filter queries, this is because those are usually easily and freely cacheable.
-// Log hits are difficult to handle because of the limit query parameter and the size of the response.
-// In the future it could be extended to cache non-empty query results.
+// Empty filter queries are always cached, this is because those are usually easily and freely cacheable.
+// If cacheNonEmpty is set, non-empty results are cached as well. Those are keyed by direction and
+// only reused for requests which they answer entirely given the requested limit.
 // see https://docs.google.com/document/d/1_mACOpxdWZ5K0cIedaja5gzMbv-m0lUVazqZd2O4mEU/edit
 func NewLogResultCache(logger log.Logger, limits Limits, cache cache.Cache, shouldCache queryrangebase.ShouldCacheFn,
-	transformer UserIDTransformer, metrics *LogResultCacheMetrics) queryrangebase.Middleware {
+	transformer UserIDTransformer, cacheNonEmpty bool, metrics *LogResultCacheMetrics) queryrangebase.Middleware {
 	if metrics == nil {
 		metrics = NewLogResultCacheMetrics(nil)
 	}
 	return queryrangebase.MiddlewareFunc(func(next queryrangebase.Handler) queryrangebase.Handler {
 		return &logResultCache{
-			next:        next,
-			limits:      limits,
-			cache:       cache,
-			logger:      logger,
-			shouldCache: shouldCache,
-			transformer: transformer,
-			metrics:     metrics,
+			next:          next,
+			limits:        limits,
+			cache:         cache,
+			logger:        logger,
+			shouldCache:   shouldCache,
+			transformer:   transformer,
+			cacheNonEmpty: cacheNonEmpty,
+			metrics:       metrics,
 		}
 	})
 }
@@ -75,6 +78,8 @@ type logResultCache struct {
 	cache       cache.Cache
 	shouldCache queryrangebase.ShouldCacheFn
 	transformer UserIDTransformer
+	// cacheNonEmpty enables caching of non-empty results.
+	cacheNonEmpty bool
 
 	metrics *LogResultCacheMetrics
 	logger  log.Logger
@@ -123,26 +128,37 @@ func (l *logResultCache) Do(ctx context.Context, req queryrangebase.Request) (qu
 	}
 
 	cacheKey := fmt.Sprintf("log:%s:%s:%d:%d", tenant.JoinTenantIDs(transformedTenantIDs), req.GetQuery(), interval.Nanoseconds(), alignedStart.UnixNano()/(interval.Nanoseconds()))
+	// non-empty results additionally depend on the direction of the query.
+	resultKey := fmt.Sprintf("logresult:%s:%s:%s:%d:%d", tenant.JoinTenantIDs(transformedTenantIDs), normalizeQuery(req.GetQuery()), lokiReq.Direction, interval.Nanoseconds(), alignedStart.UnixNano()/(interval.Nanoseconds()))
 
-	_, buff, _, err := l.cache.Fetch(ctx, []string{cache.HashKey(cacheKey)})
+	// Both the empty and the non-empty result are fetched at once.
+	keys := []string{cache.HashKey(cacheKey)}
+	if l.cacheNonEmpty {
+		keys = append(keys, cache.HashKey(resultKey))
+	}
+	found, buff, _, err := l.cache.Fetch(ctx, keys)
 	if err != nil {
 		level.Warn(l.logger).Log("msg", "error fetching cache", "err", err, "cacheKey", cacheKey)
 		return l.next.Do(ctx, req)
 	}
-	// we expect only one key to be found or missing.
-	if len(buff) > 1 {
-		level.Warn(l.logger).Log("msg", "unexpected length of cache return values", "buff", len(buff))
-		return l.next.Do(ctx, req)
+
+	var emptyBuf, resultBuf []byte
+	for i, key := range found {
+		if key == keys[0] {
+			emptyBuf = buff[i]
+		} else {
+			resultBuf = buff[i]
+		}
 	}
 
-	if len(buff) == 0 {
+	if emptyBuf == nil {
 		// cache miss
-		return l.handleMiss(ctx, cacheKey, lokiReq)
+		return l.handleMiss(ctx, cacheKey, resultKey, resultBuf, lokiReq)
 	}
 
 	// cache hit
 	var cachedRequest LokiRequest
-	err = proto.Unmarshal(buff[0], &cachedRequest)
+	err = proto.Unmarshal(emptyBuf, &cachedRequest)
 	if err != nil {
 		level.Warn(l.logger).Log("msg", "error unmarshalling request from cache", "err", err)
 		return l.next.Do(ctx, req)
@@ -150,8 +166,17 @@ func (l *logResultCache) Do(ctx context.Context, req queryrangebase.Request) (qu
 	return l.handleHit(ctx, cacheKey, &cachedRequest, lokiReq)
 }
 
-func (l *logResultCache) handleMiss(ctx context.Context, cacheKey string, req *LokiRequest) (queryrangebase.Response, error) {
-	l.metrics.CacheMiss.Inc()
+// handleMiss answers the request from the cached non-empty result in resultBuf
+// if possible, otherwise from the next handler.
+func (l *logResultCache) handleMiss(ctx context.Context, cacheKey, resultKey string, resultBuf []byte, req *LokiRequest) (queryrangebase.Response, error) {
+	if resultBuf != nil {
+		if resp, ok := l.decodeResult(resultBuf, req); ok {
+			l.recordHit(ctx)
+			return resp, nil
+		}
+	}
+
+	l.recordMiss(ctx)
 	level.Debug(l.logger).Log("msg", "cache miss", "key", cacheKey)
 	resp, err := l.next.Do(ctx, req)
 	if err != nil {
@@ -161,8 +186,10 @@ func (l *logResultCache) handleMiss(ctx context.Context, cacheKey string, req *L
 	if !ok {
 		return nil, fmt.Errorf("unexpected response type %T", resp)
 	}
-	// At the moment we only cache empty results
 	if !isEmpty(lokiRes) {
+		if l.cacheNonEmpty && isCacheableResult(lokiRes) {
+			l.storeResult(ctx, resultKey, req, lokiRes)
+		}
 		return resp, nil
 	}
 	data, err := proto.Marshal(req)
@@ -179,7 +206,7 @@ func (l *logResultCache) handleMiss(ctx context.Context, cacheKey string, req *L
 }
 
 func (l *logResultCache) handleHit(ctx context.Context, cacheKey string, cachedRequest *LokiRequest, lokiReq *LokiRequest) (queryrangebase.Response, error) {
-	l.metrics.CacheHit.Inc()
+	l.recordHit(ctx)
 	// we start with an empty response
 	result := emptyResponse(cachedRequest)
 	// if the request is the same and cover the whole time range,
@@ -295,6 +322,87 @@ func (l *logResultCache) handleHit(ctx context.Context, cacheKey string, cachedR
 	return result, nil
 }
 
+// recordHit reports a cache hit in the metrics and the statistics of the query.
+func (l *logResultCache) recordHit(ctx context.Context) {
+	l.metrics.CacheHit.Inc()
+	st := stats.FromContext(ctx)
+	st.AddCacheEntriesRequested(stats.LogResultCache, 1)
+	st.AddCacheEntriesFound(stats.LogResultCache, 1)
+}
+
+// recordMiss reports a cache miss in the metrics and the statistics of the query.
+func (l *logResultCache) recordMiss(ctx context.Context) {
+	l.metrics.CacheMiss.Inc()
+	stats.FromContext(ctx).AddCacheEntriesRequested(stats.LogResultCache, 1)
+}
+
+// decodeResult returns the cached non-empty result answering the request, if any.
+func (l *logResultCache) decodeResult(buf []byte, req *LokiRequest) (*LokiResponse, bool) {
+	var cached CachedLogResponse
+	if err := proto.Unmarshal(buf, &cached); err != nil {
+		level.Warn(l.logger).Log("msg", "error unmarshalling result from cache", "err", err)
+		return nil, false
+	}
+	return cachedResult(&cached, req)
+}
+
+// storeResult caches the non-empty result of the request.
+func (l *logResultCache) storeResult(ctx context.Context, resultKey string, req *LokiRequest, res *LokiResponse) {
+	cached := CachedLogResponse{
+		Request:  *req,
+		Response: *res,
+	}
+	// The statistics describe the original execution of the query and must not be reported again on hits.
+	cached.Response.Statistics = stats.Result{}
+	cached.Response.Headers = nil
+
+	data, err := proto.Marshal(&cached)
+	if err != nil {
+		level.Warn(l.logger).Log("msg", "error marshalling result", "err", err)
+		return
+	}
+	if err := l.cache.Store(ctx, []string{cache.HashKey(resultKey)}, [][]byte{data}); err != nil {
+		level.Warn(l.logger).Log("msg", "error storing cache", "err", err)
+	}
+}
+
+// cachedResult returns the response to the request from a cached result, if
+// the result contains all entries the request would return.
+// A result which was truncated by its limit only contains the first entries of
+// its time range in its direction, so it can only answer requests for the same
+// time range with an equal or smaller limit.
+// A complete result answers any request within its time range.
+func cachedResult(cached *CachedLogResponse, req *LokiRequest) (*LokiResponse, bool) {
+	if cached.Request.Direction != req.Direction {
+		return nil, false
+	}
+
+	res := &cached.Response
+	truncated := logqlmodel.Streams(res.Data.Result).Lines() >= int64(cached.Request.Limit)
+	sameRange := cached.Request.StartTs.Equal(req.StartTs) && cached.Request.EndTs.Equal(req.EndTs)
+
+	switch {
+	case truncated && (!sameRange || req.Limit > cached.Request.Limit):
+		return nil, false
+	case !truncated && (req.StartTs.Before(cached.Request.StartTs) || req.EndTs.After(cached.Request.EndTs)):
+		return nil, false
+	case !sameRange:
+		res = extractLokiResponse(req.StartTs, req.EndTs, res)
+		streams := res.Data.Result[:0]
+		for _, s := range res.Data.Result {
+			if len(s.Entries) > 0 {
+				streams = append(streams, s)
+			}
+		}
+		res.Data.Result = streams
+	}
+
+	result := emptyResponse(req)
+	// apply the limit of the request.
+	result.Data.Result = mergeOrderedNonOverlappingStreams([]*LokiResponse{res}, req.Limit, req.Direction)
+	return result, true
+}
+
 // extractLokiResponse extracts response with interval [start, end)
 func extractLokiResponse(start, end time.Time, r *LokiResponse) *LokiResponse {
 	extractedResp := LokiResponse{
@@ -335,7 +443,24 @@ func extractLokiResponse(start, end time.Time, r *LokiResponse) *LokiResponse {
 }
 
 func isEmpty(lokiRes *LokiResponse) bool {
-	return lokiRes.Status == loghttp.QueryStatusSuccess && len(lokiRes.Data.Result) == 0
+	// Responses with warnings may be missing data, e.g. partial results, so they are never considered empty.
+	return lokiRes.Status == loghttp.QueryStatusSuccess && len(lokiRes.Data.Result) == 0 && len(lokiRes.Warnings) == 0
+}
+
+// isCacheableResult returns whether a non-empty response can be cached.
+// Responses with warnings may be missing data, e.g. partial results.
+func isCacheableResult(lokiRes *LokiResponse) bool {
+	return lokiRes.Status == loghttp.QueryStatusSuccess && len(lokiRes.Warnings) == 0
+}
+
+// normalizeQuery returns the canonical form of the query, so that equivalent
+// queries share their cached results.
+func normalizeQuery(query string) string {
+	expr, err := syntax.ParseExpr(query)
+	if err != nil {
+		return query
+	}
+	return expr.String()
 }
 
 func emptyResponse(lokiReq *LokiRequest) *LokiResponse {
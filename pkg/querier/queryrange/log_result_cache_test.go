@@ -37,6 +37,7 @@ func Test_LogResultCacheSameRange(t *testing.T) {
 			cache.NewMockCache(),
 			nil,
 			nil,
+			false,
 			nil,
 		)
 	)
@@ -79,6 +80,7 @@ func Test_LogResultCacheSameRangeNonEmpty(t *testing.T) {
 			cache.NewMockCache(),
 			nil,
 			nil,
+			false,
 			nil,
 		)
 	)
@@ -127,6 +129,7 @@ func Test_LogResultCacheSmallerRange(t *testing.T) {
 			cache.NewMockCache(),
 			nil,
 			nil,
+			false,
 			nil,
 		)
 	)
@@ -177,6 +180,7 @@ func Test_LogResultCacheDifferentRange(t *testing.T) {
 			cache.NewMockCache(),
 			nil,
 			nil,
+			false,
 			nil,
 		)
 	)
@@ -253,6 +257,7 @@ func Test_LogResultCacheDifferentRangeNonEmpty(t *testing.T) {
 			cache.NewMockCache(),
 			nil,
 			nil,
+			false,
 			nil,
 		)
 	)
@@ -340,6 +345,7 @@ func Test_LogResultCacheDifferentRangeNonEmptyAndEmpty(t *testing.T) {
 			cache.NewMockCache(),
 			nil,
 			nil,
+			false,
 			nil,
 		)
 	)
@@ -450,6 +456,7 @@ func Test_LogResultNonOverlappingCache(t *testing.T) {
 			mockCache,
 			nil,
 			nil,
+			false,
 			metrics,
 		)
 	)
@@ -663,6 +670,166 @@ func TestExtractLokiResponse(t *testing.T) {
 	}
 }
 
+// resultCache reports the statistics of the wrapped cache as results cache.
+type resultCache struct {
+	cache.Cache
+}
+
+func (resultCache) GetCacheType() stats.CacheType {
+	return stats.ResultCache
+}
+
+func Test_LogResultCacheNonEmptyResults(t *testing.T) {
+	metrics := NewLogResultCacheMetrics(prometheus.NewPedanticRegistry())
+	var (
+		st, ctx = stats.NewContext(user.InjectOrgID(context.Background(), "foo"))
+		lrc     = NewLogResultCache(
+			log.NewNopLogger(),
+			fakeLimits{
+				splits: map[string]time.Duration{"foo": time.Minute},
+			},
+			cache.CollectStats(resultCache{cache.NewMockCache()}),
+			nil,
+			nil,
+			true,
+			metrics,
+		)
+	)
+
+	checkCacheMetrics := func(expectedHits, expectedMisses int) {
+		t.Helper()
+		require.Equal(t, float64(expectedHits), testutil.ToFloat64(metrics.CacheHit))
+		require.Equal(t, float64(expectedMisses), testutil.ToFloat64(metrics.CacheMiss))
+		// hits and misses are reported in the query statistics as well.
+		require.Equal(t, int32(expectedHits), st.Caches().LogResult.EntriesFound)
+		require.Equal(t, int32(expectedHits+expectedMisses), st.Caches().LogResult.EntriesRequested)
+	}
+
+	withLimit := func(limit uint32) *LokiRequest {
+		return &LokiRequest{
+			Query:   `{foo="bar"}`,
+			StartTs: time.Unix(60, 0),
+			EndTs:   time.Unix(120, 0),
+			Limit:   limit,
+		}
+	}
+	truncatedReq, completeReq := withLimit(5), withLimit(10)
+	backwardReq := withLimit(5)
+	backwardReq.Direction = logproto.BACKWARD
+
+	fake := newFakeResponse([]mockResponse{
+		{
+			RequestResponse: queryrangebase.RequestResponse{
+				Request:  truncatedReq,
+				Response: nonEmptyResponse(truncatedReq, time.Unix(61, 0), time.Unix(65, 0), lblFooBar),
+			},
+		},
+		{
+			RequestResponse: queryrangebase.RequestResponse{
+				Request:  completeReq,
+				Response: nonEmptyResponse(completeReq, time.Unix(61, 0), time.Unix(68, 0), lblFooBar),
+			},
+		},
+		{
+			RequestResponse: queryrangebase.RequestResponse{
+				Request:  backwardReq,
+				Response: nonEmptyResponse(backwardReq, time.Unix(61, 0), time.Unix(65, 0), lblFooBar),
+			},
+		},
+	})
+
+	h := lrc.Wrap(fake)
+
+	resp, err := h.Do(ctx, truncatedReq)
+	require.NoError(t, err)
+	require.Equal(t, nonEmptyResponse(truncatedReq, time.Unix(61, 0), time.Unix(65, 0), lblFooBar), resp)
+	checkCacheMetrics(0, 1)
+
+	// the same request is served from the cache.
+	resp, err = h.Do(ctx, truncatedReq)
+	require.NoError(t, err)
+	require.Equal(t, nonEmptyResponse(truncatedReq, time.Unix(61, 0), time.Unix(65, 0), lblFooBar), resp)
+	checkCacheMetrics(1, 1)
+
+	// a truncated result answers requests with a smaller limit.
+	smallerReq := withLimit(3)
+	resp, err = h.Do(ctx, smallerReq)
+	require.NoError(t, err)
+	require.Equal(t, nonEmptyResponse(smallerReq, time.Unix(61, 0), time.Unix(63, 0), lblFooBar), resp)
+	checkCacheMetrics(2, 1)
+
+	// but not requests with a larger limit.
+	resp, err = h.Do(ctx, completeReq)
+	require.NoError(t, err)
+	require.Equal(t, nonEmptyResponse(completeReq, time.Unix(61, 0), time.Unix(68, 0), lblFooBar), resp)
+	checkCacheMetrics(2, 2)
+
+	// a complete result answers requests for a smaller time range.
+	innerReq := withLimit(10)
+	innerReq.StartTs, innerReq.EndTs = time.Unix(62, 0), time.Unix(65, 0)
+	resp, err = h.Do(ctx, innerReq)
+	require.NoError(t, err)
+	require.Equal(t, nonEmptyResponse(innerReq, time.Unix(62, 0), time.Unix(64, 0), lblFooBar), resp)
+	checkCacheMetrics(3, 2)
+
+	// results are not shared between directions.
+	resp, err = h.Do(ctx, backwardReq)
+	require.NoError(t, err)
+	require.Equal(t, nonEmptyResponse(backwardReq, time.Unix(61, 0), time.Unix(65, 0), lblFooBar), resp)
+	checkCacheMetrics(3, 3)
+
+	// the lookups are reported in the query statistics, including the cached result which couldn't be used for the larger limit.
+	require.Equal(t, int32(4), st.Caches().Result.EntriesFound)
+	require.Equal(t, int32(3), st.Caches().Result.EntriesStored)
+	// each lookup fetches the empty and the non-empty result at once.
+	require.Equal(t, int32(6+3), st.Caches().Result.Requests)
+
+	fake.AssertExpectations(t)
+}
+
+func Test_LogResultCacheNonEmptyWithWarnings(t *testing.T) {
+	var (
+		ctx = user.InjectOrgID(context.Background(), "foo")
+		lrc = NewLogResultCache(
+			log.NewNopLogger(),
+			fakeLimits{
+				splits: map[string]time.Duration{"foo": time.Minute},
+			},
+			cache.NewMockCache(),
+			nil,
+			nil,
+			true,
+			nil,
+		)
+	)
+
+	req := &LokiRequest{
+		StartTs: time.Unix(60, 0),
+		EndTs:   time.Unix(120, 0),
+		Limit:   entriesLimit,
+	}
+	partial := nonEmptyResponse(req, time.Unix(61, 0), time.Unix(61, 0), lblFooBar)
+	partial.Warnings = []string{"partial result"}
+
+	fake := newFakeResponse([]mockResponse{
+		{RequestResponse: queryrangebase.RequestResponse{Request: req, Response: partial}},
+		{RequestResponse: queryrangebase.RequestResponse{Request: req, Response: partial}},
+	})
+
+	h := lrc.Wrap(fake)
+	for i := 0; i < 2; i++ {
+		resp, err := h.Do(ctx, req)
+		require.NoError(t, err)
+		require.Equal(t, partial, resp)
+	}
+	fake.AssertExpectations(t)
+}
+
+func TestNormalizeQuery(t *testing.T) {
+	require.Equal(t, normalizeQuery(`{foo="bar"}|="a"`), normalizeQuery(`{ foo = "bar" } |= "a"`))
+	require.Equal(t, `{foo="bar"`, normalizeQuery(`{foo="bar"`))
+}
+
 type fakeResponse struct {
 	*mock.Mock
 }
@@ -92,6 +92,15 @@ var emptyStats = `"stats": {
 			"requests": 0,
 			"downloadTime": 0
 		},
+		"logResult": {
+			"entriesFound": 0,
+			"entriesRequested": 0,
+			"entriesStored": 0,
+			"bytesReceived": 0,
+			"bytesSent": 0,
+			"requests": 0,
+			"downloadTime": 0
+		},
 		"result": {
 			"entriesFound": 0,
 			"entriesRequested": 0,
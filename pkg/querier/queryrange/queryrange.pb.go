@@ -48,6 +48,12 @@ type LokiRequest struct {
 	Direction logproto.Direction `protobuf:"varint,6,opt,name=direction,proto3,enum=logproto.Direction" json:"direction,omitempty"`
 	Path      string             `protobuf:"bytes,7,opt,name=path,proto3" json:"path,omitempty"`
 	Shards    []string           `protobuf:"bytes,8,rep,name=shards,proto3" json:"shards"`
+	// calendar is the unit of the steps aligned to the calendar, i.e. day, week or month, empty for fixed steps.
+	Calendar string `protobuf:"bytes,10,opt,name=calendar,proto3" json:"calendar,omitempty"`
+	// timeZone is the IANA time zone of the calendar.
+	TimeZone string `protobuf:"bytes,11,opt,name=timeZone,proto3" json:"timeZone,omitempty"`
+	// calendarRanges makes the range aggregations over the nominal duration of the steps cover the periods of the calendar.
+	CalendarRanges bool `protobuf:"varint,12,opt,name=calendarRanges,proto3" json:"calendarRanges,omitempty"`
 }
 
 func (m *LokiRequest) Reset()      { *m = LokiRequest{} }
@@ -145,6 +151,27 @@ func (m *LokiRequest) GetShards() []string {
 	return nil
 }
 
+func (m *LokiRequest) GetCalendar() string {
+	if m != nil {
+		return m.Calendar
+	}
+	return ""
+}
+
+func (m *LokiRequest) GetTimeZone() string {
+	if m != nil {
+		return m.TimeZone
+	}
+	return ""
+}
+
+func (m *LokiRequest) GetCalendarRanges() bool {
+	if m != nil {
+		return m.CalendarRanges
+	}
+	return false
+}
+
 type LokiInstantRequest struct {
 	Query     string             `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
 	Limit     uint32             `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
@@ -238,6 +265,7 @@ type LokiResponse struct {
 	Version    uint32                                                                                               `protobuf:"varint,7,opt,name=version,proto3" json:"version,omitempty"`
 	Statistics stats.Result                                                                                         `protobuf:"bytes,8,opt,name=statistics,proto3" json:"statistics"`
 	Headers    []github_com_grafana_loki_pkg_querier_queryrange_queryrangebase_definitions.PrometheusResponseHeader `protobuf:"bytes,9,rep,name=Headers,proto3,customtype=github.com/grafana/loki/pkg/querier/queryrange/queryrangebase/definitions.PrometheusResponseHeader" json:"-"`
+	Warnings   []string                                                                                             `protobuf:"bytes,10,rep,name=warnings,proto3" json:"warnings,omitempty"`
 }
 
 func (m *LokiResponse) Reset()      { *m = LokiResponse{} }
@@ -328,6 +356,66 @@ func (m *LokiResponse) GetStatistics() stats.Result {
 	return stats.Result{}
 }
 
+func (m *LokiResponse) GetWarnings() []string {
+	if m != nil {
+		return m.Warnings
+	}
+	return nil
+}
+
+// CachedLogResponse is a non-empty log query response stored by the log
+// result cache along with the request it answers.
+type CachedLogResponse struct {
+	Request  LokiRequest  `protobuf:"bytes,1,opt,name=request,proto3" json:"request"`
+	Response LokiResponse `protobuf:"bytes,2,opt,name=response,proto3" json:"response"`
+}
+
+func (m *CachedLogResponse) Reset()      { *m = CachedLogResponse{} }
+func (*CachedLogResponse) ProtoMessage() {}
+func (*CachedLogResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_51b9d53b40d11902, []int{3}
+}
+func (m *CachedLogResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *CachedLogResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_CachedLogResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *CachedLogResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CachedLogResponse.Merge(m, src)
+}
+func (m *CachedLogResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *CachedLogResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CachedLogResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CachedLogResponse proto.InternalMessageInfo
+
+func (m *CachedLogResponse) GetRequest() LokiRequest {
+	if m != nil {
+		return m.Request
+	}
+	return LokiRequest{}
+}
+
+func (m *CachedLogResponse) GetResponse() LokiResponse {
+	if m != nil {
+		return m.Response
+	}
+	return LokiResponse{}
+}
+
 type LokiSeriesRequest struct {
 	Match   []string  `protobuf:"bytes,1,rep,name=match,proto3" json:"match,omitempty"`
 	StartTs time.Time `protobuf:"bytes,2,opt,name=startTs,proto3,stdtime" json:"startTs"`
@@ -339,7 +427,7 @@ type LokiSeriesRequest struct {
 func (m *LokiSeriesRequest) Reset()      { *m = LokiSeriesRequest{} }
 func (*LokiSeriesRequest) ProtoMessage() {}
 func (*LokiSeriesRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_51b9d53b40d11902, []int{3}
+	return fileDescriptor_51b9d53b40d11902, []int{4}
 }
 func (m *LokiSeriesRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -414,7 +502,7 @@ type LokiSeriesResponse struct {
 func (m *LokiSeriesResponse) Reset()      { *m = LokiSeriesResponse{} }
 func (*LokiSeriesResponse) ProtoMessage() {}
 func (*LokiSeriesResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_51b9d53b40d11902, []int{4}
+	return fileDescriptor_51b9d53b40d11902, []int{5}
 }
 func (m *LokiSeriesResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -482,7 +570,7 @@ type LokiLabelNamesResponse struct {
 func (m *LokiLabelNamesResponse) Reset()      { *m = LokiLabelNamesResponse{} }
 func (*LokiLabelNamesResponse) ProtoMessage() {}
 func (*LokiLabelNamesResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_51b9d53b40d11902, []int{5}
+	return fileDescriptor_51b9d53b40d11902, []int{6}
 }
 func (m *LokiLabelNamesResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -547,7 +635,7 @@ type LokiData struct {
 func (m *LokiData) Reset()      { *m = LokiData{} }
 func (*LokiData) ProtoMessage() {}
 func (*LokiData) Descriptor() ([]byte, []int) {
-	return fileDescriptor_51b9d53b40d11902, []int{6}
+	return fileDescriptor_51b9d53b40d11902, []int{7}
 }
 func (m *LokiData) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -592,7 +680,7 @@ type LokiPromResponse struct {
 func (m *LokiPromResponse) Reset()      { *m = LokiPromResponse{} }
 func (*LokiPromResponse) ProtoMessage() {}
 func (*LokiPromResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_51b9d53b40d11902, []int{7}
+	return fileDescriptor_51b9d53b40d11902, []int{8}
 }
 func (m *LokiPromResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -643,7 +731,7 @@ type IndexStatsResponse struct {
 func (m *IndexStatsResponse) Reset()      { *m = IndexStatsResponse{} }
 func (*IndexStatsResponse) ProtoMessage() {}
 func (*IndexStatsResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_51b9d53b40d11902, []int{8}
+	return fileDescriptor_51b9d53b40d11902, []int{9}
 }
 func (m *IndexStatsResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -680,7 +768,7 @@ type VolumeResponse struct {
 func (m *VolumeResponse) Reset()      { *m = VolumeResponse{} }
 func (*VolumeResponse) ProtoMessage() {}
 func (*VolumeResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_51b9d53b40d11902, []int{9}
+	return fileDescriptor_51b9d53b40d11902, []int{10}
 }
 func (m *VolumeResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -717,7 +805,7 @@ type TopKSketchesResponse struct {
 func (m *TopKSketchesResponse) Reset()      { *m = TopKSketchesResponse{} }
 func (*TopKSketchesResponse) ProtoMessage() {}
 func (*TopKSketchesResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_51b9d53b40d11902, []int{10}
+	return fileDescriptor_51b9d53b40d11902, []int{11}
 }
 func (m *TopKSketchesResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -754,7 +842,7 @@ type QuantileSketchResponse struct {
 func (m *QuantileSketchResponse) Reset()      { *m = QuantileSketchResponse{} }
 func (*QuantileSketchResponse) ProtoMessage() {}
 func (*QuantileSketchResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_51b9d53b40d11902, []int{11}
+	return fileDescriptor_51b9d53b40d11902, []int{12}
 }
 func (m *QuantileSketchResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -799,7 +887,7 @@ type QueryResponse struct {
 func (m *QueryResponse) Reset()      { *m = QueryResponse{} }
 func (*QueryResponse) ProtoMessage() {}
 func (*QueryResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_51b9d53b40d11902, []int{12}
+	return fileDescriptor_51b9d53b40d11902, []int{13}
 }
 func (m *QueryResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -950,6 +1038,7 @@ func init() {
 	proto.RegisterType((*LokiRequest)(nil), "queryrange.LokiRequest")
 	proto.RegisterType((*LokiInstantRequest)(nil), "queryrange.LokiInstantRequest")
 	proto.RegisterType((*LokiResponse)(nil), "queryrange.LokiResponse")
+	proto.RegisterType((*CachedLogResponse)(nil), "queryrange.CachedLogResponse")
 	proto.RegisterType((*LokiSeriesRequest)(nil), "queryrange.LokiSeriesRequest")
 	proto.RegisterType((*LokiSeriesResponse)(nil), "queryrange.LokiSeriesResponse")
 	proto.RegisterType((*LokiLabelNamesResponse)(nil), "queryrange.LokiLabelNamesResponse")
@@ -967,87 +1056,94 @@ func init() {
 }
 
 var fileDescriptor_51b9d53b40d11902 = []byte{
-	// 1276 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xec, 0x97, 0xcb, 0x8f, 0xdb, 0x44,
-	0x1c, 0xc7, 0xed, 0xbc, 0x76, 0x33, 0xdb, 0x2e, 0x65, 0x76, 0xd5, 0x9a, 0xa5, 0xb2, 0xa3, 0x48,
-	0xd0, 0x20, 0x81, 0x23, 0xb2, 0xa5, 0xa5, 0xbc, 0x44, 0x4d, 0xa9, 0xb6, 0xa2, 0xa0, 0xd6, 0x8d,
-	0x38, 0x70, 0x9b, 0x24, 0xb3, 0x89, 0x89, 0x5f, 0xeb, 0x99, 0x54, 0xdd, 0x1b, 0x17, 0x6e, 0x20,
-	0xf5, 0xaf, 0x40, 0x48, 0x54, 0xfc, 0x01, 0x1c, 0x39, 0xf5, 0xb8, 0xc7, 0x2a, 0x12, 0x86, 0xa6,
-	0x17, 0xd8, 0x53, 0xff, 0x04, 0x34, 0x33, 0xb6, 0x33, 0x4e, 0xd2, 0x36, 0x29, 0x97, 0x56, 0xe2,
-	0x92, 0xcc, 0xe3, 0xf7, 0x1d, 0xcf, 0x7c, 0x7e, 0x0f, 0x7b, 0xc0, 0xb9, 0x70, 0xd8, 0x6f, 0x1e,
-	0x8c, 0x70, 0xe4, 0xe0, 0x88, 0xff, 0x1f, 0x46, 0xc8, 0xef, 0x63, 0xa9, 0x69, 0x86, 0x51, 0x40,
-	0x03, 0x08, 0xa6, 0x23, 0x3b, 0xad, 0xbe, 0x43, 0x07, 0xa3, 0x8e, 0xd9, 0x0d, 0xbc, 0x66, 0x3f,
-	0xe8, 0x07, 0xcd, 0x7e, 0x10, 0xf4, 0x5d, 0x8c, 0x42, 0x87, 0x24, 0xcd, 0x66, 0x14, 0x76, 0x9b,
-	0x84, 0x22, 0x3a, 0x22, 0x42, 0xbf, 0xb3, 0xcd, 0x0c, 0x79, 0x93, 0x4b, 0x92, 0x51, 0x23, 0x31,
-	0xe7, 0xbd, 0xce, 0x68, 0xbf, 0x49, 0x1d, 0x0f, 0x13, 0x8a, 0xbc, 0x30, 0x31, 0x78, 0x9d, 0xed,
-	0xcf, 0x0d, 0xfa, 0x42, 0x99, 0x36, 0x92, 0xc9, 0xd7, 0x72, 0x93, 0x64, 0x88, 0x69, 0x77, 0x90,
-	0x4c, 0xd5, 0x92, 0xa9, 0x03, 0xd7, 0x0b, 0x7a, 0xd8, 0xe5, 0x7b, 0x21, 0xe2, 0x37, 0xb1, 0xd8,
-	0x62, 0x16, 0xe1, 0x88, 0x0c, 0xf8, 0x4f, 0x32, 0xf8, 0xd9, 0x33, 0x71, 0x74, 0x10, 0xc1, 0xcd,
-	0x1e, 0xde, 0x77, 0x7c, 0x87, 0x3a, 0x81, 0x4f, 0xe4, 0x76, 0xb2, 0xc8, 0x85, 0xe5, 0x16, 0x99,
-	0x45, 0x5c, 0x3f, 0x2a, 0x80, 0x8d, 0xeb, 0xc1, 0xd0, 0xb1, 0xf1, 0xc1, 0x08, 0x13, 0x0a, 0xb7,
-	0x41, 0x99, 0xdb, 0x68, 0x6a, 0x4d, 0x6d, 0x54, 0x6d, 0xd1, 0x61, 0xa3, 0xae, 0xe3, 0x39, 0x54,
-	0x2b, 0xd4, 0xd4, 0xc6, 0x49, 0x5b, 0x74, 0x20, 0x04, 0x25, 0x42, 0x71, 0xa8, 0x15, 0x6b, 0x6a,
-	0xa3, 0x68, 0xf3, 0x36, 0xdc, 0x01, 0xeb, 0x8e, 0x4f, 0x71, 0x74, 0x1b, 0xb9, 0x5a, 0x95, 0x8f,
-	0x67, 0x7d, 0xf8, 0x09, 0x58, 0x23, 0x14, 0x45, 0xb4, 0x4d, 0xb4, 0x52, 0x4d, 0x6d, 0x6c, 0xb4,
-	0x76, 0x4c, 0xe1, 0x0a, 0x33, 0x75, 0x85, 0xd9, 0x4e, 0x5d, 0x61, 0xad, 0xdf, 0x8f, 0x0d, 0xe5,
-	0xee, 0x9f, 0x86, 0x6a, 0xa7, 0x22, 0xf8, 0x01, 0x28, 0x63, 0xbf, 0xd7, 0x26, 0x5a, 0x79, 0x05,
-	0xb5, 0x90, 0xc0, 0x77, 0x41, 0xb5, 0xe7, 0x44, 0xb8, 0xcb, 0x98, 0x69, 0x95, 0x9a, 0xda, 0xd8,
-	0x6c, 0x6d, 0x99, 0x99, 0x6b, 0xaf, 0xa4, 0x53, 0xf6, 0xd4, 0x8a, 0x1d, 0x2f, 0x44, 0x74, 0xa0,
-	0xad, 0x71, 0x12, 0xbc, 0x0d, 0xeb, 0xa0, 0x42, 0x06, 0x28, 0xea, 0x11, 0x6d, 0xbd, 0x56, 0x6c,
-	0x54, 0x2d, 0x70, 0x1c, 0x1b, 0xc9, 0x88, 0x9d, 0xfc, 0xd7, 0xff, 0x51, 0x01, 0x64, 0x48, 0xaf,
-	0xf9, 0x84, 0x22, 0x9f, 0x3e, 0x0f, 0xd9, 0x8f, 0x40, 0x85, 0x05, 0x65, 0x9b, 0x70, 0xb6, 0xcb,
-	0x1e, 0x35, 0xd1, 0xe4, 0xcf, 0x5a, 0x5a, 0xe9, 0xac, 0xe5, 0x85, 0x67, 0xad, 0x3c, 0xf1, 0xac,
-	0xbf, 0x94, 0xc0, 0x09, 0x11, 0x3e, 0x24, 0x0c, 0x7c, 0x82, 0x99, 0xe8, 0x16, 0x4f, 0x41, 0x71,
-	0xcc, 0x44, 0xc4, 0x47, 0xec, 0x64, 0x06, 0x7e, 0x0a, 0x4a, 0x57, 0x10, 0x45, 0xfc, 0xc8, 0x1b,
-	0xad, 0x6d, 0x53, 0x0a, 0x4a, 0xb6, 0x16, 0x9b, 0xb3, 0x4e, 0xb3, 0x53, 0x1d, 0xc7, 0xc6, 0x66,
-	0x0f, 0x51, 0xf4, 0x76, 0xe0, 0x39, 0x14, 0x7b, 0x21, 0x3d, 0xb4, 0xb9, 0x12, 0xbe, 0x07, 0xaa,
-	0x9f, 0x47, 0x51, 0x10, 0xb5, 0x0f, 0x43, 0xcc, 0x11, 0x55, 0xad, 0x33, 0xc7, 0xb1, 0xb1, 0x85,
-	0xd3, 0x41, 0x49, 0x31, 0xb5, 0x84, 0x6f, 0x81, 0x32, 0xef, 0x70, 0x28, 0x55, 0x6b, 0xeb, 0x38,
-	0x36, 0x5e, 0xe1, 0x12, 0xc9, 0x5c, 0x58, 0xe4, 0x19, 0x96, 0x97, 0x62, 0x98, 0xb9, 0xb2, 0x22,
-	0xbb, 0x52, 0x03, 0x6b, 0xb7, 0x71, 0x44, 0xd8, 0x32, 0x6b, 0x7c, 0x3c, 0xed, 0xc2, 0xcb, 0x00,
-	0x30, 0x30, 0x0e, 0xa1, 0x4e, 0x97, 0xc5, 0x13, 0x83, 0x71, 0xd2, 0x14, 0xe5, 0xc2, 0xc6, 0x64,
-	0xe4, 0x52, 0x0b, 0x26, 0x14, 0x24, 0x43, 0x5b, 0x6a, 0xc3, 0x7b, 0x2a, 0x58, 0xdb, 0xc3, 0xa8,
-	0x87, 0x23, 0xa2, 0x55, 0x6b, 0xc5, 0xc6, 0x46, 0xeb, 0x0d, 0x53, 0xae, 0x0d, 0x37, 0xa2, 0xc0,
-	0xc3, 0x74, 0x80, 0x47, 0x24, 0x75, 0x90, 0xb0, 0xb6, 0x86, 0xe3, 0xd8, 0xe8, 0xc8, 0x15, 0x35,
-	0x42, 0xfb, 0xc8, 0x47, 0x4d, 0x37, 0x18, 0x3a, 0xcd, 0x95, 0xeb, 0xd1, 0x13, 0x9f, 0x73, 0x1c,
-	0x1b, 0xea, 0x3b, 0x76, 0xba, 0xc5, 0xfa, 0x1f, 0x2a, 0x78, 0x95, 0x79, 0xf8, 0x16, 0x5b, 0x9b,
-	0x48, 0x89, 0xe1, 0x21, 0xda, 0x1d, 0x68, 0x2a, 0x0b, 0x33, 0x5b, 0x74, 0xe4, 0x62, 0x51, 0xf8,
-	0x4f, 0xc5, 0xa2, 0xb8, 0x7a, 0xb1, 0x48, 0xb3, 0xa1, 0xb4, 0x30, 0x1b, 0xca, 0x4f, 0xcc, 0x86,
-	0x1f, 0x8a, 0x22, 0xf3, 0xd3, 0xf3, 0xad, 0x90, 0x13, 0x57, 0xb3, 0x9c, 0x28, 0xf2, 0xdd, 0x66,
-	0xa1, 0x26, 0xd6, 0xba, 0xd6, 0xc3, 0x3e, 0x75, 0xf6, 0x1d, 0x1c, 0x3d, 0x23, 0x33, 0xa4, 0x70,
-	0x2b, 0xe6, 0xc3, 0x4d, 0x8e, 0x95, 0xd2, 0x0b, 0x1f, 0x2b, 0x33, 0xd9, 0x51, 0x7e, 0x8e, 0xec,
-	0xa8, 0x3f, 0x2e, 0x80, 0xd3, 0xcc, 0x1d, 0xd7, 0x51, 0x07, 0xbb, 0x5f, 0x21, 0x6f, 0x45, 0x97,
-	0xbc, 0x29, 0xb9, 0xa4, 0x6a, 0xc1, 0xff, 0x91, 0x2f, 0x81, 0xfc, 0x27, 0x15, 0xac, 0xa7, 0x35,
-	0x1c, 0x9a, 0x00, 0x08, 0x19, 0x2f, 0xd3, 0x02, 0xf4, 0x26, 0x13, 0x47, 0xd9, 0xa8, 0x2d, 0x59,
-	0xc0, 0x6f, 0x41, 0x45, 0xf4, 0x92, 0x2c, 0x38, 0x23, 0x65, 0x01, 0x8d, 0x30, 0xf2, 0x2e, 0xf7,
-	0x50, 0x48, 0x71, 0x64, 0x5d, 0x62, 0xbb, 0x18, 0xc7, 0xc6, 0xb9, 0xa7, 0x21, 0xe2, 0x5f, 0x58,
-	0x42, 0xc7, 0x9c, 0x2b, 0x9e, 0x69, 0x27, 0x4f, 0xa8, 0xff, 0xa8, 0x82, 0x53, 0x6c, 0xa3, 0x0c,
-	0x4d, 0x16, 0x15, 0x57, 0xc0, 0x7a, 0x94, 0xb4, 0xf9, 0x76, 0x37, 0x5a, 0x75, 0x33, 0x8f, 0x75,
-	0x01, 0x4a, 0xab, 0x74, 0x3f, 0x36, 0x54, 0x3b, 0x53, 0xc2, 0xdd, 0x1c, 0xc6, 0xc2, 0x22, 0x8c,
-	0x4c, 0xa2, 0xe4, 0xc0, 0xfd, 0x56, 0x00, 0xf0, 0x9a, 0xdf, 0xc3, 0x77, 0x58, 0xf0, 0x4d, 0xe3,
-	0x74, 0x34, 0xb7, 0xa3, 0xb3, 0x53, 0x28, 0xf3, 0xf6, 0xd6, 0x87, 0xe3, 0xd8, 0xb8, 0xf8, 0x34,
-	0x2a, 0x4f, 0x11, 0x4b, 0x47, 0x90, 0x03, 0xb7, 0xf0, 0xe2, 0xbf, 0x57, 0x7e, 0x2d, 0x80, 0xcd,
-	0xaf, 0x03, 0x77, 0xe4, 0xe1, 0x0c, 0x9c, 0x37, 0x07, 0x4e, 0x9b, 0x82, 0xcb, 0xdb, 0x5a, 0x17,
-	0xc7, 0xb1, 0xb1, 0xbb, 0x14, 0xb4, 0xbc, 0xf0, 0xe5, 0x05, 0x76, 0xaf, 0x00, 0xb6, 0xdb, 0x41,
-	0xf8, 0xc5, 0x2d, 0x7e, 0x7d, 0x91, 0xea, 0x22, 0x9e, 0xc3, 0xb6, 0x3d, 0xc5, 0xc6, 0x14, 0x5f,
-	0x22, 0x1a, 0x39, 0x77, 0xac, 0xdd, 0x71, 0x6c, 0x34, 0x97, 0x42, 0x36, 0x15, 0xbd, 0xbc, 0xb8,
-	0x7e, 0x2f, 0x80, 0xd3, 0x37, 0x47, 0xc8, 0xa7, 0x8e, 0x8b, 0x05, 0xb2, 0x0c, 0xd8, 0xe1, 0x1c,
-	0x30, 0x7d, 0x0a, 0x2c, 0xaf, 0x49, 0xd0, 0x7d, 0x3c, 0x8e, 0x8d, 0x4b, 0x4b, 0xa1, 0x5b, 0x24,
-	0x7f, 0x79, 0x21, 0x7e, 0x5f, 0x02, 0x27, 0x6f, 0xb2, 0x55, 0x32, 0x76, 0xef, 0x83, 0x0a, 0xe1,
-	0x5f, 0x37, 0x19, 0xb9, 0x99, 0x9b, 0x40, 0xfe, 0x3b, 0x6a, 0x4f, 0xb1, 0x13, 0x7b, 0x76, 0x3f,
-	0x72, 0xd9, 0x4b, 0x3d, 0x2d, 0xaf, 0xf5, 0x59, 0xe5, 0xfc, 0x2b, 0x9f, 0xa9, 0x85, 0x06, 0x5e,
-	0x00, 0x65, 0x5e, 0x8d, 0x93, 0x4f, 0xc3, 0xdc, 0x63, 0xe7, 0xcb, 0xe2, 0x9e, 0x62, 0x0b, 0x73,
-	0xd8, 0x02, 0xa5, 0x30, 0x0a, 0xbc, 0xe4, 0xf2, 0x7a, 0x76, 0xf6, 0x99, 0xf2, 0xab, 0x64, 0x4f,
-	0xb1, 0xb9, 0x2d, 0x3c, 0xcf, 0x3e, 0x63, 0xd9, 0x3b, 0x28, 0x7d, 0xa1, 0x6a, 0xb3, 0x32, 0x49,
-	0x92, 0x9a, 0xc2, 0xf3, 0xa0, 0x72, 0x9b, 0x97, 0x1a, 0x7e, 0x97, 0x60, 0xdf, 0x83, 0x92, 0x28,
-	0x5f, 0x84, 0xd8, 0xb9, 0x84, 0x2d, 0xbc, 0x0a, 0x4e, 0xd0, 0x20, 0x1c, 0xa6, 0x49, 0xcd, 0xef,
-	0x1b, 0x1b, 0xad, 0x9a, 0xac, 0x5d, 0x94, 0xf4, 0x7b, 0x8a, 0x9d, 0xd3, 0xc1, 0x1b, 0xe0, 0xd4,
-	0x41, 0x2e, 0xf4, 0x70, 0x7a, 0x3d, 0xc9, 0x71, 0x5e, 0x9c, 0x11, 0x7b, 0x8a, 0x3d, 0xa7, 0xb6,
-	0xc0, 0x34, 0x4b, 0xac, 0xde, 0xd1, 0x43, 0x5d, 0x79, 0xf0, 0x50, 0x57, 0x1e, 0x3f, 0xd4, 0xd5,
-	0xef, 0x26, 0xba, 0xfa, 0xf3, 0x44, 0x57, 0xef, 0x4f, 0x74, 0xf5, 0x68, 0xa2, 0xab, 0x7f, 0x4d,
-	0x74, 0xf5, 0xef, 0x89, 0xae, 0x3c, 0x9e, 0xe8, 0xea, 0xdd, 0x47, 0xba, 0x72, 0xf4, 0x48, 0x57,
-	0x1e, 0x3c, 0xd2, 0x95, 0x6f, 0xcc, 0xd5, 0xe2, 0xb5, 0x53, 0xe1, 0x29, 0xb4, 0xfb, 0x6f, 0x00,
-	0x00, 0x00, 0xff, 0xff, 0xe2, 0x6b, 0x8d, 0x42, 0x6c, 0x12, 0x00, 0x00,
+	// 1385 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xec, 0x97, 0xcb, 0x6f, 0x13, 0x47,
+	0x1c, 0xc7, 0xbd, 0x7e, 0xc5, 0x1e, 0x43, 0x0a, 0x93, 0x28, 0x6c, 0x53, 0xb4, 0x6b, 0x59, 0x2a,
+	0xb8, 0x52, 0x6b, 0xab, 0x0e, 0x85, 0x42, 0x1f, 0x2a, 0x0b, 0x45, 0x46, 0xa5, 0x15, 0x2c, 0x51,
+	0x0f, 0xdc, 0x26, 0xf6, 0xc4, 0xde, 0x66, 0x5f, 0x99, 0x19, 0x53, 0x72, 0xeb, 0xa5, 0x52, 0x0f,
+	0xad, 0xc4, 0x5f, 0x51, 0xf5, 0x80, 0xaa, 0x9e, 0x7b, 0xec, 0x89, 0x23, 0x47, 0x14, 0xa9, 0x4b,
+	0x31, 0x97, 0x36, 0x27, 0xfe, 0x84, 0x6a, 0x66, 0x76, 0xd7, 0xb3, 0xb1, 0x03, 0x31, 0xbd, 0x10,
+	0xa9, 0x17, 0x7b, 0x1e, 0xbf, 0xef, 0xce, 0xcc, 0xe7, 0xf7, 0x98, 0x5d, 0x70, 0x36, 0xdc, 0x1a,
+	0xb4, 0xb7, 0x47, 0x98, 0x38, 0x98, 0x88, 0xff, 0x1d, 0x82, 0xfc, 0x01, 0x56, 0x9a, 0xad, 0x90,
+	0x04, 0x2c, 0x80, 0x60, 0x32, 0xb2, 0xda, 0x19, 0x38, 0x6c, 0x38, 0xda, 0x68, 0xf5, 0x02, 0xaf,
+	0x3d, 0x08, 0x06, 0x41, 0x7b, 0x10, 0x04, 0x03, 0x17, 0xa3, 0xd0, 0xa1, 0x71, 0xb3, 0x4d, 0xc2,
+	0x5e, 0x9b, 0x32, 0xc4, 0x46, 0x54, 0xea, 0x57, 0x97, 0xb9, 0xa1, 0x68, 0x0a, 0x49, 0x3c, 0x6a,
+	0xc6, 0xe6, 0xa2, 0xb7, 0x31, 0xda, 0x6c, 0x33, 0xc7, 0xc3, 0x94, 0x21, 0x2f, 0x8c, 0x0d, 0xde,
+	0xe2, 0xfb, 0x73, 0x83, 0x81, 0x54, 0x26, 0x8d, 0x78, 0xf2, 0xcd, 0xcc, 0x24, 0xdd, 0xc2, 0xac,
+	0x37, 0x8c, 0xa7, 0xea, 0xf1, 0xd4, 0xb6, 0xeb, 0x05, 0x7d, 0xec, 0x8a, 0xbd, 0x50, 0xf9, 0x1b,
+	0x5b, 0x2c, 0x71, 0x8b, 0x70, 0x44, 0x87, 0xe2, 0x27, 0x1e, 0xbc, 0xf2, 0x52, 0x1c, 0x1b, 0x88,
+	0xe2, 0x76, 0x1f, 0x6f, 0x3a, 0xbe, 0xc3, 0x9c, 0xc0, 0xa7, 0x6a, 0x3b, 0x7e, 0xc8, 0xf9, 0xc3,
+	0x3d, 0x64, 0x3f, 0xe2, 0xc6, 0x6f, 0x05, 0x50, 0xbb, 0x11, 0x6c, 0x39, 0x36, 0xde, 0x1e, 0x61,
+	0xca, 0xe0, 0x32, 0x28, 0x09, 0x1b, 0x5d, 0xab, 0x6b, 0xcd, 0xaa, 0x2d, 0x3b, 0x7c, 0xd4, 0x75,
+	0x3c, 0x87, 0xe9, 0xf9, 0xba, 0xd6, 0x3c, 0x6e, 0xcb, 0x0e, 0x84, 0xa0, 0x48, 0x19, 0x0e, 0xf5,
+	0x42, 0x5d, 0x6b, 0x16, 0x6c, 0xd1, 0x86, 0xab, 0xa0, 0xe2, 0xf8, 0x0c, 0x93, 0xbb, 0xc8, 0xd5,
+	0xab, 0x62, 0x3c, 0xed, 0xc3, 0x4f, 0xc1, 0x02, 0x65, 0x88, 0xb0, 0x75, 0xaa, 0x17, 0xeb, 0x5a,
+	0xb3, 0xd6, 0x59, 0x6d, 0x49, 0x57, 0xb4, 0x12, 0x57, 0xb4, 0xd6, 0x13, 0x57, 0x58, 0x95, 0x87,
+	0x91, 0x99, 0xbb, 0xff, 0xc4, 0xd4, 0xec, 0x44, 0x04, 0x2f, 0x81, 0x12, 0xf6, 0xfb, 0xeb, 0x54,
+	0x2f, 0xcd, 0xa1, 0x96, 0x12, 0xf8, 0x3e, 0xa8, 0xf6, 0x1d, 0x82, 0x7b, 0x9c, 0x99, 0x5e, 0xae,
+	0x6b, 0xcd, 0xc5, 0xce, 0x52, 0x2b, 0x75, 0xed, 0xd5, 0x64, 0xca, 0x9e, 0x58, 0xf1, 0xe3, 0x85,
+	0x88, 0x0d, 0xf5, 0x05, 0x41, 0x42, 0xb4, 0x61, 0x03, 0x94, 0xe9, 0x10, 0x91, 0x3e, 0xd5, 0x2b,
+	0xf5, 0x42, 0xb3, 0x6a, 0x81, 0xbd, 0xc8, 0x8c, 0x47, 0xec, 0xf8, 0x9f, 0x23, 0xe8, 0x21, 0x17,
+	0xfb, 0x7d, 0x44, 0x74, 0x20, 0xb4, 0x69, 0x9f, 0xcf, 0xf1, 0x68, 0xbb, 0x13, 0xf8, 0x58, 0xaf,
+	0xc9, 0xb9, 0xa4, 0x0f, 0xcf, 0x80, 0xc5, 0xc4, 0xce, 0xe6, 0x1e, 0xa2, 0xfa, 0xb1, 0xba, 0xd6,
+	0xac, 0xd8, 0xfb, 0x46, 0x1b, 0xff, 0x68, 0x00, 0x72, 0x97, 0x5d, 0xf7, 0x29, 0x43, 0x3e, 0x7b,
+	0x15, 0xcf, 0x7d, 0x0c, 0xca, 0x7c, 0xd9, 0x75, 0xaa, 0x17, 0xe6, 0x40, 0x19, 0x6b, 0xb2, 0x2c,
+	0x8b, 0x73, 0xb1, 0x2c, 0xcd, 0x64, 0x59, 0x3e, 0x88, 0x65, 0xe3, 0x49, 0x11, 0x1c, 0x93, 0xe1,
+	0x49, 0xc3, 0xc0, 0xa7, 0x98, 0x8b, 0x6e, 0x8b, 0x14, 0x97, 0xc7, 0x8c, 0x45, 0x62, 0xc4, 0x8e,
+	0x67, 0xe0, 0x67, 0xa0, 0x78, 0x15, 0x31, 0x24, 0x8e, 0x5c, 0xeb, 0x2c, 0xb7, 0x94, 0xa0, 0xe7,
+	0xcf, 0xe2, 0x73, 0xd6, 0x0a, 0x3f, 0xd5, 0x5e, 0x64, 0x2e, 0xf6, 0x11, 0x43, 0xef, 0x06, 0x9e,
+	0xc3, 0xb0, 0x17, 0xb2, 0x1d, 0x5b, 0x28, 0xe1, 0x07, 0xa0, 0xfa, 0x39, 0x21, 0x01, 0x59, 0xdf,
+	0x09, 0xb1, 0x40, 0x54, 0xb5, 0x4e, 0xed, 0x45, 0xe6, 0x12, 0x4e, 0x06, 0x15, 0xc5, 0xc4, 0x12,
+	0xbe, 0x03, 0x4a, 0xa2, 0x23, 0xa0, 0x54, 0xad, 0xa5, 0xbd, 0xc8, 0x7c, 0x43, 0x48, 0x14, 0x73,
+	0x69, 0x91, 0x65, 0x58, 0x3a, 0x14, 0xc3, 0xd4, 0x95, 0x65, 0xd5, 0x95, 0x3a, 0x58, 0xb8, 0x8b,
+	0x09, 0xe5, 0x8f, 0x59, 0x10, 0xe3, 0x49, 0x17, 0x5e, 0x06, 0x80, 0x83, 0x71, 0x28, 0x73, 0x7a,
+	0x3c, 0x5e, 0x39, 0x8c, 0xe3, 0x2d, 0x59, 0x8e, 0x6c, 0x4c, 0x47, 0x2e, 0xb3, 0x60, 0x4c, 0x41,
+	0x31, 0xb4, 0x95, 0x36, 0x7c, 0xa0, 0x81, 0x85, 0x2e, 0x46, 0x7d, 0x4c, 0xa8, 0x5e, 0xad, 0x17,
+	0x9a, 0xb5, 0xce, 0xdb, 0x2d, 0xb5, 0xf6, 0xdc, 0x24, 0x81, 0x87, 0xd9, 0x10, 0x8f, 0x68, 0xe2,
+	0x20, 0x69, 0x6d, 0x6d, 0xed, 0x46, 0xe6, 0x86, 0x5a, 0xb1, 0x09, 0xda, 0x44, 0x3e, 0x6a, 0xbb,
+	0xc1, 0x96, 0xd3, 0x9e, 0xbb, 0xde, 0x1d, 0xb8, 0xce, 0x5e, 0x64, 0x6a, 0xef, 0xd9, 0xc9, 0x16,
+	0x61, 0x07, 0x54, 0xbe, 0x45, 0xc4, 0x77, 0xfc, 0x01, 0xd5, 0x81, 0x88, 0xa9, 0x95, 0xbd, 0xc8,
+	0x84, 0xc9, 0x98, 0xe2, 0x85, 0xd4, 0xae, 0xf1, 0x83, 0x06, 0x4e, 0x5e, 0x41, 0xbd, 0x21, 0xee,
+	0xdf, 0x08, 0x06, 0x69, 0x98, 0x5d, 0x00, 0x0b, 0x44, 0xe6, 0x95, 0x88, 0xb3, 0x5a, 0xe7, 0xd4,
+	0xfe, 0x28, 0x8a, 0xd3, 0xce, 0x2a, 0x72, 0x84, 0x76, 0x62, 0x0d, 0x2f, 0x81, 0x0a, 0x89, 0x1f,
+	0x12, 0xc7, 0x9f, 0x3e, 0xad, 0x94, 0xf3, 0xb1, 0x34, 0xb5, 0x6f, 0xfc, 0xa9, 0x81, 0x93, 0xdc,
+	0xe0, 0x36, 0x47, 0x43, 0x95, 0xbc, 0xf6, 0x10, 0xeb, 0x0d, 0x75, 0x8d, 0x9f, 0xc8, 0x96, 0x1d,
+	0xb5, 0x96, 0xe6, 0xff, 0x53, 0x2d, 0x2d, 0xcc, 0x5f, 0x4b, 0x93, 0x64, 0x2e, 0xce, 0x4c, 0xe6,
+	0xd2, 0x81, 0xc9, 0xfc, 0x63, 0x01, 0x40, 0xf5, 0x7c, 0x73, 0xa4, 0xf4, 0xb5, 0x34, 0xa5, 0x0b,
+	0x62, 0xb7, 0x69, 0xa6, 0xc8, 0x67, 0x5d, 0xef, 0x63, 0x9f, 0x39, 0x9b, 0x0e, 0x26, 0x2f, 0x49,
+	0x6c, 0x25, 0x5b, 0x0a, 0xd9, 0x6c, 0x51, 0x43, 0xbd, 0xf8, 0xfa, 0x87, 0x7a, 0x36, 0xb9, 0x4b,
+	0xaf, 0x90, 0xdc, 0x8d, 0xe7, 0x79, 0xb0, 0xc2, 0xdd, 0x71, 0x03, 0x6d, 0x60, 0xf7, 0x2b, 0xe4,
+	0xcd, 0xe9, 0x92, 0x33, 0x8a, 0x4b, 0xaa, 0x16, 0xfc, 0x1f, 0xf9, 0x21, 0x90, 0xff, 0xac, 0x81,
+	0x4a, 0x72, 0x05, 0xc1, 0x16, 0x00, 0x52, 0x26, 0x6e, 0x19, 0x09, 0x7a, 0x91, 0x8b, 0x49, 0x3a,
+	0x6a, 0x2b, 0x16, 0xf0, 0x1b, 0x50, 0x96, 0xbd, 0x38, 0x0b, 0x4e, 0x29, 0x59, 0xc0, 0x08, 0x46,
+	0xde, 0xe5, 0x3e, 0x0a, 0x19, 0x26, 0xd6, 0x45, 0xbe, 0x8b, 0xdd, 0xc8, 0x3c, 0xfb, 0x22, 0x44,
+	0xe2, 0x05, 0x54, 0xea, 0xb8, 0x73, 0xe5, 0x9a, 0x76, 0xbc, 0x42, 0xe3, 0x27, 0x0d, 0x9c, 0xe0,
+	0x1b, 0xe5, 0x68, 0xd2, 0xa8, 0xb8, 0xaa, 0xd4, 0x36, 0x59, 0x15, 0x1b, 0xad, 0x2c, 0xd6, 0x19,
+	0x28, 0x45, 0x95, 0xd3, 0x26, 0x55, 0x0e, 0xae, 0x65, 0x30, 0xe6, 0x67, 0x61, 0x94, 0x85, 0x51,
+	0x05, 0xf7, 0x7b, 0x1e, 0xc0, 0xeb, 0x7e, 0x1f, 0xdf, 0xe3, 0xc1, 0x37, 0x89, 0xd3, 0xd1, 0xd4,
+	0x8e, 0x4e, 0x4f, 0xa0, 0x4c, 0xdb, 0x5b, 0x1f, 0xed, 0x46, 0xe6, 0x85, 0x17, 0x51, 0x79, 0x81,
+	0x58, 0x39, 0x82, 0x1a, 0xb8, 0xf9, 0xd7, 0x3e, 0x70, 0x1b, 0xbf, 0xe6, 0xc1, 0xe2, 0xd7, 0x81,
+	0x3b, 0xf2, 0x70, 0x0a, 0xce, 0x9b, 0x02, 0xa7, 0x4f, 0xc0, 0x65, 0x6d, 0xad, 0x0b, 0xbb, 0x91,
+	0xb9, 0x76, 0x28, 0x68, 0x59, 0xe1, 0xd1, 0x05, 0xf6, 0x20, 0x0f, 0x96, 0xd7, 0x83, 0xf0, 0x8b,
+	0xdb, 0xe2, 0xeb, 0x4e, 0xa9, 0x8b, 0x78, 0x0a, 0xdb, 0xf2, 0x04, 0x1b, 0x57, 0x7c, 0x89, 0x18,
+	0x71, 0xee, 0x59, 0x6b, 0xbb, 0x91, 0xd9, 0x3e, 0x14, 0xb2, 0x89, 0xe8, 0xe8, 0xe2, 0xfa, 0x23,
+	0x0f, 0x56, 0x6e, 0x8d, 0x90, 0xcf, 0x1c, 0x17, 0x4b, 0x64, 0x29, 0xb0, 0x9d, 0x29, 0x60, 0xc6,
+	0x04, 0x58, 0x56, 0x13, 0xa3, 0xfb, 0x64, 0x37, 0x32, 0x2f, 0x1e, 0x0a, 0xdd, 0x2c, 0xf9, 0xd1,
+	0x85, 0xf8, 0x7d, 0x11, 0x1c, 0xbf, 0xc5, 0x9f, 0x92, 0xb2, 0xfb, 0x10, 0x94, 0x29, 0x5f, 0x88,
+	0xa6, 0xe4, 0xf6, 0xbd, 0x48, 0x66, 0xdf, 0xa3, 0xba, 0x39, 0x3b, 0xb6, 0xe7, 0x9f, 0x77, 0x2e,
+	0xbf, 0xd4, 0x93, 0xf2, 0xda, 0xd8, 0xaf, 0x9c, 0xbe, 0xf2, 0xb9, 0x5a, 0x6a, 0xe0, 0x79, 0x50,
+	0x12, 0xd5, 0x58, 0x2f, 0x4c, 0x2f, 0x3b, 0x5d, 0x16, 0xbb, 0x39, 0x5b, 0x9a, 0xc3, 0x0e, 0x28,
+	0x86, 0x24, 0xf0, 0xe2, 0x6f, 0xfb, 0xd3, 0xfb, 0xd7, 0x54, 0xaf, 0x92, 0x6e, 0xce, 0x16, 0xb6,
+	0xf0, 0x1c, 0x7f, 0x8d, 0xe5, 0x77, 0x50, 0x72, 0xa1, 0x1e, 0xf8, 0xb6, 0xdc, 0xcd, 0xd9, 0x89,
+	0x29, 0x3c, 0x07, 0xca, 0x77, 0x45, 0xa9, 0x11, 0x9f, 0x42, 0xfc, 0x7d, 0x50, 0x11, 0x65, 0x8b,
+	0x10, 0x3f, 0x97, 0xb4, 0x85, 0xd7, 0xc0, 0x31, 0x16, 0x84, 0x5b, 0x49, 0x52, 0x8b, 0xcf, 0xa5,
+	0x5a, 0xa7, 0xae, 0x6a, 0x67, 0x25, 0x7d, 0x37, 0x67, 0x67, 0x74, 0xf0, 0x26, 0x38, 0xb1, 0x9d,
+	0x09, 0x3d, 0x9c, 0x7c, 0x5d, 0x65, 0x38, 0xcf, 0xce, 0x88, 0x6e, 0xce, 0x9e, 0x52, 0x5b, 0x60,
+	0x92, 0x25, 0x56, 0xff, 0xd1, 0x53, 0x23, 0xf7, 0xf8, 0xa9, 0x91, 0x7b, 0xfe, 0xd4, 0xd0, 0xbe,
+	0x1b, 0x1b, 0xda, 0x2f, 0x63, 0x43, 0x7b, 0x38, 0x36, 0xb4, 0x47, 0x63, 0x43, 0xfb, 0x6b, 0x6c,
+	0x68, 0x7f, 0x8f, 0x8d, 0xdc, 0xf3, 0xb1, 0xa1, 0xdd, 0x7f, 0x66, 0xe4, 0x1e, 0x3d, 0x33, 0x72,
+	0x8f, 0x9f, 0x19, 0xb9, 0x3b, 0xad, 0xf9, 0xe2, 0x75, 0xa3, 0x2c, 0x52, 0x68, 0xed, 0xdf, 0x01,
+	0x00, 0x46, 0xdb, 0x71, 0xbc, 0x8b, 0x13, 0x00, 0x00,
 }
 
 func (this *LokiRequest) Equal(that interface{}) bool {
@@ -1101,6 +1197,15 @@ func (this *LokiRequest) Equal(that interface{}) bool {
 			return false
 		}
 	}
+	if this.Calendar != that1.Calendar {
+		return false
+	}
+	if this.TimeZone != that1.TimeZone {
+		return false
+	}
+	if this.CalendarRanges != that1.CalendarRanges {
+		return false
+	}
 	return true
 }
 func (this *LokiInstantRequest) Equal(that interface{}) bool {
@@ -1198,6 +1303,41 @@ func (this *LokiResponse) Equal(that interface{}) bool {
 			return false
 		}
 	}
+	if len(this.Warnings) != len(that1.Warnings) {
+		return false
+	}
+	for i := range this.Warnings {
+		if this.Warnings[i] != that1.Warnings[i] {
+			return false
+		}
+	}
+	return true
+}
+func (this *CachedLogResponse) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*CachedLogResponse)
+	if !ok {
+		that2, ok := that.(CachedLogResponse)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if !this.Request.Equal(&that1.Request) {
+		return false
+	}
+	if !this.Response.Equal(&that1.Response) {
+		return false
+	}
 	return true
 }
 func (this *LokiSeriesRequest) Equal(that interface{}) bool {
@@ -1767,7 +1907,7 @@ func (this *LokiRequest) GoString() string {
 	if this == nil {
 		return "nil"
 	}
-	s := make([]string, 0, 13)
+	s := make([]string, 0, 16)
 	s = append(s, "&queryrange.LokiRequest{")
 	s = append(s, "Query: "+fmt.Sprintf("%#v", this.Query)+",\n")
 	s = append(s, "Limit: "+fmt.Sprintf("%#v", this.Limit)+",\n")
@@ -1778,6 +1918,9 @@ func (this *LokiRequest) GoString() string {
 	s = append(s, "Direction: "+fmt.Sprintf("%#v", this.Direction)+",\n")
 	s = append(s, "Path: "+fmt.Sprintf("%#v", this.Path)+",\n")
 	s = append(s, "Shards: "+fmt.Sprintf("%#v", this.Shards)+",\n")
+	s = append(s, "Calendar: "+fmt.Sprintf("%#v", this.Calendar)+",\n")
+	s = append(s, "TimeZone: "+fmt.Sprintf("%#v", this.TimeZone)+",\n")
+	s = append(s, "CalendarRanges: "+fmt.Sprintf("%#v", this.CalendarRanges)+",\n")
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
@@ -1800,7 +1943,7 @@ func (this *LokiResponse) GoString() string {
 	if this == nil {
 		return "nil"
 	}
-	s := make([]string, 0, 13)
+	s := make([]string, 0, 14)
 	s = append(s, "&queryrange.LokiResponse{")
 	s = append(s, "Status: "+fmt.Sprintf("%#v", this.Status)+",\n")
 	s = append(s, "Data: "+strings.Replace(this.Data.GoString(), `&`, ``, 1)+",\n")
@@ -1811,6 +1954,18 @@ func (this *LokiResponse) GoString() string {
 	s = append(s, "Version: "+fmt.Sprintf("%#v", this.Version)+",\n")
 	s = append(s, "Statistics: "+strings.Replace(this.Statistics.GoString(), `&`, ``, 1)+",\n")
 	s = append(s, "Headers: "+fmt.Sprintf("%#v", this.Headers)+",\n")
+	s = append(s, "Warnings: "+fmt.Sprintf("%#v", this.Warnings)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *CachedLogResponse) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 6)
+	s = append(s, "&queryrange.CachedLogResponse{")
+	s = append(s, "Request: "+strings.Replace(this.Request.GoString(), `&`, ``, 1)+",\n")
+	s = append(s, "Response: "+strings.Replace(this.Response.GoString(), `&`, ``, 1)+",\n")
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
@@ -2034,6 +2189,30 @@ func (m *LokiRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.CalendarRanges {
+		i--
+		if m.CalendarRanges {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x60
+	}
+	if len(m.TimeZone) > 0 {
+		i -= len(m.TimeZone)
+		copy(dAtA[i:], m.TimeZone)
+		i = encodeVarintQueryrange(dAtA, i, uint64(len(m.TimeZone)))
+		i--
+		dAtA[i] = 0x5a
+	}
+	if len(m.Calendar) > 0 {
+		i -= len(m.Calendar)
+		copy(dAtA[i:], m.Calendar)
+		i = encodeVarintQueryrange(dAtA, i, uint64(len(m.Calendar)))
+		i--
+		dAtA[i] = 0x52
+	}
 	if m.Interval != 0 {
 		i = encodeVarintQueryrange(dAtA, i, uint64(m.Interval))
 		i--
@@ -2180,6 +2359,15 @@ func (m *LokiResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.Warnings) > 0 {
+		for iNdEx := len(m.Warnings) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Warnings[iNdEx])
+			copy(dAtA[i:], m.Warnings[iNdEx])
+			i = encodeVarintQueryrange(dAtA, i, uint64(len(m.Warnings[iNdEx])))
+			i--
+			dAtA[i] = 0x52
+		}
+	}
 	if len(m.Headers) > 0 {
 		for iNdEx := len(m.Headers) - 1; iNdEx >= 0; iNdEx-- {
 			{
@@ -2253,6 +2441,49 @@ func (m *LokiResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *CachedLogResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *CachedLogResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *CachedLogResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	{
+		size, err := m.Response.MarshalToSizedBuffer(dAtA[:i])
+		if err != nil {
+			return 0, err
+		}
+		i -= size
+		i = encodeVarintQueryrange(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0x12
+	{
+		size, err := m.Request.MarshalToSizedBuffer(dAtA[:i])
+		if err != nil {
+			return 0, err
+		}
+		i -= size
+		i = encodeVarintQueryrange(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0xa
+	return len(dAtA) - i, nil
+}
+
 func (m *LokiSeriesRequest) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
@@ -2289,20 +2520,20 @@ func (m *LokiSeriesRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i--
 		dAtA[i] = 0x22
 	}
-	n6, err6 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.EndTs, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.EndTs):])
-	if err6 != nil {
-		return 0, err6
+	n8, err8 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.EndTs, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.EndTs):])
+	if err8 != nil {
+		return 0, err8
 	}
-	i -= n6
-	i = encodeVarintQueryrange(dAtA, i, uint64(n6))
+	i -= n8
+	i = encodeVarintQueryrange(dAtA, i, uint64(n8))
 	i--
 	dAtA[i] = 0x1a
-	n7, err7 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.StartTs, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.StartTs):])
-	if err7 != nil {
-		return 0, err7
+	n9, err9 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.StartTs, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.StartTs):])
+	if err9 != nil {
+		return 0, err9
 	}
-	i -= n7
-	i = encodeVarintQueryrange(dAtA, i, uint64(n7))
+	i -= n9
+	i = encodeVarintQueryrange(dAtA, i, uint64(n9))
 	i--
 	dAtA[i] = 0x12
 	if len(m.Match) > 0 {
@@ -2982,6 +3213,17 @@ func (m *LokiRequest) Size() (n int) {
 	if m.Interval != 0 {
 		n += 1 + sovQueryrange(uint64(m.Interval))
 	}
+	l = len(m.Calendar)
+	if l > 0 {
+		n += 1 + l + sovQueryrange(uint64(l))
+	}
+	l = len(m.TimeZone)
+	if l > 0 {
+		n += 1 + l + sovQueryrange(uint64(l))
+	}
+	if m.CalendarRanges {
+		n += 2
+	}
 	return n
 }
 
@@ -3053,6 +3295,25 @@ func (m *LokiResponse) Size() (n int) {
 			n += 1 + l + sovQueryrange(uint64(l))
 		}
 	}
+	if len(m.Warnings) > 0 {
+		for _, s := range m.Warnings {
+			l = len(s)
+			n += 1 + l + sovQueryrange(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *CachedLogResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = m.Request.Size()
+	n += 1 + l + sovQueryrange(uint64(l))
+	l = m.Response.Size()
+	n += 1 + l + sovQueryrange(uint64(l))
 	return n
 }
 
@@ -3384,6 +3645,9 @@ func (this *LokiRequest) String() string {
 		`Path:` + fmt.Sprintf("%v", this.Path) + `,`,
 		`Shards:` + fmt.Sprintf("%v", this.Shards) + `,`,
 		`Interval:` + fmt.Sprintf("%v", this.Interval) + `,`,
+		`Calendar:` + fmt.Sprintf("%v", this.Calendar) + `,`,
+		`TimeZone:` + fmt.Sprintf("%v", this.TimeZone) + `,`,
+		`CalendarRanges:` + fmt.Sprintf("%v", this.CalendarRanges) + `,`,
 		`}`,
 	}, "")
 	return s
@@ -3417,6 +3681,18 @@ func (this *LokiResponse) String() string {
 		`Version:` + fmt.Sprintf("%v", this.Version) + `,`,
 		`Statistics:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.Statistics), "Result", "stats.Result", 1), `&`, ``, 1) + `,`,
 		`Headers:` + fmt.Sprintf("%v", this.Headers) + `,`,
+		`Warnings:` + fmt.Sprintf("%v", this.Warnings) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *CachedLogResponse) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&CachedLogResponse{`,
+		`Request:` + strings.Replace(strings.Replace(this.Request.String(), "LokiRequest", "LokiRequest", 1), `&`, ``, 1) + `,`,
+		`Response:` + strings.Replace(strings.Replace(this.Response.String(), "LokiResponse", "LokiResponse", 1), `&`, ``, 1) + `,`,
 		`}`,
 	}, "")
 	return s
@@ -3899,6 +4175,90 @@ func (m *LokiRequest) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Calendar", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQueryrange
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Calendar = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 11:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TimeZone", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQueryrange
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TimeZone = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 12:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CalendarRanges", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQueryrange
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.CalendarRanges = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipQueryrange(dAtA[iNdEx:])
@@ -4425,6 +4785,157 @@ func (m *LokiResponse) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Warnings", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQueryrange
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Warnings = append(m.Warnings, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipQueryrange(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *CachedLogResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowQueryrange
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: CachedLogResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: CachedLogResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Request", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQueryrange
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.Request.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Response", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQueryrange
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.Response.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipQueryrange(dAtA[iNdEx:])
@@ -111,9 +111,12 @@ func (prometheusCodec) MergeResponse(responses ...Response) (Response, error) {
 	// we need to pass on all the headers for results cache gen numbers.
 	var resultsCacheGenNumberHeaderValues []string
 
+	var warnings []string
+
 	for _, res := range responses {
 		promResponses = append(promResponses, res.(*PrometheusResponse))
 		resultsCacheGenNumberHeaderValues = append(resultsCacheGenNumberHeaderValues, getHeaderValuesWithName(res, ResultsCacheGenNumberHeaderName)...)
+		warnings = append(warnings, res.(*PrometheusResponse).Warnings...)
 	}
 
 	// Merge the responses.
@@ -125,6 +128,7 @@ func (prometheusCodec) MergeResponse(responses ...Response) (Response, error) {
 			ResultType: model.ValMatrix.String(),
 			Result:     matrixMerge(promResponses),
 		},
+		Warnings: MergeWarnings(warnings),
 	}
 
 	if len(resultsCacheGenNumberHeaderValues) != 0 {
@@ -137,6 +141,24 @@ func (prometheusCodec) MergeResponse(responses ...Response) (Response, error) {
 	return &response, nil
 }
 
+// MergeWarnings returns the sorted, deduplicated set of warnings.
+func MergeWarnings(warnings []string) []string {
+	if len(warnings) == 0 {
+		return nil
+	}
+	uniq := make(map[string]struct{}, len(warnings))
+	res := make([]string, 0, len(warnings))
+	for _, w := range warnings {
+		if _, ok := uniq[w]; ok {
+			continue
+		}
+		uniq[w] = struct{}{}
+		res = append(res, w)
+	}
+	sort.Strings(res)
+	return res
+}
+
 func (prometheusCodec) DecodeRequest(_ context.Context, r *http.Request, forwardHeaders []string) (Request, error) {
 	var result PrometheusRequest
 	var err error
@@ -9,10 +9,10 @@ import (
 	proto "github.com/gogo/protobuf/proto"
 	github_com_gogo_protobuf_types "github.com/gogo/protobuf/types"
 	types "github.com/gogo/protobuf/types"
-	_ "github.com/golang/protobuf/ptypes/duration"
 	github_com_grafana_loki_pkg_logproto "github.com/grafana/loki/pkg/logproto"
 	logproto "github.com/grafana/loki/pkg/logproto"
 	definitions "github.com/grafana/loki/pkg/querier/queryrange/queryrangebase/definitions"
+	_ "google.golang.org/protobuf/types/known/durationpb"
 	io "io"
 	math "math"
 	math_bits "math/bits"
@@ -138,6 +138,7 @@ type PrometheusResponse struct {
 	ErrorType string                                  `protobuf:"bytes,3,opt,name=ErrorType,proto3" json:"errorType,omitempty"`
 	Error     string                                  `protobuf:"bytes,4,opt,name=Error,proto3" json:"error,omitempty"`
 	Headers   []*definitions.PrometheusResponseHeader `protobuf:"bytes,5,rep,name=Headers,proto3" json:"-"`
+	Warnings  []string                                `protobuf:"bytes,6,rep,name=Warnings,proto3" json:"warnings,omitempty"`
 }
 
 func (m *PrometheusResponse) Reset()      { *m = PrometheusResponse{} }
@@ -207,6 +208,13 @@ func (m *PrometheusResponse) GetHeaders() []*definitions.PrometheusResponseHeade
 	return nil
 }
 
+func (m *PrometheusResponse) GetWarnings() []string {
+	if m != nil {
+		return m.Warnings
+	}
+	return nil
+}
+
 type PrometheusData struct {
 	ResultType string         `protobuf:"bytes,1,opt,name=ResultType,proto3" json:"resultType"`
 	Result     []SampleStream `protobuf:"bytes,2,rep,name=Result,proto3" json:"result"`
@@ -435,59 +443,60 @@ func init() {
 }
 
 var fileDescriptor_4cc6a0c1d6b614c4 = []byte{
-	// 821 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x54, 0x4f, 0x6f, 0xdc, 0x44,
-	0x14, 0xdf, 0xc9, 0xfe, 0x9f, 0x54, 0xdb, 0x32, 0x8d, 0x8a, 0xd3, 0x22, 0x7b, 0xb5, 0x02, 0x29,
-	0x48, 0xe0, 0x15, 0x41, 0x70, 0x2b, 0x22, 0x4e, 0x82, 0x68, 0x55, 0x89, 0x6a, 0xc2, 0x89, 0x0b,
-	0x9a, 0x5d, 0xbf, 0x38, 0x56, 0xfc, 0xaf, 0x33, 0xe3, 0x8a, 0xbd, 0x71, 0xe2, 0xcc, 0x91, 0x8f,
-	0xc0, 0x01, 0xf1, 0x39, 0x22, 0x4e, 0x39, 0x56, 0x1c, 0x0c, 0xd9, 0x5c, 0x90, 0x4f, 0xfd, 0x08,
-	0x68, 0x66, 0xec, 0x8d, 0x77, 0xab, 0x40, 0x2f, 0xbb, 0xef, 0xcf, 0xef, 0xbd, 0x79, 0xbf, 0xdf,
-	0x8c, 0x1f, 0xfe, 0x3c, 0x3b, 0x0f, 0xa6, 0x2f, 0x72, 0xe0, 0x21, 0x70, 0xfd, 0xbf, 0xe0, 0x2c,
-	0x09, 0xa0, 0x61, 0xce, 0x98, 0x68, 0xba, 0x6e, 0xc6, 0x53, 0x99, 0x92, 0xd1, 0x3a, 0xe0, 0xe1,
-	0x4e, 0x90, 0x06, 0xa9, 0x4e, 0x4d, 0x95, 0x65, 0x50, 0x0f, 0x77, 0x83, 0x34, 0x0d, 0x22, 0x98,
-	0x6a, 0x6f, 0x96, 0x9f, 0x4e, 0x59, 0xb2, 0xa8, 0x52, 0xf6, 0x66, 0xca, 0xcf, 0x39, 0x93, 0x61,
-	0x9a, 0x54, 0xf9, 0x47, 0x6a, 0xb0, 0x28, 0x0d, 0x4c, 0xcf, 0xda, 0xa8, 0x92, 0x87, 0x6f, 0x37,
-	0xb5, 0x0f, 0xa7, 0x61, 0x12, 0xaa, 0xa6, 0xa2, 0x69, 0x9b, 0x26, 0x93, 0x3f, 0xb6, 0xf0, 0x3b,
-	0xcf, 0x79, 0x1a, 0x83, 0x3c, 0x83, 0x5c, 0x50, 0x78, 0x91, 0x83, 0x90, 0x84, 0xe0, 0x4e, 0xc6,
-	0xe4, 0x99, 0x85, 0xc6, 0x68, 0x6f, 0x48, 0xb5, 0x4d, 0x76, 0x70, 0x57, 0x48, 0xc6, 0xa5, 0xb5,
-	0x35, 0x46, 0x7b, 0x6d, 0x6a, 0x1c, 0x72, 0x0f, 0xb7, 0x21, 0xf1, 0xad, 0xb6, 0x8e, 0x29, 0x53,
-	0xd5, 0x0a, 0x09, 0x99, 0xd5, 0xd1, 0x21, 0x6d, 0x93, 0xc7, 0xb8, 0x2f, 0xc3, 0x18, 0xd2, 0x5c,
-	0x5a, 0xdd, 0x31, 0xda, 0xdb, 0xde, 0xdf, 0x75, 0x0d, 0x73, 0xb7, 0x66, 0xee, 0x1e, 0x55, 0xcc,
-	0xbd, 0xc1, 0x45, 0xe1, 0xb4, 0x7e, 0xf9, 0xcb, 0x41, 0xb4, 0xae, 0x51, 0x47, 0x6b, 0x52, 0x56,
-	0x4f, 0xcf, 0x63, 0x1c, 0xf2, 0x04, 0x8f, 0xe6, 0x6c, 0x7e, 0x16, 0x26, 0xc1, 0x37, 0x99, 0xa6,
-	0x64, 0xf5, 0x75, 0xef, 0x47, 0x6e, 0x93, 0xe6, 0xe1, 0x1a, 0xc4, 0xeb, 0xa8, 0xee, 0x74, 0xa3,
-	0x90, 0x1c, 0xe3, 0xfe, 0xd7, 0xc0, 0x7c, 0xe0, 0xc2, 0x1a, 0x8c, 0xdb, 0x7b, 0xdb, 0xfb, 0xef,
-	0xaf, 0xf5, 0x78, 0x43, 0x20, 0x03, 0xf6, 0xba, 0x65, 0xe1, 0xa0, 0x8f, 0x69, 0x5d, 0x3b, 0xf9,
-	0x7d, 0x0b, 0x93, 0x26, 0x56, 0x64, 0x69, 0x22, 0x80, 0x4c, 0x70, 0xef, 0x44, 0x32, 0x99, 0x0b,
-	0xa3, 0xa7, 0x87, 0xcb, 0xc2, 0xe9, 0x09, 0x1d, 0xa1, 0x55, 0x86, 0x3c, 0xc5, 0x9d, 0x23, 0x26,
-	0x99, 0x16, 0x77, 0x7b, 0xdf, 0x76, 0xd7, 0x2f, 0xb1, 0x31, 0x81, 0x42, 0x79, 0x0f, 0x14, 0x8b,
-	0xb2, 0x70, 0x46, 0x3e, 0x93, 0xec, 0xa3, 0x34, 0x0e, 0x25, 0xc4, 0x99, 0x5c, 0x50, 0xdd, 0x83,
-	0x7c, 0x86, 0x87, 0xc7, 0x9c, 0xa7, 0xfc, 0xdb, 0x45, 0x06, 0xfa, 0x66, 0x86, 0xde, 0xbb, 0x65,
-	0xe1, 0xdc, 0x87, 0x3a, 0xd8, 0xa8, 0xb8, 0x41, 0x92, 0x0f, 0x71, 0x57, 0x3b, 0xfa, 0xe6, 0x86,
-	0xde, 0xfd, 0xb2, 0x70, 0xee, 0xea, 0x92, 0x06, 0xdc, 0x20, 0xc8, 0x57, 0x37, 0x7a, 0x75, 0xb5,
-	0x5e, 0x1f, 0xdc, 0xaa, 0x97, 0xd1, 0xe0, 0x16, 0xc1, 0x7e, 0x42, 0x78, 0xb4, 0x4e, 0x8d, 0xb8,
-	0x18, 0x53, 0x10, 0x79, 0x24, 0xf5, 0xf4, 0x46, 0xb0, 0x51, 0x59, 0x38, 0x98, 0xaf, 0xa2, 0xb4,
-	0x81, 0x20, 0x47, 0xb8, 0x67, 0x3c, 0x6b, 0x4b, 0x4f, 0xf2, 0xde, 0xa6, 0x74, 0x27, 0x2c, 0xce,
-	0x22, 0x38, 0x91, 0x1c, 0x58, 0xec, 0x8d, 0x2a, 0xe1, 0x7a, 0xa6, 0x1b, 0xad, 0x6a, 0x27, 0x17,
-	0x08, 0xdf, 0x69, 0x02, 0xc9, 0x4b, 0xdc, 0x8b, 0xd8, 0x0c, 0x22, 0x75, 0x67, 0x6d, 0xfd, 0x60,
-	0x57, 0x5f, 0xdf, 0x33, 0x08, 0xd8, 0x7c, 0xf1, 0x4c, 0x65, 0x9f, 0xb3, 0x90, 0x7b, 0x87, 0xaa,
-	0xe7, 0x9f, 0x85, 0xf3, 0x49, 0x10, 0xca, 0xb3, 0x7c, 0xe6, 0xce, 0xd3, 0x78, 0x1a, 0x70, 0x76,
-	0xca, 0x12, 0x36, 0x8d, 0xd2, 0xf3, 0x70, 0xda, 0xfc, 0x88, 0x5d, 0x5d, 0x77, 0xe0, 0xb3, 0x4c,
-	0x02, 0x57, 0x83, 0xc4, 0x20, 0x79, 0x38, 0xa7, 0xd5, 0x69, 0xe4, 0x4b, 0xdc, 0x17, 0x7a, 0x0e,
-	0x51, 0xf1, 0x79, 0xb0, 0x79, 0xb0, 0x19, 0xf3, 0x86, 0xc9, 0x4b, 0x16, 0xe5, 0x20, 0x68, 0x5d,
-	0x36, 0x49, 0xf0, 0x48, 0xbd, 0x79, 0xf0, 0x57, 0xef, 0x6f, 0x17, 0xb7, 0xcf, 0x61, 0x51, 0x69,
-	0xd9, 0x2f, 0x0b, 0x47, 0xb9, 0x54, 0xfd, 0x90, 0x03, 0xdc, 0x87, 0x1f, 0x24, 0x24, 0xf2, 0xe6,
-	0xb8, 0x0d, 0xf9, 0x8e, 0x75, 0xda, 0xbb, 0x5b, 0x1d, 0x57, 0xc3, 0x69, 0x6d, 0x4c, 0x7e, 0x43,
-	0xb8, 0x67, 0x40, 0xc4, 0xa9, 0x57, 0x84, 0x3a, 0xaa, 0xed, 0x0d, 0xcb, 0xc2, 0x31, 0x81, 0x7a,
-	0x5b, 0xec, 0x9a, 0x6d, 0xa1, 0x37, 0x88, 0x99, 0x04, 0x12, 0xdf, 0xac, 0x8d, 0x31, 0x1e, 0x48,
-	0xce, 0xe6, 0xf0, 0x7d, 0xe8, 0x57, 0x0f, 0xb0, 0x7e, 0x2c, 0x3a, 0xfc, 0xc4, 0x27, 0x5f, 0xe0,
-	0x01, 0xaf, 0x28, 0x55, 0x5b, 0x64, 0xe7, 0x8d, 0x2d, 0x72, 0x90, 0x2c, 0xbc, 0x3b, 0x65, 0xe1,
-	0xac, 0x90, 0x74, 0x65, 0x3d, 0xed, 0x0c, 0xda, 0xf7, 0x3a, 0x9e, 0xb8, 0xbc, 0xb2, 0x5b, 0xaf,
-	0xae, 0xec, 0xd6, 0xeb, 0x2b, 0x1b, 0xfd, 0xb8, 0xb4, 0xd1, 0xaf, 0x4b, 0x1b, 0x5d, 0x2c, 0x6d,
-	0x74, 0xb9, 0xb4, 0xd1, 0xdf, 0x4b, 0x1b, 0xfd, 0xb3, 0xb4, 0x5b, 0xaf, 0x97, 0x36, 0xfa, 0xf9,
-	0xda, 0x6e, 0x5d, 0x5e, 0xdb, 0xad, 0x57, 0xd7, 0x76, 0xeb, 0xbb, 0xc7, 0xff, 0x75, 0xb7, 0xff,
-	0xbb, 0x83, 0x67, 0x3d, 0x3d, 0xe0, 0xa7, 0xff, 0x06, 0x00, 0x00, 0xff, 0xff, 0xd5, 0x7e, 0x59,
-	0x12, 0x69, 0x06, 0x00, 0x00,
+	// 844 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x54, 0x4f, 0x6f, 0xe3, 0x44,
+	0x14, 0x8f, 0xeb, 0xc4, 0x49, 0xa6, 0xab, 0xec, 0x32, 0x5b, 0x15, 0x77, 0x17, 0xd9, 0x51, 0x04,
+	0x52, 0x90, 0xc0, 0x11, 0x45, 0x70, 0x5b, 0x44, 0xdd, 0x16, 0xb1, 0xab, 0x95, 0x58, 0x4d, 0x91,
+	0x90, 0xb8, 0xa0, 0x49, 0xfc, 0xea, 0x5a, 0x4d, 0x6c, 0xef, 0xcc, 0x78, 0x21, 0x37, 0x4e, 0x9c,
+	0x39, 0xf2, 0x11, 0x38, 0xf0, 0x41, 0x2a, 0x4e, 0x3d, 0xae, 0x38, 0x18, 0xea, 0x5e, 0x90, 0x4f,
+	0xfb, 0x11, 0xd0, 0xcc, 0xd8, 0x89, 0x93, 0xe5, 0xdf, 0x25, 0x79, 0x7f, 0x7e, 0xef, 0xdf, 0xef,
+	0x8d, 0x1f, 0xfa, 0x38, 0xbd, 0x0c, 0x27, 0xcf, 0x33, 0x60, 0x11, 0x30, 0xf5, 0xbf, 0x64, 0x34,
+	0x0e, 0xa1, 0x21, 0x4e, 0x29, 0x6f, 0xaa, 0x5e, 0xca, 0x12, 0x91, 0xe0, 0xc1, 0x26, 0xe0, 0xc1,
+	0x5e, 0x98, 0x84, 0x89, 0x72, 0x4d, 0xa4, 0xa4, 0x51, 0x0f, 0x0e, 0xc2, 0x24, 0x09, 0xe7, 0x30,
+	0x51, 0xda, 0x34, 0x3b, 0x9f, 0xd0, 0x78, 0x59, 0xb9, 0x9c, 0x6d, 0x57, 0x90, 0x31, 0x2a, 0xa2,
+	0x24, 0xae, 0xfc, 0x0f, 0x65, 0x63, 0xf3, 0x24, 0xd4, 0x39, 0x6b, 0xa1, 0x72, 0x1e, 0xff, 0xbf,
+	0xae, 0x03, 0x38, 0x8f, 0xe2, 0x48, 0x26, 0xe5, 0x4d, 0x59, 0x27, 0x19, 0xfd, 0xba, 0x83, 0xde,
+	0x78, 0xc6, 0x92, 0x05, 0x88, 0x0b, 0xc8, 0x38, 0x81, 0xe7, 0x19, 0x70, 0x81, 0x31, 0x6a, 0xa7,
+	0x54, 0x5c, 0xd8, 0xc6, 0xd0, 0x18, 0xf7, 0x89, 0x92, 0xf1, 0x1e, 0xea, 0x70, 0x41, 0x99, 0xb0,
+	0x77, 0x86, 0xc6, 0xd8, 0x24, 0x5a, 0xc1, 0xf7, 0x90, 0x09, 0x71, 0x60, 0x9b, 0xca, 0x26, 0x45,
+	0x19, 0xcb, 0x05, 0xa4, 0x76, 0x5b, 0x99, 0x94, 0x8c, 0x1f, 0xa1, 0xae, 0x88, 0x16, 0x90, 0x64,
+	0xc2, 0xee, 0x0c, 0x8d, 0xf1, 0xee, 0xe1, 0x81, 0xa7, 0x27, 0xf7, 0xea, 0xc9, 0xbd, 0x93, 0x6a,
+	0x72, 0xbf, 0x77, 0x95, 0xbb, 0xad, 0x9f, 0x7e, 0x77, 0x0d, 0x52, 0xc7, 0xc8, 0xd2, 0x6a, 0x28,
+	0xdb, 0x52, 0xfd, 0x68, 0x05, 0x3f, 0x46, 0x83, 0x19, 0x9d, 0x5d, 0x44, 0x71, 0xf8, 0x45, 0xaa,
+	0x46, 0xb2, 0xbb, 0x2a, 0xf7, 0x43, 0xaf, 0x39, 0xe6, 0xf1, 0x06, 0xc4, 0x6f, 0xcb, 0xec, 0x64,
+	0x2b, 0x10, 0x9f, 0xa2, 0xee, 0xe7, 0x40, 0x03, 0x60, 0xdc, 0xee, 0x0d, 0xcd, 0xf1, 0xee, 0xe1,
+	0xdb, 0x1b, 0x39, 0x5e, 0x23, 0x48, 0x83, 0xfd, 0x4e, 0x99, 0xbb, 0xc6, 0xfb, 0xa4, 0x8e, 0x1d,
+	0x15, 0x3b, 0x08, 0x37, 0xb1, 0x3c, 0x4d, 0x62, 0x0e, 0x78, 0x84, 0xac, 0x33, 0x41, 0x45, 0xc6,
+	0x35, 0x9f, 0x3e, 0x2a, 0x73, 0xd7, 0xe2, 0xca, 0x42, 0x2a, 0x0f, 0x7e, 0x82, 0xda, 0x27, 0x54,
+	0x50, 0x45, 0xee, 0xee, 0xa1, 0xe3, 0x6d, 0x2e, 0xb1, 0xd1, 0x81, 0x44, 0xf9, 0xfb, 0x72, 0x8a,
+	0x32, 0x77, 0x07, 0x01, 0x15, 0xf4, 0xbd, 0x64, 0x11, 0x09, 0x58, 0xa4, 0x62, 0x49, 0x54, 0x0e,
+	0xfc, 0x11, 0xea, 0x9f, 0x32, 0x96, 0xb0, 0x2f, 0x97, 0x29, 0xa8, 0xcd, 0xf4, 0xfd, 0x37, 0xcb,
+	0xdc, 0xbd, 0x0f, 0xb5, 0xb1, 0x11, 0xb1, 0x46, 0xe2, 0x77, 0x51, 0x47, 0x29, 0x6a, 0x73, 0x7d,
+	0xff, 0x7e, 0x99, 0xbb, 0x77, 0x55, 0x48, 0x03, 0xae, 0x11, 0xf8, 0xb3, 0x35, 0x5f, 0x1d, 0xc5,
+	0xd7, 0x3b, 0xff, 0xc8, 0x97, 0xe6, 0xe0, 0xef, 0x09, 0xc3, 0x87, 0xa8, 0xf7, 0x15, 0x65, 0x71,
+	0x14, 0x87, 0xdc, 0xb6, 0x86, 0xe6, 0xb8, 0xef, 0xef, 0x97, 0xb9, 0x8b, 0xbf, 0xad, 0x6c, 0x8d,
+	0xc2, 0x2b, 0xdc, 0xe8, 0x07, 0x03, 0x0d, 0x36, 0xe9, 0xc0, 0x1e, 0x42, 0x04, 0x78, 0x36, 0x17,
+	0x6a, 0x62, 0x4d, 0xf2, 0xa0, 0xcc, 0x5d, 0xc4, 0x56, 0x56, 0xd2, 0x40, 0xe0, 0x13, 0x64, 0x69,
+	0xcd, 0xde, 0x51, 0xdd, 0xbf, 0xb5, 0x4d, 0xf7, 0x19, 0x5d, 0xa4, 0x73, 0x38, 0x13, 0x0c, 0xe8,
+	0xc2, 0x1f, 0x54, 0x64, 0x5b, 0x3a, 0x1b, 0xa9, 0x62, 0x47, 0x57, 0x06, 0xba, 0xd3, 0x04, 0xe2,
+	0x17, 0xc8, 0x9a, 0xd3, 0x29, 0xcc, 0xe5, 0x9e, 0x4d, 0xf5, 0xc8, 0x57, 0x5f, 0xec, 0x53, 0x08,
+	0xe9, 0x6c, 0xf9, 0x54, 0x7a, 0x9f, 0xd1, 0x88, 0xf9, 0xc7, 0x32, 0xe7, 0x6f, 0xb9, 0xfb, 0x41,
+	0x18, 0x89, 0x8b, 0x6c, 0xea, 0xcd, 0x92, 0xc5, 0x24, 0x64, 0xf4, 0x9c, 0xc6, 0x74, 0x32, 0x4f,
+	0x2e, 0xa3, 0x49, 0xf3, 0xc3, 0xf7, 0x54, 0xdc, 0x51, 0x40, 0x53, 0x01, 0x4c, 0x36, 0xb2, 0x00,
+	0xc1, 0xa2, 0x19, 0xa9, 0xaa, 0xe1, 0x4f, 0x51, 0x97, 0xab, 0x3e, 0x78, 0x35, 0xcf, 0xfe, 0x76,
+	0x61, 0xdd, 0xe6, 0x7a, 0x92, 0x17, 0x74, 0x9e, 0x01, 0x27, 0x75, 0xd8, 0x28, 0x46, 0x03, 0xf9,
+	0x9d, 0x40, 0xb0, 0x7a, 0xb3, 0x07, 0xc8, 0xbc, 0x84, 0x65, 0xc5, 0x65, 0xb7, 0xcc, 0x5d, 0xa9,
+	0x12, 0xf9, 0x83, 0x8f, 0x50, 0x17, 0xbe, 0x13, 0x10, 0x8b, 0x75, 0xb9, 0x2d, 0xfa, 0x4e, 0x95,
+	0xdb, 0xbf, 0x5b, 0x95, 0xab, 0xe1, 0xa4, 0x16, 0x46, 0xbf, 0x18, 0xc8, 0xd2, 0x20, 0xec, 0xd6,
+	0x67, 0x45, 0x96, 0x32, 0xfd, 0x7e, 0x99, 0xbb, 0xda, 0x50, 0x5f, 0x98, 0x03, 0x7d, 0x61, 0xd4,
+	0xd5, 0xd1, 0x9d, 0x40, 0x1c, 0xe8, 0x53, 0x33, 0x44, 0x3d, 0xc1, 0xe8, 0x0c, 0xbe, 0x89, 0x82,
+	0xea, 0xd1, 0xd6, 0x0f, 0x4c, 0x99, 0x1f, 0x07, 0xf8, 0x13, 0xd4, 0x63, 0xd5, 0x48, 0xd5, 0xe5,
+	0xd9, 0x7b, 0xed, 0xf2, 0x1c, 0xc5, 0x4b, 0xff, 0x4e, 0x99, 0xbb, 0x2b, 0x24, 0x59, 0x49, 0x4f,
+	0xda, 0x3d, 0xf3, 0x5e, 0xdb, 0xe7, 0xd7, 0x37, 0x4e, 0xeb, 0xe5, 0x8d, 0xd3, 0x7a, 0x75, 0xe3,
+	0x18, 0xdf, 0x17, 0x8e, 0xf1, 0x73, 0xe1, 0x18, 0x57, 0x85, 0x63, 0x5c, 0x17, 0x8e, 0xf1, 0x47,
+	0xe1, 0x18, 0x7f, 0x16, 0x4e, 0xeb, 0x55, 0xe1, 0x18, 0x3f, 0xde, 0x3a, 0xad, 0xeb, 0x5b, 0xa7,
+	0xf5, 0xf2, 0xd6, 0x69, 0x7d, 0xfd, 0xe8, 0xdf, 0x76, 0xfb, 0x9f, 0x77, 0x7b, 0x6a, 0xa9, 0x06,
+	0x3f, 0xfc, 0x6b, 0x00, 0x46, 0xaa, 0x47, 0x04, 0x9d, 0x06, 0x00, 0x00,
 }
 
 func (this *PrometheusRequest) Equal(that interface{}) bool {
@@ -579,6 +588,14 @@ func (this *PrometheusResponse) Equal(that interface{}) bool {
 			return false
 		}
 	}
+	if len(this.Warnings) != len(that1.Warnings) {
+		return false
+	}
+	for i := range this.Warnings {
+		if this.Warnings[i] != that1.Warnings[i] {
+			return false
+		}
+	}
 	return true
 }
 func (this *PrometheusData) Equal(that interface{}) bool {
@@ -738,7 +755,7 @@ func (this *PrometheusResponse) GoString() string {
 	if this == nil {
 		return "nil"
 	}
-	s := make([]string, 0, 9)
+	s := make([]string, 0, 10)
 	s = append(s, "&queryrangebase.PrometheusResponse{")
 	s = append(s, "Status: "+fmt.Sprintf("%#v", this.Status)+",\n")
 	s = append(s, "Data: "+strings.Replace(this.Data.GoString(), `&`, ``, 1)+",\n")
@@ -747,6 +764,7 @@ func (this *PrometheusResponse) GoString() string {
 	if this.Headers != nil {
 		s = append(s, "Headers: "+fmt.Sprintf("%#v", this.Headers)+",\n")
 	}
+	s = append(s, "Warnings: "+fmt.Sprintf("%#v", this.Warnings)+",\n")
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
@@ -928,6 +946,15 @@ func (m *PrometheusResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.Warnings) > 0 {
+		for iNdEx := len(m.Warnings) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Warnings[iNdEx])
+			copy(dAtA[i:], m.Warnings[iNdEx])
+			i = encodeVarintQueryrange(dAtA, i, uint64(len(m.Warnings[iNdEx])))
+			i--
+			dAtA[i] = 0x32
+		}
+	}
 	if len(m.Headers) > 0 {
 		for iNdEx := len(m.Headers) - 1; iNdEx >= 0; iNdEx-- {
 			{
@@ -1240,6 +1267,12 @@ func (m *PrometheusResponse) Size() (n int) {
 			n += 1 + l + sovQueryrange(uint64(l))
 		}
 	}
+	if len(m.Warnings) > 0 {
+		for _, s := range m.Warnings {
+			l = len(s)
+			n += 1 + l + sovQueryrange(uint64(l))
+		}
+	}
 	return n
 }
 
@@ -1345,7 +1378,7 @@ func (this *PrometheusRequest) String() string {
 		`Start:` + fmt.Sprintf("%v", this.Start) + `,`,
 		`End:` + fmt.Sprintf("%v", this.End) + `,`,
 		`Step:` + fmt.Sprintf("%v", this.Step) + `,`,
-		`Timeout:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.Timeout), "Duration", "duration.Duration", 1), `&`, ``, 1) + `,`,
+		`Timeout:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.Timeout), "Duration", "durationpb.Duration", 1), `&`, ``, 1) + `,`,
 		`Query:` + fmt.Sprintf("%v", this.Query) + `,`,
 		`CachingOptions:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.CachingOptions), "CachingOptions", "definitions.CachingOptions", 1), `&`, ``, 1) + `,`,
 		`Headers:` + repeatedStringForHeaders + `,`,
@@ -1368,6 +1401,7 @@ func (this *PrometheusResponse) String() string {
 		`ErrorType:` + fmt.Sprintf("%v", this.ErrorType) + `,`,
 		`Error:` + fmt.Sprintf("%v", this.Error) + `,`,
 		`Headers:` + repeatedStringForHeaders + `,`,
+		`Warnings:` + fmt.Sprintf("%v", this.Warnings) + `,`,
 		`}`,
 	}, "")
 	return s
@@ -1907,6 +1941,38 @@ func (m *PrometheusResponse) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Warnings", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQueryrange
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Warnings = append(m.Warnings, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipQueryrange(dAtA[iNdEx:])
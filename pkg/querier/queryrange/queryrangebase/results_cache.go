@@ -281,6 +281,11 @@ func (s resultsCache) shouldCacheResponse(ctx context.Context, req Request, r Re
 		}
 	}
 
+	if w, ok := r.(interface{ GetWarnings() []string }); ok && len(w.GetWarnings()) > 0 {
+		level.Debug(logger).Log("msg", "response contains warnings, not caching partial results")
+		return false
+	}
+
 	if !s.isAtModifierCachable(req, maxCacheTime) {
 		return false
 	}
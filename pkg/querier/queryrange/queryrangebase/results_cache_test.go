@@ -139,6 +139,14 @@ func TestShouldCache(t *testing.T) {
 			}),
 			expected: true,
 		},
+		{
+			name:    "contains warnings of a partial result",
+			request: &PrometheusRequest{Query: "metric"},
+			input: Response(&PrometheusResponse{
+				Warnings: []string{"partial result: shard 1_of_2 failed"},
+			}),
+			expected: false,
+		},
 		{
 			name:    "does contain the cacheControl header which has the value",
 			request: &PrometheusRequest{Query: "metric"},
@@ -12,11 +12,23 @@ var StepAlignMiddleware = MiddlewareFunc(func(next Handler) Handler {
 	}
 })
 
+// StepAligner is implemented by the requests whose steps are not multiples of their step, e.g. aligned to the calendar.
+type StepAligner interface {
+	// AlignToSteps returns the request with its start and end aligned to its steps,
+	// or false if its steps are multiples of its step.
+	AlignToSteps() (Request, bool)
+}
+
 type stepAlign struct {
 	next Handler
 }
 
 func (s stepAlign) Do(ctx context.Context, r Request) (Response, error) {
+	if a, ok := r.(StepAligner); ok {
+		if aligned, ok := a.AlignToSteps(); ok {
+			return s.next.Do(ctx, aligned)
+		}
+	}
 	start := (r.GetStart() / r.GetStep()) * r.GetStep()
 	end := (r.GetEnd() / r.GetStep()) * r.GetStep()
 	return s.next.Do(ctx, r.WithStartEnd(start, end))
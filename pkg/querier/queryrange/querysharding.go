@@ -23,6 +23,7 @@ import (
 	"github.com/grafana/loki/pkg/querier/queryrange/queryrangebase"
 	"github.com/grafana/loki/pkg/storage/config"
 	"github.com/grafana/loki/pkg/util"
+	"github.com/grafana/loki/pkg/util/httpreq"
 	util_log "github.com/grafana/loki/pkg/util/log"
 	"github.com/grafana/loki/pkg/util/marshal"
 	"github.com/grafana/loki/pkg/util/spanlogger"
@@ -162,6 +163,11 @@ func (ast *astMapperware) Do(ctx context.Context, r queryrangebase.Request) (que
 		return nil, err
 	}
 
+	allowPartialResults := func(id string) bool { return ast.limits.AllowPartialResults(ctx, id) }
+	if validation.AllTruePerTenant(tenants, allowPartialResults) {
+		ctx = httpreq.InjectAllowPartialResults(ctx)
+	}
+
 	// The shard resolver uses index stats to determine the number of shards.
 	// We want to store the cache stats for the requests to get the index stats.
 	// Later on, the query engine overwrites the stats context with other stats,
@@ -243,7 +249,8 @@ func (ast *astMapperware) Do(ctx context.Context, r queryrangebase.Request) (que
 					ResultType: loghttp.ResultTypeMatrix,
 					Result:     toProtoMatrix(value.(loghttp.Matrix)),
 				},
-				Headers: res.Headers,
+				Headers:  res.Headers,
+				Warnings: res.Warnings,
 			},
 			Statistics: res.Statistics,
 		}, nil
@@ -263,7 +270,8 @@ func (ast *astMapperware) Do(ctx context.Context, r queryrangebase.Request) (que
 				ResultType: loghttp.ResultTypeStream,
 				Result:     value.(loghttp.Streams).ToProto(),
 			},
-			Headers: respHeaders,
+			Headers:  respHeaders,
+			Warnings: res.Warnings,
 		}, nil
 	case parser.ValueTypeVector:
 		return &LokiPromResponse{
@@ -274,7 +282,8 @@ func (ast *astMapperware) Do(ctx context.Context, r queryrangebase.Request) (que
 					ResultType: loghttp.ResultTypeVector,
 					Result:     toProtoVector(value.(loghttp.Vector)),
 				},
-				Headers: res.Headers,
+				Headers:  res.Headers,
+				Warnings: res.Warnings,
 			},
 		}, nil
 	default:
@@ -35,6 +35,7 @@ type Config struct {
 	StatsCacheConfig       IndexStatsCacheConfig `yaml:"index_stats_results_cache" doc:"description=If a cache config is not specified and cache_index_stats_results is true, the config for the results cache is used."`
 	CacheVolumeResults     bool                  `yaml:"cache_volume_results"`
 	VolumeCacheConfig      VolumeCacheConfig     `yaml:"volume_results_cache" doc:"description=If a cache config is not specified and cache_volume_results is true, the config for the results cache is used."`
+	CacheLogResults        bool                  `yaml:"cache_log_results"`
 }
 
 // RegisterFlags adds the flags required to configure this flag set.
@@ -44,6 +45,7 @@ func (cfg *Config) RegisterFlags(f *flag.FlagSet) {
 	cfg.StatsCacheConfig.RegisterFlags(f)
 	f.BoolVar(&cfg.CacheVolumeResults, "querier.cache-volume-results", false, "Cache volume query results.")
 	cfg.VolumeCacheConfig.RegisterFlags(f)
+	f.BoolVar(&cfg.CacheLogResults, "querier.cache-log-results", false, "Cache non-empty log query results per split interval in the results cache. Empty log query results are always cached when cache_results is enabled.")
 }
 
 // Validate validates the config.
@@ -177,6 +179,11 @@ func NewTripperware(
 		return nil, nil, err
 	}
 
+	joinTripperware, err := NewJoinTripperware(cfg, log, limits, schema, codec, metrics)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	seriesTripperware, err := NewSeriesTripperware(cfg, log, limits, codec, metrics, schema)
 	if err != nil {
 		return nil, nil, err
@@ -202,6 +209,7 @@ func NewTripperware(
 			metricRT       = metricsTripperware(next)
 			limitedRT      = limitedTripperware(next)
 			logFilterRT    = logFilterTripperware(next)
+			joinRT         = joinTripperware(next)
 			seriesRT       = seriesTripperware(next)
 			labelsRT       = labelsTripperware(next)
 			instantRT      = instantMetricTripperware(next)
@@ -209,24 +217,25 @@ func NewTripperware(
 			seriesVolumeRT = seriesVolumeTripperware(next)
 		)
 
-		return newRoundTripper(log, next, limitedRT, logFilterRT, metricRT, seriesRT, labelsRT, instantRT, statsRT, seriesVolumeRT, limits)
+		return newRoundTripper(log, next, limitedRT, logFilterRT, joinRT, metricRT, seriesRT, labelsRT, instantRT, statsRT, seriesVolumeRT, limits)
 	}, StopperWrapper{resultsCache, statsCache, volumeCache}, nil
 }
 
 type roundTripper struct {
 	logger log.Logger
 
-	next, limited, log, metric, series, labels, instantMetric, indexStats, seriesVolume http.RoundTripper
+	next, limited, log, join, metric, series, labels, instantMetric, indexStats, seriesVolume http.RoundTripper
 
 	limits Limits
 }
 
 // newRoundTripper creates a new queryrange roundtripper
-func newRoundTripper(logger log.Logger, next, limited, log, metric, series, labels, instantMetric, indexStats, seriesVolume http.RoundTripper, limits Limits) roundTripper {
+func newRoundTripper(logger log.Logger, next, limited, log, join, metric, series, labels, instantMetric, indexStats, seriesVolume http.RoundTripper, limits Limits) roundTripper {
 	return roundTripper{
 		logger:        logger,
 		limited:       limited,
 		log:           log,
+		join:          join,
 		limits:        limits,
 		metric:        metric,
 		series:        series,
@@ -268,7 +277,7 @@ func (r roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
 			}
 
 			for _, g := range groups {
-				if err := validateMatchers(req, r.limits, g.Matchers); err != nil {
+				if err := ValidateMatchers(req.Context(), r.limits, g.Matchers); err != nil {
 					return nil, httpgrpc.Errorf(http.StatusBadRequest, err.Error())
 				}
 			}
@@ -283,7 +292,7 @@ func (r roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
 				return nil, httpgrpc.Errorf(http.StatusBadRequest, err.Error())
 			}
 
-			if err := validateMatchers(req, r.limits, e.Matchers()); err != nil {
+			if err := ValidateMatchers(req.Context(), r.limits, e.Matchers()); err != nil {
 				return nil, httpgrpc.Errorf(http.StatusBadRequest, err.Error())
 			}
 
@@ -292,6 +301,16 @@ func (r roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
 				return r.limited.RoundTrip(req)
 			}
 			return r.log.RoundTrip(req)
+		case *syntax.JoinExpr:
+			if err := validateMaxEntriesLimits(req, rangeQuery.Limit, r.limits); err != nil {
+				return nil, httpgrpc.Errorf(http.StatusBadRequest, err.Error())
+			}
+			for _, side := range []syntax.LogSelectorExpr{e.Left, e.Right} {
+				if err := ValidateMatchers(req.Context(), r.limits, side.Matchers()); err != nil {
+					return nil, httpgrpc.Errorf(http.StatusBadRequest, err.Error())
+				}
+			}
+			return r.join.RoundTrip(req)
 
 		default:
 			return r.next.RoundTrip(req)
@@ -447,7 +466,7 @@ func NewLogFilterTripperware(
 			NewLimitsMiddleware(limits),
 			NewQuerySizeLimiterMiddleware(schema.Configs, engineOpts, log, limits, statsHandler),
 			queryrangebase.InstrumentMiddleware("split_by_interval", metrics.InstrumentMiddlewareMetrics),
-			SplitByIntervalMiddleware(schema.Configs, limits, codec, splitByTime, metrics.SplitByMetrics),
+			SplitByIntervalMiddleware(schema.Configs, limits, codec, splitByTime, metrics.SplitByMetrics, statsHandler),
 		}
 
 		if cfg.CacheResults {
@@ -459,6 +478,7 @@ func NewLogFilterTripperware(
 					return !r.GetCachingOptions().Disabled
 				},
 				cfg.Transformer,
+				cfg.CacheLogResults,
 				metrics.LogResultCacheMetrics,
 			)
 			queryRangeMiddleware = append(
@@ -504,6 +524,33 @@ func NewLogFilterTripperware(
 	}, nil
 }
 
+// NewJoinTripperware creates a new frontend tripperware responsible for handling log joins, which are neither split
+// nor sharded, as the log lines of a key can be spread across splits and shards.
+func NewJoinTripperware(
+	cfg Config,
+	log log.Logger,
+	limits Limits,
+	schema config.SchemaConfig,
+	codec queryrangebase.Codec,
+	metrics *Metrics,
+) (queryrangebase.Tripperware, error) {
+	return func(next http.RoundTripper) http.RoundTripper {
+		queryRangeMiddleware := []queryrangebase.Middleware{
+			StatsCollectorMiddleware(),
+			NewLimitsMiddleware(limits),
+		}
+
+		if cfg.MaxRetries > 0 {
+			queryRangeMiddleware = append(
+				queryRangeMiddleware, queryrangebase.InstrumentMiddleware("retry", metrics.InstrumentMiddlewareMetrics),
+				queryrangebase.NewRetryMiddleware(log, cfg.MaxRetries, metrics.RetryMiddlewareMetrics),
+			)
+		}
+
+		return NewLimitedRoundTripper(next, codec, limits, schema.Configs, queryRangeMiddleware...)
+	}, nil
+}
+
 // NewLimitedTripperware creates a new frontend tripperware responsible for handling log requests which are label matcher only, no filter expression.
 func NewLimitedTripperware(
 	_ Config,
@@ -528,7 +575,7 @@ func NewLimitedTripperware(
 			// potentially GB of logs being returned by all the shards and splits which will overwhelm the frontend
 			// Therefore we force max parallelism to one so that these queries are executed sequentially.
 			// Below we also fix the number of shards to a static number.
-			SplitByIntervalMiddleware(schema.Configs, WithMaxParallelism(limits, 1), codec, splitByTime, metrics.SplitByMetrics),
+			SplitByIntervalMiddleware(schema.Configs, WithMaxParallelism(limits, 1), codec, splitByTime, metrics.SplitByMetrics, nil),
 			NewQuerierSizeLimiterMiddleware(schema.Configs, engineOpts, log, limits, statsHandler),
 		}
 
@@ -555,7 +602,7 @@ func NewSeriesTripperware(
 		// The Series API needs to pull one chunk per series to extract the label set, which is much cheaper than iterating through all matching chunks.
 		// Force a 24 hours split by for series API, this will be more efficient with our static daily bucket storage.
 		// This would avoid queriers downloading chunks for same series over and over again for serving smaller queries.
-		SplitByIntervalMiddleware(schema.Configs, WithSplitByLimits(limits, 24*time.Hour), codec, splitByTime, metrics.SplitByMetrics),
+		SplitByIntervalMiddleware(schema.Configs, WithSplitByLimits(limits, 24*time.Hour), codec, splitByTime, metrics.SplitByMetrics, nil),
 	}
 
 	if cfg.MaxRetries > 0 {
@@ -601,7 +648,7 @@ func NewLabelsTripperware(
 		queryrangebase.InstrumentMiddleware("split_by_interval", metrics.InstrumentMiddlewareMetrics),
 		// Force a 24 hours split by for labels API, this will be more efficient with our static daily bucket storage.
 		// This is because the labels API is an index-only operation.
-		SplitByIntervalMiddleware(schema.Configs, WithSplitByLimits(limits, 24*time.Hour), codec, splitByTime, metrics.SplitByMetrics),
+		SplitByIntervalMiddleware(schema.Configs, WithSplitByLimits(limits, 24*time.Hour), codec, splitByTime, metrics.SplitByMetrics, nil),
 	}
 
 	if cfg.MaxRetries > 0 {
@@ -688,7 +735,7 @@ func NewMetricTripperware(
 			queryRangeMiddleware,
 			NewQuerySizeLimiterMiddleware(schema.Configs, engineOpts, log, limits, statsHandler),
 			queryrangebase.InstrumentMiddleware("split_by_interval", metrics.InstrumentMiddlewareMetrics),
-			SplitByIntervalMiddleware(schema.Configs, limits, codec, splitMetricByTime, metrics.SplitByMetrics),
+			SplitByIntervalMiddleware(schema.Configs, limits, codec, splitMetricByTime, metrics.SplitByMetrics, statsHandler),
 		)
 
 		if cfg.CacheResults {
@@ -979,7 +1026,7 @@ func sharedIndexTripperware(
 		middlewares := []queryrangebase.Middleware{
 			NewLimitsMiddleware(limits),
 			queryrangebase.InstrumentMiddleware("split_by_interval", metrics.InstrumentMiddlewareMetrics),
-			SplitByIntervalMiddleware(schema.Configs, limits, codec, splitByTime, metrics.SplitByMetrics),
+			SplitByIntervalMiddleware(schema.Configs, limits, codec, splitByTime, metrics.SplitByMetrics, nil),
 		}
 
 		if cacheMiddleware != nil {
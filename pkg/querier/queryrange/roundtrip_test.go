@@ -970,6 +970,10 @@ func TestPostQueries(t *testing.T) {
 		queryrangebase.RoundTripFunc(func(*http.Request) (*http.Response, error) {
 			return nil, nil
 		}),
+		queryrangebase.RoundTripFunc(func(*http.Request) (*http.Response, error) {
+			t.Error("unexpected join roundtripper called")
+			return nil, nil
+		}),
 		queryrangebase.RoundTripFunc(func(*http.Request) (*http.Response, error) {
 			t.Error("unexpected metric roundtripper called")
 			return nil, nil
@@ -1030,6 +1034,37 @@ func TestTripperware_EntriesLimit(t *testing.T) {
 	require.Equal(t, httpgrpc.Errorf(http.StatusBadRequest, "max entries limit per query exceeded, limit > max_entries_limit (10000 > 5000)"), err)
 }
 
+func TestTripperware_JoinLimits(t *testing.T) {
+	tpw, stopper, err := NewTripperware(testConfig, testEngineOpts, util_log.Logger, fakeLimits{maxQueryLength: time.Hour, maxQueryParallelism: 1}, config.SchemaConfig{Configs: testSchemas}, nil, false, nil)
+	if stopper != nil {
+		defer stopper.Stop()
+	}
+	require.NoError(t, err)
+	rt, err := newfakeRoundTripper()
+	require.NoError(t, err)
+	defer rt.Close()
+
+	lreq := &LokiRequest{
+		Query:     `join({app="foo"}, {app="bar"}, "request_id", 5s)`,
+		Limit:     1000,
+		StartTs:   testTime.Add(-6 * time.Hour),
+		EndTs:     testTime,
+		Direction: logproto.FORWARD,
+		Path:      "/loki/api/v1/query_range",
+	}
+
+	ctx := user.InjectOrgID(context.Background(), "1")
+	req, err := DefaultCodec.EncodeRequest(ctx, lreq)
+	require.NoError(t, err)
+
+	req = req.WithContext(ctx)
+	err = user.InjectOrgIDIntoHTTPRequest(ctx, req)
+	require.NoError(t, err)
+
+	_, err = tpw(rt).RoundTrip(req)
+	require.Equal(t, httpgrpc.Errorf(http.StatusBadRequest, validation.ErrQueryTooLong, "6h0m0s", "1h"), err)
+}
+
 func TestTripperware_RequiredLabels(t *testing.T) {
 
 	const noErr = ""
@@ -1045,6 +1080,8 @@ func TestTripperware_RequiredLabels(t *testing.T) {
 		{`avg(count_over_time({pod=~"foo|bar"} |~".+bar" [1m]))`, "stream selector is missing required matchers [app], labels present in the query were [pod]", nil},
 		{`{app="foo", pod="bar"}`, noErr, streams},
 		{`{pod="bar"} |= "foo" |~ ".+bar"`, "stream selector is missing required matchers [app], labels present in the query were [pod]", nil},
+		{`join({app="foo"} | json, {app="bar"}, "request_id", 5s)`, noErr, streams},
+		{`join({app="foo"}, {pod="bar"}, "request_id", 5s)`, "stream selector is missing required matchers [app], labels present in the query were [pod]", nil},
 	} {
 		t.Run(test.qs, func(t *testing.T) {
 			limits := fakeLimits{maxEntriesLimitPerQuery: 5000, maxQueryParallelism: 1, requiredLabels: []string{"app"}}
@@ -1382,6 +1419,7 @@ type fakeLimits struct {
 	maxEntriesLimitPerQuery int
 	maxSeries               int
 	splits                  map[string]time.Duration
+	splitTargetBytes        int
 	minShardingLookback     time.Duration
 	queryTimeout            time.Duration
 	requiredLabels          []string
@@ -1390,6 +1428,7 @@ type fakeLimits struct {
 	maxQuerierBytesRead     int
 	maxStatsCacheFreshness  time.Duration
 	volumeEnabled           bool
+	allowPartialResults     bool
 }
 
 func (f fakeLimits) QuerySplitDuration(key string) time.Duration {
@@ -1399,6 +1438,10 @@ func (f fakeLimits) QuerySplitDuration(key string) time.Duration {
 	return f.splits[key]
 }
 
+func (f fakeLimits) QuerySplitTargetBytes(string) int {
+	return f.splitTargetBytes
+}
+
 func (f fakeLimits) MaxQueryLength(context.Context, string) time.Duration {
 	if f.maxQueryLength == 0 {
 		return time.Hour * 7
@@ -1426,6 +1469,10 @@ func (f fakeLimits) MaxQuerySeries(context.Context, string) int {
 	return f.maxSeries
 }
 
+func (f fakeLimits) MaxQueryJoinEntries(context.Context, string) int {
+	return 0
+}
+
 func (f fakeLimits) MaxCacheFreshness(context.Context, string) time.Duration {
 	return 1 * time.Minute
 }
@@ -1470,6 +1517,10 @@ func (f fakeLimits) VolumeEnabled(_ string) bool {
 	return f.volumeEnabled
 }
 
+func (f fakeLimits) AllowPartialResults(_ context.Context, _ string) bool {
+	return f.allowPartialResults
+}
+
 func (f fakeLimits) TSDBMaxBytesPerShard(_ string) int {
 	return valid.DefaultTSDBMaxBytesPerShard
 }
@@ -1490,7 +1541,7 @@ func promqlResult(v parser.Value) (*int, http.Handler) {
 	return &count, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		lock.Lock()
 		defer lock.Unlock()
-		if err := marshal.WriteQueryResponseJSON(v, stats.Result{}, w); err != nil {
+		if err := marshal.WriteQueryResponseJSON(v, nil, stats.Result{}, w); err != nil {
 			panic(err)
 		}
 		count++
@@ -3,8 +3,11 @@ package queryrange
 import (
 	"context"
 	"net/http"
+	"strings"
 	"time"
 
+	"github.com/dustin/go-humanize"
+	"github.com/go-kit/log/level"
 	"github.com/grafana/dskit/httpgrpc"
 	"github.com/opentracing/opentracing-go"
 	otlog "github.com/opentracing/opentracing-go/log"
@@ -17,10 +20,15 @@ import (
 	"github.com/grafana/dskit/tenant"
 
 	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/logql"
+	"github.com/grafana/loki/pkg/logql/log"
 	"github.com/grafana/loki/pkg/logql/syntax"
+	"github.com/grafana/loki/pkg/logqlmodel/stats"
 	"github.com/grafana/loki/pkg/querier/queryrange/queryrangebase"
 	"github.com/grafana/loki/pkg/storage/config"
+	indexStats "github.com/grafana/loki/pkg/storage/stores/index/stats"
 	"github.com/grafana/loki/pkg/util"
+	"github.com/grafana/loki/pkg/util/spanlogger"
 	"github.com/grafana/loki/pkg/util/validation"
 )
 
@@ -49,6 +57,10 @@ func NewSplitByMetrics(r prometheus.Registerer) *SplitByMetrics {
 	}
 }
 
+// minAdaptiveSplitInterval is the smallest interval a query is split by when
+// the split interval is adapted to the volume of the query.
+const minAdaptiveSplitInterval = time.Minute
+
 type splitByInterval struct {
 	configs  []config.PeriodConfig
 	next     queryrangebase.Handler
@@ -56,24 +68,29 @@ type splitByInterval struct {
 	merger   queryrangebase.Merger
 	metrics  *SplitByMetrics
 	splitter Splitter
+	// statsHandler queries the index stats used to adapt the split interval to the volume of a query.
+	statsHandler queryrangebase.Handler
 }
 
 type Splitter func(req queryrangebase.Request, interval time.Duration) ([]queryrangebase.Request, error)
 
 // SplitByIntervalMiddleware creates a new Middleware that splits log requests by a given interval.
-func SplitByIntervalMiddleware(configs []config.PeriodConfig, limits Limits, merger queryrangebase.Merger, splitter Splitter, metrics *SplitByMetrics) queryrangebase.Middleware {
+// If statsHandler is not nil, the interval of range queries is adapted to their volume for
+// tenants with a split target size.
+func SplitByIntervalMiddleware(configs []config.PeriodConfig, limits Limits, merger queryrangebase.Merger, splitter Splitter, metrics *SplitByMetrics, statsHandler queryrangebase.Handler) queryrangebase.Middleware {
 	if metrics == nil {
 		metrics = NewSplitByMetrics(nil)
 	}
 
 	return queryrangebase.MiddlewareFunc(func(next queryrangebase.Handler) queryrangebase.Handler {
 		return &splitByInterval{
-			configs:  configs,
-			next:     next,
-			limits:   limits,
-			merger:   merger,
-			metrics:  metrics,
-			splitter: splitter,
+			configs:      configs,
+			next:         next,
+			limits:       limits,
+			merger:       merger,
+			metrics:      metrics,
+			splitter:     splitter,
+			statsHandler: statsHandler,
 		}
 	})
 }
@@ -102,6 +119,7 @@ func (h *splitByInterval) Process(
 	threshold int64,
 	input []*lokiResult,
 	maxSeries int,
+	mergeStages log.Pipeline,
 ) ([]queryrangebase.Response, error) {
 	var responses []queryrangebase.Response
 	ctx, cancel := context.WithCancel(ctx)
@@ -137,6 +155,16 @@ func (h *splitByInterval) Process(
 				return nil, data.err
 			}
 
+			// the responses are received in the order of the query, so the lines filtered out by the
+			// distinct, dedup and limit stages of a log query are removed before the limit is checked.
+			if casted, ok := data.resp.(*LokiResponse); ok && mergeStages != nil {
+				result, err := logql.ApplyMergeStages(mergeStages, casted.Data.Result, x.req.(*LokiRequest).Direction)
+				if err != nil {
+					return nil, err
+				}
+				casted.Data.Result = result
+			}
+
 			responses = append(responses, data.resp)
 
 			// see if we can exit early if a limit has been reached
@@ -184,6 +212,13 @@ func (h *splitByInterval) Do(ctx context.Context, r queryrangebase.Request) (que
 		return h.next.Do(ctx, r)
 	}
 
+	if req, ok := r.(*LokiRequest); ok {
+		if h.statsHandler != nil {
+			interval = h.adaptSplitInterval(ctx, tenantIDs, req, interval)
+		}
+		stats.FromContext(ctx).SetSplitInterval(interval)
+	}
+
 	intervals, err := h.splitter(r, interval)
 	if err != nil {
 		return nil, err
@@ -204,10 +239,17 @@ func (h *splitByInterval) Do(ctx context.Context, r queryrangebase.Request) (que
 		return h.next.Do(ctx, intervals[0])
 	}
 
-	var limit int64
+	var (
+		limit       int64
+		mergeStages log.Pipeline
+	)
 	switch req := r.(type) {
 	case *LokiRequest:
 		limit = int64(req.Limit)
+		// the lines filtered out by the distinct, dedup and limit stages of a log query can be spread across splits.
+		if mergeStages, err = newMergeStagesPipeline(req); err != nil {
+			return nil, err
+		}
 		if req.Direction == logproto.BACKWARD {
 			for i, j := 0, len(intervals)-1; i < j; i, j = i+1, j-1 {
 				intervals[i], intervals[j] = intervals[j], intervals[i]
@@ -231,13 +273,121 @@ func (h *splitByInterval) Do(ctx context.Context, r queryrangebase.Request) (que
 	maxSeriesCapture := func(id string) int { return h.limits.MaxQuerySeries(ctx, id) }
 	maxSeries := validation.SmallestPositiveIntPerTenant(tenantIDs, maxSeriesCapture)
 	maxParallelism := MinWeightedParallelism(ctx, tenantIDs, h.configs, h.limits, model.Time(r.GetStart()), model.Time(r.GetEnd()))
-	resps, err := h.Process(ctx, maxParallelism, limit, input, maxSeries)
+	resps, err := h.Process(ctx, maxParallelism, limit, input, maxSeries, mergeStages)
 	if err != nil {
 		return nil, err
 	}
 	return h.merger.MergeResponse(resps...)
 }
 
+// newMergeStagesPipeline returns the pipeline of the distinct, dedup and limit stages of a log query, nil if it has none.
+func newMergeStagesPipeline(req *LokiRequest) (log.Pipeline, error) {
+	// the query was already validated when the request was routed,
+	// a query that can't be parsed here has no merge stages to apply.
+	expr, err := syntax.ParseLogSelector(req.Query, false)
+	if err != nil {
+		return nil, nil
+	}
+	return logql.NewMergeStagesPipeline(expr, req.StartTs, req.EndTs)
+}
+
+// adaptSplitInterval returns the interval the request is split by, so that
+// each split reads about the target number of bytes of the tenants.
+// The bytes read by the request are resolved from the index stats, which are
+// only available for TSDB.
+func (h *splitByInterval) adaptSplitInterval(ctx context.Context, tenantIDs []string, r *LokiRequest, interval time.Duration) time.Duration {
+	targetBytes := validation.SmallestPositiveNonZeroIntPerTenant(tenantIDs, h.limits.QuerySplitTargetBytes)
+	if targetBytes == 0 {
+		return interval
+	}
+
+	sp, ctx := opentracing.StartSpanFromContext(ctx, "splitByInterval.adaptSplitInterval")
+	defer sp.Finish()
+	log := spanlogger.FromContext(ctx)
+	defer log.Finish()
+
+	maxRVDuration, maxOffset, err := maxRangeVectorAndOffsetDuration(r.GetQuery())
+	if err != nil {
+		return interval
+	}
+	start := model.Time(r.GetStart()).Add(-maxRVDuration).Add(-maxOffset)
+	end := model.Time(r.GetEnd()).Add(-maxOffset)
+	if conf, err := ShardingConfigs(h.configs).ValidRange(int64(start), int64(end)); err != nil || conf.IndexType != config.TSDBType {
+		return interval
+	}
+
+	expr, err := syntax.ParseExpr(r.GetQuery())
+	if err != nil {
+		return interval
+	}
+	matcherGroups, err := syntax.MatcherGroups(expr)
+	if err != nil {
+		return interval
+	}
+	// If there are zero matchers groups, we'll inject one to query everything
+	if len(matcherGroups) == 0 {
+		matcherGroups = append(matcherGroups, syntax.MatcherRange{})
+	}
+
+	const maxConcurrentIndexReq = 10
+	matcherStats, err := getStatsForMatchers(ctx, log, h.statsHandler, model.Time(r.GetStart()), model.Time(r.GetEnd()), matcherGroups, maxConcurrentIndexReq, 0)
+	if err != nil {
+		level.Warn(log).Log("msg", "failed to get index stats, splitting by the configured interval", "err", err)
+		return interval
+	}
+	bytes := indexStats.MergeStats(matcherStats...).Bytes
+
+	adapted := adaptiveSplitInterval(interval, r.EndTs.Sub(r.StartTs), bytes, targetBytes)
+	level.Debug(log).Log(
+		"msg", "adapted split interval",
+		"total_bytes", strings.Replace(humanize.Bytes(bytes), " ", "", 1),
+		"target_bytes", strings.Replace(humanize.Bytes(uint64(targetBytes)), " ", "", 1),
+		"interval", interval,
+		"adapted_interval", adapted,
+	)
+	return adapted
+}
+
+// adaptiveSplitInterval multiplies or divides the interval by a power of two,
+// so that splitting a query of the given length and number of bytes yields
+// splits of at most targetBytes each, assuming the bytes are spread evenly over
+// time. The interval is never reduced below minAdaptiveSplitInterval, and is
+// reduced to it instead of to an odd fraction of it if it divides the interval.
+// Using power of two multiples and divisors of the configured interval keeps
+// the boundaries of the splits aligned with the ones of the configured interval,
+// which are used by the results caches.
+func adaptiveSplitInterval(interval, length time.Duration, bytes uint64, targetBytes int) time.Duration {
+	if length <= 0 || targetBytes <= 0 {
+		return interval
+	}
+
+	// the length of a split reading targetBytes.
+	target := length
+	if bytes > uint64(targetBytes) {
+		target = time.Duration(float64(length) * float64(targetBytes) / float64(bytes))
+	}
+
+	lower := minAdaptiveSplitInterval
+	if interval < lower {
+		lower = interval
+	}
+
+	adapted := interval
+	for adapted > target && adapted > lower {
+		if adapted/2 < lower {
+			if interval%lower == 0 {
+				adapted = lower
+			}
+			break
+		}
+		adapted /= 2
+	}
+	for adapted*2 <= target {
+		adapted *= 2
+	}
+	return adapted
+}
+
 func splitByTime(req queryrangebase.Request, interval time.Duration) ([]queryrangebase.Request, error) {
 	var reqs []queryrangebase.Request
 
@@ -315,18 +465,27 @@ func maxRangeVectorAndOffsetDuration(q string) (time.Duration, time.Duration, er
 		return 0, 0, nil
 	}
 
-	var maxRVDuration, maxOffset time.Duration
+	var maxRVDuration, maxOffset, maxSubqueryRange, maxSubqueryOffset time.Duration
 	expr.Walk(func(e interface{}) {
-		if r, ok := e.(*syntax.LogRange); ok {
+		switch r := e.(type) {
+		case *syntax.LogRange:
 			if r.Interval > maxRVDuration {
 				maxRVDuration = r.Interval
 			}
 			if r.Offset > maxOffset {
 				maxOffset = r.Offset
 			}
+		case *syntax.SubqueryExpr:
+			if r.Range > maxSubqueryRange {
+				maxSubqueryRange = r.Range
+			}
+			if r.Offset > maxSubqueryOffset {
+				maxSubqueryOffset = r.Offset
+			}
 		}
 	})
-	return maxRVDuration, maxOffset, nil
+	// subqueries select the data of their range vectors over the range of the subquery.
+	return maxRVDuration + maxSubqueryRange, maxOffset + maxSubqueryOffset, nil
 }
 
 // reduceSplitIntervalForRangeVector reduces the split interval for a range query based on the duration of the range vector.
@@ -352,6 +511,10 @@ func splitMetricByTime(r queryrangebase.Request, interval time.Duration) ([]quer
 
 	lokiReq := r.(*LokiRequest)
 
+	if cal := lokiReq.calendar(); cal != nil {
+		return splitMetricByCalendar(lokiReq, cal, interval), nil
+	}
+
 	// step align start and end time of the query. Start time is rounded down and end time is rounded up.
 	stepNs := r.GetStep() * 1e6
 	startNs := lokiReq.StartTs.UnixNano()
@@ -403,6 +566,32 @@ func splitMetricByTime(r queryrangebase.Request, interval time.Duration) ([]quer
 	return reqs, nil
 }
 
+// splitMetricByCalendar splits a metric query whose steps are aligned to the calendar into queries over
+// the consecutive steps within the same split interval, i.e. a query per step when the steps are longer than the interval.
+func splitMetricByCalendar(r *LokiRequest, cal *logql.Calendar, interval time.Duration) []queryrangebase.Request {
+	var reqs []queryrangebase.Request
+
+	// like for fixed steps, the start is rounded down and the end is rounded up to the steps.
+	startNs, endNs := cal.Floor(r.StartTs).UnixNano(), cal.Ceil(r.EndTs).UnixNano()
+	for startNs <= endNs {
+		lastNs := startNs
+		for next := cal.Next(lastNs); next <= endNs && sameInterval(startNs, next, interval); next = cal.Next(next) {
+			lastNs = next
+		}
+		reqs = append(reqs, r.WithStartEndTime(time.Unix(0, startNs), time.Unix(0, lastNs)))
+		startNs = cal.Next(lastNs)
+	}
+	return reqs
+}
+
+// sameInterval tells whether the timestamps a and b in nanoseconds fall in the same split interval.
+func sameInterval(a, b int64, interval time.Duration) bool {
+	if interval <= 0 {
+		return true
+	}
+	return a/interval.Nanoseconds() == b/interval.Nanoseconds()
+}
+
 // Round up to the step before the next interval boundary.
 func nextIntervalBoundary(t time.Time, step int64, interval time.Duration) time.Time {
 	stepNs := step * 1e6
@@ -17,6 +17,7 @@ import (
 
 	"github.com/grafana/loki/pkg/loghttp"
 	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/logql"
 	"github.com/grafana/loki/pkg/logqlmodel/stats"
 	"github.com/grafana/loki/pkg/querier/queryrange/queryrangebase"
 	"github.com/grafana/loki/pkg/storage/config"
@@ -605,6 +606,51 @@ func Test_splitMetricQuery(t *testing.T) {
 	}
 }
 
+func Test_splitMetricQuery_Calendar(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	require.NoError(t, err)
+	day := func(d int) time.Time { return time.Date(2023, 3, d, 0, 0, 0, 0, paris) }
+
+	input := (&LokiRequest{
+		StartTs: time.Date(2023, 3, 25, 12, 0, 0, 0, paris),
+		EndTs:   time.Date(2023, 3, 27, 12, 0, 0, 0, paris),
+		Step:    86400000,
+		Query:   `count_over_time({app="foo"}[1d])`,
+	}).WithCalendar(&logql.Calendar{Unit: logql.CalendarDay, Location: paris})
+
+	for _, tc := range []struct {
+		name     string
+		interval time.Duration
+		expected [][2]time.Time
+	}{
+		{
+			// the steps are longer than the interval therefore we split per step.
+			name:     "per step",
+			interval: time.Hour,
+			expected: [][2]time.Time{{day(25), day(25)}, {day(26), day(26)}, {day(27), day(27)}, {day(28), day(28)}},
+		},
+		{
+			// all the steps fall in the same 30 days since the epoch.
+			name:     "per interval",
+			interval: 30 * 24 * time.Hour,
+			expected: [][2]time.Time{{day(25), day(28)}},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			splits, err := splitMetricByTime(input, tc.interval)
+			require.NoError(t, err)
+			require.Len(t, splits, len(tc.expected))
+			for i, s := range splits {
+				s := s.(*LokiRequest)
+				require.True(t, tc.expected[i][0].Equal(s.StartTs), "start of split %d: %s", i, s.StartTs)
+				require.True(t, tc.expected[i][1].Equal(s.EndTs), "end of split %d: %s", i, s.EndTs)
+				require.Equal(t, input.Calendar, s.Calendar)
+				require.Equal(t, input.TimeZone, s.TimeZone)
+			}
+		})
+	}
+}
+
 func Test_splitByInterval_Do(t *testing.T) {
 	ctx := user.InjectOrgID(context.Background(), "1")
 	next := queryrangebase.HandlerFunc(func(_ context.Context, r queryrangebase.Request) (queryrangebase.Response, error) {
@@ -634,6 +680,7 @@ func Test_splitByInterval_Do(t *testing.T) {
 		DefaultCodec,
 		splitByTime,
 		nilMetrics,
+		nil,
 	).Wrap(next)
 
 	tests := []struct {
@@ -780,6 +827,137 @@ func Test_splitByInterval_Do(t *testing.T) {
 	}
 }
 
+func Test_splitByInterval_DoMergeStages(t *testing.T) {
+	ctx := user.InjectOrgID(context.Background(), "1")
+	next := queryrangebase.HandlerFunc(func(_ context.Context, r queryrangebase.Request) (queryrangebase.Response, error) {
+		return &LokiResponse{
+			Status:    loghttp.QueryStatusSuccess,
+			Direction: r.(*LokiRequest).Direction,
+			Limit:     r.(*LokiRequest).Limit,
+			Version:   uint32(loghttp.VersionV1),
+			Data: LokiData{
+				ResultType: loghttp.ResultTypeStream,
+				Result: []logproto.Stream{
+					{
+						Labels: `{foo="bar", level="debug"}`,
+						Entries: []logproto.Entry{
+							{Timestamp: time.Unix(0, r.(*LokiRequest).StartTs.UnixNano()), Line: fmt.Sprintf("%d", r.(*LokiRequest).StartTs.UnixNano())},
+						},
+					},
+				},
+			},
+		}, nil
+	})
+
+	l := WithSplitByLimits(fakeLimits{maxQueryParallelism: 1}, time.Hour)
+	split := SplitByIntervalMiddleware(
+		testSchemas,
+		l,
+		DefaultCodec,
+		splitByTime,
+		nilMetrics,
+		nil,
+	).Wrap(next)
+
+	// each split keeps its own line, only the first line across all splits is kept after the merge.
+	res, err := split.Do(ctx, &LokiRequest{
+		StartTs:   time.Unix(0, 0),
+		EndTs:     time.Unix(0, (4 * time.Hour).Nanoseconds()),
+		Query:     `{foo="bar"} | distinct level`,
+		Limit:     1000,
+		Step:      1,
+		Direction: logproto.BACKWARD,
+		Path:      "/api/prom/query_range",
+	})
+	require.NoError(t, err)
+	require.Equal(t, []logproto.Stream{
+		{
+			Labels: `{foo="bar", level="debug"}`,
+			Entries: []logproto.Entry{
+				{Timestamp: time.Unix(0, 3*time.Hour.Nanoseconds()), Line: fmt.Sprintf("%d", 3*time.Hour.Nanoseconds())},
+			},
+		},
+	}, res.(*LokiResponse).Data.Result)
+
+	for _, tc := range []struct {
+		query string
+		want  []time.Duration
+	}{
+		{`{foo="bar"} | limit 3 by (level)`, []time.Duration{3 * time.Hour, 2 * time.Hour, time.Hour}},
+		// the range is sampled in intervals of 2^43ns, about 2h26m, aligned on the epoch.
+		{`{foo="bar"} | limit 3 by (level) sample`, []time.Duration{3 * time.Hour, 2 * time.Hour}},
+	} {
+		res, err := split.Do(ctx, &LokiRequest{
+			StartTs:   time.Unix(0, 0),
+			EndTs:     time.Unix(0, (4 * time.Hour).Nanoseconds()),
+			Query:     tc.query,
+			Limit:     1000,
+			Step:      1,
+			Direction: logproto.BACKWARD,
+			Path:      "/api/prom/query_range",
+		})
+		require.NoError(t, err)
+		entries := make([]logproto.Entry, 0, len(tc.want))
+		for _, ts := range tc.want {
+			entries = append(entries, logproto.Entry{Timestamp: time.Unix(0, ts.Nanoseconds()), Line: fmt.Sprintf("%d", ts.Nanoseconds())})
+		}
+		require.Equal(t, []logproto.Stream{{Labels: `{foo="bar", level="debug"}`, Entries: entries}}, res.(*LokiResponse).Data.Result, tc.query)
+	}
+}
+
+func Test_splitByInterval_DoMergeStagesBeforeLimit(t *testing.T) {
+	ctx := user.InjectOrgID(context.Background(), "1")
+	next := queryrangebase.HandlerFunc(func(_ context.Context, r queryrangebase.Request) (queryrangebase.Response, error) {
+		start := r.(*LokiRequest).StartTs.UnixNano()
+		return &LokiResponse{
+			Status:    loghttp.QueryStatusSuccess,
+			Direction: r.(*LokiRequest).Direction,
+			Limit:     r.(*LokiRequest).Limit,
+			Version:   uint32(loghttp.VersionV1),
+			Data: LokiData{
+				ResultType: loghttp.ResultTypeStream,
+				Result: []logproto.Stream{
+					{
+						Labels:  fmt.Sprintf(`{foo="bar", level="%d"}`, start),
+						Entries: []logproto.Entry{{Timestamp: time.Unix(0, start+1), Line: "split"}},
+					},
+					{
+						Labels:  `{foo="bar", level="debug"}`,
+						Entries: []logproto.Entry{{Timestamp: time.Unix(0, start), Line: "debug"}},
+					},
+				},
+			},
+		}, nil
+	})
+
+	l := WithSplitByLimits(fakeLimits{maxQueryParallelism: 1}, time.Hour)
+	split := SplitByIntervalMiddleware(
+		testSchemas,
+		l,
+		DefaultCodec,
+		splitByTime,
+		nilMetrics,
+		nil,
+	).Wrap(next)
+
+	// the lines filtered out by the distinct stage don't count towards the limit.
+	res, err := split.Do(ctx, &LokiRequest{
+		StartTs:   time.Unix(0, 0),
+		EndTs:     time.Unix(0, (4 * time.Hour).Nanoseconds()),
+		Query:     `{foo="bar"} | distinct level`,
+		Limit:     4,
+		Step:      1,
+		Direction: logproto.BACKWARD,
+		Path:      "/api/prom/query_range",
+	})
+	require.NoError(t, err)
+	var lines int
+	for _, stream := range res.(*LokiResponse).Data.Result {
+		lines += len(stream.Entries)
+	}
+	require.Equal(t, 4, lines)
+}
+
 func Test_series_splitByInterval_Do(t *testing.T) {
 	ctx := user.InjectOrgID(context.Background(), "1")
 	next := queryrangebase.HandlerFunc(func(_ context.Context, r queryrangebase.Request) (queryrangebase.Response, error) {
@@ -807,6 +985,7 @@ func Test_series_splitByInterval_Do(t *testing.T) {
 		DefaultCodec,
 		splitByTime,
 		nilMetrics,
+		nil,
 	).Wrap(next)
 
 	tests := []struct {
@@ -856,6 +1035,7 @@ func Test_seriesvolume_splitByInterval_Do(t *testing.T) {
 			DefaultCodec,
 			splitByTime,
 			nilMetrics,
+			nil,
 		).Wrap(next)
 	}
 
@@ -1017,6 +1197,7 @@ func Test_ExitEarly(t *testing.T) {
 		DefaultCodec,
 		splitByTime,
 		nilMetrics,
+		nil,
 	).Wrap(next)
 
 	req := &LokiRequest{
@@ -1099,6 +1280,7 @@ func Test_DoesntDeadlock(t *testing.T) {
 		DefaultCodec,
 		splitByTime,
 		nilMetrics,
+		nil,
 	).Wrap(next)
 
 	// split into n requests w/ n/2 limit, ensuring unused responses are cleaned up properly
@@ -1131,3 +1313,93 @@ func Test_DoesntDeadlock(t *testing.T) {
 	// Allow for 1% increase in goroutines
 	require.LessOrEqual(t, endingGoroutines, startingGoroutines*101/100)
 }
+
+func Test_adaptiveSplitInterval(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		interval    time.Duration
+		length      time.Duration
+		bytes       uint64
+		targetBytes int
+		expected    time.Duration
+	}{
+		{"no data", time.Hour, 24 * time.Hour, 0, 1 << 30, 16 * time.Hour},
+		{"sparse", time.Hour, 24 * time.Hour, 3 << 30, 1 << 30, 8 * time.Hour},
+		{"target", time.Hour, 24 * time.Hour, 24 << 30, 1 << 30, time.Hour},
+		{"dense", time.Hour, 24 * time.Hour, 96 << 30, 1 << 30, 15 * time.Minute},
+		{"very dense", time.Hour, 24 * time.Hour, 1 << 50, 1 << 30, time.Minute},
+		{"very dense two minutes", 2 * time.Minute, 24 * time.Hour, 1 << 50, 1 << 30, time.Minute},
+		{"very dense not a multiple of a minute", 90 * time.Second, 24 * time.Hour, 1 << 50, 1 << 30, 90 * time.Second},
+		{"small interval", 30 * time.Second, time.Hour, 1 << 50, 1 << 30, 30 * time.Second},
+		{"disabled", time.Hour, 24 * time.Hour, 1 << 50, 0, time.Hour},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, adaptiveSplitInterval(tc.interval, tc.length, tc.bytes, tc.targetBytes))
+		})
+	}
+}
+
+func Test_splitByInterval_AdaptiveInterval(t *testing.T) {
+	var (
+		mtx    sync.Mutex
+		splits []*LokiRequest
+	)
+	next := queryrangebase.HandlerFunc(func(_ context.Context, r queryrangebase.Request) (queryrangebase.Response, error) {
+		mtx.Lock()
+		defer mtx.Unlock()
+		splits = append(splits, r.(*LokiRequest))
+		return &LokiResponse{
+			Status:  loghttp.QueryStatusSuccess,
+			Limit:   r.(*LokiRequest).Limit,
+			Version: uint32(loghttp.VersionV1),
+			Data:    LokiData{ResultType: loghttp.ResultTypeStream},
+		}, nil
+	})
+
+	var bytes uint64
+	statsHandler := queryrangebase.HandlerFunc(func(_ context.Context, _ queryrangebase.Request) (queryrangebase.Response, error) {
+		return &IndexStatsResponse{Response: &logproto.IndexStatsResponse{Bytes: bytes}}, nil
+	})
+
+	req := &LokiRequest{
+		StartTs: time.Unix(0, 0),
+		EndTs:   time.Unix(0, (8 * time.Hour).Nanoseconds()),
+		Query:   `{foo="bar"}`,
+		Limit:   1000,
+		Path:    "/loki/api/v1/query_range",
+	}
+
+	for _, tc := range []struct {
+		name             string
+		schemas          []config.PeriodConfig
+		bytes            uint64
+		expectedSplits   int
+		expectedInterval time.Duration
+	}{
+		{"sparse", testSchemasTSDB, 1 << 20, 1, 8 * time.Hour},
+		{"dense", testSchemasTSDB, 16 << 30, 16, 30 * time.Minute},
+		{"no tsdb", testSchemas, 16 << 30, 8, time.Hour},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			splits, bytes = nil, tc.bytes
+			split := SplitByIntervalMiddleware(
+				tc.schemas,
+				fakeLimits{
+					maxQueryParallelism: 1,
+					splits:              map[string]time.Duration{"1": time.Hour},
+					splitTargetBytes:    1 << 30,
+				},
+				DefaultCodec,
+				splitByTime,
+				nilMetrics,
+				statsHandler,
+			).Wrap(next)
+
+			st, ctx := stats.NewContext(user.InjectOrgID(context.Background(), "1"))
+			_, err := split.Do(ctx, req)
+			require.NoError(t, err)
+			require.Len(t, splits, tc.expectedSplits)
+			require.Equal(t, tc.expectedInterval.Seconds(), st.Result(0, 0, 0).Summary.SplitInterval)
+		})
+	}
+}
@@ -17,7 +17,6 @@ import (
 	"github.com/pkg/errors"
 	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
 	"github.com/prometheus/prometheus/model/labels"
-	"github.com/prometheus/prometheus/model/rulefmt"
 	"gopkg.in/yaml.v3"
 
 	"github.com/grafana/dskit/tenant"
@@ -496,7 +495,7 @@ func (a *API) ListRules(w http.ResponseWriter, req *http.Request) {
 		rgs = fitlerRuleGroups(rgs, pr.Labels)
 	}
 
-	formatted := rgs.Formatted()
+	formatted := rgs.ParamFormatted()
 	marshalAndSend(formatted, w, logger)
 }
 
@@ -518,7 +517,7 @@ func (a *API) GetRuleGroup(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	formatted := rulespb.FromProto(rg)
+	formatted := rulespb.ParamFromProto(rg)
 	marshalAndSend(formatted, w, logger)
 }
 
@@ -541,14 +540,22 @@ func (a *API) CreateRuleGroup(w http.ResponseWriter, req *http.Request) {
 
 	level.Debug(logger).Log("msg", "attempting to unmarshal rulegroup", "group", string(payload))
 
-	rg := rulefmt.RuleGroup{}
-	err = yaml.Unmarshal(payload, &rg)
+	prg := rulespb.ParamRuleGroup{}
+	err = yaml.Unmarshal(payload, &prg)
 	if err != nil {
 		level.Error(logger).Log("msg", "unable to unmarshal rule group payload", "err", err.Error())
 		http.Error(w, ErrBadRuleGroup.Error(), http.StatusBadRequest)
 		return
 	}
 
+	// the group is validated with the parameters of its rules bound, but stored with them unbound.
+	rg, err := prg.Bind()
+	if err != nil {
+		level.Error(logger).Log("msg", "unable to bind rule group parameters", "err", err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	errs := a.ruler.manager.ValidateRuleGroup(rg)
 	if len(errs) > 0 {
 		e := []string{}
@@ -580,7 +587,7 @@ func (a *API) CreateRuleGroup(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	rgProto := rulespb.ToProto(pr.UserID, pr.Namespace, rg)
+	rgProto := rulespb.ParamToProto(pr.UserID, pr.Namespace, prg)
 
 	level.Debug(logger).Log("msg", "attempting to store rulegroup", "group", rgProto.String())
 	err = a.store.SetRuleGroup(req.Context(), pr.UserID, pr.Namespace, rgProto)
@@ -21,6 +21,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"gopkg.in/yaml.v3"
 
+	"github.com/grafana/loki/pkg/logproto"
 	"github.com/grafana/loki/pkg/ruler/rulespb"
 )
 
@@ -406,6 +407,34 @@ rules:
 `,
 			output: "name: test\ninterval: 15s\nlimit: 10\nrules:\n    - record: up_rule\n      expr: up{}\n    - alert: up_alert\n      expr: sum(up{}) > 1\n      for: 30s\n      labels:\n        test: test\n      annotations:\n        test: test\n",
 		},
+		{
+			name:   "with a a valid rules file with params",
+			status: 202,
+			input: `
+name: test
+interval: 15s
+rules:
+- alert: up_alert
+  expr: sum(count_over_time({job=$job}[5m])) > $threshold
+  params:
+    job: api
+    threshold: 1
+`,
+			output: "name: test\ninterval: 15s\nrules:\n    - alert: up_alert\n      expr: sum(count_over_time({job=$job}[5m])) > $threshold\n      params:\n        job: api\n        threshold: \"1\"\n",
+		},
+		{
+			name:   "with an unbound param",
+			status: 400,
+			input: `
+name: test
+rules:
+- alert: up_alert
+  expr: sum(count_over_time({job=$job}[5m])) > $threshold
+  params:
+    job: api
+`,
+			err: errors.New("could not bind the parameters of the expression of rule 'up_alert' in group 'test': parse error at line 1, col 40: parameter $threshold is not bound"),
+		},
 	}
 
 	for _, tt := range tc {
@@ -435,6 +464,39 @@ rules:
 	}
 }
 
+func TestRuler_CreateRuleGroupWithParams(t *testing.T) {
+	cfg := defaultRulerConfig(t, newMockRuleStore(make(map[string]rulespb.RuleGroupList)))
+
+	r := newTestRuler(t, cfg)
+	defer services.StopAndAwaitTerminated(context.Background(), r) //nolint:errcheck
+
+	a := NewAPI(r, r.store, log.NewNopLogger())
+
+	router := mux.NewRouter()
+	router.Path("/api/v1/rules/{namespace}").Methods(http.MethodPost).HandlerFunc(a.CreateRuleGroup)
+
+	input := `
+name: test
+rules:
+- alert: up_alert
+  expr: sum(count_over_time({job=$job}[5m])) > $threshold
+  params:
+    job: api
+    threshold: 1
+`
+	req := requestFor(t, http.MethodPost, "https://localhost:8080/api/v1/rules/namespace", strings.NewReader(input), "user1")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusAccepted, w.Code)
+
+	// The group is stored with the parameters of its rules, which are bound when the rules are loaded.
+	rg, err := r.store.GetRuleGroup(context.Background(), "user1", "namespace", "test")
+	require.NoError(t, err)
+	require.Equal(t, "sum(count_over_time({job=$job}[5m])) > $threshold", rg.Rules[0].Expr)
+	require.Equal(t, map[string]string{"job": "api", "threshold": "1"}, logproto.FromLabelAdaptersToLabels(rg.Rules[0].Params).Map())
+	require.Equal(t, `(sum(count_over_time({job="api"}[5m])) > 1)`, rulespb.FromProto(rg).Rules[0].Expr.Value)
+}
+
 func TestRuler_DeleteNamespace(t *testing.T) {
 	cfg := defaultRulerConfig(t, newMockRuleStore(mockRulesNamespaces))
 
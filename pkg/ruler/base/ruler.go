@@ -1064,7 +1064,7 @@ func (r *Ruler) ListAllRules(w http.ResponseWriter, req *http.Request) {
 		if err := r.store.LoadRuleGroups(ctx, userRules); err != nil {
 			return errors.Wrapf(err, "failed to load ruler config for user %s", userID)
 		}
-		data := map[string]map[string][]rulefmt.RuleGroup{userID: userRules[userID].Formatted()}
+		data := map[string]map[string][]rulespb.ParamRuleGroup{userID: userRules[userID].ParamFormatted()}
 
 		select {
 		case iter <- data:
@@ -1632,9 +1632,9 @@ func TestRuler_ListAllRules(t *testing.T) {
 	require.Equal(t, http.StatusOK, resp.StatusCode)
 	require.Equal(t, "application/yaml", resp.Header.Get("Content-Type"))
 
-	gs := make(map[string]map[string][]rulefmt.RuleGroup) // user:namespace:[]rulefmt.RuleGroup
+	gs := make(map[string]map[string][]rulespb.ParamRuleGroup) // user:namespace:[]rulespb.ParamRuleGroup
 	for userID := range mockRules {
-		gs[userID] = mockRules[userID].Formatted()
+		gs[userID] = mockRules[userID].ParamFormatted()
 	}
 	expectedResponse, err := yaml.Marshal(gs)
 	require.NoError(t, err)
@@ -12,6 +12,7 @@ import (
 	"gopkg.in/yaml.v3"
 
 	"github.com/grafana/loki/pkg/logql/syntax"
+	"github.com/grafana/loki/pkg/ruler/rulespb"
 )
 
 type GroupLoader struct{}
@@ -39,14 +40,14 @@ func (g GroupLoader) Load(identifier string) (*rulefmt.RuleGroups, []error) {
 
 func (GroupLoader) parseRules(content []byte) (*rulefmt.RuleGroups, []error) {
 	var (
-		groups rulefmt.RuleGroups
-		errs   []error
+		paramGroups rulespb.ParamRuleGroups
+		errs        []error
 	)
 
 	decoder := yaml.NewDecoder(bytes.NewReader(content))
 	decoder.KnownFields(true)
 
-	if err := decoder.Decode(&groups); err != nil {
+	if err := decoder.Decode(&paramGroups); err != nil {
 		errs = append(errs, err)
 	}
 
@@ -54,6 +55,12 @@ func (GroupLoader) parseRules(content []byte) (*rulefmt.RuleGroups, []error) {
 		return nil, errs
 	}
 
+	// the parameters of the rules are bound as the rules are loaded.
+	groups, err := paramGroups.Bind()
+	if err != nil {
+		return nil, []error{err}
+	}
+
 	return &groups, ValidateGroups(groups.Groups...)
 }
 
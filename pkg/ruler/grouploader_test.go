@@ -239,6 +239,19 @@ groups:
             severity: page
         annotations:
             's.ummary': High request latency
+`,
+		},
+		{
+			desc:  "fail unbound param",
+			match: "parameter $needle is not bound",
+			data: `
+groups:
+  - name: grp1
+    rules:
+      - alert: HighErrorRate
+        expr: sum(rate({app=$app} |= $needle [5m])) > 10
+        params:
+          app: api
 `,
 		},
 	} {
@@ -268,6 +281,27 @@ groups:
 	}
 }
 
+func Test_GroupLoaderParams(t *testing.T) {
+	var loader GroupLoader
+	groups, errs := loader.parseRules([]byte(`
+groups:
+  - name: grp1
+    rules:
+      - alert: HighErrorRate
+        expr: sum(rate({app=$app} |= $needle [5m])) > $threshold
+        params:
+          app: api
+          needle: 'msg="error"'
+          threshold: "10"
+      - record: app:errors:rate5m
+        expr: sum(rate({app="api"} |= "error" [5m]))
+`))
+	require.Nil(t, errs)
+	require.Len(t, groups.Groups[0].Rules, 2)
+	require.Equal(t, `(sum(rate({app="api"} |= "msg=\"error\""[5m])) > 10)`, groups.Groups[0].Rules[0].Expr.Value)
+	require.Equal(t, `sum(rate({app="api"} |= "error" [5m]))`, groups.Groups[0].Rules[1].Expr.Value)
+}
+
 func TestCachingGroupLoader(t *testing.T) {
 	t.Run("it caches rules as they are loaded from the underlying loader", func(t *testing.T) {
 		l := newFakeGroupLoader()
@@ -9,6 +9,7 @@ import (
 	"gopkg.in/yaml.v3"
 
 	"github.com/grafana/loki/pkg/logproto" //lint:ignore faillint allowed to import other protobuf
+	"github.com/grafana/loki/pkg/logql/syntax"
 )
 
 // ToProto transforms a formatted prometheus rulegroup to a rule group protobuf
@@ -40,7 +41,7 @@ func formattedRuleToProto(rls []rulefmt.RuleNode) []*RuleDesc {
 	return rules
 }
 
-// FromProto generates a rulefmt RuleGroup
+// FromProto generates a rulefmt RuleGroup with the parameters of its expressions bound
 func FromProto(rg *RuleGroupDesc) rulefmt.RuleGroup {
 	formattedRuleGroup := rulefmt.RuleGroup{
 		Name:     rg.GetName(),
@@ -51,7 +52,7 @@ func FromProto(rg *RuleGroupDesc) rulefmt.RuleGroup {
 
 	for i, rl := range rg.GetRules() {
 		exprNode := yaml.Node{}
-		exprNode.SetString(rl.GetExpr())
+		exprNode.SetString(bindParams(rl))
 
 		newRule := rulefmt.RuleNode{
 			Expr:        exprNode,
@@ -75,3 +76,16 @@ func FromProto(rg *RuleGroupDesc) rulefmt.RuleGroup {
 
 	return formattedRuleGroup
 }
+
+// bindParams returns the expression of the rule with its parameters bound. The expression is
+// returned unbound if they can't be bound, so that loading the rule reports the error.
+func bindParams(rl *RuleDesc) string {
+	if len(rl.Params) == 0 {
+		return rl.GetExpr()
+	}
+	expr, err := syntax.BindParams(rl.GetExpr(), logproto.FromLabelAdaptersToLabels(rl.Params).Map())
+	if err != nil {
+		return rl.GetExpr()
+	}
+	return expr
+}
@@ -19,3 +19,13 @@ func (l RuleGroupList) Formatted() map[string][]rulefmt.RuleGroup {
 	}
 	return ruleMap
 }
+
+// ParamFormatted returns the rule group list as a set of rule groups mapped by namespace,
+// whose rules keep their expressions unbound along with the values of their parameters
+func (l RuleGroupList) ParamFormatted() map[string][]ParamRuleGroup {
+	ruleMap := map[string][]ParamRuleGroup{}
+	for _, g := range l {
+		ruleMap[g.Namespace] = append(ruleMap[g.Namespace], ParamFromProto(g))
+	}
+	return ruleMap
+}
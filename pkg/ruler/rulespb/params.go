@@ -0,0 +1,117 @@
+package rulespb
+
+import (
+	"github.com/pkg/errors"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/rulefmt"
+
+	"github.com/grafana/loki/pkg/logproto" //lint:ignore faillint allowed to import other protobuf
+	"github.com/grafana/loki/pkg/logql/syntax"
+)
+
+// ParamRuleGroups is a set of rule groups whose rules can set the values of the parameters of their expressions.
+type ParamRuleGroups struct {
+	Groups []ParamRuleGroup `yaml:"groups"`
+}
+
+// ParamRuleGroup is a rulefmt.RuleGroup whose rules can set the values of the parameters of their expressions.
+type ParamRuleGroup struct {
+	Name     string          `yaml:"name"`
+	Interval model.Duration  `yaml:"interval,omitempty"`
+	Limit    int             `yaml:"limit,omitempty"`
+	Rules    []ParamRuleNode `yaml:"rules"`
+}
+
+// ParamRuleNode is a rulefmt.RuleNode with the values of the parameters of its expression, e.g.
+//
+//	expr: sum(rate({app=$app} |= $needle [5m])) > 10
+//	params:
+//	  app: api
+//	  needle: error
+type ParamRuleNode struct {
+	rulefmt.RuleNode `yaml:",inline"`
+	Params           map[string]string `yaml:"params,omitempty"`
+}
+
+// Bind returns the groups with the parameters of their expressions bound.
+func (g ParamRuleGroups) Bind() (rulefmt.RuleGroups, error) {
+	groups := rulefmt.RuleGroups{Groups: make([]rulefmt.RuleGroup, 0, len(g.Groups))}
+	for _, grp := range g.Groups {
+		bound, err := grp.Bind()
+		if err != nil {
+			return rulefmt.RuleGroups{}, err
+		}
+		groups.Groups = append(groups.Groups, bound)
+	}
+	return groups, nil
+}
+
+// Bind returns the group with the parameters of its expressions bound.
+func (g ParamRuleGroup) Bind() (rulefmt.RuleGroup, error) {
+	group := rulefmt.RuleGroup{
+		Name:     g.Name,
+		Interval: g.Interval,
+		Limit:    g.Limit,
+		Rules:    make([]rulefmt.RuleNode, 0, len(g.Rules)),
+	}
+	for _, r := range g.Rules {
+		rule := r.RuleNode
+		if len(r.Params) > 0 {
+			expr, err := syntax.BindParams(rule.Expr.Value, r.Params)
+			if err != nil {
+				name := rule.Alert.Value
+				if name == "" {
+					name = rule.Record.Value
+				}
+				return rulefmt.RuleGroup{}, errors.Wrapf(err, "could not bind the parameters of the expression of rule '%s' in group '%s'", name, g.Name)
+			}
+			rule.Expr.SetString(expr)
+		}
+		group.Rules = append(group.Rules, rule)
+	}
+	return group, nil
+}
+
+// ParamToProto transforms a rule group whose rules can set the values of the parameters of their
+// expressions to a rule group protobuf, keeping the expressions unbound.
+func ParamToProto(user string, namespace string, g ParamRuleGroup) *RuleGroupDesc {
+	rules := make([]rulefmt.RuleNode, len(g.Rules))
+	for i := range g.Rules {
+		rules[i] = g.Rules[i].RuleNode
+	}
+
+	rg := ToProto(user, namespace, rulefmt.RuleGroup{
+		Name:     g.Name,
+		Interval: g.Interval,
+		Limit:    g.Limit,
+		Rules:    rules,
+	})
+	for i := range g.Rules {
+		if len(g.Rules[i].Params) > 0 {
+			rg.Rules[i].Params = logproto.FromLabelsToLabelAdapters(labels.FromMap(g.Rules[i].Params))
+		}
+	}
+	return rg
+}
+
+// ParamFromProto generates a rule group whose rules keep their expressions unbound along with the
+// values of their parameters.
+func ParamFromProto(rg *RuleGroupDesc) ParamRuleGroup {
+	formatted := FromProto(rg)
+	group := ParamRuleGroup{
+		Name:     formatted.Name,
+		Interval: formatted.Interval,
+		Limit:    formatted.Limit,
+		Rules:    make([]ParamRuleNode, len(formatted.Rules)),
+	}
+
+	for i, rl := range rg.GetRules() {
+		group.Rules[i].RuleNode = formatted.Rules[i]
+		if len(rl.Params) > 0 {
+			group.Rules[i].Expr.SetString(rl.GetExpr())
+			group.Rules[i].Params = logproto.FromLabelAdaptersToLabels(rl.Params).Map()
+		}
+	}
+	return group
+}
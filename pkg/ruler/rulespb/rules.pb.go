@@ -9,9 +9,9 @@ import (
 	proto "github.com/gogo/protobuf/proto"
 	github_com_gogo_protobuf_types "github.com/gogo/protobuf/types"
 	types "github.com/gogo/protobuf/types"
-	_ "github.com/golang/protobuf/ptypes/duration"
 	_ "github.com/grafana/loki/pkg/logproto"
 	github_com_grafana_loki_pkg_logproto "github.com/grafana/loki/pkg/logproto"
+	_ "google.golang.org/protobuf/types/known/durationpb"
 	io "io"
 	math "math"
 	math_bits "math/bits"
@@ -136,6 +136,8 @@ type RuleDesc struct {
 	For         time.Duration                                       `protobuf:"bytes,4,opt,name=for,proto3,stdduration" json:"for"`
 	Labels      []github_com_grafana_loki_pkg_logproto.LabelAdapter `protobuf:"bytes,5,rep,name=labels,proto3,customtype=github.com/grafana/loki/pkg/logproto.LabelAdapter" json:"labels"`
 	Annotations []github_com_grafana_loki_pkg_logproto.LabelAdapter `protobuf:"bytes,6,rep,name=annotations,proto3,customtype=github.com/grafana/loki/pkg/logproto.LabelAdapter" json:"annotations"`
+	// The values of the parameters of the expression, bound when the rule is loaded.
+	Params []github_com_grafana_loki_pkg_logproto.LabelAdapter `protobuf:"bytes,13,rep,name=params,proto3,customtype=github.com/grafana/loki/pkg/logproto.LabelAdapter" json:"params"`
 }
 
 func (m *RuleDesc) Reset()      { *m = RuleDesc{} }
@@ -206,39 +208,39 @@ func init() {
 func init() { proto.RegisterFile("pkg/ruler/rulespb/rules.proto", fileDescriptor_dd3ef3757f506fba) }
 
 var fileDescriptor_dd3ef3757f506fba = []byte{
-	// 501 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x53, 0x41, 0x6f, 0xd3, 0x30,
-	0x18, 0x8d, 0xdb, 0x34, 0x4b, 0x5c, 0x4d, 0x54, 0x56, 0x85, 0xd2, 0x01, 0x6e, 0x35, 0x09, 0xa9,
-	0x5c, 0x12, 0x31, 0xc4, 0x81, 0x13, 0x5a, 0x35, 0x09, 0xa9, 0xda, 0x01, 0xe5, 0xc8, 0x05, 0x39,
-	0xa9, 0x6b, 0xa2, 0xb9, 0x71, 0xe4, 0x24, 0x13, 0xbd, 0xf1, 0x13, 0xb8, 0x20, 0xf1, 0x13, 0xf8,
-	0x29, 0x3b, 0xf6, 0x38, 0x71, 0x18, 0x34, 0xbd, 0x70, 0x63, 0x3f, 0x01, 0xd9, 0x4e, 0xc6, 0x00,
-	0x09, 0x71, 0xe1, 0x12, 0x7f, 0xcf, 0xcf, 0x9f, 0xdf, 0xfb, 0x5e, 0x12, 0xf8, 0x20, 0x3f, 0x63,
-	0xa1, 0xac, 0x38, 0x95, 0xfa, 0x59, 0xe4, 0xb1, 0x59, 0x83, 0x5c, 0x8a, 0x52, 0xa0, 0x9e, 0x06,
-	0x07, 0x43, 0x26, 0x98, 0xd0, 0x3b, 0xa1, 0xaa, 0x0c, 0x79, 0x30, 0x62, 0x42, 0x30, 0x4e, 0x43,
-	0x8d, 0xe2, 0x6a, 0x19, 0x92, 0x6c, 0xdd, 0x50, 0xf8, 0x77, 0x6a, 0x51, 0x49, 0x52, 0xa6, 0x22,
-	0x6b, 0xf8, 0x7b, 0x4a, 0x96, 0x0b, 0x66, 0xee, 0x6c, 0x0b, 0x43, 0x1e, 0x7e, 0xe8, 0xc0, 0xfd,
-	0xa8, 0xe2, 0xf4, 0x85, 0x14, 0x55, 0x7e, 0x42, 0x8b, 0x04, 0x21, 0x68, 0x67, 0x64, 0x45, 0x7d,
-	0x30, 0x01, 0x53, 0x2f, 0xd2, 0x35, 0xba, 0x0f, 0x3d, 0xb5, 0x16, 0x39, 0x49, 0xa8, 0xdf, 0xd1,
-	0xc4, 0xcf, 0x0d, 0xf4, 0x1c, 0xba, 0x69, 0x56, 0x52, 0x79, 0x4e, 0xb8, 0xdf, 0x9d, 0x80, 0x69,
-	0xff, 0x68, 0x14, 0x18, 0x4f, 0x41, 0xeb, 0x29, 0x38, 0x69, 0x3c, 0xcd, 0xdc, 0x8b, 0xab, 0xb1,
-	0xf5, 0xf1, 0xcb, 0x18, 0x44, 0x37, 0x4d, 0xe8, 0x21, 0x34, 0xb3, 0xfb, 0xf6, 0xa4, 0x3b, 0xed,
-	0x1f, 0xdd, 0x09, 0x4c, 0x2c, 0xca, 0x97, 0xb2, 0x14, 0x19, 0x56, 0x39, 0xab, 0x0a, 0x2a, 0x7d,
-	0xc7, 0x38, 0x53, 0x35, 0x0a, 0xe0, 0x9e, 0xc8, 0xd5, 0xc5, 0x85, 0xef, 0xe9, 0xe6, 0xe1, 0x1f,
-	0xd2, 0xc7, 0xd9, 0x3a, 0x6a, 0x0f, 0xa1, 0x21, 0xec, 0xf1, 0x74, 0x95, 0x96, 0x3e, 0x9c, 0x80,
-	0x69, 0x37, 0x32, 0x60, 0x6e, 0xbb, 0xbd, 0x81, 0x33, 0xb7, 0xdd, 0xbd, 0x81, 0x3b, 0xb7, 0x5d,
-	0x77, 0xe0, 0x1d, 0x7e, 0xef, 0x40, 0xb7, 0xd5, 0x57, 0xc2, 0xf4, 0x6d, 0x2e, 0xdb, 0x48, 0x54,
-	0x8d, 0xee, 0x42, 0x47, 0xd2, 0x44, 0xc8, 0x45, 0x93, 0x47, 0x83, 0x94, 0x00, 0xe1, 0x54, 0x96,
-	0x3a, 0x09, 0x2f, 0x32, 0x00, 0x3d, 0x85, 0xdd, 0xa5, 0x90, 0xbe, 0xfd, 0xef, 0xe9, 0xa8, 0xf3,
-	0x88, 0x43, 0x87, 0x93, 0x98, 0xf2, 0xc2, 0xef, 0xe9, 0xe1, 0x46, 0xc1, 0xcd, 0xeb, 0x3b, 0xa5,
-	0x8c, 0x24, 0xeb, 0x53, 0xc5, 0xbe, 0x24, 0xa9, 0x9c, 0x3d, 0x53, 0x9d, 0x9f, 0xaf, 0xc6, 0x8f,
-	0x59, 0x5a, 0xbe, 0xa9, 0xe2, 0x20, 0x11, 0xab, 0x90, 0x49, 0xb2, 0x24, 0x19, 0x09, 0xb9, 0x38,
-	0x4b, 0xc3, 0xdb, 0x5f, 0x41, 0xa0, 0xfb, 0x8e, 0x17, 0x24, 0x2f, 0xa9, 0x8c, 0x1a, 0x0d, 0x74,
-	0x0e, 0xfb, 0x24, 0xcb, 0x44, 0x49, 0x4c, 0x9e, 0xce, 0x7f, 0x94, 0xbc, 0x2d, 0xa4, 0x73, 0xdf,
-	0x9f, 0xbd, 0xde, 0x6c, 0xb1, 0x75, 0xb9, 0xc5, 0xd6, 0xf5, 0x16, 0x83, 0x77, 0x35, 0x06, 0x9f,
-	0x6a, 0x0c, 0x2e, 0x6a, 0x0c, 0x36, 0x35, 0x06, 0x5f, 0x6b, 0x0c, 0xbe, 0xd5, 0xd8, 0xba, 0xae,
-	0x31, 0x78, 0xbf, 0xc3, 0xd6, 0x66, 0x87, 0xad, 0xcb, 0x1d, 0xb6, 0x5e, 0x3d, 0xfa, 0x9b, 0xf6,
-	0x2f, 0xff, 0x5a, 0xec, 0x68, 0x1f, 0x4f, 0x7e, 0x04, 0x00, 0x00, 0xff, 0xff, 0x6f, 0xf3, 0x96,
-	0x82, 0x87, 0x03, 0x00, 0x00,
+	// 512 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x53, 0x41, 0x6b, 0xdb, 0x3e,
+	0x1c, 0xb5, 0x1a, 0xc7, 0x75, 0x14, 0xc2, 0x3f, 0x88, 0xf0, 0x47, 0xe9, 0x36, 0x25, 0x14, 0x06,
+	0xd9, 0x45, 0x66, 0x1d, 0x3b, 0xec, 0x34, 0x1a, 0x0a, 0x83, 0xd0, 0xc3, 0xf0, 0x71, 0x97, 0xa1,
+	0x38, 0x8a, 0x67, 0xaa, 0x58, 0x46, 0xb6, 0xcb, 0x72, 0xdb, 0x47, 0xd8, 0x65, 0xb0, 0x8f, 0xb0,
+	0x8f, 0xd2, 0x63, 0x8e, 0x65, 0xb0, 0x6e, 0x71, 0x2e, 0x3b, 0xf6, 0x23, 0x0c, 0x49, 0x76, 0xd7,
+	0x6d, 0x30, 0x76, 0xe9, 0x25, 0xfa, 0x3d, 0x3d, 0xfd, 0xf4, 0xde, 0xef, 0x29, 0x86, 0x0f, 0xb2,
+	0xb3, 0x38, 0x50, 0xa5, 0xe0, 0xca, 0xfc, 0xe6, 0xd9, 0xdc, 0xae, 0x34, 0x53, 0xb2, 0x90, 0xa8,
+	0x6d, 0xc0, 0xc1, 0x20, 0x96, 0xb1, 0x34, 0x3b, 0x81, 0xae, 0x2c, 0x79, 0x30, 0x8c, 0xa5, 0x8c,
+	0x05, 0x0f, 0x0c, 0x9a, 0x97, 0xcb, 0x80, 0xa5, 0xeb, 0x9a, 0x22, 0xbf, 0x53, 0x8b, 0x52, 0xb1,
+	0x22, 0x91, 0x69, 0xcd, 0xdf, 0xd3, 0xb2, 0x42, 0xc6, 0xf6, 0xce, 0xa6, 0xb0, 0xe4, 0xe1, 0x87,
+	0x3d, 0xd8, 0x0b, 0x4b, 0xc1, 0x5f, 0x28, 0x59, 0x66, 0x27, 0x3c, 0x8f, 0x10, 0x82, 0x6e, 0xca,
+	0x56, 0x1c, 0x83, 0x31, 0x98, 0x74, 0x42, 0x53, 0xa3, 0xfb, 0xb0, 0xa3, 0xd7, 0x3c, 0x63, 0x11,
+	0xc7, 0x7b, 0x86, 0xf8, 0xb9, 0x81, 0x9e, 0x43, 0x3f, 0x49, 0x0b, 0xae, 0xce, 0x99, 0xc0, 0xad,
+	0x31, 0x98, 0x74, 0x8f, 0x86, 0xd4, 0x7a, 0xa2, 0x8d, 0x27, 0x7a, 0x52, 0x7b, 0x9a, 0xfa, 0x17,
+	0x57, 0x23, 0xe7, 0xe3, 0xd7, 0x11, 0x08, 0x6f, 0x9a, 0xd0, 0x43, 0x68, 0x67, 0xc7, 0xee, 0xb8,
+	0x35, 0xe9, 0x1e, 0xfd, 0x47, 0x0d, 0xa2, 0xda, 0x97, 0xb6, 0x14, 0x5a, 0x56, 0x3b, 0x2b, 0x73,
+	0xae, 0xb0, 0x67, 0x9d, 0xe9, 0x1a, 0x51, 0xb8, 0x2f, 0x33, 0x7d, 0x71, 0x8e, 0x3b, 0xa6, 0x79,
+	0xf0, 0x87, 0xf4, 0x71, 0xba, 0x0e, 0x9b, 0x43, 0x68, 0x00, 0xdb, 0x22, 0x59, 0x25, 0x05, 0x86,
+	0x63, 0x30, 0x69, 0x85, 0x16, 0xcc, 0x5c, 0xbf, 0xdd, 0xf7, 0x66, 0xae, 0xbf, 0xdf, 0xf7, 0x67,
+	0xae, 0xef, 0xf7, 0x3b, 0x87, 0x5f, 0x5a, 0xd0, 0x6f, 0xf4, 0xb5, 0x30, 0x7f, 0x9b, 0xa9, 0x26,
+	0x12, 0x5d, 0xa3, 0xff, 0xa1, 0xa7, 0x78, 0x24, 0xd5, 0xa2, 0xce, 0xa3, 0x46, 0x5a, 0x80, 0x09,
+	0xae, 0x0a, 0x93, 0x44, 0x27, 0xb4, 0x00, 0x3d, 0x85, 0xad, 0xa5, 0x54, 0xd8, 0xfd, 0xf7, 0x74,
+	0xf4, 0x79, 0x24, 0xa0, 0x27, 0xd8, 0x9c, 0x8b, 0x1c, 0xb7, 0xcd, 0x70, 0x43, 0x7a, 0xf3, 0x7c,
+	0xa7, 0x3c, 0x66, 0xd1, 0xfa, 0x54, 0xb3, 0x2f, 0x59, 0xa2, 0xa6, 0xcf, 0x74, 0xe7, 0xe7, 0xab,
+	0xd1, 0xe3, 0x38, 0x29, 0xde, 0x94, 0x73, 0x1a, 0xc9, 0x55, 0x10, 0x2b, 0xb6, 0x64, 0x29, 0x0b,
+	0x84, 0x3c, 0x4b, 0x82, 0xdb, 0xff, 0x02, 0x6a, 0xfa, 0x8e, 0x17, 0x2c, 0x2b, 0xb8, 0x0a, 0x6b,
+	0x0d, 0x74, 0x0e, 0xbb, 0x2c, 0x4d, 0x65, 0xc1, 0x6c, 0x9e, 0xde, 0x1d, 0x4a, 0xde, 0x16, 0xd2,
+	0x53, 0x66, 0x4c, 0xb1, 0x55, 0x8e, 0x7b, 0x77, 0x39, 0xa5, 0xd5, 0x30, 0xaf, 0xdc, 0x9b, 0xbe,
+	0xde, 0x6c, 0x89, 0x73, 0xb9, 0x25, 0xce, 0xf5, 0x96, 0x80, 0x77, 0x15, 0x01, 0x9f, 0x2a, 0x02,
+	0x2e, 0x2a, 0x02, 0x36, 0x15, 0x01, 0xdf, 0x2a, 0x02, 0xbe, 0x57, 0xc4, 0xb9, 0xae, 0x08, 0x78,
+	0xbf, 0x23, 0xce, 0x66, 0x47, 0x9c, 0xcb, 0x1d, 0x71, 0x5e, 0x3d, 0xfa, 0x9b, 0xec, 0x2f, 0x5f,
+	0xf6, 0xdc, 0x33, 0x16, 0x9e, 0xfc, 0x18, 0x00, 0x60, 0x07, 0xc0, 0xa8, 0xf5, 0x03, 0x00, 0x00,
 }
 
 func (this *RuleGroupDesc) Equal(that interface{}) bool {
@@ -340,6 +342,14 @@ func (this *RuleDesc) Equal(that interface{}) bool {
 			return false
 		}
 	}
+	if len(this.Params) != len(that1.Params) {
+		return false
+	}
+	for i := range this.Params {
+		if !this.Params[i].Equal(that1.Params[i]) {
+			return false
+		}
+	}
 	return true
 }
 func (this *RuleGroupDesc) GoString() string {
@@ -366,7 +376,7 @@ func (this *RuleDesc) GoString() string {
 	if this == nil {
 		return "nil"
 	}
-	s := make([]string, 0, 10)
+	s := make([]string, 0, 11)
 	s = append(s, "&rulespb.RuleDesc{")
 	s = append(s, "Expr: "+fmt.Sprintf("%#v", this.Expr)+",\n")
 	s = append(s, "Record: "+fmt.Sprintf("%#v", this.Record)+",\n")
@@ -374,6 +384,7 @@ func (this *RuleDesc) GoString() string {
 	s = append(s, "For: "+fmt.Sprintf("%#v", this.For)+",\n")
 	s = append(s, "Labels: "+fmt.Sprintf("%#v", this.Labels)+",\n")
 	s = append(s, "Annotations: "+fmt.Sprintf("%#v", this.Annotations)+",\n")
+	s = append(s, "Params: "+fmt.Sprintf("%#v", this.Params)+",\n")
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
@@ -490,6 +501,20 @@ func (m *RuleDesc) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.Params) > 0 {
+		for iNdEx := len(m.Params) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size := m.Params[iNdEx].Size()
+				i -= size
+				if _, err := m.Params[iNdEx].MarshalTo(dAtA[i:]); err != nil {
+					return 0, err
+				}
+				i = encodeVarintRules(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x6a
+		}
+	}
 	if len(m.Annotations) > 0 {
 		for iNdEx := len(m.Annotations) - 1; iNdEx >= 0; iNdEx-- {
 			{
@@ -631,6 +656,12 @@ func (m *RuleDesc) Size() (n int) {
 			n += 1 + l + sovRules(uint64(l))
 		}
 	}
+	if len(m.Params) > 0 {
+		for _, e := range m.Params {
+			l = e.Size()
+			n += 1 + l + sovRules(uint64(l))
+		}
+	}
 	return n
 }
 
@@ -657,7 +688,7 @@ func (this *RuleGroupDesc) String() string {
 	s := strings.Join([]string{`&RuleGroupDesc{`,
 		`Name:` + fmt.Sprintf("%v", this.Name) + `,`,
 		`Namespace:` + fmt.Sprintf("%v", this.Namespace) + `,`,
-		`Interval:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.Interval), "Duration", "duration.Duration", 1), `&`, ``, 1) + `,`,
+		`Interval:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.Interval), "Duration", "durationpb.Duration", 1), `&`, ``, 1) + `,`,
 		`Rules:` + repeatedStringForRules + `,`,
 		`User:` + fmt.Sprintf("%v", this.User) + `,`,
 		`Options:` + repeatedStringForOptions + `,`,
@@ -674,9 +705,10 @@ func (this *RuleDesc) String() string {
 		`Expr:` + fmt.Sprintf("%v", this.Expr) + `,`,
 		`Record:` + fmt.Sprintf("%v", this.Record) + `,`,
 		`Alert:` + fmt.Sprintf("%v", this.Alert) + `,`,
-		`For:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.For), "Duration", "duration.Duration", 1), `&`, ``, 1) + `,`,
+		`For:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.For), "Duration", "durationpb.Duration", 1), `&`, ``, 1) + `,`,
 		`Labels:` + fmt.Sprintf("%v", this.Labels) + `,`,
 		`Annotations:` + fmt.Sprintf("%v", this.Annotations) + `,`,
+		`Params:` + fmt.Sprintf("%v", this.Params) + `,`,
 		`}`,
 	}, "")
 	return s
@@ -1184,6 +1216,40 @@ func (m *RuleDesc) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 13:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Params", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRules
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRules
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthRules
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Params = append(m.Params, github_com_grafana_loki_pkg_logproto.LabelAdapter{})
+			if err := m.Params[len(m.Params)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipRules(dAtA[iNdEx:])
@@ -32,6 +32,7 @@ type Config struct {
 	MemcacheClient MemcachedClientConfig `yaml:"memcached_client"`
 	Redis          RedisConfig           `yaml:"redis"`
 	EmbeddedCache  EmbeddedCacheConfig   `yaml:"embedded_cache"`
+	DiskCache      DiskCacheConfig       `yaml:"disk_cache"`
 
 	// This is to name the cache metrics properly.
 	Prefix string `yaml:"prefix" doc:"hidden"`
@@ -52,6 +53,7 @@ func (cfg *Config) RegisterFlagsWithPrefix(prefix string, description string, f
 	cfg.MemcacheClient.RegisterFlagsWithPrefix(prefix, description, f)
 	cfg.Redis.RegisterFlagsWithPrefix(prefix, description, f)
 	cfg.EmbeddedCache.RegisterFlagsWithPrefix(prefix, description, f)
+	cfg.DiskCache.RegisterFlagsWithPrefix(prefix, description, f)
 	f.IntVar(&cfg.AsyncCacheWriteBackConcurrency, prefix+"max-async-cache-write-back-concurrency", 16, "The maximum number of concurrent asynchronous writeback cache can occur.")
 	f.IntVar(&cfg.AsyncCacheWriteBackBufferSize, prefix+"max-async-cache-write-back-buffer-size", 500, "The maximum number of enqueued asynchronous writeback cache allowed.")
 	f.DurationVar(&cfg.DefaultValidity, prefix+"default-validity", time.Hour, description+"The default validity of entries for caches unless overridden.")
@@ -78,6 +80,11 @@ func IsEmbeddedCacheSet(cfg Config) bool {
 	return cfg.EmbeddedCache.Enabled
 }
 
+// IsDiskCacheSet returns whether the local on-disk cache is enabled.
+func IsDiskCacheSet(cfg Config) bool {
+	return cfg.DiskCache.Enabled
+}
+
 func IsSpecificImplementationSet(cfg Config) bool {
 	return cfg.Cache != nil
 }
@@ -86,9 +93,10 @@ func IsSpecificImplementationSet(cfg Config) bool {
 // - memcached
 // - redis
 // - embedded-cache
+// - disk-cache
 // - specific cache implementation
 func IsCacheConfigured(cfg Config) bool {
-	return IsMemcacheSet(cfg) || IsRedisSet(cfg) || IsEmbeddedCacheSet(cfg) || IsSpecificImplementationSet(cfg)
+	return IsMemcacheSet(cfg) || IsRedisSet(cfg) || IsEmbeddedCacheSet(cfg) || IsDiskCacheSet(cfg) || IsSpecificImplementationSet(cfg)
 }
 
 // New creates a new Cache using Config.
@@ -111,6 +119,19 @@ func New(cfg Config, reg prometheus.Registerer, logger log.Logger, cacheType sta
 		}
 	}
 
+	if IsDiskCacheSet(cfg) {
+		if cfg.DiskCache.TTL == 0 && cfg.DefaultValidity != 0 {
+			cfg.DiskCache.TTL = cfg.DefaultValidity
+		}
+
+		cacheName := cfg.Prefix + "disk-cache"
+		cache, err := NewDiskCache(cacheName, cfg.DiskCache, reg, logger, cacheType)
+		if err != nil {
+			return nil, fmt.Errorf("disk cache setup failed: %w", err)
+		}
+		caches = append(caches, CollectStats(NewBackground(cacheName, cfg.Background, Instrument(cacheName, cache, reg), reg)))
+	}
+
 	if IsMemcacheSet(cfg) && IsRedisSet(cfg) {
 		return nil, errors.New("use of multiple cache storage systems is not supported")
 	}
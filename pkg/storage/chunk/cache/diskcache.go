@@ -0,0 +1,485 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/prometheus/tsdb/fileutil"
+
+	"github.com/grafana/loki/pkg/logqlmodel/stats"
+)
+
+const (
+	diskCacheFormatV1 = byte(1)
+
+	// diskCacheHeaderSize is the size of the fixed part of the header:
+	// format (1 byte) + written at (8 bytes) + key length (4 bytes) + value checksum (4 bytes).
+	diskCacheHeaderSize = 1 + 8 + 4 + 4
+
+	diskCacheFileExt    = ".cache"
+	diskCacheTmpFileExt = ".tmp"
+
+	corruptReason = "corrupt"
+)
+
+var castagnoliTable = crc32.MakeTable(crc32.Castagnoli)
+
+// DiskCacheConfig represents the configuration of the local on-disk cache.
+type DiskCacheConfig struct {
+	Enabled   bool          `yaml:"enabled,omitempty"`
+	Directory string        `yaml:"directory"`
+	MaxSizeMB int64         `yaml:"max_size_mb"`
+	TTL       time.Duration `yaml:"ttl"`
+
+	// PurgeInterval tell how often should we remove keys that are expired.
+	// by default it takes `defaultPurgeInterval`
+	PurgeInterval time.Duration `yaml:"-"`
+}
+
+func (cfg *DiskCacheConfig) RegisterFlagsWithPrefix(prefix, description string, f *flag.FlagSet) {
+	f.BoolVar(&cfg.Enabled, prefix+"disk-cache.enabled", false, description+"Whether the on-disk cache is enabled. When used together with the embedded cache, the disk cache acts as a second tier behind it.")
+	f.StringVar(&cfg.Directory, prefix+"disk-cache.directory", "", description+"Directory in which cached entries are stored. Each cache type uses its own subdirectory.")
+	f.Int64Var(&cfg.MaxSizeMB, prefix+"disk-cache.max-size-mb", 1024, description+"Maximum size of the on-disk cache in MB.")
+	f.DurationVar(&cfg.TTL, prefix+"disk-cache.ttl", 24*time.Hour, description+"The time to live for items in the on-disk cache before they get purged.")
+}
+
+func (cfg *DiskCacheConfig) IsEnabled() bool {
+	return cfg.Enabled
+}
+
+func (cfg *DiskCacheConfig) Validate() error {
+	if !cfg.Enabled {
+		return nil
+	}
+	if cfg.Directory == "" {
+		return errors.New("disk cache directory must be set when the disk cache is enabled")
+	}
+	if cfg.MaxSizeMB <= 0 {
+		return errors.New("disk cache max size must be greater than zero")
+	}
+	return nil
+}
+
+// DiskCache is a size-bounded cache that keeps entries as files in a local directory,
+// so that they survive restarts of the process.
+//
+// Entries are written to a temporary file first and atomically renamed into place, which
+// guarantees that a crash never leaves a partially written entry behind. Every entry
+// carries a checksum of its value, entries that fail verification are removed on read.
+// Once the cache grows beyond MaxSizeMB, the oldest entries are evicted first.
+type DiskCache struct {
+	cacheType stats.CacheType
+	logger    log.Logger
+
+	dir string
+	ttl time.Duration
+
+	lock          sync.Mutex
+	maxSizeBytes  uint64
+	currSizeBytes uint64
+
+	entries map[string]*list.Element
+	fifo    *list.List
+
+	done     chan struct{}
+	stopOnce sync.Once
+
+	entriesAddedNew prometheus.Counter
+	entriesEvicted  *prometheus.CounterVec
+	entriesCurrent  prometheus.Gauge
+	diskBytes       prometheus.Gauge
+}
+
+type diskCacheEntry struct {
+	file    string
+	updated time.Time
+	size    uint64
+}
+
+// NewDiskCache returns a new initialised DiskCache. Entries left on disk by a previous
+// process are loaded into the index on startup.
+func NewDiskCache(name string, cfg DiskCacheConfig, reg prometheus.Registerer, logger log.Logger, cacheType stats.CacheType) (*DiskCache, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	if cfg.PurgeInterval == 0 {
+		cfg.PurgeInterval = defaultPurgeInterval
+	}
+
+	dir := filepath.Join(cfg.Directory, string(cacheType))
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, errors.Wrapf(err, "creating disk cache directory %s", dir)
+	}
+
+	cache := &DiskCache{
+		cacheType: cacheType,
+		logger:    log.With(logger, "cache", name),
+
+		dir:          dir,
+		ttl:          cfg.TTL,
+		maxSizeBytes: uint64(cfg.MaxSizeMB * 1e6),
+		entries:      make(map[string]*list.Element),
+		fifo:         list.New(),
+
+		done: make(chan struct{}),
+
+		entriesAddedNew: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Namespace:   "loki",
+			Subsystem:   "diskcache",
+			Name:        "added_new_total",
+			Help:        "The total number of new entries added to the cache",
+			ConstLabels: prometheus.Labels{"cache": name},
+		}),
+
+		entriesEvicted: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Namespace:   "loki",
+			Subsystem:   "diskcache",
+			Name:        "evicted_total",
+			Help:        "The total number of evicted entries",
+			ConstLabels: prometheus.Labels{"cache": name},
+		}, []string{"reason"}),
+
+		entriesCurrent: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Namespace:   "loki",
+			Subsystem:   "diskcache",
+			Name:        "entries",
+			Help:        "Current number of entries in the cache",
+			ConstLabels: prometheus.Labels{"cache": name},
+		}),
+
+		diskBytes: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Namespace:   "loki",
+			Subsystem:   "diskcache",
+			Name:        "disk_bytes",
+			Help:        "The current cache size on disk in bytes",
+			ConstLabels: prometheus.Labels{"cache": name},
+		}),
+	}
+
+	if err := cache.load(); err != nil {
+		return nil, err
+	}
+
+	if cfg.TTL > 0 {
+		go cache.runPruneJob(cfg.PurgeInterval)
+	}
+
+	return cache, nil
+}
+
+// load rebuilds the in-memory index from the files found in the cache directory.
+// Leftover temporary files from interrupted writes are removed.
+func (c *DiskCache) load() error {
+	dirEntries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return errors.Wrapf(err, "reading disk cache directory %s", c.dir)
+	}
+
+	loaded := make([]*diskCacheEntry, 0, len(dirEntries))
+	for _, de := range dirEntries {
+		if de.IsDir() {
+			continue
+		}
+		path := filepath.Join(c.dir, de.Name())
+		if !strings.HasSuffix(de.Name(), diskCacheFileExt) {
+			if strings.HasSuffix(de.Name(), diskCacheTmpFileExt) {
+				_ = os.Remove(path)
+			}
+			continue
+		}
+
+		info, err := de.Info()
+		if err != nil {
+			continue
+		}
+		loaded = append(loaded, &diskCacheEntry{
+			file:    strings.TrimSuffix(de.Name(), diskCacheFileExt),
+			updated: info.ModTime(),
+			size:    uint64(info.Size()),
+		})
+	}
+
+	// Oldest entries go to the back of the list so they are evicted first.
+	sort.Slice(loaded, func(i, j int) bool {
+		return loaded[i].updated.Before(loaded[j].updated)
+	})
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for _, entry := range loaded {
+		c.entries[entry.file] = c.fifo.PushFront(entry)
+		c.currSizeBytes += entry.size
+		c.entriesCurrent.Inc()
+	}
+	c.evictLocked(0)
+	c.diskBytes.Set(float64(c.currSizeBytes))
+
+	level.Debug(c.logger).Log("msg", "loaded disk cache entries", "entries", len(c.entries), "bytes", c.currSizeBytes)
+	return nil
+}
+
+func (c *DiskCache) runPruneJob(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-ticker.C:
+			c.pruneExpiredItems()
+		}
+	}
+}
+
+// pruneExpiredItems removes items in the cache that exceeded their ttl.
+func (c *DiskCache) pruneExpiredItems() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for element := c.fifo.Back(); element != nil; {
+		entry := element.Value.(*diskCacheEntry)
+		if time.Since(entry.updated) <= c.ttl {
+			// Entries are ordered by write time, so all remaining ones are still valid.
+			break
+		}
+		prev := element.Prev()
+		c.removeLocked(element, expiredReason)
+		element = prev
+	}
+	c.diskBytes.Set(float64(c.currSizeBytes))
+}
+
+// Fetch implements Cache.
+func (c *DiskCache) Fetch(_ context.Context, keys []string) (found []string, bufs [][]byte, missing []string, err error) {
+	found, missing, bufs = make([]string, 0, len(keys)), make([]string, 0, len(keys)), make([][]byte, 0, len(keys))
+	for _, key := range keys {
+		val, ok := c.get(key)
+		if !ok {
+			missing = append(missing, key)
+			continue
+		}
+
+		found = append(found, key)
+		bufs = append(bufs, val)
+	}
+	return
+}
+
+// Store implements Cache.
+func (c *DiskCache) Store(_ context.Context, keys []string, values [][]byte) error {
+	var lastErr error
+	for i := range keys {
+		if err := c.put(keys[i], values[i]); err != nil {
+			level.Warn(c.logger).Log("msg", "failed to write disk cache entry", "err", err)
+			lastErr = err
+		}
+	}
+	return lastErr
+}
+
+// Stop implements Cache.
+func (c *DiskCache) Stop() {
+	c.stopOnce.Do(func() {
+		close(c.done)
+	})
+}
+
+func (c *DiskCache) GetCacheType() stats.CacheType {
+	return c.cacheType
+}
+
+func (c *DiskCache) put(key string, value []byte) error {
+	file := fileNameForKey(key)
+	buf := encodeDiskCacheEntry(key, value, time.Now())
+	size := uint64(len(buf))
+
+	if size > c.maxSizeBytes {
+		// Cannot keep this item in the cache.
+		c.entriesEvicted.WithLabelValues(tooBigReason).Inc()
+		return nil
+	}
+
+	tmp, err := os.CreateTemp(c.dir, file+"-*"+diskCacheTmpFileExt)
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+	defer os.Remove(tmpName)
+
+	if _, err := tmp.Write(buf); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	element, ok := c.entries[file]
+	if ok {
+		entry := c.fifo.Remove(element).(*diskCacheEntry)
+		delete(c.entries, file)
+		c.currSizeBytes -= entry.size
+		c.entriesCurrent.Dec()
+	}
+
+	c.evictLocked(size)
+
+	if err := fileutil.Replace(tmpName, c.path(file)); err != nil {
+		c.diskBytes.Set(float64(c.currSizeBytes))
+		return err
+	}
+
+	c.entries[file] = c.fifo.PushFront(&diskCacheEntry{
+		file:    file,
+		updated: time.Now(),
+		size:    size,
+	})
+	c.currSizeBytes += size
+	if !ok {
+		c.entriesAddedNew.Inc()
+	}
+	c.entriesCurrent.Inc()
+	c.diskBytes.Set(float64(c.currSizeBytes))
+	return nil
+}
+
+func (c *DiskCache) get(key string) ([]byte, bool) {
+	file := fileNameForKey(key)
+
+	c.lock.Lock()
+	element, ok := c.entries[file]
+	c.lock.Unlock()
+	if !ok {
+		return nil, false
+	}
+
+	buf, err := os.ReadFile(c.path(file))
+	if err != nil {
+		if os.IsNotExist(err) {
+			c.remove(element, "")
+		}
+		return nil, false
+	}
+
+	storedKey, value, written, err := decodeDiskCacheEntry(buf)
+	if err != nil {
+		level.Warn(c.logger).Log("msg", "removing corrupt disk cache entry", "file", file, "err", err)
+		c.remove(element, corruptReason)
+		return nil, false
+	}
+	if storedKey != key {
+		// Hash collision, treat it as a miss and let the next Store overwrite the entry.
+		return nil, false
+	}
+	if c.ttl > 0 && time.Since(written) > c.ttl {
+		c.remove(element, expiredReason)
+		return nil, false
+	}
+
+	return value, true
+}
+
+func (c *DiskCache) remove(element *list.Element, reason string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	entry := element.Value.(*diskCacheEntry)
+	if current, ok := c.entries[entry.file]; !ok || current != element {
+		// Already removed or replaced by a concurrent Store.
+		return
+	}
+	c.removeLocked(element, reason)
+	c.diskBytes.Set(float64(c.currSizeBytes))
+}
+
+// evictLocked removes the oldest entries until an entry of the given size fits in the cache.
+func (c *DiskCache) evictLocked(size uint64) {
+	for c.currSizeBytes+size > c.maxSizeBytes {
+		lastElement := c.fifo.Back()
+		if lastElement == nil {
+			break
+		}
+		c.removeLocked(lastElement, fullReason)
+	}
+}
+
+func (c *DiskCache) removeLocked(element *list.Element, reason string) {
+	entry := c.fifo.Remove(element).(*diskCacheEntry)
+	delete(c.entries, entry.file)
+	c.currSizeBytes -= entry.size
+	c.entriesCurrent.Dec()
+	if reason != "" {
+		c.entriesEvicted.WithLabelValues(reason).Inc()
+	}
+	if err := os.Remove(c.path(entry.file)); err != nil && !os.IsNotExist(err) {
+		level.Warn(c.logger).Log("msg", "failed to remove disk cache entry", "file", entry.file, "err", err)
+	}
+}
+
+func (c *DiskCache) path(file string) string {
+	return filepath.Join(c.dir, file+diskCacheFileExt)
+}
+
+func fileNameForKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+func encodeDiskCacheEntry(key string, value []byte, written time.Time) []byte {
+	buf := make([]byte, diskCacheHeaderSize+len(key)+len(value))
+	buf[0] = diskCacheFormatV1
+	binary.BigEndian.PutUint64(buf[1:9], uint64(written.UnixNano()))
+	binary.BigEndian.PutUint32(buf[9:13], uint32(len(key)))
+	binary.BigEndian.PutUint32(buf[13:17], crc32.Checksum(value, castagnoliTable))
+	copy(buf[diskCacheHeaderSize:], key)
+	copy(buf[diskCacheHeaderSize+len(key):], value)
+	return buf
+}
+
+func decodeDiskCacheEntry(buf []byte) (key string, value []byte, written time.Time, err error) {
+	if len(buf) < diskCacheHeaderSize {
+		return "", nil, time.Time{}, errors.New("entry too short")
+	}
+	if buf[0] != diskCacheFormatV1 {
+		return "", nil, time.Time{}, fmt.Errorf("unknown entry format %d", buf[0])
+	}
+	written = time.Unix(0, int64(binary.BigEndian.Uint64(buf[1:9])))
+	keyLen := int(binary.BigEndian.Uint32(buf[9:13]))
+	checksum := binary.BigEndian.Uint32(buf[13:17])
+	if len(buf) < diskCacheHeaderSize+keyLen {
+		return "", nil, time.Time{}, errors.New("entry too short")
+	}
+	key = string(buf[diskCacheHeaderSize : diskCacheHeaderSize+keyLen])
+	value = buf[diskCacheHeaderSize+keyLen:]
+	if crc32.Checksum(value, castagnoliTable) != checksum {
+		return "", nil, time.Time{}, errors.New("checksum mismatch")
+	}
+	return key, value, written, nil
+}
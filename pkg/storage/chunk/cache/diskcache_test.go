@@ -0,0 +1,155 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestDiskCache(t *testing.T, cfg DiskCacheConfig) *DiskCache {
+	t.Helper()
+	cfg.Enabled = true
+	c, err := NewDiskCache("test", cfg, nil, log.NewNopLogger(), "test")
+	require.NoError(t, err)
+	t.Cleanup(c.Stop)
+	return c
+}
+
+func TestDiskCache(t *testing.T) {
+	dir := t.TempDir()
+	c := newTestDiskCache(t, DiskCacheConfig{Directory: dir, MaxSizeMB: 1, TTL: time.Hour})
+	ctx := context.Background()
+
+	keys := []string{"foo", "bar", "baz"}
+	bufs := [][]byte{[]byte("1"), []byte("22"), []byte("333")}
+	require.NoError(t, c.Store(ctx, keys, bufs))
+
+	found, values, missing, err := c.Fetch(ctx, []string{"foo", "bar", "baz", "qux"})
+	require.NoError(t, err)
+	require.Equal(t, keys, found)
+	require.Equal(t, bufs, values)
+	require.Equal(t, []string{"qux"}, missing)
+
+	// Overwriting a key replaces the value in place.
+	require.NoError(t, c.Store(ctx, []string{"foo"}, [][]byte{[]byte("11")}))
+	_, values, _, err = c.Fetch(ctx, []string{"foo"})
+	require.NoError(t, err)
+	require.Equal(t, [][]byte{[]byte("11")}, values)
+	require.Equal(t, float64(3), testutil.ToFloat64(c.entriesCurrent))
+}
+
+func TestDiskCacheSurvivesRestart(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	cfg := DiskCacheConfig{Directory: dir, MaxSizeMB: 1, TTL: time.Hour}
+
+	c := newTestDiskCache(t, cfg)
+	require.NoError(t, c.Store(ctx, []string{"foo"}, [][]byte{[]byte("bar")}))
+	c.Stop()
+
+	// An interrupted write leaves a temporary file behind which must be ignored and cleaned up.
+	tmp := filepath.Join(dir, "test", fileNameForKey("partial")+"-123"+diskCacheTmpFileExt)
+	require.NoError(t, os.WriteFile(tmp, []byte("garbage"), 0o640))
+
+	c = newTestDiskCache(t, cfg)
+	found, values, missing, err := c.Fetch(ctx, []string{"foo", "partial"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"foo"}, found)
+	require.Equal(t, [][]byte{[]byte("bar")}, values)
+	require.Equal(t, []string{"partial"}, missing)
+	require.NoFileExists(t, tmp)
+}
+
+func TestDiskCacheEviction(t *testing.T) {
+	const cnt = 10
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	// Each entry accounts for exactly 1/10th of 1MB on disk.
+	valueSize := int(1e6/cnt) - diskCacheHeaderSize - 2
+	c := newTestDiskCache(t, DiskCacheConfig{Directory: dir, MaxSizeMB: 1, TTL: time.Hour})
+
+	for i := 0; i < cnt+5; i++ {
+		require.NoError(t, c.Store(ctx, []string{fmt.Sprintf("%02d", i)}, [][]byte{make([]byte, valueSize)}))
+	}
+
+	require.Equal(t, float64(cnt), testutil.ToFloat64(c.entriesCurrent))
+	require.Equal(t, float64(5), testutil.ToFloat64(c.entriesEvicted.WithLabelValues(fullReason)))
+	require.Equal(t, float64(1e6), testutil.ToFloat64(c.diskBytes))
+
+	// The oldest entries are evicted first.
+	found, _, missing, err := c.Fetch(ctx, []string{"00", "04", "05", "14"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"05", "14"}, found)
+	require.Equal(t, []string{"00", "04"}, missing)
+
+	files, err := os.ReadDir(filepath.Join(dir, "test"))
+	require.NoError(t, err)
+	require.Len(t, files, cnt)
+}
+
+func TestDiskCacheExpiry(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	c := newTestDiskCache(t, DiskCacheConfig{Directory: dir, MaxSizeMB: 1, TTL: 50 * time.Millisecond, PurgeInterval: time.Hour})
+
+	require.NoError(t, c.Store(ctx, []string{"foo", "bar"}, [][]byte{[]byte("1"), []byte("2")}))
+	time.Sleep(100 * time.Millisecond)
+
+	// Expired entries are never returned, even before the purge job ran.
+	_, _, missing, err := c.Fetch(ctx, []string{"foo"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"foo"}, missing)
+
+	c.pruneExpiredItems()
+	require.Equal(t, float64(0), testutil.ToFloat64(c.entriesCurrent))
+	require.Equal(t, float64(2), testutil.ToFloat64(c.entriesEvicted.WithLabelValues(expiredReason)))
+}
+
+func TestDiskCacheCorruptEntry(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	c := newTestDiskCache(t, DiskCacheConfig{Directory: dir, MaxSizeMB: 1, TTL: time.Hour})
+
+	require.NoError(t, c.Store(ctx, []string{"foo"}, [][]byte{[]byte("bar")}))
+
+	path := c.path(fileNameForKey("foo"))
+	buf, err := os.ReadFile(path)
+	require.NoError(t, err)
+	buf[len(buf)-1] ^= 0xff
+	require.NoError(t, os.WriteFile(path, buf, 0o640))
+
+	_, _, missing, err := c.Fetch(ctx, []string{"foo"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"foo"}, missing)
+	require.NoFileExists(t, path)
+	require.Equal(t, float64(1), testutil.ToFloat64(c.entriesEvicted.WithLabelValues(corruptReason)))
+}
+
+func TestDiskCacheAsSecondTier(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	l1 := NewEmbeddedCache("l1", EmbeddedCacheConfig{MaxSizeMB: 1}, nil, log.NewNopLogger(), "test")
+	l2 := newTestDiskCache(t, DiskCacheConfig{Directory: dir, MaxSizeMB: 1, TTL: time.Hour})
+	require.NoError(t, l2.Store(ctx, []string{"foo"}, [][]byte{[]byte("bar")}))
+
+	c := NewTiered([]Cache{l1, l2})
+	found, values, missing, err := c.Fetch(ctx, []string{"foo"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"foo"}, found)
+	require.Equal(t, [][]byte{[]byte("bar")}, values)
+	require.Empty(t, missing)
+
+	// Hits from the disk cache are backfilled into the embedded cache.
+	value, ok := l1.Get(ctx, "foo")
+	require.True(t, ok)
+	require.Equal(t, []byte("bar"), value)
+}
@@ -0,0 +1,374 @@
+package encryption
+
+import (
+	"bytes"
+	"context"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/sync/singleflight"
+
+	"github.com/grafana/loki/pkg/storage/chunk"
+	"github.com/grafana/loki/pkg/storage/chunk/client"
+	"github.com/grafana/loki/pkg/util/kms"
+)
+
+// magic prefixes the encrypted objects. Objects without it are returned as is, so that encryption can be
+// enabled on an existing object store.
+var magic = []byte("LOKIENC1")
+
+// ErrDataKeyNotFound is returned when reading an object whose data key was deleted.
+var ErrDataKeyNotFound = errors.New("data key not found")
+
+// Config configures the encryption of the objects of an object store.
+type Config struct {
+	Enabled     bool          `yaml:"enabled"`
+	KMS         kms.Config    `yaml:",inline"`
+	KeysPrefix  string        `yaml:"keys_prefix"`
+	IndexPrefix string        `yaml:"index_prefix"`
+	KeyCacheTTL time.Duration `yaml:"key_cache_ttl"`
+}
+
+// RegisterFlagsWithPrefix registers flags.
+func (cfg *Config) RegisterFlagsWithPrefix(prefix string, f *flag.FlagSet) {
+	f.BoolVar(&cfg.Enabled, prefix+"encryption.enabled", false, "Encrypt the chunks and index files in the object stores. Each object is encrypted with AES-256-GCM using a data key of its tenant, which is stored in the object store wrapped by a master key of the key provider. Deleting the data keys of a tenant makes all its objects unreadable.")
+	cfg.KMS.RegisterFlagsWithPrefix(prefix+"encryption.", f)
+	f.StringVar(&cfg.KeysPrefix, prefix+"encryption.keys-prefix", "encryption-keys/", "Prefix of the keys of the wrapped data keys in the object stores. The data keys of a tenant are stored under <prefix>tenants/<tenant>/.")
+	f.StringVar(&cfg.IndexPrefix, prefix+"encryption.index-prefix", "index/", "Prefix of the keys of the index files in the object stores, which must match the path_prefix of the index of the schema periods. The index files of a tenant are encrypted with its data key, and the index files shared by the tenants with a shared data key.")
+	f.DurationVar(&cfg.KeyCacheTTL, prefix+"encryption.key-cache-ttl", 5*time.Minute, "How long the data keys are cached. Deleted data keys can still be used for this long by the processes which cached them.")
+}
+
+func (cfg *Config) Validate() error {
+	if !cfg.Enabled {
+		return nil
+	}
+	if err := cfg.KMS.Validate(); err != nil {
+		return fmt.Errorf("invalid object store encryption config: %w", err)
+	}
+	for _, prefix := range []string{cfg.KeysPrefix, cfg.IndexPrefix} {
+		if prefix == "" || strings.HasPrefix(prefix, "/") || !strings.HasSuffix(prefix, "/") {
+			return errors.Errorf("invalid object store encryption prefix %q, it should end with a '/' but not start with one", prefix)
+		}
+	}
+	return nil
+}
+
+// storedKey is a data key as stored in the object store.
+type storedKey struct {
+	MasterKeyID string    `json:"master_key_id"`
+	WrappedKey  []byte    `json:"wrapped_key"`
+	Created     time.Time `json:"created"`
+}
+
+type cachedKey struct {
+	id      string
+	aead    cipher.AEAD
+	fetched time.Time
+}
+
+// ObjectClient encrypts the objects of an ObjectClient with per-tenant data keys wrapped by a master key.
+// The tenant of a chunk is the first component of its key, and the tenant of an index file its tenant
+// component under the index prefix. The other objects are encrypted with the shared data key.
+// Objects are encrypted and decrypted as a whole in memory, which takes about twice their size.
+type ObjectClient struct {
+	client.ObjectClient
+
+	cfg      Config
+	provider kms.KeyProvider
+
+	mtx sync.Mutex
+	// the data keys encrypting the new objects, by tenant.
+	current map[string]*cachedKey
+	// the data keys decrypting the objects, by tenant and ID.
+	keys map[string]*cachedKey
+
+	// loads the data keys from the object store and the key provider, once at a time per tenant
+	// and per key, without holding mtx.
+	loads singleflight.Group
+}
+
+// NewObjectClient makes a new ObjectClient encrypting the objects of c.
+func NewObjectClient(c client.ObjectClient, cfg Config) *ObjectClient {
+	return &ObjectClient{
+		ObjectClient: c,
+		cfg:          cfg,
+		provider:     kms.NewKeyProvider(cfg.KMS),
+		current:      map[string]*cachedKey{},
+		keys:         map[string]*cachedKey{},
+	}
+}
+
+func (c *ObjectClient) PutObject(ctx context.Context, objectKey string, object io.ReadSeeker) error {
+	plaintext, err := io.ReadAll(object)
+	if err != nil {
+		return err
+	}
+	tenant := c.tenant(objectKey)
+	key, err := c.currentKey(ctx, tenant)
+	if err != nil {
+		return fmt.Errorf("failed to get the data key of tenant %q: %w", tenant, err)
+	}
+
+	header := append([]byte{}, magic...)
+	header = binary.AppendUvarint(header, uint64(len(tenant)))
+	header = append(header, tenant...)
+	header = binary.AppendUvarint(header, uint64(len(key.id)))
+	header = append(header, key.id...)
+	encrypted := make([]byte, len(header), len(header)+len(plaintext)+kms.Overhead(key.aead))
+	copy(encrypted, header)
+	encrypted, err = kms.Seal(key.aead, encrypted, plaintext, header)
+	if err != nil {
+		return err
+	}
+	return c.ObjectClient.PutObject(ctx, objectKey, bytes.NewReader(encrypted))
+}
+
+func (c *ObjectClient) GetObject(ctx context.Context, objectKey string) (io.ReadCloser, int64, error) {
+	reader, _, err := c.ObjectClient.GetObject(ctx, objectKey)
+	if err != nil {
+		return nil, 0, err
+	}
+	object, err := io.ReadAll(reader)
+	reader.Close()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	plaintext, err := c.decrypt(ctx, object)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to decrypt object %s: %w", objectKey, err)
+	}
+	return io.NopCloser(bytes.NewReader(plaintext)), int64(len(plaintext)), nil
+}
+
+// List hides the data keys.
+func (c *ObjectClient) List(ctx context.Context, prefix string, delimiter string) ([]client.StorageObject, []client.StorageCommonPrefix, error) {
+	objects, prefixes, err := c.ObjectClient.List(ctx, prefix, delimiter)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	filteredObjects := objects[:0]
+	for _, object := range objects {
+		if !strings.HasPrefix(object.Key, c.cfg.KeysPrefix) {
+			filteredObjects = append(filteredObjects, object)
+		}
+	}
+	filteredPrefixes := prefixes[:0]
+	for _, p := range prefixes {
+		if !strings.HasPrefix(string(p), c.cfg.KeysPrefix) {
+			filteredPrefixes = append(filteredPrefixes, p)
+		}
+	}
+	return filteredObjects, filteredPrefixes, nil
+}
+
+// IsObjectNotFoundErr returns true for the objects whose data key was deleted, which can't be read anymore.
+func (c *ObjectClient) IsObjectNotFoundErr(err error) bool {
+	return errors.Is(err, ErrDataKeyNotFound) || c.ObjectClient.IsObjectNotFoundErr(err)
+}
+
+func (c *ObjectClient) decrypt(ctx context.Context, object []byte) ([]byte, error) {
+	if !bytes.HasPrefix(object, magic) {
+		return object, nil
+	}
+
+	rest := object[len(magic):]
+	tenant, rest, err := readString(rest)
+	if err != nil {
+		return nil, err
+	}
+	id, rest, err := readString(rest)
+	if err != nil {
+		return nil, err
+	}
+	header := object[:len(object)-len(rest)]
+
+	key, err := c.key(ctx, tenant, id)
+	if err != nil {
+		return nil, err
+	}
+	return kms.Open(key.aead, nil, rest, header)
+}
+
+func readString(b []byte) (string, []byte, error) {
+	l, n := binary.Uvarint(b)
+	if n <= 0 || uint64(len(b)-n) < l {
+		return "", nil, errors.New("invalid encrypted object header")
+	}
+	return string(b[n : n+int(l)]), b[n+int(l):], nil
+}
+
+// tenant returns the tenant of the chunks and index files, or an empty string for the objects shared by the tenants.
+func (c *ObjectClient) tenant(objectKey string) string {
+	if strings.HasPrefix(objectKey, c.cfg.IndexPrefix) {
+		// <index prefix><table>/<tenant>/<file>, or <index prefix><table>/<file> for the shared index files.
+		parts := strings.Split(strings.TrimPrefix(objectKey, c.cfg.IndexPrefix), "/")
+		if len(parts) == 3 {
+			return parts[1]
+		}
+		return ""
+	}
+	i := strings.IndexByte(objectKey, '/')
+	if i <= 0 {
+		return ""
+	}
+	// Only chunks are stored under the tenant, the other objects with a path may not belong to a tenant.
+	if _, err := chunk.ParseExternalKey(objectKey[:i], objectKey); err != nil {
+		return ""
+	}
+	return objectKey[:i]
+}
+
+// keysPrefix returns the prefix of the data keys of the tenant.
+func (c *ObjectClient) keysPrefix(tenant string) string {
+	if tenant == "" {
+		return c.cfg.KeysPrefix + "shared/"
+	}
+	return c.cfg.KeysPrefix + "tenants/" + tenant + "/"
+}
+
+// currentKey returns the data key encrypting the new objects of the tenant, generating one if it has none.
+// When several data keys were generated concurrently, the first one by ID is used.
+func (c *ObjectClient) currentKey(ctx context.Context, tenant string) (*cachedKey, error) {
+	c.mtx.Lock()
+	key, ok := c.current[tenant]
+	c.mtx.Unlock()
+	if ok && time.Since(key.fetched) < c.cfg.KeyCacheTTL {
+		return key, nil
+	}
+
+	prefix := c.keysPrefix(tenant)
+	v, err, _ := c.loads.Do("current/"+prefix, func() (interface{}, error) {
+		key, err := c.loadCurrentKey(ctx, tenant)
+		if err != nil {
+			return nil, err
+		}
+		c.mtx.Lock()
+		defer c.mtx.Unlock()
+		c.current[tenant] = key
+		c.keys[prefix+key.id] = key
+		return key, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*cachedKey), nil
+}
+
+func (c *ObjectClient) loadCurrentKey(ctx context.Context, tenant string) (*cachedKey, error) {
+	prefix := c.keysPrefix(tenant)
+	objects, _, err := c.ObjectClient.List(ctx, prefix, "")
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(objects))
+	for _, object := range objects {
+		if strings.HasSuffix(object.Key, ".json") {
+			ids = append(ids, strings.TrimSuffix(strings.TrimPrefix(object.Key, prefix), ".json"))
+		}
+	}
+	sort.Strings(ids)
+
+	if len(ids) == 0 {
+		return c.newKey(ctx, tenant)
+	}
+	key, err := c.fetchKey(ctx, tenant, ids[0])
+	if errors.Is(err, ErrDataKeyNotFound) {
+		// deleted since listed
+		return c.newKey(ctx, tenant)
+	}
+	return key, err
+}
+
+// key returns the data key of the tenant with the given ID.
+func (c *ObjectClient) key(ctx context.Context, tenant, id string) (*cachedKey, error) {
+	path := c.keysPrefix(tenant) + id
+	c.mtx.Lock()
+	key, ok := c.keys[path]
+	c.mtx.Unlock()
+	if ok && time.Since(key.fetched) < c.cfg.KeyCacheTTL {
+		return key, nil
+	}
+
+	v, err, _ := c.loads.Do(path, func() (interface{}, error) {
+		key, err := c.fetchKey(ctx, tenant, id)
+		c.mtx.Lock()
+		defer c.mtx.Unlock()
+		if err != nil {
+			delete(c.keys, path)
+			return nil, err
+		}
+		c.keys[path] = key
+		return key, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*cachedKey), nil
+}
+
+func (c *ObjectClient) fetchKey(ctx context.Context, tenant, id string) (*cachedKey, error) {
+	reader, _, err := c.ObjectClient.GetObject(ctx, c.keysPrefix(tenant)+id+".json")
+	if err != nil {
+		if c.ObjectClient.IsObjectNotFoundErr(err) {
+			return nil, ErrDataKeyNotFound
+		}
+		return nil, err
+	}
+	defer reader.Close()
+
+	var stored storedKey
+	if err := json.NewDecoder(reader).Decode(&stored); err != nil {
+		return nil, fmt.Errorf("failed to decode data key %s: %w", id, err)
+	}
+	raw, err := c.provider.Unwrap(ctx, stored.MasterKeyID, stored.WrappedKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap data key %s: %w", id, err)
+	}
+	aead, err := kms.NewAEAD(raw)
+	if err != nil {
+		return nil, err
+	}
+	return &cachedKey{id: id, aead: aead, fetched: time.Now()}, nil
+}
+
+func (c *ObjectClient) newKey(ctx context.Context, tenant string) (*cachedKey, error) {
+	raw, err := kms.NewDataKey()
+	if err != nil {
+		return nil, err
+	}
+	masterKeyID, wrapped, err := c.provider.Wrap(ctx, raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to wrap data key: %w", err)
+	}
+	aead, err := kms.NewAEAD(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	idBytes := make([]byte, 16)
+	if _, err := io.ReadFull(rand.Reader, idBytes); err != nil {
+		return nil, err
+	}
+	id := hex.EncodeToString(idBytes)
+	b, err := json.Marshal(storedKey{MasterKeyID: masterKeyID, WrappedKey: wrapped, Created: time.Now()})
+	if err != nil {
+		return nil, err
+	}
+	if err := c.ObjectClient.PutObject(ctx, c.keysPrefix(tenant)+id+".json", bytes.NewReader(b)); err != nil {
+		return nil, err
+	}
+	return &cachedKey{id: id, aead: aead, fetched: time.Now()}, nil
+}
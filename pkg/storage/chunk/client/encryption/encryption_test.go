@@ -0,0 +1,172 @@
+package encryption
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+
+	"github.com/grafana/loki/pkg/storage/chunk/client"
+	"github.com/grafana/loki/pkg/storage/chunk/client/local"
+	"github.com/grafana/loki/pkg/util/kms"
+)
+
+func newTestConfig(t *testing.T) Config {
+	key := make([]byte, kms.DataKeySize)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+	b, err := yaml.Marshal(kms.MasterKeysFile{Current: "key-1", Keys: map[string]string{"key-1": base64.StdEncoding.EncodeToString(key)}})
+	require.NoError(t, err)
+	keyFile := filepath.Join(t.TempDir(), "keys.yaml")
+	require.NoError(t, os.WriteFile(keyFile, b, 0o600))
+
+	cfg := Config{
+		Enabled:     true,
+		KMS:         kms.Config{Provider: kms.ProviderFile, KeyFile: keyFile},
+		KeysPrefix:  "encryption-keys/",
+		IndexPrefix: "index/",
+		KeyCacheTTL: time.Minute,
+	}
+	require.NoError(t, cfg.Validate())
+	return cfg
+}
+
+func readObject(t *testing.T, c client.ObjectClient, key string) string {
+	reader, size, err := c.GetObject(context.Background(), key)
+	require.NoError(t, err)
+	defer reader.Close()
+	b, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	require.Equal(t, int64(len(b)), size)
+	return string(b)
+}
+
+func listKeys(t *testing.T, c client.ObjectClient, prefix string) []string {
+	objects, _, err := c.List(context.Background(), prefix, "")
+	require.NoError(t, err)
+	var keys []string
+	for _, object := range objects {
+		keys = append(keys, object.Key)
+	}
+	return keys
+}
+
+func TestObjectClient(t *testing.T) {
+	store, err := local.NewFSObjectClient(local.FSConfig{Directory: t.TempDir()})
+	require.NoError(t, err)
+	cfg := newTestConfig(t)
+	c := NewObjectClient(store, cfg)
+	ctx := context.Background()
+
+	objects := map[string]string{
+		"tenant-1/1f/1:2:3":                     "chunk of tenant 1",
+		"tenant-1/1f/4:5:6":                     "another chunk of tenant 1",
+		"tenant-2/2f/1:2:3":                     "chunk of tenant 2",
+		"index/index_19000/tenant-1/1.tsdb.gz":  "index of tenant 1",
+		"index/index_19000/tenant-2/2.tsdb.gz":  "index of tenant 2",
+		"index/index_19000/compacted-1.tsdb.gz": "index shared by the tenants",
+		"index/index_19001/compacted-2.tsdb.gz": "another index shared by the tenants",
+		"rules/tenant-1/namespace":              "object which isn't a chunk",
+	}
+	for key, content := range objects {
+		require.NoError(t, c.PutObject(ctx, key, strings.NewReader(content)))
+	}
+
+	for key, content := range objects {
+		require.Equal(t, content, readObject(t, c, key))
+
+		// the objects are encrypted in the underlying store
+		raw := readObject(t, store, key)
+		require.True(t, strings.HasPrefix(raw, string(magic)))
+		require.NotContains(t, raw, content)
+	}
+
+	// a single data key per tenant, and one for the shared index files and the objects which aren't chunks
+	for _, prefix := range []string{"encryption-keys/tenants/tenant-1/", "encryption-keys/tenants/tenant-2/", "encryption-keys/shared/"} {
+		require.Len(t, listKeys(t, store, prefix), 1, prefix)
+	}
+	require.Empty(t, listKeys(t, store, "encryption-keys/tenants/rules/"))
+	// the data keys are hidden
+	require.Len(t, listKeys(t, c, ""), len(objects))
+	_, prefixes, err := c.List(ctx, "", "/")
+	require.NoError(t, err)
+	require.ElementsMatch(t, []client.StorageCommonPrefix{"index/", "rules/", "tenant-1/", "tenant-2/"}, prefixes)
+
+	// objects which aren't encrypted are read as is
+	require.NoError(t, store.PutObject(ctx, "tenant-1/1f/7:8:9", strings.NewReader("plain chunk")))
+	require.Equal(t, "plain chunk", readObject(t, c, "tenant-1/1f/7:8:9"))
+
+	// a corrupted object fails to decrypt
+	raw := []byte(readObject(t, store, "tenant-1/1f/1:2:3"))
+	raw[len(raw)-1] ^= 0xff
+	require.NoError(t, store.PutObject(ctx, "tenant-1/1f/1:2:3", bytes.NewReader(raw)))
+	_, _, err = c.GetObject(ctx, "tenant-1/1f/1:2:3")
+	require.Error(t, err)
+	require.False(t, c.IsObjectNotFoundErr(err))
+}
+
+func TestObjectClient_DeletedDataKeys(t *testing.T) {
+	store, err := local.NewFSObjectClient(local.FSConfig{Directory: t.TempDir()})
+	require.NoError(t, err)
+	cfg := newTestConfig(t)
+	cfg.KeyCacheTTL = 0
+	ctx := context.Background()
+
+	// clients of different processes share the data keys
+	writer, reader := NewObjectClient(store, cfg), NewObjectClient(store, cfg)
+	require.NoError(t, writer.PutObject(ctx, "tenant-1/1f/1:2:3", strings.NewReader("chunk of tenant 1")))
+	require.NoError(t, reader.PutObject(ctx, "tenant-1/1f/4:5:6", strings.NewReader("another chunk of tenant 1")))
+	require.NoError(t, writer.PutObject(ctx, "tenant-2/2f/1:2:3", strings.NewReader("chunk of tenant 2")))
+	require.Len(t, listKeys(t, store, "encryption-keys/tenants/tenant-1/"), 1)
+	require.Equal(t, "chunk of tenant 1", readObject(t, reader, "tenant-1/1f/1:2:3"))
+	require.Equal(t, "another chunk of tenant 1", readObject(t, writer, "tenant-1/1f/4:5:6"))
+
+	// deleting the data keys of a tenant makes its objects unreadable
+	for _, key := range listKeys(t, store, "encryption-keys/tenants/tenant-1/") {
+		require.NoError(t, store.DeleteObject(ctx, key))
+	}
+	_, _, err = reader.GetObject(ctx, "tenant-1/1f/1:2:3")
+	require.Error(t, err)
+	require.True(t, reader.IsObjectNotFoundErr(err))
+	require.Equal(t, "chunk of tenant 2", readObject(t, reader, "tenant-2/2f/1:2:3"))
+
+	// new objects of the tenant are encrypted with a new data key
+	require.NoError(t, writer.PutObject(ctx, "tenant-1/1f/7:8:9", strings.NewReader("new chunk of tenant 1")))
+	require.Equal(t, "new chunk of tenant 1", readObject(t, reader, "tenant-1/1f/7:8:9"))
+	_, _, err = reader.GetObject(ctx, "tenant-1/1f/4:5:6")
+	require.True(t, reader.IsObjectNotFoundErr(err))
+}
+
+func TestObjectClient_ConcurrentPuts(t *testing.T) {
+	store, err := local.NewFSObjectClient(local.FSConfig{Directory: t.TempDir()})
+	require.NoError(t, err)
+	c := NewObjectClient(store, newTestConfig(t))
+
+	// the data key of a tenant is generated once
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			key := fmt.Sprintf("tenant-1/1f/%x:2:3", i)
+			require.NoError(t, c.PutObject(context.Background(), key, strings.NewReader(key)))
+		}(i)
+	}
+	wg.Wait()
+	require.Len(t, listKeys(t, store, "encryption-keys/tenants/tenant-1/"), 1)
+	for i := 0; i < 10; i++ {
+		key := fmt.Sprintf("tenant-1/1f/%x:2:3", i)
+		require.Equal(t, key, readObject(t, c, key))
+	}
+}
@@ -0,0 +1,125 @@
+package client
+
+import (
+	"context"
+	"io"
+	"sort"
+)
+
+// TieredObjectClient stores objects in a hot ObjectClient and reads them from a cold ObjectClient
+// once they have been moved there, for example by the compactor. Objects are looked up in the hot
+// tier first so reads only pay for the cold tier when the object isn't found in the hot one.
+type TieredObjectClient struct {
+	hot  ObjectClient
+	cold ObjectClient
+}
+
+// NewTieredObjectClient makes a new TieredObjectClient writing to hot and reading from both tiers.
+func NewTieredObjectClient(hot, cold ObjectClient) *TieredObjectClient {
+	return &TieredObjectClient{
+		hot:  hot,
+		cold: cold,
+	}
+}
+
+// Hot returns the ObjectClient of the hot tier.
+func (t *TieredObjectClient) Hot() ObjectClient {
+	return t.hot
+}
+
+// Cold returns the ObjectClient of the cold tier.
+func (t *TieredObjectClient) Cold() ObjectClient {
+	return t.cold
+}
+
+func (t *TieredObjectClient) ObjectExists(ctx context.Context, objectKey string) (bool, error) {
+	exists, err := t.hot.ObjectExists(ctx, objectKey)
+	if exists || (err != nil && !t.hot.IsObjectNotFoundErr(err)) {
+		return exists, err
+	}
+	return t.cold.ObjectExists(ctx, objectKey)
+}
+
+// PutObject always writes to the hot tier.
+func (t *TieredObjectClient) PutObject(ctx context.Context, objectKey string, object io.ReadSeeker) error {
+	return t.hot.PutObject(ctx, objectKey, object)
+}
+
+func (t *TieredObjectClient) GetObject(ctx context.Context, objectKey string) (io.ReadCloser, int64, error) {
+	reader, size, err := t.hot.GetObject(ctx, objectKey)
+	if err == nil || !t.hot.IsObjectNotFoundErr(err) {
+		return reader, size, err
+	}
+	return t.cold.GetObject(ctx, objectKey)
+}
+
+// List returns the objects and common prefixes of both tiers. Objects being moved
+// between the tiers are only returned once, with the modification time of the hot tier.
+func (t *TieredObjectClient) List(ctx context.Context, prefix string, delimiter string) ([]StorageObject, []StorageCommonPrefix, error) {
+	hotObjects, hotPrefixes, err := t.hot.List(ctx, prefix, delimiter)
+	if err != nil {
+		return nil, nil, err
+	}
+	coldObjects, coldPrefixes, err := t.cold.List(ctx, prefix, delimiter)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(coldObjects) == 0 && len(coldPrefixes) == 0 {
+		return hotObjects, hotPrefixes, nil
+	}
+
+	objects := make([]StorageObject, 0, len(hotObjects)+len(coldObjects))
+	seenObjects := make(map[string]struct{}, len(hotObjects))
+	for _, object := range hotObjects {
+		seenObjects[object.Key] = struct{}{}
+		objects = append(objects, object)
+	}
+	for _, object := range coldObjects {
+		if _, ok := seenObjects[object.Key]; !ok {
+			objects = append(objects, object)
+		}
+	}
+	sort.Slice(objects, func(i, j int) bool { return objects[i].Key < objects[j].Key })
+
+	prefixes := make([]StorageCommonPrefix, 0, len(hotPrefixes)+len(coldPrefixes))
+	seenPrefixes := make(map[StorageCommonPrefix]struct{}, len(hotPrefixes))
+	for _, p := range append(hotPrefixes, coldPrefixes...) {
+		if _, ok := seenPrefixes[p]; !ok {
+			seenPrefixes[p] = struct{}{}
+			prefixes = append(prefixes, p)
+		}
+	}
+	sort.Slice(prefixes, func(i, j int) bool { return prefixes[i] < prefixes[j] })
+
+	return objects, prefixes, nil
+}
+
+// DeleteObject deletes the object from both tiers. It only returns a not found error
+// when the object is found in none of the tiers.
+func (t *TieredObjectClient) DeleteObject(ctx context.Context, objectKey string) error {
+	hotErr := t.hot.DeleteObject(ctx, objectKey)
+	if hotErr != nil && !t.hot.IsObjectNotFoundErr(hotErr) {
+		return hotErr
+	}
+	coldErr := t.cold.DeleteObject(ctx, objectKey)
+	if coldErr != nil && !t.cold.IsObjectNotFoundErr(coldErr) {
+		return coldErr
+	}
+	if hotErr != nil && coldErr != nil {
+		return hotErr
+	}
+	return nil
+}
+
+func (t *TieredObjectClient) IsObjectNotFoundErr(err error) bool {
+	return t.hot.IsObjectNotFoundErr(err) || t.cold.IsObjectNotFoundErr(err)
+}
+
+func (t *TieredObjectClient) IsRetryableErr(err error) bool {
+	return t.hot.IsRetryableErr(err) || t.cold.IsRetryableErr(err)
+}
+
+func (t *TieredObjectClient) Stop() {
+	t.hot.Stop()
+	t.cold.Stop()
+}
@@ -0,0 +1,71 @@
+package client_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/pkg/storage/chunk/client"
+	"github.com/grafana/loki/pkg/storage/chunk/client/local"
+)
+
+func TestTieredObjectClient(t *testing.T) {
+	ctx := context.Background()
+	hot, err := local.NewFSObjectClient(local.FSConfig{Directory: t.TempDir()})
+	require.NoError(t, err)
+	cold, err := local.NewFSObjectClient(local.FSConfig{Directory: t.TempDir()})
+	require.NoError(t, err)
+	tiered := client.NewTieredObjectClient(hot, cold)
+
+	require.NoError(t, hot.PutObject(ctx, "fake/hot", bytes.NewReader([]byte("hot"))))
+	require.NoError(t, cold.PutObject(ctx, "fake/cold", bytes.NewReader([]byte("cold"))))
+	require.NoError(t, hot.PutObject(ctx, "fake/moving", bytes.NewReader([]byte("moving"))))
+	require.NoError(t, cold.PutObject(ctx, "fake/moving", bytes.NewReader([]byte("moving"))))
+	require.NoError(t, cold.PutObject(ctx, "other/cold", bytes.NewReader([]byte("cold"))))
+
+	// Objects are read from the tier they are stored in.
+	for key, expected := range map[string]string{"fake/hot": "hot", "fake/cold": "cold", "fake/moving": "moving"} {
+		reader, _, err := tiered.GetObject(ctx, key)
+		require.NoError(t, err)
+		data, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		require.NoError(t, reader.Close())
+		require.Equal(t, expected, string(data))
+	}
+	_, _, err = tiered.GetObject(ctx, "fake/missing")
+	require.True(t, tiered.IsObjectNotFoundErr(err))
+
+	// Listings of both tiers are merged.
+	objects, prefixes, err := tiered.List(ctx, "", "/")
+	require.NoError(t, err)
+	require.Empty(t, objects)
+	require.Equal(t, []client.StorageCommonPrefix{"fake/", "other/"}, prefixes)
+
+	objects, _, err = tiered.List(ctx, "fake/", "")
+	require.NoError(t, err)
+	keys := make([]string, 0, len(objects))
+	for _, object := range objects {
+		keys = append(keys, object.Key)
+	}
+	require.Equal(t, []string{"fake/cold", "fake/hot", "fake/moving"}, keys)
+
+	// New objects are written to the hot tier.
+	require.NoError(t, tiered.PutObject(ctx, "fake/new", bytes.NewReader([]byte("new"))))
+	reader, _, err := hot.GetObject(ctx, "fake/new")
+	require.NoError(t, err)
+	require.NoError(t, reader.Close())
+
+	// Objects are deleted from both tiers.
+	require.NoError(t, tiered.DeleteObject(ctx, "fake/moving"))
+	require.NoError(t, tiered.DeleteObject(ctx, "fake/cold"))
+	for _, c := range []client.ObjectClient{hot, cold} {
+		for _, key := range []string{"fake/moving", "fake/cold"} {
+			_, _, err := c.GetObject(ctx, key)
+			require.True(t, c.IsObjectNotFoundErr(err))
+		}
+	}
+	require.True(t, tiered.IsObjectNotFoundErr(tiered.DeleteObject(ctx, "fake/missing")))
+}
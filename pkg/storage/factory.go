@@ -22,6 +22,7 @@ import (
 	"github.com/grafana/loki/pkg/storage/chunk/client/baidubce"
 	"github.com/grafana/loki/pkg/storage/chunk/client/cassandra"
 	"github.com/grafana/loki/pkg/storage/chunk/client/congestion"
+	"github.com/grafana/loki/pkg/storage/chunk/client/encryption"
 	"github.com/grafana/loki/pkg/storage/chunk/client/gcp"
 	"github.com/grafana/loki/pkg/storage/chunk/client/grpc"
 	"github.com/grafana/loki/pkg/storage/chunk/client/hedging"
@@ -310,6 +311,45 @@ func (ns *NamedStores) Validate() error {
 	return ns.populateStoreType()
 }
 
+// ColdStorageConfig configures the object store to which the compactor moves the data
+// older than the cold_storage_after limit of the tenants.
+type ColdStorageConfig struct {
+	ObjectStore string                 `yaml:"object_store"`
+	Stores      flagext.StringSliceCSV `yaml:"stores"`
+}
+
+func (cfg *ColdStorageConfig) RegisterFlagsWithPrefix(prefix string, f *flag.FlagSet) {
+	f.StringVar(&cfg.ObjectStore, prefix+"cold-storage.object-store", "", "Object store to which the compactor moves the chunks and index files older than the cold_storage_after limit of the tenants. It can be one of the supported object store types or the name of a named store. Objects are read from this store once they aren't found in the store of their schema period. Moving data to a cold storage is disabled if empty.")
+	f.Var(&cfg.Stores, prefix+"cold-storage.stores", "Comma separated list of the object stores of the schema periods whose data is moved to the cold storage. Only the reads of these stores look up the cold storage. Required when a cold storage is configured.")
+}
+
+// Tiered returns whether the data of the object store is moved to the cold storage.
+func (cfg *ColdStorageConfig) Tiered(name string) bool {
+	return cfg.Enabled() && name != cfg.ObjectStore && util.StringsContain(cfg.Stores, name)
+}
+
+// Enabled returns whether a cold storage is configured.
+func (cfg *ColdStorageConfig) Enabled() bool {
+	return cfg.ObjectStore != ""
+}
+
+func (cfg *ColdStorageConfig) Validate(namedStores NamedStores) error {
+	if !cfg.Enabled() {
+		return nil
+	}
+	if len(cfg.Stores) == 0 {
+		return errors.New("the object stores whose data is moved to the cold storage must be set")
+	}
+	storeType := cfg.ObjectStore
+	if nsType, ok := namedStores.storeType[cfg.ObjectStore]; ok {
+		storeType = nsType
+	}
+	if !util.StringsContain(supportedStorageTypes, storeType) {
+		return fmt.Errorf("invalid cold storage object store %q, must be one of %s or a named store", cfg.ObjectStore, strings.Join(supportedStorageTypes, ", "))
+	}
+	return nil
+}
+
 // Config chooses which storage client to use.
 type Config struct {
 	AlibabaStorageConfig   alibaba.OssConfig         `yaml:"alibabacloud"`
@@ -325,6 +365,8 @@ type Config struct {
 	GrpcConfig             grpc.Config               `yaml:"grpc_store" doc:"deprecated"`
 	Hedging                hedging.Config            `yaml:"hedging"`
 	NamedStores            NamedStores               `yaml:"named_stores"`
+	ColdStorage            ColdStorageConfig         `yaml:"cold_storage" doc:"description=Configures the object store to which the compactor moves the data older than the cold_storage_after limit of the tenants."`
+	Encryption             encryption.Config         `yaml:"encryption" doc:"description=Configures the client-side encryption of the chunks and index files in the object stores."`
 	COSConfig              ibmcloud.COSConfig        `yaml:"cos"`
 	IndexCacheValidity     time.Duration             `yaml:"index_cache_validity"`
 	CongestionControl      congestion.Config         `yaml:"congestion_control,omitempty"`
@@ -359,6 +401,8 @@ func (cfg *Config) RegisterFlags(f *flag.FlagSet) {
 	cfg.GrpcConfig.RegisterFlags(f)
 	cfg.Hedging.RegisterFlagsWithPrefix("store.", f)
 	cfg.CongestionControl.RegisterFlagsWithPrefix("store.", f)
+	cfg.ColdStorage.RegisterFlagsWithPrefix("store.", f)
+	cfg.Encryption.RegisterFlagsWithPrefix("store.", f)
 
 	cfg.IndexQueriesCacheConfig.RegisterFlagsWithPrefix("store.index-cache-read.", "", f)
 	f.DurationVar(&cfg.IndexCacheValidity, "store.index-cache-validity", 5*time.Minute, "Cache validity for active index entries. Should be no higher than -ingester.max-chunk-idle.")
@@ -397,7 +441,13 @@ func (cfg *Config) Validate() error {
 		return errors.Wrap(err, "invalid bloom shipper config")
 	}
 
-	return cfg.NamedStores.Validate()
+	if err := cfg.NamedStores.Validate(); err != nil {
+		return err
+	}
+	if err := cfg.Encryption.Validate(); err != nil {
+		return err
+	}
+	return cfg.ColdStorage.Validate(cfg.NamedStores)
 }
 
 // NewIndexClient creates a new index client of the desired type specified in the PeriodConfig
@@ -652,7 +702,30 @@ func (c *ClientMetrics) Unregister() {
 }
 
 // NewObjectClient makes a new StorageClient of the desired types.
+// When its data is moved to the cold storage, the objects moved are also read from it.
 func NewObjectClient(name string, cfg Config, clientMetrics ClientMetrics) (client.ObjectClient, error) {
+	c, err := newObjectClient(name, cfg, clientMetrics)
+	if err != nil || !cfg.ColdStorage.Tiered(name) {
+		return c, err
+	}
+
+	cold, err := newObjectClient(cfg.ColdStorage.ObjectStore, cfg, clientMetrics)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cold storage object client: %w", err)
+	}
+	return client.NewTieredObjectClient(c, cold), nil
+}
+
+// newObjectClient makes a new StorageClient of the desired type, which encrypts the objects if enabled.
+func newObjectClient(name string, cfg Config, clientMetrics ClientMetrics) (client.ObjectClient, error) {
+	c, err := newStoreObjectClient(name, cfg, clientMetrics)
+	if err != nil || !cfg.Encryption.Enabled {
+		return c, err
+	}
+	return encryption.NewObjectClient(c, cfg.Encryption), nil
+}
+
+func newStoreObjectClient(name string, cfg Config, clientMetrics ClientMetrics) (client.ObjectClient, error) {
 	var (
 		namedStore string
 		storeType  = name
@@ -12,6 +12,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/grafana/loki/pkg/storage/chunk/client"
 	"github.com/grafana/loki/pkg/storage/chunk/client/cassandra"
 	"github.com/grafana/loki/pkg/storage/chunk/client/local"
 	"github.com/grafana/loki/pkg/storage/config"
@@ -248,3 +249,31 @@ func DefaultSchemaConfig(store, schema string, from model.Time) config.SchemaCon
 	}
 	return s
 }
+
+func TestNewObjectClient_ColdStorage(t *testing.T) {
+	tempDir := t.TempDir()
+	cfg := Config{
+		NamedStores: NamedStores{
+			Filesystem: map[string]NamedFSConfig{
+				"hot":   {Directory: path.Join(tempDir, "hot")},
+				"other": {Directory: path.Join(tempDir, "other")},
+				"cold":  {Directory: path.Join(tempDir, "cold")},
+			},
+		},
+		ColdStorage: ColdStorageConfig{ObjectStore: "cold"},
+	}
+	require.NoError(t, cfg.NamedStores.Validate())
+	require.Error(t, cfg.ColdStorage.Validate(cfg.NamedStores))
+
+	cfg.ColdStorage.Stores = []string{"hot"}
+	require.NoError(t, cfg.ColdStorage.Validate(cfg.NamedStores))
+
+	// Only the object stores whose data is moved look up the cold storage.
+	for name, tiered := range map[string]bool{"hot": true, "other": false, "cold": false} {
+		objectClient, err := NewObjectClient(name, cfg, cm)
+		require.NoError(t, err)
+		_, ok := objectClient.(*client.TieredObjectClient)
+		require.Equal(t, tiered, ok, name)
+		objectClient.Stop()
+	}
+}
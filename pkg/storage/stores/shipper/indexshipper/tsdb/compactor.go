@@ -303,8 +303,20 @@ func (c *compactedIndex) ForEachChunk(ctx context.Context, callback retention.Ch
 		chunkEntry.SeriesID = getUnsafeBytes(seriesID)
 		chunkEntry.Labels = withoutTenantLabel(stream.labels)
 
+		// skip the chunks already lined up for deletion by a previous iteration
+		var deletedChunks map[tsdbindex.ChunkMeta]struct{}
+		if chks := c.deleteChunks[seriesID]; len(chks) > 0 {
+			deletedChunks = make(map[tsdbindex.ChunkMeta]struct{}, len(chks))
+			for _, chk := range chks {
+				deletedChunks[chk] = struct{}{}
+			}
+		}
+
 		for i := 0; i < len(stream.chunks) && ctx.Err() == nil; i++ {
 			chk := stream.chunks[i]
+			if _, ok := deletedChunks[chk]; ok {
+				continue
+			}
 			logprotoChunkRef.From = chk.From()
 			logprotoChunkRef.Through = chk.Through()
 			logprotoChunkRef.Checksum = chk.Checksum
@@ -312,6 +324,8 @@ func (c *compactedIndex) ForEachChunk(ctx context.Context, callback retention.Ch
 			chunkEntry.ChunkID = getUnsafeBytes(schemaCfg.ExternalKey(logprotoChunkRef))
 			chunkEntry.From = logprotoChunkRef.From
 			chunkEntry.Through = logprotoChunkRef.Through
+			chunkEntry.KB = chk.KB
+			chunkEntry.Entries = chk.Entries
 
 			deleteChunk, err := callback(chunkEntry)
 			if err != nil {
@@ -635,7 +635,9 @@ func chunkMetasToChunkEntry(schemaCfg config.SchemaConfig, userID string, lbls l
 				From:     chunkMeta.From(),
 				Through:  chunkMeta.Through(),
 			},
-			Labels: lbls,
+			Labels:  lbls,
+			KB:      chunkMeta.KB,
+			Entries: chunkMeta.Entries,
 		})
 	}
 
@@ -805,6 +807,15 @@ func TestCompactedIndex(t *testing.T) {
 
 			require.Equal(t, testCtx.expectedChunkEntries, foundChunkEntries)
 
+			// chunks lined up for deletion should not be iterated again
+			err = compactedIndex.ForEachChunk(context.Background(), func(chunkEntry retention.ChunkEntry) (deleteChunk bool, err error) {
+				for _, chk := range tc.deleteChunks[string(chunkEntry.SeriesID)] {
+					require.False(t, chk.MinTime == int64(chunkEntry.From) && chk.MaxTime == int64(chunkEntry.Through))
+				}
+				return false, nil
+			})
+			require.NoError(t, err)
+
 			for _, lbls := range tc.deleteSeries {
 				require.NoError(t, compactedIndex.CleanupSeries(nil, lbls))
 			}
@@ -3,6 +3,7 @@ package httpreq
 import (
 	"context"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/grafana/dskit/middleware"
@@ -16,8 +17,33 @@ var (
 
 	// LokiActorPathDelimiter is the delimiter used to serialise the hierarchy of the actor.
 	LokiActorPathDelimiter = "|"
+
+	// LokiAllowPartialResultsHeader is the name of the header used to opt into partial query results.
+	LokiAllowPartialResultsHeader = "X-Loki-Allow-Partial-Results"
+
+	// LokiPushAckModeHeader is the name of the header used to choose when a push request is acknowledged.
+	LokiPushAckModeHeader = "X-Loki-Ack-Mode"
+)
+
+// The acknowledgement modes of push requests.
+const (
+	// PushAckModeAsync acknowledges push requests once validated, before they are sent to the ingesters.
+	PushAckModeAsync = "async"
+	// PushAckModeQuorum acknowledges push requests once a quorum of the ingesters of each stream received them.
+	PushAckModeQuorum = "quorum"
+	// PushAckModeAll acknowledges push requests once all the ingesters of each stream received them.
+	PushAckModeAll = "all"
 )
 
+// ValidPushAckMode returns whether mode is a valid acknowledgement mode, the empty mode being the default one.
+func ValidPushAckMode(mode string) bool {
+	switch mode {
+	case "", PushAckModeAsync, PushAckModeQuorum, PushAckModeAll:
+		return true
+	}
+	return false
+}
+
 func PropagateHeadersMiddleware(headers ...string) middleware.Interface {
 	return middleware.Func(func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
@@ -46,3 +72,30 @@ func ExtractActorPath(ctx context.Context) []string {
 	}
 	return strings.Split(value, LokiActorPathDelimiter)
 }
+
+// AllowPartialResults returns whether the request opted into partial results,
+// either via the X-Loki-Allow-Partial-Results header or InjectAllowPartialResults.
+func AllowPartialResults(ctx context.Context) bool {
+	allow, _ := strconv.ParseBool(ExtractHeader(ctx, LokiAllowPartialResultsHeader))
+	return allow
+}
+
+// InjectAllowPartialResults returns a derived context that allows partial results.
+func InjectAllowPartialResults(ctx context.Context) context.Context {
+	return context.WithValue(ctx, headerContextKey(LokiAllowPartialResultsHeader), "true")
+}
+
+// PushAckMode returns the acknowledgement mode of the push request,
+// set either via the X-Loki-Ack-Mode header or InjectPushAckMode.
+// It defaults to PushAckModeQuorum.
+func PushAckMode(ctx context.Context) string {
+	if mode := ExtractHeader(ctx, LokiPushAckModeHeader); mode != "" {
+		return mode
+	}
+	return PushAckModeQuorum
+}
+
+// InjectPushAckMode returns a derived context with the given acknowledgement mode.
+func InjectPushAckMode(ctx context.Context, mode string) context.Context {
+	return context.WithValue(ctx, headerContextKey(LokiPushAckModeHeader), mode)
+}
@@ -0,0 +1,262 @@
+// Package kms provides the master keys wrapping the data keys used to encrypt data at rest.
+package kms
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	ProviderFile   = "file"
+	ProviderPlugin = "plugin"
+
+	// DataKeySize is the size of the AES-256 data keys.
+	DataKeySize = 32
+)
+
+// Config configures the provider of the master keys.
+type Config struct {
+	Provider     string `yaml:"key_provider"`
+	KeyFile      string `yaml:"key_file"`
+	PluginSocket string `yaml:"plugin_socket"`
+
+	// KeyProvider wraps the data keys, overriding the configured provider.
+	KeyProvider KeyProvider `yaml:"-"`
+}
+
+// RegisterFlagsWithPrefix adds the flags required to config this to the given FlagSet
+func (cfg *Config) RegisterFlagsWithPrefix(prefix string, f *flag.FlagSet) {
+	f.StringVar(&cfg.Provider, prefix+"key-provider", ProviderFile, "The provider of the master keys wrapping the data keys. Supported values are: file, plugin.")
+	f.StringVar(&cfg.KeyFile, prefix+"key-file", "", "Path of the YAML file holding the base64 encoded 256-bit master keys by ID in 'keys', and the ID of the key wrapping new data keys in 'current'. The file is read again whenever data keys are wrapped or unwrapped, so that master keys can be rotated without a restart.")
+	f.StringVar(&cfg.PluginSocket, prefix+"plugin-socket", "", "Path of the Unix socket of the local KMS plugin wrapping the data keys. The plugin serves the POST /v1/wrap and POST /v1/unwrap JSON endpoints.")
+}
+
+func (cfg *Config) Validate() error {
+	if cfg.KeyProvider != nil {
+		return nil
+	}
+	switch cfg.Provider {
+	case ProviderFile:
+		if cfg.KeyFile == "" {
+			return errors.New("the key file is required with the file key provider")
+		}
+	case ProviderPlugin:
+		if cfg.PluginSocket == "" {
+			return errors.New("the plugin socket is required with the plugin key provider")
+		}
+	default:
+		return errors.Errorf("invalid key provider %q, expected one of file or plugin", cfg.Provider)
+	}
+	return nil
+}
+
+// KeyProvider wraps and unwraps data keys with master keys, which are never stored alongside the data.
+type KeyProvider interface {
+	// Wrap encrypts a data key with the current master key and returns the ID of the master key.
+	Wrap(ctx context.Context, dataKey []byte) (keyID string, wrapped []byte, err error)
+	// Unwrap decrypts a data key wrapped by the master key with the given ID.
+	Unwrap(ctx context.Context, keyID string, wrapped []byte) ([]byte, error)
+}
+
+// NewKeyProvider returns the configured KeyProvider.
+func NewKeyProvider(cfg Config) KeyProvider {
+	if cfg.KeyProvider != nil {
+		return cfg.KeyProvider
+	}
+	if cfg.Provider == ProviderPlugin {
+		return NewPluginKeyProvider(cfg.PluginSocket)
+	}
+	return NewFileKeyProvider(cfg.KeyFile)
+}
+
+// FileKeyProvider wraps the data keys with the master keys of a local file.
+type FileKeyProvider struct {
+	path string
+}
+
+// MasterKeysFile is the content of the file of a FileKeyProvider.
+type MasterKeysFile struct {
+	Current string            `yaml:"current"`
+	Keys    map[string]string `yaml:"keys"`
+}
+
+func NewFileKeyProvider(path string) *FileKeyProvider {
+	return &FileKeyProvider{path: path}
+}
+
+func (p *FileKeyProvider) key(keyID string) (string, cipher.AEAD, error) {
+	b, err := os.ReadFile(p.path)
+	if err != nil {
+		return "", nil, err
+	}
+	var file MasterKeysFile
+	if err := yaml.UnmarshalStrict(b, &file); err != nil {
+		return "", nil, fmt.Errorf("failed to parse key file: %w", err)
+	}
+	if keyID == "" {
+		keyID = file.Current
+	}
+	encoded, ok := file.Keys[keyID]
+	if !ok {
+		return "", nil, errors.Errorf("master key %q not found in key file", keyID)
+	}
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid master key %q: %w", keyID, err)
+	}
+	aead, err := NewAEAD(key)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid master key %q: %w", keyID, err)
+	}
+	return keyID, aead, nil
+}
+
+func (p *FileKeyProvider) Wrap(_ context.Context, dataKey []byte) (string, []byte, error) {
+	keyID, aead, err := p.key("")
+	if err != nil {
+		return "", nil, err
+	}
+	wrapped, err := Seal(aead, nil, dataKey, []byte(keyID))
+	return keyID, wrapped, err
+}
+
+func (p *FileKeyProvider) Unwrap(_ context.Context, keyID string, wrapped []byte) ([]byte, error) {
+	_, aead, err := p.key(keyID)
+	if err != nil {
+		return nil, err
+	}
+	return Open(aead, nil, wrapped, []byte(keyID))
+}
+
+// PluginKeyProvider wraps the data keys with a local KMS plugin serving JSON over a Unix socket.
+type PluginKeyProvider struct {
+	client *http.Client
+}
+
+type PluginWrapRequest struct {
+	Plaintext []byte `json:"plaintext"`
+}
+
+type PluginWrapResponse struct {
+	KeyID      string `json:"key_id"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+type PluginUnwrapRequest struct {
+	KeyID      string `json:"key_id"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+type PluginUnwrapResponse struct {
+	Plaintext []byte `json:"plaintext"`
+}
+
+func NewPluginKeyProvider(socket string) *PluginKeyProvider {
+	return &PluginKeyProvider{
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+			Transport: &http.Transport{
+				DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+					var d net.Dialer
+					return d.DialContext(ctx, "unix", socket)
+				},
+			},
+		},
+	}
+}
+
+func (p *PluginKeyProvider) call(ctx context.Context, path string, req, resp interface{}) error {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, "http://kms-plugin"+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpResp, err := p.client.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	defer httpResp.Body.Close()
+	if httpResp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(httpResp.Body, 1024))
+		return errors.Errorf("KMS plugin returned %s: %s", httpResp.Status, msg)
+	}
+	return json.NewDecoder(httpResp.Body).Decode(resp)
+}
+
+func (p *PluginKeyProvider) Wrap(ctx context.Context, dataKey []byte) (string, []byte, error) {
+	var resp PluginWrapResponse
+	if err := p.call(ctx, "/v1/wrap", PluginWrapRequest{Plaintext: dataKey}, &resp); err != nil {
+		return "", nil, err
+	}
+	return resp.KeyID, resp.Ciphertext, nil
+}
+
+func (p *PluginKeyProvider) Unwrap(ctx context.Context, keyID string, wrapped []byte) ([]byte, error) {
+	var resp PluginUnwrapResponse
+	if err := p.call(ctx, "/v1/unwrap", PluginUnwrapRequest{KeyID: keyID, Ciphertext: wrapped}, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Plaintext, nil
+}
+
+// NewDataKey returns a new random data key.
+func NewDataKey() ([]byte, error) {
+	key := make([]byte, DataKeySize)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// NewAEAD returns the AES-GCM AEAD of the key.
+func NewAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// Seal appends a random nonce and the encrypted plaintext to dst.
+func Seal(aead cipher.AEAD, dst, plaintext, additionalData []byte) ([]byte, error) {
+	nonceStart := len(dst)
+	dst = append(dst, make([]byte, aead.NonceSize())...)
+	if _, err := io.ReadFull(rand.Reader, dst[nonceStart:]); err != nil {
+		return nil, err
+	}
+	nonce := dst[nonceStart:]
+	return aead.Seal(dst, nonce, plaintext, additionalData), nil
+}
+
+// Open decrypts a ciphertext prefixed by its nonce and appends the plaintext to dst.
+func Open(aead cipher.AEAD, dst, ciphertext, additionalData []byte) ([]byte, error) {
+	if len(ciphertext) < aead.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+	nonce, ciphertext := ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():]
+	return aead.Open(dst, nonce, ciphertext, additionalData)
+}
+
+// Overhead returns the size added to a plaintext by Seal.
+func Overhead(aead cipher.AEAD) int {
+	return aead.NonceSize() + aead.Overhead()
+}
@@ -0,0 +1,104 @@
+package kms
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+func TestFileKeyProvider(t *testing.T) {
+	newKey := func() string {
+		key := make([]byte, DataKeySize)
+		_, err := rand.Read(key)
+		require.NoError(t, err)
+		return base64.StdEncoding.EncodeToString(key)
+	}
+	writeKeys := func(path string, file MasterKeysFile) {
+		b, err := yaml.Marshal(file)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(path, b, 0o600))
+	}
+
+	path := filepath.Join(t.TempDir(), "keys.yaml")
+	key1, key2 := newKey(), newKey()
+	writeKeys(path, MasterKeysFile{Current: "key-1", Keys: map[string]string{"key-1": key1}})
+	provider := NewFileKeyProvider(path)
+
+	keyID, wrapped1, err := provider.Wrap(context.Background(), []byte("data key 1"))
+	require.NoError(t, err)
+	require.Equal(t, "key-1", keyID)
+
+	// the file is read again after the master key is rotated
+	writeKeys(path, MasterKeysFile{Current: "key-2", Keys: map[string]string{"key-1": key1, "key-2": key2}})
+	keyID, wrapped2, err := provider.Wrap(context.Background(), []byte("data key 2"))
+	require.NoError(t, err)
+	require.Equal(t, "key-2", keyID)
+
+	key, err := provider.Unwrap(context.Background(), "key-1", wrapped1)
+	require.NoError(t, err)
+	require.Equal(t, []byte("data key 1"), key)
+	key, err = provider.Unwrap(context.Background(), "key-2", wrapped2)
+	require.NoError(t, err)
+	require.Equal(t, []byte("data key 2"), key)
+
+	// a data key can't be unwrapped with another master key
+	_, err = provider.Unwrap(context.Background(), "key-2", wrapped1)
+	require.Error(t, err)
+	_, err = provider.Unwrap(context.Background(), "key-3", wrapped1)
+	require.Error(t, err)
+}
+
+func TestPluginKeyProvider(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "kms.sock")
+	listener, err := net.Listen("unix", socket)
+	require.NoError(t, err)
+
+	// a fake KMS plugin which "wraps" the keys by reversing them
+	reverse := func(b []byte) []byte {
+		res := make([]byte, len(b))
+		for i := range b {
+			res[len(b)-1-i] = b[i]
+		}
+		return res
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/wrap", func(w http.ResponseWriter, r *http.Request) {
+		var req PluginWrapRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		require.NoError(t, json.NewEncoder(w).Encode(PluginWrapResponse{KeyID: "kms-key", Ciphertext: reverse(req.Plaintext)}))
+	})
+	mux.HandleFunc("/v1/unwrap", func(w http.ResponseWriter, r *http.Request) {
+		var req PluginUnwrapRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		if req.KeyID != "kms-key" {
+			http.Error(w, "unknown key", http.StatusNotFound)
+			return
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(PluginUnwrapResponse{Plaintext: reverse(req.Ciphertext)}))
+	})
+	server := &http.Server{Handler: mux}
+	go server.Serve(listener) //nolint:errcheck
+	defer server.Close()
+
+	provider := NewPluginKeyProvider(socket)
+	keyID, wrapped, err := provider.Wrap(context.Background(), []byte("data key"))
+	require.NoError(t, err)
+	require.Equal(t, "kms-key", keyID)
+	require.Equal(t, []byte("yek atad"), wrapped)
+
+	key, err := provider.Unwrap(context.Background(), keyID, wrapped)
+	require.NoError(t, err)
+	require.Equal(t, []byte("data key"), key)
+
+	_, err = provider.Unwrap(context.Background(), "other-key", wrapped)
+	require.Error(t, err)
+}
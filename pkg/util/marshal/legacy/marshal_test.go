@@ -139,6 +139,15 @@ var queryTests = []struct {
 						"requests": 0,
 						"downloadTime": 0
 					},
+					"logResult": {
+						"entriesFound": 0,
+						"entriesRequested": 0,
+						"entriesStored": 0,
+						"bytesReceived": 0,
+						"bytesSent": 0,
+						"requests": 0,
+						"downloadTime": 0
+					},
 					"result": {
 						"entriesFound": 0,
 						"entriesRequested": 0,
@@ -25,7 +25,7 @@ func WriteResponseJSON(r *http.Request, v any, w http.ResponseWriter) error {
 	case logqlmodel.Result:
 		version := loghttp.GetVersion(r.RequestURI)
 		if version == loghttp.VersionV1 {
-			return WriteQueryResponseJSON(result.Data, result.Statistics, w)
+			return WriteQueryResponseJSON(result.Data, result.Warnings, result.Statistics, w)
 		}
 
 		return marshal_legacy.WriteQueryResponseJSON(result, w)
@@ -48,10 +48,23 @@ func WriteResponseJSON(r *http.Request, v any, w http.ResponseWriter) error {
 
 // WriteQueryResponseJSON marshals the promql.Value to v1 loghttp JSON and then
 // writes it to the provided io.Writer.
-func WriteQueryResponseJSON(data parser.Value, statistics stats.Result, w io.Writer) error {
+func WriteQueryResponseJSON(data parser.Value, warnings []string, statistics stats.Result, w io.Writer) error {
 	s := jsoniter.ConfigFastest.BorrowStream(w)
 	defer jsoniter.ConfigFastest.ReturnStream(s)
-	err := EncodeResult(data, statistics, s)
+	err := EncodeResult(data, warnings, statistics, s)
+	if err != nil {
+		return fmt.Errorf("could not write JSON response: %w", err)
+	}
+	s.WriteRaw("\n")
+	return s.Flush()
+}
+
+// WriteExportStreamJSON marshals a logproto.Stream to a single line of v1
+// loghttp JSON and then writes it to the provided io.Writer.
+func WriteExportStreamJSON(stream logproto.Stream, w io.Writer) error {
+	s := jsoniter.ConfigFastest.BorrowStream(w)
+	defer jsoniter.ConfigFastest.ReturnStream(s)
+	err := encodeStream(stream, s)
 	if err != nil {
 		return fmt.Errorf("could not write JSON response: %w", err)
 	}
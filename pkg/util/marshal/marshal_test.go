@@ -142,6 +142,15 @@ var queryTests = []struct {
 							"requests": 0,
 							"downloadTime": 0
 						},
+						"logResult": {
+							"entriesFound": 0,
+							"entriesRequested": 0,
+							"entriesStored": 0,
+							"bytesReceived": 0,
+							"bytesSent": 0,
+							"requests": 0,
+							"downloadTime": 0
+						},
 						"result": {
 							"entriesFound": 0,
 							"entriesRequested": 0,
@@ -307,6 +316,15 @@ var queryTests = []struct {
 						"requests": 0,
 						"downloadTime": 0
 					},
+					"logResult": {
+						"entriesFound": 0,
+						"entriesRequested": 0,
+						"entriesStored": 0,
+						"bytesReceived": 0,
+						"bytesSent": 0,
+						"requests": 0,
+						"downloadTime": 0
+					},
 					"result": {
 						"entriesFound": 0,
 						"entriesRequested": 0,
@@ -493,6 +511,15 @@ var queryTests = []struct {
 						"requests": 0,
 						"downloadTime": 0
 					},
+					"logResult": {
+						"entriesFound": 0,
+						"entriesRequested": 0,
+						"entriesStored": 0,
+						"bytesReceived": 0,
+						"bytesSent": 0,
+						"requests": 0,
+						"downloadTime": 0
+					},
 					"result": {
 						"entriesFound": 0,
 						"entriesRequested": 0,
@@ -601,7 +628,7 @@ var tailTests = []struct {
 func Test_WriteQueryResponseJSON(t *testing.T) {
 	for i, queryTest := range queryTests {
 		var b bytes.Buffer
-		err := WriteQueryResponseJSON(queryTest.actual, stats.Result{}, &b)
+		err := WriteQueryResponseJSON(queryTest.actual, nil, stats.Result{}, &b)
 		require.NoError(t, err)
 
 		require.JSONEqf(t, queryTest.expected, b.String(), "Query Test %d failed", i)
@@ -633,10 +660,22 @@ func Test_WriteQueryResponseJSONWithError(t *testing.T) {
 		},
 	}
 	var b bytes.Buffer
-	err := WriteQueryResponseJSON(broken.Data, stats.Result{}, &b)
+	err := WriteQueryResponseJSON(broken.Data, nil, stats.Result{}, &b)
 	require.Error(t, err)
 }
 
+func Test_WriteQueryResponseJSONWithWarnings(t *testing.T) {
+	warnings := []string{"partial result: shard 1_of_2 failed"}
+
+	var b bytes.Buffer
+	err := WriteQueryResponseJSON(logqlmodel.Streams{}, warnings, stats.Result{}, &b)
+	require.NoError(t, err)
+
+	var resp loghttp.QueryResponse
+	require.NoError(t, json.Unmarshal(b.Bytes(), &resp))
+	require.Equal(t, warnings, resp.Warnings)
+}
+
 func Test_MarshalTailResponse(t *testing.T) {
 	for i, tailTest := range tailTests {
 		// convert logproto to model objects
@@ -883,7 +922,7 @@ func Benchmark_Encode(b *testing.B) {
 
 	for n := 0; n < b.N; n++ {
 		for _, queryTest := range queryTests {
-			require.NoError(b, WriteQueryResponseJSON(queryTest.actual, stats.Result{}, buf))
+			require.NoError(b, WriteQueryResponseJSON(queryTest.actual, nil, stats.Result{}, buf))
 			buf.Reset()
 		}
 	}
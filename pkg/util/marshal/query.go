@@ -174,7 +174,7 @@ func NewMetric(l labels.Labels) model.Metric {
 	return ret
 }
 
-func EncodeResult(data parser.Value, statistics stats.Result, s *jsoniter.Stream) error {
+func EncodeResult(data parser.Value, warnings []string, statistics stats.Result, s *jsoniter.Stream) error {
 	s.WriteObjectStart()
 	s.WriteObjectField("status")
 	s.WriteString("success")
@@ -186,6 +186,19 @@ func EncodeResult(data parser.Value, statistics stats.Result, s *jsoniter.Stream
 		return err
 	}
 
+	if len(warnings) > 0 {
+		s.WriteMore()
+		s.WriteObjectField("warnings")
+		s.WriteArrayStart()
+		for i, w := range warnings {
+			if i > 0 {
+				s.WriteMore()
+			}
+			s.WriteString(w)
+		}
+		s.WriteArrayEnd()
+	}
+
 	s.WriteObjectEnd()
 	return nil
 }
@@ -87,3 +87,17 @@ func MaxDurationOrZeroPerTenant(tenantIDs []string, f func(string) time.Duration
 	}
 	return *result
 }
+
+// AllTruePerTenant returns true only if f returns true for all given tenants.
+// Without tenants given it will return false.
+func AllTruePerTenant(tenantIDs []string, f func(string) bool) bool {
+	if len(tenantIDs) == 0 {
+		return false
+	}
+	for _, tenantID := range tenantIDs {
+		if !f(tenantID) {
+			return false
+		}
+	}
+	return true
+}
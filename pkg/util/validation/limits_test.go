@@ -3,6 +3,8 @@ package validation
 import (
 	"testing"
 	"time"
+
+	"github.com/stretchr/testify/require"
 )
 
 // nolint:goconst
@@ -220,3 +222,13 @@ func TestMaxDurationOrZeroPerTenant(t *testing.T) {
 		})
 	}
 }
+
+func TestAllTruePerTenant(t *testing.T) {
+	enabled := map[string]bool{"tenant1": true, "tenant2": true, "tenant3": false}
+	f := func(tenantID string) bool { return enabled[tenantID] }
+
+	require.False(t, AllTruePerTenant(nil, f))
+	require.True(t, AllTruePerTenant([]string{"tenant1"}, f))
+	require.True(t, AllTruePerTenant([]string{"tenant1", "tenant2"}, f))
+	require.False(t, AllTruePerTenant([]string{"tenant1", "tenant3"}, f))
+}
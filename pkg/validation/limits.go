@@ -20,6 +20,7 @@ import (
 	"gopkg.in/yaml.v2"
 
 	"github.com/grafana/loki/pkg/compactor/deletionmode"
+	"github.com/grafana/loki/pkg/distributor/labelpolicy"
 	"github.com/grafana/loki/pkg/distributor/shardstreams"
 	"github.com/grafana/loki/pkg/logql/syntax"
 	ruler_config "github.com/grafana/loki/pkg/ruler/config"
@@ -87,6 +88,7 @@ type Limits struct {
 	// Querier enforced limits.
 	MaxChunksPerQuery          int              `yaml:"max_chunks_per_query" json:"max_chunks_per_query"`
 	MaxQuerySeries             int              `yaml:"max_query_series" json:"max_query_series"`
+	MaxQueryJoinEntries        int              `yaml:"max_query_join_entries" json:"max_query_join_entries"`
 	MaxQueryLookback           model.Duration   `yaml:"max_query_lookback" json:"max_query_lookback"`
 	MaxQueryLength             model.Duration   `yaml:"max_query_length" json:"max_query_length"`
 	MaxQueryRange              model.Duration   `yaml:"max_query_range" json:"max_query_range"`
@@ -97,19 +99,22 @@ type Limits struct {
 	MaxStreamsMatchersPerQuery int              `yaml:"max_streams_matchers_per_query" json:"max_streams_matchers_per_query"`
 	MaxConcurrentTailRequests  int              `yaml:"max_concurrent_tail_requests" json:"max_concurrent_tail_requests"`
 	MaxEntriesLimitPerQuery    int              `yaml:"max_entries_limit_per_query" json:"max_entries_limit_per_query"`
+	MaxExportEntriesPerQuery   int              `yaml:"max_export_entries_per_query" json:"max_export_entries_per_query"`
 	MaxCacheFreshness          model.Duration   `yaml:"max_cache_freshness_per_query" json:"max_cache_freshness_per_query"`
 	MaxStatsCacheFreshness     model.Duration   `yaml:"max_stats_cache_freshness" json:"max_stats_cache_freshness"`
 	MaxQueriersPerTenant       int              `yaml:"max_queriers_per_tenant" json:"max_queriers_per_tenant"`
 	QueryReadyIndexNumDays     int              `yaml:"query_ready_index_num_days" json:"query_ready_index_num_days"`
 	QueryTimeout               model.Duration   `yaml:"query_timeout" json:"query_timeout"`
+	AllowPartialResults        bool             `yaml:"allow_partial_results" json:"allow_partial_results"`
 
 	// Query frontend enforced limits. The default is actually parameterized by the queryrange config.
-	QuerySplitDuration  model.Duration   `yaml:"split_queries_by_interval" json:"split_queries_by_interval"`
-	MinShardingLookback model.Duration   `yaml:"min_sharding_lookback" json:"min_sharding_lookback"`
-	MaxQueryBytesRead   flagext.ByteSize `yaml:"max_query_bytes_read" json:"max_query_bytes_read"`
-	MaxQuerierBytesRead flagext.ByteSize `yaml:"max_querier_bytes_read" json:"max_querier_bytes_read"`
-	VolumeEnabled       bool             `yaml:"volume_enabled" json:"volume_enabled" doc:"description=Enable log-volume endpoints."`
-	VolumeMaxSeries     int              `yaml:"volume_max_series" json:"volume_max_series" doc:"description=The maximum number of aggregated series in a log-volume response"`
+	QuerySplitDuration    model.Duration   `yaml:"split_queries_by_interval" json:"split_queries_by_interval"`
+	QuerySplitTargetBytes flagext.ByteSize `yaml:"split_queries_target_bytes" json:"split_queries_target_bytes"`
+	MinShardingLookback   model.Duration   `yaml:"min_sharding_lookback" json:"min_sharding_lookback"`
+	MaxQueryBytesRead     flagext.ByteSize `yaml:"max_query_bytes_read" json:"max_query_bytes_read"`
+	MaxQuerierBytesRead   flagext.ByteSize `yaml:"max_querier_bytes_read" json:"max_querier_bytes_read"`
+	VolumeEnabled         bool             `yaml:"volume_enabled" json:"volume_enabled" doc:"description=Enable log-volume endpoints."`
+	VolumeMaxSeries       int              `yaml:"volume_max_series" json:"volume_max_series" doc:"description=The maximum number of aggregated series in a log-volume response"`
 
 	// Ruler defaults and limits.
 
@@ -165,7 +170,10 @@ type Limits struct {
 
 	// Global and per tenant retention
 	RetentionPeriod model.Duration    `yaml:"retention_period" json:"retention_period"`
-	StreamRetention []StreamRetention `yaml:"retention_stream,omitempty" json:"retention_stream,omitempty" doc:"description=Per-stream retention to apply, if the retention is enable on the compactor side.\nExample:\n retention_stream:\n - selector: '{namespace=\"dev\"}'\n priority: 1\n period: 24h\n- selector: '{container=\"nginx\"}'\n priority: 1\n period: 744h\nSelector is a Prometheus labels matchers that will apply the 'period' retention only if the stream is matching. In case multiple stream are matching, the highest priority will be picked. If no rule is matched the 'retention_period' is used."`
+	StreamRetention []StreamRetention `yaml:"retention_stream,omitempty" json:"retention_stream,omitempty" doc:"description=Per-stream retention to apply, if the retention is enable on the compactor side.\nExample:\n retention_stream:\n - selector: '{namespace=\"dev\"}'\n priority: 1\n period: 24h\n- selector: '{container=\"nginx\"}'\n priority: 1\n period: 744h\nSelector is a Prometheus labels matchers that will apply the 'period' retention only if the stream is matching. In case multiple stream are matching, the highest priority will be picked. If no rule is matched the 'retention_period' is used.\nRules with a 'filter', a LogQL pipeline such as a line filter or a label filter on structured metadata, only delete the lines of the matching streams selected by the filter once they are older than the rule's period. They don't take part in the selection of the retention period of the streams."`
+
+	// Age of the data moved to the cold storage by the compactor
+	ColdStorageAfter model.Duration `yaml:"cold_storage_after" json:"cold_storage_after"`
 
 	// Config for overrides, convenient if it goes here.
 	PerTenantOverrideConfig string         `yaml:"per_tenant_override_config" json:"per_tenant_override_config"`
@@ -176,6 +184,10 @@ type Limits struct {
 
 	ShardStreams *shardstreams.Config `yaml:"shard_streams" json:"shard_streams"`
 
+	LabelPolicy *labelpolicy.Config `yaml:"label_policy" json:"label_policy" doc:"description=Policy enforced by the distributor on the labels of the pushed streams. Streams with a label which isn't allowed or with a label value exceeding the maximum number of distinct values of the label violate the policy."`
+
+	IngestionPipelines []IngestionPipeline `yaml:"ingestion_pipelines,omitempty" json:"ingestion_pipelines,omitempty" doc:"description=Ingestion pipelines applied by the distributor to the pushed entries after their validation and before they are sent to the ingesters.\nExample:\n ingestion_pipelines:\n - name: drop-debug\n selector: '{namespace=\"dev\"}'\n pipeline: '!= \"/health\"'\nEach pipeline is a sequence of LogQL pipeline stages applied to the entries of the streams matching the selector, or of all streams if no selector is set. Pipelines are applied in order. Entries filtered out by a stage are dropped. The unnest stage is not supported. Labels of the stream and labels set with label_format remain stream labels, labels listed in structured_metadata are stored as structured metadata and all other extracted labels are discarded."`
+
 	BlockedQueries []*validation.BlockedQuery `yaml:"blocked_queries,omitempty" json:"blocked_queries,omitempty"`
 
 	RequiredLabels       []string `yaml:"required_labels,omitempty" json:"required_labels,omitempty" doc:"description=Define a list of required selector labels."`
@@ -193,7 +205,19 @@ type StreamRetention struct {
 	Period   model.Duration    `yaml:"period" json:"period" doc:"description:Retention period applied to the log lines matching the selector."`
 	Priority int               `yaml:"priority" json:"priority" doc:"description:The larger the value, the higher the priority."`
 	Selector string            `yaml:"selector" json:"selector" doc:"description:Stream selector expression."`
+	Filter   string            `yaml:"filter,omitempty" json:"filter,omitempty" doc:"description:Optional LogQL pipeline selecting the lines the retention period applies to, for example '| level=\"debug\"'. Only the matching lines are deleted once they are older than the period, the other lines of the streams keep the retention of the rules without filter."`
 	Matchers []*labels.Matcher `yaml:"-" json:"-"` // populated during validation.
+	// Expr is the selector and the filter of the rule, populated during validation for rules with a filter.
+	Expr syntax.LogSelectorExpr `yaml:"-" json:"-"`
+}
+
+type IngestionPipeline struct {
+	Name               string             `yaml:"name" json:"name" doc:"description:Name of the pipeline used in metrics."`
+	Selector           string             `yaml:"selector" json:"selector" doc:"description:Stream selector expression of the streams the pipeline applies to."`
+	Pipeline           string             `yaml:"pipeline" json:"pipeline" doc:"description:LogQL pipeline stages applied to the entries."`
+	StructuredMetadata []string           `yaml:"structured_metadata" json:"structured_metadata" doc:"description:Extracted labels stored as structured metadata of the entries."`
+	Matchers           []*labels.Matcher  `yaml:"-" json:"-"` // populated during validation.
+	Stages             []syntax.StageExpr `yaml:"-" json:"-"` // populated during validation.
 }
 
 // LimitError are errors that do not comply with the limits specified.
@@ -224,6 +248,7 @@ func (l *Limits) RegisterFlags(f *flag.FlagSet) {
 	f.Var(&l.CreationGracePeriod, "validation.create-grace-period", "Duration which table will be created/deleted before/after it's needed; we won't accept sample from before this time.")
 	f.BoolVar(&l.EnforceMetricName, "validation.enforce-metric-name", true, "Enforce every sample has a metric name.")
 	f.IntVar(&l.MaxEntriesLimitPerQuery, "validation.max-entries-limit", 5000, "Maximum number of log entries that will be returned for a query.")
+	f.IntVar(&l.MaxExportEntriesPerQuery, "validation.max-export-entries-limit", 10000000, "Maximum number of log entries that will be exported by a single export request. Larger exports are resumed from the cursor of the previous request. 0 to disable.")
 
 	f.IntVar(&l.MaxLocalStreamsPerUser, "ingester.max-streams-per-user", 0, "Maximum number of active streams per user, per ingester. 0 to disable.")
 	f.IntVar(&l.MaxGlobalStreamsPerUser, "ingester.max-global-streams-per-user", 5000, "Maximum number of active streams per user, across the cluster. 0 to disable. When the global limit is enabled, each ingester is configured with a dynamic local limit based on the replication factor and the current number of healthy ingesters, and is kept updated whenever the number of ingesters change.")
@@ -241,10 +266,12 @@ func (l *Limits) RegisterFlags(f *flag.FlagSet) {
 	_ = l.MaxQueryLength.Set("721h")
 	f.Var(&l.MaxQueryLength, "store.max-query-length", "The limit to length of chunk store queries. 0 to disable.")
 	f.IntVar(&l.MaxQuerySeries, "querier.max-query-series", 500, "Limit the maximum of unique series that is returned by a metric query. When the limit is reached an error is returned.")
+	f.IntVar(&l.MaxQueryJoinEntries, "querier.max-query-join-entries", 100000, "Limit the maximum of log lines a log join keeps in memory within its tolerance. When the limit is reached an error is returned. 0 to disable.")
 	_ = l.MaxQueryRange.Set("0s")
 	f.Var(&l.MaxQueryRange, "querier.max-query-range", "Limit the length of the [range] inside a range query. Default is 0 or unlimited")
 	_ = l.QueryTimeout.Set(DefaultPerTenantQueryTimeout)
 	f.Var(&l.QueryTimeout, "querier.query-timeout", "Timeout when querying backends (ingesters or storage) during the execution of a query request. When a specific per-tenant timeout is used, the global timeout is ignored.")
+	f.BoolVar(&l.AllowPartialResults, "querier.allow-partial-results", false, "Return partial results with warnings instead of failing the query when individual query shards or ingesters fail. Queries can also opt in using the X-Loki-Allow-Partial-Results header.")
 
 	_ = l.MaxQueryLookback.Set("0s")
 	f.Var(&l.MaxQueryLookback, "querier.max-query-lookback", "Limit how far back in time series data and metadata can be queried, up until lookback duration ago. This limit is enforced in the query frontend, the querier and the ruler. If the requested time range is outside the allowed range, the request will not fail, but will be modified to only query data within the allowed time range. The default value of 0 does not set a limit.")
@@ -283,12 +310,15 @@ func (l *Limits) RegisterFlags(f *flag.FlagSet) {
 	f.StringVar(&l.PerTenantOverrideConfig, "limits.per-user-override-config", "", "Feature renamed to 'runtime configuration', flag deprecated in favor of -runtime-config.file (runtime_config.file in YAML).")
 	_ = l.RetentionPeriod.Set("0s")
 	f.Var(&l.RetentionPeriod, "store.retention", "Retention period to apply to stored data, only applies if retention_enabled is true in the compactor config. As of version 2.8.0, a zero value of 0 or 0s disables retention. In previous releases, Loki did not properly honor a zero value to disable retention and a really large value should be used instead.")
+	_ = l.ColdStorageAfter.Set("0s")
+	f.Var(&l.ColdStorageAfter, "store.cold-storage-after", "Age after which the compactor moves the chunks and index files of the tenant to the cold storage configured in the storage_config. 0 disables moving data to the cold storage.")
 
 	_ = l.PerTenantOverridePeriod.Set("10s")
 	f.Var(&l.PerTenantOverridePeriod, "limits.per-user-override-period", "Feature renamed to 'runtime configuration'; flag deprecated in favor of -runtime-config.reload-period (runtime_config.period in YAML).")
 
 	_ = l.QuerySplitDuration.Set("1h")
 	f.Var(&l.QuerySplitDuration, "querier.split-queries-by-interval", "Split queries by a time interval and execute in parallel. The value 0 disables splitting by time. This also determines how cache keys are chosen when result caching is enabled.")
+	f.Var(&l.QuerySplitTargetBytes, "querier.split-queries-target-bytes", "Target number of bytes read by each split of a range query against TSDB. If set, the split interval is derived from the index stats of the query: split_queries_by_interval is multiplied or divided by a power of two, so that each split reads about this many bytes, but splits are never shorter than 1m. The value 0 always splits by split_queries_by_interval. Also expressible in human readable forms (1GB, etc).")
 
 	f.StringVar(&l.DeletionMode, "compactor.deletion-mode", "filter-and-delete", "Deletion mode. Can be one of 'disabled', 'filter-only', or 'filter-and-delete'. When set to 'filter-only' or 'filter-and-delete', and if retention_enabled is true, then the log entry deletion API endpoints are available.")
 
@@ -301,6 +331,9 @@ func (l *Limits) RegisterFlags(f *flag.FlagSet) {
 	l.ShardStreams = &shardstreams.Config{}
 	l.ShardStreams.RegisterFlagsWithPrefix("shard-streams", f)
 
+	l.LabelPolicy = &labelpolicy.Config{}
+	l.LabelPolicy.RegisterFlagsWithPrefix("validation.label-policy", f)
+
 	f.IntVar(&l.VolumeMaxSeries, "limits.volume-max-series", 1000, "The default number of aggregated series or labels that can be returned from a log-volume endpoint")
 
 	f.BoolVar(&l.AllowStructuredMetadata, "validation.allow-structured-metadata", false, "Allow user to send structured metadata (non-indexed labels) in push payload.")
@@ -343,9 +376,71 @@ func (l *Limits) Validate() error {
 			}
 			// populate matchers during validation
 			l.StreamRetention[i].Matchers = matchers
+
+			if rule.Filter != "" {
+				expr, err := syntax.ParseLogSelector(rule.Selector+" "+rule.Filter, true)
+				if err != nil {
+					return fmt.Errorf("invalid retention filter: %w", err)
+				}
+				if _, ok := expr.(*syntax.PipelineExpr); !ok {
+					return fmt.Errorf("retention filter %q has no stages", rule.Filter)
+				}
+				if err := syntax.ValidateNoMergeStages(expr); err != nil {
+					return fmt.Errorf("invalid retention filter: %w", err)
+				}
+				l.StreamRetention[i].Expr = expr
+			}
+		}
+	}
+
+	if l.LabelPolicy != nil {
+		if err := l.LabelPolicy.Validate(); err != nil {
+			return err
 		}
 	}
 
+	names := make(map[string]struct{}, len(l.IngestionPipelines))
+	for i, p := range l.IngestionPipelines {
+		if p.Name == "" {
+			return errors.New("ingestion pipeline name must not be empty")
+		}
+		if _, ok := names[p.Name]; ok {
+			return fmt.Errorf("duplicate ingestion pipeline name %q", p.Name)
+		}
+		names[p.Name] = struct{}{}
+
+		if p.Selector != "" {
+			matchers, err := syntax.ParseMatchers(p.Selector, true)
+			if err != nil {
+				return fmt.Errorf("invalid selector of ingestion pipeline %q: %w", p.Name, err)
+			}
+			l.IngestionPipelines[i].Matchers = matchers
+		}
+
+		expr, err := syntax.ParseLogSelector("{} "+p.Pipeline, false)
+		if err != nil {
+			return fmt.Errorf("invalid ingestion pipeline %q: %w", p.Name, err)
+		}
+		pipeline, ok := expr.(*syntax.PipelineExpr)
+		if !ok {
+			return fmt.Errorf("ingestion pipeline %q has no stages", p.Name)
+		}
+		if err := syntax.ValidateNoMergeStages(pipeline); err != nil {
+			return fmt.Errorf("invalid ingestion pipeline %q: %w", p.Name, err)
+		}
+		// Build the stages once to reject the invalid ones, like malformed templates.
+		for _, stage := range pipeline.MultiStages {
+			// the distributor processes each line once, so a stage can't fan out lines.
+			if _, ok := stage.(*syntax.UnnestExpr); ok {
+				return fmt.Errorf("invalid stage %q of ingestion pipeline %q: the %s stage is not supported in ingestion pipelines", stage, p.Name, syntax.OpUnnest)
+			}
+			if _, err := stage.Stage(); err != nil {
+				return fmt.Errorf("invalid stage %q of ingestion pipeline %q: %w", stage, p.Name, err)
+			}
+		}
+		l.IngestionPipelines[i].Stages = pipeline.MultiStages
+	}
+
 	if _, err := deletionmode.ParseMode(l.DeletionMode); err != nil {
 		return err
 	}
@@ -482,6 +577,11 @@ func (o *Overrides) MaxQuerySeries(_ context.Context, userID string) int {
 	return o.getOverridesForUser(userID).MaxQuerySeries
 }
 
+// MaxQueryJoinEntries returns the limit of the log lines buffered by log joins.
+func (o *Overrides) MaxQueryJoinEntries(_ context.Context, userID string) int {
+	return o.getOverridesForUser(userID).MaxQueryJoinEntries
+}
+
 // MaxQueryRange returns the limit for the max [range] value that can be in a range query
 func (o *Overrides) MaxQueryRange(_ context.Context, userID string) time.Duration {
 	return time.Duration(o.getOverridesForUser(userID).MaxQueryRange)
@@ -539,6 +639,11 @@ func (o *Overrides) QuerySplitDuration(userID string) time.Duration {
 	return time.Duration(o.getOverridesForUser(userID).QuerySplitDuration)
 }
 
+// QuerySplitTargetBytes returns the tenant specific number of bytes each split of a query should read.
+func (o *Overrides) QuerySplitTargetBytes(userID string) int {
+	return o.getOverridesForUser(userID).QuerySplitTargetBytes.Val()
+}
+
 // MaxQueryBytesRead returns the maximum bytes a query can read.
 func (o *Overrides) MaxQueryBytesRead(_ context.Context, userID string) int {
 	return o.getOverridesForUser(userID).MaxQueryBytesRead.Val()
@@ -569,10 +674,20 @@ func (o *Overrides) MaxEntriesLimitPerQuery(_ context.Context, userID string) in
 	return o.getOverridesForUser(userID).MaxEntriesLimitPerQuery
 }
 
+// MaxExportEntriesPerQuery returns the limit to number of entries the querier should export per export request.
+func (o *Overrides) MaxExportEntriesPerQuery(_ context.Context, userID string) int {
+	return o.getOverridesForUser(userID).MaxExportEntriesPerQuery
+}
+
 func (o *Overrides) QueryTimeout(_ context.Context, userID string) time.Duration {
 	return time.Duration(o.getOverridesForUser(userID).QueryTimeout)
 }
 
+// AllowPartialResults returns whether queries of the tenant return partial results when parts of the query fail.
+func (o *Overrides) AllowPartialResults(_ context.Context, userID string) bool {
+	return o.getOverridesForUser(userID).AllowPartialResults
+}
+
 func (o *Overrides) MaxCacheFreshness(_ context.Context, userID string) time.Duration {
 	return time.Duration(o.getOverridesForUser(userID).MaxCacheFreshness)
 }
@@ -729,6 +844,11 @@ func (o *Overrides) StreamRetention(userID string) []StreamRetention {
 	return o.getOverridesForUser(userID).StreamRetention
 }
 
+// ColdStorageAfter returns the age after which the data of a given user is moved to the cold storage.
+func (o *Overrides) ColdStorageAfter(userID string) time.Duration {
+	return time.Duration(o.getOverridesForUser(userID).ColdStorageAfter)
+}
+
 func (o *Overrides) UnorderedWrites(userID string) bool {
 	return o.getOverridesForUser(userID).UnorderedWrites
 }
@@ -741,6 +861,14 @@ func (o *Overrides) ShardStreams(userID string) *shardstreams.Config {
 	return o.getOverridesForUser(userID).ShardStreams
 }
 
+func (o *Overrides) LabelPolicy(userID string) *labelpolicy.Config {
+	return o.getOverridesForUser(userID).LabelPolicy
+}
+
+func (o *Overrides) IngestionPipelines(userID string) []IngestionPipeline {
+	return o.getOverridesForUser(userID).IngestionPipelines
+}
+
 func (o *Overrides) BlockedQueries(_ context.Context, userID string) []*validation.BlockedQuery {
 	return o.getOverridesForUser(userID).BlockedQueries
 }
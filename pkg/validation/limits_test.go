@@ -304,3 +304,53 @@ func TestLimitsValidation(t *testing.T) {
 		require.True(t, errors.Is(limits.Validate(), tc.expected))
 	}
 }
+
+func TestLimitsValidation_IngestionPipelines(t *testing.T) {
+	for _, tc := range []struct {
+		pipeline string
+		err      string
+	}{
+		{pipeline: `!= "/health"`},
+		{pipeline: `| json | level="debug"`},
+		{pipeline: `| unnest items`, err: `invalid stage "| unnest items" of ingestion pipeline "test": the unnest stage is not supported in ingestion pipelines`},
+		{pipeline: `| json | distinct id`, err: `invalid ingestion pipeline "test": parse error : the distinct stage is only supported in log queries`},
+		{pipeline: `| dedup 1s`, err: `invalid ingestion pipeline "test": parse error : the dedup stage is only supported in log queries`},
+		{pipeline: `| json | limit 1 by (id)`, err: `invalid ingestion pipeline "test": parse error : the limit stage is only supported in log queries`},
+	} {
+		t.Run(tc.pipeline, func(t *testing.T) {
+			limits := Limits{DeletionMode: "disabled", IngestionPipelines: []IngestionPipeline{{Name: "test", Pipeline: tc.pipeline}}}
+			err := limits.Validate()
+			if tc.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tc.err)
+		})
+	}
+}
+
+func TestLimitsValidation_RetentionFilters(t *testing.T) {
+	for _, tc := range []struct {
+		filter string
+		err    string
+	}{
+		{filter: `| level="debug"`},
+		{filter: `| dedup`, err: `invalid retention filter: parse error : the dedup stage is only supported in log queries`},
+		{filter: `| json | distinct id`, err: `invalid retention filter: parse error : the distinct stage is only supported in log queries`},
+		{filter: `| json | limit 10 by (id)`, err: `invalid retention filter: parse error : the limit stage is only supported in log queries`},
+	} {
+		t.Run(tc.filter, func(t *testing.T) {
+			limits := Limits{DeletionMode: "disabled", StreamRetention: []StreamRetention{{
+				Period:   model.Duration(72 * time.Hour),
+				Selector: `{app="api"}`,
+				Filter:   tc.filter,
+			}}}
+			err := limits.Validate()
+			if tc.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tc.err)
+		})
+	}
+}
@@ -66,6 +66,12 @@ const (
 	StructuredMetadataTooLargeErrorMsg   = "stream '%s' has structured metadata too large: '%d' bytes, limit: '%d' bytes. Please see `limits_config.structured_metadata_max_size` or contact your Loki administrator to increase it."
 	StructuredMetadataTooMany            = "structured_metadata_too_many"
 	StructuredMetadataTooManyErrorMsg    = "stream '%s' has too many structured metadata labels: '%d', limit: '%d'. Please see `limits_config.max_structured_metadata_entries_count` or contact your Loki administrator to increase it."
+	// DisallowedLabelName is a reason for discarding or mutating a stream which has a label not allowed by the label policy
+	DisallowedLabelName         = "disallowed_label_name"
+	DisallowedLabelNameErrorMsg = "stream '%s' has label name not allowed by the label policy: '%s'"
+	// LabelValuesLimit is a reason for discarding or mutating a stream which has a label exceeding its maximum number of distinct values
+	LabelValuesLimit         = "label_values_limit"
+	LabelValuesLimitErrorMsg = "stream '%s' has label '%s' exceeding the limit of %d distinct values of the label policy"
 )
 
 type ErrStreamRateLimit struct {